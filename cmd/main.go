@@ -185,13 +185,17 @@ func main() {
 			}
 		}
 
+		// Replenishment: drafts POs per tenant when auto_create_material_request is enabled.
+		_, replenishmentSvc := wire.NewReplenishment(db, pool)
+		replenishFn := replenishmentSvc.RunScheduled
+
 		log.Info().Msg("cron: first run (post-startup)")
-		tools.CronDispatch(db, analyzer, lotNotifyFn, lowStockNotifyFn, trialSendFn)
+		tools.CronDispatch(db, analyzer, lotNotifyFn, lowStockNotifyFn, trialSendFn, replenishFn)
 
 		ticker := time.NewTicker(1 * time.Hour)
 		defer ticker.Stop()
 		for range ticker.C {
-			tools.CronDispatch(db, analyzer, lotNotifyFn, lowStockNotifyFn, trialSendFn)
+			tools.CronDispatch(db, analyzer, lotNotifyFn, lowStockNotifyFn, trialSendFn, replenishFn)
 		}
 	}()
 
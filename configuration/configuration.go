@@ -104,6 +104,25 @@ type Config struct {
 	VPSManagerAPIKey       string // env: VPS_MANAGER_API_KEY (service key configured in VPS Manager)
 	VPSManagerFromAddr     string // env: VPS_MANAGER_FROM_ADDR, e.g. "noreply@eflowsuite.com"
 	EmailGatewayDisabled   bool   // env: EMAIL_GATEWAY_DISABLED=true — skips gateway tier; falls through to next sender
+
+	// GS1CompanyPrefix is the GS1 company prefix used to build SSCC package ids (env:
+	// GS1_COMPANY_PREFIX, 7–10 digits). It is one value for the whole installation, shared by
+	// every tenant like the SSCC serial sequence. Defaults to "0000000" (no GS1 membership)
+	// if unset; any other value that is not 7–10 digits fails startup.
+	GS1CompanyPrefix string
+
+	// Document storage for delivery note PDFs and proof-of-delivery images
+	// (env: DOCUMENT_STORAGE = "local" | "s3"; default "local").
+	// local writes under DocumentStorageDir (env: DOCUMENT_STORAGE_DIR, default "/tmp/estock-documents"),
+	// which is only shared across replicas when it is a shared volume.
+	// s3 uses an S3-compatible bucket (AWS S3, MinIO, ...) addressed with path-style URLs.
+	DocumentStorage    string
+	DocumentStorageDir string
+	S3Endpoint         string // env: S3_ENDPOINT, e.g. "https://s3.us-east-1.amazonaws.com" or "http://minio:9000"
+	S3Region           string // env: S3_REGION (default "us-east-1")
+	S3Bucket           string // env: S3_BUCKET
+	S3AccessKeyID      string // env: S3_ACCESS_KEY_ID
+	S3SecretAccessKey  string // env: S3_SECRET_ACCESS_KEY
 }
 
 // LoadConfig loads configuration from environment variables, optionally from a .env file if present.
@@ -151,6 +170,14 @@ func LoadConfig() (Config, error) {
 		VPSManagerAPIKey:      os.Getenv("VPS_MANAGER_API_KEY"),
 		VPSManagerFromAddr:    os.Getenv("VPS_MANAGER_FROM_ADDR"),
 		EmailGatewayDisabled:  os.Getenv("EMAIL_GATEWAY_DISABLED") == "true",
+		GS1CompanyPrefix:      strings.TrimSpace(os.Getenv("GS1_COMPANY_PREFIX")),
+		DocumentStorage:       strings.ToLower(os.Getenv("DOCUMENT_STORAGE")),
+		DocumentStorageDir:    os.Getenv("DOCUMENT_STORAGE_DIR"),
+		S3Endpoint:            os.Getenv("S3_ENDPOINT"),
+		S3Region:              os.Getenv("S3_REGION"),
+		S3Bucket:              os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:         os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:     os.Getenv("S3_SECRET_ACCESS_KEY"),
 	}
 	if cfg.TenantID == "" {
 		cfg.TenantID = "00000000-0000-0000-0000-000000000001"
@@ -173,6 +200,15 @@ func LoadConfig() (Config, error) {
 	if cfg.EmailFromName == "" {
 		cfg.EmailFromName = "eSTOCK"
 	}
+	if cfg.GS1CompanyPrefix == "" {
+		cfg.GS1CompanyPrefix = "0000000"
+	}
+	if cfg.DocumentStorage == "" {
+		cfg.DocumentStorage = "local"
+	}
+	if cfg.DocumentStorageDir == "" {
+		cfg.DocumentStorageDir = "/tmp/estock-documents"
+	}
 
 	// EnableSignup: explicit env var takes priority; defaults to true in development, false elsewhere.
 	if raw := os.Getenv("ENABLE_SIGNUP"); raw != "" {
@@ -200,6 +236,12 @@ func LoadConfig() (Config, error) {
 	if err := validateRequired(cfg); err != nil {
 		return Config{}, err
 	}
+	if err := validateDocumentStorage(cfg); err != nil {
+		return Config{}, err
+	}
+	if err := validateGS1CompanyPrefix(cfg.GS1CompanyPrefix); err != nil {
+		return Config{}, err
+	}
 
 	return cfg, nil
 }
@@ -245,6 +287,46 @@ func validateRequired(cfg Config) error {
 	return nil
 }
 
+// validateDocumentStorage checks DOCUMENT_STORAGE and, for s3, that the bucket settings are present.
+func validateDocumentStorage(cfg Config) error {
+	switch cfg.DocumentStorage {
+	case "local":
+		return nil
+	case "s3":
+		var missing []string
+		for _, r := range []struct{ name, value string }{
+			{"S3_ENDPOINT", cfg.S3Endpoint},
+			{"S3_BUCKET", cfg.S3Bucket},
+			{"S3_ACCESS_KEY_ID", cfg.S3AccessKeyID},
+			{"S3_SECRET_ACCESS_KEY", cfg.S3SecretAccessKey},
+		} {
+			if r.value == "" {
+				missing = append(missing, r.name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required config for DOCUMENT_STORAGE=s3: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	default:
+		return fmt.Errorf("DOCUMENT_STORAGE must be \"local\" or \"s3\", got %q", cfg.DocumentStorage)
+	}
+}
+
+// validateGS1CompanyPrefix checks the prefix SSCCs are built with, so a bad value fails at
+// startup instead of on every package added during packing.
+func validateGS1CompanyPrefix(prefix string) error {
+	if len(prefix) < 7 || len(prefix) > 10 {
+		return fmt.Errorf("GS1_COMPANY_PREFIX must have 7 to 10 digits, got %q", prefix)
+	}
+	for _, c := range prefix {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("GS1_COMPANY_PREFIX must contain only digits, got %q", prefix)
+		}
+	}
+	return nil
+}
+
 // isNotFound reports whether the error is due to .env file not existing (so env-only config is allowed).
 func isNotFound(err error) bool {
 	if err == nil {
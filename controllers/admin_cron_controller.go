@@ -10,13 +10,15 @@ import (
 
 type AdminCronController struct {
 	DB *gorm.DB
+	// Replenisher drafts replenishment POs for one tenant (optional; nil skips the job).
+	Replenisher func(tenantID string) error
 }
 
-func NewAdminCronController(db *gorm.DB) *AdminCronController {
-	return &AdminCronController{DB: db}
+func NewAdminCronController(db *gorm.DB, replenisher func(tenantID string) error) *AdminCronController {
+	return &AdminCronController{DB: db, Replenisher: replenisher}
 }
 
-// Trigger handles POST /admin/cron/trigger?job=stock_alerts|stale_reservations|trial_expiration|all
+// Trigger handles POST /admin/cron/trigger?job=stock_alerts|stale_reservations|trial_expiration|replenishment|all
 // Protected by JWTAuthMiddleware + RequirePermission("cron","trigger").
 func (c *AdminCronController) Trigger(ctx *gin.Context) {
 	job := ctx.DefaultQuery("job", "all")
@@ -53,12 +55,17 @@ func (c *AdminCronController) Trigger(ctx *gin.Context) {
 			tools.ResponseInternal(ctx, "CronTrigger", "Error al ejecutar trial_expiration", "cron_trigger")
 			return
 		}
+	case "replenishment":
+		if err := tools.RunReplenishment(c.DB, c.Replenisher); err != nil {
+			tools.ResponseInternal(ctx, "CronTrigger", "Error al ejecutar replenishment", "cron_trigger")
+			return
+		}
 	case "all":
 		// Admin manual trigger: no notification callbacks (fire-and-forget; notifications
 		// are wired in the background cron goroutine in main.go).
-		tools.CronDispatch(c.DB, analyzer, nil, nil, nil)
+		tools.CronDispatch(c.DB, analyzer, nil, nil, nil, c.Replenisher)
 	default:
-		tools.ResponseBadRequest(ctx, "CronTrigger", "Job inválido. Use: stock_alerts | stale_reservations | trial_expiration | all", "cron_trigger")
+		tools.ResponseBadRequest(ctx, "CronTrigger", "Job inválido. Use: stock_alerts | stale_reservations | trial_expiration | replenishment | all", "cron_trigger")
 		return
 	}
 
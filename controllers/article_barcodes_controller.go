@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleBarcodesController handles HTTP for alternate article codes (GTIN/EAN/internal)
+// and the scan parse-and-resolve endpoint used by receiving and picking handhelds.
+type ArticleBarcodesController struct {
+	Service  *services.ArticleBarcodesService
+	TenantID string
+}
+
+func NewArticleBarcodesController(svc *services.ArticleBarcodesService, tenantID string) *ArticleBarcodesController {
+	return &ArticleBarcodesController{Service: svc, TenantID: tenantID}
+}
+
+// ListArticleBarcodes handles GET /api/article-barcodes?sku=
+func (c *ArticleBarcodesController) ListArticleBarcodes(ctx *gin.Context) {
+	barcodes, resp := c.Service.List(c.resolveTenantID(ctx), ctx.Query("sku"))
+	if resp != nil {
+		writeErrorResponse(ctx, "ListArticleBarcodes", "list_article_barcodes", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListArticleBarcodes", "Códigos de barras obtenidos", "list_article_barcodes", barcodes, false, "")
+}
+
+// CreateArticleBarcode handles POST /api/article-barcodes
+func (c *ArticleBarcodesController) CreateArticleBarcode(ctx *gin.Context) {
+	var req requests.CreateArticleBarcodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateArticleBarcode", "Datos de solicitud inválidos", "create_article_barcode")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateArticleBarcode", "create_article_barcode", errs)
+		return
+	}
+	barcode, resp := c.Service.Create(c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateArticleBarcode", "create_article_barcode", resp)
+		return
+	}
+	tools.ResponseCreated(ctx, "CreateArticleBarcode", "Código de barras creado", "create_article_barcode", barcode, false, "")
+}
+
+// DeleteArticleBarcode handles DELETE /api/article-barcodes/:id
+func (c *ArticleBarcodesController) DeleteArticleBarcode(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DeleteArticleBarcode", "delete_article_barcode", "ID de código de barras inválido")
+	if !ok {
+		return
+	}
+	if resp := c.Service.Delete(c.resolveTenantID(ctx), id); resp != nil {
+		writeErrorResponse(ctx, "DeleteArticleBarcode", "delete_article_barcode", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "DeleteArticleBarcode", "Código de barras eliminado", "delete_article_barcode", nil, false, "")
+}
+
+// ParseScan handles POST /api/scan/parse — decodes a GS1 (or plain) scan and returns the
+// resolved article with a prefilled lot/serial ready for CompleteReceivingLine or
+// CompletePickingLine.
+func (c *ArticleBarcodesController) ParseScan(ctx *gin.Context) {
+	var req requests.ScanParseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "ParseScan", "Datos de solicitud inválidos", "parse_scan")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "ParseScan", "parse_scan", errs)
+		return
+	}
+	result, resp := c.Service.ParseScan(c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "ParseScan", "parse_scan", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ParseScan", "Código procesado", "parse_scan", result, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only.
+func (c *ArticleBarcodesController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockArticleBarcodesRepoCtrl struct {
+	created *requests.CreateArticleBarcodeRequest
+}
+
+func (m *mockArticleBarcodesRepoCtrl) List(_, sku string) ([]database.ArticleBarcode, *responses.InternalResponse) {
+	return []database.ArticleBarcode{{ID: "bc-1", SKU: sku, Code: "09506000134352", CodeType: database.ArticleBarcodeGTIN, PackQuantity: 1}}, nil
+}
+
+func (m *mockArticleBarcodesRepoCtrl) Create(_ string, req *requests.CreateArticleBarcodeRequest) (*database.ArticleBarcode, *responses.InternalResponse) {
+	m.created = req
+	return &database.ArticleBarcode{ID: "bc-2", SKU: req.SKU, Code: req.Code}, nil
+}
+
+func (m *mockArticleBarcodesRepoCtrl) Delete(_, _ string) *responses.InternalResponse {
+	return &responses.InternalResponse{Message: "Código de barras no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+}
+
+func (m *mockArticleBarcodesRepoCtrl) FindByCode(_, code string) (*database.ArticleBarcode, *database.Article, *responses.InternalResponse) {
+	if code != "09506000134352" {
+		return nil, nil, nil
+	}
+	return &database.ArticleBarcode{Code: code, CodeType: database.ArticleBarcodeGTIN, SKU: "AMOX-500", PackQuantity: 1},
+		&database.Article{ID: "art-1", SKU: "AMOX-500", Name: "Amoxicilina", TrackByLot: true}, nil
+}
+
+func (m *mockArticleBarcodesRepoCtrl) FindArticleBySKU(_, _ string) (*database.Article, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepoCtrl) FindLot(_, _, _ string) (*database.Lot, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepoCtrl) FindSerial(_, _, _ string) (*database.Serial, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func newArticleBarcodesRouter(repo *mockArticleBarcodesRepoCtrl) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewArticleBarcodesController(services.NewArticleBarcodesService(repo), "00000000-0000-0000-0000-000000000001")
+	r := gin.New()
+	r.GET("/article-barcodes", ctrl.ListArticleBarcodes)
+	r.POST("/article-barcodes", ctrl.CreateArticleBarcode)
+	r.DELETE("/article-barcodes/:id", ctrl.DeleteArticleBarcode)
+	r.POST("/scan/parse", ctrl.ParseScan)
+	return r
+}
+
+func postJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestArticleBarcodesController_ParseScan(t *testing.T) {
+	w := postJSON(newArticleBarcodesRouter(&mockArticleBarcodesRepoCtrl{}), "/scan/parse",
+		requests.ScanParseRequest{Code: "(01)09506000134352(10)L-5(17)270630", Purpose: "receiving"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data responses.ScanParseResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Data.Resolved)
+	require.NotNil(t, body.Data.LotRequest)
+	assert.Equal(t, "L-5", body.Data.LotRequest.LotNumber)
+	assert.Equal(t, "AMOX-500", body.Data.LotRequest.SKU)
+}
+
+func TestArticleBarcodesController_ParseScan_BadRequests(t *testing.T) {
+	r := newArticleBarcodesRouter(&mockArticleBarcodesRepoCtrl{})
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/scan/parse", map[string]string{}).Code, "missing code")
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/scan/parse", requests.ScanParseRequest{Code: "X", Purpose: "shipping"}).Code, "bad purpose")
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/scan/parse", requests.ScanParseRequest{Code: "]C1" + "0109506000134353"}).Code, "bad check digit")
+}
+
+func TestArticleBarcodesController_CreateAndDelete(t *testing.T) {
+	repo := &mockArticleBarcodesRepoCtrl{}
+	r := newArticleBarcodesRouter(repo)
+
+	w := postJSON(r, "/article-barcodes", requests.CreateArticleBarcodeRequest{SKU: "AMOX-500", Code: "9506000134352"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.created)
+	assert.Equal(t, "AMOX-500", repo.created.SKU)
+
+	w = postJSON(r, "/article-barcodes", map[string]string{"sku": "AMOX-500", "code": "1", "code_type": "ean"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/article-barcodes/bc-9", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -306,6 +306,10 @@ func writeErrorResponse(ctx *gin.Context, transactionType, endpointCode string,
 	case responses.StatusNotFound:
 		tools.ResponseNotFound(ctx, transactionType, resp.Message, endpointCode)
 	case responses.StatusConflict:
+		if resp.Details != nil {
+			tools.ResponseConflictWithData(ctx, transactionType, resp.Message, endpointCode, resp.Details)
+			return
+		}
 		tools.ResponseConflict(ctx, transactionType, resp.Message, endpointCode)
 	case responses.StatusInternalServerError:
 		tools.ResponseInternal(ctx, transactionType, resp.Message, endpointCode)
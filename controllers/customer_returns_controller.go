@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// CustomerReturnsController handles HTTP for customer returns (RMA).
+type CustomerReturnsController struct {
+	Service      *services.CustomerReturnsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewCustomerReturnsController(svc *services.CustomerReturnsService, tenantID string, auditSvc *services.AuditService) *CustomerReturnsController {
+	return &CustomerReturnsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a customer return when the audit service is configured.
+func (c *CustomerReturnsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceCustomerReturn, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListReturns handles GET /api/customer-returns
+func (c *CustomerReturnsController) ListReturns(ctx *gin.Context) {
+	var status, salesOrderID *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+	if v := ctx.Query("sales_order_id"); v != "" {
+		salesOrderID = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	returns, resp := c.Service.ListReturns(c.resolveTenantID(ctx), status, salesOrderID, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListCustomerReturns", "list_customer_returns", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListCustomerReturns", "Devoluciones recuperadas", "list_customer_returns", returns, false, "")
+}
+
+// GetReturn handles GET /api/customer-returns/:id
+func (c *CustomerReturnsController) GetReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetCustomerReturn", "get_customer_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetReturn(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetCustomerReturn", "get_customer_return", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetCustomerReturn", "Devolución recuperada", "get_customer_return", view, false, "")
+}
+
+// CreateReturn handles POST /api/customer-returns
+func (c *CustomerReturnsController) CreateReturn(ctx *gin.Context) {
+	var req requests.CreateCustomerReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateCustomerReturn", "Datos de solicitud inválidos", "create_customer_return")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateCustomerReturn", "create_customer_return", errs)
+		return
+	}
+
+	view, resp := c.Service.CreateReturn(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateCustomerReturn", "create_customer_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, view.ID, view)
+	tools.ResponseCreated(ctx, "CreateCustomerReturn", "Devolución autorizada", "create_customer_return", view, false, "")
+}
+
+// ReceiveReturn handles POST /api/customer-returns/:id/receive
+func (c *CustomerReturnsController) ReceiveReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ReceiveCustomerReturn", "receive_customer_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.ReceiveCustomerReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "ReceiveCustomerReturn", "Datos de solicitud inválidos", "receive_customer_return")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "ReceiveCustomerReturn", "receive_customer_return", errs)
+		return
+	}
+
+	view, resp := c.Service.ReceiveReturn(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "ReceiveCustomerReturn", "receive_customer_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionExecute, id, req)
+	tools.ResponseOK(ctx, "ReceiveCustomerReturn", "Inspección de la devolución registrada", "receive_customer_return", view, false, "")
+}
+
+// CancelReturn handles PATCH /api/customer-returns/:id/cancel
+func (c *CustomerReturnsController) CancelReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CancelCustomerReturn", "cancel_customer_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	ret, resp := c.Service.CancelReturn(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "CancelCustomerReturn", "cancel_customer_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, ret)
+	tools.ResponseOK(ctx, "CancelCustomerReturn", "Devolución cancelada", "cancel_customer_return", ret, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *CustomerReturnsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCustomerReturnsCtrlRepo struct {
+	createReq  *requests.CreateCustomerReturnRequest
+	receiveReq *requests.ReceiveCustomerReturnRequest
+	cancelResp *responses.InternalResponse
+}
+
+func (m *mockCustomerReturnsCtrlRepo) ListReturns(tenantID string, status, salesOrderID *string, limit, offset int) ([]database.CustomerReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCustomerReturnsCtrlRepo) GetReturn(id, tenantID string) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	return &responses.CustomerReturnView{CustomerReturn: database.CustomerReturn{ID: id}}, nil
+}
+func (m *mockCustomerReturnsCtrlRepo) CreateReturn(tenantID, userID string, req *requests.CreateCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	m.createReq = req
+	return &responses.CustomerReturnView{CustomerReturn: database.CustomerReturn{ID: "r1", ReturnNumber: "RMA-2026-0001", Status: database.CustomerReturnAuthorized}}, nil
+}
+func (m *mockCustomerReturnsCtrlRepo) ReceiveReturn(id, tenantID, userID string, req *requests.ReceiveCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	m.receiveReq = req
+	return &responses.CustomerReturnView{CustomerReturn: database.CustomerReturn{ID: id, Status: database.CustomerReturnReceived}}, nil
+}
+func (m *mockCustomerReturnsCtrlRepo) CancelReturn(id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse) {
+	if m.cancelResp != nil {
+		return nil, m.cancelResp
+	}
+	return &database.CustomerReturn{ID: id, Status: database.CustomerReturnCancelled}, nil
+}
+
+func newCustomerReturnsTestRouter(repo *mockCustomerReturnsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := NewCustomerReturnsController(services.NewCustomerReturnsService(repo), ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	rg := r.Group("/api/customer-returns")
+	rg.Use(injectUser)
+	rg.POST("", ctrl.CreateReturn)
+	rg.POST("/:id/receive", ctrl.ReceiveReturn)
+	rg.PATCH("/:id/cancel", ctrl.CancelReturn)
+	return r
+}
+
+func TestCustomerReturnsController_Create_Returns201(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/customer-returns", map[string]interface{}{
+		"delivery_note_id": "dn-1",
+		"lines": []map[string]interface{}{
+			{"article_sku": "SKU-1", "lot_number": "L1", "qty": 2, "reason_code": "damaged"},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, "damaged", repo.createReq.Lines[0].ReasonCode)
+}
+
+func TestCustomerReturnsController_Create_Returns400_InvalidReason(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/customer-returns", map[string]interface{}{
+		"sales_order_id": "so-1",
+		"lines":          []map[string]interface{}{{"article_sku": "SKU-1", "qty": 1, "reason_code": "changed_mind"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestCustomerReturnsController_Create_Returns400_NoSource(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/customer-returns", map[string]interface{}{
+		"lines": []map[string]interface{}{{"article_sku": "SKU-1", "qty": 1, "reason_code": "other"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestCustomerReturnsController_Receive_Returns400_InvalidDisposition(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/customer-returns/r1/receive", map[string]interface{}{
+		"inspections": []map[string]interface{}{{"return_line_id": "l1", "qty": 1, "disposition": "resell", "location": "A-01"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.receiveReq)
+}
+
+func TestCustomerReturnsController_Receive_Returns200(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/customer-returns/r1/receive", map[string]interface{}{
+		"inspections": []map[string]interface{}{
+			{"return_line_id": "l1", "qty": 1, "disposition": "restock", "location": "A-01"},
+			{"return_line_id": "l1", "qty": 1, "disposition": "quarantine", "location": "QC-01", "notes": "caja abierta"},
+		},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.receiveReq)
+	assert.Len(t, repo.receiveReq.Inspections, 2)
+}
+
+func TestCustomerReturnsController_Cancel_Returns409(t *testing.T) {
+	repo := &mockCustomerReturnsCtrlRepo{cancelResp: &responses.InternalResponse{Message: "recibida", Handled: true, StatusCode: responses.StatusConflict}}
+	r := newCustomerReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/customer-returns/r1/cancel", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
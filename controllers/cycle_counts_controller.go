@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// CycleCountsController handles HTTP for count plans and count tasks.
+type CycleCountsController struct {
+	Service      *services.CycleCountsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewCycleCountsController(svc *services.CycleCountsService, tenantID string, auditSvc *services.AuditService) *CycleCountsController {
+	return &CycleCountsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a count task or plan when the audit service is configured.
+func (c *CycleCountsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceCycleCount, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count plans
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CreatePlan handles POST /api/cycle-counts/plans
+func (c *CycleCountsController) CreatePlan(ctx *gin.Context) {
+	var req requests.CreateCountPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateCountPlan", "Datos de solicitud inválidos", "create_count_plan")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateCountPlan", "create_count_plan", errs)
+		return
+	}
+
+	userID := ctx.GetString(tools.ContextKeyUserID)
+
+	plan, resp := c.Service.CreatePlan(c.resolveTenantID(ctx), userID, &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateCountPlan", "create_count_plan", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, plan.ID, plan)
+	tools.ResponseCreated(ctx, "CreateCountPlan", "Plan de conteo creado exitosamente", "create_count_plan", plan, false, "")
+}
+
+// ListPlans handles GET /api/cycle-counts/plans
+func (c *CycleCountsController) ListPlans(ctx *gin.Context) {
+	plans, resp := c.Service.ListPlans(c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "ListCountPlans", "list_count_plans", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListCountPlans", "Planes de conteo recuperados", "list_count_plans", plans, false, "")
+}
+
+// GetPlan handles GET /api/cycle-counts/plans/:id
+func (c *CycleCountsController) GetPlan(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetCountPlan", "get_count_plan", "ID de plan de conteo inválido")
+	if !ok {
+		return
+	}
+
+	plan, resp := c.Service.GetPlanByID(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetCountPlan", "get_count_plan", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetCountPlan", "Plan de conteo recuperado", "get_count_plan", plan, false, "")
+}
+
+// UpdatePlan handles PATCH /api/cycle-counts/plans/:id
+func (c *CycleCountsController) UpdatePlan(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "UpdateCountPlan", "update_count_plan", "ID de plan de conteo inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.UpdateCountPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "UpdateCountPlan", "Datos de solicitud inválidos", "update_count_plan")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "UpdateCountPlan", "update_count_plan", errs)
+		return
+	}
+
+	plan, resp := c.Service.UpdatePlan(id, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "UpdateCountPlan", "update_count_plan", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, plan)
+	tools.ResponseOK(ctx, "UpdateCountPlan", "Plan de conteo actualizado", "update_count_plan", plan, false, "")
+}
+
+// DeletePlan handles DELETE /api/cycle-counts/plans/:id
+func (c *CycleCountsController) DeletePlan(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DeleteCountPlan", "delete_count_plan", "ID de plan de conteo inválido")
+	if !ok {
+		return
+	}
+
+	if resp := c.Service.DeletePlan(id, c.resolveTenantID(ctx)); resp != nil {
+		writeErrorResponse(ctx, "DeleteCountPlan", "delete_count_plan", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionDelete, id, nil)
+	tools.ResponseOK(ctx, "DeleteCountPlan", "Plan de conteo eliminado", "delete_count_plan", nil, false, "")
+}
+
+// GenerateTask handles POST /api/cycle-counts/plans/:id/generate
+func (c *CycleCountsController) GenerateTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GenerateCountTask", "generate_count_task", "ID de plan de conteo inválido")
+	if !ok {
+		return
+	}
+
+	// Body is optional: assigned_to and notes only.
+	var req requests.GenerateCountTaskRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, "GenerateCountTask", "Datos de solicitud inválidos", "generate_count_task")
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, "GenerateCountTask", "generate_count_task", errs)
+			return
+		}
+	}
+
+	userID := ctx.GetString(tools.ContextKeyUserID)
+
+	task, resp := c.Service.GenerateTask(id, c.resolveTenantID(ctx), userID, &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "GenerateCountTask", "generate_count_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, task.ID, task)
+	tools.ResponseCreated(ctx, "GenerateCountTask", "Tarea de conteo generada", "generate_count_task", task, false, "")
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count tasks
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ListTasks handles GET /api/cycle-counts/tasks
+func (c *CycleCountsController) ListTasks(ctx *gin.Context) {
+	var status *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	tasks, resp := c.Service.ListTasks(c.resolveTenantID(ctx), status, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListCountTasks", "list_count_tasks", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListCountTasks", "Tareas de conteo recuperadas", "list_count_tasks", tasks, false, "")
+}
+
+// GetTask handles GET /api/cycle-counts/tasks/:id (blind tasks hide expected quantities).
+func (c *CycleCountsController) GetTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetCountTask", "get_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.GetTask(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetCountTask", "get_count_task", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetCountTask", "Tarea de conteo recuperada", "get_count_task", task, false, "")
+}
+
+// ReviewTask handles GET /api/cycle-counts/tasks/:id/review (expected quantities and variances).
+func (c *CycleCountsController) ReviewTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ReviewCountTask", "review_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.ReviewTask(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "ReviewCountTask", "review_count_task", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ReviewCountTask", "Revisión de tarea de conteo recuperada", "review_count_task", task, false, "")
+}
+
+// StartTask handles PATCH /api/cycle-counts/tasks/:id/start
+func (c *CycleCountsController) StartTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "StartCountTask", "start_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.StartTask(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "StartCountTask", "start_count_task", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "StartCountTask", "Tarea de conteo iniciada", "start_count_task", task, false, "")
+}
+
+// RecordCount handles POST /api/cycle-counts/tasks/:id/lines/:lineId/count
+func (c *CycleCountsController) RecordCount(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RecordCount", "record_count", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+	lineID, ok := tools.ParseRequiredParam(ctx, "lineId", "RecordCount", "record_count", "ID de línea inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.RecordCountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "RecordCount", "Datos de solicitud inválidos", "record_count")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "RecordCount", "record_count", errs)
+		return
+	}
+
+	line, resp := c.Service.RecordCount(id, lineID, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "RecordCount", "record_count", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "RecordCount", "Conteo registrado", "record_count", line, false, "")
+}
+
+// SubmitTask handles PATCH /api/cycle-counts/tasks/:id/submit
+func (c *CycleCountsController) SubmitTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "SubmitCountTask", "submit_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.SubmitTask(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "SubmitCountTask", "submit_count_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, task)
+	tools.ResponseOK(ctx, "SubmitCountTask", "Tarea de conteo enviada a aprobación", "submit_count_task", task, false, "")
+}
+
+// ApproveTask handles PATCH /api/cycle-counts/tasks/:id/approve
+func (c *CycleCountsController) ApproveTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ApproveCountTask", "approve_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.ApproveTask(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "ApproveCountTask", "approve_count_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionExecute, id, task)
+	tools.ResponseOK(ctx, "ApproveCountTask", "Tarea de conteo aprobada y diferencias ajustadas", "approve_count_task", task, false, "")
+}
+
+// RejectTask handles PATCH /api/cycle-counts/tasks/:id/reject
+func (c *CycleCountsController) RejectTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RejectCountTask", "reject_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.RejectCountTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "RejectCountTask", "Datos de solicitud inválidos", "reject_count_task")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "RejectCountTask", "reject_count_task", errs)
+		return
+	}
+
+	task, resp := c.Service.RejectTask(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "RejectCountTask", "reject_count_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, req)
+	tools.ResponseOK(ctx, "RejectCountTask", "Líneas enviadas a reconteo", "reject_count_task", task, false, "")
+}
+
+// CancelTask handles PATCH /api/cycle-counts/tasks/:id/cancel
+func (c *CycleCountsController) CancelTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CancelCountTask", "cancel_count_task", "ID de tarea de conteo inválido")
+	if !ok {
+		return
+	}
+
+	task, resp := c.Service.CancelTask(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "CancelCountTask", "cancel_count_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, task)
+	tools.ResponseOK(ctx, "CancelCountTask", "Tarea de conteo cancelada", "cancel_count_task", task, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *CycleCountsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
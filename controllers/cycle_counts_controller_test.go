@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mock repository for controller tests
+// ─────────────────────────────────────────────────────────────────────────────
+
+type mockCycleCountsCtrlRepo struct {
+	plan      *database.CountPlan
+	task      *database.CountTask
+	lines     []database.CountTaskLine
+	createReq *requests.CreateCountPlanRequest
+}
+
+func (m *mockCycleCountsCtrlRepo) CreatePlan(tenantID, createdBy string, req *requests.CreateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	m.createReq = req
+	return &database.CountPlan{ID: "plan-1", Name: req.Name, ScopeType: req.ScopeType, IsActive: true}, nil
+}
+func (m *mockCycleCountsCtrlRepo) GetPlanByID(id, tenantID string) (*database.CountPlan, *responses.InternalResponse) {
+	if m.plan == nil || m.plan.ID != id {
+		return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.plan, nil
+}
+func (m *mockCycleCountsCtrlRepo) ListPlans(tenantID string) ([]database.CountPlan, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCycleCountsCtrlRepo) UpdatePlan(id, tenantID string, req *requests.UpdateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	return m.plan, nil
+}
+func (m *mockCycleCountsCtrlRepo) SoftDeletePlan(id, tenantID string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockCycleCountsCtrlRepo) GenerateTask(plan *database.CountPlan, createdBy string, req *requests.GenerateCountTaskRequest) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	return m.task, m.lines, nil
+}
+func (m *mockCycleCountsCtrlRepo) GetTaskByID(id, tenantID string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	if m.task == nil || m.task.ID != id {
+		return nil, nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.task, m.lines, nil
+}
+func (m *mockCycleCountsCtrlRepo) ListTasks(tenantID string, status *string, limit, offset int) ([]database.CountTask, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCycleCountsCtrlRepo) TransitionTask(id, tenantID, next, userID string) (*database.CountTask, *responses.InternalResponse) {
+	m.task.Status = next
+	return m.task, nil
+}
+func (m *mockCycleCountsCtrlRepo) SaveLineCount(line *database.CountTaskLine) *responses.InternalResponse {
+	return nil
+}
+func (m *mockCycleCountsCtrlRepo) FlagLinesForRecount(taskID string, lineIDs []string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockCycleCountsCtrlRepo) ApproveTask(id, tenantID, userID, gainReason, lossReason string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	m.task.Status = "approved"
+	return m.task, nil, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+func newCycleCountsTestRouter(repo *mockCycleCountsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := services.NewCycleCountsService(repo, nil)
+	ctrl := NewCycleCountsController(svc, ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	cc := r.Group("/api/cycle-counts")
+	cc.Use(injectUser)
+	cc.POST("/plans", ctrl.CreatePlan)
+	cc.POST("/plans/:id/generate", ctrl.GenerateTask)
+	cc.GET("/tasks/:id", ctrl.GetTask)
+	cc.POST("/tasks/:id/lines/:lineId/count", ctrl.RecordCount)
+	cc.PATCH("/tasks/:id/submit", ctrl.SubmitTask)
+	return r
+}
+
+func sampleCountTaskRepo(status string) *mockCycleCountsCtrlRepo {
+	return &mockCycleCountsCtrlRepo{
+		plan: &database.CountPlan{ID: "plan-1", ScopeType: "zone", IsActive: true, Blind: true},
+		task: &database.CountTask{ID: "task-1", TaskNumber: "CC-2026-0001", Status: status, Blind: true},
+		lines: []database.CountTaskLine{
+			{ID: "line-1", CountTaskID: "task-1", SKU: "SKU-1", Location: "A-01", ExpectedQty: 10, Status: "pending"},
+		},
+	}
+}
+
+func doCycleCountRequest(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Tests
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestCycleCountsController_CreatePlan_Returns201(t *testing.T) {
+	repo := sampleCountTaskRepo("open")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/cycle-counts/plans", map[string]interface{}{
+		"name":         "Zona A semanal",
+		"scope_type":   "zone",
+		"scope_values": []string{"A"},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, "zone", repo.createReq.ScopeType)
+}
+
+func TestCycleCountsController_CreatePlan_Returns400_InvalidScopeType(t *testing.T) {
+	repo := sampleCountTaskRepo("open")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/cycle-counts/plans", map[string]interface{}{
+		"name":         "Bad",
+		"scope_type":   "warehouse",
+		"scope_values": []string{"A"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestCycleCountsController_GenerateTask_EmptyBody_Returns201(t *testing.T) {
+	repo := sampleCountTaskRepo("open")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/cycle-counts/plans/plan-1/generate", nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCycleCountsController_GetTask_BlindHidesExpected(t *testing.T) {
+	repo := sampleCountTaskRepo("in_progress")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/cycle-counts/tasks/task-1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "expected_qty")
+}
+
+func TestCycleCountsController_GetTask_Returns404(t *testing.T) {
+	repo := sampleCountTaskRepo("in_progress")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/cycle-counts/tasks/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCycleCountsController_RecordCount_Returns400_MissingQty(t *testing.T) {
+	repo := sampleCountTaskRepo("in_progress")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/cycle-counts/tasks/task-1/lines/line-1/count", map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCycleCountsController_RecordCount_Returns200(t *testing.T) {
+	repo := sampleCountTaskRepo("in_progress")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/cycle-counts/tasks/task-1/lines/line-1/count", map[string]interface{}{
+		"counted_qty": 10,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCycleCountsController_SubmitTask_Returns400_WhenLinesPending(t *testing.T) {
+	repo := sampleCountTaskRepo("in_progress")
+	r := newCycleCountsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/cycle-counts/tasks/task-1/submit", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
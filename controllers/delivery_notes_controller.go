@@ -1,23 +1,28 @@
 package controllers
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
-	"os"
 	"strconv"
 
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/services"
 	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/gin-gonic/gin"
 )
 
-// DeliveryNotesController handles HTTP for delivery note endpoints (DN3 list + download).
+// DeliveryNotesController handles HTTP for delivery note endpoints (DN3 list + download, proof of delivery).
 type DeliveryNotesController struct {
-	Service  *services.DeliveryNotesService
-	TenantID string
+	Service      *services.DeliveryNotesService
+	TenantID     string
+	AuditService *services.AuditService
 }
 
-func NewDeliveryNotesController(svc *services.DeliveryNotesService, tenantID string) *DeliveryNotesController {
-	return &DeliveryNotesController{Service: svc, TenantID: tenantID}
+func NewDeliveryNotesController(svc *services.DeliveryNotesService, tenantID string, auditSvc *services.AuditService) *DeliveryNotesController {
+	return &DeliveryNotesController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
 }
 
 // List handles GET /api/delivery-notes/
@@ -74,22 +79,20 @@ func (c *DeliveryNotesController) GetByID(ctx *gin.Context) {
 }
 
 // DownloadPDF handles GET /api/delivery-notes/:id/pdf
-// Streams the PDF from local FS. Returns 202 if PDF not yet generated.
+// Streams the PDF from the document storage (regenerated when the stored file is missing).
+// Returns 202 if PDF not yet generated.
 func (c *DeliveryNotesController) DownloadPDF(ctx *gin.Context) {
 	id, ok := tools.ParseRequiredParam(ctx, "id", "DownloadDNPDF", "download_dn_pdf", "ID de nota de entrega inválido")
 	if !ok {
 		return
 	}
 
-	// Verify DN exists and belongs to tenant.
-	dnNumber, resp := c.Service.GetDNNumber(id, c.resolveTenantID(ctx))
+	pdf, dnNumber, resp := c.Service.GetPDF(id, c.resolveTenantID(ctx))
 	if resp != nil {
 		writeErrorResponse(ctx, "DownloadDNPDF", "download_dn_pdf", resp)
 		return
 	}
-
-	pdfPath := services.PDFLocalPath(id)
-	if _, err := os.Stat(pdfPath); os.IsNotExist(err) {
+	if pdf == nil {
 		// PDF not yet generated — return 202 Accepted.
 		ctx.JSON(http.StatusAccepted, gin.H{
 			"message": "PDF not yet generated. Please retry shortly.",
@@ -100,8 +103,100 @@ func (c *DeliveryNotesController) DownloadPDF(ctx *gin.Context) {
 
 	filename := dnNumber + ".pdf"
 	ctx.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
-	ctx.Header("Content-Type", "application/pdf")
-	ctx.File(pdfPath)
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// RecordPOD handles POST /api/delivery-notes/:id/pod (multipart/form-data).
+// Fields: receiver_name, delivered_at (RFC3339, optional), gps, notes; files: signature, photos.
+func (c *DeliveryNotesController) RecordPOD(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RecordDeliveryNotePOD", "record_delivery_note_pod", "ID de nota de entrega inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.RecordPODRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "RecordDeliveryNotePOD", "Datos de prueba de entrega inválidos", "record_delivery_note_pod")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "RecordDeliveryNotePOD", "record_delivery_note_pod", errs)
+		return
+	}
+
+	var signature []byte
+	if fh, err := ctx.FormFile("signature"); err == nil {
+		if signature, err = readPODFile(fh); err != nil {
+			tools.ResponseBadRequest(ctx, "RecordDeliveryNotePOD", "Error al leer la firma", "record_delivery_note_pod")
+			return
+		}
+	}
+	var photos [][]byte
+	if form, err := ctx.MultipartForm(); err == nil && form != nil {
+		for i, fh := range form.File["photos"] {
+			data, err := readPODFile(fh)
+			if err != nil {
+				tools.ResponseBadRequest(ctx, "RecordDeliveryNotePOD", fmt.Sprintf("Error al leer la foto %d", i+1), "record_delivery_note_pod")
+				return
+			}
+			photos = append(photos, data)
+		}
+	}
+
+	dn, resp := c.Service.RecordPOD(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req, signature, photos)
+	if resp != nil {
+		writeErrorResponse(ctx, "RecordDeliveryNotePOD", "record_delivery_note_pod", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, dn)
+	tools.ResponseOK(ctx, "RecordDeliveryNotePOD", "Prueba de entrega registrada", "record_delivery_note_pod", dn, false, "")
+}
+
+// DownloadPODFile handles GET /api/delivery-notes/:id/pod/:file
+// Streams the signature or a photo of the DN's proof of delivery.
+func (c *DeliveryNotesController) DownloadPODFile(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DownloadDNPODFile", "download_dn_pod_file", "ID de nota de entrega inválido")
+	if !ok {
+		return
+	}
+	name, ok := tools.ParseRequiredParam(ctx, "file", "DownloadDNPODFile", "download_dn_pod_file", "Archivo inválido")
+	if !ok {
+		return
+	}
+
+	data, contentType, resp := c.Service.PODFile(id, c.resolveTenantID(ctx), name)
+	if resp != nil {
+		writeErrorResponse(ctx, "DownloadDNPODFile", "download_dn_pod_file", resp)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// readPODFile reads an uploaded POD image, refusing files above services.MaxPODImageBytes
+// (the service reports the size error; one extra byte is enough to detect it).
+func readPODFile(fh *multipart.FileHeader) ([]byte, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(io.LimitReader(file, services.MaxPODImageBytes+1))
+}
+
+// audit logs an action on a delivery note when the audit service is configured.
+func (c *DeliveryNotesController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceDeliveryNote, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
 }
 
 // resolveTenantID — S3.5 W5.5 (HR-S3.5 C1): JWT-first, env fallback only.
@@ -1,13 +1,18 @@
 package controllers
 
 import (
+	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
+	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +28,7 @@ type stubDNRepo struct {
 	getErr      *responses.InternalResponse
 	dnNumber    string
 	dnNumberErr *responses.InternalResponse
+	pod         *database.ProofOfDelivery
 }
 
 func (s *stubDNRepo) List(_ string, _, _ *string, _, _ *string, _, _ int) (*responses.DeliveryNoteListResponse, *responses.InternalResponse) {
@@ -31,18 +37,26 @@ func (s *stubDNRepo) List(_ string, _, _ *string, _, _ *string, _, _ int) (*resp
 func (s *stubDNRepo) GetByID(_, _ string) (*responses.DeliveryNoteResponse, *responses.InternalResponse) {
 	return s.getResult, s.getErr
 }
-func (s *stubDNRepo) UpdatePDFURL(_, _ string) *responses.InternalResponse { return nil }
+func (s *stubDNRepo) UpdatePDFStorageKey(_, _, _ string) *responses.InternalResponse { return nil }
 func (s *stubDNRepo) GetDNNumber(_, _ string) (string, *responses.InternalResponse) {
 	return s.dnNumber, s.dnNumberErr
 }
+func (s *stubDNRepo) RecordPOD(_, _ string, pod *database.ProofOfDelivery) (*database.SalesOrder, *responses.InternalResponse) {
+	s.pod = pod
+	return &database.SalesOrder{ID: "so-1", Status: "delivered"}, nil
+}
 
 // ─────────────────────────────────────────────────────────────────────────────
 // helpers
 // ─────────────────────────────────────────────────────────────────────────────
 
-func dnController(repo *stubDNRepo) *DeliveryNotesController {
-	svc := services.NewDeliveryNotesService(repo, nil)
-	return NewDeliveryNotesController(svc, "tenant-test")
+func dnController(t *testing.T, repo *stubDNRepo) *DeliveryNotesController {
+	// Not t.TempDir: DownloadPDF may start a background generation that writes after the test ends.
+	root, err := os.MkdirTemp("", "estock-dn-test-")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+	svc := services.NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(root))
+	return NewDeliveryNotesController(svc, "tenant-test", nil)
 }
 
 func dnGin(ctrl *DeliveryNotesController) *gin.Engine {
@@ -51,6 +65,8 @@ func dnGin(ctrl *DeliveryNotesController) *gin.Engine {
 	r.GET("/delivery-notes", ctrl.List)
 	r.GET("/delivery-notes/:id", ctrl.GetByID)
 	r.GET("/delivery-notes/:id/pdf", ctrl.DownloadPDF)
+	r.POST("/delivery-notes/:id/pod", ctrl.RecordPOD)
+	r.GET("/delivery-notes/:id/pod/:file", ctrl.DownloadPODFile)
 	return r
 }
 
@@ -63,7 +79,7 @@ func TestDNController_List_OK(t *testing.T) {
 		Items: []responses.DeliveryNoteListItem{{ID: "dn-1", DNNumber: "DN-2026-0001"}},
 		Total: 1, Page: 1, Limit: 50,
 	}}
-	r := dnGin(dnController(repo))
+	r := dnGin(dnController(t, repo))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/delivery-notes", nil)
@@ -81,7 +97,7 @@ func TestDNController_GetByID_OK(t *testing.T) {
 		DNNumber: "DN-2026-0001",
 		Items:    []responses.DeliveryNoteItemResponse{},
 	}}
-	r := dnGin(dnController(repo))
+	r := dnGin(dnController(t, repo))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/delivery-notes/dn-1", nil)
@@ -96,7 +112,7 @@ func TestDNController_GetByID_NotFound(t *testing.T) {
 		Handled:    true,
 		StatusCode: responses.StatusNotFound,
 	}}
-	r := dnGin(dnController(repo))
+	r := dnGin(dnController(t, repo))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/delivery-notes/nonexistent", nil)
@@ -112,7 +128,7 @@ func TestDNController_DownloadPDF_NotFound_DN(t *testing.T) {
 		Handled:    true,
 		StatusCode: responses.StatusNotFound,
 	}}
-	r := dnGin(dnController(repo))
+	r := dnGin(dnController(t, repo))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/delivery-notes/dn-1/pdf", nil)
@@ -122,14 +138,68 @@ func TestDNController_DownloadPDF_NotFound_DN(t *testing.T) {
 }
 
 func TestDNController_DownloadPDF_NotYetGenerated(t *testing.T) {
-	// DN exists but PDF not yet on disk.
-	repo := &stubDNRepo{dnNumber: "DN-2026-0001"}
-	r := dnGin(dnController(repo))
+	// DN exists but its PDF has not been stored yet.
+	repo := &stubDNRepo{dnNumber: "DN-2026-0001", getResult: &responses.DeliveryNoteResponse{ID: "dn-1"}}
+	r := dnGin(dnController(t, repo))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/delivery-notes/nonexistent-dn/pdf", nil)
 	r.ServeHTTP(w, req)
 
-	// PDF file won't exist → 202 Accepted.
+	// No storage key yet → 202 Accepted.
 	require.Equal(t, http.StatusAccepted, w.Code)
 }
+
+// podFormRequest builds a multipart POST to /delivery-notes/dn-1/pod with the given fields.
+func podFormRequest(t *testing.T, fields map[string]string) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		require.NoError(t, mw.WriteField(k, v))
+	}
+	require.NoError(t, mw.Close())
+	req, _ := http.NewRequest("POST", "/delivery-notes/dn-1/pod", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestDNController_RecordPOD_MissingReceiverName(t *testing.T) {
+	repo := &stubDNRepo{}
+	r := dnGin(dnController(t, repo))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, podFormRequest(t, map[string]string{"gps": "9.93,-84.08"}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Nil(t, repo.pod)
+}
+
+func TestDNController_RecordPOD_OK(t *testing.T) {
+	repo := &stubDNRepo{getResult: &responses.DeliveryNoteResponse{ID: "dn-1", DNNumber: "DN-2026-0001"}}
+	r := dnGin(dnController(t, repo))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, podFormRequest(t, map[string]string{
+		"receiver_name": "Ana Mora",
+		"delivered_at":  "2026-10-01T10:30:00Z",
+		"gps":           "9.93,-84.08",
+	}))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.pod)
+	require.Equal(t, "Ana Mora", repo.pod.ReceiverName)
+	require.Equal(t, "9.93,-84.08", *repo.pod.GPS)
+	require.Nil(t, repo.pod.SignaturePath)
+}
+
+func TestDNController_DownloadPODFile_UnknownFile(t *testing.T) {
+	sig := "pod/dn-1/1-signature.png"
+	repo := &stubDNRepo{getResult: &responses.DeliveryNoteResponse{ID: "dn-1", PODSignaturePath: &sig}}
+	r := dnGin(dnController(t, repo))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/delivery-notes/dn-1/pod/other.png", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeRatesController handles HTTP for the tenant's local exchange rates.
+type ExchangeRatesController struct {
+	Service  *services.ExchangeRatesService
+	TenantID string
+}
+
+func NewExchangeRatesController(svc *services.ExchangeRatesService, tenantID string) *ExchangeRatesController {
+	return &ExchangeRatesController{Service: svc, TenantID: tenantID}
+}
+
+// List handles GET /api/settings/exchange-rates
+func (c *ExchangeRatesController) List(ctx *gin.Context) {
+	rates, resp := c.Service.List(c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "ListExchangeRates", "list_exchange_rates", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListExchangeRates", "Tipos de cambio recuperados", "list_exchange_rates", rates, false, "")
+}
+
+// Upsert handles POST /api/settings/exchange-rates
+func (c *ExchangeRatesController) Upsert(ctx *gin.Context) {
+	var req requests.UpsertExchangeRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "UpsertExchangeRate", "Datos de solicitud inválidos", "upsert_exchange_rate")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "UpsertExchangeRate", "upsert_exchange_rate", errs)
+		return
+	}
+
+	userID := ctx.GetString(tools.ContextKeyUserID)
+
+	rate, resp := c.Service.Upsert(c.resolveTenantID(ctx), userID, &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "UpsertExchangeRate", "upsert_exchange_rate", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "UpsertExchangeRate", "Tipo de cambio guardado", "upsert_exchange_rate", rate, false, "")
+}
+
+// Delete handles DELETE /api/settings/exchange-rates/:id
+func (c *ExchangeRatesController) Delete(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DeleteExchangeRate", "delete_exchange_rate", "ID de tipo de cambio inválido")
+	if !ok {
+		return
+	}
+	if resp := c.Service.Delete(id, c.resolveTenantID(ctx)); resp != nil {
+		writeErrorResponse(ctx, "DeleteExchangeRate", "delete_exchange_rate", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "DeleteExchangeRate", "Tipo de cambio eliminado", "delete_exchange_rate", nil, false, "")
+}
+
+// Convert handles GET /api/settings/exchange-rates/convert?from=USD&to=CRC&date=2026-01-31
+// and returns the rate in effect on date (today when omitted).
+func (c *ExchangeRatesController) Convert(ctx *gin.Context) {
+	from, to := ctx.Query("from"), ctx.Query("to")
+	if from == "" || to == "" {
+		tools.ResponseBadRequest(ctx, "ConvertExchangeRate", "Los parámetros from y to son requeridos", "convert_exchange_rate")
+		return
+	}
+	asOf := tools.GetCurrentTime()
+	if d := ctx.Query("date"); d != "" {
+		parsed, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			tools.ResponseBadRequest(ctx, "ConvertExchangeRate", "Fecha inválida (use YYYY-MM-DD)", "convert_exchange_rate")
+			return
+		}
+		asOf = parsed
+	}
+
+	rate, resp := c.Service.GetRate(c.resolveTenantID(ctx), from, to, asOf)
+	if resp != nil {
+		writeErrorResponse(ctx, "ConvertExchangeRate", "convert_exchange_rate", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ConvertExchangeRate", "Tipo de cambio recuperado", "convert_exchange_rate", gin.H{
+		"from": from,
+		"to":   to,
+		"date": asOf.Format("2006-01-02"),
+		"rate": rate,
+	}, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as StockSettingsController).
+func (c *ExchangeRatesController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
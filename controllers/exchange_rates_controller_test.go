@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── mock repo ────────────────────────────────────────────────────────────────
+
+type mockExchangeRatesRepoCtrl struct {
+	upserted *requests.UpsertExchangeRateRequest
+	rate     float64
+	rateErr  *responses.InternalResponse
+	asOf     time.Time
+}
+
+func (m *mockExchangeRatesRepoCtrl) List(_ string) ([]database.ExchangeRate, *responses.InternalResponse) {
+	return []database.ExchangeRate{}, nil
+}
+
+func (m *mockExchangeRatesRepoCtrl) Upsert(_, _ string, req *requests.UpsertExchangeRateRequest) (*database.ExchangeRate, *responses.InternalResponse) {
+	m.upserted = req
+	return &database.ExchangeRate{ID: "er-1", FromCurrency: req.FromCurrency, ToCurrency: req.ToCurrency, Rate: req.Rate}, nil
+}
+
+func (m *mockExchangeRatesRepoCtrl) Delete(_, _ string) *responses.InternalResponse {
+	return nil
+}
+
+func (m *mockExchangeRatesRepoCtrl) BaseCurrency(_ string) (string, *responses.InternalResponse) {
+	return "CRC", nil
+}
+
+func (m *mockExchangeRatesRepoCtrl) GetRate(_, _, _ string, asOf time.Time) (float64, *responses.InternalResponse) {
+	m.asOf = asOf
+	return m.rate, m.rateErr
+}
+
+// ─── helpers ──────────────────────────────────────────────────────────────────
+
+func newExchangeRatesRouter(repo *mockExchangeRatesRepoCtrl) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewExchangeRatesController(services.NewExchangeRatesService(repo), "00000000-0000-0000-0000-000000000001")
+	r := gin.New()
+	r.GET("/settings/exchange-rates", ctrl.List)
+	r.GET("/settings/exchange-rates/convert", ctrl.Convert)
+	r.POST("/settings/exchange-rates", ctrl.Upsert)
+	return r
+}
+
+func postExchangeRate(r *gin.Engine, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/settings/exchange-rates", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// ─── tests ────────────────────────────────────────────────────────────────────
+
+func TestExchangeRatesController_Upsert_HappyPath(t *testing.T) {
+	repo := &mockExchangeRatesRepoCtrl{}
+	w := postExchangeRate(newExchangeRatesRouter(repo), map[string]interface{}{
+		"from_currency":  "USD",
+		"to_currency":    "CRC",
+		"rate":           512.35,
+		"effective_date": "2026-03-01",
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.upserted)
+	assert.Equal(t, 512.35, repo.upserted.Rate)
+}
+
+func TestExchangeRatesController_Upsert_Validation(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"same currency": {"from_currency": "USD", "to_currency": "USD", "rate": 1, "effective_date": "2026-03-01"},
+		"unknown code":  {"from_currency": "USX", "to_currency": "CRC", "rate": 500, "effective_date": "2026-03-01"},
+		"zero rate":     {"from_currency": "USD", "to_currency": "CRC", "rate": 0, "effective_date": "2026-03-01"},
+		"bad date":      {"from_currency": "USD", "to_currency": "CRC", "rate": 500, "effective_date": "01/03/2026"},
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockExchangeRatesRepoCtrl{}
+			w := postExchangeRate(newExchangeRatesRouter(repo), body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Nil(t, repo.upserted)
+		})
+	}
+}
+
+func TestExchangeRatesController_Convert(t *testing.T) {
+	t.Run("rate on date", func(t *testing.T) {
+		repo := &mockExchangeRatesRepoCtrl{rate: 0.002}
+		req := httptest.NewRequest(http.MethodGet, "/settings/exchange-rates/convert?from=CRC&to=USD&date=2026-02-15", nil)
+		w := httptest.NewRecorder()
+		newExchangeRatesRouter(repo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2026-02-15", repo.asOf.Format("2006-01-02"))
+	})
+
+	t.Run("missing pair", func(t *testing.T) {
+		repo := &mockExchangeRatesRepoCtrl{rateErr: &responses.InternalResponse{Message: "No hay tipo de cambio", Handled: true, StatusCode: responses.StatusBadRequest}}
+		req := httptest.NewRequest(http.MethodGet, "/settings/exchange-rates/convert?from=EUR&to=CRC", nil)
+		w := httptest.NewRecorder()
+		newExchangeRatesRouter(repo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/settings/exchange-rates/convert?from=USD", nil)
+		w := httptest.NewRecorder()
+		newExchangeRatesRouter(&mockExchangeRatesRepoCtrl{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
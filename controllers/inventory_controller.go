@@ -13,17 +13,24 @@ import (
 type InventoryController struct {
 	Service   services.InventoryService
 	JWTSecret string
+	TenantID  string
 }
 
-func NewInventoryController(service services.InventoryService, jwtSecret string) *InventoryController {
+func NewInventoryController(service services.InventoryService, jwtSecret, tenantID string) *InventoryController {
 	return &InventoryController{
 		Service:   service,
 		JWTSecret: jwtSecret,
+		TenantID:  tenantID,
 	}
 }
 
+// resolveTenantID — JWT-first, env fallback only (same contract as SalesOrdersController).
+func (c *InventoryController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
+
 func (c *InventoryController) GetAllInventory(ctx *gin.Context) {
-	inventory, response := c.Service.GetAllInventory()
+	inventory, response := c.Service.GetAllInventory(c.resolveTenantID(ctx))
 
 	if response != nil {
 		writeErrorResponse(ctx, "GetAllInventory", "get_all_inventory", response)
@@ -41,7 +48,7 @@ func (c *InventoryController) GetInventoryBySkuAndLocation(ctx *gin.Context) {
 		return
 	}
 
-	item, response := c.Service.GetInventoryBySkuAndLocation(sku, location)
+	item, response := c.Service.GetInventoryBySkuAndLocation(c.resolveTenantID(ctx), sku, location)
 	if response != nil {
 		writeErrorResponse(ctx, "GetInventoryBySkuAndLocation", "get_inventory_by_sku_location", response)
 		return
@@ -65,7 +72,11 @@ func (c *InventoryController) GetPickSuggestions(ctx *gin.Context) {
 			qty = parsed
 		}
 	}
-	resp, errResp := c.Service.GetPickSuggestionsBySKU(sku, qty)
+	suggest := c.Service.GetPickSuggestionsBySKU
+	if minStops, _ := strconv.ParseBool(ctx.Query("min_stops")); minStops {
+		suggest = c.Service.GetPickSuggestionsFewestStops
+	}
+	resp, errResp := suggest(c.resolveTenantID(ctx), sku, qty)
 	if errResp != nil {
 		writeErrorResponse(ctx, "GetPickSuggestions", "get_pick_suggestions", errResp)
 		return
@@ -91,7 +102,7 @@ func (c *InventoryController) CreateInventory(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.CreateInventory(userId, &request)
+	response := c.Service.CreateInventory(c.resolveTenantID(ctx), userId, &request)
 	if response != nil {
 		writeErrorResponse(ctx, "CreateInventory", "create_inventory", response)
 		return
@@ -111,7 +122,7 @@ func (c *InventoryController) UpdateInventory(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.UpdateInventory(&request)
+	response := c.Service.UpdateInventory(c.resolveTenantID(ctx), &request)
 	if response != nil {
 		writeErrorResponse(ctx, "UpdateInventory", "update_inventory", response)
 		return
@@ -124,7 +135,7 @@ func (c *InventoryController) DeleteInventory(ctx *gin.Context) {
 	id := ctx.Param("id")
 	location := ctx.Param("location")
 
-	response := c.Service.DeleteInventory(id, location)
+	response := c.Service.DeleteInventory(c.resolveTenantID(ctx), id, location)
 	if response != nil {
 		writeErrorResponse(ctx, "DeleteInventory", "delete_inventory", response)
 		return
@@ -136,7 +147,7 @@ func (c *InventoryController) DeleteInventory(ctx *gin.Context) {
 func (c *InventoryController) Trend(ctx *gin.Context) {
 	sku := ctx.Param("sku")
 
-	trend, response := c.Service.Trend(sku)
+	trend, response := c.Service.Trend(c.resolveTenantID(ctx), sku)
 	if response != nil {
 		writeErrorResponse(ctx, "Trend", "inventory_trend", response)
 		return
@@ -172,7 +183,7 @@ func (c *InventoryController) ImportInventoryFromExcel(ctx *gin.Context) {
 		return
 	}
 
-	imported, skipped, errResp := c.Service.ImportInventoryFromExcel(userId, fileBytes)
+	imported, skipped, errResp := c.Service.ImportInventoryFromExcel(c.resolveTenantID(ctx), userId, fileBytes)
 	if errResp != nil && len(imported) == 0 {
 		writeErrorResponse(ctx, "ImportInventoryFromExcel", "import_inventory_from_excel", errResp)
 		return
@@ -197,7 +208,7 @@ func (c *InventoryController) ValidateImportRows(ctx *gin.Context) {
 		tools.ResponseBadRequest(ctx, "ValidateImportRows", "No se proporcionaron filas", "validate_inventory_import_rows")
 		return
 	}
-	results, resp := c.Service.ValidateImportRows(rows)
+	results, resp := c.Service.ValidateImportRows(c.resolveTenantID(ctx), rows)
 	if resp != nil {
 		writeErrorResponse(ctx, "ValidateImportRows", "validate_inventory_import_rows", resp)
 		return
@@ -224,7 +235,7 @@ func (c *InventoryController) ImportInventoryFromJSON(ctx *gin.Context) {
 		tools.ResponseBadRequest(ctx, "ImportInventoryFromJSON", "No se proporcionaron filas", "import_inventory_from_json")
 		return
 	}
-	imported, skipped, errResp := c.Service.ImportInventoryFromJSON(userId, rows)
+	imported, skipped, errResp := c.Service.ImportInventoryFromJSON(c.resolveTenantID(ctx), userId, rows)
 	if errResp != nil {
 		writeErrorResponse(ctx, "ImportInventoryFromJSON", "import_inventory_from_json", errResp)
 		return
@@ -251,7 +262,7 @@ func (c *InventoryController) DownloadImportTemplate(ctx *gin.Context) {
 }
 
 func (c *InventoryController) ExportInventoryToExcel(ctx *gin.Context) {
-	fileBytes, response := c.Service.ExportInventoryToExcel()
+	fileBytes, response := c.Service.ExportInventoryToExcel(c.resolveTenantID(ctx))
 	if response != nil {
 		writeErrorResponse(ctx, "ExportInventoryToExcel", "export_inventory_to_excel", response)
 		return
@@ -268,7 +279,7 @@ func (c *InventoryController) GetInventoryLots(ctx *gin.Context) {
 		return
 	}
 
-	lots, response := c.Service.GetInventoryLots(inventoryID)
+	lots, response := c.Service.GetInventoryLots(c.resolveTenantID(ctx), inventoryID)
 	if response != nil {
 		writeErrorResponse(ctx, "GetInventoryLots", "get_inventory_lots", response)
 		return
@@ -283,7 +294,7 @@ func (c *InventoryController) GetInventorySerials(ctx *gin.Context) {
 		return
 	}
 
-	serials, response := c.Service.GetInventorySerials(inventoryID)
+	serials, response := c.Service.GetInventorySerials(c.resolveTenantID(ctx), inventoryID)
 	if response != nil {
 		writeErrorResponse(ctx, "GetInventorySerials", "get_inventory_serials", response)
 		return
@@ -308,7 +319,7 @@ func (c *InventoryController) CreateInventoryLot(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.CreateInventoryLot(id, &request)
+	response := c.Service.CreateInventoryLot(c.resolveTenantID(ctx), id, &request)
 	if response != nil {
 		writeErrorResponse(ctx, "CreateInventoryLot", "create_inventory_lot", response)
 		return
@@ -323,7 +334,7 @@ func (c *InventoryController) DeleteInventoryLot(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.DeleteInventoryLot(id)
+	response := c.Service.DeleteInventoryLot(c.resolveTenantID(ctx), id)
 	if response != nil {
 		writeErrorResponse(ctx, "DeleteInventoryLot", "delete_inventory_lot", response)
 		return
@@ -348,7 +359,7 @@ func (c *InventoryController) CreateInventorySerial(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.CreateInventorySerial(id, &request)
+	response := c.Service.CreateInventorySerial(c.resolveTenantID(ctx), id, &request)
 	if response != nil {
 		writeErrorResponse(ctx, "CreateInventorySerial", "create_inventory_serial", response)
 		return
@@ -363,7 +374,7 @@ func (c *InventoryController) DeleteInventorySerial(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.DeleteInventorySerial(id)
+	response := c.Service.DeleteInventorySerial(c.resolveTenantID(ctx), id)
 	if response != nil {
 		writeErrorResponse(ctx, "DeleteInventorySerial", "delete_inventory_serial", response)
 		return
@@ -372,13 +383,27 @@ func (c *InventoryController) DeleteInventorySerial(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "DeleteInventorySerial", "Serial de inventario eliminado con éxito", "delete_inventory_serial", nil, false, "")
 }
 
-// GetInventoryValuation handles GET /api/inventory/valuation?group_by=article|location|category
+// GetInventoryValuation handles GET /api/inventory/valuation?group_by=article|location|category&currency=USD
 func (c *InventoryController) GetInventoryValuation(ctx *gin.Context) {
 	groupBy := ctx.DefaultQuery("group_by", "article")
-	result, errResp := c.Service.GetValuation(groupBy)
+	result, errResp := c.Service.GetValuation(c.resolveTenantID(ctx), groupBy, ctx.Query("currency"))
 	if errResp != nil {
 		writeErrorResponse(ctx, "GetInventoryValuation", "get_inventory_valuation", errResp)
 		return
 	}
 	tools.ResponseOK(ctx, "GetInventoryValuation", "Valuación de inventario obtenida", "get_inventory_valuation", result, false, "")
 }
+
+// ExportInventoryValuation handles GET /api/inventory/valuation/export?group_by=article|location|category
+func (c *InventoryController) ExportInventoryValuation(ctx *gin.Context) {
+	groupBy := ctx.DefaultQuery("group_by", "article")
+	fileBytes, errResp := c.Service.ExportValuationToExcel(c.resolveTenantID(ctx), groupBy)
+	if errResp != nil {
+		writeErrorResponse(ctx, "ExportInventoryValuation", "export_inventory_valuation", errResp)
+		return
+	}
+
+	ctx.Header("Content-Description", "File Transfer")
+	ctx.Header("Content-Disposition", `attachment; filename="inventory_valuation.xlsx"`)
+	ctx.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", fileBytes)
+}
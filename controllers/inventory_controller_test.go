@@ -37,13 +37,22 @@ type mockInventoryRepoCtrl struct {
 	trendErr     *responses.InternalResponse
 	pickResp     *dto.PickSuggestionResponse
 	suggestErr   *responses.InternalResponse
+	lastTenantID string
+	fewestStops  bool
 }
 
-func (m *mockInventoryRepoCtrl) GetAllInventory() ([]*dto.EnhancedInventory, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetPickSuggestionsFewestStops(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	m.lastTenantID = tenantID
+	m.fewestStops = true
+	return m.pickResp, m.suggestErr
+}
+
+func (m *mockInventoryRepoCtrl) GetAllInventory(tenantID string) ([]*dto.EnhancedInventory, *responses.InternalResponse) {
+	m.lastTenantID = tenantID
 	return m.inventory, nil
 }
 
-func (m *mockInventoryRepoCtrl) GetInventoryBySkuAndLocation(sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetInventoryBySkuAndLocation(tenantID, sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
 	key := sku + ":" + location
 	if m.bySkuLoc != nil {
 		if item, ok := m.bySkuLoc[key]; ok {
@@ -53,27 +62,27 @@ func (m *mockInventoryRepoCtrl) GetInventoryBySkuAndLocation(sku, location strin
 	return nil, nil
 }
 
-func (m *mockInventoryRepoCtrl) CreateInventory(userId string, item *requests.CreateInventory) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) CreateInventory(tenantID, userId string, item *requests.CreateInventory) *responses.InternalResponse {
 	return m.createErr
 }
 
-func (m *mockInventoryRepoCtrl) UpdateInventory(item *requests.UpdateInventory) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) UpdateInventory(tenantID string, item *requests.UpdateInventory) *responses.InternalResponse {
 	return m.updateErr
 }
 
-func (m *mockInventoryRepoCtrl) DeleteInventory(sku, location string) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) DeleteInventory(tenantID, sku, location string) *responses.InternalResponse {
 	return m.deleteErr
 }
 
-func (m *mockInventoryRepoCtrl) Trend(sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) Trend(tenantID, sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
 	return m.trend, m.trendErr
 }
 
-func (m *mockInventoryRepoCtrl) ImportInventoryFromExcel(userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) ImportInventoryFromExcel(tenantID, userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
 	return []string{"inv-1"}, nil, nil
 }
 
-func (m *mockInventoryRepoCtrl) ImportInventoryFromJSON(userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) ImportInventoryFromJSON(tenantID, userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
 	imported := make([]string, len(rows))
 	for i, r := range rows {
 		imported[i] = r.SKU
@@ -81,39 +90,40 @@ func (m *mockInventoryRepoCtrl) ImportInventoryFromJSON(userId string, rows []re
 	return imported, nil, nil
 }
 
-func (m *mockInventoryRepoCtrl) ValidateImportRows(rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) ValidateImportRows(tenantID string, rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
 	return []responses.InventoryValidationResult{}, nil
 }
 
-func (m *mockInventoryRepoCtrl) ExportInventoryToExcel() ([]byte, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) ExportInventoryToExcel(tenantID string) ([]byte, *responses.InternalResponse) {
 	return []byte("xlsx"), nil
 }
 
-func (m *mockInventoryRepoCtrl) GetInventoryLots(inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetInventoryLots(tenantID, inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
 	return m.lots, m.lotsErr
 }
 
-func (m *mockInventoryRepoCtrl) GetInventorySerials(inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetInventorySerials(tenantID, inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
 	return m.serials, m.serialsErr
 }
 
-func (m *mockInventoryRepoCtrl) CreateInventoryLot(id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) CreateInventoryLot(tenantID, id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
 	return m.createLotErr
 }
 
-func (m *mockInventoryRepoCtrl) DeleteInventoryLot(id string) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) DeleteInventoryLot(tenantID, id string) *responses.InternalResponse {
 	return m.deleteLotErr
 }
 
-func (m *mockInventoryRepoCtrl) CreateInventorySerial(id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) CreateInventorySerial(tenantID, id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
 	return m.createSerErr
 }
 
-func (m *mockInventoryRepoCtrl) DeleteInventorySerial(id string) *responses.InternalResponse {
+func (m *mockInventoryRepoCtrl) DeleteInventorySerial(tenantID, id string) *responses.InternalResponse {
 	return m.deleteSerErr
 }
 
-func (m *mockInventoryRepoCtrl) GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	m.lastTenantID = tenantID
 	return m.pickResp, m.suggestErr
 }
 
@@ -121,10 +131,14 @@ func (m *mockInventoryRepoCtrl) GenerateImportTemplate(language string) ([]byte,
 	return []byte("tpl"), nil
 }
 
-func (m *mockInventoryRepoCtrl) GetValuation(_ string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+func (m *mockInventoryRepoCtrl) GetValuation(_, _ string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
 	return nil, nil
 }
 
+func (m *mockInventoryRepoCtrl) ExportValuationToExcel(_, _ string) ([]byte, *responses.InternalResponse) {
+	return []byte("xlsx"), nil
+}
+
 // ─── helpers ─────────────────────────────────────────────────────────────────
 
 const inventoryTestJWTSecret = "test-secret"
@@ -140,7 +154,7 @@ func generateInventoryTestToken(t *testing.T) string {
 
 func newInventoryController(repo *mockInventoryRepoCtrl) *InventoryController {
 	svc := services.NewInventoryService(repo, nil)
-	return NewInventoryController(*svc, inventoryTestJWTSecret)
+	return NewInventoryController(*svc, inventoryTestJWTSecret, "tenant-test")
 }
 
 func performInventoryRequestWithToken(handler gin.HandlerFunc, method, path string, body interface{}, params gin.Params, token string) *httptest.ResponseRecorder {
@@ -185,6 +199,28 @@ func TestInventoryController_GetAllInventory_WithData(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestInventoryController_GetAllInventory_UsesContextTenant(t *testing.T) {
+	repo := &mockInventoryRepoCtrl{inventory: []*dto.EnhancedInventory{}}
+	ctrl := newInventoryController(repo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/inventory", nil)
+	c.Set(tools.ContextKeyTenantID, "tenant-jwt")
+	ctrl.GetAllInventory(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "tenant-jwt", repo.lastTenantID)
+}
+
+func TestInventoryController_GetAllInventory_FallsBackToConfigTenant(t *testing.T) {
+	repo := &mockInventoryRepoCtrl{inventory: []*dto.EnhancedInventory{}}
+	ctrl := newInventoryController(repo)
+	w := performRequest(ctrl.GetAllInventory, "GET", "/inventory", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "tenant-test", repo.lastTenantID)
+}
+
 func TestInventoryController_GetInventoryBySkuAndLocation_Found(t *testing.T) {
 	repo := &mockInventoryRepoCtrl{
 		bySkuLoc: map[string]*dto.EnhancedInventory{
@@ -443,6 +479,21 @@ func TestInventoryController_GetPickSuggestions_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestInventoryController_GetPickSuggestions_MinStops(t *testing.T) {
+	repo := &mockInventoryRepoCtrl{pickResp: &dto.PickSuggestionResponse{Requested: 3, Sufficient: true}}
+	ctrl := newInventoryController(repo)
+
+	w := performRequest(ctrl.GetPickSuggestions, "GET", "/inventory/SKU1/pick?qty=3", nil,
+		gin.Params{{Key: "sku", Value: "SKU1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, repo.fewestStops)
+
+	w = performRequest(ctrl.GetPickSuggestions, "GET", "/inventory/SKU1/pick?qty=3&min_stops=true", nil,
+		gin.Params{{Key: "sku", Value: "SKU1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, repo.fewestStops)
+}
+
 func TestInventoryController_GetPickSuggestions_MissingParam(t *testing.T) {
 	ctrl := newInventoryController(&mockInventoryRepoCtrl{})
 	w := performRequest(ctrl.GetPickSuggestions, "GET", "/inventory//pick", nil,
@@ -62,3 +62,14 @@ func (c *InventoryMovementsController) ListMovements(ctx *gin.Context) {
 
 	tools.ResponseOK(ctx, "ListMovements", "Movimientos de inventario obtenidos", "list_movements", movements, false, "")
 }
+
+// GetMovementCOGS handles GET /api/inventory-movements/:id/cogs
+func (c *InventoryMovementsController) GetMovementCOGS(ctx *gin.Context) {
+	result, response := c.Service.GetMovementCOGS(ctx.Param("id"))
+	if response != nil {
+		writeErrorResponse(ctx, "GetMovementCOGS", "get_movement_cogs", response)
+		return
+	}
+
+	tools.ResponseOK(ctx, "GetMovementCOGS", "Costo del movimiento obtenido", "get_movement_cogs", result, false, "")
+}
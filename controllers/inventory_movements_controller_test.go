@@ -17,6 +17,7 @@ import (
 type mockInventoryMovementsRepoCtrl struct {
 	movements []database.InventoryMovement
 	listErr   *responses.InternalResponse
+	cogs      map[string]*responses.MovementCOGS
 }
 
 func (m *mockInventoryMovementsRepoCtrl) GetAllInventoryMovements(sku string) ([]database.InventoryMovement, *responses.InternalResponse) {
@@ -33,6 +34,13 @@ func (m *mockInventoryMovementsRepoCtrl) ListMovements(_ ports.MovementsFilter)
 	return m.movements, nil
 }
 
+func (m *mockInventoryMovementsRepoCtrl) GetMovementCOGS(id string) (*responses.MovementCOGS, *responses.InternalResponse) {
+	if c, ok := m.cogs[id]; ok {
+		return c, nil
+	}
+	return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+}
+
 // ─── helpers ─────────────────────────────────────────────────────────────────
 
 func newInventoryMovementsController(repo *mockInventoryMovementsRepoCtrl) *InventoryMovementsController {
@@ -90,3 +98,33 @@ func TestInventoryMovementsController_GetAllInventoryMovements_Error(t *testing.
 	w := performRequest(ctrl.GetAllInventoryMovements, "GET", "/inventory-movements/SKU001", nil, gin.Params{{Key: "sku", Value: "SKU001"}})
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestInventoryMovementsController_GetMovementCOGS_ReturnsConsumptions(t *testing.T) {
+	layerID := "layer-1"
+	repo := &mockInventoryMovementsRepoCtrl{
+		cogs: map[string]*responses.MovementCOGS{
+			"mv-out": {
+				MovementID:   "mv-out",
+				SKU:          "SKU001",
+				MovementType: "outbound",
+				Quantity:     5,
+				COGS:         12.5,
+				UnitCost:     2.5,
+				Consumptions: []responses.MovementCostConsumption{
+					{LayerID: &layerID, Quantity: 5, UnitCost: 2.5, Total: 12.5},
+				},
+			},
+		},
+	}
+	ctrl := newInventoryMovementsController(repo)
+	w := performRequest(ctrl.GetMovementCOGS, "GET", "/inventory-movements/mv-out/cogs", nil, gin.Params{{Key: "id", Value: "mv-out"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"cogs":12.5`)
+	assert.Contains(t, w.Body.String(), `"layer_id":"layer-1"`)
+}
+
+func TestInventoryMovementsController_GetMovementCOGS_NotFound(t *testing.T) {
+	ctrl := newInventoryMovementsController(&mockInventoryMovementsRepoCtrl{})
+	w := performRequest(ctrl.GetMovementCOGS, "GET", "/inventory-movements/missing/cogs", nil, gin.Params{{Key: "id", Value: "missing"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// KitsController handles HTTP for kit bills of materials and assembly orders.
+type KitsController struct {
+	Service      *services.KitsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewKitsController(svc *services.KitsService, tenantID string, auditSvc *services.AuditService) *KitsController {
+	return &KitsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a kit or assembly order when the audit service is configured.
+func (c *KitsController) audit(ctx *gin.Context, action, resource, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, resource, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListKits handles GET /api/kits
+func (c *KitsController) ListKits(ctx *gin.Context) {
+	kits, resp := c.Service.ListKits(c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "ListKits", "list_kits", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListKits", "Kits recuperados", "list_kits", kits, false, "")
+}
+
+// GetKit handles GET /api/kits/:sku
+func (c *KitsController) GetKit(ctx *gin.Context) {
+	sku, ok := tools.ParseRequiredParam(ctx, "sku", "GetKit", "get_kit", "SKU de kit inválido")
+	if !ok {
+		return
+	}
+
+	kit, resp := c.Service.GetKit(c.resolveTenantID(ctx), sku)
+	if resp != nil {
+		writeErrorResponse(ctx, "GetKit", "get_kit", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetKit", "Kit recuperado", "get_kit", kit, false, "")
+}
+
+// UpsertKit handles PUT /api/kits/:sku
+func (c *KitsController) UpsertKit(ctx *gin.Context) {
+	sku, ok := tools.ParseRequiredParam(ctx, "sku", "UpsertKit", "upsert_kit", "SKU de kit inválido")
+	if !ok {
+		return
+	}
+	var req requests.UpsertKitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "UpsertKit", "Datos de solicitud inválidos", "upsert_kit")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "UpsertKit", "upsert_kit", errs)
+		return
+	}
+
+	kit, resp := c.Service.UpsertKit(c.resolveTenantID(ctx), sku, ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "UpsertKit", "upsert_kit", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, tools.ResourceKit, kit.ID, req)
+	tools.ResponseOK(ctx, "UpsertKit", "Kit guardado", "upsert_kit", kit, false, "")
+}
+
+// DeleteKit handles DELETE /api/kits/:sku
+func (c *KitsController) DeleteKit(ctx *gin.Context) {
+	sku, ok := tools.ParseRequiredParam(ctx, "sku", "DeleteKit", "delete_kit", "SKU de kit inválido")
+	if !ok {
+		return
+	}
+
+	if resp := c.Service.DeleteKit(c.resolveTenantID(ctx), sku); resp != nil {
+		writeErrorResponse(ctx, "DeleteKit", "delete_kit", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionDelete, tools.ResourceKit, sku, nil)
+	tools.ResponseOK(ctx, "DeleteKit", "Kit eliminado", "delete_kit", nil, false, "")
+}
+
+// ListAssemblyOrders handles GET /api/assembly-orders
+func (c *KitsController) ListAssemblyOrders(ctx *gin.Context) {
+	var status, orderType, kitSKU *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+	if v := ctx.Query("type"); v != "" {
+		orderType = &v
+	}
+	if v := ctx.Query("kit_sku"); v != "" {
+		kitSKU = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	orders, resp := c.Service.ListAssemblyOrders(c.resolveTenantID(ctx), status, orderType, kitSKU, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListAssemblyOrders", "list_assembly_orders", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListAssemblyOrders", "Órdenes de ensamble recuperadas", "list_assembly_orders", orders, false, "")
+}
+
+// GetAssemblyOrder handles GET /api/assembly-orders/:id
+func (c *KitsController) GetAssemblyOrder(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetAssemblyOrder", "get_assembly_order", "ID de orden inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetAssemblyOrder(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetAssemblyOrder", "get_assembly_order", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetAssemblyOrder", "Orden de ensamble recuperada", "get_assembly_order", view, false, "")
+}
+
+// CreateAssemblyOrder handles POST /api/assembly-orders
+func (c *KitsController) CreateAssemblyOrder(ctx *gin.Context) {
+	var req requests.CreateAssemblyOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateAssemblyOrder", "Datos de solicitud inválidos", "create_assembly_order")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateAssemblyOrder", "create_assembly_order", errs)
+		return
+	}
+
+	view, resp := c.Service.CreateAssemblyOrder(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateAssemblyOrder", "create_assembly_order", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, tools.ResourceAssemblyOrder, view.ID, view.AssemblyOrder)
+	tools.ResponseCreated(ctx, "CreateAssemblyOrder", "Orden de ensamble creada", "create_assembly_order", view, false, "")
+}
+
+// CompleteAssemblyOrder handles POST /api/assembly-orders/:id/complete
+func (c *KitsController) CompleteAssemblyOrder(ctx *gin.Context) {
+	c.transition(ctx, "CompleteAssemblyOrder", "complete_assembly_order", "Orden de ensamble completada", tools.ActionExecute, c.Service.CompleteAssemblyOrder)
+}
+
+// CancelAssemblyOrder handles PATCH /api/assembly-orders/:id/cancel
+func (c *KitsController) CancelAssemblyOrder(ctx *gin.Context) {
+	c.transition(ctx, "CancelAssemblyOrder", "cancel_assembly_order", "Orden de ensamble cancelada", tools.ActionUpdate, c.Service.CancelAssemblyOrder)
+}
+
+// transition completes or cancels a draft assembly order.
+func (c *KitsController) transition(ctx *gin.Context, handler, operation, okMessage, action string,
+	apply func(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse)) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", handler, operation, "ID de orden inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := apply(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, handler, operation, resp)
+		return
+	}
+	c.audit(ctx, action, tools.ResourceAssemblyOrder, id, view.AssemblyOrder)
+	tools.ResponseOK(ctx, handler, okMessage, operation, view, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *KitsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
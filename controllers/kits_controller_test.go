@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockKitsCtrlRepo struct {
+	upsertReq    *requests.UpsertKitRequest
+	createReq    *requests.CreateAssemblyOrderRequest
+	completeResp *responses.InternalResponse
+	completedID  string
+}
+
+func (m *mockKitsCtrlRepo) ListKits(tenantID string) ([]responses.KitView, *responses.InternalResponse) {
+	return []responses.KitView{}, nil
+}
+func (m *mockKitsCtrlRepo) GetKit(tenantID, kitSKU string) (*responses.KitView, *responses.InternalResponse) {
+	return &responses.KitView{Kit: database.Kit{ID: "k1", KitSKU: kitSKU}, Components: []responses.KitComponentView{}}, nil
+}
+func (m *mockKitsCtrlRepo) UpsertKit(tenantID, kitSKU, userID string, req *requests.UpsertKitRequest) (*responses.KitView, *responses.InternalResponse) {
+	m.upsertReq = req
+	return &responses.KitView{Kit: database.Kit{ID: "k1", KitSKU: kitSKU}, Components: []responses.KitComponentView{}}, nil
+}
+func (m *mockKitsCtrlRepo) DeleteKit(tenantID, kitSKU string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockKitsCtrlRepo) ListAssemblyOrders(tenantID string, status, orderType, kitSKU *string, limit, offset int) ([]database.AssemblyOrder, *responses.InternalResponse) {
+	return []database.AssemblyOrder{}, nil
+}
+func (m *mockKitsCtrlRepo) GetAssemblyOrder(id, tenantID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id}, Lines: []database.AssemblyOrderLine{}}, nil
+}
+func (m *mockKitsCtrlRepo) CreateAssemblyOrder(tenantID, userID string, req *requests.CreateAssemblyOrderRequest) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	m.createReq = req
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: "a1", Status: database.AssemblyOrderDraft}, Lines: []database.AssemblyOrderLine{}}, nil
+}
+func (m *mockKitsCtrlRepo) CompleteAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	if m.completeResp != nil {
+		return nil, m.completeResp
+	}
+	m.completedID = id
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id, Status: database.AssemblyOrderCompleted}, Lines: []database.AssemblyOrderLine{}}, nil
+}
+func (m *mockKitsCtrlRepo) CancelAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id, Status: database.AssemblyOrderCancelled}, Lines: []database.AssemblyOrderLine{}}, nil
+}
+
+func newKitsTestRouter(repo *mockKitsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := NewKitsController(services.NewKitsService(repo), ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	kits := r.Group("/api/kits")
+	kits.Use(injectUser)
+	kits.GET("/:sku", ctrl.GetKit)
+	kits.PUT("/:sku", ctrl.UpsertKit)
+
+	orders := r.Group("/api/assembly-orders")
+	orders.Use(injectUser)
+	orders.POST("", ctrl.CreateAssemblyOrder)
+	orders.POST("/:id/complete", ctrl.CompleteAssemblyOrder)
+	orders.PATCH("/:id/cancel", ctrl.CancelAssemblyOrder)
+	return r
+}
+
+func TestKitsController_UpsertKit(t *testing.T) {
+	repo := &mockKitsCtrlRepo{}
+	r := newKitsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPut, "/api/kits/KIT-FA", map[string]interface{}{
+		"explode_on_pick": true,
+		"components":      []map[string]interface{}{{"sku": "GAUZE", "quantity": 2}, {"sku": "BANDAGE", "quantity": 1}},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.upsertReq)
+	assert.True(t, repo.upsertReq.ExplodeOnPick)
+	assert.Len(t, repo.upsertReq.Components, 2)
+}
+
+func TestKitsController_UpsertKit_Returns400_WithoutComponents(t *testing.T) {
+	repo := &mockKitsCtrlRepo{}
+	r := newKitsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPut, "/api/kits/KIT-FA", map[string]interface{}{"components": []interface{}{}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.upsertReq)
+}
+
+func TestKitsController_CreateAssemblyOrder(t *testing.T) {
+	repo := &mockKitsCtrlRepo{}
+	r := newKitsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/assembly-orders", map[string]interface{}{
+		"order_type": "assembly", "kit_sku": "KIT-FA", "quantity": 10, "location": "ASM-01",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, 10.0, repo.createReq.Quantity)
+}
+
+func TestKitsController_CreateAssemblyOrder_Returns400_InvalidType(t *testing.T) {
+	repo := &mockKitsCtrlRepo{}
+	r := newKitsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/assembly-orders", map[string]interface{}{
+		"order_type": "repack", "kit_sku": "KIT-FA", "quantity": 1, "location": "ASM-01",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestKitsController_CompleteAssemblyOrder(t *testing.T) {
+	repo := &mockKitsCtrlRepo{}
+	r := newKitsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/assembly-orders/a1/complete", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a1", repo.completedID)
+
+	repo.completeResp = &responses.InternalResponse{Message: "Stock insuficiente", Handled: true, StatusCode: responses.StatusConflict}
+	w = doCycleCountRequest(r, http.MethodPost, "/api/assembly-orders/a1/complete", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
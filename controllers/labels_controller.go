@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// LabelsController handles HTTP for printable barcode labels. Every endpoint accepts
+// ?format=pdf|zpl, ?symbology=code128|gs1-128|qr and ?copies=N (the batch endpoint takes
+// them in the body) and streams the rendered document.
+type LabelsController struct {
+	Service  *services.LabelsService
+	TenantID string
+}
+
+func NewLabelsController(svc *services.LabelsService, tenantID string) *LabelsController {
+	return &LabelsController{Service: svc, TenantID: tenantID}
+}
+
+// LocationLabel handles GET /api/labels/locations/:id
+func (c *LabelsController) LocationLabel(ctx *gin.Context) {
+	c.singleLabel(ctx, responses.LabelKindLocation, "LocationLabel", "location_label")
+}
+
+// ArticleLabel handles GET /api/labels/articles/:id
+func (c *LabelsController) ArticleLabel(ctx *gin.Context) {
+	c.singleLabel(ctx, responses.LabelKindArticle, "ArticleLabel", "article_label")
+}
+
+// LotLabel handles GET /api/labels/lots/:id
+func (c *LabelsController) LotLabel(ctx *gin.Context) {
+	c.singleLabel(ctx, responses.LabelKindLot, "LotLabel", "lot_label")
+}
+
+// SerialLabel handles GET /api/labels/serials/:id
+func (c *LabelsController) SerialLabel(ctx *gin.Context) {
+	c.singleLabel(ctx, responses.LabelKindSerial, "SerialLabel", "serial_label")
+}
+
+// BatchLabels handles POST /api/labels/batch
+func (c *LabelsController) BatchLabels(ctx *gin.Context) {
+	var req requests.LabelBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "BatchLabels", "Datos de solicitud inválidos", "batch_labels")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "BatchLabels", "batch_labels", errs)
+		return
+	}
+	doc, resp := c.Service.RenderLabels(c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "BatchLabels", "batch_labels", resp)
+		return
+	}
+	writeLabelDocument(ctx, doc)
+}
+
+// ReceivingTaskLabels handles GET /api/labels/receiving-tasks/:id — every lot, serial and
+// untracked article label of a completed receiving task.
+func (c *LabelsController) ReceivingTaskLabels(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ReceivingTaskLabels", "receiving_task_labels", "ID de tarea de recepción inválido")
+	if !ok {
+		return
+	}
+	req, ok := labelQuery(ctx, responses.LabelKindArticle, id, "ReceivingTaskLabels", "receiving_task_labels")
+	if !ok {
+		return
+	}
+	doc, resp := c.Service.RenderReceivingTaskLabels(c.resolveTenantID(ctx), id, req.Format, req.Symbology, req.Copies)
+	if resp != nil {
+		writeErrorResponse(ctx, "ReceivingTaskLabels", "receiving_task_labels", resp)
+		return
+	}
+	writeLabelDocument(ctx, doc)
+}
+
+func (c *LabelsController) singleLabel(ctx *gin.Context, kind, fn, code string) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", fn, code, "ID inválido")
+	if !ok {
+		return
+	}
+	req, ok := labelQuery(ctx, kind, id, fn, code)
+	if !ok {
+		return
+	}
+	doc, resp := c.Service.RenderLabels(c.resolveTenantID(ctx), req)
+	if resp != nil {
+		writeErrorResponse(ctx, fn, code, resp)
+		return
+	}
+	writeLabelDocument(ctx, doc)
+}
+
+// labelQuery reads format/symbology/copies from the query string and validates them with
+// the same rules as the batch body.
+func labelQuery(ctx *gin.Context, kind, id, fn, code string) (*requests.LabelBatchRequest, bool) {
+	req := &requests.LabelBatchRequest{
+		Kind:      kind,
+		IDs:       []string{id},
+		Format:    ctx.Query("format"),
+		Symbology: ctx.Query("symbology"),
+	}
+	if raw := ctx.Query("copies"); raw != "" {
+		copies, err := strconv.Atoi(raw)
+		if err != nil {
+			tools.ResponseBadRequest(ctx, fn, "El parámetro copies debe ser un número entero", code)
+			return nil, false
+		}
+		req.Copies = copies
+	}
+	if errs := tools.ValidateStruct(req); errs != nil {
+		tools.ResponseValidationError(ctx, fn, code, errs)
+		return nil, false
+	}
+	return req, true
+}
+
+func writeLabelDocument(ctx *gin.Context, doc *services.LabelDocument) {
+	ctx.Header("Content-Disposition", `attachment; filename="`+doc.Filename+`"`)
+	ctx.Data(200, doc.ContentType, doc.Data)
+}
+
+// resolveTenantID — JWT-first, env fallback only.
+func (c *LabelsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
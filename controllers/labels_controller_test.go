@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLabelsRepoCtrl struct {
+	kind string
+	ids  []string
+}
+
+func (m *mockLabelsRepoCtrl) GetLabelItems(_, kind string, ids []string) ([]responses.LabelItem, *responses.InternalResponse) {
+	m.kind, m.ids = kind, ids
+	items := make([]responses.LabelItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, responses.LabelItem{Kind: kind, ID: id, Code: "CODE-" + id, SKU: "SKU-1"})
+	}
+	return items, nil
+}
+
+func (m *mockLabelsRepoCtrl) GetReceivingTaskLabelItems(_, _ string) ([]responses.LabelItem, *responses.InternalResponse) {
+	return nil, &responses.InternalResponse{Message: "Tarea de recepción no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+}
+
+func newLabelsRouter(repo *mockLabelsRepoCtrl) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewLabelsController(services.NewLabelsService(repo), "00000000-0000-0000-0000-000000000001")
+	r := gin.New()
+	r.GET("/labels/locations/:id", ctrl.LocationLabel)
+	r.GET("/labels/serials/:id", ctrl.SerialLabel)
+	r.GET("/labels/receiving-tasks/:id", ctrl.ReceivingTaskLabels)
+	r.POST("/labels/batch", ctrl.BatchLabels)
+	return r
+}
+
+func TestLabelsController_LocationLabel_PDF(t *testing.T) {
+	repo := &mockLabelsRepoCtrl{}
+	w := httptest.NewRecorder()
+	newLabelsRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels/locations/loc-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "labels-location.pdf")
+	assert.Equal(t, responses.LabelKindLocation, repo.kind)
+	assert.Equal(t, []string{"loc-1"}, repo.ids)
+}
+
+func TestLabelsController_SerialLabel_ZPL(t *testing.T) {
+	w := httptest.NewRecorder()
+	newLabelsRouter(&mockLabelsRepoCtrl{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels/serials/s-1?format=zpl&copies=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "^PQ2")
+}
+
+func TestLabelsController_InvalidQuery(t *testing.T) {
+	for _, q := range []string{"?format=png", "?symbology=ean13", "?copies=abc", "?copies=101"} {
+		w := httptest.NewRecorder()
+		newLabelsRouter(&mockLabelsRepoCtrl{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels/locations/loc-1"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
+
+func TestLabelsController_BatchLabels(t *testing.T) {
+	repo := &mockLabelsRepoCtrl{}
+	body, _ := json.Marshal(requests.LabelBatchRequest{Kind: "article", IDs: []string{"a-1", "a-2"}, Format: "zpl"})
+	w := httptest.NewRecorder()
+	newLabelsRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/labels/batch", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, bytes.Count(w.Body.Bytes(), []byte("^XA")))
+	assert.Equal(t, []string{"a-1", "a-2"}, repo.ids)
+
+	body, _ = json.Marshal(requests.LabelBatchRequest{Kind: "pallet", IDs: []string{"a-1"}})
+	w = httptest.NewRecorder()
+	newLabelsRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/labels/batch", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLabelsController_ReceivingTaskLabels_NotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	newLabelsRouter(&mockLabelsRepoCtrl{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels/receiving-tasks/rt-1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -41,6 +41,17 @@ func (c *LocationsController) GetAllLocations(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "GetAllLocations", "Ubicaciones obtenidas con éxito", "get_all_locations", locations, false, "")
 }
 
+// GetLocationUtilization handles GET /api/locations/utilization?zone=&over_capacity=true
+func (c *LocationsController) GetLocationUtilization(ctx *gin.Context) {
+	overOnly := ctx.Query("over_capacity") == "true"
+	rows, response := c.Service.GetLocationUtilization(c.resolveTenantID(ctx), ctx.Query("zone"), overOnly)
+	if response != nil {
+		writeErrorResponse(ctx, "GetLocationUtilization", "get_location_utilization", response)
+		return
+	}
+	tools.ResponseOK(ctx, "GetLocationUtilization", "Utilización de ubicaciones obtenida con éxito", "get_location_utilization", rows, false, "")
+}
+
 func (c *LocationsController) GetLocationByID(ctx *gin.Context) {
 	id, ok := tools.ParseRequiredParam(ctx, "id", "GetLocationByID", "get_location_by_id", "ID de ubicación inválido")
 	if !ok {
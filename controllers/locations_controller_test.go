@@ -28,6 +28,7 @@ type mockLocationsRepoCtrl struct {
 	createErr    *responses.InternalResponse
 	updateErr    *responses.InternalResponse
 	deleteErr    *responses.InternalResponse
+	utilization  []responses.LocationUtilization
 	gotTenantIDs []string
 }
 
@@ -91,6 +92,11 @@ func (m *mockLocationsRepoCtrl) GenerateImportTemplate(language string) ([]byte,
 	return []byte("tpl"), nil
 }
 
+func (m *mockLocationsRepoCtrl) GetLocationUtilization(tenantID string) ([]responses.LocationUtilization, *responses.InternalResponse) {
+	m.recordTenant(tenantID)
+	return m.utilization, nil
+}
+
 // ─── helper ──────────────────────────────────────────────────────────────────
 
 func newLocationsController(repo *mockLocationsRepoCtrl) *LocationsController {
@@ -275,3 +281,19 @@ func TestLocationsController_TenantIsolation_GetAll_forwardsControllerTenant(t *
 	}
 	assert.NotContains(t, repo.gotTenantIDs, ctrlTenantB)
 }
+
+func TestLocationsController_GetLocationUtilization(t *testing.T) {
+	limit := 10.0
+	repo := &mockLocationsRepoCtrl{utilization: []responses.LocationUtilization{
+		{LocationCode: "A-01", MaxUnits: &limit, UsedUnits: 12},
+		{LocationCode: "A-02", MaxUnits: &limit, UsedUnits: 2},
+	}}
+	ctrl := newLocationsController(repo)
+
+	w := performRequest(ctrl.GetLocationUtilization, "GET", "/locations/utilization?over_capacity=true", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"location_code":"A-01"`)
+	assert.NotContains(t, w.Body.String(), `"location_code":"A-02"`)
+	assert.Contains(t, w.Body.String(), `"over_capacity":true`)
+	assert.Equal(t, []string{ctrlTenantA}, repo.gotTenantIDs)
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// LotRecallsController handles HTTP for lot recalls.
+type LotRecallsController struct {
+	Service      *services.LotRecallsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewLotRecallsController(svc *services.LotRecallsService, tenantID string, auditSvc *services.AuditService) *LotRecallsController {
+	return &LotRecallsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a lot recall when the audit service is configured.
+func (c *LotRecallsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceLotRecall, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListRecalls handles GET /api/lot-recalls
+func (c *LotRecallsController) ListRecalls(ctx *gin.Context) {
+	var status, sku *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+	if v := ctx.Query("sku"); v != "" {
+		sku = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	recalls, resp := c.Service.ListRecalls(c.resolveTenantID(ctx), status, sku, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListLotRecalls", "list_lot_recalls", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListLotRecalls", "Retiros de lote recuperados", "list_lot_recalls", recalls, false, "")
+}
+
+// GetRecall handles GET /api/lot-recalls/:id
+func (c *LotRecallsController) GetRecall(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetLotRecall", "get_lot_recall", "ID de retiro inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetRecall(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetLotRecall", "get_lot_recall", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetLotRecall", "Retiro de lote recuperado", "get_lot_recall", view, false, "")
+}
+
+// DownloadReport handles GET /api/lot-recalls/:id/report?format=pdf|xlsx (default pdf).
+func (c *LotRecallsController) DownloadReport(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DownloadLotRecallReport", "download_lot_recall_report", "ID de retiro inválido")
+	if !ok {
+		return
+	}
+
+	data, filename, contentType, resp := c.Service.GetReport(id, c.resolveTenantID(ctx), ctx.DefaultQuery("format", services.RecallReportPDF))
+	if resp != nil {
+		writeErrorResponse(ctx, "DownloadLotRecallReport", "download_lot_recall_report", resp)
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// CreateRecall handles POST /api/lot-recalls
+func (c *LotRecallsController) CreateRecall(ctx *gin.Context) {
+	var req requests.CreateLotRecallRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateLotRecall", "Datos de solicitud inválidos", "create_lot_recall")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateLotRecall", "create_lot_recall", errs)
+		return
+	}
+
+	view, resp := c.Service.CreateRecall(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateLotRecall", "create_lot_recall", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, view.ID, view.LotRecall)
+	tools.ResponseCreated(ctx, "CreateLotRecall", "Retiro de lote creado", "create_lot_recall", view, false, "")
+}
+
+// CompleteTask handles PATCH /api/lot-recalls/:id/tasks/:taskId/complete
+func (c *LotRecallsController) CompleteTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CompleteLotRecallTask", "complete_lot_recall_task", "ID de retiro inválido")
+	if !ok {
+		return
+	}
+	taskID, ok := tools.ParseRequiredParam(ctx, "taskId", "CompleteLotRecallTask", "complete_lot_recall_task", "ID de tarea inválido")
+	if !ok {
+		return
+	}
+
+	// The body is optional (qty, notes).
+	var req requests.CompleteRecallTaskRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, "CompleteLotRecallTask", "Datos de solicitud inválidos", "complete_lot_recall_task")
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, "CompleteLotRecallTask", "complete_lot_recall_task", errs)
+			return
+		}
+	}
+
+	view, resp := c.Service.CompleteTask(id, c.resolveTenantID(ctx), taskID, ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CompleteLotRecallTask", "complete_lot_recall_task", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, req)
+	tools.ResponseOK(ctx, "CompleteLotRecallTask", "Tarea de retiro completada", "complete_lot_recall_task", view, false, "")
+}
+
+// CloseRecall handles PATCH /api/lot-recalls/:id/close
+func (c *LotRecallsController) CloseRecall(ctx *gin.Context) {
+	c.end(ctx, "CloseLotRecall", "close_lot_recall", "Retiro de lote cerrado", c.Service.CloseRecall)
+}
+
+// CancelRecall handles PATCH /api/lot-recalls/:id/cancel
+func (c *LotRecallsController) CancelRecall(ctx *gin.Context) {
+	c.end(ctx, "CancelLotRecall", "cancel_lot_recall", "Retiro de lote cancelado", c.Service.CancelRecall)
+}
+
+// end binds the optional notes and closes or cancels the recall.
+func (c *LotRecallsController) end(ctx *gin.Context, handler, operation, okMessage string,
+	apply func(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse)) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", handler, operation, "ID de retiro inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.CloseLotRecallRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, handler, "Datos de solicitud inválidos", operation)
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, handler, operation, errs)
+			return
+		}
+	}
+
+	view, resp := apply(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, handler, operation, resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, view.LotRecall)
+	tools.ResponseOK(ctx, handler, okMessage, operation, view, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *LotRecallsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
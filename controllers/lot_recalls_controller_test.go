@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLotRecallsCtrlRepo struct {
+	createReq   *requests.CreateLotRecallRequest
+	completeReq *requests.CompleteRecallTaskRequest
+	closeResp   *responses.InternalResponse
+}
+
+func recallCtrlView(id, status string) *responses.LotRecallView {
+	return &responses.LotRecallView{
+		LotRecall: database.LotRecall{ID: id, RecallNumber: "RCL-2026-0001", SKU: "SKU-1", LotNumber: "L1", Status: status, Reason: "Contaminación"},
+		Tasks:     []database.LotRecallTask{},
+		Affected: responses.LotRecallAffected{
+			Customers: []responses.RecallCustomer{}, SalesOrders: []responses.RecallSalesOrder{},
+			DeliveryNotes: []responses.RecallDelivery{}, PickingTasks: []responses.RecallPickingTask{},
+		},
+	}
+}
+
+func (m *mockLotRecallsCtrlRepo) ListRecalls(tenantID string, status, sku *string, limit, offset int) ([]database.LotRecall, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockLotRecallsCtrlRepo) GetRecall(id, tenantID string) (*responses.LotRecallView, *responses.InternalResponse) {
+	return recallCtrlView(id, database.LotRecallOpen), nil
+}
+func (m *mockLotRecallsCtrlRepo) CreateRecall(tenantID, userID string, req *requests.CreateLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.createReq = req
+	return recallCtrlView("r1", database.LotRecallOpen), nil
+}
+func (m *mockLotRecallsCtrlRepo) CompleteTask(id, tenantID, taskID, userID string, req *requests.CompleteRecallTaskRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.completeReq = req
+	return recallCtrlView(id, database.LotRecallOpen), nil
+}
+func (m *mockLotRecallsCtrlRepo) CloseRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	if m.closeResp != nil {
+		return nil, m.closeResp
+	}
+	return recallCtrlView(id, database.LotRecallClosed), nil
+}
+func (m *mockLotRecallsCtrlRepo) CancelRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	return recallCtrlView(id, database.LotRecallCancelled), nil
+}
+func (m *mockLotRecallsCtrlRepo) TenantAdminIDs(tenantID string) ([]string, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func newLotRecallsTestRouter(repo *mockLotRecallsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := NewLotRecallsController(services.NewLotRecallsService(repo), ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	rg := r.Group("/api/lot-recalls")
+	rg.Use(injectUser)
+	rg.POST("", ctrl.CreateRecall)
+	rg.GET("/:id/report", ctrl.DownloadReport)
+	rg.PATCH("/:id/tasks/:taskId/complete", ctrl.CompleteTask)
+	rg.PATCH("/:id/close", ctrl.CloseRecall)
+	return r
+}
+
+func TestLotRecallsController_Create_Returns201(t *testing.T) {
+	repo := &mockLotRecallsCtrlRepo{}
+	r := newLotRecallsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/lot-recalls", map[string]interface{}{
+		"lot_id": "lot-1", "reason": "Contaminación cruzada", "classification": "I", "return_location": "RECALL-01",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, "RECALL-01", *repo.createReq.ReturnLocation)
+}
+
+func TestLotRecallsController_Create_Returns400_InvalidClassification(t *testing.T) {
+	repo := &mockLotRecallsCtrlRepo{}
+	r := newLotRecallsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/lot-recalls", map[string]interface{}{
+		"lot_id": "lot-1", "reason": "x", "classification": "IV",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestLotRecallsController_CompleteTask_WithoutBody(t *testing.T) {
+	repo := &mockLotRecallsCtrlRepo{}
+	r := newLotRecallsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/lot-recalls/r1/tasks/t1/complete", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.completeReq)
+	assert.Nil(t, repo.completeReq.Qty)
+}
+
+func TestLotRecallsController_Close_Conflict(t *testing.T) {
+	repo := &mockLotRecallsCtrlRepo{closeResp: &responses.InternalResponse{Message: "tareas pendientes", Handled: true, StatusCode: responses.StatusConflict}}
+	r := newLotRecallsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/lot-recalls/r1/close", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestLotRecallsController_DownloadReport(t *testing.T) {
+	r := newLotRecallsTestRouter(&mockLotRecallsCtrlRepo{})
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/lot-recalls/r1/report?format=xlsx", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "spreadsheetml")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "RCL-2026-0001.xlsx")
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("PK")))
+
+	w = doCycleCountRequest(r, http.MethodGet, "/api/lot-recalls/r1/report", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+
+	w = doCycleCountRequest(r, http.MethodGet, "/api/lot-recalls/r1/report?format=doc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
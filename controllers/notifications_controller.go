@@ -141,7 +141,8 @@ func (c *NotificationsController) UpsertPreferences(ctx *gin.Context) {
 	validEventTypes := map[string]bool{
 		"task_assigned": true, "task_completed": true,
 		"lot_expiring_7d": true, "lot_expiring_1d": true,
-		"low_stock": true, "user_welcome": true,
+		"low_stock": true, "user_welcome": true, "order_delivered": true,
+		"lot_recall": true,
 	}
 
 	for _, item := range body {
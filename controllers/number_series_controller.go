@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// NumberSeriesController handles HTTP for the lot and serial number series.
+type NumberSeriesController struct {
+	Service  *services.NumberSeriesService
+	TenantID string
+}
+
+func NewNumberSeriesController(svc *services.NumberSeriesService, tenantID string) *NumberSeriesController {
+	return &NumberSeriesController{Service: svc, TenantID: tenantID}
+}
+
+// Preview handles GET /api/settings/number-series/preview?kind=lot|serial&sku=&pattern=&count=
+func (c *NumberSeriesController) Preview(ctx *gin.Context) {
+	var req requests.NumberSeriesPreviewRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "PreviewNumberSeries", "Parámetros inválidos", "preview_number_series")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "PreviewNumberSeries", "preview_number_series", errs)
+		return
+	}
+
+	preview, resp := c.Service.Preview(c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "PreviewNumberSeries", "preview_number_series", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "PreviewNumberSeries", "Próximos números de la serie", "preview_number_series", preview, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as StockSettingsController).
+func (c *NumberSeriesController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
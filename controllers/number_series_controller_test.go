@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNumberSeriesRepoCtrl struct {
+	gotReq *requests.NumberSeriesPreviewRequest
+}
+
+func (m *mockNumberSeriesRepoCtrl) Preview(tenantID string, req *requests.NumberSeriesPreviewRequest) (*responses.NumberSeriesPreview, *responses.InternalResponse) {
+	m.gotReq = req
+	return &responses.NumberSeriesPreview{Kind: req.Kind, Pattern: "LOT-{####}", Next: []string{"LOT-0001"}}, nil
+}
+
+func TestNumberSeriesController_Preview(t *testing.T) {
+	repo := &mockNumberSeriesRepoCtrl{}
+	ctrl := NewNumberSeriesController(services.NewNumberSeriesService(repo), ctrlTenantA)
+
+	w := performRequest(ctrl.Preview, "GET", "/settings/number-series/preview?kind=lot&sku=SKU-1&count=3", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, repo.gotReq) {
+		assert.Equal(t, "SKU-1", repo.gotReq.SKU)
+		assert.Equal(t, 3, repo.gotReq.Count)
+	}
+}
+
+func TestNumberSeriesController_Preview_InvalidQuery(t *testing.T) {
+	ctrl := NewNumberSeriesController(services.NewNumberSeriesService(&mockNumberSeriesRepoCtrl{}), ctrlTenantA)
+
+	for _, path := range []string{
+		"/settings/number-series/preview",
+		"/settings/number-series/preview?kind=pallet",
+		"/settings/number-series/preview?kind=serial&count=500",
+	} {
+		w := performRequest(ctrl.Preview, "GET", path, nil, nil)
+		assert.NotEqual(t, http.StatusOK, w.Code, path)
+	}
+}
@@ -55,6 +55,21 @@ func (c *PickingTasksController) GetPickingTaskByID(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "GetPickingTaskByID", "Tarea de picking recuperada con éxito", "get_picking_task_by_id", task, false, "")
 }
 
+// GetPickRoute handles GET /api/picking-tasks/:id/route — the task's allocations grouped by
+// location in walk order.
+func (c *PickingTasksController) GetPickRoute(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetPickRoute", "get_pick_route", "ID de tarea inválido")
+	if !ok {
+		return
+	}
+	route, response := c.Service.GetPickRoute(id, c.resolveTenantID(ctx))
+	if response != nil {
+		writeErrorResponse(ctx, "GetPickRoute", "get_pick_route", response)
+		return
+	}
+	tools.ResponseOK(ctx, "GetPickRoute", "Ruta de picking recuperada con éxito", "get_pick_route", route, false, "")
+}
+
 func (c *PickingTasksController) CreatePickingTask(ctx *gin.Context) {
 	var request requests.CreatePickingTaskRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
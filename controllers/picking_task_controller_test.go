@@ -12,6 +12,7 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/services"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ─── mock repo ───────────────────────────────────────────────────────────────
@@ -84,6 +85,10 @@ func (m *mockPickingTaskRepoCtrl) LinkCustomer(taskID string, customerID *string
 	return nil
 }
 
+func (m *mockPickingTaskRepoCtrl) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	return nil, nil
+}
+
 // ─── helpers ─────────────────────────────────────────────────────────────────
 
 func newPickingTasksController(repo *mockPickingTaskRepoCtrl) *PickingTasksController {
@@ -289,6 +294,39 @@ func TestPickingTasksController_CompletePickingLine_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestPickingTasksController_CompletePickingLine_OverPickReturnsExcess(t *testing.T) {
+	repo := &mockPickingTaskRepoCtrl{
+		completeLineErr: &responses.InternalResponse{
+			Message:    "La cantidad pickeada excede el máximo permitido",
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+			Details: &responses.AllowanceExceeded{
+				Rule: responses.AllowanceOverPicking, SKU: "SKU-001", Location: "LOC-A",
+				BaseQty: 5, MaxAllowedQty: 5, RequestedQty: 7, ExcessQty: 2,
+			},
+		},
+	}
+	ctrl := newPickingTasksController(repo)
+	body := requests.PickingTaskItemRequest{
+		SKU:              "SKU-001",
+		ExpectedQuantity: 5,
+		Allocations: []database.LocationAllocation{
+			{Location: "LOC-A", Quantity: 5},
+		},
+	}
+	w := performRequestWithHeader(ctrl.CompletePickingLine, "PATCH", "/picking-tasks/pt-1/complete-line", body,
+		gin.Params{{Key: "id", Value: "pt-1"}},
+		map[string]string{"Authorization": makeTestToken()})
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	var resp struct {
+		Data responses.AllowanceExceeded `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, responses.AllowanceOverPicking, resp.Data.Rule)
+	assert.Equal(t, 2.0, resp.Data.ExcessQty)
+}
+
 func TestPickingTasksController_CompletePickingLine_Unauthorized(t *testing.T) {
 	ctrl := newPickingTasksController(&mockPickingTaskRepoCtrl{})
 	// Body must pass struct validation so the controller reaches the auth check.
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// PickingWavesController handles HTTP for picking waves (batch picking of several tasks).
+type PickingWavesController struct {
+	Service      *services.PickingWavesService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewPickingWavesController(svc *services.PickingWavesService, tenantID string, auditSvc *services.AuditService) *PickingWavesController {
+	return &PickingWavesController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a wave when the audit service is configured.
+func (c *PickingWavesController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourcePickingWave, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// CreateWave handles POST /api/picking-waves
+func (c *PickingWavesController) CreateWave(ctx *gin.Context) {
+	var req requests.CreatePickingWaveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreatePickingWave", "Datos de solicitud inválidos", "create_picking_wave")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreatePickingWave", "create_picking_wave", errs)
+		return
+	}
+
+	wave, resp := c.Service.CreateWave(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreatePickingWave", "create_picking_wave", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, wave.Wave.ID, wave)
+	tools.ResponseCreated(ctx, "CreatePickingWave", "Ola de picking creada exitosamente", "create_picking_wave", wave, false, "")
+}
+
+// ListWaves handles GET /api/picking-waves
+func (c *PickingWavesController) ListWaves(ctx *gin.Context) {
+	var status *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	waves, resp := c.Service.ListWaves(c.resolveTenantID(ctx), status, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListPickingWaves", "list_picking_waves", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListPickingWaves", "Olas de picking recuperadas", "list_picking_waves", waves, false, "")
+}
+
+// GetWave handles GET /api/picking-waves/:id
+func (c *PickingWavesController) GetWave(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetPickingWave", "get_picking_wave", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	wave, resp := c.Service.GetWave(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetPickingWave", "get_picking_wave", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetPickingWave", "Ola de picking recuperada", "get_picking_wave", wave, false, "")
+}
+
+// GetPickList handles GET /api/picking-waves/:id/pick-list
+func (c *PickingWavesController) GetPickList(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetWavePickList", "get_wave_pick_list", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	list, resp := c.Service.GetPickList(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetWavePickList", "get_wave_pick_list", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetWavePickList", "Lista de picking de la ola recuperada", "get_wave_pick_list", list, false, "")
+}
+
+// StartWave handles PATCH /api/picking-waves/:id/start
+func (c *PickingWavesController) StartWave(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "StartPickingWave", "start_picking_wave", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	result, resp := c.Service.StartWave(ctx.Request.Context(), id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "StartPickingWave", "start_picking_wave", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, result)
+	tools.ResponseOK(ctx, "StartPickingWave", "Ola de picking iniciada", "start_picking_wave", result, false, "")
+}
+
+// RecordPick handles POST /api/picking-waves/:id/picks
+func (c *PickingWavesController) RecordPick(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RecordWavePick", "record_wave_pick", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.RecordWavePickRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "RecordWavePick", "Datos de solicitud inválidos", "record_wave_pick")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "RecordWavePick", "record_wave_pick", errs)
+		return
+	}
+
+	line, resp := c.Service.RecordPick(id, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "RecordWavePick", "record_wave_pick", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "RecordWavePick", "Pick registrado", "record_wave_pick", line, false, "")
+}
+
+// CompleteWave handles PATCH /api/picking-waves/:id/complete
+func (c *PickingWavesController) CompleteWave(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CompletePickingWave", "complete_picking_wave", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	result, resp := c.Service.CompleteWave(ctx.Request.Context(), id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID))
+	if resp != nil {
+		writeErrorResponse(ctx, "CompletePickingWave", "complete_picking_wave", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionExecute, id, result)
+	tools.ResponseOK(ctx, "CompletePickingWave", "Ola de picking procesada", "complete_picking_wave", result, false, "")
+}
+
+// CancelWave handles PATCH /api/picking-waves/:id/cancel
+func (c *PickingWavesController) CancelWave(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CancelPickingWave", "cancel_picking_wave", "ID de ola de picking inválido")
+	if !ok {
+		return
+	}
+
+	wave, resp := c.Service.CancelWave(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "CancelPickingWave", "cancel_picking_wave", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, wave)
+	tools.ResponseOK(ctx, "CancelPickingWave", "Ola de picking cancelada", "cancel_picking_wave", wave, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *PickingWavesController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
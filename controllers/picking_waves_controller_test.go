@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPickingWavesCtrlRepo struct {
+	wave      *database.PickingWave
+	createReq *requests.CreatePickingWaveRequest
+	pickReq   *requests.RecordWavePickRequest
+}
+
+func (m *mockPickingWavesCtrlRepo) CreateWave(tenantID, createdBy string, req *requests.CreatePickingWaveRequest) (*database.PickingWave, *responses.InternalResponse) {
+	m.createReq = req
+	return m.wave, nil
+}
+func (m *mockPickingWavesCtrlRepo) GetWaveByID(id, tenantID string) (*database.PickingWave, *responses.InternalResponse) {
+	if m.wave == nil || m.wave.ID != id {
+		return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.wave, nil
+}
+func (m *mockPickingWavesCtrlRepo) ListWaves(tenantID string, status *string, limit, offset int) ([]database.PickingWave, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockPickingWavesCtrlRepo) GetWaveMembers(waveID string) ([]database.PickingWaveMember, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockPickingWavesCtrlRepo) TransitionWave(id, tenantID, next string) (*database.PickingWave, *responses.InternalResponse) {
+	m.wave.Status = next
+	return m.wave, nil
+}
+func (m *mockPickingWavesCtrlRepo) RecordPick(waveID, tenantID string, req *requests.RecordWavePickRequest) *responses.InternalResponse {
+	m.pickReq = req
+	return &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+}
+
+func (m *mockPickingWavesCtrlRepo) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func newPickingWavesTestRouter(repo *mockPickingWavesCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := services.NewPickingWavesService(repo, nil)
+	ctrl := NewPickingWavesController(svc, ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	pw := r.Group("/api/picking-waves")
+	pw.Use(injectUser)
+	pw.POST("/", ctrl.CreateWave)
+	pw.GET("/:id/pick-list", ctrl.GetPickList)
+	pw.POST("/:id/picks", ctrl.RecordPick)
+	pw.PATCH("/:id/cancel", ctrl.CancelWave)
+	return r
+}
+
+func samplePickingWaveRepo(status string) *mockPickingWavesCtrlRepo {
+	return &mockPickingWavesCtrlRepo{
+		wave: &database.PickingWave{ID: "wave-1", WaveNumber: "WV-2026-0001", Status: status, GroupBy: "manual"},
+	}
+}
+
+func TestPickingWavesController_CreateWave_Returns201(t *testing.T) {
+	repo := samplePickingWaveRepo("open")
+	r := newPickingWavesTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/picking-waves/", map[string]interface{}{
+		"group_by":  "priority",
+		"priority":  "high",
+		"max_tasks": 10,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, "priority", repo.createReq.GroupBy)
+}
+
+func TestPickingWavesController_CreateWave_Returns400_InvalidGroupBy(t *testing.T) {
+	repo := samplePickingWaveRepo("open")
+	r := newPickingWavesTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/picking-waves/", map[string]interface{}{
+		"group_by": "route",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestPickingWavesController_CreateWave_Returns400_BadExpectedDate(t *testing.T) {
+	repo := samplePickingWaveRepo("open")
+	r := newPickingWavesTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/picking-waves/", map[string]interface{}{
+		"group_by":      "expected_date",
+		"expected_date": "18/10/2026",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestPickingWavesController_GetPickList_Returns404(t *testing.T) {
+	r := newPickingWavesTestRouter(samplePickingWaveRepo("open"))
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/picking-waves/missing/pick-list", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPickingWavesController_RecordPick_Returns400_MissingQty(t *testing.T) {
+	repo := samplePickingWaveRepo("in_progress")
+	r := newPickingWavesTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/picking-waves/wave-1/picks", map[string]interface{}{
+		"location": "A-01",
+		"sku":      "SKU-1",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.pickReq)
+}
+
+func TestPickingWavesController_CancelWave_Returns200(t *testing.T) {
+	repo := samplePickingWaveRepo("open")
+	r := newPickingWavesTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/picking-waves/wave-1/cancel", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "cancelled", repo.wave.Status)
+}
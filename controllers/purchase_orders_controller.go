@@ -86,14 +86,14 @@ func (c *PurchaseOrdersController) List(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "ListPurchaseOrders", "Órdenes de compra recuperadas", "list_purchase_orders", pos, false, "")
 }
 
-// GetByID handles GET /api/purchase-orders/:id
+// GetByID handles GET /api/purchase-orders/:id?currency=USD
 func (c *PurchaseOrdersController) GetByID(ctx *gin.Context) {
 	id, ok := tools.ParseRequiredParam(ctx, "id", "GetPurchaseOrder", "get_purchase_order", "ID de orden de compra inválido")
 	if !ok {
 		return
 	}
 
-	po, resp := c.Service.GetByID(id, c.resolveTenantID(ctx))
+	po, resp := c.Service.GetByIDWithTotals(id, c.resolveTenantID(ctx), ctx.Query("currency"))
 	if resp != nil {
 		writeErrorResponse(ctx, "GetPurchaseOrder", "get_purchase_order", resp)
 		return
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// PutawayController handles HTTP for putaway suggestions on receiving tasks.
+type PutawayController struct {
+	Service  *services.PutawayService
+	TenantID string
+}
+
+func NewPutawayController(svc *services.PutawayService, tenantID string) *PutawayController {
+	return &PutawayController{Service: svc, TenantID: tenantID}
+}
+
+// SuggestReceivingTask handles GET /api/putaway/receiving-tasks/:id?limit=&zones=A,B&location_types=PALLET
+func (c *PutawayController) SuggestReceivingTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "SuggestReceivingTask", "suggest_receiving_task_putaway", "ID de tarea de recepción inválido")
+	if !ok {
+		return
+	}
+	req := requests.PutawaySuggestRequest{
+		Zones:         splitQueryList(ctx.Query("zones")),
+		LocationTypes: splitQueryList(ctx.Query("location_types")),
+	}
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			tools.ResponseBadRequest(ctx, "SuggestReceivingTask", "El parámetro limit debe ser un número entero", "suggest_receiving_task_putaway")
+			return
+		}
+		req.Limit = limit
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "SuggestReceivingTask", "suggest_receiving_task_putaway", errs)
+		return
+	}
+
+	result, resp := c.Service.SuggestForReceivingTask(c.resolveTenantID(ctx), id, &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "SuggestReceivingTask", "suggest_receiving_task_putaway", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "SuggestReceivingTask", "Sugerencias de ubicación obtenidas", "suggest_receiving_task_putaway", result, false, "")
+}
+
+// ApplyReceivingTask handles POST /api/putaway/receiving-tasks/:id/apply — fills each open
+// line's location with its best suggestion. The body is optional.
+func (c *PutawayController) ApplyReceivingTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ApplyReceivingTask", "apply_receiving_task_putaway", "ID de tarea de recepción inválido")
+	if !ok {
+		return
+	}
+	var req requests.PutawaySuggestRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, "ApplyReceivingTask", "Datos de solicitud inválidos", "apply_receiving_task_putaway")
+			return
+		}
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "ApplyReceivingTask", "apply_receiving_task_putaway", errs)
+		return
+	}
+
+	result, resp := c.Service.ApplyToReceivingTask(c.resolveTenantID(ctx), id, &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "ApplyReceivingTask", "apply_receiving_task_putaway", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ApplyReceivingTask", "Ubicaciones asignadas a la tarea de recepción", "apply_receiving_task_putaway", result, false, "")
+}
+
+// splitQueryList parses a comma-separated query value, dropping empty entries.
+func splitQueryList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// resolveTenantID — JWT-first, env fallback only.
+func (c *PutawayController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPutawayRepoCtrl struct {
+	saved json.RawMessage
+}
+
+func (m *mockPutawayRepoCtrl) GetReceivingTask(_, id string) (*database.ReceivingTask, *responses.InternalResponse) {
+	if id != "rt-1" {
+		return nil, &responses.InternalResponse{Message: "Tarea de recepción no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return &database.ReceivingTask{ID: "rt-1", Status: "open", Items: json.RawMessage(`[{"sku":"SKU-1","expected_qty":4,"location":""}]`)}, nil
+}
+
+func (m *mockPutawayRepoCtrl) GetArticles(_ string, _ []string) ([]database.Article, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockPutawayRepoCtrl) ListPutawayLocations(_ string) ([]database.Location, *responses.InternalResponse) {
+	zone := "A"
+	return []database.Location{{ID: "l1", LocationCode: "A-01", Zone: &zone, Type: "BIN", IsActive: true}}, nil
+}
+
+func (m *mockPutawayRepoCtrl) ListLocationTypes() ([]database.LocationType, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockPutawayRepoCtrl) GetLocationStock(_ string, _ []string) ([]ports.LocationStock, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockPutawayRepoCtrl) GetLocationCapacity(_ string, _ []string) (map[string]ports.LocationCapacity, *responses.InternalResponse) {
+	return map[string]ports.LocationCapacity{}, nil
+}
+
+func (m *mockPutawayRepoCtrl) UpdateReceivingTaskItems(_, _ string, items json.RawMessage) *responses.InternalResponse {
+	m.saved = items
+	return nil
+}
+
+func newPutawayRouter(repo *mockPutawayRepoCtrl) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewPutawayController(services.NewPutawayService(repo), "00000000-0000-0000-0000-000000000001")
+	r := gin.New()
+	r.GET("/putaway/receiving-tasks/:id", ctrl.SuggestReceivingTask)
+	r.POST("/putaway/receiving-tasks/:id/apply", ctrl.ApplyReceivingTask)
+	return r
+}
+
+func TestPutawayController_Suggest(t *testing.T) {
+	w := httptest.NewRecorder()
+	newPutawayRouter(&mockPutawayRepoCtrl{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/putaway/receiving-tasks/rt-1?zones=A,+B&limit=3", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data responses.PutawayResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Lines, 1)
+	assert.Equal(t, "A-01", body.Data.Lines[0].Suggestions[0].LocationCode)
+	assert.Contains(t, body.Data.Lines[0].Suggestions[0].Reasons, "Zona preferida")
+}
+
+func TestPutawayController_Suggest_BadRequests(t *testing.T) {
+	r := newPutawayRouter(&mockPutawayRepoCtrl{})
+	for _, path := range []string{"/putaway/receiving-tasks/rt-1?limit=x", "/putaway/receiving-tasks/rt-1?limit=50"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/putaway/receiving-tasks/nope", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPutawayController_Apply(t *testing.T) {
+	repo := &mockPutawayRepoCtrl{}
+	r := newPutawayRouter(repo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/putaway/receiving-tasks/rt-1/apply", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, string(repo.saved), `"location":"A-01"`)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/putaway/receiving-tasks/rt-1/apply", strings.NewReader(`{"limit":0,"overwrite":`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// QCHoldsController handles HTTP for quarantine / QC holds.
+type QCHoldsController struct {
+	Service      *services.QCHoldsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewQCHoldsController(svc *services.QCHoldsService, tenantID string, auditSvc *services.AuditService) *QCHoldsController {
+	return &QCHoldsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a QC hold when the audit service is configured.
+func (c *QCHoldsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceQCHold, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListHolds handles GET /api/qc-holds
+func (c *QCHoldsController) ListHolds(ctx *gin.Context) {
+	var status, scope, sku *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+	if v := ctx.Query("scope"); v != "" {
+		scope = &v
+	}
+	if v := ctx.Query("sku"); v != "" {
+		sku = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	holds, resp := c.Service.ListHolds(c.resolveTenantID(ctx), status, scope, sku, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListQCHolds", "list_qc_holds", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListQCHolds", "Retenciones de calidad recuperadas", "list_qc_holds", holds, false, "")
+}
+
+// GetHold handles GET /api/qc-holds/:id
+func (c *QCHoldsController) GetHold(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetQCHold", "get_qc_hold", "ID de retención inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetHold(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetQCHold", "get_qc_hold", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetQCHold", "Retención de calidad recuperada", "get_qc_hold", view, false, "")
+}
+
+// CreateHold handles POST /api/qc-holds
+func (c *QCHoldsController) CreateHold(ctx *gin.Context) {
+	var req requests.CreateQCHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateQCHold", "Datos de solicitud inválidos", "create_qc_hold")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateQCHold", "create_qc_hold", errs)
+		return
+	}
+
+	view, resp := c.Service.CreateHold(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateQCHold", "create_qc_hold", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, view.ID, view)
+	tools.ResponseCreated(ctx, "CreateQCHold", "Retención de calidad creada", "create_qc_hold", view, false, "")
+}
+
+// RecordInspection handles POST /api/qc-holds/:id/inspections
+func (c *QCHoldsController) RecordInspection(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RecordQCInspection", "record_qc_inspection", "ID de retención inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.RecordQCInspectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "RecordQCInspection", "Datos de solicitud inválidos", "record_qc_inspection")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "RecordQCInspection", "record_qc_inspection", errs)
+		return
+	}
+
+	view, resp := c.Service.RecordInspection(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "RecordQCInspection", "record_qc_inspection", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, req)
+	tools.ResponseCreated(ctx, "RecordQCInspection", "Inspección registrada", "record_qc_inspection", view, false, "")
+}
+
+// AddAttachment handles POST /api/qc-holds/:id/attachments (multipart/form-data).
+// Fields: file (PDF or image), inspection_id (optional).
+func (c *QCHoldsController) AddAttachment(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "AddQCAttachment", "add_qc_attachment", "ID de retención inválido")
+	if !ok {
+		return
+	}
+
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		tools.ResponseBadRequest(ctx, "AddQCAttachment", "Adjunte el archivo en el campo 'file'", "add_qc_attachment")
+		return
+	}
+	file, err := fh.Open()
+	if err != nil {
+		tools.ResponseBadRequest(ctx, "AddQCAttachment", "Error al leer el archivo", "add_qc_attachment")
+		return
+	}
+	defer file.Close()
+	// One extra byte is enough for the service to report the size error.
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxQCAttachmentBytes+1))
+	if err != nil {
+		tools.ResponseBadRequest(ctx, "AddQCAttachment", "Error al leer el archivo", "add_qc_attachment")
+		return
+	}
+	var inspectionID *string
+	if v := ctx.PostForm("inspection_id"); v != "" {
+		inspectionID = &v
+	}
+
+	attachment, resp := c.Service.AddAttachment(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), fh.Filename, inspectionID, data)
+	if resp != nil {
+		writeErrorResponse(ctx, "AddQCAttachment", "add_qc_attachment", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, attachment)
+	tools.ResponseCreated(ctx, "AddQCAttachment", "Adjunto guardado", "add_qc_attachment", attachment, false, "")
+}
+
+// DownloadAttachment handles GET /api/qc-holds/:id/attachments/:attachmentId
+func (c *QCHoldsController) DownloadAttachment(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DownloadQCAttachment", "download_qc_attachment", "ID de retención inválido")
+	if !ok {
+		return
+	}
+	attachmentID, ok := tools.ParseRequiredParam(ctx, "attachmentId", "DownloadQCAttachment", "download_qc_attachment", "ID de adjunto inválido")
+	if !ok {
+		return
+	}
+
+	data, attachment, resp := c.Service.AttachmentFile(id, c.resolveTenantID(ctx), attachmentID)
+	if resp != nil {
+		writeErrorResponse(ctx, "DownloadQCAttachment", "download_qc_attachment", resp)
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=\""+attachment.FileName+"\"")
+	ctx.Data(http.StatusOK, attachment.ContentType, data)
+}
+
+// ReleaseHold handles PATCH /api/qc-holds/:id/release
+func (c *QCHoldsController) ReleaseHold(ctx *gin.Context) {
+	c.decide(ctx, "ReleaseQCHold", "release_qc_hold", "Retención de calidad liberada", c.Service.ReleaseHold)
+}
+
+// RejectHold handles PATCH /api/qc-holds/:id/reject
+func (c *QCHoldsController) RejectHold(ctx *gin.Context) {
+	c.decide(ctx, "RejectQCHold", "reject_qc_hold", "Retención de calidad rechazada", c.Service.RejectHold)
+}
+
+// decide binds the optional decision notes and applies the release or reject decision.
+func (c *QCHoldsController) decide(ctx *gin.Context, handler, operation, okMessage string,
+	apply func(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse)) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", handler, operation, "ID de retención inválido")
+	if !ok {
+		return
+	}
+
+	// The body is optional (decision notes).
+	var req requests.QCDecisionRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, handler, "Datos de solicitud inválidos", operation)
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, handler, operation, errs)
+			return
+		}
+	}
+
+	view, resp := apply(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, handler, operation, resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, view)
+	tools.ResponseOK(ctx, handler, okMessage, operation, view, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *QCHoldsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
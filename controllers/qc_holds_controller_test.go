@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQCHoldsCtrlRepo struct {
+	createReq  *requests.CreateQCHoldRequest
+	decision   *requests.QCDecisionRequest
+	attachment *database.QCAttachment
+	rejectResp *responses.InternalResponse
+}
+
+func (m *mockQCHoldsCtrlRepo) ListHolds(tenantID string, status, scope, sku *string, limit, offset int) ([]database.QCHold, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockQCHoldsCtrlRepo) GetHold(id, tenantID string) (*responses.QCHoldView, *responses.InternalResponse) {
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id}}, nil
+}
+func (m *mockQCHoldsCtrlRepo) CreateHold(tenantID, userID string, req *requests.CreateQCHoldRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	m.createReq = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: "h1", HoldNumber: "QC-2026-0001", Status: database.QCHoldQuarantine}}, nil
+}
+func (m *mockQCHoldsCtrlRepo) RecordInspection(id, tenantID, userID string, req *requests.RecordQCInspectionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id}}, nil
+}
+func (m *mockQCHoldsCtrlRepo) AddAttachment(id, tenantID string, attachment *database.QCAttachment) (*database.QCAttachment, *responses.InternalResponse) {
+	attachment.ID = "a1"
+	attachment.HoldID = id
+	m.attachment = attachment
+	return attachment, nil
+}
+func (m *mockQCHoldsCtrlRepo) GetAttachment(id, tenantID, attachmentID string) (*database.QCAttachment, *responses.InternalResponse) {
+	if m.attachment == nil || m.attachment.ID != attachmentID {
+		return nil, &responses.InternalResponse{Message: "Adjunto no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.attachment, nil
+}
+func (m *mockQCHoldsCtrlRepo) ReleaseHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	m.decision = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id, Status: database.QCHoldReleased}}, nil
+}
+func (m *mockQCHoldsCtrlRepo) RejectHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	if m.rejectResp != nil {
+		return nil, m.rejectResp
+	}
+	m.decision = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id, Status: database.QCHoldRejected}}, nil
+}
+
+func newQCHoldsTestRouter(t *testing.T, repo *mockQCHoldsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := services.NewQCHoldsService(repo, tools.NewLocalDocumentStorage(t.TempDir()))
+	ctrl := NewQCHoldsController(svc, ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	rg := r.Group("/api/qc-holds")
+	rg.Use(injectUser)
+	rg.POST("", ctrl.CreateHold)
+	rg.POST("/:id/attachments", ctrl.AddAttachment)
+	rg.GET("/:id/attachments/:attachmentId", ctrl.DownloadAttachment)
+	rg.PATCH("/:id/release", ctrl.ReleaseHold)
+	rg.PATCH("/:id/reject", ctrl.RejectHold)
+	return r
+}
+
+func TestQCHoldsController_Create_Returns201(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{}
+	r := newQCHoldsTestRouter(t, repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/qc-holds", map[string]interface{}{
+		"scope": "lot", "sku": "SKU-1", "lot_number": "L1", "reason": "Temperatura fuera de rango",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Equal(t, "L1", *repo.createReq.LotNumber)
+}
+
+func TestQCHoldsController_Create_Returns400_InvalidScope(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{}
+	r := newQCHoldsTestRouter(t, repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/qc-holds", map[string]interface{}{
+		"scope": "pallet", "sku": "SKU-1", "reason": "x",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestQCHoldsController_Release_WithoutBody(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{}
+	r := newQCHoldsTestRouter(t, repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/qc-holds/h1/release", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.decision)
+	assert.Nil(t, repo.decision.Notes)
+}
+
+func TestQCHoldsController_Reject_Conflict(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{rejectResp: &responses.InternalResponse{Message: "ya fue resuelta", Handled: true, StatusCode: responses.StatusConflict}}
+	r := newQCHoldsTestRouter(t, repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/qc-holds/h1/reject", map[string]interface{}{"notes": "pH fuera de especificación"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// qcAttachmentRequest builds a multipart POST to /api/qc-holds/h1/attachments with one file.
+func qcAttachmentRequest(t *testing.T, fileName string, data []byte) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = fw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/qc-holds/h1/attachments", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestQCHoldsController_Attachment_UploadAndDownload(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{}
+	r := newQCHoldsTestRouter(t, repo)
+	pdf := []byte("%PDF-1.4\n% certificate\n")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, qcAttachmentRequest(t, "coa.pdf", pdf))
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.attachment)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/qc-holds/h1/attachments/a1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "coa.pdf")
+	assert.Equal(t, pdf, w.Body.Bytes())
+}
+
+func TestQCHoldsController_Attachment_MissingFile(t *testing.T) {
+	repo := &mockQCHoldsCtrlRepo{}
+	r := newQCHoldsTestRouter(t, repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/qc-holds/h1/attachments", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.attachment)
+}
@@ -186,7 +186,7 @@ func (c *ReceivingTasksController) CompleteFullTask(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.CompleteFullTask(id, location, userId)
+	response := c.Service.CompleteFullTask(id, location, userId, nil)
 	if response != nil {
 		writeErrorResponse(ctx, "CompleteFullTask", "complete_full_task", response)
 		return
@@ -220,7 +220,7 @@ func (c *ReceivingTasksController) CompleteReceivingLine(ctx *gin.Context) {
 		return
 	}
 
-	response := c.Service.CompleteReceivingLine(id, location, userId, item)
+	response := c.Service.CompleteReceivingLine(id, location, userId, item, nil)
 	if response != nil {
 		writeErrorResponse(ctx, "CompleteReceivingLine", "complete_receiving_line", response)
 		return
@@ -229,6 +229,79 @@ func (c *ReceivingTasksController) CompleteReceivingLine(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "CompleteReceivingLine", "Línea de recepción marcada como completa con éxito", "complete_receiving_line", nil, false, "")
 }
 
+// OverrideCompleteFullTask handles PATCH /receiving-tasks/complete-full-task/:id/:location/override.
+// Supervisor variant of CompleteFullTask: counts above the over-receipt allowance are accepted
+// and the override reason is written to the audit log.
+func (c *ReceivingTasksController) OverrideCompleteFullTask(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "OverrideCompleteFullTask", "override_complete_full_task", "ID de tarea inválido")
+	if !ok {
+		return
+	}
+
+	location := ctx.Param("location")
+	token := ctx.Request.Header.Get("Authorization")
+	userId, userIdErr := tools.GetUserId(c.JWTSecret, token)
+	if userIdErr != nil {
+		tools.ResponseUnauthorized(ctx, "GetUserId", "Token inválido", "invalid_token")
+		return
+	}
+
+	var override requests.OverReceiptOverride
+	if err := ctx.ShouldBindJSON(&override); err != nil {
+		tools.ResponseBadRequest(ctx, "OverrideCompleteFullTask", "Formato de solicitud inválido", "override_complete_full_task")
+		return
+	}
+	if errs := tools.ValidateStruct(&override); errs != nil {
+		tools.ResponseValidationError(ctx, "OverrideCompleteFullTask", "override_complete_full_task", errs)
+		return
+	}
+
+	response := c.Service.CompleteFullTask(id, location, userId, &override)
+	if response != nil {
+		writeErrorResponse(ctx, "OverrideCompleteFullTask", "override_complete_full_task", response)
+		return
+	}
+
+	tools.ResponseOK(ctx, "OverrideCompleteFullTask", "Tarea de recepción marcada como completa con autorización de supervisor", "override_complete_full_task", nil, false, "")
+}
+
+// OverrideCompleteReceivingLine handles PATCH /receiving-tasks/complete-receiving-line/:id/:location/override.
+// Supervisor variant of CompleteReceivingLine (see OverrideCompleteFullTask).
+func (c *ReceivingTasksController) OverrideCompleteReceivingLine(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "OverrideCompleteReceivingLine", "override_complete_receiving_line", "ID de tarea inválido")
+	if !ok {
+		return
+	}
+
+	location := ctx.Param("location")
+
+	token := ctx.Request.Header.Get("Authorization")
+	userId, userIdErr := tools.GetUserId(c.JWTSecret, token)
+	if userIdErr != nil {
+		tools.ResponseUnauthorized(ctx, "GetUserId", "Token inválido", "invalid_token")
+		return
+	}
+
+	var body requests.CompleteReceivingLineOverrideRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		tools.ResponseBadRequest(ctx, "OverrideCompleteReceivingLine", "Formato de solicitud inválido", "override_complete_receiving_line")
+		return
+	}
+	if errs := tools.ValidateStruct(&body); errs != nil {
+		tools.ResponseValidationError(ctx, "OverrideCompleteReceivingLine", "override_complete_receiving_line", errs)
+		return
+	}
+
+	override := &requests.OverReceiptOverride{Reason: body.Reason}
+	response := c.Service.CompleteReceivingLine(id, location, userId, body.Item, override)
+	if response != nil {
+		writeErrorResponse(ctx, "OverrideCompleteReceivingLine", "override_complete_receiving_line", response)
+		return
+	}
+
+	tools.ResponseOK(ctx, "OverrideCompleteReceivingLine", "Línea de recepción completada con autorización de supervisor", "override_complete_receiving_line", nil, false, "")
+}
+
 // LinkSupplier handles PATCH /receiving-tasks/:id/supplier (S2 R2 E1.7).
 func (c *ReceivingTasksController) LinkSupplier(ctx *gin.Context) {
 	id, ok := tools.ParseRequiredParam(ctx, "id", "LinkSupplier", "link_supplier", "ID de tarea inválido")
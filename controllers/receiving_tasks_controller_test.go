@@ -29,6 +29,8 @@ type mockReceivingTasksRepoCtrl struct {
 	completeErr   *responses.InternalResponse
 	completeLineErr *responses.InternalResponse
 	templateErr   error
+	override      *requests.OverReceiptOverride
+	lineItem      *requests.ReceivingTaskItemRequest
 }
 
 func (m *mockReceivingTasksRepoCtrl) GetAllReceivingTasks() ([]responses.ReceivingTasksView, *responses.InternalResponse) {
@@ -64,11 +66,14 @@ func (m *mockReceivingTasksRepoCtrl) ExportReceivingTaskToExcel(_ string) ([]byt
 	return m.exportData, m.exportErr
 }
 
-func (m *mockReceivingTasksRepoCtrl) CompleteFullTask(id string, location, userId string) *responses.InternalResponse {
+func (m *mockReceivingTasksRepoCtrl) CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse {
+	m.override = override
 	return m.completeErr
 }
 
-func (m *mockReceivingTasksRepoCtrl) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+func (m *mockReceivingTasksRepoCtrl) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse {
+	m.override = override
+	m.lineItem = &item
 	return m.completeLineErr
 }
 
@@ -300,3 +305,64 @@ func TestReceivingTasksController_CompleteFullTask_ServiceError(t *testing.T) {
 	})
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestReceivingTasksController_CompleteFullTask_OverReceiptNeedsOverride(t *testing.T) {
+	repo := &mockReceivingTasksRepoCtrl{
+		completeErr: &responses.InternalResponse{
+			Message:    "se requiere autorización de supervisor",
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		},
+	}
+	ctrl := newReceivingTasksController(repo)
+	w := performRequestWithHeader(ctrl.CompleteFullTask, "PATCH", "/receiving-tasks/complete-full-task/t-1/LOC-1", nil, gin.Params{{Key: "id", Value: "t-1"}, {Key: "location", Value: "LOC-1"}}, map[string]string{
+		"Authorization": makeTestToken(),
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Nil(t, repo.override, "regular completion never carries an override")
+}
+
+func TestReceivingTasksController_OverrideCompleteFullTask_PassesReason(t *testing.T) {
+	repo := &mockReceivingTasksRepoCtrl{}
+	ctrl := newReceivingTasksController(repo)
+	w := performRequestWithHeader(ctrl.OverrideCompleteFullTask, "PATCH", "/receiving-tasks/complete-full-task/t-1/LOC-1/override",
+		map[string]interface{}{"reason": "Proveedor envió caja completa"},
+		gin.Params{{Key: "id", Value: "t-1"}, {Key: "location", Value: "LOC-1"}}, map[string]string{
+			"Authorization": makeTestToken(),
+		})
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.override)
+	assert.Equal(t, "Proveedor envió caja completa", repo.override.Reason)
+}
+
+func TestReceivingTasksController_OverrideCompleteFullTask_MissingReason(t *testing.T) {
+	repo := &mockReceivingTasksRepoCtrl{}
+	ctrl := newReceivingTasksController(repo)
+	w := performRequestWithHeader(ctrl.OverrideCompleteFullTask, "PATCH", "/receiving-tasks/complete-full-task/t-1/LOC-1/override",
+		map[string]interface{}{},
+		gin.Params{{Key: "id", Value: "t-1"}, {Key: "location", Value: "LOC-1"}}, map[string]string{
+			"Authorization": makeTestToken(),
+		})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.override)
+}
+
+func TestReceivingTasksController_OverrideCompleteReceivingLine_PassesItemAndReason(t *testing.T) {
+	repo := &mockReceivingTasksRepoCtrl{}
+	ctrl := newReceivingTasksController(repo)
+	w := performRequestWithHeader(ctrl.OverrideCompleteReceivingLine, "PATCH", "/receiving-tasks/complete-receiving-line/t-1/LOC-1/override",
+		map[string]interface{}{
+			"reason": "Sobrante aceptado por compras",
+			"item":   map[string]interface{}{"sku": "SKU-1", "expected_qty": 10, "location": "LOC-1", "accepted_qty": 15},
+		},
+		gin.Params{{Key: "id", Value: "t-1"}, {Key: "location", Value: "LOC-1"}}, map[string]string{
+			"Authorization": makeTestToken(),
+		})
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.override)
+	assert.Equal(t, "Sobrante aceptado por compras", repo.override.Reason)
+	require.NotNil(t, repo.lineItem)
+	assert.Equal(t, "SKU-1", repo.lineItem.SKU)
+	require.NotNil(t, repo.lineItem.AcceptedQty)
+	assert.Equal(t, 15.0, *repo.lineItem.AcceptedQty)
+}
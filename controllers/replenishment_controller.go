@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ReplenishmentController handles HTTP for replenishment suggestions and draft PO generation.
+type ReplenishmentController struct {
+	Service  *services.ReplenishmentService
+	TenantID string
+}
+
+func NewReplenishmentController(svc *services.ReplenishmentService, tenantID string) *ReplenishmentController {
+	return &ReplenishmentController{Service: svc, TenantID: tenantID}
+}
+
+// Preview handles GET /api/replenishment/suggestions
+func (c *ReplenishmentController) Preview(ctx *gin.Context) {
+	preview, resp := c.Service.Preview(c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "PreviewReplenishment", "preview_replenishment", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "PreviewReplenishment", "Sugerencias de reabastecimiento calculadas", "preview_replenishment", preview, false, "")
+}
+
+// Generate handles POST /api/replenishment/generate — drafts one PO per supplier on demand,
+// regardless of auto_create_material_request (that setting only gates the cron).
+func (c *ReplenishmentController) Generate(ctx *gin.Context) {
+	userID := ctx.GetString(tools.ContextKeyUserID)
+
+	pos, resp := c.Service.GenerateDraftPOs(c.resolveTenantID(ctx), userID)
+	if resp != nil {
+		writeErrorResponse(ctx, "GenerateReplenishment", "generate_replenishment", resp)
+		return
+	}
+	tools.ResponseCreated(ctx, "GenerateReplenishment", "Órdenes de compra en borrador generadas", "generate_replenishment", pos, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *ReplenishmentController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
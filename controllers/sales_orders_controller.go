@@ -54,13 +54,13 @@ func (c *SalesOrdersController) List(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "ListSalesOrders", "Órdenes de venta recuperadas", "list_sales_orders", result, false, "")
 }
 
-// GetByID godoc: GET /api/sales-orders/:id
+// GetByID godoc: GET /api/sales-orders/:id?currency=USD
 func (c *SalesOrdersController) GetByID(ctx *gin.Context) {
 	id, ok := tools.ParseRequiredParam(ctx, "id", "GetSalesOrder", "get_sales_order", "ID de orden inválido")
 	if !ok {
 		return
 	}
-	so, resp := c.Service.GetByID(id, c.resolveTenantID(ctx))
+	so, resp := c.Service.GetByIDWithTotals(id, c.resolveTenantID(ctx), ctx.Query("currency"))
 	if resp != nil {
 		writeErrorResponse(ctx, "GetSalesOrder", "get_sales_order", resp)
 		return
@@ -112,6 +112,48 @@ func (c *SerialsController) DeleteSerial(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "DeleteSerial", "Serie eliminada con éxito", "delete_serial", nil, false, "")
 }
 
+// TransitionSerial handles POST /api/serials/:id/transition
+func (c *SerialsController) TransitionSerial(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "TransitionSerial", "transition_serial", "ID de serie inválido")
+	if !ok {
+		return
+	}
+
+	var request requests.SerialTransitionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		tools.ResponseBadRequest(ctx, "TransitionSerial", "Entrada inválida", "transition_serial")
+		return
+	}
+	if errs := tools.ValidateStruct(&request); errs != nil {
+		tools.ResponseValidationError(ctx, "TransitionSerial", "transition_serial", errs)
+		return
+	}
+
+	serial, resp := c.Service.TransitionSerial(c.resolveTenantID(ctx), id, ctx.GetString(tools.ContextKeyUserID), &request)
+	if resp != nil {
+		writeErrorResponse(ctx, "TransitionSerial", "transition_serial", resp)
+		return
+	}
+
+	tools.ResponseOK(ctx, "TransitionSerial", "Estado de la serie actualizado", "transition_serial", serial, false, "")
+}
+
+// GetChainOfCustody handles GET /api/serials/custody/:serialNumber
+func (c *SerialsController) GetChainOfCustody(ctx *gin.Context) {
+	serialNumber, ok := tools.ParseRequiredParam(ctx, "serialNumber", "GetSerialChainOfCustody", "get_serial_chain_of_custody", "Número de serie inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetChainOfCustody(c.resolveTenantID(ctx), serialNumber)
+	if resp != nil {
+		writeErrorResponse(ctx, "GetSerialChainOfCustody", "get_serial_chain_of_custody", resp)
+		return
+	}
+
+	tools.ResponseOK(ctx, "GetSerialChainOfCustody", "Cadena de custodia obtenida", "get_serial_chain_of_custody", view, false, "")
+}
+
 // resolveTenantID — S3.5 W5.5 (HR-S3.5 C1): JWT-first, env fallback only.
 // The TenantID field stays as a non-JWT fallback (cron/admin/test paths only).
 func (c *SerialsController) resolveTenantID(ctx *gin.Context) string {
@@ -124,14 +124,21 @@ func TestSerialsController_CreateSerial_InvalidJSON(t *testing.T) {
 
 func TestSerialsController_UpdateSerial_Success(t *testing.T) {
 	ctrl := newSerialsController(&mockSerialsRepoCtrl{})
-	body := map[string]interface{}{"status": "used"}
+	body := map[string]interface{}{"sku": "SKU-B"}
 	w := performRequest(ctrl.UpdateSerial, "PUT", "/serials/s1", body, gin.Params{{Key: "id", Value: "s1"}})
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestSerialsController_UpdateSerial_StatusRejected(t *testing.T) {
+	ctrl := newSerialsController(&mockSerialsRepoCtrl{})
+	body := map[string]interface{}{"status": "scrapped"}
+	w := performRequest(ctrl.UpdateSerial, "PUT", "/serials/s1", body, gin.Params{{Key: "id", Value: "s1"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestSerialsController_UpdateSerial_MissingParam(t *testing.T) {
 	ctrl := newSerialsController(&mockSerialsRepoCtrl{})
-	body := map[string]interface{}{"status": "used"}
+	body := map[string]interface{}{"sku": "SKU-B"}
 	w := performRequest(ctrl.UpdateSerial, "PUT", "/serials/", body, gin.Params{{Key: "id", Value: ""}})
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
@@ -148,6 +155,66 @@ func TestSerialsController_DeleteSerial_MissingParam(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+type mockSerialLifecycleRepoCtrl struct {
+	transitionErr *responses.InternalResponse
+	custody       *responses.SerialCustodyView
+}
+
+func (m *mockSerialLifecycleRepoCtrl) TransitionSerial(tenantID, id, userID string, req *requests.SerialTransitionRequest) (*database.Serial, *responses.InternalResponse) {
+	if m.transitionErr != nil {
+		return nil, m.transitionErr
+	}
+	return &database.Serial{ID: id, Status: req.Status}, nil
+}
+
+func (m *mockSerialLifecycleRepoCtrl) GetChainOfCustody(tenantID, serialNumber string) (*responses.SerialCustodyView, *responses.InternalResponse) {
+	if m.custody == nil {
+		return nil, &responses.InternalResponse{Message: "Serie no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.custody, nil
+}
+
+func newSerialsLifecycleController(lifecycle *mockSerialLifecycleRepoCtrl) *SerialsController {
+	svc := services.NewSerialsService(&mockSerialsRepoCtrl{})
+	svc.Lifecycle = lifecycle
+	return NewSerialsController(*svc, ctrlTenantA)
+}
+
+func TestSerialsController_TransitionSerial_Success(t *testing.T) {
+	ctrl := newSerialsLifecycleController(&mockSerialLifecycleRepoCtrl{})
+	body := requests.SerialTransitionRequest{Status: "scrapped"}
+	w := performRequest(ctrl.TransitionSerial, "POST", "/serials/s1/transition", body, gin.Params{{Key: "id", Value: "s1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSerialsController_TransitionSerial_InvalidStatus(t *testing.T) {
+	ctrl := newSerialsLifecycleController(&mockSerialLifecycleRepoCtrl{})
+	body := requests.SerialTransitionRequest{Status: "received"}
+	w := performRequest(ctrl.TransitionSerial, "POST", "/serials/s1/transition", body, gin.Params{{Key: "id", Value: "s1"}})
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
+
+func TestSerialsController_TransitionSerial_Conflict(t *testing.T) {
+	ctrl := newSerialsLifecycleController(&mockSerialLifecycleRepoCtrl{transitionErr: &responses.InternalResponse{
+		Message: "La serie SN-001 está en estado scrapped; no puede pasar a in_stock", Handled: true, StatusCode: responses.StatusConflict,
+	}})
+	body := requests.SerialTransitionRequest{Status: "in_stock"}
+	w := performRequest(ctrl.TransitionSerial, "POST", "/serials/s1/transition", body, gin.Params{{Key: "id", Value: "s1"}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSerialsController_GetChainOfCustody(t *testing.T) {
+	ctrl := newSerialsLifecycleController(&mockSerialLifecycleRepoCtrl{custody: &responses.SerialCustodyView{
+		Serial: database.Serial{ID: "s1", SerialNumber: "SN-001"},
+	}})
+	w := performRequest(ctrl.GetChainOfCustody, "GET", "/serials/custody/SN-001", nil, gin.Params{{Key: "serialNumber", Value: "SN-001"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	ctrl = newSerialsLifecycleController(&mockSerialLifecycleRepoCtrl{})
+	w = performRequest(ctrl.GetChainOfCustody, "GET", "/serials/custody/SN-404", nil, gin.Params{{Key: "serialNumber", Value: "SN-404"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // TestSerialsController_TenantIsolation_forwardsControllerTenant verifies the
 // constructor-injected TenantID is forwarded to every repo call.
 func TestSerialsController_TenantIsolation_forwardsControllerTenant(t *testing.T) {
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ShipmentsController handles HTTP for the packing stage (shipments, packages, packing list).
+type ShipmentsController struct {
+	Service      *services.ShipmentsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewShipmentsController(svc *services.ShipmentsService, tenantID string, auditSvc *services.AuditService) *ShipmentsController {
+	return &ShipmentsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a shipment when the audit service is configured.
+func (c *ShipmentsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceShipment, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListShipments handles GET /api/shipments
+func (c *ShipmentsController) ListShipments(ctx *gin.Context) {
+	var status *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	shipments, resp := c.Service.ListShipments(c.resolveTenantID(ctx), status, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListShipments", "list_shipments", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListShipments", "Envíos recuperados", "list_shipments", shipments, false, "")
+}
+
+// GetShipment handles GET /api/shipments/:id
+func (c *ShipmentsController) GetShipment(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetShipment", "get_shipment", "ID de envío inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetShipment(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetShipment", "get_shipment", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetShipment", "Envío recuperado", "get_shipment", view, false, "")
+}
+
+// GetPackingList handles GET /api/shipments/:id/packing-list (?format=pdf for the printable list)
+func (c *ShipmentsController) GetPackingList(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetPackingList", "get_packing_list", "ID de envío inválido")
+	if !ok {
+		return
+	}
+
+	if ctx.Query("format") == "pdf" {
+		data, filename, resp := c.Service.GetPackingListPDF(id, c.resolveTenantID(ctx))
+		if resp != nil {
+			writeErrorResponse(ctx, "GetPackingList", "get_packing_list", resp)
+			return
+		}
+		ctx.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+		ctx.Data(http.StatusOK, "application/pdf", data)
+		return
+	}
+
+	view, resp := c.Service.GetShipment(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetPackingList", "get_packing_list", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetPackingList", "Lista de empaque recuperada", "get_packing_list", view, false, "")
+}
+
+// AddPackage handles POST /api/shipments/:id/packages
+func (c *ShipmentsController) AddPackage(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "AddPackage", "add_package", "ID de envío inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.PackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "AddPackage", "Datos de solicitud inválidos", "add_package")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "AddPackage", "add_package", errs)
+		return
+	}
+
+	pkg, resp := c.Service.AddPackage(id, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "AddPackage", "add_package", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, id, pkg)
+	tools.ResponseCreated(ctx, "AddPackage", "Bulto creado exitosamente", "add_package", pkg, false, "")
+}
+
+// UpdatePackage handles PATCH /api/shipments/:id/packages/:packageId
+func (c *ShipmentsController) UpdatePackage(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "UpdatePackage", "update_package", "ID de envío inválido")
+	if !ok {
+		return
+	}
+	packageID, ok := tools.ParseRequiredParam(ctx, "packageId", "UpdatePackage", "update_package", "ID de bulto inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.PackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "UpdatePackage", "Datos de solicitud inválidos", "update_package")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "UpdatePackage", "update_package", errs)
+		return
+	}
+
+	pkg, resp := c.Service.UpdatePackage(id, packageID, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "UpdatePackage", "update_package", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, pkg)
+	tools.ResponseOK(ctx, "UpdatePackage", "Bulto actualizado", "update_package", pkg, false, "")
+}
+
+// DeletePackage handles DELETE /api/shipments/:id/packages/:packageId
+func (c *ShipmentsController) DeletePackage(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DeletePackage", "delete_package", "ID de envío inválido")
+	if !ok {
+		return
+	}
+	packageID, ok := tools.ParseRequiredParam(ctx, "packageId", "DeletePackage", "delete_package", "ID de bulto inválido")
+	if !ok {
+		return
+	}
+
+	if resp := c.Service.DeletePackage(id, packageID, c.resolveTenantID(ctx)); resp != nil {
+		writeErrorResponse(ctx, "DeletePackage", "delete_package", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionDelete, id, gin.H{"package_id": packageID})
+	tools.ResponseOK(ctx, "DeletePackage", "Bulto eliminado", "delete_package", nil, false, "")
+}
+
+// AddPackageContent handles POST /api/shipments/:id/packages/:packageId/contents
+func (c *ShipmentsController) AddPackageContent(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "AddPackageContent", "add_package_content", "ID de envío inválido")
+	if !ok {
+		return
+	}
+	packageID, ok := tools.ParseRequiredParam(ctx, "packageId", "AddPackageContent", "add_package_content", "ID de bulto inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.AddPackageContentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "AddPackageContent", "Datos de solicitud inválidos", "add_package_content")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "AddPackageContent", "add_package_content", errs)
+		return
+	}
+
+	content, resp := c.Service.AddPackageContent(id, packageID, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "AddPackageContent", "add_package_content", resp)
+		return
+	}
+	tools.ResponseCreated(ctx, "AddPackageContent", "Contenido empacado", "add_package_content", content, false, "")
+}
+
+// RemovePackageContent handles DELETE /api/shipments/:id/packages/:packageId/contents/:contentId
+func (c *ShipmentsController) RemovePackageContent(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "RemovePackageContent", "remove_package_content", "ID de envío inválido")
+	if !ok {
+		return
+	}
+	packageID, ok := tools.ParseRequiredParam(ctx, "packageId", "RemovePackageContent", "remove_package_content", "ID de bulto inválido")
+	if !ok {
+		return
+	}
+	contentID, ok := tools.ParseRequiredParam(ctx, "contentId", "RemovePackageContent", "remove_package_content", "ID de contenido inválido")
+	if !ok {
+		return
+	}
+
+	if resp := c.Service.RemovePackageContent(id, packageID, contentID, c.resolveTenantID(ctx)); resp != nil {
+		writeErrorResponse(ctx, "RemovePackageContent", "remove_package_content", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "RemovePackageContent", "Contenido quitado del bulto", "remove_package_content", nil, false, "")
+}
+
+// CloseShipment handles PATCH /api/shipments/:id/close
+func (c *ShipmentsController) CloseShipment(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CloseShipment", "close_shipment", "ID de envío inválido")
+	if !ok {
+		return
+	}
+
+	// The body is optional (notes only).
+	var req requests.CloseShipmentRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, "CloseShipment", "Datos de solicitud inválidos", "close_shipment")
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, "CloseShipment", "close_shipment", errs)
+			return
+		}
+	}
+
+	shipment, resp := c.Service.CloseShipment(id, c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CloseShipment", "close_shipment", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionExecute, id, shipment)
+	tools.ResponseOK(ctx, "CloseShipment", "Envío empacado; nota de entrega generada", "close_shipment", shipment, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *ShipmentsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockShipmentsCtrlRepo struct {
+	view       *responses.ShipmentView
+	packageReq *requests.PackageRequest
+	contentReq *requests.AddPackageContentRequest
+	closeResp  *responses.InternalResponse
+}
+
+func (m *mockShipmentsCtrlRepo) ListShipments(tenantID string, status *string, limit, offset int) ([]database.Shipment, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockShipmentsCtrlRepo) GetShipment(id, tenantID string) (*responses.ShipmentView, *responses.InternalResponse) {
+	if m.view == nil || m.view.ID != id {
+		return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.view, nil
+}
+func (m *mockShipmentsCtrlRepo) AddPackage(shipmentID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	m.packageReq = req
+	return &database.Package{ID: "p1", ShipmentID: shipmentID, SSCC: "000000000000000017", PackageType: "carton", Sequence: 1}, nil
+}
+func (m *mockShipmentsCtrlRepo) UpdatePackage(shipmentID, packageID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	m.packageReq = req
+	return &database.Package{ID: packageID}, nil
+}
+func (m *mockShipmentsCtrlRepo) DeletePackage(shipmentID, packageID, tenantID string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockShipmentsCtrlRepo) AddPackageContent(shipmentID, packageID, tenantID string, req *requests.AddPackageContentRequest) (*database.PackageContent, *responses.InternalResponse) {
+	m.contentReq = req
+	return &database.PackageContent{ID: "c1", PackageID: packageID, ShipmentLineID: req.ShipmentLineID, Qty: *req.Qty}, nil
+}
+func (m *mockShipmentsCtrlRepo) RemovePackageContent(shipmentID, packageID, contentID, tenantID string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockShipmentsCtrlRepo) CloseShipment(id, tenantID, userID string, req *requests.CloseShipmentRequest) (*database.Shipment, *responses.InternalResponse) {
+	if m.closeResp != nil {
+		return nil, m.closeResp
+	}
+	return &database.Shipment{ID: id, Status: "packed"}, nil
+}
+
+func newShipmentsTestRouter(repo *mockShipmentsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := services.NewShipmentsService(repo, nil)
+	ctrl := NewShipmentsController(svc, ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	sh := r.Group("/api/shipments")
+	sh.Use(injectUser)
+	sh.GET("/:id/packing-list", ctrl.GetPackingList)
+	sh.POST("/:id/packages", ctrl.AddPackage)
+	sh.POST("/:id/packages/:packageId/contents", ctrl.AddPackageContent)
+	sh.PATCH("/:id/close", ctrl.CloseShipment)
+	return r
+}
+
+func sampleShipmentsRepo() *mockShipmentsCtrlRepo {
+	return &mockShipmentsCtrlRepo{
+		view: &responses.ShipmentView{
+			Shipment: database.Shipment{ID: "sh-1", ShipmentNumber: "SH-2026-0001", Status: "packing"},
+			Packages: []responses.PackageView{},
+		},
+	}
+}
+
+func TestShipmentsController_AddPackage_Returns201(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/shipments/sh-1/packages", map[string]interface{}{
+		"package_type": "pallet",
+		"weight_kg":    250.5,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.packageReq)
+	assert.Equal(t, "pallet", repo.packageReq.PackageType)
+}
+
+func TestShipmentsController_AddPackage_Returns400_InvalidType(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/shipments/sh-1/packages", map[string]interface{}{
+		"package_type": "crate",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.packageReq)
+}
+
+func TestShipmentsController_AddPackage_Returns400_NegativeWeight(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/shipments/sh-1/packages", map[string]interface{}{
+		"weight_kg": -1,
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.packageReq)
+}
+
+func TestShipmentsController_AddPackageContent_Returns400_MissingQty(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/shipments/sh-1/packages/p1/contents", map[string]interface{}{
+		"shipment_line_id": "l1",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.contentReq)
+}
+
+func TestShipmentsController_AddPackageContent_Returns201(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/shipments/sh-1/packages/p1/contents", map[string]interface{}{
+		"shipment_line_id": "l1",
+		"qty":              2,
+		"serial_numbers":   []string{"S1", "S2"},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.contentReq)
+	assert.Equal(t, []string{"S1", "S2"}, repo.contentReq.SerialNumbers)
+}
+
+func TestShipmentsController_GetPackingList_PDF(t *testing.T) {
+	r := newShipmentsTestRouter(sampleShipmentsRepo())
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/shipments/sh-1/packing-list?format=pdf", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "packing-list-SH-2026-0001.pdf")
+}
+
+func TestShipmentsController_CloseShipment_Returns409(t *testing.T) {
+	repo := sampleShipmentsRepo()
+	repo.closeResp = &responses.InternalResponse{Message: "Faltan por empacar", Handled: true, StatusCode: responses.StatusConflict}
+	r := newShipmentsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/shipments/sh-1/close", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -47,6 +47,7 @@ func (m *mockStockSettingsRepo) Upsert(_ string, data *requests.UpdateStockSetti
 		PickBatchBasedOn:      data.PickBatchBasedOn,
 		ExpiryAlertDays:       data.ExpiryAlertDays,
 		PartialDeliveryPolicy: data.PartialDeliveryPolicy,
+		BaseCurrency:          data.BaseCurrency,
 	}
 	return s, nil
 }
@@ -168,3 +169,38 @@ func TestStockSettingsController_GetOrCreate_Idempotent(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	}
 }
+
+func TestStockSettingsController_Update_BaseCurrency(t *testing.T) {
+	send := func(t *testing.T, repo *mockStockSettingsRepo, body map[string]interface{}) *httptest.ResponseRecorder {
+		r := newStockSettingsRouter(newStockSettingsController(repo))
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPatch, "/settings/stock", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"valuation_method":        "avco",
+			"pick_batch_based_on":     "fefo",
+			"partial_delivery_policy": "immediate",
+		}
+	}
+
+	t.Run("omitted keeps current", func(t *testing.T) {
+		repo := &mockStockSettingsRepo{settings: &database.StockSetting{BaseCurrency: "USD"}}
+		w := send(t, repo, base())
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "USD", resp["data"].(map[string]interface{})["base_currency"])
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		body := base()
+		body["base_currency"] = "XXY"
+		w := send(t, &mockStockSettingsRepo{}, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -153,6 +153,81 @@ func (c *StockTransfersController) ExecuteStockTransfer(ctx *gin.Context) {
 	tools.ResponseOK(ctx, "ExecuteStockTransfer", "Stock transfer executed", "execute_stock_transfer", transfer, false, "")
 }
 
+// ShipStockTransfer is the ship step of a two-step transfer: stock leaves the source and the
+// lines go in_transit until ReceiveStockTransfer confirms them.
+func (c *StockTransfersController) ShipStockTransfer(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ShipStockTransfer", "ship_stock_transfer", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+	token := ctx.Request.Header.Get("Authorization")
+	userID, _ := tools.GetUserId(c.JWTSecret, token)
+	if userID == "" {
+		tools.ResponseUnauthorized(ctx, "ShipStockTransfer", "Unauthorized", "ship_stock_transfer")
+		return
+	}
+	existing, _ := c.Service.GetStockTransferByID(id)
+	transfer, resp := c.Service.ShipTransfer(id, userID)
+	if resp != nil {
+		writeErrorResponse(ctx, "ShipStockTransfer", "ship_stock_transfer", resp)
+		return
+	}
+	if c.AuditService != nil && transfer != nil {
+		oldVal, _ := json.Marshal(existing)
+		newVal, _ := json.Marshal(transfer)
+		c.AuditService.Log(ctx.Request.Context(), c.auditUserID(ctx), tools.ActionExecute, tools.ResourceStockTransfer, id, oldVal, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+	}
+	tools.ResponseOK(ctx, "ShipStockTransfer", "Stock transfer shipped", "ship_stock_transfer", transfer, false, "")
+}
+
+// ReceiveStockTransfer confirms received quantities for an in-transit transfer.
+func (c *StockTransfersController) ReceiveStockTransfer(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ReceiveStockTransfer", "receive_stock_transfer", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+	token := ctx.Request.Header.Get("Authorization")
+	userID, _ := tools.GetUserId(c.JWTSecret, token)
+	if userID == "" {
+		tools.ResponseUnauthorized(ctx, "ReceiveStockTransfer", "Unauthorized", "receive_stock_transfer")
+		return
+	}
+	var body requests.ReceiveStockTransferRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		tools.ResponseBadRequest(ctx, "ReceiveStockTransfer", "Invalid request body", "receive_stock_transfer")
+		return
+	}
+	if errs := tools.ValidateStruct(&body); errs != nil {
+		tools.ResponseValidationError(ctx, "ReceiveStockTransfer", "receive_stock_transfer", errs)
+		return
+	}
+	existing, _ := c.Service.GetStockTransferByID(id)
+	result, resp := c.Service.ReceiveTransfer(id, userID, &body)
+	if resp != nil {
+		writeErrorResponse(ctx, "ReceiveStockTransfer", "receive_stock_transfer", resp)
+		return
+	}
+	if c.AuditService != nil && result != nil {
+		oldVal, _ := json.Marshal(existing)
+		newVal, _ := json.Marshal(result)
+		c.AuditService.Log(ctx.Request.Context(), c.auditUserID(ctx), tools.ActionExecute, tools.ResourceStockTransfer, id, oldVal, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+	}
+	tools.ResponseOK(ctx, "ReceiveStockTransfer", "Stock transfer received", "receive_stock_transfer", result, false, "")
+}
+
+func (c *StockTransfersController) GetStockTransferDiscrepancies(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetStockTransferDiscrepancies", "get_stock_transfer_discrepancies", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+	list, resp := c.Service.GetTransferDiscrepancies(id)
+	if resp != nil {
+		writeErrorResponse(ctx, "GetStockTransferDiscrepancies", "get_stock_transfer_discrepancies", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetStockTransferDiscrepancies", "Stock transfer discrepancies retrieved", "get_stock_transfer_discrepancies", list, false, "")
+}
+
 func (c *StockTransfersController) ListStockTransferLines(ctx *gin.Context) {
 	transferID, ok := tools.ParseRequiredParam(ctx, "id", "ListStockTransferLines", "list_stock_transfer_lines", "Invalid stock transfer ID")
 	if !ok {
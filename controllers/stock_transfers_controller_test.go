@@ -412,3 +412,52 @@ func TestStockTransfersController_UpdateStockTransferLine_InvalidJSON(t *testing
 	ctrl.UpdateStockTransferLine(c)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestStockTransfersController_ShipStockTransfer_Unauthorized(t *testing.T) {
+	ctrl := newStockTransfersController(&mockStockTransfersRepoCtrl{})
+	w := performRequest(ctrl.ShipStockTransfer, "POST", "/stock-transfers/st-1/ship", nil, gin.Params{{Key: "id", Value: "st-1"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestStockTransfersController_ShipStockTransfer_ServiceError(t *testing.T) {
+	ctrl := newStockTransfersController(&mockStockTransfersRepoCtrl{
+		byID: map[string]*database.StockTransfer{"st-1": {ID: "st-1", Status: "draft"}},
+	})
+	w := performRequestWithHeader(ctrl.ShipStockTransfer, "POST", "/stock-transfers/st-1/ship", nil, gin.Params{{Key: "id", Value: "st-1"}}, map[string]string{
+		"Authorization": makeTestToken(),
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestStockTransfersController_ReceiveStockTransfer_ValidationError(t *testing.T) {
+	ctrl := newStockTransfersController(&mockStockTransfersRepoCtrl{})
+	body := requests.ReceiveStockTransferRequest{Lines: []requests.ReceiveStockTransferLine{}}
+	w := performRequestWithHeader(ctrl.ReceiveStockTransfer, "POST", "/stock-transfers/st-1/receive", body, gin.Params{{Key: "id", Value: "st-1"}}, map[string]string{
+		"Authorization": makeTestToken(),
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body = requests.ReceiveStockTransferRequest{Lines: []requests.ReceiveStockTransferLine{{LineID: "l1", ReceivedQty: -1}}}
+	w = performRequestWithHeader(ctrl.ReceiveStockTransfer, "POST", "/stock-transfers/st-1/receive", body, gin.Params{{Key: "id", Value: "st-1"}}, map[string]string{
+		"Authorization": makeTestToken(),
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStockTransfersController_ReceiveStockTransfer_Unauthorized(t *testing.T) {
+	ctrl := newStockTransfersController(&mockStockTransfersRepoCtrl{})
+	body := requests.ReceiveStockTransferRequest{Lines: []requests.ReceiveStockTransferLine{{LineID: "l1", ReceivedQty: 1}}}
+	w := performRequest(ctrl.ReceiveStockTransfer, "POST", "/stock-transfers/st-1/receive", body, gin.Params{{Key: "id", Value: "st-1"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestStockTransfersController_GetStockTransferDiscrepancies(t *testing.T) {
+	ctrl := newStockTransfersController(&mockStockTransfersRepoCtrl{
+		byID: map[string]*database.StockTransfer{"st-1": {ID: "st-1", Status: "completed"}},
+	})
+	w := performRequest(ctrl.GetStockTransferDiscrepancies, "GET", "/stock-transfers/st-1/discrepancies", nil, gin.Params{{Key: "id", Value: "st-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(ctrl.GetStockTransferDiscrepancies, "GET", "/stock-transfers/99/discrepancies", nil, gin.Params{{Key: "id", Value: "99"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// VendorReturnsController handles HTTP for returns to vendor (RTV).
+type VendorReturnsController struct {
+	Service      *services.VendorReturnsService
+	TenantID     string
+	AuditService *services.AuditService
+}
+
+func NewVendorReturnsController(svc *services.VendorReturnsService, tenantID string, auditSvc *services.AuditService) *VendorReturnsController {
+	return &VendorReturnsController{Service: svc, TenantID: tenantID, AuditService: auditSvc}
+}
+
+// audit logs an action on a vendor return when the audit service is configured.
+func (c *VendorReturnsController) audit(ctx *gin.Context, action, id string, newValue interface{}) {
+	if c.AuditService == nil {
+		return
+	}
+	var userID *string
+	if v := ctx.GetString(tools.ContextKeyUserID); v != "" {
+		userID = &v
+	}
+	var newVal []byte
+	if newValue != nil {
+		newVal, _ = json.Marshal(newValue)
+	}
+	c.AuditService.Log(ctx.Request.Context(), userID, action, tools.ResourceVendorReturn, id, nil, newVal, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+}
+
+// ListReturns handles GET /api/vendor-returns
+func (c *VendorReturnsController) ListReturns(ctx *gin.Context) {
+	var status, purchaseOrderID, supplierID *string
+	if v := ctx.Query("status"); v != "" {
+		status = &v
+	}
+	if v := ctx.Query("purchase_order_id"); v != "" {
+		purchaseOrderID = &v
+	}
+	if v := ctx.Query("supplier_id"); v != "" {
+		supplierID = &v
+	}
+
+	limit := 50
+	offset := 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	returns, resp := c.Service.ListReturns(c.resolveTenantID(ctx), status, purchaseOrderID, supplierID, limit, offset)
+	if resp != nil {
+		writeErrorResponse(ctx, "ListVendorReturns", "list_vendor_returns", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "ListVendorReturns", "Devoluciones a proveedor recuperadas", "list_vendor_returns", returns, false, "")
+}
+
+// GetReturn handles GET /api/vendor-returns/:id
+func (c *VendorReturnsController) GetReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "GetVendorReturn", "get_vendor_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	view, resp := c.Service.GetReturn(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "GetVendorReturn", "get_vendor_return", resp)
+		return
+	}
+	tools.ResponseOK(ctx, "GetVendorReturn", "Devolución a proveedor recuperada", "get_vendor_return", view, false, "")
+}
+
+// DownloadPDF handles GET /api/vendor-returns/:id/pdf (the return document for the supplier)
+func (c *VendorReturnsController) DownloadPDF(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "DownloadVendorReturnPDF", "download_vendor_return_pdf", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	data, filename, resp := c.Service.GetReturnPDF(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "DownloadVendorReturnPDF", "download_vendor_return_pdf", resp)
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	ctx.Data(http.StatusOK, "application/pdf", data)
+}
+
+// CreateReturn handles POST /api/vendor-returns
+func (c *VendorReturnsController) CreateReturn(ctx *gin.Context) {
+	var req requests.CreateVendorReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreateVendorReturn", "Datos de solicitud inválidos", "create_vendor_return")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreateVendorReturn", "create_vendor_return", errs)
+		return
+	}
+
+	view, resp := c.Service.CreateReturn(c.resolveTenantID(ctx), ctx.GetString(tools.ContextKeyUserID), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreateVendorReturn", "create_vendor_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionCreate, view.ID, view)
+	tools.ResponseCreated(ctx, "CreateVendorReturn", "Devolución a proveedor creada", "create_vendor_return", view, false, "")
+}
+
+// ShipReturn handles PATCH /api/vendor-returns/:id/ship
+func (c *VendorReturnsController) ShipReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "ShipVendorReturn", "ship_vendor_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	// The body is optional (carrier, tracking number, shipped_at).
+	var req requests.ShipVendorReturnRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			tools.ResponseBadRequest(ctx, "ShipVendorReturn", "Datos de solicitud inválidos", "ship_vendor_return")
+			return
+		}
+		if errs := tools.ValidateStruct(&req); errs != nil {
+			tools.ResponseValidationError(ctx, "ShipVendorReturn", "ship_vendor_return", errs)
+			return
+		}
+	}
+
+	view, resp := c.Service.ShipReturn(id, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "ShipVendorReturn", "ship_vendor_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, req)
+	tools.ResponseOK(ctx, "ShipVendorReturn", "Devolución a proveedor despachada", "ship_vendor_return", view, false, "")
+}
+
+// CreditReturn handles PATCH /api/vendor-returns/:id/credit
+func (c *VendorReturnsController) CreditReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CreditVendorReturn", "credit_vendor_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	var req requests.CreditVendorReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tools.ResponseBadRequest(ctx, "CreditVendorReturn", "Datos de solicitud inválidos", "credit_vendor_return")
+		return
+	}
+	if errs := tools.ValidateStruct(&req); errs != nil {
+		tools.ResponseValidationError(ctx, "CreditVendorReturn", "credit_vendor_return", errs)
+		return
+	}
+
+	view, resp := c.Service.CreditReturn(id, c.resolveTenantID(ctx), &req)
+	if resp != nil {
+		writeErrorResponse(ctx, "CreditVendorReturn", "credit_vendor_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, req)
+	tools.ResponseOK(ctx, "CreditVendorReturn", "Crédito de la devolución a proveedor registrado", "credit_vendor_return", view, false, "")
+}
+
+// CancelReturn handles PATCH /api/vendor-returns/:id/cancel
+func (c *VendorReturnsController) CancelReturn(ctx *gin.Context) {
+	id, ok := tools.ParseRequiredParam(ctx, "id", "CancelVendorReturn", "cancel_vendor_return", "ID de devolución inválido")
+	if !ok {
+		return
+	}
+
+	ret, resp := c.Service.CancelReturn(id, c.resolveTenantID(ctx))
+	if resp != nil {
+		writeErrorResponse(ctx, "CancelVendorReturn", "cancel_vendor_return", resp)
+		return
+	}
+	c.audit(ctx, tools.ActionUpdate, id, ret)
+	tools.ResponseOK(ctx, "CancelVendorReturn", "Devolución a proveedor cancelada", "cancel_vendor_return", ret, false, "")
+}
+
+// resolveTenantID — JWT-first, env fallback only (same contract as PurchaseOrdersController).
+func (c *VendorReturnsController) resolveTenantID(ctx *gin.Context) string {
+	return tools.ResolveTenantID(ctx, c.TenantID)
+}
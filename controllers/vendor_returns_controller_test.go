@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockVendorReturnsCtrlRepo struct {
+	createReq *requests.CreateVendorReturnRequest
+	shipReq   *requests.ShipVendorReturnRequest
+	creditReq *requests.CreditVendorReturnRequest
+	shipResp  *responses.InternalResponse
+}
+
+func (m *mockVendorReturnsCtrlRepo) ListReturns(tenantID string, status, purchaseOrderID, supplierID *string, limit, offset int) ([]database.VendorReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockVendorReturnsCtrlRepo) GetReturn(id, tenantID string) (*responses.VendorReturnView, *responses.InternalResponse) {
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: id, ReturnNumber: "RTV-2026-0001"}, Lines: []database.VendorReturnLine{}}, nil
+}
+func (m *mockVendorReturnsCtrlRepo) CreateReturn(tenantID, userID string, req *requests.CreateVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	m.createReq = req
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: "v1", Status: database.VendorReturnDraft}}, nil
+}
+func (m *mockVendorReturnsCtrlRepo) ShipReturn(id, tenantID string, req *requests.ShipVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	if m.shipResp != nil {
+		return nil, m.shipResp
+	}
+	m.shipReq = req
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: id, Status: database.VendorReturnShipped}}, nil
+}
+func (m *mockVendorReturnsCtrlRepo) CreditReturn(id, tenantID string, req *requests.CreditVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	m.creditReq = req
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: id, Status: database.VendorReturnCredited}}, nil
+}
+func (m *mockVendorReturnsCtrlRepo) CancelReturn(id, tenantID string) (*database.VendorReturn, *responses.InternalResponse) {
+	return &database.VendorReturn{ID: id, Status: database.VendorReturnCancelled}, nil
+}
+
+func newVendorReturnsTestRouter(repo *mockVendorReturnsCtrlRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := NewVendorReturnsController(services.NewVendorReturnsService(repo), ctrlTenantID, nil)
+
+	injectUser := func(c *gin.Context) {
+		c.Set(tools.ContextKeyUserID, "test-user")
+		c.Next()
+	}
+
+	rg := r.Group("/api/vendor-returns")
+	rg.Use(injectUser)
+	rg.GET("/:id/pdf", ctrl.DownloadPDF)
+	rg.POST("", ctrl.CreateReturn)
+	rg.PATCH("/:id/ship", ctrl.ShipReturn)
+	rg.PATCH("/:id/credit", ctrl.CreditReturn)
+	return r
+}
+
+func TestVendorReturnsController_Create_Returns201WithoutLines(t *testing.T) {
+	repo := &mockVendorReturnsCtrlRepo{}
+	r := newVendorReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/vendor-returns", map[string]interface{}{"purchase_order_id": "po-1"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, repo.createReq)
+	assert.Empty(t, repo.createReq.Lines)
+}
+
+func TestVendorReturnsController_Create_Returns400_InvalidQty(t *testing.T) {
+	repo := &mockVendorReturnsCtrlRepo{}
+	r := newVendorReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPost, "/api/vendor-returns", map[string]interface{}{
+		"purchase_order_id": "po-1",
+		"lines":             []map[string]interface{}{{"article_sku": "SKU-1", "qty": 0}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.createReq)
+}
+
+func TestVendorReturnsController_Ship_BodyOptional(t *testing.T) {
+	repo := &mockVendorReturnsCtrlRepo{}
+	r := newVendorReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/vendor-returns/v1/ship", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.shipReq)
+	assert.Nil(t, repo.shipReq.TrackingNumber)
+}
+
+func TestVendorReturnsController_Ship_Returns409(t *testing.T) {
+	repo := &mockVendorReturnsCtrlRepo{shipResp: &responses.InternalResponse{Message: "no es borrador", Handled: true, StatusCode: responses.StatusConflict}}
+	r := newVendorReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/vendor-returns/v1/ship", map[string]interface{}{"tracking_number": "TRK-1"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestVendorReturnsController_Credit_Returns400_MissingAmount(t *testing.T) {
+	repo := &mockVendorReturnsCtrlRepo{}
+	r := newVendorReturnsTestRouter(repo)
+
+	w := doCycleCountRequest(r, http.MethodPatch, "/api/vendor-returns/v1/credit", map[string]interface{}{"credit_note_number": "NC-1"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, repo.creditReq)
+
+	w = doCycleCountRequest(r, http.MethodPatch, "/api/vendor-returns/v1/credit", map[string]interface{}{"credit_note_number": "NC-1", "credited_amount": 15.5})
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, repo.creditReq)
+	assert.InDelta(t, 15.5, *repo.creditReq.CreditedAmount, 1e-9)
+}
+
+func TestVendorReturnsController_DownloadPDF(t *testing.T) {
+	r := newVendorReturnsTestRouter(&mockVendorReturnsCtrlRepo{})
+
+	w := doCycleCountRequest(r, http.MethodGet, "/api/vendor-returns/v1/pdf", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "RTV-2026-0001.pdf")
+}
@@ -0,0 +1,8 @@
+-- Migration 000037 DOWN: drop cycle counting tables in reverse FK order.
+
+DELETE FROM public.adjustment_reason_codes
+ WHERE code IN ('cycle_count_gain', 'cycle_count_loss') AND is_system = true;
+
+DROP TABLE IF EXISTS count_task_lines;
+DROP TABLE IF EXISTS count_tasks;
+DROP TABLE IF EXISTS count_plans;
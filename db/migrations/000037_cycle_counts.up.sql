@@ -0,0 +1,90 @@
+-- Migration 000037: Cycle counting — count plans, count tasks and count lines.
+--
+-- Until now every physical count was done on paper and keyed in by hand as a
+-- count_reconcile adjustment. This migration adds:
+--   * count_plans       — what to count (zone / location / category / ABC class),
+--                         blind flag, recount thresholds and the reason code to post.
+--   * count_tasks       — one generated count run for a plan (CC-YYYY-NNNN).
+--   * count_task_lines  — one SKU+location per line with the expected qty snapshot,
+--                         the first count, the optional recount and the adjustment
+--                         posted on approval.
+--
+-- Variances are never written to inventory directly: on approval the service posts
+-- them through AdjustmentsRepository.CreateAdjustment with adjustment_type =
+-- 'count_reconcile', and stores the resulting adjustment id on the line so a
+-- partially failed approval can be retried without double-posting.
+
+CREATE TABLE count_plans (
+  id                     TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id              UUID NOT NULL,
+  name                   TEXT NOT NULL,
+  description            TEXT,
+  scope_type             TEXT NOT NULL CHECK (scope_type IN ('zone','location','category','abc_class')),
+  scope_values           TEXT[] NOT NULL DEFAULT '{}',
+  blind                  BOOLEAN NOT NULL DEFAULT true,
+  recount_threshold_pct  NUMERIC(7,3),
+  recount_threshold_qty  NUMERIC(12,3),
+  reason_code            TEXT,
+  is_active              BOOLEAN NOT NULL DEFAULT true,
+  created_by             TEXT REFERENCES users(id) ON DELETE SET NULL,
+  created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at             TIMESTAMPTZ
+);
+CREATE INDEX idx_count_plans_tenant_created ON count_plans(tenant_id, created_at DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE count_tasks (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id       UUID NOT NULL,
+  task_number     TEXT NOT NULL,                       -- e.g., "CC-2026-0001"
+  count_plan_id   TEXT REFERENCES count_plans(id) ON DELETE SET NULL,
+  status          TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open','in_progress','pending_approval','approved','cancelled')),
+  blind           BOOLEAN NOT NULL DEFAULT true,
+  -- thresholds and reason code are copied from the plan so later plan edits do not
+  -- change how a task already in the field is counted or posted.
+  recount_threshold_pct NUMERIC(7,3),
+  recount_threshold_qty NUMERIC(12,3),
+  reason_code     TEXT,
+  assigned_to     TEXT REFERENCES users(id) ON DELETE SET NULL,
+  notes           TEXT,
+  created_by      TEXT REFERENCES users(id) ON DELETE SET NULL,
+  started_at      TIMESTAMPTZ,
+  submitted_at    TIMESTAMPTZ,
+  approved_by     TEXT REFERENCES users(id) ON DELETE SET NULL,
+  approved_at     TIMESTAMPTZ,
+  cancelled_at    TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE(tenant_id, task_number)
+);
+CREATE INDEX idx_count_tasks_tenant_status ON count_tasks(tenant_id, status);
+CREATE INDEX idx_count_tasks_tenant_created ON count_tasks(tenant_id, created_at DESC);
+CREATE INDEX idx_count_tasks_plan ON count_tasks(count_plan_id);
+
+CREATE TABLE count_task_lines (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  count_task_id   TEXT NOT NULL REFERENCES count_tasks(id) ON DELETE CASCADE,
+  sku             TEXT NOT NULL,
+  location        TEXT NOT NULL,
+  expected_qty    NUMERIC(12,3) NOT NULL DEFAULT 0,    -- snapshot at generation time, hidden on blind tasks
+  counted_qty     NUMERIC(12,3),
+  recount_qty     NUMERIC(12,3),
+  status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','counted','recount_required','recounted')),
+  counted_by      TEXT REFERENCES users(id) ON DELETE SET NULL,
+  counted_at      TIMESTAMPTZ,
+  recounted_by    TEXT REFERENCES users(id) ON DELETE SET NULL,
+  recounted_at    TIMESTAMPTZ,
+  notes           TEXT,
+  adjustment_id   TEXT REFERENCES adjustments(id) ON DELETE SET NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE(count_task_id, sku, location)
+);
+CREATE INDEX idx_count_task_lines_task ON count_task_lines(count_task_id);
+CREATE INDEX idx_count_task_lines_sku_location ON count_task_lines(sku, location);
+
+-- System reason codes used when a count plan does not specify its own.
+INSERT INTO public.adjustment_reason_codes (id, code, name, direction, is_system, display_order, is_active) VALUES
+    (nanoid(), 'cycle_count_gain', 'Cycle count gain', 'inbound', true, 30, true),
+    (nanoid(), 'cycle_count_loss', 'Cycle count loss', 'outbound', true, 40, true)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,11 @@
+-- Migration 000038 DOWN: drop cost layers and restore the avco/fifo valuation methods.
+
+DROP TABLE IF EXISTS cost_layer_consumptions;
+DROP TABLE IF EXISTS cost_layers;
+
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS cogs;
+
+UPDATE stock_settings SET valuation_method = 'avco' WHERE valuation_method = 'lifo';
+ALTER TABLE stock_settings DROP CONSTRAINT IF EXISTS stock_settings_valuation_method_check;
+ALTER TABLE stock_settings
+  ADD CONSTRAINT stock_settings_valuation_method_check CHECK (valuation_method IN ('avco','fifo'));
@@ -0,0 +1,74 @@
+-- Migration 000038: Cost layers — FIFO / LIFO / weighted-average (AVCO) valuation.
+--
+-- Until now GetValuation recomputed an AVCO average over inventory_movements on every
+-- call and stock_settings.valuation_method was never read. This migration adds:
+--   * cost_layers              — one layer per inbound movement (receiving, positive
+--                                adjustment, initial stock) with its unit cost and the
+--                                quantity still on hand.
+--   * cost_layer_consumptions  — which layers each outbound movement consumed and at
+--                                what cost; a NULL layer_id records stock that left
+--                                without any layer behind it (costed at the article price).
+--   * inventory_movements.cogs — cost of goods of an outbound movement, the sum of its
+--                                consumptions.
+--   * 'lifo' as a valuation method.
+--
+-- Layers are per tenant + SKU (not per location): transfers between locations move
+-- stock but not its cost. Existing stock gets one opening layer per SKU, costed at the
+-- average inbound unit cost (the old AVCO figure) or the article price when no inbound
+-- movement carries a cost, so valuations do not jump when this migration runs.
+
+ALTER TABLE stock_settings DROP CONSTRAINT IF EXISTS stock_settings_valuation_method_check;
+ALTER TABLE stock_settings
+  ADD CONSTRAINT stock_settings_valuation_method_check CHECK (valuation_method IN ('avco','fifo','lifo'));
+
+ALTER TABLE inventory_movements ADD COLUMN cogs NUMERIC(14,4);
+
+CREATE TABLE cost_layers (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id       UUID NOT NULL,
+  sku             VARCHAR(50) NOT NULL,
+  movement_id     TEXT REFERENCES inventory_movements(id) ON DELETE SET NULL,
+  reference_type  VARCHAR(30),
+  reference_id    VARCHAR(80),
+  original_qty    NUMERIC(12,4) NOT NULL CHECK (original_qty > 0),
+  remaining_qty   NUMERIC(12,4) NOT NULL CHECK (remaining_qty >= 0),
+  unit_cost       NUMERIC(12,4) NOT NULL DEFAULT 0,
+  received_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- Open layers in consumption order (FIFO walks it forward, LIFO backward).
+CREATE INDEX idx_cost_layers_open ON cost_layers(tenant_id, sku, received_at, id) WHERE remaining_qty > 0;
+CREATE INDEX idx_cost_layers_movement ON cost_layers(movement_id) WHERE movement_id IS NOT NULL;
+
+CREATE TABLE cost_layer_consumptions (
+  id           TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id    UUID NOT NULL,
+  movement_id  TEXT NOT NULL REFERENCES inventory_movements(id) ON DELETE CASCADE,
+  layer_id     TEXT REFERENCES cost_layers(id) ON DELETE SET NULL,
+  sku          VARCHAR(50) NOT NULL,
+  quantity     NUMERIC(12,4) NOT NULL CHECK (quantity > 0),
+  unit_cost    NUMERIC(12,4) NOT NULL DEFAULT 0,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_cost_layer_consumptions_movement ON cost_layer_consumptions(movement_id);
+CREATE INDEX idx_cost_layer_consumptions_layer ON cost_layer_consumptions(layer_id) WHERE layer_id IS NOT NULL;
+
+-- Opening layers for stock on hand.
+INSERT INTO cost_layers (tenant_id, sku, reference_type, original_qty, remaining_qty, unit_cost)
+SELECT a.tenant_id,
+       stock.sku,
+       'opening_balance',
+       stock.qty,
+       stock.qty,
+       COALESCE(avco.unit_cost, a.unit_price, 0)
+  FROM (SELECT sku, SUM(quantity) AS qty FROM inventory GROUP BY sku HAVING SUM(quantity) > 0) stock
+  JOIN articles a ON a.sku = stock.sku
+  LEFT JOIN (
+        SELECT sku,
+               SUM(quantity * COALESCE(unit_cost, 0)) / NULLIF(SUM(quantity), 0) AS unit_cost
+          FROM inventory_movements
+         WHERE movement_type IN ('inbound','INBOUND','ADJUSTMENT','TRANSFER_IN')
+           AND quantity > 0
+           AND unit_cost IS NOT NULL
+         GROUP BY sku
+       ) avco ON avco.sku = stock.sku;
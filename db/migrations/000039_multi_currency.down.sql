@@ -0,0 +1,8 @@
+-- Migration 000039 DOWN: drop exchange rates and currency columns.
+
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE sales_order_items DROP COLUMN IF EXISTS currency;
+ALTER TABLE purchase_order_items DROP COLUMN IF EXISTS currency;
+ALTER TABLE articles DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE stock_settings DROP COLUMN IF EXISTS base_currency;
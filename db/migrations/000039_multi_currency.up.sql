@@ -0,0 +1,33 @@
+-- Migration 000039: Per-tenant base currency, currency codes on prices and exchange rates.
+--
+-- Everything used to be implicitly CRC (InventoryValuationResponse.Currency was hardcoded).
+-- Operations that buy in USD and sell in CRC need:
+--   * stock_settings.base_currency           — the tenant's reporting currency (ISO 4217).
+--   * articles.price_currency                — currency of articles.unit_price.
+--   * purchase_order_items.currency          — currency of unit_cost.
+--   * sales_order_items.currency             — currency of unit_price.
+--   * exchange_rates                         — local, dated rates per tenant (1 from = rate to).
+--
+-- A NULL currency on a price means "the tenant base currency", so existing rows keep their
+-- meaning without a backfill. Cost layers (000038) are always stored in the base currency:
+-- received PO costs are converted at the rate in effect on the receipt date.
+
+ALTER TABLE stock_settings ADD COLUMN base_currency CHAR(3) NOT NULL DEFAULT 'CRC';
+
+ALTER TABLE articles ADD COLUMN price_currency CHAR(3);
+ALTER TABLE purchase_order_items ADD COLUMN currency CHAR(3);
+ALTER TABLE sales_order_items ADD COLUMN currency CHAR(3);
+
+CREATE TABLE exchange_rates (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id       UUID NOT NULL,
+  from_currency   CHAR(3) NOT NULL,
+  to_currency     CHAR(3) NOT NULL,
+  rate            NUMERIC(18,8) NOT NULL CHECK (rate > 0),
+  effective_date  DATE NOT NULL,
+  created_by      TEXT REFERENCES users(id) ON DELETE SET NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (from_currency <> to_currency),
+  -- Also serves rate lookups: latest effective_date on or before a day for a pair.
+  UNIQUE (tenant_id, from_currency, to_currency, effective_date)
+);
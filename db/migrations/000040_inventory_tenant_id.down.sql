@@ -0,0 +1,7 @@
+-- 000040_inventory_tenant_id.down.sql
+-- Reverse inventory tenant isolation.
+
+DROP INDEX IF EXISTS idx_inventory_tenant_created_at;
+DROP INDEX IF EXISTS idx_inventory_tenant_sku_location;
+
+ALTER TABLE inventory DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,48 @@
+-- 000040_inventory_tenant_id.up.sql
+-- Multi-tenant isolation for the inventory table.
+--
+-- Problem: inventory carries no tenant_id, so every InventoryRepository query
+-- (list, lookup by SKU + location, trend, pick suggestions, export, valuation)
+-- reads the whole table. Two tenants with the same SKU and location code would
+-- see — and pick from — each other's stock.
+--
+-- Fix:
+--   1. Add tenant_id (nullable while backfilling).
+--   2. Backfill from the owning article (articles.sku is still globally unique),
+--      then from the location when exactly one tenant owns that location_code,
+--      then fall back to the default tenant (matches 000019/000032).
+--   3. SET NOT NULL without a DEFAULT so future inserts must set tenant_id.
+--   4. Composite indexes for the common query shapes:
+--        a. WHERE tenant_id = ? AND sku = ? AND location = ?  (lookup / pick)
+--        b. WHERE tenant_id = ? ORDER BY created_at           (list / export)
+
+ALTER TABLE inventory ADD COLUMN tenant_id UUID;
+
+UPDATE inventory i
+   SET tenant_id = a.tenant_id
+  FROM articles a
+ WHERE a.sku = i.sku
+   AND i.tenant_id IS NULL;
+
+UPDATE inventory i
+   SET tenant_id = l.tenant_id
+  FROM (
+        SELECT location_code, MIN(tenant_id::text)::uuid AS tenant_id
+          FROM locations
+         GROUP BY location_code
+        HAVING COUNT(DISTINCT tenant_id) = 1
+       ) l
+ WHERE l.location_code = i.location
+   AND i.tenant_id IS NULL;
+
+UPDATE inventory
+   SET tenant_id = '00000000-0000-0000-0000-000000000001'::uuid
+ WHERE tenant_id IS NULL;
+
+ALTER TABLE inventory ALTER COLUMN tenant_id SET NOT NULL;
+
+CREATE INDEX idx_inventory_tenant_sku_location
+  ON inventory(tenant_id, sku, location);
+
+CREATE INDEX idx_inventory_tenant_created_at
+  ON inventory(tenant_id, created_at);
@@ -0,0 +1,25 @@
+-- 000041_stock_transfer_in_transit.down.sql
+-- Reverse two-step stock transfers. In-transit transfers fall back to in_progress.
+
+DROP INDEX IF EXISTS idx_stock_transfer_lines_open;
+
+UPDATE stock_transfers SET status = 'in_progress' WHERE status IN ('in_transit', 'partially_received');
+UPDATE stock_transfer_lines SET line_status = 'picked' WHERE line_status IN ('in_transit', 'partially_received');
+UPDATE stock_transfer_lines SET line_status = 'received' WHERE line_status IN ('short_received', 'over_received');
+
+ALTER TABLE stock_transfer_lines DROP CONSTRAINT IF EXISTS chk_stock_transfer_line_moved_qty;
+ALTER TABLE stock_transfer_lines
+  DROP COLUMN IF EXISTS received_at,
+  DROP COLUMN IF EXISTS discrepancy_reason,
+  DROP COLUMN IF EXISTS received_qty,
+  DROP COLUMN IF EXISTS shipped_qty;
+
+ALTER TABLE stock_transfer_lines DROP CONSTRAINT IF EXISTS chk_stock_transfer_line_status;
+ALTER TABLE stock_transfer_lines
+  ADD CONSTRAINT chk_stock_transfer_line_status
+  CHECK (line_status IN ('pending', 'picked', 'received', 'cancelled'));
+
+ALTER TABLE stock_transfers DROP CONSTRAINT IF EXISTS chk_stock_transfer_status;
+ALTER TABLE stock_transfers
+  ADD CONSTRAINT chk_stock_transfer_status
+  CHECK (status IN ('draft', 'in_progress', 'completed', 'cancelled'));
@@ -0,0 +1,49 @@
+-- 000041_stock_transfer_in_transit.up.sql
+-- Two-step stock transfers: ship, then receive.
+--
+-- Until now a transfer moved stock in one step (execute) and line_status was never
+-- touched. Transfers between sites hours apart need the stock to leave the source
+-- when the truck leaves and arrive when the destination confirms it:
+--   * ship    — decrements the source, sets shipped_qty on every open line and puts
+--               the transfer (and its lines) in_transit.
+--   * receive — confirms lines one by one, possibly partially, short or over the
+--               shipped quantity. Received stock lands at dock_location when the
+--               transfer has one, otherwise at the destination location.
+--
+-- Lines keep shipped_qty / received_qty so discrepancies (received − shipped) can be
+-- reported once a line is closed. Discrepancies are not adjusted automatically.
+
+ALTER TABLE stock_transfers DROP CONSTRAINT IF EXISTS chk_stock_transfer_status;
+ALTER TABLE stock_transfers
+  ADD CONSTRAINT chk_stock_transfer_status
+  CHECK (status IN ('draft', 'in_progress', 'in_transit', 'partially_received', 'completed', 'cancelled'));
+
+ALTER TABLE stock_transfer_lines DROP CONSTRAINT IF EXISTS chk_stock_transfer_line_status;
+ALTER TABLE stock_transfer_lines
+  ADD CONSTRAINT chk_stock_transfer_line_status
+  CHECK (line_status IN ('pending', 'picked', 'in_transit', 'partially_received', 'received',
+                         'short_received', 'over_received', 'cancelled'));
+
+ALTER TABLE stock_transfer_lines
+  ADD COLUMN shipped_qty        NUMERIC(10,3) NOT NULL DEFAULT 0,
+  ADD COLUMN received_qty       NUMERIC(10,3) NOT NULL DEFAULT 0,
+  ADD COLUMN discrepancy_reason TEXT,
+  ADD COLUMN received_at        TIMESTAMP;
+
+ALTER TABLE stock_transfer_lines
+  ADD CONSTRAINT chk_stock_transfer_line_moved_qty CHECK (shipped_qty >= 0 AND received_qty >= 0);
+
+-- Lines of transfers already executed in one step were shipped and received in full.
+UPDATE stock_transfer_lines l
+   SET shipped_qty  = l.quantity,
+       received_qty = l.quantity,
+       line_status  = 'received',
+       received_at  = t.completed_at
+  FROM stock_transfers t
+ WHERE t.id = l.stock_transfer_id
+   AND t.status = 'completed'
+   AND l.line_status <> 'cancelled';
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfer_lines_open
+  ON stock_transfer_lines (stock_transfer_id)
+  WHERE line_status IN ('in_transit', 'partially_received');
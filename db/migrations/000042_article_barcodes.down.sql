@@ -0,0 +1,3 @@
+-- Migration 000042 down: drop article alternate barcodes.
+
+DROP TABLE IF EXISTS article_barcodes;
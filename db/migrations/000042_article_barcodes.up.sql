@@ -0,0 +1,23 @@
+-- Migration 000042: Alternate barcodes per article (GTIN and other scannable codes).
+--
+-- Pharma cartons are scanned as GS1-128 / GS1 DataMatrix carrying a GTIN (AI 01), not our
+-- SKU. article_barcodes maps those codes to articles so the scan endpoint can resolve
+-- them. GTINs (GTIN-8/12/13/14) are stored normalized to 14 digits so an EAN-13 printed on
+-- the unit and the (01) element of a carton label match the same row.
+--
+-- pack_quantity is how many article units one scan of the code represents (1 for the
+-- unit GTIN, e.g. 12 for a carton GTIN).
+
+CREATE TABLE article_barcodes (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id      UUID NOT NULL,
+  article_id     TEXT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  code           VARCHAR(64) NOT NULL,
+  code_type      VARCHAR(20) NOT NULL DEFAULT 'gtin',
+  pack_quantity  NUMERIC(10,3) NOT NULL DEFAULT 1 CHECK (pack_quantity > 0),
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT chk_article_barcodes_code_type CHECK (code_type IN ('gtin', 'internal')),
+  UNIQUE (tenant_id, code)
+);
+
+CREATE INDEX idx_article_barcodes_article ON article_barcodes (article_id);
@@ -0,0 +1,20 @@
+-- 000043_location_capacity.down.sql
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS chk_articles_dimensions;
+ALTER TABLE articles
+  DROP COLUMN IF EXISTS weight_kg,
+  DROP COLUMN IF EXISTS length_cm,
+  DROP COLUMN IF EXISTS width_cm,
+  DROP COLUMN IF EXISTS height_cm;
+
+ALTER TABLE locations DROP CONSTRAINT IF EXISTS chk_locations_capacity;
+ALTER TABLE locations
+  DROP COLUMN IF EXISTS max_units,
+  DROP COLUMN IF EXISTS max_weight_kg,
+  DROP COLUMN IF EXISTS max_volume_m3;
+
+ALTER TABLE location_types DROP CONSTRAINT IF EXISTS chk_location_types_capacity;
+ALTER TABLE location_types
+  DROP COLUMN IF EXISTS max_units,
+  DROP COLUMN IF EXISTS max_weight_kg,
+  DROP COLUMN IF EXISTS max_volume_m3;
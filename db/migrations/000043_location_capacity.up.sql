@@ -0,0 +1,45 @@
+-- 000043_location_capacity.up.sql
+-- Location capacity and article dimensions.
+--
+-- Locations and location types get optional limits on units, weight (kg) and volume (m³).
+-- A location's own limit wins over its type's default; NULL on both means unlimited.
+-- Articles get their unit weight and dimensions so stock weight and volume can be
+-- derived. Stock of articles without dimensions counts towards units only.
+--
+-- Capacity is enforced when stock is put into a location (inventory creation, receiving,
+-- stock transfers); existing overfilled locations are reported, not corrected.
+
+ALTER TABLE location_types
+  ADD COLUMN max_units     NUMERIC(12,3),
+  ADD COLUMN max_weight_kg NUMERIC(12,3),
+  ADD COLUMN max_volume_m3 NUMERIC(12,4);
+
+ALTER TABLE location_types
+  ADD CONSTRAINT chk_location_types_capacity CHECK (
+    (max_units IS NULL OR max_units > 0) AND
+    (max_weight_kg IS NULL OR max_weight_kg > 0) AND
+    (max_volume_m3 IS NULL OR max_volume_m3 > 0));
+
+ALTER TABLE locations
+  ADD COLUMN max_units     NUMERIC(12,3),
+  ADD COLUMN max_weight_kg NUMERIC(12,3),
+  ADD COLUMN max_volume_m3 NUMERIC(12,4);
+
+ALTER TABLE locations
+  ADD CONSTRAINT chk_locations_capacity CHECK (
+    (max_units IS NULL OR max_units > 0) AND
+    (max_weight_kg IS NULL OR max_weight_kg > 0) AND
+    (max_volume_m3 IS NULL OR max_volume_m3 > 0));
+
+ALTER TABLE articles
+  ADD COLUMN weight_kg NUMERIC(12,4),
+  ADD COLUMN length_cm NUMERIC(10,2),
+  ADD COLUMN width_cm  NUMERIC(10,2),
+  ADD COLUMN height_cm NUMERIC(10,2);
+
+ALTER TABLE articles
+  ADD CONSTRAINT chk_articles_dimensions CHECK (
+    (weight_kg IS NULL OR weight_kg >= 0) AND
+    (length_cm IS NULL OR length_cm >= 0) AND
+    (width_cm IS NULL OR width_cm >= 0) AND
+    (height_cm IS NULL OR height_cm >= 0));
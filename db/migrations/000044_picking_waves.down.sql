@@ -0,0 +1,4 @@
+-- Migration 000044 DOWN: drop wave picking tables.
+
+DROP TABLE IF EXISTS picking_wave_tasks;
+DROP TABLE IF EXISTS picking_waves;
@@ -0,0 +1,50 @@
+-- Migration 000044: Wave / batch picking.
+--
+-- Picking tasks were picked one order at a time. A wave groups several open or
+-- in-progress picking tasks (chosen by hand, or by customer, priority or the sales
+-- order expected date) so one operator walks the warehouse once:
+--   * picking_waves       — the wave header (WV-YYYY-NNNN) and its lifecycle
+--                           open → in_progress → completed | completed_with_differences,
+--                           or cancelled.
+--   * picking_wave_tasks  — the member tasks, in sort order. The order decides which
+--                           task a short pick is sorted to first.
+--
+-- The consolidated pick list (one line per location + SKU + lot) is derived from the
+-- member tasks' allocations; nothing is copied. Quantities picked for the wave are
+-- sorted back into each task's allocation picked_qty, and completing the wave runs the
+-- normal per-task completion (stock, sales order picked qty, delivery notes).
+--
+-- A task belongs to at most one live wave. Cancelling a wave releases its tasks
+-- (active = false) but keeps the membership for history.
+
+CREATE TABLE picking_waves (
+  id            TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id     UUID NOT NULL,
+  wave_number   TEXT NOT NULL,
+  status        TEXT NOT NULL DEFAULT 'open'
+                CHECK (status IN ('open','in_progress','completed','completed_with_differences','cancelled')),
+  group_by      TEXT NOT NULL DEFAULT 'manual'
+                CHECK (group_by IN ('manual','customer','priority','expected_date')),
+  group_value   TEXT,
+  assigned_to   TEXT REFERENCES users(id) ON DELETE SET NULL,
+  notes         TEXT,
+  created_by    TEXT REFERENCES users(id) ON DELETE SET NULL,
+  started_at    TIMESTAMPTZ,
+  completed_at  TIMESTAMPTZ,
+  cancelled_at  TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, wave_number)
+);
+CREATE INDEX idx_picking_waves_tenant_status ON picking_waves (tenant_id, status);
+CREATE INDEX idx_picking_waves_tenant_created ON picking_waves (tenant_id, created_at DESC);
+
+CREATE TABLE picking_wave_tasks (
+  wave_id          TEXT NOT NULL REFERENCES picking_waves(id) ON DELETE CASCADE,
+  picking_task_id  TEXT NOT NULL REFERENCES picking_tasks(id) ON DELETE CASCADE,
+  sequence         INT NOT NULL,
+  active           BOOLEAN NOT NULL DEFAULT true,
+  PRIMARY KEY (wave_id, picking_task_id)
+);
+CREATE UNIQUE INDEX uq_picking_wave_tasks_live_task
+  ON picking_wave_tasks (picking_task_id) WHERE active;
@@ -0,0 +1,9 @@
+-- 000045_location_pick_path.down.sql
+
+DROP INDEX IF EXISTS idx_locations_tenant_pick_sequence;
+ALTER TABLE locations DROP CONSTRAINT IF EXISTS chk_locations_pick_sequence;
+ALTER TABLE locations
+  DROP COLUMN IF EXISTS aisle,
+  DROP COLUMN IF EXISTS rack,
+  DROP COLUMN IF EXISTS level,
+  DROP COLUMN IF EXISTS pick_sequence;
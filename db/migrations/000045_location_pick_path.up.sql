@@ -0,0 +1,19 @@
+-- 000045_location_pick_path.up.sql
+-- Pick path: where a location sits on the operator's walk.
+--
+-- FEFO allocation decides what to pick; these columns decide the order in which the
+-- locations are visited. A location is placed on the walk by its explicit pick_sequence
+-- when set, otherwise by zone → aisle → rack → level, walking the aisles in a serpentine
+-- (racks ascending in one aisle, descending in the next). Locations with neither are
+-- visited last, by code.
+
+ALTER TABLE locations
+  ADD COLUMN aisle         TEXT,
+  ADD COLUMN rack          TEXT,
+  ADD COLUMN level         TEXT,
+  ADD COLUMN pick_sequence INTEGER;
+
+ALTER TABLE locations
+  ADD CONSTRAINT chk_locations_pick_sequence CHECK (pick_sequence IS NULL OR pick_sequence >= 0);
+
+CREATE INDEX idx_locations_tenant_pick_sequence ON locations (tenant_id, pick_sequence);
@@ -0,0 +1,9 @@
+-- Migration 000046 down: drop the packing stage.
+
+ALTER TABLE delivery_note_items DROP COLUMN IF EXISTS package_id;
+DROP TABLE IF EXISTS package_contents;
+DROP TABLE IF EXISTS packages;
+DROP TABLE IF EXISTS shipment_lines;
+DROP TABLE IF EXISTS shipments;
+DROP SEQUENCE IF EXISTS package_sscc_serial_seq;
+ALTER TABLE stock_settings DROP COLUMN IF EXISTS require_packing;
@@ -0,0 +1,80 @@
+-- Migration 000046: Packing stage between picking and the delivery note.
+--
+-- Until now completing a picking task created the delivery note right away. Tenants that
+-- pack before shipping turn on stock_settings.require_packing; completing a picking task
+-- then opens a shipment instead:
+--   * shipments         — one per completed picking task (SH-YYYY-NNNN), packing → packed.
+--   * shipment_lines    — what was picked, per SKU + lot, with the picked serials. This is
+--                         the snapshot the delivery note used to be built from.
+--   * packages          — cartons and pallets with weight and dimensions, identified by an
+--                         18-digit SSCC (extension digit + GS1 company prefix + serial
+--                         reference + check digit; serials come from package_sscc_serial_seq).
+--   * package_contents  — quantity (and serials) of a shipment line packed into a package.
+--
+-- Closing a fully packed shipment creates the delivery note with one item per package +
+-- SKU + lot; delivery_note_items.package_id points at the package. With require_packing
+-- off (the default) nothing changes.
+
+ALTER TABLE stock_settings ADD COLUMN require_packing BOOLEAN NOT NULL DEFAULT false;
+
+CREATE SEQUENCE package_sscc_serial_seq;
+
+CREATE TABLE shipments (
+  id               TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id        UUID NOT NULL,
+  shipment_number  TEXT NOT NULL,
+  picking_task_id  TEXT NOT NULL REFERENCES picking_tasks(id) ON DELETE CASCADE,
+  sales_order_id   TEXT NOT NULL REFERENCES sales_orders(id),
+  customer_id      TEXT REFERENCES clients(id),
+  status           TEXT NOT NULL DEFAULT 'packing' CHECK (status IN ('packing','packed')),
+  delivery_note_id TEXT REFERENCES delivery_notes(id) ON DELETE SET NULL,
+  notes            TEXT,
+  packed_by        TEXT REFERENCES users(id) ON DELETE SET NULL,
+  packed_at        TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, shipment_number),
+  UNIQUE (picking_task_id)
+);
+CREATE INDEX idx_shipments_tenant_status ON shipments (tenant_id, status);
+CREATE INDEX idx_shipments_so ON shipments (sales_order_id);
+
+CREATE TABLE shipment_lines (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  shipment_id    TEXT NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+  article_sku    TEXT NOT NULL,
+  lot_number     TEXT,
+  qty            NUMERIC(12,3) NOT NULL CHECK (qty > 0),
+  serial_numbers TEXT[] NOT NULL DEFAULT '{}'
+);
+CREATE INDEX idx_shipment_lines_shipment ON shipment_lines (shipment_id);
+
+CREATE TABLE packages (
+  id            TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id     UUID NOT NULL,
+  shipment_id   TEXT NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+  sscc          TEXT NOT NULL CHECK (sscc ~ '^[0-9]{18}$'),
+  package_type  TEXT NOT NULL DEFAULT 'carton' CHECK (package_type IN ('carton','pallet')),
+  sequence      INT NOT NULL,
+  weight_kg     NUMERIC(12,3) CHECK (weight_kg IS NULL OR weight_kg > 0),
+  length_cm     NUMERIC(12,2) CHECK (length_cm IS NULL OR length_cm > 0),
+  width_cm      NUMERIC(12,2) CHECK (width_cm IS NULL OR width_cm > 0),
+  height_cm     NUMERIC(12,2) CHECK (height_cm IS NULL OR height_cm > 0),
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, sscc),
+  UNIQUE (shipment_id, sequence)
+);
+
+CREATE TABLE package_contents (
+  id               TEXT PRIMARY KEY DEFAULT nanoid(),
+  package_id       TEXT NOT NULL REFERENCES packages(id) ON DELETE CASCADE,
+  shipment_line_id TEXT NOT NULL REFERENCES shipment_lines(id) ON DELETE CASCADE,
+  qty              NUMERIC(12,3) NOT NULL CHECK (qty > 0),
+  serial_numbers   TEXT[] NOT NULL DEFAULT '{}',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_package_contents_package ON package_contents (package_id);
+CREATE INDEX idx_package_contents_line ON package_contents (shipment_line_id);
+
+ALTER TABLE delivery_note_items ADD COLUMN package_id TEXT REFERENCES packages(id) ON DELETE SET NULL;
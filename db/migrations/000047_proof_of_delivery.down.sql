@@ -0,0 +1,14 @@
+-- Migration 000047 down: drop proof of delivery columns and the 'delivered' sales order status.
+
+UPDATE sales_orders SET status = 'completed' WHERE status = 'delivered';
+ALTER TABLE sales_orders DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE sales_orders DROP CONSTRAINT IF EXISTS sales_orders_status_check;
+ALTER TABLE sales_orders ADD CONSTRAINT sales_orders_status_check
+  CHECK (status IN ('draft','submitted','partial','completed','cancelled'));
+
+ALTER TABLE delivery_notes
+  DROP COLUMN IF EXISTS pod_recorded_by,
+  DROP COLUMN IF EXISTS pod_notes,
+  DROP COLUMN IF EXISTS pod_gps,
+  DROP COLUMN IF EXISTS pod_photo_paths,
+  DROP COLUMN IF EXISTS pod_signature_path;
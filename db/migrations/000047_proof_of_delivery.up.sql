@@ -0,0 +1,25 @@
+-- Migration 000047: Proof of delivery on delivery notes.
+--
+-- delivery_notes already had delivered_at and signed_by; nothing set them. Recording a proof
+-- of delivery (POST /api/delivery-notes/:id/pod) now fills them — signed_by is the name of
+-- the person who received the goods — together with:
+--   * pod_signature_path — storage key of the signature image (optional);
+--   * pod_photo_paths    — storage keys of the delivery photos;
+--   * pod_gps            — free-text GPS position where the delivery happened;
+--   * pod_notes, pod_recorded_by (the user who recorded the POD).
+--
+-- Sales orders gain a final 'delivered' status (and delivered_at): a completed order moves to
+-- it once every delivery note of the order has a proof of delivery and no shipment is still
+-- being packed.
+
+ALTER TABLE delivery_notes
+  ADD COLUMN pod_signature_path TEXT,
+  ADD COLUMN pod_photo_paths    TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN pod_gps            TEXT,
+  ADD COLUMN pod_notes          TEXT,
+  ADD COLUMN pod_recorded_by    TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE sales_orders DROP CONSTRAINT IF EXISTS sales_orders_status_check;
+ALTER TABLE sales_orders ADD CONSTRAINT sales_orders_status_check
+  CHECK (status IN ('draft','submitted','partial','completed','delivered','cancelled'));
+ALTER TABLE sales_orders ADD COLUMN delivered_at TIMESTAMPTZ;
@@ -0,0 +1,11 @@
+-- Migration 000048 down: back to the fixed PDF API path.
+
+UPDATE delivery_notes
+   SET pod_photo_paths = ARRAY(SELECT regexp_replace(p, '^[^/]+/(pod/)', '\1') FROM unnest(pod_photo_paths) AS p);
+UPDATE delivery_notes
+   SET pod_signature_path = regexp_replace(pod_signature_path, '^[^/]+/(pod/)', '\1')
+ WHERE pod_signature_path IS NOT NULL;
+
+ALTER TABLE delivery_notes DROP COLUMN IF EXISTS pdf_sha256;
+ALTER TABLE delivery_notes RENAME COLUMN pdf_storage_key TO pdf_url;
+UPDATE delivery_notes SET pdf_url = NULL, pdf_generated_at = NULL WHERE pdf_url IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Migration 000048: Document storage keys for delivery note PDFs.
+--
+-- Delivery note PDFs and proof-of-delivery images move from /tmp/estock-pdfs to the configured
+-- document storage (local directory or S3-compatible bucket). Objects use tenant-prefixed keys:
+--   <tenant_id>/delivery-notes/<dn_id>.pdf
+--   <tenant_id>/pod/<dn_id>/<file>
+--
+-- pdf_url held the fixed API path; it becomes pdf_storage_key (the API path is derived from
+-- the id) and pdf_sha256 keeps the checksum of the stored PDF. PDFs written to /tmp are not in
+-- the storage, so their key is cleared: they are regenerated on the next download.
+
+ALTER TABLE delivery_notes RENAME COLUMN pdf_url TO pdf_storage_key;
+ALTER TABLE delivery_notes ADD COLUMN pdf_sha256 TEXT;
+UPDATE delivery_notes SET pdf_storage_key = NULL, pdf_generated_at = NULL WHERE pdf_storage_key IS NOT NULL;
+
+-- Proof-of-delivery keys recorded before this migration ("pod/<dn_id>/<file>") get the tenant prefix.
+UPDATE delivery_notes
+   SET pod_signature_path = tenant_id::text || '/' || pod_signature_path
+ WHERE pod_signature_path LIKE 'pod/%';
+UPDATE delivery_notes
+   SET pod_photo_paths = ARRAY(SELECT tenant_id::text || '/' || p FROM unnest(pod_photo_paths) AS p)
+ WHERE EXISTS (SELECT 1 FROM unnest(pod_photo_paths) AS p WHERE p LIKE 'pod/%');
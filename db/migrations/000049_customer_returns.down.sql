@@ -0,0 +1,5 @@
+-- Migration 000049 down: drop customer returns.
+
+DROP TABLE IF EXISTS customer_return_inspections;
+DROP TABLE IF EXISTS customer_return_lines;
+DROP TABLE IF EXISTS customer_returns;
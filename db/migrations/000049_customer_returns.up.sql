@@ -0,0 +1,63 @@
+-- Migration 000049: Customer returns (RMA).
+--
+-- A return authorization is created against a delivery note or a sales order and lists the
+-- lines coming back (SKU, lot, serials, quantity) with a reason. Receiving the return is an
+-- inspection: each inspected quantity gets a disposition and posts an inventory movement.
+--   * customer_returns            — header (RMA-YYYY-NNNN):
+--                                   authorized → partially_received → received | cancelled.
+--   * customer_return_lines       — what the customer sends back and why; received_qty is
+--                                   the inspected quantity so far.
+--   * customer_return_inspections — one per inspected quantity: restock (return_restock,
+--                                   back to sellable stock), quarantine (return_quarantine,
+--                                   stock kept apart in the given location) or scrap
+--                                   (return_scrap, no stock). Restock and quarantine restore
+--                                   the lot quantities.
+
+CREATE TABLE customer_returns (
+  id               TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id        UUID NOT NULL,
+  return_number    TEXT NOT NULL,
+  sales_order_id   TEXT NOT NULL REFERENCES sales_orders(id),
+  delivery_note_id TEXT REFERENCES delivery_notes(id),
+  customer_id      TEXT REFERENCES clients(id),
+  status           TEXT NOT NULL DEFAULT 'authorized'
+                   CHECK (status IN ('authorized','partially_received','received','cancelled')),
+  notes            TEXT,
+  created_by       TEXT REFERENCES users(id) ON DELETE SET NULL,
+  received_at      TIMESTAMPTZ,
+  cancelled_at     TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, return_number)
+);
+CREATE INDEX idx_customer_returns_tenant_status ON customer_returns (tenant_id, status);
+CREATE INDEX idx_customer_returns_so ON customer_returns (sales_order_id);
+CREATE INDEX idx_customer_returns_dn ON customer_returns (delivery_note_id);
+
+CREATE TABLE customer_return_lines (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  return_id      TEXT NOT NULL REFERENCES customer_returns(id) ON DELETE CASCADE,
+  article_sku    TEXT NOT NULL,
+  lot_number     TEXT,
+  serial_numbers TEXT[] NOT NULL DEFAULT '{}',
+  qty            NUMERIC(12,3) NOT NULL CHECK (qty > 0),
+  reason_code    TEXT NOT NULL
+                 CHECK (reason_code IN ('damaged','defective','wrong_item','expired','not_ordered','other')),
+  reason_notes   TEXT,
+  received_qty   NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (received_qty >= 0 AND received_qty <= qty)
+);
+CREATE INDEX idx_customer_return_lines_return ON customer_return_lines (return_id);
+
+CREATE TABLE customer_return_inspections (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  return_line_id TEXT NOT NULL REFERENCES customer_return_lines(id) ON DELETE CASCADE,
+  qty            NUMERIC(12,3) NOT NULL CHECK (qty > 0),
+  disposition    TEXT NOT NULL CHECK (disposition IN ('restock','quarantine','scrap')),
+  location       TEXT NOT NULL,
+  serial_numbers TEXT[] NOT NULL DEFAULT '{}',
+  notes          TEXT,
+  movement_id    TEXT,
+  inspected_by   TEXT REFERENCES users(id) ON DELETE SET NULL,
+  inspected_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_customer_return_inspections_line ON customer_return_inspections (return_line_id);
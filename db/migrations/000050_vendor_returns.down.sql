@@ -0,0 +1,4 @@
+-- Migration 000050 down: drop vendor returns.
+
+DROP TABLE IF EXISTS vendor_return_lines;
+DROP TABLE IF EXISTS vendor_returns;
@@ -0,0 +1,53 @@
+-- Migration 000050: Return-to-vendor (RTV) for rejected receipt quantities.
+--
+-- Units rejected at receiving (purchase_order_items.rejected_qty, rejected_qty of the receiving
+-- task items) never enter stock. A vendor return documents sending them back to the supplier
+-- and tracks the credit the supplier grants for them.
+--   * vendor_returns      — header (RTV-YYYY-NNNN) linked to the purchase order and / or the
+--                           receiving task the rejections come from:
+--                           draft → shipped → credited, or draft → cancelled.
+--                           expected credit is the sum of the lines at PO unit cost;
+--                           credited_amount is what the supplier's credit note grants.
+--   * vendor_return_lines — rejected quantity per SKU sent back, with the PO item and its
+--                           unit cost / currency when the return is linked to a PO.
+
+CREATE TABLE vendor_returns (
+  id                 TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id          UUID NOT NULL,
+  return_number      TEXT NOT NULL,
+  purchase_order_id  TEXT REFERENCES purchase_orders(id),
+  receiving_task_id  TEXT REFERENCES receiving_tasks(id),
+  supplier_id        TEXT NOT NULL REFERENCES clients(id),
+  status             TEXT NOT NULL DEFAULT 'draft'
+                     CHECK (status IN ('draft','shipped','credited','cancelled')),
+  notes              TEXT,
+  carrier            TEXT,
+  tracking_number    TEXT,
+  shipped_at         TIMESTAMPTZ,
+  credit_note_number TEXT,
+  credited_amount    NUMERIC(14,4),
+  credited_at        TIMESTAMPTZ,
+  cancelled_at       TIMESTAMPTZ,
+  created_by         TEXT REFERENCES users(id) ON DELETE SET NULL,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, return_number),
+  CHECK (purchase_order_id IS NOT NULL OR receiving_task_id IS NOT NULL)
+);
+CREATE INDEX idx_vendor_returns_tenant_status ON vendor_returns (tenant_id, status);
+CREATE INDEX idx_vendor_returns_po ON vendor_returns (purchase_order_id);
+CREATE INDEX idx_vendor_returns_task ON vendor_returns (receiving_task_id);
+CREATE INDEX idx_vendor_returns_supplier ON vendor_returns (supplier_id);
+
+CREATE TABLE vendor_return_lines (
+  id                     TEXT PRIMARY KEY DEFAULT nanoid(),
+  return_id              TEXT NOT NULL REFERENCES vendor_returns(id) ON DELETE CASCADE,
+  purchase_order_item_id TEXT REFERENCES purchase_order_items(id),
+  article_sku            TEXT NOT NULL,
+  qty                    NUMERIC(12,3) NOT NULL CHECK (qty > 0),
+  unit_cost              NUMERIC(12,4),
+  currency               CHAR(3),
+  reason                 TEXT
+);
+CREATE INDEX idx_vendor_return_lines_return ON vendor_return_lines (return_id);
+CREATE INDEX idx_vendor_return_lines_po_item ON vendor_return_lines (purchase_order_item_id);
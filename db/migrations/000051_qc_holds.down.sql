@@ -0,0 +1,11 @@
+-- Migration 000051 down: drop the QC hold workflow.
+
+UPDATE public.roles SET permissions = permissions - 'qc_holds' WHERE LOWER(name) IN ('operator','viewer');
+
+DROP TABLE IF EXISTS qc_attachments;
+DROP TABLE IF EXISTS qc_inspections;
+DROP TABLE IF EXISTS qc_holds;
+
+ALTER TABLE stock_settings DROP COLUMN IF EXISTS require_lot_release;
+ALTER TABLE inventory DROP CONSTRAINT IF EXISTS chk_held_non_negative;
+ALTER TABLE inventory DROP COLUMN IF EXISTS held_qty;
@@ -0,0 +1,86 @@
+-- Migration 000051: Quarantine and QC hold workflow for lots and inventory.
+--
+-- Stock on QC hold cannot be allocated, reserved, picked or moved:
+--   * a lot hold moves lots.status to 'quarantine'; the QC decision moves it to 'released' or
+--     'rejected'. Stock of quarantined and rejected lots is excluded from availability.
+--   * an inventory hold blocks a quantity of one inventory row (inventory.held_qty) without a
+--     lot; release gives it back, reject scraps it with a 'qc_reject' movement.
+--   * stock_settings.require_lot_release (pharma tenants): lots not released yet — including
+--     'pending' ones — are treated as held and cannot ship.
+-- Holds are numbered QC-YYYY-NNNN per tenant and carry inspection results and attachments
+-- (files in the document storage, see migration 000048).
+
+ALTER TABLE inventory
+  ADD COLUMN IF NOT EXISTS held_qty NUMERIC(10,3) NOT NULL DEFAULT 0;
+ALTER TABLE inventory
+  ADD CONSTRAINT chk_held_non_negative CHECK (held_qty >= 0);
+
+ALTER TABLE stock_settings
+  ADD COLUMN IF NOT EXISTS require_lot_release BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE qc_holds (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id      UUID NOT NULL,
+  hold_number    TEXT NOT NULL,
+  scope          TEXT NOT NULL CHECK (scope IN ('lot','inventory')),
+  sku            TEXT NOT NULL,
+  lot_id         TEXT REFERENCES lots(id),
+  inventory_id   TEXT REFERENCES inventory(id),
+  location       TEXT,
+  qty            NUMERIC(10,3),
+  serial_numbers TEXT[] NOT NULL DEFAULT '{}',
+  status         TEXT NOT NULL DEFAULT 'quarantine'
+                 CHECK (status IN ('quarantine','released','rejected')),
+  reason         TEXT NOT NULL,
+  source_type    TEXT,
+  source_id      TEXT,
+  created_by     TEXT REFERENCES users(id) ON DELETE SET NULL,
+  decided_by     TEXT REFERENCES users(id) ON DELETE SET NULL,
+  decided_at     TIMESTAMPTZ,
+  decision_notes TEXT,
+  movement_id    TEXT,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, hold_number),
+  CHECK ((scope = 'lot' AND lot_id IS NOT NULL)
+      OR (scope = 'inventory' AND inventory_id IS NOT NULL AND qty > 0))
+);
+CREATE INDEX idx_qc_holds_tenant_status ON qc_holds (tenant_id, status);
+CREATE INDEX idx_qc_holds_sku ON qc_holds (tenant_id, sku);
+CREATE INDEX idx_qc_holds_inventory ON qc_holds (inventory_id);
+-- One open hold per lot.
+CREATE UNIQUE INDEX uq_qc_holds_open_lot ON qc_holds (lot_id) WHERE scope = 'lot' AND status = 'quarantine';
+
+CREATE TABLE qc_inspections (
+  id           TEXT PRIMARY KEY DEFAULT nanoid(),
+  hold_id      TEXT NOT NULL REFERENCES qc_holds(id) ON DELETE CASCADE,
+  result       TEXT NOT NULL CHECK (result IN ('pass','fail','conditional')),
+  notes        TEXT,
+  measurements JSONB,
+  inspected_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+  inspected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_qc_inspections_hold ON qc_inspections (hold_id);
+
+CREATE TABLE qc_attachments (
+  id            TEXT PRIMARY KEY DEFAULT nanoid(),
+  hold_id       TEXT NOT NULL REFERENCES qc_holds(id) ON DELETE CASCADE,
+  inspection_id TEXT REFERENCES qc_inspections(id) ON DELETE SET NULL,
+  file_name     TEXT NOT NULL,
+  content_type  TEXT NOT NULL,
+  size_bytes    BIGINT NOT NULL,
+  storage_key   TEXT NOT NULL,
+  sha256        TEXT NOT NULL,
+  uploaded_by   TEXT REFERENCES users(id) ON DELETE SET NULL,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_qc_attachments_hold ON qc_attachments (hold_id);
+
+-- Operators open holds and record inspections; releasing or rejecting ('release') stays with
+-- Admin unless granted explicitly.
+UPDATE public.roles
+   SET permissions = permissions || '{"qc_holds": {"read": true, "create": true}}'::jsonb
+ WHERE LOWER(name) = 'operator';
+UPDATE public.roles
+   SET permissions = permissions || '{"qc_holds": {"read": true}}'::jsonb
+ WHERE LOWER(name) = 'viewer';
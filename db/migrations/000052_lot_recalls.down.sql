@@ -0,0 +1,12 @@
+-- Migration 000052 down: drop the lot recall workflow. Recalled lots go back to the status
+-- they had before their recall.
+
+UPDATE public.roles SET permissions = permissions - 'lot_recalls' WHERE LOWER(name) IN ('operator','viewer');
+
+UPDATE lots l
+   SET status = r.previous_lot_status, updated_at = NOW()
+  FROM lot_recalls r
+ WHERE r.lot_id = l.id AND r.status <> 'cancelled' AND l.status = 'recalled';
+
+DROP TABLE IF EXISTS lot_recall_tasks;
+DROP TABLE IF EXISTS lot_recalls;
@@ -0,0 +1,66 @@
+-- Migration 000052: Lot recall workflow.
+--
+-- A recall freezes a lot everywhere: lots.status moves to 'recalled' and, like a QC hold
+-- (migration 000051), its stock is excluded from allocation, reservations, picking and moves.
+--   * lot_recalls      — header (RCL-YYYY-NNNN) per lot: open → closed, or open → cancelled
+--                        (cancelling restores previous_lot_status). At most one open recall
+--                        per lot.
+--   * lot_recall_tasks — one task per location holding the lot when the recall opened:
+--                        'pick_back' moves the stock to the recall's return location
+--                        (movements 'recall_out' / 'recall_in'), 'quarantine' segregates it
+--                        in place. A recall closes once no task is pending.
+-- Affected customers, sales orders and delivery notes are derived from the delivery notes
+-- that shipped the lot (delivery_note_items.lot_numbers).
+
+CREATE TABLE lot_recalls (
+  id                  TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id           UUID NOT NULL,
+  recall_number       TEXT NOT NULL,
+  lot_id              TEXT NOT NULL REFERENCES lots(id),
+  sku                 TEXT NOT NULL,
+  lot_number          TEXT NOT NULL,
+  status              TEXT NOT NULL DEFAULT 'open'
+                      CHECK (status IN ('open','closed','cancelled')),
+  classification      TEXT CHECK (classification IN ('I','II','III')),
+  reason              TEXT NOT NULL,
+  return_location     TEXT,
+  previous_lot_status TEXT,
+  notes               TEXT,
+  created_by          TEXT REFERENCES users(id) ON DELETE SET NULL,
+  closed_by           TEXT REFERENCES users(id) ON DELETE SET NULL,
+  closed_at           TIMESTAMPTZ,
+  cancelled_at        TIMESTAMPTZ,
+  created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, recall_number)
+);
+CREATE INDEX idx_lot_recalls_tenant_status ON lot_recalls (tenant_id, status);
+CREATE UNIQUE INDEX uq_lot_recalls_open_lot ON lot_recalls (lot_id) WHERE status = 'open';
+
+CREATE TABLE lot_recall_tasks (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  recall_id       TEXT NOT NULL REFERENCES lot_recalls(id) ON DELETE CASCADE,
+  task_type       TEXT NOT NULL CHECK (task_type IN ('pick_back','quarantine')),
+  inventory_id    TEXT NOT NULL REFERENCES inventory(id),
+  location        TEXT NOT NULL,
+  qty             NUMERIC(10,3) NOT NULL CHECK (qty >= 0),
+  target_location TEXT,
+  status          TEXT NOT NULL DEFAULT 'pending'
+                  CHECK (status IN ('pending','completed','cancelled')),
+  completed_qty   NUMERIC(10,3),
+  notes           TEXT,
+  completed_by    TEXT REFERENCES users(id) ON DELETE SET NULL,
+  completed_at    TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (task_type = 'quarantine' OR target_location IS NOT NULL)
+);
+CREATE INDEX idx_lot_recall_tasks_recall ON lot_recall_tasks (recall_id);
+
+-- Operators carry out the recall tasks; opening, closing and cancelling recalls stays with
+-- Admin unless granted explicitly.
+UPDATE public.roles
+   SET permissions = permissions || '{"lot_recalls": {"read": true, "update": true}}'::jsonb
+ WHERE LOWER(name) = 'operator';
+UPDATE public.roles
+   SET permissions = permissions || '{"lot_recalls": {"read": true}}'::jsonb
+ WHERE LOWER(name) = 'viewer';
@@ -0,0 +1,15 @@
+-- Migration 000053 down: drop the serial history and go back to the free-form legacy
+-- statuses (warehouse states become 'available', returned becomes 'quarantined').
+
+DROP TABLE IF EXISTS serial_history;
+
+ALTER TABLE serials DROP CONSTRAINT IF EXISTS chk_serials_status;
+ALTER TABLE serials ALTER COLUMN status SET DEFAULT 'available';
+
+UPDATE serials
+   SET status = CASE status
+         WHEN 'returned' THEN 'quarantined'
+         WHEN 'scrapped' THEN 'scrapped'
+         WHEN 'shipped' THEN 'shipped'
+         ELSE 'available'
+       END;
@@ -0,0 +1,45 @@
+-- Migration 000053: Serial lifecycle and chain of custody.
+--
+-- serials.status becomes a state machine: received → in_stock → reserved → shipped →
+-- returned, and scrapped from any warehouse state (see repositories/serial_lifecycle.go).
+-- Legacy statuses are mapped: 'available' is in_stock when the serial is linked to an
+-- inventory row and received otherwise (declared on a receiving task, never put away);
+-- 'quarantined' (customer return held by QC) is returned.
+--   * serial_history — one row per state change with location, document (type, id and
+--                      human number) and user. Existing serials get a first row with their
+--                      current status so every chain of custody has a start.
+
+UPDATE serials s
+   SET status = CASE
+         WHEN s.status IN ('received','in_stock','reserved','shipped','returned','scrapped') THEN s.status
+         WHEN s.status = 'quarantined' THEN 'returned'
+         WHEN EXISTS (SELECT 1 FROM inventory_serials i WHERE i.serial_id = s.id) THEN 'in_stock'
+         ELSE 'received'
+       END;
+
+ALTER TABLE serials ALTER COLUMN status SET DEFAULT 'received';
+ALTER TABLE serials ADD CONSTRAINT chk_serials_status
+  CHECK (status IN ('received','in_stock','reserved','shipped','returned','scrapped'));
+
+CREATE TABLE serial_history (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id       UUID NOT NULL,
+  serial_id       TEXT NOT NULL REFERENCES serials(id) ON DELETE CASCADE,
+  from_status     TEXT,
+  to_status       TEXT NOT NULL,
+  location        TEXT,
+  document_type   TEXT,
+  document_id     TEXT,
+  document_number TEXT,
+  user_id         TEXT REFERENCES users(id) ON DELETE SET NULL,
+  notes           TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_serial_history_serial ON serial_history (serial_id, created_at);
+CREATE INDEX idx_serial_history_document ON serial_history (tenant_id, document_type, document_id);
+
+INSERT INTO serial_history (tenant_id, serial_id, to_status, location, notes, created_at)
+SELECT s.tenant_id, s.id, s.status,
+       (SELECT i.location FROM inventory_serials i WHERE i.serial_id = s.id ORDER BY i.created_at DESC LIMIT 1),
+       'Estado inicial (migración 000053)', COALESCE(s.updated_at, NOW())
+  FROM serials s;
@@ -0,0 +1,7 @@
+-- Migration 000054 down: drop the number series counters and tenant patterns.
+
+DROP TABLE IF EXISTS number_series_counters;
+
+ALTER TABLE stock_settings
+  DROP COLUMN IF EXISTS serial_number_pattern,
+  DROP COLUMN IF EXISTS lot_number_pattern;
@@ -0,0 +1,24 @@
+-- Migration 000054: Automatic lot and serial numbers.
+--
+-- Lot and serial numbers can be generated from a pattern such as LOT-{YYYY}{MM}-{####}
+-- (tools/number_series.go). The article's batch_number_series / serial_number_series wins;
+-- otherwise the tenant pattern below applies.
+--   * stock_settings.lot_number_pattern / serial_number_pattern — tenant patterns, NULL
+--     uses the built-in default.
+--   * number_series_counters — last number handed out per (tenant, kind, series key). The
+--     key is the pattern rendered without its counter (e.g. LOT-202610-{#}), so date and
+--     SKU tokens start their own count. Numbers are taken with an UPSERT … RETURNING, whose
+--     row lock serializes concurrent receipts on the same series.
+
+ALTER TABLE stock_settings
+  ADD COLUMN IF NOT EXISTS lot_number_pattern TEXT,
+  ADD COLUMN IF NOT EXISTS serial_number_pattern TEXT;
+
+CREATE TABLE IF NOT EXISTS number_series_counters (
+  tenant_id   UUID NOT NULL,
+  kind        TEXT NOT NULL CHECK (kind IN ('lot', 'serial')),
+  series_key  TEXT NOT NULL,
+  last_value  BIGINT NOT NULL DEFAULT 0 CHECK (last_value >= 0),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, kind, series_key)
+);
@@ -0,0 +1,8 @@
+-- Migration 000055 down: drop kits and assembly orders. Movements already posted stay.
+
+UPDATE public.roles SET permissions = permissions - 'kits' - 'assembly_orders' WHERE LOWER(name) IN ('operator','viewer');
+
+DROP TABLE IF EXISTS assembly_order_lines;
+DROP TABLE IF EXISTS assembly_orders;
+DROP TABLE IF EXISTS kit_components;
+DROP TABLE IF EXISTS kits;
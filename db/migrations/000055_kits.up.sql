@@ -0,0 +1,79 @@
+-- Migration 000055: Kits (bills of materials) and assembly / disassembly orders.
+--
+--   * kits                 — one BOM per kit article (kit_sku). explode_on_pick lets a sales
+--                            order short of assembled kits pick the missing kits as their
+--                            components instead.
+--   * kit_components       — components of a kit and their quantity per kit.
+--   * assembly_orders      — ASM-YYYY-NNNN, draft → completed | cancelled. An assembly
+--                            consumes the components at the order location (FEFO lots,
+--                            movements 'assembly_consume') and produces the kit there
+--                            ('assembly_produce') at the cost of the consumed components. A
+--                            disassembly does the reverse ('disassembly_consume' /
+--                            'disassembly_produce'), splitting the kit cost over the components.
+--   * assembly_order_lines — the component lines, snapshot of the BOM when the order was
+--                            created; on completion they keep the lots moved and their cost.
+
+CREATE TABLE kits (
+  id              TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id       UUID NOT NULL,
+  kit_sku         TEXT NOT NULL,
+  explode_on_pick BOOLEAN NOT NULL DEFAULT false,
+  notes           TEXT,
+  created_by      TEXT REFERENCES users(id) ON DELETE SET NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, kit_sku)
+);
+
+CREATE TABLE kit_components (
+  id            TEXT PRIMARY KEY DEFAULT nanoid(),
+  kit_id        TEXT NOT NULL REFERENCES kits(id) ON DELETE CASCADE,
+  component_sku TEXT NOT NULL,
+  quantity      NUMERIC(10,3) NOT NULL CHECK (quantity > 0),
+  UNIQUE (kit_id, component_sku)
+);
+CREATE INDEX idx_kit_components_sku ON kit_components (component_sku);
+
+CREATE TABLE assembly_orders (
+  id             TEXT PRIMARY KEY DEFAULT nanoid(),
+  tenant_id      UUID NOT NULL,
+  order_number   TEXT NOT NULL,
+  order_type     TEXT NOT NULL CHECK (order_type IN ('assembly','disassembly')),
+  kit_sku        TEXT NOT NULL,
+  quantity       NUMERIC(10,3) NOT NULL CHECK (quantity > 0),
+  location       TEXT NOT NULL,
+  kit_lot_number TEXT,
+  kit_unit_cost  NUMERIC(12,4),
+  status         TEXT NOT NULL DEFAULT 'draft'
+                 CHECK (status IN ('draft','completed','cancelled')),
+  notes          TEXT,
+  created_by     TEXT REFERENCES users(id) ON DELETE SET NULL,
+  completed_by   TEXT REFERENCES users(id) ON DELETE SET NULL,
+  completed_at   TIMESTAMPTZ,
+  cancelled_at   TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tenant_id, order_number)
+);
+CREATE INDEX idx_assembly_orders_tenant_status ON assembly_orders (tenant_id, status);
+
+CREATE TABLE assembly_order_lines (
+  id            TEXT PRIMARY KEY DEFAULT nanoid(),
+  order_id      TEXT NOT NULL REFERENCES assembly_orders(id) ON DELETE CASCADE,
+  component_sku TEXT NOT NULL,
+  qty_per_kit   NUMERIC(10,3) NOT NULL CHECK (qty_per_kit > 0),
+  quantity      NUMERIC(10,3) NOT NULL CHECK (quantity > 0),
+  lot_number    TEXT,
+  allocations   JSONB,
+  unit_cost     NUMERIC(12,4),
+  total_cost    NUMERIC(14,4)
+);
+CREATE INDEX idx_assembly_order_lines_order ON assembly_order_lines (order_id);
+
+-- Operators carry out assembly orders; BOMs stay with Admin unless granted explicitly.
+UPDATE public.roles
+   SET permissions = permissions || '{"kits": {"read": true}, "assembly_orders": {"read": true, "create": true, "update": true}}'::jsonb
+ WHERE LOWER(name) = 'operator';
+UPDATE public.roles
+   SET permissions = permissions || '{"kits": {"read": true}, "assembly_orders": {"read": true}}'::jsonb
+ WHERE LOWER(name) = 'viewer';
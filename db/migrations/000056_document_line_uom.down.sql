@@ -0,0 +1,15 @@
+-- Migration 000056 down: drop the entered presentation of document lines. Quantities stay in base units.
+
+ALTER TABLE stock_transfer_lines DROP COLUMN IF EXISTS presentation_factor;
+
+ALTER TABLE delivery_note_items
+  DROP COLUMN IF EXISTS presentation_factor,
+  DROP COLUMN IF EXISTS presentation;
+
+ALTER TABLE purchase_order_items
+  DROP COLUMN IF EXISTS presentation_factor,
+  DROP COLUMN IF EXISTS presentation;
+
+ALTER TABLE sales_order_items
+  DROP COLUMN IF EXISTS presentation_factor,
+  DROP COLUMN IF EXISTS presentation;
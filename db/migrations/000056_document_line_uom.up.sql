@@ -0,0 +1,23 @@
+-- Migration 000056: unit of measure on document lines.
+--
+-- Quantities on sales order, purchase order, delivery note and stock transfer lines are always
+-- stored in the article's base presentation. A line entered in another presentation (e.g. 2
+-- CAJA of an article kept in UNIDAD) keeps that presentation and the conversion factor used
+-- (base units per unit of the presentation) so it can be shown as entered: qty / factor.
+-- Receiving and picking task items are JSONB and carry the same two fields inside the item.
+-- stock_transfer_lines already had presentation; only the factor is new there.
+
+ALTER TABLE sales_order_items
+  ADD COLUMN IF NOT EXISTS presentation VARCHAR(50),
+  ADD COLUMN IF NOT EXISTS presentation_factor NUMERIC(18, 6);
+
+ALTER TABLE purchase_order_items
+  ADD COLUMN IF NOT EXISTS presentation VARCHAR(50),
+  ADD COLUMN IF NOT EXISTS presentation_factor NUMERIC(18, 6);
+
+ALTER TABLE delivery_note_items
+  ADD COLUMN IF NOT EXISTS presentation VARCHAR(50),
+  ADD COLUMN IF NOT EXISTS presentation_factor NUMERIC(18, 6);
+
+ALTER TABLE stock_transfer_lines
+  ADD COLUMN IF NOT EXISTS presentation_factor NUMERIC(18, 6);
@@ -0,0 +1,3 @@
+-- Migration 000057 down: revoke the cycle count permissions of the default non-admin roles.
+
+UPDATE public.roles SET permissions = permissions - 'cycle_counts' WHERE LOWER(name) IN ('operator','viewer');
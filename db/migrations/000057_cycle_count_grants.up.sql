@@ -0,0 +1,11 @@
+-- Migration 000057: cycle count permissions for the default non-admin roles.
+--
+-- Operators generate count tasks and record counts; approving (which posts the adjustments)
+-- and deleting tasks stay with Admin unless granted explicitly.
+
+UPDATE public.roles
+   SET permissions = permissions || '{"cycle_counts": {"read": true, "create": true, "update": true}}'::jsonb
+ WHERE LOWER(name) = 'operator';
+UPDATE public.roles
+   SET permissions = permissions || '{"cycle_counts": {"read": true}}'::jsonb
+ WHERE LOWER(name) = 'viewer';
@@ -0,0 +1,6 @@
+-- Migration 000058 down: revoke the over-receipt override from operators, keeping their other
+-- receiving_tasks permissions.
+
+UPDATE public.roles
+   SET permissions = permissions || jsonb_build_object('receiving_tasks', (permissions->'receiving_tasks') - 'override')
+ WHERE LOWER(name) = 'operator' AND permissions ? 'receiving_tasks';
@@ -0,0 +1,9 @@
+-- Migration 000058: let operators confirm receipts above the over-receipt allowance.
+--
+-- The override is merged into whatever receiving_tasks permissions the role already has so the
+-- existing read/create/update flags are kept. Revoke it per role to require an Admin instead.
+
+UPDATE public.roles
+   SET permissions = permissions || jsonb_build_object(
+           'receiving_tasks', COALESCE(permissions->'receiving_tasks', '{}'::jsonb) || '{"override": true}'::jsonb)
+ WHERE LOWER(name) = 'operator';
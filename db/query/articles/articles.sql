@@ -13,7 +13,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 ORDER BY created_at ASC;
 
@@ -25,7 +26,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE tenant_id = $1
 ORDER BY created_at DESC;
@@ -38,7 +40,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE id = $1
 LIMIT 1;
@@ -51,7 +54,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE id = $1 AND tenant_id = $2
 LIMIT 1;
@@ -64,7 +68,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE sku = $1
 LIMIT 1;
@@ -77,7 +82,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE sku = $1 AND tenant_id = $2
 LIMIT 1;
@@ -97,10 +103,12 @@ INSERT INTO articles (
     min_quantity, max_quantity, image_url,
     category_id, shelf_life_in_days, safety_stock, batch_number_series,
     serial_number_series, min_order_qty, default_location_id,
-    receiving_notes, shipping_notes
+    receiving_notes, shipping_notes, price_currency,
+    weight_kg, length_cm, width_cm, height_cm
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
-    $14, $15, $16, $17, $18, $19, $20, $21, $22
+    $14, $15, $16, $17, $18, $19, $20, $21, $22, $23,
+    $24, $25, $26, $27
 )
 RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           track_by_lot, track_by_serial, track_expiration, rotation_strategy,
@@ -108,7 +116,8 @@ RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           created_at, updated_at,
           category_id, shelf_life_in_days, safety_stock, batch_number_series,
           serial_number_series, min_order_qty, default_location_id,
-          receiving_notes, shipping_notes;
+          receiving_notes, shipping_notes, price_currency,
+          weight_kg, length_cm, width_cm, height_cm;
 
 -- name: UpdateArticle :one
 -- Tenant guard via WHERE id = $1 AND tenant_id = $24 — prevents cross-tenant update.
@@ -136,6 +145,11 @@ SET
     default_location_id = $21,
     receiving_notes = $22,
     shipping_notes = $23,
+    price_currency = $25,
+    weight_kg = $26,
+    length_cm = $27,
+    width_cm = $28,
+    height_cm = $29,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND tenant_id = $24
 RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
@@ -144,7 +158,8 @@ RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           created_at, updated_at,
           category_id, shelf_life_in_days, safety_stock, batch_number_series,
           serial_number_series, min_order_qty, default_location_id,
-          receiving_notes, shipping_notes;
+          receiving_notes, shipping_notes, price_currency,
+          weight_kg, length_cm, width_cm, height_cm;
 
 -- name: DeleteArticle :exec
 -- Tenant guard prevents cross-tenant delete.
@@ -1,24 +1,28 @@
 -- Location types CRUD for sqlc. Schema: db/migrations (location_types table)
 
 -- name: ListLocationTypes :many
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE is_active = true
 ORDER BY sort_order ASC, code ASC;
 
 -- name: ListLocationTypesAdmin :many
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 ORDER BY sort_order ASC, code ASC;
 
 -- name: GetLocationTypeByID :one
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE id = $1
 LIMIT 1;
 
 -- name: GetLocationTypeByCode :one
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE code = $1
 LIMIT 1;
@@ -27,9 +31,10 @@ LIMIT 1;
 SELECT EXISTS(SELECT 1 FROM location_types WHERE code = $1) AS exists;
 
 -- name: CreateLocationType :one
-INSERT INTO location_types (code, name, sort_order, is_active)
-VALUES ($1, $2, $3, $4)
-RETURNING id, code, name, sort_order, is_active, created_at, updated_at;
+INSERT INTO location_types (code, name, sort_order, is_active, max_units, max_weight_kg, max_volume_m3)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, code, name, sort_order, is_active, created_at, updated_at,
+          max_units, max_weight_kg, max_volume_m3;
 
 -- name: UpdateLocationType :one
 UPDATE location_types
@@ -38,9 +43,13 @@ SET
     name = $3,
     sort_order = $4,
     is_active = $5,
+    max_units = $6,
+    max_weight_kg = $7,
+    max_volume_m3 = $8,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, code, name, sort_order, is_active, created_at, updated_at;
+RETURNING id, code, name, sort_order, is_active, created_at, updated_at,
+          max_units, max_weight_kg, max_volume_m3;
 
 -- name: DeleteLocationType :exec
 DELETE FROM location_types WHERE id = $1;
@@ -4,21 +4,24 @@
 
 -- name: ListLocationsByTenant :many
 -- S3.5 W2-A: tenant_id guard prevents cross-tenant location enumeration.
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE tenant_id = $1
 ORDER BY created_at ASC;
 
 -- name: GetLocationByIDForTenant :one
 -- S3.5 W2-A: tenant_id guard prevents cross-tenant id lookup.
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE id = $1 AND tenant_id = $2
 LIMIT 1;
 
 -- name: GetLocationByLocationCodeForTenant :one
 -- S3.5 W2-A: tenant_id guard. Used as fallback by ID lookup when caller passed a code.
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE location_code = $1 AND tenant_id = $2
 LIMIT 1;
@@ -31,9 +34,11 @@ SELECT EXISTS(
 
 -- name: CreateLocation :one
 -- S3.5 W2-A: tenant_id is required and provided by the controller layer.
-INSERT INTO locations (location_code, description, zone, type, is_active, is_way_out, tenant_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id;
+INSERT INTO locations (location_code, description, zone, type, is_active, is_way_out, tenant_id,
+                       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+          max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence;
 
 -- name: UpdateLocationForTenant :one
 -- S3.5 W2-A: tenant_id guard prevents cross-tenant update.
@@ -45,9 +50,17 @@ SET
     type = $5,
     is_active = $6,
     is_way_out = $7,
+    max_units = $9,
+    max_weight_kg = $10,
+    max_volume_m3 = $11,
+    aisle = $12,
+    rack = $13,
+    level = $14,
+    pick_sequence = $15,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND tenant_id = $8
-RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id;
+RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+          max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence;
 
 -- name: DeleteLocationForTenant :exec
 -- S3.5 W2-A: tenant_id guard prevents cross-tenant delete.
@@ -5,7 +5,8 @@
 SELECT tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy, updated_at
+  partial_delivery_policy, updated_at, base_currency, require_packing,
+  require_lot_release, lot_number_pattern, serial_number_pattern
 FROM stock_settings WHERE tenant_id = $1;
 
 -- name: UpsertStockSettings :one
@@ -13,8 +14,9 @@ INSERT INTO stock_settings (
   tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+  partial_delivery_policy, base_currency, require_packing, require_lot_release,
+  lot_number_pattern, serial_number_pattern
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 ON CONFLICT (tenant_id) DO UPDATE SET
   valuation_method = EXCLUDED.valuation_method,
   pick_batch_based_on = EXCLUDED.pick_batch_based_on,
@@ -26,8 +28,14 @@ ON CONFLICT (tenant_id) DO UPDATE SET
   expiry_alert_days = EXCLUDED.expiry_alert_days,
   auto_create_material_request = EXCLUDED.auto_create_material_request,
   partial_delivery_policy = EXCLUDED.partial_delivery_policy,
+  base_currency = EXCLUDED.base_currency,
+  require_packing = EXCLUDED.require_packing,
+  require_lot_release = EXCLUDED.require_lot_release,
+  lot_number_pattern = EXCLUDED.lot_number_pattern,
+  serial_number_pattern = EXCLUDED.serial_number_pattern,
   updated_at = now()
 RETURNING tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy, updated_at;
+  partial_delivery_policy, updated_at, base_currency, require_packing,
+  require_lot_release, lot_number_pattern, serial_number_pattern;
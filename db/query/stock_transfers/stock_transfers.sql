@@ -45,27 +45,27 @@ RETURNING id, transfer_number, from_location_id, to_location_id, status, created
 DELETE FROM stock_transfers WHERE id = $1;
 
 -- name: ListStockTransferLinesByTransferID :many
-SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 FROM stock_transfer_lines
 WHERE stock_transfer_id = $1
 ORDER BY created_at ASC;
 
 -- name: GetStockTransferLineByID :one
-SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 FROM stock_transfer_lines
 WHERE id = $1
 LIMIT 1;
 
 -- name: CreateStockTransferLine :one
-INSERT INTO stock_transfer_lines (stock_transfer_id, sku, quantity, presentation, line_status)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at;
+INSERT INTO stock_transfer_lines (stock_transfer_id, sku, quantity, presentation, line_status, presentation_factor)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor;
 
 -- name: UpdateStockTransferLine :one
 UPDATE stock_transfer_lines
-SET quantity = $2, presentation = $3, line_status = $4
+SET quantity = $2, presentation = $3, line_status = $4, presentation_factor = $5
 WHERE id = $1
-RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at;
+RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor;
 
 -- name: DeleteStockTransferLine :exec
 DELETE FROM stock_transfer_lines WHERE id = $1;
@@ -46,10 +46,12 @@ INSERT INTO articles (
     min_quantity, max_quantity, image_url,
     category_id, shelf_life_in_days, safety_stock, batch_number_series,
     serial_number_series, min_order_qty, default_location_id,
-    receiving_notes, shipping_notes
+    receiving_notes, shipping_notes, price_currency,
+    weight_kg, length_cm, width_cm, height_cm
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
-    $14, $15, $16, $17, $18, $19, $20, $21, $22
+    $14, $15, $16, $17, $18, $19, $20, $21, $22, $23,
+    $24, $25, $26, $27
 )
 RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           track_by_lot, track_by_serial, track_expiration, rotation_strategy,
@@ -57,7 +59,8 @@ RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           created_at, updated_at,
           category_id, shelf_life_in_days, safety_stock, batch_number_series,
           serial_number_series, min_order_qty, default_location_id,
-          receiving_notes, shipping_notes
+          receiving_notes, shipping_notes, price_currency,
+          weight_kg, length_cm, width_cm, height_cm
 `
 
 type CreateArticleParams struct {
@@ -83,6 +86,11 @@ type CreateArticleParams struct {
 	DefaultLocationID  pgtype.Text    `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text    `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text    `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text    `json:"price_currency"`
+	WeightKg           pgtype.Numeric `json:"weight_kg"`
+	LengthCm           pgtype.Numeric `json:"length_cm"`
+	WidthCm            pgtype.Numeric `json:"width_cm"`
+	HeightCm           pgtype.Numeric `json:"height_cm"`
 }
 
 type CreateArticleRow struct {
@@ -112,6 +120,11 @@ type CreateArticleRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // All inserts now require tenant_id ($1).
@@ -139,6 +152,11 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (C
 		arg.DefaultLocationID,
 		arg.ReceivingNotes,
 		arg.ShippingNotes,
+		arg.PriceCurrency,
+		arg.WeightKg,
+		arg.LengthCm,
+		arg.WidthCm,
+		arg.HeightCm,
 	)
 	var i CreateArticleRow
 	err := row.Scan(
@@ -168,6 +186,11 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (C
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -194,7 +217,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE id = $1
 LIMIT 1
@@ -227,6 +251,11 @@ type GetArticleByIDRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // INTERNAL USE ONLY. HTTP handlers must call GetArticleByIDForTenant.
@@ -260,6 +289,11 @@ func (q *Queries) GetArticleByID(ctx context.Context, id string) (GetArticleByID
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -271,7 +305,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE id = $1 AND tenant_id = $2
 LIMIT 1
@@ -309,6 +344,11 @@ type GetArticleByIDForTenantRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // HR-style tenant guard. Use for HTTP responses to prevent cross-tenant enumeration.
@@ -342,6 +382,11 @@ func (q *Queries) GetArticleByIDForTenant(ctx context.Context, arg GetArticleByI
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -353,7 +398,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE sku = $1
 LIMIT 1
@@ -386,6 +432,11 @@ type GetArticleBySkuRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // INTERNAL USE ONLY (FK lookups, dashboards). HTTP handlers must call GetArticleBySkuForTenant.
@@ -419,6 +470,11 @@ func (q *Queries) GetArticleBySku(ctx context.Context, sku string) (GetArticleBy
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -430,7 +486,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE sku = $1 AND tenant_id = $2
 LIMIT 1
@@ -468,6 +525,11 @@ type GetArticleBySkuForTenantRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // Per-tenant SKU lookup. Hits articles_tenant_sku_key index.
@@ -501,6 +563,11 @@ func (q *Queries) GetArticleBySkuForTenant(ctx context.Context, arg GetArticleBy
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -513,7 +580,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 ORDER BY created_at ASC
 `
@@ -545,6 +613,11 @@ type ListArticlesRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // Articles CRUD and related queries for sqlc.
@@ -590,6 +663,11 @@ func (q *Queries) ListArticles(ctx context.Context) ([]ListArticlesRow, error) {
 			&i.DefaultLocationID,
 			&i.ReceivingNotes,
 			&i.ShippingNotes,
+			&i.PriceCurrency,
+			&i.WeightKg,
+			&i.LengthCm,
+			&i.WidthCm,
+			&i.HeightCm,
 		); err != nil {
 			return nil, err
 		}
@@ -608,7 +686,8 @@ SELECT id, tenant_id, sku, name, description, unit_price, presentation,
        created_at, updated_at,
        category_id, shelf_life_in_days, safety_stock, batch_number_series,
        serial_number_series, min_order_qty, default_location_id,
-       receiving_notes, shipping_notes
+       receiving_notes, shipping_notes, price_currency,
+       weight_kg, length_cm, width_cm, height_cm
 FROM articles
 WHERE tenant_id = $1
 ORDER BY created_at DESC
@@ -641,6 +720,11 @@ type ListArticlesForTenantRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // HTTP-facing list. Uses idx_articles_tenant_created (composite covering index).
@@ -680,6 +764,11 @@ func (q *Queries) ListArticlesForTenant(ctx context.Context, tenantID pgtype.UUI
 			&i.DefaultLocationID,
 			&i.ReceivingNotes,
 			&i.ShippingNotes,
+			&i.PriceCurrency,
+			&i.WeightKg,
+			&i.LengthCm,
+			&i.WidthCm,
+			&i.HeightCm,
 		); err != nil {
 			return nil, err
 		}
@@ -804,6 +893,11 @@ SET
     default_location_id = $21,
     receiving_notes = $22,
     shipping_notes = $23,
+    price_currency = $25,
+    weight_kg = $26,
+    length_cm = $27,
+    width_cm = $28,
+    height_cm = $29,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND tenant_id = $24
 RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
@@ -812,7 +906,8 @@ RETURNING id, tenant_id, sku, name, description, unit_price, presentation,
           created_at, updated_at,
           category_id, shelf_life_in_days, safety_stock, batch_number_series,
           serial_number_series, min_order_qty, default_location_id,
-          receiving_notes, shipping_notes
+          receiving_notes, shipping_notes, price_currency,
+          weight_kg, length_cm, width_cm, height_cm
 `
 
 type UpdateArticleParams struct {
@@ -840,6 +935,11 @@ type UpdateArticleParams struct {
 	ReceivingNotes     pgtype.Text    `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text    `json:"shipping_notes"`
 	TenantID           pgtype.UUID    `json:"tenant_id"`
+	PriceCurrency      pgtype.Text    `json:"price_currency"`
+	WeightKg           pgtype.Numeric `json:"weight_kg"`
+	LengthCm           pgtype.Numeric `json:"length_cm"`
+	WidthCm            pgtype.Numeric `json:"width_cm"`
+	HeightCm           pgtype.Numeric `json:"height_cm"`
 }
 
 type UpdateArticleRow struct {
@@ -869,6 +969,11 @@ type UpdateArticleRow struct {
 	DefaultLocationID  pgtype.Text      `json:"default_location_id"`
 	ReceivingNotes     pgtype.Text      `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text      `json:"shipping_notes"`
+	PriceCurrency      pgtype.Text      `json:"price_currency"`
+	WeightKg           pgtype.Numeric   `json:"weight_kg"`
+	LengthCm           pgtype.Numeric   `json:"length_cm"`
+	WidthCm            pgtype.Numeric   `json:"width_cm"`
+	HeightCm           pgtype.Numeric   `json:"height_cm"`
 }
 
 // Tenant guard via WHERE id = $1 AND tenant_id = $24 — prevents cross-tenant update.
@@ -898,6 +1003,11 @@ func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (U
 		arg.ReceivingNotes,
 		arg.ShippingNotes,
 		arg.TenantID,
+		arg.PriceCurrency,
+		arg.WeightKg,
+		arg.LengthCm,
+		arg.WidthCm,
+		arg.HeightCm,
 	)
 	var i UpdateArticleRow
 	err := row.Scan(
@@ -927,6 +1037,11 @@ func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (U
 		&i.DefaultLocationID,
 		&i.ReceivingNotes,
 		&i.ShippingNotes,
+		&i.PriceCurrency,
+		&i.WeightKg,
+		&i.LengthCm,
+		&i.WidthCm,
+		&i.HeightCm,
 	)
 	return i, err
 }
@@ -7,19 +7,25 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createLocationType = `-- name: CreateLocationType :one
-INSERT INTO location_types (code, name, sort_order, is_active)
-VALUES ($1, $2, $3, $4)
-RETURNING id, code, name, sort_order, is_active, created_at, updated_at
+INSERT INTO location_types (code, name, sort_order, is_active, max_units, max_weight_kg, max_volume_m3)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, code, name, sort_order, is_active, created_at, updated_at,
+          max_units, max_weight_kg, max_volume_m3
 `
 
 type CreateLocationTypeParams struct {
-	Code      string `json:"code"`
-	Name      string `json:"name"`
-	SortOrder int32  `json:"sort_order"`
-	IsActive  bool   `json:"is_active"`
+	Code        string         `json:"code"`
+	Name        string         `json:"name"`
+	SortOrder   int32          `json:"sort_order"`
+	IsActive    bool           `json:"is_active"`
+	MaxUnits    pgtype.Numeric `json:"max_units"`
+	MaxWeightKg pgtype.Numeric `json:"max_weight_kg"`
+	MaxVolumeM3 pgtype.Numeric `json:"max_volume_m3"`
 }
 
 func (q *Queries) CreateLocationType(ctx context.Context, arg CreateLocationTypeParams) (LocationType, error) {
@@ -28,6 +34,9 @@ func (q *Queries) CreateLocationType(ctx context.Context, arg CreateLocationType
 		arg.Name,
 		arg.SortOrder,
 		arg.IsActive,
+		arg.MaxUnits,
+		arg.MaxWeightKg,
+		arg.MaxVolumeM3,
 	)
 	var i LocationType
 	err := row.Scan(
@@ -38,6 +47,9 @@ func (q *Queries) CreateLocationType(ctx context.Context, arg CreateLocationType
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
 	)
 	return i, err
 }
@@ -52,7 +64,8 @@ func (q *Queries) DeleteLocationType(ctx context.Context, id string) error {
 }
 
 const getLocationTypeByCode = `-- name: GetLocationTypeByCode :one
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE code = $1
 LIMIT 1
@@ -69,12 +82,16 @@ func (q *Queries) GetLocationTypeByCode(ctx context.Context, code string) (Locat
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
 	)
 	return i, err
 }
 
 const getLocationTypeByID = `-- name: GetLocationTypeByID :one
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE id = $1
 LIMIT 1
@@ -91,13 +108,17 @@ func (q *Queries) GetLocationTypeByID(ctx context.Context, id string) (LocationT
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
 	)
 	return i, err
 }
 
 const listLocationTypes = `-- name: ListLocationTypes :many
 
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 WHERE is_active = true
 ORDER BY sort_order ASC, code ASC
@@ -121,6 +142,9 @@ func (q *Queries) ListLocationTypes(ctx context.Context) ([]LocationType, error)
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MaxUnits,
+			&i.MaxWeightKg,
+			&i.MaxVolumeM3,
 		); err != nil {
 			return nil, err
 		}
@@ -133,7 +157,8 @@ func (q *Queries) ListLocationTypes(ctx context.Context) ([]LocationType, error)
 }
 
 const listLocationTypesAdmin = `-- name: ListLocationTypesAdmin :many
-SELECT id, code, name, sort_order, is_active, created_at, updated_at
+SELECT id, code, name, sort_order, is_active, created_at, updated_at,
+       max_units, max_weight_kg, max_volume_m3
 FROM location_types
 ORDER BY sort_order ASC, code ASC
 `
@@ -155,6 +180,9 @@ func (q *Queries) ListLocationTypesAdmin(ctx context.Context) ([]LocationType, e
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MaxUnits,
+			&i.MaxWeightKg,
+			&i.MaxVolumeM3,
 		); err != nil {
 			return nil, err
 		}
@@ -184,17 +212,24 @@ SET
     name = $3,
     sort_order = $4,
     is_active = $5,
+    max_units = $6,
+    max_weight_kg = $7,
+    max_volume_m3 = $8,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, code, name, sort_order, is_active, created_at, updated_at
+RETURNING id, code, name, sort_order, is_active, created_at, updated_at,
+          max_units, max_weight_kg, max_volume_m3
 `
 
 type UpdateLocationTypeParams struct {
-	ID        string `json:"id"`
-	Code      string `json:"code"`
-	Name      string `json:"name"`
-	SortOrder int32  `json:"sort_order"`
-	IsActive  bool   `json:"is_active"`
+	ID          string         `json:"id"`
+	Code        string         `json:"code"`
+	Name        string         `json:"name"`
+	SortOrder   int32          `json:"sort_order"`
+	IsActive    bool           `json:"is_active"`
+	MaxUnits    pgtype.Numeric `json:"max_units"`
+	MaxWeightKg pgtype.Numeric `json:"max_weight_kg"`
+	MaxVolumeM3 pgtype.Numeric `json:"max_volume_m3"`
 }
 
 func (q *Queries) UpdateLocationType(ctx context.Context, arg UpdateLocationTypeParams) (LocationType, error) {
@@ -204,6 +239,9 @@ func (q *Queries) UpdateLocationType(ctx context.Context, arg UpdateLocationType
 		arg.Name,
 		arg.SortOrder,
 		arg.IsActive,
+		arg.MaxUnits,
+		arg.MaxWeightKg,
+		arg.MaxVolumeM3,
 	)
 	var i LocationType
 	err := row.Scan(
@@ -214,6 +252,9 @@ func (q *Queries) UpdateLocationType(ctx context.Context, arg UpdateLocationType
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
 	)
 	return i, err
 }
@@ -12,19 +12,28 @@ import (
 )
 
 const createLocation = `-- name: CreateLocation :one
-INSERT INTO locations (location_code, description, zone, type, is_active, is_way_out, tenant_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+INSERT INTO locations (location_code, description, zone, type, is_active, is_way_out, tenant_id,
+                       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+          max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 `
 
 type CreateLocationParams struct {
-	LocationCode string      `json:"location_code"`
-	Description  pgtype.Text `json:"description"`
-	Zone         pgtype.Text `json:"zone"`
-	Type         string      `json:"type"`
-	IsActive     bool        `json:"is_active"`
-	IsWayOut     bool        `json:"is_way_out"`
-	TenantID     pgtype.UUID `json:"tenant_id"`
+	LocationCode string         `json:"location_code"`
+	Description  pgtype.Text    `json:"description"`
+	Zone         pgtype.Text    `json:"zone"`
+	Type         string         `json:"type"`
+	IsActive     bool           `json:"is_active"`
+	IsWayOut     bool           `json:"is_way_out"`
+	TenantID     pgtype.UUID    `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric `json:"max_volume_m3"`
+	Aisle        pgtype.Text    `json:"aisle"`
+	Rack         pgtype.Text    `json:"rack"`
+	Level        pgtype.Text    `json:"level"`
+	PickSequence pgtype.Int4    `json:"pick_sequence"`
 }
 
 type CreateLocationRow struct {
@@ -38,6 +47,13 @@ type CreateLocationRow struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	TenantID     pgtype.UUID      `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric   `json:"max_volume_m3"`
+	Aisle        pgtype.Text      `json:"aisle"`
+	Rack         pgtype.Text      `json:"rack"`
+	Level        pgtype.Text      `json:"level"`
+	PickSequence pgtype.Int4      `json:"pick_sequence"`
 }
 
 // S3.5 W2-A: tenant_id is required and provided by the controller layer.
@@ -50,6 +66,13 @@ func (q *Queries) CreateLocation(ctx context.Context, arg CreateLocationParams)
 		arg.IsActive,
 		arg.IsWayOut,
 		arg.TenantID,
+		arg.MaxUnits,
+		arg.MaxWeightKg,
+		arg.MaxVolumeM3,
+		arg.Aisle,
+		arg.Rack,
+		arg.Level,
+		arg.PickSequence,
 	)
 	var i CreateLocationRow
 	err := row.Scan(
@@ -63,6 +86,13 @@ func (q *Queries) CreateLocation(ctx context.Context, arg CreateLocationParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
+		&i.Aisle,
+		&i.Rack,
+		&i.Level,
+		&i.PickSequence,
 	)
 	return i, err
 }
@@ -83,7 +113,8 @@ func (q *Queries) DeleteLocationForTenant(ctx context.Context, arg DeleteLocatio
 }
 
 const getLocationByIDForTenant = `-- name: GetLocationByIDForTenant :one
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE id = $1 AND tenant_id = $2
 LIMIT 1
@@ -105,6 +136,13 @@ type GetLocationByIDForTenantRow struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	TenantID     pgtype.UUID      `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric   `json:"max_volume_m3"`
+	Aisle        pgtype.Text      `json:"aisle"`
+	Rack         pgtype.Text      `json:"rack"`
+	Level        pgtype.Text      `json:"level"`
+	PickSequence pgtype.Int4      `json:"pick_sequence"`
 }
 
 // S3.5 W2-A: tenant_id guard prevents cross-tenant id lookup.
@@ -122,12 +160,20 @@ func (q *Queries) GetLocationByIDForTenant(ctx context.Context, arg GetLocationB
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
+		&i.Aisle,
+		&i.Rack,
+		&i.Level,
+		&i.PickSequence,
 	)
 	return i, err
 }
 
 const getLocationByLocationCodeForTenant = `-- name: GetLocationByLocationCodeForTenant :one
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE location_code = $1 AND tenant_id = $2
 LIMIT 1
@@ -149,6 +195,13 @@ type GetLocationByLocationCodeForTenantRow struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	TenantID     pgtype.UUID      `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric   `json:"max_volume_m3"`
+	Aisle        pgtype.Text      `json:"aisle"`
+	Rack         pgtype.Text      `json:"rack"`
+	Level        pgtype.Text      `json:"level"`
+	PickSequence pgtype.Int4      `json:"pick_sequence"`
 }
 
 // S3.5 W2-A: tenant_id guard. Used as fallback by ID lookup when caller passed a code.
@@ -166,13 +219,21 @@ func (q *Queries) GetLocationByLocationCodeForTenant(ctx context.Context, arg Ge
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
+		&i.Aisle,
+		&i.Rack,
+		&i.Level,
+		&i.PickSequence,
 	)
 	return i, err
 }
 
 const listLocationsByTenant = `-- name: ListLocationsByTenant :many
 
-SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+SELECT id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+       max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 FROM locations
 WHERE tenant_id = $1
 ORDER BY created_at ASC
@@ -189,6 +250,13 @@ type ListLocationsByTenantRow struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	TenantID     pgtype.UUID      `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric   `json:"max_volume_m3"`
+	Aisle        pgtype.Text      `json:"aisle"`
+	Rack         pgtype.Text      `json:"rack"`
+	Level        pgtype.Text      `json:"level"`
+	PickSequence pgtype.Int4      `json:"pick_sequence"`
 }
 
 // Locations CRUD for sqlc
@@ -215,6 +283,13 @@ func (q *Queries) ListLocationsByTenant(ctx context.Context, tenantID pgtype.UUI
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TenantID,
+			&i.MaxUnits,
+			&i.MaxWeightKg,
+			&i.MaxVolumeM3,
+			&i.Aisle,
+			&i.Rack,
+			&i.Level,
+			&i.PickSequence,
 		); err != nil {
 			return nil, err
 		}
@@ -254,20 +329,35 @@ SET
     type = $5,
     is_active = $6,
     is_way_out = $7,
+    max_units = $9,
+    max_weight_kg = $10,
+    max_volume_m3 = $11,
+    aisle = $12,
+    rack = $13,
+    level = $14,
+    pick_sequence = $15,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND tenant_id = $8
-RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id
+RETURNING id, location_code, description, zone, type, is_active, is_way_out, created_at, updated_at, tenant_id,
+          max_units, max_weight_kg, max_volume_m3, aisle, rack, level, pick_sequence
 `
 
 type UpdateLocationForTenantParams struct {
-	ID           string      `json:"id"`
-	LocationCode string      `json:"location_code"`
-	Description  pgtype.Text `json:"description"`
-	Zone         pgtype.Text `json:"zone"`
-	Type         string      `json:"type"`
-	IsActive     bool        `json:"is_active"`
-	IsWayOut     bool        `json:"is_way_out"`
-	TenantID     pgtype.UUID `json:"tenant_id"`
+	ID           string         `json:"id"`
+	LocationCode string         `json:"location_code"`
+	Description  pgtype.Text    `json:"description"`
+	Zone         pgtype.Text    `json:"zone"`
+	Type         string         `json:"type"`
+	IsActive     bool           `json:"is_active"`
+	IsWayOut     bool           `json:"is_way_out"`
+	TenantID     pgtype.UUID    `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric `json:"max_volume_m3"`
+	Aisle        pgtype.Text    `json:"aisle"`
+	Rack         pgtype.Text    `json:"rack"`
+	Level        pgtype.Text    `json:"level"`
+	PickSequence pgtype.Int4    `json:"pick_sequence"`
 }
 
 type UpdateLocationForTenantRow struct {
@@ -281,6 +371,13 @@ type UpdateLocationForTenantRow struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	TenantID     pgtype.UUID      `json:"tenant_id"`
+	MaxUnits     pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg  pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3  pgtype.Numeric   `json:"max_volume_m3"`
+	Aisle        pgtype.Text      `json:"aisle"`
+	Rack         pgtype.Text      `json:"rack"`
+	Level        pgtype.Text      `json:"level"`
+	PickSequence pgtype.Int4      `json:"pick_sequence"`
 }
 
 // S3.5 W2-A: tenant_id guard prevents cross-tenant update.
@@ -294,6 +391,13 @@ func (q *Queries) UpdateLocationForTenant(ctx context.Context, arg UpdateLocatio
 		arg.IsActive,
 		arg.IsWayOut,
 		arg.TenantID,
+		arg.MaxUnits,
+		arg.MaxWeightKg,
+		arg.MaxVolumeM3,
+		arg.Aisle,
+		arg.Rack,
+		arg.Level,
+		arg.PickSequence,
 	)
 	var i UpdateLocationForTenantRow
 	err := row.Scan(
@@ -307,6 +411,13 @@ func (q *Queries) UpdateLocationForTenant(ctx context.Context, arg UpdateLocatio
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.MaxUnits,
+		&i.MaxWeightKg,
+		&i.MaxVolumeM3,
+		&i.Aisle,
+		&i.Rack,
+		&i.Level,
+		&i.PickSequence,
 	)
 	return i, err
 }
@@ -68,6 +68,11 @@ type Article struct {
 	ReceivingNotes     pgtype.Text    `json:"receiving_notes"`
 	ShippingNotes      pgtype.Text    `json:"shipping_notes"`
 	TenantID           pgtype.UUID    `json:"tenant_id"`
+	PriceCurrency      pgtype.Text    `json:"price_currency"`
+	WeightKg           pgtype.Numeric `json:"weight_kg"`
+	LengthCm           pgtype.Numeric `json:"length_cm"`
+	WidthCm            pgtype.Numeric `json:"width_cm"`
+	HeightCm           pgtype.Numeric `json:"height_cm"`
 }
 
 type ArticleSupplier struct {
@@ -246,19 +251,30 @@ type Location struct {
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 	// When true, location is used as dock, loading bay, or exit point in WMS.
-	IsWayOut bool        `json:"is_way_out"`
-	TenantID pgtype.UUID `json:"tenant_id"`
+	IsWayOut    bool           `json:"is_way_out"`
+	TenantID    pgtype.UUID    `json:"tenant_id"`
+	MaxUnits    pgtype.Numeric `json:"max_units"`
+	MaxWeightKg pgtype.Numeric `json:"max_weight_kg"`
+	MaxVolumeM3 pgtype.Numeric `json:"max_volume_m3"`
+	// Pick path (migration 000045): explicit walk order, else zone/aisle/rack/level.
+	Aisle        pgtype.Text `json:"aisle"`
+	Rack         pgtype.Text `json:"rack"`
+	Level        pgtype.Text `json:"level"`
+	PickSequence pgtype.Int4 `json:"pick_sequence"`
 }
 
 // Location type catalog (Pallet, Shelf, Bin, etc.); used by locations.type as code reference
 type LocationType struct {
-	ID        string           `json:"id"`
-	Code      string           `json:"code"`
-	Name      string           `json:"name"`
-	SortOrder int32            `json:"sort_order"`
-	IsActive  bool             `json:"is_active"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ID          string           `json:"id"`
+	Code        string           `json:"code"`
+	Name        string           `json:"name"`
+	SortOrder   int32            `json:"sort_order"`
+	IsActive    bool             `json:"is_active"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	MaxUnits    pgtype.Numeric   `json:"max_units"`
+	MaxWeightKg pgtype.Numeric   `json:"max_weight_kg"`
+	MaxVolumeM3 pgtype.Numeric   `json:"max_volume_m3"`
 }
 
 type Lot struct {
@@ -386,6 +402,7 @@ type PurchaseOrderItem struct {
 	Discrepancy     pgtype.Numeric `json:"discrepancy"`
 	Notes           pgtype.Text    `json:"notes"`
 	CreatedAt       time.Time      `json:"created_at"`
+	Currency        pgtype.Text    `json:"currency"`
 }
 
 type ReceivingTask struct {
@@ -448,6 +465,7 @@ type SalesOrderItem struct {
 	UnitPrice    pgtype.Numeric `json:"unit_price"`
 	Notes        pgtype.Text    `json:"notes"`
 	CreatedAt    time.Time      `json:"created_at"`
+	Currency     pgtype.Text    `json:"currency"`
 }
 
 type Serial struct {
@@ -545,6 +563,11 @@ type StockSetting struct {
 	AutoCreateMaterialRequest bool           `json:"auto_create_material_request"`
 	PartialDeliveryPolicy     string         `json:"partial_delivery_policy"`
 	UpdatedAt                 time.Time      `json:"updated_at"`
+	BaseCurrency              string         `json:"base_currency"`
+	RequirePacking            bool           `json:"require_packing"`
+	RequireLotRelease         bool           `json:"require_lot_release"`
+	LotNumberPattern          pgtype.Text    `json:"lot_number_pattern"`
+	SerialNumberPattern       pgtype.Text    `json:"serial_number_pattern"`
 }
 
 type StockTransfer struct {
@@ -564,13 +587,18 @@ type StockTransfer struct {
 }
 
 type StockTransferLine struct {
-	ID              string           `json:"id"`
-	StockTransferID string           `json:"stock_transfer_id"`
-	Sku             string           `json:"sku"`
-	Quantity        pgtype.Numeric   `json:"quantity"`
-	Presentation    pgtype.Text      `json:"presentation"`
-	LineStatus      string           `json:"line_status"`
-	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	ID                 string           `json:"id"`
+	StockTransferID    string           `json:"stock_transfer_id"`
+	Sku                string           `json:"sku"`
+	Quantity           pgtype.Numeric   `json:"quantity"`
+	Presentation       pgtype.Text      `json:"presentation"`
+	LineStatus         string           `json:"line_status"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
+	ShippedQty         pgtype.Numeric   `json:"shipped_qty"`
+	ReceivedQty        pgtype.Numeric   `json:"received_qty"`
+	DiscrepancyReason  pgtype.Text      `json:"discrepancy_reason"`
+	ReceivedAt         pgtype.Timestamp `json:"received_at"`
+	PresentationFactor pgtype.Numeric   `json:"presentation_factor"`
 }
 
 type StripeWebhookEvent struct {
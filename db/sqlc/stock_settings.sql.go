@@ -16,7 +16,8 @@ const getStockSettings = `-- name: GetStockSettings :one
 SELECT tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy, updated_at
+  partial_delivery_policy, updated_at, base_currency, require_packing,
+  require_lot_release, lot_number_pattern, serial_number_pattern
 FROM stock_settings WHERE tenant_id = $1
 `
 
@@ -38,6 +39,11 @@ func (q *Queries) GetStockSettings(ctx context.Context, tenantID pgtype.UUID) (S
 		&i.AutoCreateMaterialRequest,
 		&i.PartialDeliveryPolicy,
 		&i.UpdatedAt,
+		&i.BaseCurrency,
+		&i.RequirePacking,
+		&i.RequireLotRelease,
+		&i.LotNumberPattern,
+		&i.SerialNumberPattern,
 	)
 	return i, err
 }
@@ -47,8 +53,9 @@ INSERT INTO stock_settings (
   tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+  partial_delivery_policy, base_currency, require_packing, require_lot_release,
+  lot_number_pattern, serial_number_pattern
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 ON CONFLICT (tenant_id) DO UPDATE SET
   valuation_method = EXCLUDED.valuation_method,
   pick_batch_based_on = EXCLUDED.pick_batch_based_on,
@@ -60,11 +67,17 @@ ON CONFLICT (tenant_id) DO UPDATE SET
   expiry_alert_days = EXCLUDED.expiry_alert_days,
   auto_create_material_request = EXCLUDED.auto_create_material_request,
   partial_delivery_policy = EXCLUDED.partial_delivery_policy,
+  base_currency = EXCLUDED.base_currency,
+  require_packing = EXCLUDED.require_packing,
+  require_lot_release = EXCLUDED.require_lot_release,
+  lot_number_pattern = EXCLUDED.lot_number_pattern,
+  serial_number_pattern = EXCLUDED.serial_number_pattern,
   updated_at = now()
 RETURNING tenant_id, valuation_method, pick_batch_based_on, over_receipt_allowance_pct,
   over_delivery_allowance_pct, over_picking_allowance_pct, auto_reserve_stock,
   allow_partial_reservation, expiry_alert_days, auto_create_material_request,
-  partial_delivery_policy, updated_at
+  partial_delivery_policy, updated_at, base_currency, require_packing,
+  require_lot_release, lot_number_pattern, serial_number_pattern
 `
 
 type UpsertStockSettingsParams struct {
@@ -79,6 +92,11 @@ type UpsertStockSettingsParams struct {
 	ExpiryAlertDays           int32          `json:"expiry_alert_days"`
 	AutoCreateMaterialRequest bool           `json:"auto_create_material_request"`
 	PartialDeliveryPolicy     string         `json:"partial_delivery_policy"`
+	BaseCurrency              string         `json:"base_currency"`
+	RequirePacking            bool           `json:"require_packing"`
+	RequireLotRelease         bool           `json:"require_lot_release"`
+	LotNumberPattern          pgtype.Text    `json:"lot_number_pattern"`
+	SerialNumberPattern       pgtype.Text    `json:"serial_number_pattern"`
 }
 
 func (q *Queries) UpsertStockSettings(ctx context.Context, arg UpsertStockSettingsParams) (StockSetting, error) {
@@ -94,6 +112,11 @@ func (q *Queries) UpsertStockSettings(ctx context.Context, arg UpsertStockSettin
 		arg.ExpiryAlertDays,
 		arg.AutoCreateMaterialRequest,
 		arg.PartialDeliveryPolicy,
+		arg.BaseCurrency,
+		arg.RequirePacking,
+		arg.RequireLotRelease,
+		arg.LotNumberPattern,
+		arg.SerialNumberPattern,
 	)
 	var i StockSetting
 	err := row.Scan(
@@ -109,6 +132,11 @@ func (q *Queries) UpsertStockSettings(ctx context.Context, arg UpsertStockSettin
 		&i.AutoCreateMaterialRequest,
 		&i.PartialDeliveryPolicy,
 		&i.UpdatedAt,
+		&i.BaseCurrency,
+		&i.RequirePacking,
+		&i.RequireLotRelease,
+		&i.LotNumberPattern,
+		&i.SerialNumberPattern,
 	)
 	return i, err
 }
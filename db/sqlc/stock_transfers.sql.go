@@ -73,17 +73,18 @@ func (q *Queries) CreateStockTransfer(ctx context.Context, arg CreateStockTransf
 }
 
 const createStockTransferLine = `-- name: CreateStockTransferLine :one
-INSERT INTO stock_transfer_lines (stock_transfer_id, sku, quantity, presentation, line_status)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+INSERT INTO stock_transfer_lines (stock_transfer_id, sku, quantity, presentation, line_status, presentation_factor)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 `
 
 type CreateStockTransferLineParams struct {
-	StockTransferID string         `json:"stock_transfer_id"`
-	Sku             string         `json:"sku"`
-	Quantity        pgtype.Numeric `json:"quantity"`
-	Presentation    pgtype.Text    `json:"presentation"`
-	LineStatus      string         `json:"line_status"`
+	StockTransferID    string         `json:"stock_transfer_id"`
+	Sku                string         `json:"sku"`
+	Quantity           pgtype.Numeric `json:"quantity"`
+	Presentation       pgtype.Text    `json:"presentation"`
+	LineStatus         string         `json:"line_status"`
+	PresentationFactor pgtype.Numeric `json:"presentation_factor"`
 }
 
 func (q *Queries) CreateStockTransferLine(ctx context.Context, arg CreateStockTransferLineParams) (StockTransferLine, error) {
@@ -93,6 +94,7 @@ func (q *Queries) CreateStockTransferLine(ctx context.Context, arg CreateStockTr
 		arg.Quantity,
 		arg.Presentation,
 		arg.LineStatus,
+		arg.PresentationFactor,
 	)
 	var i StockTransferLine
 	err := row.Scan(
@@ -103,6 +105,11 @@ func (q *Queries) CreateStockTransferLine(ctx context.Context, arg CreateStockTr
 		&i.Presentation,
 		&i.LineStatus,
 		&i.CreatedAt,
+		&i.ShippedQty,
+		&i.ReceivedQty,
+		&i.DiscrepancyReason,
+		&i.ReceivedAt,
+		&i.PresentationFactor,
 	)
 	return i, err
 }
@@ -219,7 +226,7 @@ func (q *Queries) GetStockTransferByTransferNumber(ctx context.Context, transfer
 }
 
 const getStockTransferLineByID = `-- name: GetStockTransferLineByID :one
-SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 FROM stock_transfer_lines
 WHERE id = $1
 LIMIT 1
@@ -236,12 +243,17 @@ func (q *Queries) GetStockTransferLineByID(ctx context.Context, id string) (Stoc
 		&i.Presentation,
 		&i.LineStatus,
 		&i.CreatedAt,
+		&i.ShippedQty,
+		&i.ReceivedQty,
+		&i.DiscrepancyReason,
+		&i.ReceivedAt,
+		&i.PresentationFactor,
 	)
 	return i, err
 }
 
 const listStockTransferLinesByTransferID = `-- name: ListStockTransferLinesByTransferID :many
-SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+SELECT id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 FROM stock_transfer_lines
 WHERE stock_transfer_id = $1
 ORDER BY created_at ASC
@@ -264,6 +276,11 @@ func (q *Queries) ListStockTransferLinesByTransferID(ctx context.Context, stockT
 			&i.Presentation,
 			&i.LineStatus,
 			&i.CreatedAt,
+			&i.ShippedQty,
+			&i.ReceivedQty,
+			&i.DiscrepancyReason,
+			&i.ReceivedAt,
+			&i.PresentationFactor,
 		); err != nil {
 			return nil, err
 		}
@@ -449,16 +466,17 @@ func (q *Queries) UpdateStockTransfer(ctx context.Context, arg UpdateStockTransf
 
 const updateStockTransferLine = `-- name: UpdateStockTransferLine :one
 UPDATE stock_transfer_lines
-SET quantity = $2, presentation = $3, line_status = $4
+SET quantity = $2, presentation = $3, line_status = $4, presentation_factor = $5
 WHERE id = $1
-RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at
+RETURNING id, stock_transfer_id, sku, quantity, presentation, line_status, created_at, shipped_qty, received_qty, discrepancy_reason, received_at, presentation_factor
 `
 
 type UpdateStockTransferLineParams struct {
-	ID           string         `json:"id"`
-	Quantity     pgtype.Numeric `json:"quantity"`
-	Presentation pgtype.Text    `json:"presentation"`
-	LineStatus   string         `json:"line_status"`
+	ID                 string         `json:"id"`
+	Quantity           pgtype.Numeric `json:"quantity"`
+	Presentation       pgtype.Text    `json:"presentation"`
+	LineStatus         string         `json:"line_status"`
+	PresentationFactor pgtype.Numeric `json:"presentation_factor"`
 }
 
 func (q *Queries) UpdateStockTransferLine(ctx context.Context, arg UpdateStockTransferLineParams) (StockTransferLine, error) {
@@ -467,6 +485,7 @@ func (q *Queries) UpdateStockTransferLine(ctx context.Context, arg UpdateStockTr
 		arg.Quantity,
 		arg.Presentation,
 		arg.LineStatus,
+		arg.PresentationFactor,
 	)
 	var i StockTransferLine
 	err := row.Scan(
@@ -477,6 +496,11 @@ func (q *Queries) UpdateStockTransferLine(ctx context.Context, arg UpdateStockTr
 		&i.Presentation,
 		&i.LineStatus,
 		&i.CreatedAt,
+		&i.ShippedQty,
+		&i.ReceivedQty,
+		&i.DiscrepancyReason,
+		&i.ReceivedAt,
+		&i.PresentationFactor,
 	)
 	return i, err
 }
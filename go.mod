@@ -3,6 +3,7 @@ module github.com/eflowcr/eSTOCK_backend
 go 1.25.0
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/gin-gonic/gin v1.10.1
 	github.com/go-playground/validator/v10 v10.30.1
 	github.com/golang-jwt/jwt/v4 v4.5.2
@@ -36,6 +36,13 @@ type Article struct {
 	DefaultLocationID   *string  `gorm:"column:default_location_id" json:"default_location_id,omitempty"`
 	ReceivingNotes      *string  `gorm:"column:receiving_notes" json:"receiving_notes,omitempty"`
 	ShippingNotes       *string  `gorm:"column:shipping_notes" json:"shipping_notes,omitempty"`
+	// PriceCurrency is the ISO 4217 currency of UnitPrice; nil means the tenant base currency.
+	PriceCurrency *string `gorm:"column:price_currency" json:"price_currency,omitempty"`
+	// Unit weight and dimensions, used for location weight/volume capacity.
+	WeightKg *float64 `gorm:"column:weight_kg" json:"weight_kg,omitempty"`
+	LengthCm *float64 `gorm:"column:length_cm" json:"length_cm,omitempty"`
+	WidthCm  *float64 `gorm:"column:width_cm" json:"width_cm,omitempty"`
+	HeightCm *float64 `gorm:"column:height_cm" json:"height_cm,omitempty"`
 }
 
 func (Article) TableName() string {
@@ -0,0 +1,27 @@
+package database
+
+import "time"
+
+// Article barcode code types.
+const (
+	ArticleBarcodeGTIN     = "gtin"
+	ArticleBarcodeInternal = "internal"
+)
+
+// ArticleBarcode is an alternate scannable code for an article (migration 000042). GTINs are
+// stored normalized to 14 digits; PackQuantity is the number of article units one scan of
+// the code represents.
+type ArticleBarcode struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID     string    `gorm:"column:tenant_id;type:uuid;not null" json:"-"`
+	ArticleID    string    `gorm:"column:article_id" json:"article_id"`
+	SKU          string    `gorm:"->;column:sku" json:"sku"`
+	Code         string    `gorm:"column:code" json:"code"`
+	CodeType     string    `gorm:"column:code_type" json:"code_type"`
+	PackQuantity float64   `gorm:"column:pack_quantity" json:"pack_quantity"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (ArticleBarcode) TableName() string {
+	return "article_barcodes"
+}
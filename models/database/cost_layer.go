@@ -0,0 +1,48 @@
+package database
+
+import "time"
+
+// Valuation methods accepted in stock_settings.valuation_method.
+const (
+	ValuationAVCO = "avco"
+	ValuationFIFO = "fifo"
+	ValuationLIFO = "lifo"
+)
+
+// CostLayer is the cost of one inbound quantity of a SKU (receiving, positive adjustment,
+// initial stock or the opening balance created by migration 000038). RemainingQty is what
+// is still on hand; outbound movements draw it down according to the tenant valuation method.
+type CostLayer struct {
+	ID            string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID      string    `gorm:"column:tenant_id;type:uuid" json:"tenant_id"`
+	SKU           string    `gorm:"column:sku" json:"sku"`
+	MovementID    *string   `gorm:"column:movement_id" json:"movement_id,omitempty"`
+	ReferenceType *string   `gorm:"column:reference_type" json:"reference_type,omitempty"`
+	ReferenceID   *string   `gorm:"column:reference_id" json:"reference_id,omitempty"`
+	OriginalQty   float64   `gorm:"column:original_qty" json:"original_qty"`
+	RemainingQty  float64   `gorm:"column:remaining_qty" json:"remaining_qty"`
+	UnitCost      float64   `gorm:"column:unit_cost" json:"unit_cost"`
+	ReceivedAt    time.Time `gorm:"column:received_at" json:"received_at"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (CostLayer) TableName() string {
+	return "cost_layers"
+}
+
+// CostLayerConsumption records the quantity an outbound movement took from a layer.
+// LayerID is nil for stock that left without a layer behind it (costed at the article price).
+type CostLayerConsumption struct {
+	ID         string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID   string    `gorm:"column:tenant_id;type:uuid" json:"tenant_id"`
+	MovementID string    `gorm:"column:movement_id" json:"movement_id"`
+	LayerID    *string   `gorm:"column:layer_id" json:"layer_id,omitempty"`
+	SKU        string    `gorm:"column:sku" json:"sku"`
+	Quantity   float64   `gorm:"column:quantity" json:"quantity"`
+	UnitCost   float64   `gorm:"column:unit_cost" json:"unit_cost"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (CostLayerConsumption) TableName() string {
+	return "cost_layer_consumptions"
+}
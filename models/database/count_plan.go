@@ -0,0 +1,34 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CountPlan defines what a cycle count covers and how it is executed (migration 000037).
+// ScopeType selects how ScopeValues is interpreted: zone codes, location codes,
+// category IDs or ABC classes ("A", "B", "C").
+// RecountThresholdPct / RecountThresholdQty are optional; when either is exceeded by
+// the first count of a line, the line is sent back for a blind recount.
+type CountPlan struct {
+	ID                  string         `gorm:"column:id;primaryKey" json:"id"`
+	TenantID            string         `gorm:"column:tenant_id" json:"-"`
+	Name                string         `gorm:"column:name" json:"name"`
+	Description         *string        `gorm:"column:description" json:"description,omitempty"`
+	ScopeType           string         `gorm:"column:scope_type" json:"scope_type"`
+	ScopeValues         pq.StringArray `gorm:"column:scope_values;type:text[]" json:"scope_values"`
+	Blind               bool           `gorm:"column:blind" json:"blind"`
+	RecountThresholdPct *float64       `gorm:"column:recount_threshold_pct" json:"recount_threshold_pct,omitempty"`
+	RecountThresholdQty *float64       `gorm:"column:recount_threshold_qty" json:"recount_threshold_qty,omitempty"`
+	ReasonCode          *string        `gorm:"column:reason_code" json:"reason_code,omitempty"`
+	IsActive            bool           `gorm:"column:is_active" json:"is_active"`
+	CreatedBy           *string        `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt           *time.Time     `gorm:"column:deleted_at" json:"deleted_at,omitempty"`
+}
+
+func (CountPlan) TableName() string {
+	return "count_plans"
+}
@@ -0,0 +1,32 @@
+package database
+
+import "time"
+
+// CountTask is one generated cycle count run (open→in_progress→pending_approval→approved|cancelled).
+// Blind, the recount thresholds and the reason code are copied from the plan at
+// generation time so editing the plan does not change tasks already in the field.
+type CountTask struct {
+	ID                  string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID            string     `gorm:"column:tenant_id" json:"-"`
+	TaskNumber          string     `gorm:"column:task_number" json:"task_number"`
+	CountPlanID         *string    `gorm:"column:count_plan_id" json:"count_plan_id,omitempty"`
+	Status              string     `gorm:"column:status" json:"status"`
+	Blind               bool       `gorm:"column:blind" json:"blind"`
+	RecountThresholdPct *float64   `gorm:"column:recount_threshold_pct" json:"recount_threshold_pct,omitempty"`
+	RecountThresholdQty *float64   `gorm:"column:recount_threshold_qty" json:"recount_threshold_qty,omitempty"`
+	ReasonCode          *string    `gorm:"column:reason_code" json:"reason_code,omitempty"`
+	AssignedTo          *string    `gorm:"column:assigned_to" json:"assigned_to,omitempty"`
+	Notes               *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy           *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	StartedAt           *time.Time `gorm:"column:started_at" json:"started_at,omitempty"`
+	SubmittedAt         *time.Time `gorm:"column:submitted_at" json:"submitted_at,omitempty"`
+	ApprovedBy          *string    `gorm:"column:approved_by" json:"approved_by,omitempty"`
+	ApprovedAt          *time.Time `gorm:"column:approved_at" json:"approved_at,omitempty"`
+	CancelledAt         *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (CountTask) TableName() string {
+	return "count_tasks"
+}
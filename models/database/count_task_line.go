@@ -0,0 +1,42 @@
+package database
+
+import "time"
+
+// CountTaskLine is one SKU+location to count inside a CountTask.
+// ExpectedQty is the inventory snapshot taken when the task was generated.
+// Line status: pending → counted | recount_required → recounted.
+// AdjustmentID is set once the variance has been posted as a count_reconcile adjustment.
+type CountTaskLine struct {
+	ID           string     `gorm:"column:id;primaryKey" json:"id"`
+	CountTaskID  string     `gorm:"column:count_task_id" json:"count_task_id"`
+	SKU          string     `gorm:"column:sku" json:"sku"`
+	Location     string     `gorm:"column:location" json:"location"`
+	ExpectedQty  float64    `gorm:"column:expected_qty" json:"expected_qty"`
+	CountedQty   *float64   `gorm:"column:counted_qty" json:"counted_qty,omitempty"`
+	RecountQty   *float64   `gorm:"column:recount_qty" json:"recount_qty,omitempty"`
+	Status       string     `gorm:"column:status" json:"status"`
+	CountedBy    *string    `gorm:"column:counted_by" json:"counted_by,omitempty"`
+	CountedAt    *time.Time `gorm:"column:counted_at" json:"counted_at,omitempty"`
+	RecountedBy  *string    `gorm:"column:recounted_by" json:"recounted_by,omitempty"`
+	RecountedAt  *time.Time `gorm:"column:recounted_at" json:"recounted_at,omitempty"`
+	Notes        *string    `gorm:"column:notes" json:"notes,omitempty"`
+	AdjustmentID *string    `gorm:"column:adjustment_id" json:"adjustment_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (CountTaskLine) TableName() string {
+	return "count_task_lines"
+}
+
+// FinalQty returns the quantity that will be reconciled: the recount when present,
+// otherwise the first count. ok is false while the line has not been counted yet.
+func (l CountTaskLine) FinalQty() (qty float64, ok bool) {
+	if l.RecountQty != nil {
+		return *l.RecountQty, true
+	}
+	if l.CountedQty != nil {
+		return *l.CountedQty, true
+	}
+	return 0, false
+}
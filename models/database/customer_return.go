@@ -0,0 +1,90 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Customer return statuses. A return is authorized when created, partially_received while
+// some lines still have quantity to inspect and received once every line is fully inspected.
+// Only an authorized return (nothing inspected yet) can be cancelled.
+const (
+	CustomerReturnAuthorized        = "authorized"
+	CustomerReturnPartiallyReceived = "partially_received"
+	CustomerReturnReceived          = "received"
+	CustomerReturnCancelled         = "cancelled"
+)
+
+// Inspection dispositions of returned goods.
+const (
+	ReturnDispositionRestock    = "restock"
+	ReturnDispositionQuarantine = "quarantine"
+	ReturnDispositionScrap      = "scrap"
+)
+
+// Inventory movement types posted by return inspections (reference_type "customer_return").
+const (
+	MovementReturnRestock    = "return_restock"
+	MovementReturnQuarantine = "return_quarantine"
+	MovementReturnScrap      = "return_scrap"
+)
+
+// CustomerReturn is a return authorization (RMA) against a sales order and, when the customer
+// returns goods of a specific delivery, its delivery note.
+type CustomerReturn struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID       string     `gorm:"column:tenant_id" json:"-"`
+	ReturnNumber   string     `gorm:"column:return_number" json:"return_number"`
+	SalesOrderID   string     `gorm:"column:sales_order_id" json:"sales_order_id"`
+	DeliveryNoteID *string    `gorm:"column:delivery_note_id" json:"delivery_note_id,omitempty"`
+	CustomerID     *string    `gorm:"column:customer_id" json:"customer_id,omitempty"`
+	Status         string     `gorm:"column:status" json:"status"`
+	Notes          *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy      *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	ReceivedAt     *time.Time `gorm:"column:received_at" json:"received_at,omitempty"`
+	CancelledAt    *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (CustomerReturn) TableName() string {
+	return "customer_returns"
+}
+
+// CustomerReturnLine is a SKU (and lot / serials) the customer sends back, with the reason.
+// ReceivedQty is how much of it has been inspected.
+type CustomerReturnLine struct {
+	ID            string         `gorm:"column:id;primaryKey" json:"id"`
+	ReturnID      string         `gorm:"column:return_id" json:"return_id"`
+	ArticleSKU    string         `gorm:"column:article_sku" json:"article_sku"`
+	LotNumber     *string        `gorm:"column:lot_number" json:"lot_number,omitempty"`
+	SerialNumbers pq.StringArray `gorm:"column:serial_numbers;type:text[]" json:"serial_numbers,omitempty"`
+	Qty           float64        `gorm:"column:qty" json:"qty"`
+	ReasonCode    string         `gorm:"column:reason_code" json:"reason_code"`
+	ReasonNotes   *string        `gorm:"column:reason_notes" json:"reason_notes,omitempty"`
+	ReceivedQty   float64        `gorm:"column:received_qty" json:"received_qty"`
+}
+
+func (CustomerReturnLine) TableName() string {
+	return "customer_return_lines"
+}
+
+// CustomerReturnInspection is the outcome of inspecting a quantity of a return line.
+// MovementID is the inventory movement it posted.
+type CustomerReturnInspection struct {
+	ID            string         `gorm:"column:id;primaryKey" json:"id"`
+	ReturnLineID  string         `gorm:"column:return_line_id" json:"return_line_id"`
+	Qty           float64        `gorm:"column:qty" json:"qty"`
+	Disposition   string         `gorm:"column:disposition" json:"disposition"`
+	Location      string         `gorm:"column:location" json:"location"`
+	SerialNumbers pq.StringArray `gorm:"column:serial_numbers;type:text[]" json:"serial_numbers,omitempty"`
+	Notes         *string        `gorm:"column:notes" json:"notes,omitempty"`
+	MovementID    *string        `gorm:"column:movement_id" json:"movement_id,omitempty"`
+	InspectedBy   *string        `gorm:"column:inspected_by" json:"inspected_by,omitempty"`
+	InspectedAt   time.Time      `gorm:"column:inspected_at" json:"inspected_at"`
+}
+
+func (CustomerReturnInspection) TableName() string {
+	return "customer_return_inspections"
+}
@@ -1,9 +1,14 @@
 package database
 
-import "time"
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
 
 // DeliveryNote represents a delivery note generated when picking completes against a SalesOrder.
-// PDF generation is async (goroutine); PdfURL is set after generation.
+// PDF generation is async (goroutine); PdfStorageKey (document storage key) and PdfSHA256 are set after generation.
+// DeliveredAt, SignedBy (receiver name) and the POD* fields are set when the proof of delivery is recorded.
 type DeliveryNote struct {
 	ID             string     `gorm:"column:id;primaryKey" json:"id"`
 	TenantID       string     `gorm:"column:tenant_id" json:"tenant_id"`
@@ -12,14 +17,32 @@ type DeliveryNote struct {
 	PickingTaskID  *string    `gorm:"column:picking_task_id" json:"picking_task_id,omitempty"`
 	CustomerID     string     `gorm:"column:customer_id" json:"customer_id"`
 	TotalItems     int        `gorm:"column:total_items" json:"total_items"`
-	PdfURL         *string    `gorm:"column:pdf_url" json:"pdf_url,omitempty"`
+	PdfStorageKey  *string    `gorm:"column:pdf_storage_key" json:"-"`
+	PdfSHA256      *string    `gorm:"column:pdf_sha256" json:"pdf_sha256,omitempty"`
 	PdfGeneratedAt *time.Time `gorm:"column:pdf_generated_at" json:"pdf_generated_at,omitempty"`
 	DeliveredAt    *time.Time `gorm:"column:delivered_at" json:"delivered_at,omitempty"`
 	SignedBy       *string    `gorm:"column:signed_by" json:"signed_by,omitempty"`
-	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	// PODSignaturePath and PODPhotoPaths are storage keys of the signature and delivery photos.
+	PODSignaturePath *string        `gorm:"column:pod_signature_path" json:"pod_signature_path,omitempty"`
+	PODPhotoPaths    pq.StringArray `gorm:"column:pod_photo_paths;type:text[]" json:"pod_photo_paths,omitempty"`
+	PODGPS           *string        `gorm:"column:pod_gps" json:"pod_gps,omitempty"`
+	PODNotes         *string        `gorm:"column:pod_notes" json:"pod_notes,omitempty"`
+	PODRecordedBy    *string        `gorm:"column:pod_recorded_by" json:"pod_recorded_by,omitempty"`
+	CreatedAt        time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 }
 
 func (DeliveryNote) TableName() string {
 	return "delivery_notes"
 }
+
+// ProofOfDelivery is what RecordPOD stores on a delivery note (not a table).
+type ProofOfDelivery struct {
+	DeliveredAt   time.Time
+	ReceiverName  string
+	SignaturePath *string
+	PhotoPaths    []string
+	GPS           *string
+	Notes         *string
+	RecordedBy    string
+}
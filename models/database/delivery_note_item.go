@@ -16,6 +16,12 @@ type DeliveryNoteItem struct {
 	LotNumbers     pq.StringArray `gorm:"column:lot_numbers;type:text[]" json:"lot_numbers,omitempty"`
 	Notes          *string        `gorm:"column:notes" json:"notes,omitempty"`
 	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	// PackageID is the package the item was packed in (packing stage only).
+	PackageID *string `gorm:"column:package_id" json:"package_id,omitempty"`
+	// Presentation and PresentationFactor carry the presentation the order line was entered in;
+	// Qty is in base units.
+	Presentation       *string  `gorm:"column:presentation" json:"presentation,omitempty"`
+	PresentationFactor *float64 `gorm:"column:presentation_factor" json:"presentation_factor,omitempty"`
 }
 
 func (DeliveryNoteItem) TableName() string {
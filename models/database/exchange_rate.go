@@ -0,0 +1,24 @@
+package database
+
+import "time"
+
+// DefaultCurrency is the base currency of tenants that never configured one
+// (stock_settings.base_currency column default).
+const DefaultCurrency = "CRC"
+
+// ExchangeRate is a dated conversion rate entered by the tenant: 1 FromCurrency = Rate ToCurrency
+// from EffectiveDate until the next rate for the same pair. Lookups also use the inverse pair.
+type ExchangeRate struct {
+	ID            string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID      string    `gorm:"column:tenant_id;type:uuid" json:"-"`
+	FromCurrency  string    `gorm:"column:from_currency" json:"from_currency"`
+	ToCurrency    string    `gorm:"column:to_currency" json:"to_currency"`
+	Rate          float64   `gorm:"column:rate" json:"rate"`
+	EffectiveDate time.Time `gorm:"column:effective_date;type:date" json:"effective_date"`
+	CreatedBy     *string   `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
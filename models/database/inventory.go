@@ -6,12 +6,14 @@ import (
 
 type Inventory struct {
 	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID     string    `gorm:"column:tenant_id;type:uuid;not null;index" json:"-"`
 	SKU          string    `gorm:"column:sku;index:sku_location_idx" json:"sku"`
 	Name         string    `gorm:"column:name" json:"name"`
 	Description  *string   `gorm:"column:description" json:"description"`
 	Location     string    `gorm:"column:location;index:sku_location_idx" json:"location"`
 	Quantity     float64   `gorm:"column:quantity" json:"quantity"`
 	ReservedQty  float64   `gorm:"column:reserved_qty" json:"reserved_qty"`
+	HeldQty      float64   `gorm:"column:held_qty" json:"held_qty"`
 	Status       string    `gorm:"column:status" json:"status"`
 	Presentation string    `gorm:"column:presentation" json:"presentation"`
 	UnitPrice    *float64  `gorm:"column:unit_price" json:"unit_price"`
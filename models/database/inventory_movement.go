@@ -21,6 +21,9 @@ type InventoryMovement struct {
 	BeforeQty     *float64 `gorm:"column:before_qty" json:"before_qty,omitempty"`
 	AfterQty      *float64 `gorm:"column:after_qty" json:"after_qty,omitempty"`
 	UserID        *string  `gorm:"column:user_id" json:"user_id,omitempty"`
+	// COGS is the cost of goods of an outbound movement, taken from the cost layers it
+	// consumed (migration 000038). Nil for inbound movements.
+	COGS *float64 `gorm:"column:cogs" json:"cogs,omitempty"`
 }
 
 func (InventoryMovement) TableName() string {
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Assembly order types: an assembly builds kits from their components, a disassembly breaks
+// kits back into components.
+const (
+	AssemblyOrderAssembly    = "assembly"
+	AssemblyOrderDisassembly = "disassembly"
+)
+
+// Assembly order statuses: draft → completed when the stock is moved, or draft → cancelled.
+const (
+	AssemblyOrderDraft     = "draft"
+	AssemblyOrderCompleted = "completed"
+	AssemblyOrderCancelled = "cancelled"
+)
+
+// Movements posted by assembly orders (reference_type "assembly_order").
+const (
+	MovementAssemblyConsume    = "assembly_consume"
+	MovementAssemblyProduce    = "assembly_produce"
+	MovementDisassemblyConsume = "disassembly_consume"
+	MovementDisassemblyProduce = "disassembly_produce"
+)
+
+// Kit is the bill of materials header of a kit article. With ExplodeOnPick a sales order short
+// of assembled kits picks the missing kits as their components.
+type Kit struct {
+	ID            string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID      string    `gorm:"column:tenant_id" json:"-"`
+	KitSKU        string    `gorm:"column:kit_sku" json:"kit_sku"`
+	ExplodeOnPick bool      `gorm:"column:explode_on_pick" json:"explode_on_pick"`
+	Notes         *string   `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy     *string   `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (Kit) TableName() string {
+	return "kits"
+}
+
+// KitComponent is a component of a kit and the quantity one kit takes of it.
+type KitComponent struct {
+	ID           string  `gorm:"column:id;primaryKey" json:"id"`
+	KitID        string  `gorm:"column:kit_id" json:"kit_id"`
+	ComponentSKU string  `gorm:"column:component_sku" json:"component_sku"`
+	Quantity     float64 `gorm:"column:quantity" json:"quantity"`
+}
+
+func (KitComponent) TableName() string {
+	return "kit_components"
+}
+
+// AssemblyOrder builds (assembly) or breaks down (disassembly) Quantity kits at Location.
+// KitLotNumber is the lot the kits are produced into or consumed from; KitUnitCost is set on
+// completion.
+type AssemblyOrder struct {
+	ID           string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID     string     `gorm:"column:tenant_id" json:"-"`
+	OrderNumber  string     `gorm:"column:order_number" json:"order_number"`
+	OrderType    string     `gorm:"column:order_type" json:"order_type"`
+	KitSKU       string     `gorm:"column:kit_sku" json:"kit_sku"`
+	Quantity     float64    `gorm:"column:quantity" json:"quantity"`
+	Location     string     `gorm:"column:location" json:"location"`
+	KitLotNumber *string    `gorm:"column:kit_lot_number" json:"kit_lot_number,omitempty"`
+	KitUnitCost  *float64   `gorm:"column:kit_unit_cost" json:"kit_unit_cost,omitempty"`
+	Status       string     `gorm:"column:status" json:"status"`
+	Notes        *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy    *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	CompletedBy  *string    `gorm:"column:completed_by" json:"completed_by,omitempty"`
+	CompletedAt  *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	CancelledAt  *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (AssemblyOrder) TableName() string {
+	return "assembly_orders"
+}
+
+// AssemblyOrderLine is a component of an assembly order. LotNumber is the lot a disassembly
+// returns the component into; Allocations the locations and lots moved on completion, with
+// their cost in UnitCost / TotalCost.
+type AssemblyOrderLine struct {
+	ID           string          `gorm:"column:id;primaryKey" json:"id"`
+	OrderID      string          `gorm:"column:order_id" json:"order_id"`
+	ComponentSKU string          `gorm:"column:component_sku" json:"component_sku"`
+	QtyPerKit    float64         `gorm:"column:qty_per_kit" json:"qty_per_kit"`
+	Quantity     float64         `gorm:"column:quantity" json:"quantity"`
+	LotNumber    *string         `gorm:"column:lot_number" json:"lot_number,omitempty"`
+	Allocations  json.RawMessage `gorm:"column:allocations;type:jsonb" json:"allocations,omitempty"`
+	UnitCost     *float64        `gorm:"column:unit_cost" json:"unit_cost,omitempty"`
+	TotalCost    *float64        `gorm:"column:total_cost" json:"total_cost,omitempty"`
+}
+
+func (AssemblyOrderLine) TableName() string {
+	return "assembly_order_lines"
+}
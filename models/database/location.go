@@ -18,6 +18,15 @@ type Location struct {
 	IsWayOut     bool      `gorm:"column:is_way_out" json:"is_way_out"`
 	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	// Capacity limits (migration 000043); nil falls back to the location type, then unlimited.
+	MaxUnits    *float64 `gorm:"column:max_units" json:"max_units,omitempty"`
+	MaxWeightKg *float64 `gorm:"column:max_weight_kg" json:"max_weight_kg,omitempty"`
+	MaxVolumeM3 *float64 `gorm:"column:max_volume_m3" json:"max_volume_m3,omitempty"`
+	// Pick path (migration 000045): pick_sequence wins when set, else zone/aisle/rack/level.
+	Aisle        *string `gorm:"column:aisle" json:"aisle,omitempty"`
+	Rack         *string `gorm:"column:rack" json:"rack,omitempty"`
+	Level        *string `gorm:"column:level" json:"level,omitempty"`
+	PickSequence *int    `gorm:"column:pick_sequence" json:"pick_sequence,omitempty"`
 }
 
 func (Location) TableName() string {
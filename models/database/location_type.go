@@ -10,4 +10,8 @@ type LocationType struct {
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Default capacity for locations of this type; nil means unlimited.
+	MaxUnits    *float64 `json:"max_units,omitempty"`
+	MaxWeightKg *float64 `json:"max_weight_kg,omitempty"`
+	MaxVolumeM3 *float64 `json:"max_volume_m3,omitempty"`
 }
@@ -0,0 +1,84 @@
+package database
+
+import "time"
+
+// LotStatusRecalled is the lots.status of a lot under recall: its stock is frozen like stock
+// on QC hold.
+const LotStatusRecalled = "recalled"
+
+// Lot recall statuses: open → closed once no task is pending, or open → cancelled (the lot
+// gets back its previous status).
+const (
+	LotRecallOpen      = "open"
+	LotRecallClosed    = "closed"
+	LotRecallCancelled = "cancelled"
+)
+
+// Recall task types: pick_back moves the lot's stock of a location to the return location,
+// quarantine segregates it in place.
+const (
+	RecallTaskPickBack   = "pick_back"
+	RecallTaskQuarantine = "quarantine"
+)
+
+// Recall task statuses.
+const (
+	RecallTaskPending   = "pending"
+	RecallTaskCompleted = "completed"
+	RecallTaskCancelled = "cancelled"
+)
+
+// Movements posted by a pick-back task (reference_type "lot_recall").
+const (
+	MovementRecallOut = "recall_out"
+	MovementRecallIn  = "recall_in"
+)
+
+// LotRecall freezes a lot and tracks the retrieval of its remaining stock. Classification is
+// the regulatory recall class (I, II or III).
+type LotRecall struct {
+	ID                string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID          string     `gorm:"column:tenant_id" json:"-"`
+	RecallNumber      string     `gorm:"column:recall_number" json:"recall_number"`
+	LotID             string     `gorm:"column:lot_id" json:"lot_id"`
+	SKU               string     `gorm:"column:sku" json:"sku"`
+	LotNumber         string     `gorm:"column:lot_number" json:"lot_number"`
+	Status            string     `gorm:"column:status" json:"status"`
+	Classification    *string    `gorm:"column:classification" json:"classification,omitempty"`
+	Reason            string     `gorm:"column:reason" json:"reason"`
+	ReturnLocation    *string    `gorm:"column:return_location" json:"return_location,omitempty"`
+	PreviousLotStatus *string    `gorm:"column:previous_lot_status" json:"previous_lot_status,omitempty"`
+	Notes             *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy         *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	ClosedBy          *string    `gorm:"column:closed_by" json:"closed_by,omitempty"`
+	ClosedAt          *time.Time `gorm:"column:closed_at" json:"closed_at,omitempty"`
+	CancelledAt       *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (LotRecall) TableName() string {
+	return "lot_recalls"
+}
+
+// LotRecallTask is the retrieval of the recalled lot's stock in one location. Qty is what the
+// location held when the recall opened; CompletedQty what was actually moved or segregated.
+type LotRecallTask struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	RecallID       string     `gorm:"column:recall_id" json:"recall_id"`
+	TaskType       string     `gorm:"column:task_type" json:"task_type"`
+	InventoryID    string     `gorm:"column:inventory_id" json:"inventory_id"`
+	Location       string     `gorm:"column:location" json:"location"`
+	Qty            float64    `gorm:"column:qty" json:"qty"`
+	TargetLocation *string    `gorm:"column:target_location" json:"target_location,omitempty"`
+	Status         string     `gorm:"column:status" json:"status"`
+	CompletedQty   *float64   `gorm:"column:completed_qty" json:"completed_qty,omitempty"`
+	Notes          *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CompletedBy    *string    `gorm:"column:completed_by" json:"completed_by,omitempty"`
+	CompletedAt    *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (LotRecallTask) TableName() string {
+	return "lot_recall_tasks"
+}
@@ -0,0 +1,49 @@
+package database
+
+import "time"
+
+// PickingWave groups several picking tasks so they are picked in one walk
+// (open→in_progress→completed|completed_with_differences, or cancelled).
+// GroupBy/GroupValue record how the member tasks were selected.
+type PickingWave struct {
+	ID          string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID    string     `gorm:"column:tenant_id" json:"-"`
+	WaveNumber  string     `gorm:"column:wave_number" json:"wave_number"`
+	Status      string     `gorm:"column:status" json:"status"`
+	GroupBy     string     `gorm:"column:group_by" json:"group_by"`
+	GroupValue  *string    `gorm:"column:group_value" json:"group_value,omitempty"`
+	AssignedTo  *string    `gorm:"column:assigned_to" json:"assigned_to,omitempty"`
+	Notes       *string    `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedBy   *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	StartedAt   *time.Time `gorm:"column:started_at" json:"started_at,omitempty"`
+	CompletedAt *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	CancelledAt *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (PickingWave) TableName() string {
+	return "picking_waves"
+}
+
+// PickingWaveTask is the membership of a picking task in a wave. Sequence is the sort
+// order (which task a pick is sorted to first); Active is cleared when the wave is cancelled.
+type PickingWaveTask struct {
+	WaveID        string `gorm:"column:wave_id;primaryKey" json:"wave_id"`
+	PickingTaskID string `gorm:"column:picking_task_id;primaryKey" json:"picking_task_id"`
+	Sequence      int    `gorm:"column:sequence" json:"sequence"`
+	Active        bool   `gorm:"column:active" json:"active"`
+}
+
+func (PickingWaveTask) TableName() string {
+	return "picking_wave_tasks"
+}
+
+// PickingWaveMember is a member picking task read together with its wave membership and
+// the expected date of its sales order (not a table).
+type PickingWaveMember struct {
+	PickingTask
+	Sequence     int        `gorm:"column:sequence"`
+	Active       bool       `gorm:"column:active"`
+	ExpectedDate *time.Time `gorm:"column:expected_date"`
+}
@@ -13,8 +13,13 @@ type PurchaseOrderItem struct {
 	ReceivedQty     float64    `gorm:"column:received_qty" json:"received_qty"`
 	RejectedQty     float64    `gorm:"column:rejected_qty" json:"rejected_qty"`
 	UnitCost        *float64   `gorm:"column:unit_cost" json:"unit_cost,omitempty"`
+	Currency        *string    `gorm:"column:currency" json:"currency,omitempty"` // of UnitCost; nil = tenant base currency
 	Discrepancy     *float64   `gorm:"column:discrepancy;<-:false" json:"discrepancy,omitempty"` // generated, read-only
 	Notes           *string    `gorm:"column:notes" json:"notes,omitempty"`
+	// Presentation and PresentationFactor keep the line as entered when it was given in another
+	// presentation; quantities and cost are in base units (as entered: qty / factor).
+	Presentation       *string  `gorm:"column:presentation" json:"presentation,omitempty"`
+	PresentationFactor *float64 `gorm:"column:presentation_factor" json:"presentation_factor,omitempty"`
 	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// QC hold statuses; a lot on hold carries the same value in lots.status. A hold starts in
+// quarantine and the QC decision releases or rejects it.
+const (
+	QCHoldQuarantine = "quarantine"
+	QCHoldReleased   = "released"
+	QCHoldRejected   = "rejected"
+)
+
+// QC hold scopes: a whole lot, or a quantity of one inventory row (location).
+const (
+	QCHoldScopeLot       = "lot"
+	QCHoldScopeInventory = "inventory"
+)
+
+// QC inspection results.
+const (
+	QCInspectionPass        = "pass"
+	QCInspectionFail        = "fail"
+	QCInspectionConditional = "conditional"
+)
+
+// MovementQCReject is the outbound movement posted when held inventory is rejected
+// (reference_type "qc_hold").
+const MovementQCReject = "qc_reject"
+
+// QCHold blocks stock pending a quality decision. A lot hold covers the lot in every location;
+// an inventory hold blocks Qty of one inventory row through inventory.held_qty (SerialNumbers
+// being the held units of serial-tracked stock).
+type QCHold struct {
+	ID            string         `gorm:"column:id;primaryKey" json:"id"`
+	TenantID      string         `gorm:"column:tenant_id" json:"-"`
+	HoldNumber    string         `gorm:"column:hold_number" json:"hold_number"`
+	Scope         string         `gorm:"column:scope" json:"scope"`
+	SKU           string         `gorm:"column:sku" json:"sku"`
+	LotID         *string        `gorm:"column:lot_id" json:"lot_id,omitempty"`
+	InventoryID   *string        `gorm:"column:inventory_id" json:"inventory_id,omitempty"`
+	Location      *string        `gorm:"column:location" json:"location,omitempty"`
+	Qty           *float64       `gorm:"column:qty" json:"qty,omitempty"`
+	SerialNumbers pq.StringArray `gorm:"column:serial_numbers;type:text[]" json:"serial_numbers,omitempty"`
+	Status        string         `gorm:"column:status" json:"status"`
+	Reason        string         `gorm:"column:reason" json:"reason"`
+	SourceType    *string        `gorm:"column:source_type" json:"source_type,omitempty"`
+	SourceID      *string        `gorm:"column:source_id" json:"source_id,omitempty"`
+	CreatedBy     *string        `gorm:"column:created_by" json:"created_by,omitempty"`
+	DecidedBy     *string        `gorm:"column:decided_by" json:"decided_by,omitempty"`
+	DecidedAt     *time.Time     `gorm:"column:decided_at" json:"decided_at,omitempty"`
+	DecisionNotes *string        `gorm:"column:decision_notes" json:"decision_notes,omitempty"`
+	MovementID    *string        `gorm:"column:movement_id" json:"movement_id,omitempty"`
+	CreatedAt     time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (QCHold) TableName() string {
+	return "qc_holds"
+}
+
+// QCInspection is an inspection result recorded on a hold; Measurements holds free-form
+// readings (e.g. {"ph": 6.8}).
+type QCInspection struct {
+	ID           string          `gorm:"column:id;primaryKey" json:"id"`
+	HoldID       string          `gorm:"column:hold_id" json:"hold_id"`
+	Result       string          `gorm:"column:result" json:"result"`
+	Notes        *string         `gorm:"column:notes" json:"notes,omitempty"`
+	Measurements json.RawMessage `gorm:"column:measurements;type:jsonb" json:"measurements,omitempty"`
+	InspectedBy  *string         `gorm:"column:inspected_by" json:"inspected_by,omitempty"`
+	InspectedAt  time.Time       `gorm:"column:inspected_at" json:"inspected_at"`
+}
+
+func (QCInspection) TableName() string {
+	return "qc_inspections"
+}
+
+// QCAttachment is a file (certificate of analysis, photo...) attached to a hold and, optionally,
+// to one of its inspections. The content lives in the document storage under StorageKey.
+type QCAttachment struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	HoldID       string    `gorm:"column:hold_id" json:"hold_id"`
+	InspectionID *string   `gorm:"column:inspection_id" json:"inspection_id,omitempty"`
+	FileName     string    `gorm:"column:file_name" json:"file_name"`
+	ContentType  string    `gorm:"column:content_type" json:"content_type"`
+	SizeBytes    int64     `gorm:"column:size_bytes" json:"size_bytes"`
+	StorageKey   string    `gorm:"column:storage_key" json:"-"`
+	SHA256       string    `gorm:"column:sha256" json:"sha256"`
+	UploadedBy   *string   `gorm:"column:uploaded_by" json:"uploaded_by,omitempty"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (QCAttachment) TableName() string {
+	return "qc_attachments"
+}
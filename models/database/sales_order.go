@@ -2,7 +2,8 @@ package database
 
 import "time"
 
-// SalesOrder represents a sales order header (draft→submitted→partial→completed|cancelled).
+// SalesOrder represents a sales order header (draft→submitted→partial→completed→delivered|cancelled).
+// An order becomes delivered once every delivery note of the completed order has a proof of delivery.
 // When submitted, a PickingTask is auto-generated and linked via PickingTaskID.
 type SalesOrder struct {
 	ID            string     `gorm:"column:id;primaryKey" json:"id"`
@@ -15,6 +16,7 @@ type SalesOrder struct {
 	CreatedBy     *string    `gorm:"column:created_by" json:"created_by,omitempty"`
 	SubmittedAt   *time.Time `gorm:"column:submitted_at" json:"submitted_at,omitempty"`
 	CompletedAt   *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at" json:"delivered_at,omitempty"`
 	CancelledAt   *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
 	PickingTaskID *string    `gorm:"column:picking_task_id" json:"picking_task_id,omitempty"`
 	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
@@ -11,7 +11,12 @@ type SalesOrderItem struct {
 	ExpectedQty   float64   `gorm:"column:expected_qty" json:"expected_qty"`
 	PickedQty     float64   `gorm:"column:picked_qty" json:"picked_qty"`
 	UnitPrice     *float64  `gorm:"column:unit_price" json:"unit_price,omitempty"`
+	Currency      *string   `gorm:"column:currency" json:"currency,omitempty"` // of UnitPrice; nil = tenant base currency
 	Notes         *string   `gorm:"column:notes" json:"notes,omitempty"`
+	// Presentation and PresentationFactor keep the line as entered when it was given in another
+	// presentation; quantities and price are in base units (as entered: qty / factor).
+	Presentation       *string   `gorm:"column:presentation" json:"presentation,omitempty"`
+	PresentationFactor *float64  `gorm:"column:presentation_factor" json:"presentation_factor,omitempty"`
 	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
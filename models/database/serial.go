@@ -2,6 +2,18 @@ package database
 
 import "time"
 
+// Serial statuses. A unit moves received → in_stock → reserved → shipped → returned and can be
+// scrapped while it is in the warehouse; the allowed changes live in the serial state machine
+// (repositories/serial_lifecycle.go).
+const (
+	SerialReceived = "received"
+	SerialInStock  = "in_stock"
+	SerialReserved = "reserved"
+	SerialShipped  = "shipped"
+	SerialReturned = "returned"
+	SerialScrapped = "scrapped"
+)
+
 // Serial is the master-data row for a serial-tracked unit.
 //
 // S3.5 W2-A: tenant_id added so serials are tenant-scoped. The composite
@@ -19,3 +31,25 @@ type Serial struct {
 func (Serial) TableName() string {
 	return "serials"
 }
+
+// SerialHistory is one state change of a serial: where the unit was, which document moved it
+// (receiving task, picking task, delivery note, return, QC hold, adjustment) and who did it.
+// FromStatus is nil on the row that registers the serial.
+type SerialHistory struct {
+	ID             string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID       string    `gorm:"column:tenant_id" json:"-"`
+	SerialID       string    `gorm:"column:serial_id" json:"serial_id"`
+	FromStatus     *string   `gorm:"column:from_status" json:"from_status,omitempty"`
+	ToStatus       string    `gorm:"column:to_status" json:"to_status"`
+	Location       *string   `gorm:"column:location" json:"location,omitempty"`
+	DocumentType   *string   `gorm:"column:document_type" json:"document_type,omitempty"`
+	DocumentID     *string   `gorm:"column:document_id" json:"document_id,omitempty"`
+	DocumentNumber *string   `gorm:"column:document_number" json:"document_number,omitempty"`
+	UserID         *string   `gorm:"column:user_id" json:"user_id,omitempty"`
+	Notes          *string   `gorm:"column:notes" json:"notes,omitempty"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (SerialHistory) TableName() string {
+	return "serial_history"
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Shipment is the packing stage of a completed picking task (packing→packed) when the tenant
+// requires packing. The delivery note is created when the shipment is closed.
+type Shipment struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID       string     `gorm:"column:tenant_id" json:"-"`
+	ShipmentNumber string     `gorm:"column:shipment_number" json:"shipment_number"`
+	PickingTaskID  string     `gorm:"column:picking_task_id" json:"picking_task_id"`
+	SalesOrderID   string     `gorm:"column:sales_order_id" json:"sales_order_id"`
+	CustomerID     *string    `gorm:"column:customer_id" json:"customer_id,omitempty"`
+	Status         string     `gorm:"column:status" json:"status"`
+	DeliveryNoteID *string    `gorm:"column:delivery_note_id" json:"delivery_note_id,omitempty"`
+	Notes          *string    `gorm:"column:notes" json:"notes,omitempty"`
+	PackedBy       *string    `gorm:"column:packed_by" json:"packed_by,omitempty"`
+	PackedAt       *time.Time `gorm:"column:packed_at" json:"packed_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+// ShipmentLine is what was picked for a shipment, per SKU + lot. SerialNumbers holds the
+// serials picked for the SKU (kept on the SKU's first line, as picking tracks serials per SKU).
+type ShipmentLine struct {
+	ID            string         `gorm:"column:id;primaryKey" json:"id"`
+	ShipmentID    string         `gorm:"column:shipment_id" json:"shipment_id"`
+	ArticleSKU    string         `gorm:"column:article_sku" json:"article_sku"`
+	LotNumber     *string        `gorm:"column:lot_number" json:"lot_number,omitempty"`
+	Qty           float64        `gorm:"column:qty" json:"qty"`
+	SerialNumbers pq.StringArray `gorm:"column:serial_numbers;type:text[]" json:"serial_numbers,omitempty"`
+}
+
+func (ShipmentLine) TableName() string {
+	return "shipment_lines"
+}
+
+// Package is a carton or pallet of a shipment, identified by its SSCC.
+type Package struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	TenantID    string    `gorm:"column:tenant_id" json:"-"`
+	ShipmentID  string    `gorm:"column:shipment_id" json:"shipment_id"`
+	SSCC        string    `gorm:"column:sscc" json:"sscc"`
+	PackageType string    `gorm:"column:package_type" json:"package_type"`
+	Sequence    int       `gorm:"column:sequence" json:"sequence"`
+	WeightKg    *float64  `gorm:"column:weight_kg" json:"weight_kg,omitempty"`
+	LengthCm    *float64  `gorm:"column:length_cm" json:"length_cm,omitempty"`
+	WidthCm     *float64  `gorm:"column:width_cm" json:"width_cm,omitempty"`
+	HeightCm    *float64  `gorm:"column:height_cm" json:"height_cm,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (Package) TableName() string {
+	return "packages"
+}
+
+// PackageContent is a quantity (and its serials) of a shipment line packed into a package.
+type PackageContent struct {
+	ID             string         `gorm:"column:id;primaryKey" json:"id"`
+	PackageID      string         `gorm:"column:package_id" json:"package_id"`
+	ShipmentLineID string         `gorm:"column:shipment_line_id" json:"shipment_line_id"`
+	Qty            float64        `gorm:"column:qty" json:"qty"`
+	SerialNumbers  pq.StringArray `gorm:"column:serial_numbers;type:text[]" json:"serial_numbers,omitempty"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (PackageContent) TableName() string {
+	return "package_contents"
+}
@@ -15,4 +15,29 @@ type StockSetting struct {
 	AutoCreateMaterialRequest bool      `json:"auto_create_material_request"`
 	PartialDeliveryPolicy     string    `json:"partial_delivery_policy"`
 	UpdatedAt                 time.Time `json:"updated_at"`
+	// BaseCurrency is the ISO 4217 currency valuation and order totals are reported in.
+	BaseCurrency string `json:"base_currency"`
+	// RequirePacking routes completed picking tasks through a packing shipment; the delivery
+	// note is only created once the shipment is packed.
+	RequirePacking bool `json:"require_packing"`
+	// RequireLotRelease (pharma tenants) keeps the stock of every lot on QC hold until the lot
+	// is released; otherwise only quarantined and rejected lots are held.
+	RequireLotRelease bool `json:"require_lot_release"`
+	// LotNumberPattern / SerialNumberPattern generate lot and serial numbers on receipt
+	// (e.g. "LOT-{YYYY}{MM}-{####}"); nil uses the built-in defaults. An article's own
+	// series takes precedence.
+	LotNumberPattern    *string `json:"lot_number_pattern,omitempty"`
+	SerialNumberPattern *string `json:"serial_number_pattern,omitempty"`
 }
+
+// Built-in number series patterns, used when neither the article nor the tenant set one.
+const (
+	DefaultLotNumberPattern    = "LOT-{YYYY}{MM}-{####}"
+	DefaultSerialNumberPattern = "SN-{YYYY}-{######}"
+)
+
+// Number series kinds (number_series_counters.kind).
+const (
+	NumberSeriesLot    = "lot"
+	NumberSeriesSerial = "serial"
+)
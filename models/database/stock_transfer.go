@@ -2,6 +2,32 @@ package database
 
 import "time"
 
+// Stock transfer statuses. A transfer is either executed in one step (draft/in_progress →
+// completed) or shipped and then received: in_transit after ship, partially_received while
+// some lines are still open, completed once every line is closed.
+const (
+	StockTransferStatusDraft             = "draft"
+	StockTransferStatusInProgress        = "in_progress"
+	StockTransferStatusInTransit         = "in_transit"
+	StockTransferStatusPartiallyReceived = "partially_received"
+	StockTransferStatusCompleted         = "completed"
+	StockTransferStatusCancelled         = "cancelled"
+)
+
+// Stock transfer line statuses. in_transit and partially_received lines are open; received,
+// short_received and over_received lines are closed (received_qty equal to, below or above
+// shipped_qty).
+const (
+	TransferLinePending           = "pending"
+	TransferLinePicked            = "picked"
+	TransferLineInTransit         = "in_transit"
+	TransferLinePartiallyReceived = "partially_received"
+	TransferLineReceived          = "received"
+	TransferLineShortReceived     = "short_received"
+	TransferLineOverReceived      = "over_received"
+	TransferLineCancelled         = "cancelled"
+)
+
 // StockTransfer represents a WMS transfer order (from location to location).
 type StockTransfer struct {
 	ID             string     `json:"id"`
@@ -27,4 +53,12 @@ type StockTransferLine struct {
 	Presentation     *string   `json:"presentation,omitempty"`
 	LineStatus      string    `json:"line_status"`
 	CreatedAt       time.Time `json:"created_at"`
+	// ShippedQty left the source on ship; ReceivedQty has been confirmed at the destination.
+	ShippedQty        float64    `json:"shipped_qty"`
+	ReceivedQty       float64    `json:"received_qty"`
+	DiscrepancyReason *string    `json:"discrepancy_reason,omitempty"`
+	ReceivedAt        *time.Time `json:"received_at,omitempty"`
+	// Quantities are in base units; Presentation is the one the line was entered in and
+	// PresentationFactor the base units per unit of it.
+	PresentationFactor *float64 `json:"presentation_factor,omitempty"`
 }
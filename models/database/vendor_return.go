@@ -0,0 +1,57 @@
+package database
+
+import "time"
+
+// Vendor return statuses. A return is a draft until the goods leave for the supplier
+// (shipped) and credited once the supplier's credit note is recorded. Only a draft can be
+// cancelled.
+const (
+	VendorReturnDraft     = "draft"
+	VendorReturnShipped   = "shipped"
+	VendorReturnCredited  = "credited"
+	VendorReturnCancelled = "cancelled"
+)
+
+// VendorReturn is a return-to-vendor (RTV) of units rejected at receiving, linked to the
+// purchase order and / or receiving task the rejections were recorded on.
+type VendorReturn struct {
+	ID               string     `gorm:"column:id;primaryKey" json:"id"`
+	TenantID         string     `gorm:"column:tenant_id" json:"-"`
+	ReturnNumber     string     `gorm:"column:return_number" json:"return_number"`
+	PurchaseOrderID  *string    `gorm:"column:purchase_order_id" json:"purchase_order_id,omitempty"`
+	ReceivingTaskID  *string    `gorm:"column:receiving_task_id" json:"receiving_task_id,omitempty"`
+	SupplierID       string     `gorm:"column:supplier_id" json:"supplier_id"`
+	Status           string     `gorm:"column:status" json:"status"`
+	Notes            *string    `gorm:"column:notes" json:"notes,omitempty"`
+	Carrier          *string    `gorm:"column:carrier" json:"carrier,omitempty"`
+	TrackingNumber   *string    `gorm:"column:tracking_number" json:"tracking_number,omitempty"`
+	ShippedAt        *time.Time `gorm:"column:shipped_at" json:"shipped_at,omitempty"`
+	CreditNoteNumber *string    `gorm:"column:credit_note_number" json:"credit_note_number,omitempty"`
+	CreditedAmount   *float64   `gorm:"column:credited_amount" json:"credited_amount,omitempty"`
+	CreditedAt       *time.Time `gorm:"column:credited_at" json:"credited_at,omitempty"`
+	CancelledAt      *time.Time `gorm:"column:cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedBy        *string    `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (VendorReturn) TableName() string {
+	return "vendor_returns"
+}
+
+// VendorReturnLine is a rejected quantity of a SKU sent back. UnitCost / Currency come from
+// the purchase order item (nil when the return has no PO or the item has no cost).
+type VendorReturnLine struct {
+	ID                  string   `gorm:"column:id;primaryKey" json:"id"`
+	ReturnID            string   `gorm:"column:return_id" json:"return_id"`
+	PurchaseOrderItemID *string  `gorm:"column:purchase_order_item_id" json:"purchase_order_item_id,omitempty"`
+	ArticleSKU          string   `gorm:"column:article_sku" json:"article_sku"`
+	Qty                 float64  `gorm:"column:qty" json:"qty"`
+	UnitCost            *float64 `gorm:"column:unit_cost" json:"unit_cost,omitempty"`
+	Currency            *string  `gorm:"column:currency" json:"currency,omitempty"`
+	Reason              *string  `gorm:"column:reason" json:"reason,omitempty"`
+}
+
+func (VendorReturnLine) TableName() string {
+	return "vendor_return_lines"
+}
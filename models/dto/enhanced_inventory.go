@@ -12,6 +12,7 @@ type EnhancedInventory struct {
 	Location        string            `json:"location"`
 	Quantity        float64           `json:"quantity"`
 	ReservedQty     float64           `json:"reserved_qty"`
+	HeldQty         float64           `json:"held_qty"`
 	AvailableQty    float64           `json:"available_qty"`
 	Status          string            `json:"status"`
 	UnitPrice       *float64          `json:"unit_price"`
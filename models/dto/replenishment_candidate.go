@@ -0,0 +1,21 @@
+package dto
+
+// ReplenishmentCandidate is one active article with its stock position and preferred supplier,
+// as loaded by ReplenishmentRepository. The reorder decision itself is computed in the service.
+// Supplier fields are nil when the article has no (non-deleted) supplier link.
+type ReplenishmentCandidate struct {
+	SKU          string   `gorm:"column:sku"`
+	Name         string   `gorm:"column:name"`
+	SafetyStock  float64  `gorm:"column:safety_stock"`
+	MinQuantity  *int     `gorm:"column:min_quantity"`
+	MaxQuantity  *int     `gorm:"column:max_quantity"`
+	MinOrderQty  float64  `gorm:"column:min_order_qty"`
+	OnHand       float64  `gorm:"column:on_hand"`
+	Reserved     float64  `gorm:"column:reserved"`
+	OpenPOQty    float64  `gorm:"column:open_po_qty"`
+	DailyDemand  float64  `gorm:"column:daily_demand"`
+	SupplierID   *string  `gorm:"column:supplier_id"`
+	SupplierName *string  `gorm:"column:supplier_name"`
+	LeadTimeDays *int     `gorm:"column:lead_time_days"`
+	UnitCost     *float64 `gorm:"column:unit_cost"`
+}
@@ -0,0 +1,16 @@
+package requests
+
+// CreateArticleBarcodeRequest registers an alternate code (GTIN by default) for an article.
+type CreateArticleBarcodeRequest struct {
+	SKU          string   `json:"sku" validate:"required,max=100"`
+	Code         string   `json:"code" validate:"required,max=64"`
+	CodeType     string   `json:"code_type" validate:"omitempty,oneof=gtin internal"`
+	PackQuantity *float64 `json:"pack_quantity" validate:"omitempty,gt=0"`
+}
+
+// ScanParseRequest is a raw scan from a handheld. Purpose tunes the warnings: receiving
+// flags serials that already exist, picking flags lots and serials that do not.
+type ScanParseRequest struct {
+	Code    string `json:"code" validate:"required,max=512"`
+	Purpose string `json:"purpose" validate:"omitempty,oneof=receiving picking"`
+}
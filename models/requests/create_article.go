@@ -23,4 +23,10 @@ type Article struct {
 	DefaultLocationID  *string  `json:"default_location_id,omitempty"`
 	ReceivingNotes     *string  `json:"receiving_notes,omitempty"`
 	ShippingNotes      *string  `json:"shipping_notes,omitempty"`
+	PriceCurrency      *string  `json:"price_currency,omitempty" validate:"omitempty,iso4217"`
+	// Unit weight and dimensions, used for location capacity checks.
+	WeightKg *float64 `json:"weight_kg,omitempty" validate:"omitempty,gte=0"`
+	LengthCm *float64 `json:"length_cm,omitempty" validate:"omitempty,gte=0"`
+	WidthCm  *float64 `json:"width_cm,omitempty" validate:"omitempty,gte=0"`
+	HeightCm *float64 `json:"height_cm,omitempty" validate:"omitempty,gte=0"`
 }
@@ -6,4 +6,13 @@ type Location struct {
 	Zone         *string `json:"zone"`
 	Type         string  `json:"type" binding:"required" validate:"required,max=50"`
 	IsWayOut     bool    `json:"is_way_out"`
+	// Optional capacity limits; omitted inherits the location type's limits.
+	MaxUnits    *float64 `json:"max_units" validate:"omitempty,gt=0"`
+	MaxWeightKg *float64 `json:"max_weight_kg" validate:"omitempty,gt=0"`
+	MaxVolumeM3 *float64 `json:"max_volume_m3" validate:"omitempty,gt=0"`
+	// Optional pick path; an explicit pick_sequence overrides the aisle/rack/level walk.
+	Aisle        *string `json:"aisle" validate:"omitempty,max=20"`
+	Rack         *string `json:"rack" validate:"omitempty,max=20"`
+	Level        *string `json:"level" validate:"omitempty,max=20"`
+	PickSequence *int    `json:"pick_sequence" validate:"omitempty,gte=0"`
 }
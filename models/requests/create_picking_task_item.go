@@ -1,6 +1,7 @@
 package requests
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/eflowcr/eSTOCK_backend/models/database"
@@ -17,6 +18,16 @@ type PickingTaskItemRequest struct {
 	SerialNumbers    []database.Serial             `json:"serials,omitempty"`
 	Status           *string                       `json:"status,omitempty"`
 	PickedQty        *float64                      `json:"picked_qty,omitempty"`
+	// KitSKU is set on component lines of a kit picked as its components (kits.explode_on_pick);
+	// KitQtyPer is the component quantity per kit.
+	KitSKU    *string  `json:"kit_sku,omitempty"`
+	KitQtyPer *float64 `json:"kit_qty_per,omitempty"`
+	// Presentation is the unit the line's quantities are entered in (type code, e.g. CAJA); they
+	// are converted to base units on save and the line is stored in base units without a
+	// presentation, so a line that carries one is always converted.
+	Presentation *string `json:"presentation,omitempty" validate:"omitempty,max=50"`
+	// PresentationFactor is set server-side when the quantities are converted to base units.
+	PresentationFactor *float64 `json:"-"`
 }
 
 // CreatePickingTaskItemRequest is an alias kept for backwards compatibility with
@@ -41,3 +52,32 @@ func (r PickingTaskItemRequest) ValidateAllocationSum() error {
 	}
 	return nil
 }
+
+// ParsePickingTaskItems decodes the items JSON of a picking task. It accepts both the
+// current format (items with allocations) and the legacy one (a single "location" string
+// and no allocations); legacy items get a synthetic single allocation so callers can treat
+// all items uniformly.
+func ParsePickingTaskItems(raw json.RawMessage) ([]PickingTaskItemRequest, error) {
+	var items []PickingTaskItemRequest
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	// Second pass: check for legacy "location" field (items with no allocations).
+	var legacyPayload []map[string]interface{}
+	if err := json.Unmarshal(raw, &legacyPayload); err != nil {
+		// If this fails the first unmarshal was sufficient.
+		return items, nil
+	}
+
+	for i := range items {
+		if len(items[i].Allocations) == 0 && i < len(legacyPayload) {
+			if location, ok := legacyPayload[i]["location"].(string); ok && location != "" {
+				items[i].Allocations = []database.LocationAllocation{
+					{Location: location, Quantity: items[i].ExpectedQuantity},
+				}
+			}
+		}
+	}
+	return items, nil
+}
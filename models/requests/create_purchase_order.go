@@ -7,7 +7,11 @@ type CreatePurchaseOrderItemRequest struct {
 	ArticleSKU  string   `json:"article_sku" validate:"required,max=100"`
 	ExpectedQty float64  `json:"expected_qty" validate:"required,gt=0"`
 	UnitCost    *float64 `json:"unit_cost,omitempty" validate:"omitempty,gte=0"`
+	Currency    *string  `json:"currency,omitempty" validate:"omitempty,iso4217"`
 	Notes       *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+	// Presentation is the unit ExpectedQty and UnitCost are given in (type code, e.g. CAJA);
+	// empty means the article's base presentation.
+	Presentation *string `json:"presentation,omitempty" validate:"omitempty,max=50"`
 }
 
 // CreatePurchaseOrderRequest is the body for POST /api/purchase-orders/.
@@ -14,4 +14,13 @@ type ReceivingTaskItemRequest struct {
 	// the service backfills AcceptedQty = ReceivedQuantity (legacy compatibility).
 	AcceptedQty *float64 `json:"accepted_qty,omitempty" validate:"omitempty,gte=0"`
 	RejectedQty *float64 `json:"rejected_qty,omitempty" validate:"omitempty,gte=0"`
+	// GenerateNumbers lets receiving generate the lot (one for the line) or serial numbers of
+	// a tracked article from its number series when none were captured.
+	GenerateNumbers bool `json:"generate_numbers,omitempty"`
+	// Presentation is the unit the line's quantities are entered in (type code, e.g. CAJA); they
+	// are converted to base units on save and the line is stored in base units without a
+	// presentation, so a line that carries one is always converted.
+	Presentation *string `json:"presentation,omitempty" validate:"omitempty,max=50"`
+	// PresentationFactor is set server-side when the quantities are converted to base units.
+	PresentationFactor *float64 `json:"-"`
 }
@@ -15,7 +15,11 @@ type CreateSalesOrderItem struct {
 	ArticleSKU  string   `json:"article_sku" validate:"required,max=100"`
 	ExpectedQty float64  `json:"expected_qty" validate:"required,gt=0"`
 	UnitPrice   *float64 `json:"unit_price,omitempty" validate:"omitempty,gte=0"`
+	Currency    *string  `json:"currency,omitempty" validate:"omitempty,iso4217"`
 	Notes       *string  `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	// Presentation is the unit ExpectedQty and UnitPrice are given in (type code, e.g. CAJA);
+	// empty means the article's base presentation.
+	Presentation *string `json:"presentation,omitempty" validate:"omitempty,max=50"`
 }
 
 // UpdateSalesOrderRequest is the body for PATCH /api/sales-orders/:id (draft only).
@@ -0,0 +1,41 @@
+package requests
+
+// CreateCustomerReturnRequest is the body for POST /api/customer-returns. Exactly one of
+// delivery_note_id or sales_order_id is required; with a delivery note only what it delivered
+// can be returned, with a sales order what any of its delivery notes delivered.
+type CreateCustomerReturnRequest struct {
+	DeliveryNoteID *string                     `json:"delivery_note_id,omitempty" validate:"omitempty,min=1"`
+	SalesOrderID   *string                     `json:"sales_order_id,omitempty" validate:"omitempty,min=1"`
+	Notes          *string                     `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Lines          []CustomerReturnLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// CustomerReturnLineRequest is a SKU coming back. lot_number is required for lot-tracked
+// articles and serial_numbers (one per unit) for serial-tracked ones.
+type CustomerReturnLineRequest struct {
+	ArticleSKU    string   `json:"article_sku" validate:"required"`
+	LotNumber     *string  `json:"lot_number,omitempty" validate:"omitempty,min=1"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" validate:"omitempty,dive,required"`
+	Qty           *float64 `json:"qty" validate:"required,gt=0"`
+	ReasonCode    string   `json:"reason_code" validate:"required,oneof=damaged defective wrong_item expired not_ordered other"`
+	ReasonNotes   *string  `json:"reason_notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// ReceiveCustomerReturnRequest is the body for POST /api/customer-returns/:id/receive: the
+// inspection results. A line may be split over several dispositions and received over
+// several calls, up to its quantity.
+type ReceiveCustomerReturnRequest struct {
+	Inspections []ReturnInspectionRequest `json:"inspections" validate:"required,min=1,dive"`
+}
+
+// ReturnInspectionRequest is the disposition of a quantity of a return line. location is where
+// the goods are put (restock / quarantine) or scrapped; serial_numbers must be among the
+// line's serials not inspected yet, one per unit for serial-tracked articles.
+type ReturnInspectionRequest struct {
+	ReturnLineID  string   `json:"return_line_id" validate:"required"`
+	Qty           *float64 `json:"qty" validate:"required,gt=0"`
+	Disposition   string   `json:"disposition" validate:"required,oneof=restock quarantine scrap"`
+	Location      string   `json:"location" validate:"required"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" validate:"omitempty,dive,required"`
+	Notes         *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
@@ -0,0 +1,48 @@
+package requests
+
+// CreateCountPlanRequest is the body for POST /api/cycle-counts/plans.
+// scope_values are interpreted according to scope_type: zone names, location codes,
+// category IDs or ABC classes (A, B, C). tenant_id and created_by are stamped server-side.
+type CreateCountPlanRequest struct {
+	Name                string   `json:"name" validate:"required,max=150"`
+	Description         *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ScopeType           string   `json:"scope_type" validate:"required,oneof=zone location category abc_class"`
+	ScopeValues         []string `json:"scope_values" validate:"required,min=1,dive,required,max=100"`
+	Blind               *bool    `json:"blind,omitempty"`
+	RecountThresholdPct *float64 `json:"recount_threshold_pct,omitempty" validate:"omitempty,gte=0"`
+	RecountThresholdQty *float64 `json:"recount_threshold_qty,omitempty" validate:"omitempty,gte=0"`
+	ReasonCode          *string  `json:"reason_code,omitempty" validate:"omitempty,max=80"`
+}
+
+// UpdateCountPlanRequest is the body for PATCH /api/cycle-counts/plans/:id. All fields optional.
+type UpdateCountPlanRequest struct {
+	Name                *string  `json:"name,omitempty" validate:"omitempty,max=150"`
+	Description         *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ScopeType           *string  `json:"scope_type,omitempty" validate:"omitempty,oneof=zone location category abc_class"`
+	ScopeValues         []string `json:"scope_values,omitempty" validate:"omitempty,min=1,dive,required,max=100"`
+	Blind               *bool    `json:"blind,omitempty"`
+	RecountThresholdPct *float64 `json:"recount_threshold_pct,omitempty" validate:"omitempty,gte=0"`
+	RecountThresholdQty *float64 `json:"recount_threshold_qty,omitempty" validate:"omitempty,gte=0"`
+	ReasonCode          *string  `json:"reason_code,omitempty" validate:"omitempty,max=80"`
+	IsActive            *bool    `json:"is_active,omitempty"`
+}
+
+// GenerateCountTaskRequest is the body for POST /api/cycle-counts/plans/:id/generate.
+type GenerateCountTaskRequest struct {
+	AssignedTo *string `json:"assigned_to,omitempty" validate:"omitempty,max=40"`
+	Notes      *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// RecordCountRequest is the body for POST /api/cycle-counts/tasks/:id/lines/:lineId/count.
+// The same endpoint records the first count and, when the line was flagged, the recount.
+type RecordCountRequest struct {
+	CountedQty *float64 `json:"counted_qty" validate:"required,gte=0"`
+	Notes      *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// RejectCountTaskRequest is the body for PATCH /api/cycle-counts/tasks/:id/reject.
+// The listed lines are sent back for recount and the task returns to in_progress.
+type RejectCountTaskRequest struct {
+	LineIDs []string `json:"line_ids" validate:"required,min=1,dive,required"`
+	Notes   *string  `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
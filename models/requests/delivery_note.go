@@ -0,0 +1,11 @@
+package requests
+
+// RecordPODRequest holds the form fields of POST /api/delivery-notes/:id/pod (multipart/form-data;
+// the optional "signature" image and "photos" images are read from the form files).
+// delivered_at is RFC3339 and defaults to now; it cannot be in the future.
+type RecordPODRequest struct {
+	ReceiverName string  `form:"receiver_name" json:"receiver_name" validate:"required,max=200"`
+	DeliveredAt  *string `form:"delivered_at" json:"delivered_at,omitempty" validate:"omitempty"`
+	GPS          *string `form:"gps" json:"gps,omitempty" validate:"omitempty,max=100"`
+	Notes        *string `form:"notes" json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,10 @@
+package requests
+
+// UpsertExchangeRateRequest sets the rate of a currency pair (1 from = rate to) from
+// EffectiveDate (YYYY-MM-DD) on. Posting the same pair and date again replaces the rate.
+type UpsertExchangeRateRequest struct {
+	FromCurrency  string  `json:"from_currency" validate:"required,iso4217"`
+	ToCurrency    string  `json:"to_currency" validate:"required,iso4217,nefield=FromCurrency"`
+	Rate          float64 `json:"rate" validate:"required,gt=0"`
+	EffectiveDate string  `json:"effective_date" validate:"required,datetime=2006-01-02"`
+}
@@ -0,0 +1,34 @@
+package requests
+
+// UpsertKitRequest is the body for PUT /api/kits/:sku. It replaces the kit's bill of materials.
+type UpsertKitRequest struct {
+	ExplodeOnPick bool                  `json:"explode_on_pick"`
+	Notes         *string               `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Components    []KitComponentRequest `json:"components" validate:"required,min=1,dive"`
+}
+
+// KitComponentRequest is a component of a kit and the quantity one kit takes of it.
+type KitComponentRequest struct {
+	SKU      string  `json:"sku" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateAssemblyOrderRequest is the body for POST /api/assembly-orders. kit_lot_number is the
+// lot produced by an assembly (generated from the number series when the kit tracks lots and
+// none is given) or the lot a disassembly consumes (FEFO when omitted). component_lots names the
+// lot each lot-tracked component returns into on a disassembly.
+type CreateAssemblyOrderRequest struct {
+	OrderType     string                        `json:"order_type" validate:"required,oneof=assembly disassembly"`
+	KitSKU        string                        `json:"kit_sku" validate:"required"`
+	Quantity      float64                       `json:"quantity" validate:"required,gt=0"`
+	Location      string                        `json:"location" validate:"required"`
+	KitLotNumber  *string                       `json:"kit_lot_number,omitempty" validate:"omitempty,min=1,max=100"`
+	ComponentLots []AssemblyComponentLotRequest `json:"component_lots,omitempty" validate:"omitempty,dive"`
+	Notes         *string                       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// AssemblyComponentLotRequest names the lot a component returns into on a disassembly.
+type AssemblyComponentLotRequest struct {
+	SKU       string `json:"sku" validate:"required"`
+	LotNumber string `json:"lot_number" validate:"required,max=100"`
+}
@@ -0,0 +1,12 @@
+package requests
+
+// LabelBatchRequest is the body for printing several labels of one kind in a single document.
+// Format defaults to pdf; Symbology defaults per kind (code128 for locations and articles,
+// gs1-128 for lots and serials).
+type LabelBatchRequest struct {
+	Kind      string   `json:"kind" validate:"required,oneof=location article lot serial"`
+	IDs       []string `json:"ids" validate:"required,min=1,max=500,dive,required"`
+	Format    string   `json:"format" validate:"omitempty,oneof=pdf zpl"`
+	Symbology string   `json:"symbology" validate:"omitempty,oneof=code128 gs1-128 qr"`
+	Copies    int      `json:"copies" validate:"omitempty,min=1,max=100"`
+}
@@ -1,15 +1,21 @@
 package requests
 
 type LocationTypeCreate struct {
-	Code      string `json:"code" binding:"required" validate:"required,max=20"`
-	Name      string `json:"name" binding:"required" validate:"required,max=100"`
-	SortOrder int32  `json:"sort_order" validate:"gte=0"`
-	IsActive  *bool  `json:"is_active"`
+	Code        string   `json:"code" binding:"required" validate:"required,max=20"`
+	Name        string   `json:"name" binding:"required" validate:"required,max=100"`
+	SortOrder   int32    `json:"sort_order" validate:"gte=0"`
+	IsActive    *bool    `json:"is_active"`
+	MaxUnits    *float64 `json:"max_units" validate:"omitempty,gt=0"`
+	MaxWeightKg *float64 `json:"max_weight_kg" validate:"omitempty,gt=0"`
+	MaxVolumeM3 *float64 `json:"max_volume_m3" validate:"omitempty,gt=0"`
 }
 
 type LocationTypeUpdate struct {
-	Code      string `json:"code" binding:"required" validate:"required,max=20"`
-	Name      string `json:"name" binding:"required" validate:"required,max=100"`
-	SortOrder int32  `json:"sort_order" validate:"gte=0"`
-	IsActive  *bool  `json:"is_active"`
+	Code        string   `json:"code" binding:"required" validate:"required,max=20"`
+	Name        string   `json:"name" binding:"required" validate:"required,max=100"`
+	SortOrder   int32    `json:"sort_order" validate:"gte=0"`
+	IsActive    *bool    `json:"is_active"`
+	MaxUnits    *float64 `json:"max_units" validate:"omitempty,gt=0"`
+	MaxWeightKg *float64 `json:"max_weight_kg" validate:"omitempty,gt=0"`
+	MaxVolumeM3 *float64 `json:"max_volume_m3" validate:"omitempty,gt=0"`
 }
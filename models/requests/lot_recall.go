@@ -0,0 +1,23 @@
+package requests
+
+// CreateLotRecallRequest is the body for POST /api/lot-recalls. With return_location the
+// remaining stock gets pick-back tasks to that location, otherwise quarantine-in-place tasks.
+type CreateLotRecallRequest struct {
+	LotID          string  `json:"lot_id" validate:"required"`
+	Reason         string  `json:"reason" validate:"required,max=1000"`
+	Classification *string `json:"classification,omitempty" validate:"omitempty,oneof=I II III"`
+	ReturnLocation *string `json:"return_location,omitempty" validate:"omitempty,min=1"`
+	Notes          *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CompleteRecallTaskRequest is the optional body for PATCH /api/lot-recalls/:id/tasks/:taskId/complete.
+// qty defaults to what the location still holds of the lot (up to the task quantity).
+type CompleteRecallTaskRequest struct {
+	Qty   *float64 `json:"qty,omitempty" validate:"omitempty,gte=0"`
+	Notes *string  `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CloseLotRecallRequest is the optional body for PATCH /api/lot-recalls/:id/close and /cancel.
+type CloseLotRecallRequest struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+// NumberSeriesPreviewRequest is the query of GET /api/settings/number-series/preview. With a
+// sku the article's own series (and its {SKU} token) apply; pattern tries an unsaved pattern.
+// Count defaults to 5.
+type NumberSeriesPreviewRequest struct {
+	Kind    string `form:"kind" validate:"required,oneof=lot serial"`
+	SKU     string `form:"sku" validate:"omitempty,max=100"`
+	Pattern string `form:"pattern" validate:"omitempty,max=100"`
+	Count   int    `form:"count" validate:"omitempty,gte=1,lte=100"`
+}
@@ -0,0 +1,14 @@
+package requests
+
+// OverReceiptOverride is a supervisor authorization to receive more than the expected
+// quantity plus the tenant's over-receipt allowance (StockSetting.OverReceiptAllowancePct).
+type OverReceiptOverride struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// CompleteReceivingLineOverrideRequest is the body of the supervisor variant of
+// complete-receiving-line: the line being received plus the override reason.
+type CompleteReceivingLineOverrideRequest struct {
+	Item   ReceivingTaskItemRequest `json:"item"`
+	Reason string                   `json:"reason" validate:"required,min=3,max=500"`
+}
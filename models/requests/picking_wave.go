@@ -0,0 +1,31 @@
+package requests
+
+// CreatePickingWaveRequest is the body for POST /api/picking-waves.
+// Member tasks are either listed explicitly (group_by "manual" or omitted with
+// picking_task_ids) or selected from the open/in-progress tasks not already in a wave:
+//   - customer:      tasks of the given customer_ids
+//   - priority:      tasks with the given priority (high, normal, low)
+//   - expected_date: tasks whose sales order is expected on expected_date (YYYY-MM-DD)
+//
+// max_tasks caps how many candidates are taken (highest priority, earliest expected date first).
+type CreatePickingWaveRequest struct {
+	GroupBy        string   `json:"group_by,omitempty" validate:"omitempty,oneof=manual customer priority expected_date"`
+	PickingTaskIDs []string `json:"picking_task_ids,omitempty" validate:"omitempty,dive,required"`
+	CustomerIDs    []string `json:"customer_ids,omitempty" validate:"omitempty,dive,required"`
+	Priority       *string  `json:"priority,omitempty" validate:"omitempty,oneof=high normal low"`
+	ExpectedDate   *string  `json:"expected_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	MaxTasks       *int     `json:"max_tasks,omitempty" validate:"omitempty,gt=0,lte=200"`
+	AssignedTo     *string  `json:"assigned_to,omitempty" validate:"omitempty,max=40"`
+	Notes          *string  `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// RecordWavePickRequest is the body for POST /api/picking-waves/:id/picks. picked_qty is the
+// total picked for one line of the consolidated pick list (location + SKU + lot); recording
+// the same line again replaces the previous quantity. It is sorted to the member tasks in
+// wave order.
+type RecordWavePickRequest struct {
+	Location  string   `json:"location" validate:"required"`
+	SKU       string   `json:"sku" validate:"required"`
+	LotNumber *string  `json:"lot_number,omitempty"`
+	PickedQty *float64 `json:"picked_qty" validate:"required,gte=0"`
+}
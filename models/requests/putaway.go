@@ -0,0 +1,12 @@
+package requests
+
+// PutawaySuggestRequest tunes putaway ranking. Zones are preferred zones (bonus, not a
+// filter); LocationTypes, when set, restricts candidates to those location type codes.
+type PutawaySuggestRequest struct {
+	Zones         []string `json:"zones,omitempty" validate:"omitempty,dive,max=50"`
+	LocationTypes []string `json:"location_types,omitempty" validate:"omitempty,dive,max=20"`
+	Limit         int      `json:"limit,omitempty" validate:"omitempty,min=1,max=20"`
+	// Overwrite (auto-fill only) replaces locations already set on the line; by default only
+	// lines without a location are filled.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateQCHoldRequest is the body for POST /api/qc-holds. scope "lot" quarantines the lot
+// lot_number of sku in every location; scope "inventory" blocks qty of sku at location.
+type CreateQCHoldRequest struct {
+	Scope      string   `json:"scope" validate:"required,oneof=lot inventory"`
+	SKU        string   `json:"sku" validate:"required"`
+	LotNumber  *string  `json:"lot_number,omitempty" validate:"omitempty,min=1"`
+	Location   *string  `json:"location,omitempty" validate:"omitempty,min=1"`
+	Qty        *float64 `json:"qty,omitempty" validate:"omitempty,gt=0"`
+	Reason     string   `json:"reason" validate:"required,max=500"`
+	SourceType *string  `json:"source_type,omitempty" validate:"omitempty,max=30"`
+	SourceID   *string  `json:"source_id,omitempty" validate:"omitempty,max=80"`
+}
+
+// RecordQCInspectionRequest is the body for POST /api/qc-holds/:id/inspections.
+type RecordQCInspectionRequest struct {
+	Result       string          `json:"result" validate:"required,oneof=pass fail conditional"`
+	Notes        *string         `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Measurements json.RawMessage `json:"measurements,omitempty"`
+	InspectedAt  *time.Time      `json:"inspected_at,omitempty"`
+}
+
+// QCDecisionRequest is the optional body for PATCH /api/qc-holds/:id/release and /reject.
+type QCDecisionRequest struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,13 @@
+package requests
+
+// SerialTransitionRequest is the body for POST /api/serials/:id/transition. It moves a serial
+// through its lifecycle by hand (e.g. scrapping a damaged unit); received is only set when a
+// serial is created. The document fields link the change to the paper that backs it.
+type SerialTransitionRequest struct {
+	Status         string  `json:"status" validate:"required,oneof=in_stock reserved shipped returned scrapped"`
+	Location       *string `json:"location,omitempty" validate:"omitempty,max=100"`
+	DocumentType   *string `json:"document_type,omitempty" validate:"omitempty,max=50"`
+	DocumentID     *string `json:"document_id,omitempty" validate:"omitempty,max=80"`
+	DocumentNumber *string `json:"document_number,omitempty" validate:"omitempty,max=100"`
+	Notes          *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,26 @@
+package requests
+
+// PackageRequest is the body for POST /api/shipments/:id/packages and
+// PATCH /api/shipments/:id/packages/:packageId. package_type defaults to "carton" on create
+// and is left unchanged on update when omitted; omitted measures are left unchanged too.
+type PackageRequest struct {
+	PackageType string   `json:"package_type,omitempty" validate:"omitempty,oneof=carton pallet"`
+	WeightKg    *float64 `json:"weight_kg,omitempty" validate:"omitempty,gt=0"`
+	LengthCm    *float64 `json:"length_cm,omitempty" validate:"omitempty,gt=0"`
+	WidthCm     *float64 `json:"width_cm,omitempty" validate:"omitempty,gt=0"`
+	HeightCm    *float64 `json:"height_cm,omitempty" validate:"omitempty,gt=0"`
+}
+
+// AddPackageContentRequest is the body for POST /api/shipments/:id/packages/:packageId/contents.
+// It packs qty of a shipment line into the package; serial_numbers must be among the serials
+// picked for the line's SKU that are not packed yet (at most qty of them).
+type AddPackageContentRequest struct {
+	ShipmentLineID string   `json:"shipment_line_id" validate:"required"`
+	Qty            *float64 `json:"qty" validate:"required,gt=0"`
+	SerialNumbers  []string `json:"serial_numbers,omitempty" validate:"omitempty,dive,required"`
+}
+
+// CloseShipmentRequest is the optional body for PATCH /api/shipments/:id/close.
+type CloseShipmentRequest struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
@@ -1,10 +1,13 @@
 package requests
 
 // StockTransferLineInput is a line for create/update (SKU, quantity, optional presentation).
+// A quantity given in a presentation other than the article's is stored in base units.
 type StockTransferLineInput struct {
-	Sku         string   `json:"sku" binding:"required"`
-	Quantity    float64  `json:"quantity" binding:"required" validate:"required,gt=0"`
+	Sku          string  `json:"sku" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required" validate:"required,gt=0"`
 	Presentation *string `json:"presentation"`
+	// PresentationFactor is set server-side when Quantity is converted to base units.
+	PresentationFactor *float64 `json:"-"`
 }
 
 // StockTransferCreate is the request body for creating a stock transfer (header + lines).
@@ -29,7 +32,23 @@ type StockTransferUpdate struct {
 
 // StockTransferLineUpdate is the request body for updating a transfer line.
 type StockTransferLineUpdate struct {
-	Quantity    float64  `json:"quantity" validate:"gt=0"`
+	Quantity     float64 `json:"quantity" validate:"gt=0"`
 	Presentation *string `json:"presentation"`
-	LineStatus  string   `json:"line_status" validate:"omitempty,oneof=pending picked received cancelled"`
+	LineStatus   string  `json:"line_status" validate:"omitempty,oneof=pending picked received cancelled"`
+	// PresentationFactor is set server-side when Quantity is converted to base units.
+	PresentationFactor *float64 `json:"-"`
+}
+
+// ReceiveStockTransferLine confirms the quantity that arrived for one shipped transfer line.
+// Close marks the line as final even when less than the shipped quantity arrived (short receipt).
+type ReceiveStockTransferLine struct {
+	LineID      string  `json:"line_id" validate:"required"`
+	ReceivedQty float64 `json:"received_qty" validate:"gte=0"`
+	Close       bool    `json:"close"`
+	Reason      *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// ReceiveStockTransferRequest is the request body for confirming receipt of an in-transit transfer.
+type ReceiveStockTransferRequest struct {
+	Lines []ReceiveStockTransferLine `json:"lines" validate:"required,min=1,dive"`
 }
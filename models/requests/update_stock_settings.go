@@ -1,7 +1,7 @@
 package requests
 
 type UpdateStockSettingsRequest struct {
-	ValuationMethod           string  `json:"valuation_method" binding:"required" validate:"required,oneof=avco fifo"`
+	ValuationMethod           string  `json:"valuation_method" binding:"required" validate:"required,oneof=avco fifo lifo"`
 	PickBatchBasedOn          string  `json:"pick_batch_based_on" binding:"required" validate:"required,oneof=fefo fifo lifo"`
 	OverReceiptAllowancePct   float64 `json:"over_receipt_allowance_pct" validate:"gte=0,lte=100"`
 	OverDeliveryAllowancePct  float64 `json:"over_delivery_allowance_pct" validate:"gte=0,lte=100"`
@@ -11,4 +11,11 @@ type UpdateStockSettingsRequest struct {
 	ExpiryAlertDays           int     `json:"expiry_alert_days" validate:"gte=0"`
 	AutoCreateMaterialRequest bool    `json:"auto_create_material_request"`
 	PartialDeliveryPolicy     string  `json:"partial_delivery_policy" binding:"required" validate:"required,oneof=immediate when_all_ready"`
+	// BaseCurrency is optional; when omitted the tenant keeps its current base currency.
+	BaseCurrency      string `json:"base_currency,omitempty" validate:"omitempty,iso4217"`
+	RequirePacking    bool   `json:"require_packing"`
+	RequireLotRelease bool   `json:"require_lot_release"`
+	// Number series patterns for generated lot and serial numbers; empty uses the defaults.
+	LotNumberPattern    *string `json:"lot_number_pattern,omitempty" validate:"omitempty,max=100"`
+	SerialNumberPattern *string `json:"serial_number_pattern,omitempty" validate:"omitempty,max=100"`
 }
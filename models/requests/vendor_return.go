@@ -0,0 +1,36 @@
+package requests
+
+import "time"
+
+// CreateVendorReturnRequest is the body for POST /api/vendor-returns. Exactly one of
+// purchase_order_id or receiving_task_id is required. Without lines the return takes every
+// rejected quantity not yet on another vendor return.
+type CreateVendorReturnRequest struct {
+	PurchaseOrderID *string                   `json:"purchase_order_id,omitempty" validate:"omitempty,min=1"`
+	ReceivingTaskID *string                   `json:"receiving_task_id,omitempty" validate:"omitempty,min=1"`
+	Notes           *string                   `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Lines           []VendorReturnLineRequest `json:"lines,omitempty" validate:"omitempty,dive"`
+}
+
+// VendorReturnLineRequest is a rejected quantity of a SKU to send back.
+type VendorReturnLineRequest struct {
+	ArticleSKU string   `json:"article_sku" validate:"required"`
+	Qty        *float64 `json:"qty" validate:"required,gt=0"`
+	Reason     *string  `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// ShipVendorReturnRequest is the optional body for PATCH /api/vendor-returns/:id/ship.
+// shipped_at defaults to now.
+type ShipVendorReturnRequest struct {
+	Carrier        *string    `json:"carrier,omitempty" validate:"omitempty,max=100"`
+	TrackingNumber *string    `json:"tracking_number,omitempty" validate:"omitempty,max=100"`
+	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+}
+
+// CreditVendorReturnRequest is the body for PATCH /api/vendor-returns/:id/credit: the
+// supplier's credit note. credited_at defaults to now.
+type CreditVendorReturnRequest struct {
+	CreditNoteNumber string     `json:"credit_note_number" validate:"required,max=100"`
+	CreditedAmount   *float64   `json:"credited_amount" validate:"required,gte=0"`
+	CreditedAt       *time.Time `json:"credited_at,omitempty"`
+}
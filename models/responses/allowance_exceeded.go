@@ -0,0 +1,22 @@
+package responses
+
+// Allowance rules checked against the per-tenant tolerances in stock_settings.
+const (
+	AllowanceOverReceipt  = "over_receipt"
+	AllowanceOverPicking  = "over_picking"
+	AllowanceOverDelivery = "over_delivery"
+)
+
+// AllowanceExceeded is the structured detail of a 409 returned when a quantity is above
+// its base quantity plus the tenant allowance. It travels in InternalResponse.Details and
+// is sent as the response data so clients can show how far over the limit the operation is.
+type AllowanceExceeded struct {
+	Rule          string  `json:"rule"`
+	SKU           string  `json:"sku"`
+	Location      string  `json:"location,omitempty"`
+	BaseQty       float64 `json:"base_qty"` // expected, allocated or ordered quantity
+	AllowancePct  float64 `json:"allowance_pct"`
+	MaxAllowedQty float64 `json:"max_allowed_qty"`
+	RequestedQty  float64 `json:"requested_qty"`
+	ExcessQty     float64 `json:"excess_qty"` // RequestedQty - MaxAllowedQty
+}
@@ -45,4 +45,8 @@ type ArticleResponse struct {
 	DefaultLocation    *EmbeddedLocation `json:"default_location,omitempty"`
 	ReceivingNotes     *string           `json:"receiving_notes,omitempty"`
 	ShippingNotes      *string           `json:"shipping_notes,omitempty"`
+	WeightKg           *float64          `json:"weight_kg,omitempty"`
+	LengthCm           *float64          `json:"length_cm,omitempty"`
+	WidthCm            *float64          `json:"width_cm,omitempty"`
+	HeightCm           *float64          `json:"height_cm,omitempty"`
 }
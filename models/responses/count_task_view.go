@@ -0,0 +1,43 @@
+package responses
+
+import "time"
+
+// CountTaskLineView is a count line as returned to the client.
+// ExpectedQty and Variance are nil while a blind task is still being counted.
+type CountTaskLineView struct {
+	ID           string     `json:"id"`
+	SKU          string     `json:"sku"`
+	Location     string     `json:"location"`
+	ExpectedQty  *float64   `json:"expected_qty,omitempty"`
+	CountedQty   *float64   `json:"counted_qty,omitempty"`
+	RecountQty   *float64   `json:"recount_qty,omitempty"`
+	Variance     *float64   `json:"variance,omitempty"`
+	Status       string     `json:"status"`
+	CountedBy    *string    `json:"counted_by,omitempty"`
+	CountedAt    *time.Time `json:"counted_at,omitempty"`
+	RecountedBy  *string    `json:"recounted_by,omitempty"`
+	RecountedAt  *time.Time `json:"recounted_at,omitempty"`
+	Notes        *string    `json:"notes,omitempty"`
+	AdjustmentID *string    `json:"adjustment_id,omitempty"`
+}
+
+// CountTaskView is the response shape for count task endpoints (header + lines).
+type CountTaskView struct {
+	ID          string              `json:"id"`
+	TaskNumber  string              `json:"task_number"`
+	CountPlanID *string             `json:"count_plan_id,omitempty"`
+	Status      string              `json:"status"`
+	Blind       bool                `json:"blind"`
+	AssignedTo  *string             `json:"assigned_to,omitempty"`
+	Notes       *string             `json:"notes,omitempty"`
+	CreatedBy   *string             `json:"created_by,omitempty"`
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	SubmittedAt *time.Time          `json:"submitted_at,omitempty"`
+	ApprovedBy  *string             `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time          `json:"approved_at,omitempty"`
+	CancelledAt *time.Time          `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	TotalLines  int                 `json:"total_lines"`
+	Lines       []CountTaskLineView `json:"lines,omitempty"`
+}
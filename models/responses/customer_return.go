@@ -0,0 +1,20 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// CustomerReturnLineView is a return line with its inspections and what is left to inspect.
+type CustomerReturnLineView struct {
+	database.CustomerReturnLine
+	PendingQty  float64                             `json:"pending_qty"`
+	Inspections []database.CustomerReturnInspection `json:"inspections"`
+}
+
+// CustomerReturnView is a customer return with its lines and the numbers of the documents it
+// refers to.
+type CustomerReturnView struct {
+	database.CustomerReturn
+	SONumber     string                   `json:"so_number,omitempty"`
+	DNNumber     *string                  `json:"dn_number,omitempty"`
+	CustomerName *string                  `json:"customer_name,omitempty"`
+	Lines        []CustomerReturnLineView `json:"lines"`
+}
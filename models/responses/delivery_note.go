@@ -11,6 +11,12 @@ type DeliveryNoteItemResponse struct {
 	LotNumbers     []string   `json:"lot_numbers,omitempty"`
 	Notes          *string    `json:"notes,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
+	// PackageID and SSCC identify the package the item was packed in, when the tenant packs.
+	PackageID *string `json:"package_id,omitempty"`
+	SSCC      *string `json:"sscc,omitempty"`
+	// Presentation the order line was entered in and base units per unit of it.
+	Presentation       *string  `json:"presentation,omitempty"`
+	PresentationFactor *float64 `json:"presentation_factor,omitempty"`
 }
 
 // DeliveryNoteResponse is the full response for a single delivery note (header + items).
@@ -29,6 +35,18 @@ type DeliveryNoteResponse struct {
 	Items          []DeliveryNoteItemResponse `json:"items"`
 	CreatedAt      time.Time                  `json:"created_at"`
 	UpdatedAt      time.Time                  `json:"updated_at"`
+	// Proof of delivery: signature and photos are served by GET /api/delivery-notes/:id/pod/:file.
+	PODSignatureURL *string  `json:"pod_signature_url,omitempty"`
+	PODPhotoURLs    []string `json:"pod_photo_urls,omitempty"`
+	PODGPS          *string  `json:"pod_gps,omitempty"`
+	PODNotes        *string  `json:"pod_notes,omitempty"`
+	PODRecordedBy   *string  `json:"pod_recorded_by,omitempty"`
+	// PdfSHA256 is the checksum of the stored PDF.
+	PdfSHA256 *string `json:"pdf_sha256,omitempty"`
+	// Document storage keys behind pdf_url and the POD URLs above.
+	PdfStorageKey    *string  `json:"-"`
+	PODSignaturePath *string  `json:"-"`
+	PODPhotoPaths    []string `json:"-"`
 }
 
 // DeliveryNoteListItem is the lightweight row used in list responses.
@@ -18,7 +18,8 @@ type InternalResponse struct {
 	Error      error
 	Message    string
 	Handled    bool
-	StatusCode int // optional: 400, 404, 409, 500, etc.; 0 = use Handled for legacy 200/400
+	StatusCode int         // optional: 400, 404, 409, 500, etc.; 0 = use Handled for legacy 200/400
+	Details    interface{} // optional: structured detail sent as response data (e.g. *AllowanceExceeded)
 }
 
 func InternalErrorResponse(err error, message string, handled bool) InternalResponse {
@@ -6,6 +6,8 @@ type ValuationBreakdownItem struct {
 	Label string  `json:"label"`
 	Value float64 `json:"value"`
 	Qty   float64 `json:"qty"`
+	// RequestedValue is Value in the requested currency (only when one was requested).
+	RequestedValue *float64 `json:"requested_value,omitempty"`
 }
 
 // InventoryValuationResponse is the response for GET /api/inventory/valuation.
@@ -14,4 +16,8 @@ type InventoryValuationResponse struct {
 	Currency   string                   `json:"currency"`
 	GroupBy    string                   `json:"group_by"`
 	Breakdown  []ValuationBreakdownItem `json:"breakdown"`
+	// Requested* are set when ?currency= asks for a currency other than the base (Currency).
+	RequestedCurrency   string   `json:"requested_currency,omitempty"`
+	ExchangeRate        *float64 `json:"exchange_rate,omitempty"`
+	RequestedTotalValue *float64 `json:"requested_total_value,omitempty"`
 }
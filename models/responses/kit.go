@@ -0,0 +1,27 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// KitView is a kit with its components. AvailableQty is the assembled stock available to pick;
+// BuildableQty how many more kits the available components allow.
+type KitView struct {
+	database.Kit
+	KitName      *string            `json:"kit_name,omitempty"`
+	Components   []KitComponentView `json:"components"`
+	AvailableQty float64            `json:"available_qty"`
+	BuildableQty float64            `json:"buildable_qty"`
+}
+
+// KitComponentView is a kit component with its name and available stock.
+type KitComponentView struct {
+	database.KitComponent
+	ComponentName *string `json:"component_name,omitempty"`
+	AvailableQty  float64 `json:"available_qty"`
+}
+
+// AssemblyOrderView is an assembly order with its component lines.
+type AssemblyOrderView struct {
+	database.AssemblyOrder
+	KitName *string                      `json:"kit_name,omitempty"`
+	Lines   []database.AssemblyOrderLine `json:"lines"`
+}
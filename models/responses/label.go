@@ -0,0 +1,23 @@
+package responses
+
+import "time"
+
+// Label kinds.
+const (
+	LabelKindLocation = "location"
+	LabelKindArticle  = "article"
+	LabelKindLot      = "lot"
+	LabelKindSerial   = "serial"
+)
+
+// LabelItem is the data printed on one label. Code is the location code, SKU, lot number or
+// serial number depending on Kind; Name is the article name (or location description).
+type LabelItem struct {
+	Kind           string     `json:"kind"`
+	ID             string     `json:"id"`
+	Code           string     `json:"code"`
+	SKU            string     `json:"sku,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	Zone           string     `json:"zone,omitempty"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+}
@@ -0,0 +1,42 @@
+package responses
+
+// Capacity dimensions checked against location limits.
+const (
+	CapacityUnits  = "units"
+	CapacityWeight = "weight"
+	CapacityVolume = "volume"
+)
+
+// CapacityExceeded is the structured detail of a 409 returned when stock put into a
+// location would take it over one of its limits (max units, weight or volume). It travels
+// in InternalResponse.Details like AllowanceExceeded.
+type CapacityExceeded struct {
+	Location  string  `json:"location"`
+	Dimension string  `json:"dimension"`
+	Capacity  float64 `json:"capacity"`
+	Current   float64 `json:"current"`
+	Incoming  float64 `json:"incoming"`
+	Projected float64 `json:"projected"` // Current + Incoming
+}
+
+// LocationUtilization is one row of the location utilization report. Max* are the
+// effective limits (location value, else its type's); nil means unlimited and the
+// matching percentage is omitted.
+type LocationUtilization struct {
+	LocationID     string   `json:"location_id"`
+	LocationCode   string   `json:"location_code"`
+	Zone           *string  `json:"zone,omitempty"`
+	Type           string   `json:"type"`
+	IsActive       bool     `json:"is_active"`
+	MaxUnits       *float64 `json:"max_units,omitempty"`
+	MaxWeightKg    *float64 `json:"max_weight_kg,omitempty"`
+	MaxVolumeM3    *float64 `json:"max_volume_m3,omitempty"`
+	UsedUnits      float64  `json:"used_units"`
+	UsedWeightKg   float64  `json:"used_weight_kg"`
+	UsedVolumeM3   float64  `json:"used_volume_m3"`
+	UnitsPct       *float64 `json:"units_pct,omitempty"`
+	WeightPct      *float64 `json:"weight_pct,omitempty"`
+	VolumePct      *float64 `json:"volume_pct,omitempty"`
+	UtilizationPct *float64 `json:"utilization_pct,omitempty"` // highest of the three
+	OverCapacity   bool     `json:"over_capacity"`
+}
@@ -0,0 +1,72 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+)
+
+// LotRecallView is a recall with its tasks and what the recalled lot reached.
+type LotRecallView struct {
+	database.LotRecall
+	ArticleName    *string                  `json:"article_name,omitempty"`
+	ExpirationDate *time.Time               `json:"expiration_date,omitempty"`
+	Tasks          []database.LotRecallTask `json:"tasks"`
+	Affected       LotRecallAffected        `json:"affected"`
+}
+
+// LotRecallAffected lists the delivery notes that shipped the lot, grouped by customer and
+// sales order, and the open picking tasks holding it (blocked until the recall ends).
+type LotRecallAffected struct {
+	Customers     []RecallCustomer    `json:"customers"`
+	SalesOrders   []RecallSalesOrder  `json:"sales_orders"`
+	DeliveryNotes []RecallDelivery    `json:"delivery_notes"`
+	PickingTasks  []RecallPickingTask `json:"picking_tasks"`
+	ShippedQty    float64             `json:"shipped_qty"`
+}
+
+// RecallDelivery is a delivery note line that shipped the lot. MixedLots is set when the line
+// also carried other lots, so Qty is an upper bound of the lot's quantity.
+type RecallDelivery struct {
+	DeliveryNoteID string     `json:"delivery_note_id"`
+	DNNumber       string     `json:"dn_number"`
+	SalesOrderID   string     `json:"sales_order_id"`
+	SONumber       *string    `json:"so_number,omitempty"`
+	CustomerID     string     `json:"customer_id"`
+	CustomerCode   *string    `json:"customer_code,omitempty"`
+	CustomerName   *string    `json:"customer_name,omitempty"`
+	CustomerEmail  *string    `json:"customer_email,omitempty"`
+	CustomerPhone  *string    `json:"customer_phone,omitempty"`
+	Qty            float64    `json:"qty"`
+	MixedLots      bool       `json:"mixed_lots"`
+	ShippedAt      time.Time  `json:"shipped_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// RecallCustomer is a customer that received the lot.
+type RecallCustomer struct {
+	CustomerID    string  `json:"customer_id"`
+	Code          *string `json:"code,omitempty"`
+	Name          *string `json:"name,omitempty"`
+	Email         *string `json:"email,omitempty"`
+	Phone         *string `json:"phone,omitempty"`
+	Qty           float64 `json:"qty"`
+	DeliveryNotes int     `json:"delivery_notes"`
+}
+
+// RecallSalesOrder is a sales order shipped (partly) from the lot.
+type RecallSalesOrder struct {
+	SalesOrderID string  `json:"sales_order_id"`
+	SONumber     *string `json:"so_number,omitempty"`
+	CustomerID   string  `json:"customer_id"`
+	Qty          float64 `json:"qty"`
+}
+
+// RecallPickingTask is an open picking task that allocates the lot.
+type RecallPickingTask struct {
+	ID           string  `json:"id"`
+	TaskID       string  `json:"task_id"`
+	Status       string  `json:"status"`
+	SalesOrderID *string `json:"sales_order_id,omitempty"`
+	SONumber     *string `json:"so_number,omitempty"`
+}
@@ -0,0 +1,23 @@
+package responses
+
+// MovementCostConsumption is one cost layer drawn by an outbound movement.
+// LayerID is nil for quantity that left without a layer behind it.
+type MovementCostConsumption struct {
+	LayerID         *string `json:"layer_id,omitempty"`
+	LayerReceivedAt *string `json:"layer_received_at,omitempty"`
+	Quantity        float64 `json:"quantity"`
+	UnitCost        float64 `json:"unit_cost"`
+	Total           float64 `json:"total"`
+}
+
+// MovementCOGS is the response for GET /api/inventory-movements/:id/cogs: the cost of
+// goods of an outbound movement and the cost layers it consumed.
+type MovementCOGS struct {
+	MovementID   string                    `json:"movement_id"`
+	SKU          string                    `json:"sku"`
+	MovementType string                    `json:"movement_type"`
+	Quantity     float64                   `json:"quantity"`
+	COGS         float64                   `json:"cogs"`
+	UnitCost     float64                   `json:"unit_cost"`
+	Consumptions []MovementCostConsumption `json:"consumptions"`
+}
@@ -0,0 +1,14 @@
+package responses
+
+// NumberSeriesPreview is the next numbers a lot or serial series will hand out. Source is
+// where the pattern came from: request, article, tenant or default. LastValue is the counter
+// of SeriesKey (0 when the series has not been used yet).
+type NumberSeriesPreview struct {
+	Kind      string   `json:"kind"`
+	SKU       string   `json:"sku,omitempty"`
+	Pattern   string   `json:"pattern"`
+	Source    string   `json:"source"`
+	SeriesKey string   `json:"series_key"`
+	LastValue int64    `json:"last_value"`
+	Next      []string `json:"next"`
+}
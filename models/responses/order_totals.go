@@ -0,0 +1,11 @@
+package responses
+
+// OrderTotals is the value of an order's lines (qty × unit price/cost) converted to the
+// tenant base currency, and optionally to a requested currency. Lines without a price count as 0.
+type OrderTotals struct {
+	BaseCurrency      string   `json:"base_currency"`
+	Total             float64  `json:"total"`
+	RequestedCurrency string   `json:"requested_currency,omitempty"`
+	ExchangeRate      *float64 `json:"exchange_rate,omitempty"`
+	RequestedTotal    *float64 `json:"requested_total,omitempty"`
+}
@@ -0,0 +1,37 @@
+package responses
+
+// LocationPickPath is where a location sits on the operator's walk (migration 000045).
+type LocationPickPath struct {
+	LocationCode string  `gorm:"column:location_code" json:"location_code"`
+	Zone         *string `gorm:"column:zone" json:"zone,omitempty"`
+	Aisle        *string `gorm:"column:aisle" json:"aisle,omitempty"`
+	Rack         *string `gorm:"column:rack" json:"rack,omitempty"`
+	Level        *string `gorm:"column:level" json:"level,omitempty"`
+	PickSequence *int    `gorm:"column:pick_sequence" json:"pick_sequence,omitempty"`
+}
+
+// PickRouteLine is one allocation to pick at a stop.
+type PickRouteLine struct {
+	SKU            string   `json:"sku"`
+	LotNumber      *string  `json:"lot_number,omitempty"`
+	ExpirationDate *string  `json:"expiration_date,omitempty"`
+	Quantity       float64  `json:"quantity"`
+	PickedQty      *float64 `json:"picked_qty,omitempty"`
+	Status         string   `json:"status"` // pending | picked | skipped
+}
+
+// PickRouteStop is one location visit on the route, numbered from 1.
+type PickRouteStop struct {
+	Stop     int              `json:"stop"`
+	Location LocationPickPath `json:"location"`
+	Lines    []PickRouteLine  `json:"lines"`
+}
+
+// PickRoute is a picking task's allocations grouped by location and ordered along the walk.
+type PickRoute struct {
+	PickingTaskID string          `json:"picking_task_id"`
+	TaskID        string          `json:"task_id"`
+	Status        string          `json:"status"`
+	TotalStops    int             `json:"total_stops"`
+	Stops         []PickRouteStop `json:"stops"`
+}
@@ -0,0 +1,80 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+)
+
+// PickingWaveTaskView is a member task of a wave, in sort order.
+type PickingWaveTaskView struct {
+	PickingTaskID string     `json:"picking_task_id"`
+	TaskID        string     `json:"task_id"`
+	OrderNumber   string     `json:"order_number"`
+	Status        string     `json:"status"`
+	Priority      string     `json:"priority"`
+	CustomerID    *string    `json:"customer_id,omitempty"`
+	SalesOrderID  *string    `json:"sales_order_id,omitempty"`
+	ExpectedDate  *time.Time `json:"expected_date,omitempty"`
+	Sequence      int        `json:"sequence"`
+	Active        bool       `json:"active"`
+}
+
+// PickingWaveView is the response shape for wave endpoints (header + member tasks).
+type PickingWaveView struct {
+	Wave  *database.PickingWave `json:"wave"`
+	Tasks []PickingWaveTaskView `json:"tasks"`
+}
+
+// WavePickSortTo is the share of a consolidated pick line that belongs to one member task:
+// the operator sorts PickedQty of the line into that task's tote.
+type WavePickSortTo struct {
+	PickingTaskID string   `json:"picking_task_id"`
+	TaskID        string   `json:"task_id"`
+	OrderNumber   string   `json:"order_number"`
+	Sequence      int      `json:"sequence"`
+	Quantity      float64  `json:"quantity"`
+	PickedQty     *float64 `json:"picked_qty,omitempty"`
+	Status        string   `json:"status"`
+}
+
+// WavePickLine is one line of the consolidated pick list: everything the wave needs from a
+// location for one SKU (and lot). Status is pending until picked, then picked or short.
+// Stop is the 1-based position of the location on the pick path; lines of the same location
+// share it.
+type WavePickLine struct {
+	Stop           int              `json:"stop"`
+	Location       string           `json:"location"`
+	SKU            string           `json:"sku"`
+	LotNumber      *string          `json:"lot_number,omitempty"`
+	ExpirationDate *string          `json:"expiration_date,omitempty"`
+	Quantity       float64          `json:"quantity"`
+	PickedQty      *float64         `json:"picked_qty,omitempty"`
+	Status         string           `json:"status"`
+	SortTo         []WavePickSortTo `json:"sort_to"`
+}
+
+// WavePickList is the consolidated pick list of a wave, in walk order.
+type WavePickList struct {
+	WaveID     string         `json:"wave_id"`
+	WaveNumber string         `json:"wave_number"`
+	Status     string         `json:"status"`
+	TotalLines int            `json:"total_lines"`
+	Lines      []WavePickLine `json:"lines"`
+}
+
+// PickingWaveTaskResult reports what happened to one member task when a wave was started or
+// completed. Error is set (and Status left unchanged) when the task could not be processed.
+type PickingWaveTaskResult struct {
+	PickingTaskID string `json:"picking_task_id"`
+	TaskID        string `json:"task_id"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+}
+
+// PickingWaveActionResult is returned by wave start/complete: the wave after the action and
+// the per-task outcome.
+type PickingWaveActionResult struct {
+	Wave    *PickingWaveView        `json:"wave"`
+	Results []PickingWaveTaskResult `json:"results"`
+}
@@ -11,7 +11,11 @@ type PurchaseOrderItemView struct {
 	RejectedQty     float64  `json:"rejected_qty"`
 	Discrepancy     *float64 `json:"discrepancy,omitempty"`
 	UnitCost        *float64 `json:"unit_cost,omitempty"`
+	Currency        *string  `json:"currency,omitempty"`
 	Notes           *string  `json:"notes,omitempty"`
+	// Presentation the line was entered in and base units per unit of it.
+	Presentation       *string  `json:"presentation,omitempty"`
+	PresentationFactor *float64 `json:"presentation_factor,omitempty"`
 }
 
 // PurchaseOrderView is the response shape for purchase order endpoints.
@@ -31,6 +35,8 @@ type PurchaseOrderView struct {
 	CreatedAt       time.Time               `json:"created_at"`
 	UpdatedAt       time.Time               `json:"updated_at"`
 	Items           []PurchaseOrderItemView `json:"items,omitempty"`
+	// Totals is filled on GetByID when exchange rates are available.
+	Totals *OrderTotals `json:"totals,omitempty"`
 	// Tenant isolation — never leak UUID in HTTP responses.
 	TenantID string `json:"-" gorm:"column:tenant_id"`
 }
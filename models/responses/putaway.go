@@ -0,0 +1,36 @@
+package responses
+
+// PutawaySuggestion is one ranked destination for a received line. Reasons lists the rules
+// that contributed to Score, in the order they were applied.
+type PutawaySuggestion struct {
+	LocationID   string   `json:"location_id"`
+	LocationCode string   `json:"location_code"`
+	Zone         *string  `json:"zone,omitempty"`
+	Type         string   `json:"type"`
+	Score        float64  `json:"score"`
+	Reasons      []string `json:"reasons"`
+	// SKUQuantity is what the location already holds of the line's SKU; Occupancy is its
+	// total units across all SKUs.
+	SKUQuantity float64 `json:"sku_quantity"`
+	Occupancy   float64 `json:"occupancy"`
+	// FreeCapacityPct is the share of the tightest capped dimension still free after the
+	// line is put away; omitted for locations without capacity limits.
+	FreeCapacityPct *float64 `json:"free_capacity_pct,omitempty"`
+}
+
+// PutawayLineSuggestions is the ranked list for one receiving task item.
+type PutawayLineSuggestions struct {
+	SKU             string              `json:"sku"`
+	LotNumbers      []string            `json:"lot_numbers,omitempty"`
+	Quantity        float64             `json:"quantity"`
+	CurrentLocation string              `json:"current_location"`
+	Suggestions     []PutawaySuggestion `json:"suggestions"`
+	// Applied is set by the auto-fill endpoint when the line's location was replaced.
+	Applied bool `json:"applied"`
+}
+
+// PutawayResult is the putaway plan for a receiving task.
+type PutawayResult struct {
+	TaskID string                   `json:"task_id"`
+	Lines  []PutawayLineSuggestions `json:"lines"`
+}
@@ -0,0 +1,11 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// QCHoldView is a QC hold with its lot number, inspections and attachments.
+type QCHoldView struct {
+	database.QCHold
+	LotNumber   *string                 `json:"lot_number,omitempty"`
+	Inspections []database.QCInspection `json:"inspections"`
+	Attachments []database.QCAttachment `json:"attachments"`
+}
@@ -0,0 +1,38 @@
+package responses
+
+// ReplenishmentSuggestion is the reorder proposal for one article.
+// Projected = OnHand − Reserved + OpenPOQty. ReorderPoint = SafetyStock + DailyDemand × LeadTimeDays,
+// never below MinQuantity. SuggestedQty brings Projected up to TargetQty, rounded up to MinOrderQty.
+type ReplenishmentSuggestion struct {
+	SKU          string   `json:"sku"`
+	Name         string   `json:"name"`
+	OnHand       float64  `json:"on_hand"`
+	Reserved     float64  `json:"reserved"`
+	OpenPOQty    float64  `json:"open_po_qty"`
+	Projected    float64  `json:"projected"`
+	DailyDemand  float64  `json:"daily_demand"`
+	ReorderPoint float64  `json:"reorder_point"`
+	TargetQty    float64  `json:"target_qty"`
+	SuggestedQty float64  `json:"suggested_qty"`
+	SupplierID   *string  `json:"supplier_id,omitempty"`
+	SupplierName *string  `json:"supplier_name,omitempty"`
+	LeadTimeDays int      `json:"lead_time_days"`
+	UnitCost     *float64 `json:"unit_cost,omitempty"`
+}
+
+// ReplenishmentSupplierGroup is the set of suggestions that would become one draft PO.
+type ReplenishmentSupplierGroup struct {
+	SupplierID     string                    `json:"supplier_id"`
+	SupplierName   *string                   `json:"supplier_name,omitempty"`
+	LeadTimeDays   int                       `json:"lead_time_days"`
+	EstimatedTotal float64                   `json:"estimated_total"`
+	Items          []ReplenishmentSuggestion `json:"items"`
+}
+
+// ReplenishmentPreview is the response of GET /api/replenishment/suggestions.
+// Unassigned lists articles that need stock but have no supplier, so no PO can be drafted for them.
+type ReplenishmentPreview struct {
+	AutoCreateEnabled bool                         `json:"auto_create_enabled"`
+	Groups            []ReplenishmentSupplierGroup `json:"groups"`
+	Unassigned        []ReplenishmentSuggestion    `json:"unassigned"`
+}
@@ -19,11 +19,14 @@ type SalesOrderResponse struct {
 	CreatedBy     *string                       `json:"created_by,omitempty"`
 	SubmittedAt   *time.Time                    `json:"submitted_at,omitempty"`
 	CompletedAt   *time.Time                    `json:"completed_at,omitempty"`
+	DeliveredAt   *time.Time                    `json:"delivered_at,omitempty"`
 	CancelledAt   *time.Time                    `json:"cancelled_at,omitempty"`
 	PickingTaskID *string                       `json:"picking_task_id,omitempty"`
 	CreatedAt     time.Time                     `json:"created_at"`
 	UpdatedAt     time.Time                     `json:"updated_at"`
 	Items         []database.SalesOrderItem     `json:"items"`
+	// Totals is filled on GetByID when exchange rates are available.
+	Totals *OrderTotals `json:"totals,omitempty"`
 }
 
 // SalesOrderListItem is the lightweight view used in list responses.
@@ -36,6 +39,7 @@ type SalesOrderListItem struct {
 	ExpectedDate  *time.Time `json:"expected_date,omitempty"`
 	SubmittedAt   *time.Time `json:"submitted_at,omitempty"`
 	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
 	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
 	PickingTaskID *string    `json:"picking_task_id,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
@@ -0,0 +1,49 @@
+package responses
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+)
+
+// ScanElement is one decoded GS1 application identifier.
+type ScanElement struct {
+	AI    string `json:"ai"`
+	Value string `json:"value"`
+}
+
+// ScanArticle is the article a scan resolved to, with the tracking flags the client needs
+// to decide which lines to fill.
+type ScanArticle struct {
+	ID              string `json:"id"`
+	SKU             string `json:"sku"`
+	Name            string `json:"name"`
+	Presentation    string `json:"presentation"`
+	TrackByLot      bool   `json:"track_by_lot"`
+	TrackBySerial   bool   `json:"track_by_serial"`
+	TrackExpiration bool   `json:"track_expiration"`
+}
+
+// ScanParseResult is a decoded and resolved scan. LotRequest plugs into the lots of a
+// CompleteReceivingLine item, LotEntry into the lots of a CompletePickingLine item, and
+// Serial into the serials of either.
+type ScanParseResult struct {
+	Raw            string        `json:"raw"`
+	IsGS1          bool          `json:"is_gs1"`
+	Symbology      string        `json:"symbology,omitempty"`
+	Elements       []ScanElement `json:"elements"`
+	GTIN           *string       `json:"gtin,omitempty"`
+	SSCC           *string       `json:"sscc,omitempty"`
+	LotNumber      *string       `json:"lot_number,omitempty"`
+	ExpirationDate *string       `json:"expiration_date,omitempty"`
+	SerialNumber   *string       `json:"serial_number,omitempty"`
+	// Quantity is the count encoded in the scan (AI 30/37, else 1) times the pack quantity
+	// of the matched barcode.
+	Quantity   float64                    `json:"quantity"`
+	Resolved   bool                       `json:"resolved"`
+	MatchedBy  string                     `json:"matched_by,omitempty"` // gtin | barcode | sku
+	Article    *ScanArticle               `json:"article,omitempty"`
+	LotRequest *requests.CreateLotRequest `json:"lot_request,omitempty"`
+	LotEntry   *database.LotEntry         `json:"lot_entry,omitempty"`
+	Serial     *database.Serial           `json:"serial,omitempty"`
+	Warnings   []string                   `json:"warnings"`
+}
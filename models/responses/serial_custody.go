@@ -0,0 +1,43 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+)
+
+// SerialCustodyView is a serial with its full chain of custody: every status change in order,
+// where the unit is now and the last delivery note (and customer) it left on.
+type SerialCustodyView struct {
+	database.Serial
+	ArticleName     *string              `json:"article_name,omitempty"`
+	CurrentLocation *string              `json:"current_location,omitempty"`
+	LastDelivery    *SerialDelivery      `json:"last_delivery,omitempty"`
+	History         []SerialHistoryEntry `json:"history"`
+}
+
+// SerialHistoryEntry is a history row with the user that made the change and, for delivery
+// note entries, the sales order and customer it shipped to.
+type SerialHistoryEntry struct {
+	database.SerialHistory
+	UserName     *string    `json:"user_name,omitempty"`
+	SalesOrderID *string    `json:"sales_order_id,omitempty"`
+	SONumber     *string    `json:"so_number,omitempty"`
+	CustomerID   *string    `json:"customer_id,omitempty"`
+	CustomerCode *string    `json:"customer_code,omitempty"`
+	CustomerName *string    `json:"customer_name,omitempty"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+}
+
+// SerialDelivery is the delivery note a serial shipped on and who received it.
+type SerialDelivery struct {
+	DeliveryNoteID string     `json:"delivery_note_id"`
+	DNNumber       *string    `json:"dn_number,omitempty"`
+	SalesOrderID   *string    `json:"sales_order_id,omitempty"`
+	SONumber       *string    `json:"so_number,omitempty"`
+	CustomerID     *string    `json:"customer_id,omitempty"`
+	CustomerCode   *string    `json:"customer_code,omitempty"`
+	CustomerName   *string    `json:"customer_name,omitempty"`
+	ShippedAt      time.Time  `json:"shipped_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
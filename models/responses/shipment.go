@@ -0,0 +1,38 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// ShipmentLineView is a shipment line with how much of it is already packed.
+type ShipmentLineView struct {
+	database.ShipmentLine
+	PackedQty    float64 `json:"packed_qty"`
+	RemainingQty float64 `json:"remaining_qty"`
+}
+
+// PackageContentView is a package content with the SKU and lot of its shipment line.
+type PackageContentView struct {
+	database.PackageContent
+	ArticleSKU string  `json:"article_sku"`
+	LotNumber  *string `json:"lot_number,omitempty"`
+}
+
+// PackageView is a package with its contents. VolumeM3 is set when all three dimensions are.
+type PackageView struct {
+	database.Package
+	VolumeM3 *float64             `json:"volume_m3,omitempty"`
+	Contents []PackageContentView `json:"contents"`
+}
+
+// ShipmentView is a shipment with its lines, packages and totals. It is also the JSON form of
+// the packing list.
+type ShipmentView struct {
+	database.Shipment
+	SONumber      string             `json:"so_number,omitempty"`
+	CustomerName  *string            `json:"customer_name,omitempty"`
+	DNNumber      *string            `json:"dn_number,omitempty"`
+	Lines         []ShipmentLineView `json:"lines"`
+	Packages      []PackageView      `json:"packages"`
+	TotalPackages int                `json:"total_packages"`
+	TotalWeightKg float64            `json:"total_weight_kg"`
+	TotalVolumeM3 float64            `json:"total_volume_m3"`
+}
@@ -0,0 +1,22 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// StockTransferDiscrepancy describes a closed transfer line whose received quantity differs
+// from the shipped quantity. DiscrepancyQty is received minus shipped (negative = short).
+type StockTransferDiscrepancy struct {
+	LineID         string  `json:"line_id"`
+	Sku            string  `json:"sku"`
+	ShippedQty     float64 `json:"shipped_qty"`
+	ReceivedQty    float64 `json:"received_qty"`
+	DiscrepancyQty float64 `json:"discrepancy_qty"`
+	LineStatus     string  `json:"line_status"`
+	Reason         *string `json:"reason,omitempty"`
+}
+
+// StockTransferReceiptResult is returned after a receive confirmation.
+type StockTransferReceiptResult struct {
+	Transfer      *database.StockTransfer      `json:"transfer"`
+	Lines         []database.StockTransferLine `json:"lines"`
+	Discrepancies []StockTransferDiscrepancy   `json:"discrepancies"`
+}
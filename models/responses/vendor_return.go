@@ -0,0 +1,20 @@
+package responses
+
+import "github.com/eflowcr/eSTOCK_backend/models/database"
+
+// VendorReturnView is a vendor return with its lines, the numbers and supplier of the
+// documents it refers to and its credit: ExpectedCredit sums the lines that have a unit cost,
+// CreditDifference is credited minus expected once the credit note is recorded.
+type VendorReturnView struct {
+	database.VendorReturn
+	PONumber         *string                     `json:"po_number,omitempty"`
+	InboundNumber    *string                     `json:"inbound_number,omitempty"`
+	SupplierName     string                      `json:"supplier_name"`
+	SupplierAddress  *string                     `json:"supplier_address,omitempty"`
+	SupplierTaxID    *string                     `json:"supplier_tax_id,omitempty"`
+	Lines            []database.VendorReturnLine `json:"lines"`
+	TotalQty         float64                     `json:"total_qty"`
+	ExpectedCredit   float64                     `json:"expected_credit"`
+	Currency         *string                     `json:"currency,omitempty"`
+	CreditDifference *float64                    `json:"credit_difference,omitempty"`
+}
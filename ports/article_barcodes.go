@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// ArticleBarcodesRepository persists alternate article codes and answers the lookups the
+// scan parser needs. All methods are tenant-scoped.
+type ArticleBarcodesRepository interface {
+	// List returns the tenant's barcodes, optionally only those of sku.
+	List(tenantID, sku string) ([]database.ArticleBarcode, *responses.InternalResponse)
+	Create(tenantID string, req *requests.CreateArticleBarcodeRequest) (*database.ArticleBarcode, *responses.InternalResponse)
+	Delete(tenantID, id string) *responses.InternalResponse
+	// FindByCode returns the barcode row and its article; nil, nil, nil when the code is unknown.
+	FindByCode(tenantID, code string) (*database.ArticleBarcode, *database.Article, *responses.InternalResponse)
+	// FindArticleBySKU returns nil, nil when the tenant has no article with sku.
+	FindArticleBySKU(tenantID, sku string) (*database.Article, *responses.InternalResponse)
+	// FindLot / FindSerial return nil, nil when the lot or serial does not exist yet.
+	FindLot(tenantID, sku, lotNumber string) (*database.Lot, *responses.InternalResponse)
+	FindSerial(tenantID, sku, serialNumber string) (*database.Serial, *responses.InternalResponse)
+}
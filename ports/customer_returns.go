@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// CustomerReturnsRepository defines persistence operations for customer returns (RMA). All
+// operations are tenant-scoped.
+type CustomerReturnsRepository interface {
+	// ListReturns returns a tenant's returns with optional status / sales order filters and pagination.
+	ListReturns(tenantID string, status, salesOrderID *string, limit, offset int) ([]database.CustomerReturn, *responses.InternalResponse)
+
+	// GetReturn returns the return with its lines, their inspections and the SO / DN numbers.
+	GetReturn(id, tenantID string) (*responses.CustomerReturnView, *responses.InternalResponse)
+
+	// CreateReturn authorizes a return against a delivery note or sales order. Lines are checked
+	// against what was delivered minus what other open returns already claim.
+	CreateReturn(tenantID, userID string, req *requests.CreateCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse)
+
+	// ReceiveReturn records inspection results and posts their inventory movements: restock and
+	// quarantine put the goods (and their lots / serials) back in stock, scrap writes them off.
+	ReceiveReturn(id, tenantID, userID string, req *requests.ReceiveCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse)
+
+	// CancelReturn cancels an authorized return that has nothing received yet.
+	CancelReturn(id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse)
+}
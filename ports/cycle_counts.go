@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// CycleCountsRepository defines persistence operations for count plans and count tasks.
+// All operations are tenant-scoped. ApproveTask posts the variances to inventory as
+// count_reconcile adjustments, in the same transaction that approves the task.
+type CycleCountsRepository interface {
+	// CreatePlan inserts a new count plan. created_by and tenant_id are injected by the caller.
+	CreatePlan(tenantID, createdBy string, req *requests.CreateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse)
+
+	// GetPlanByID returns a non-deleted plan scoped to tenantID.
+	GetPlanByID(id, tenantID string) (*database.CountPlan, *responses.InternalResponse)
+
+	// ListPlans returns the non-deleted plans for a tenant, newest first.
+	ListPlans(tenantID string) ([]database.CountPlan, *responses.InternalResponse)
+
+	// UpdatePlan patches the mutable fields of a plan. Tasks already generated keep their snapshot.
+	UpdatePlan(id, tenantID string, req *requests.UpdateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse)
+
+	// SoftDeletePlan sets deleted_at on a plan, scoped to tenantID.
+	SoftDeletePlan(id, tenantID string) *responses.InternalResponse
+
+	// GenerateTask resolves the plan scope against inventory and creates an open count task
+	// with one line per SKU+location, snapshotting the current quantity as expected_qty.
+	GenerateTask(plan *database.CountPlan, createdBy string, req *requests.GenerateCountTaskRequest) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse)
+
+	// GetTaskByID returns a count task and its lines, scoped to tenantID.
+	GetTaskByID(id, tenantID string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse)
+
+	// ListTasks returns count tasks for a tenant with optional status filter and pagination.
+	ListTasks(tenantID string, status *string, limit, offset int) ([]database.CountTask, *responses.InternalResponse)
+
+	// TransitionTask moves a task to next, validating the count task state machine and
+	// stamping the matching timestamp (started_at, submitted_at, approved_at, cancelled_at).
+	TransitionTask(id, tenantID, next, userID string) (*database.CountTask, *responses.InternalResponse)
+
+	// SaveLineCount persists counted_qty/recount_qty/status/notes and the counter stamps of a line.
+	SaveLineCount(line *database.CountTaskLine) *responses.InternalResponse
+
+	// FlagLinesForRecount sends the given lines of a task back to recount_required (clears recount_qty).
+	FlagLinesForRecount(taskID string, lineIDs []string) *responses.InternalResponse
+
+	// ApproveTask locks a pending_approval task and, in one transaction, posts each counted line
+	// as a count_reconcile adjustment that brings the current on-hand quantity to the final
+	// count (gainReason / lossReason unless the task carries its own reason code), links the
+	// adjustments to the lines and marks the task approved.
+	ApproveTask(id, tenantID, userID, gainReason, lossReason string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse)
+}
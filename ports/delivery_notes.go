@@ -1,6 +1,9 @@
 package ports
 
-import "github.com/eflowcr/eSTOCK_backend/models/responses"
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
 
 // DeliveryNotesRepository defines persistence operations for delivery notes (DN1–DN3).
 type DeliveryNotesRepository interface {
@@ -10,9 +13,14 @@ type DeliveryNotesRepository interface {
 	// GetByID returns the full delivery note (header + items), scoped to tenantID.
 	GetByID(id, tenantID string) (*responses.DeliveryNoteResponse, *responses.InternalResponse)
 
-	// UpdatePDFURL sets pdf_url and pdf_generated_at after async PDF generation.
-	UpdatePDFURL(id, pdfURL string) *responses.InternalResponse
+	// UpdatePDFStorageKey records the document storage key and SHA-256 of the generated PDF
+	// and sets pdf_generated_at.
+	UpdatePDFStorageKey(id, storageKey, checksum string) *responses.InternalResponse
 
 	// GetDNNumber returns the dn_number for a given delivery note ID (used for PDF filename).
 	GetDNNumber(id, tenantID string) (string, *responses.InternalResponse)
+
+	// RecordPOD stores the proof of delivery on a delivery note that has none yet and moves the
+	// sales order to delivered when it was its last undelivered note. Returns the sales order.
+	RecordPOD(id, tenantID string, pod *database.ProofOfDelivery) (*database.SalesOrder, *responses.InternalResponse)
 }
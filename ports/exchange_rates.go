@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// ExchangeRatesRepository defines persistence for per-tenant dated exchange rates and the
+// lookups used to report amounts in the tenant base currency.
+type ExchangeRatesRepository interface {
+	List(tenantID string) ([]database.ExchangeRate, *responses.InternalResponse)
+	Upsert(tenantID, createdBy string, req *requests.UpsertExchangeRateRequest) (*database.ExchangeRate, *responses.InternalResponse)
+	Delete(id, tenantID string) *responses.InternalResponse
+	// BaseCurrency returns stock_settings.base_currency (DefaultCurrency when not configured).
+	BaseCurrency(tenantID string) (string, *responses.InternalResponse)
+	// GetRate returns how many units of to one unit of from was worth on asOf: the latest
+	// direct rate effective that day or earlier, else the inverse of the latest reverse rate.
+	// 1 when from == to; a handled 400 when the pair has no rate.
+	GetRate(tenantID, from, to string, asOf time.Time) (float64, *responses.InternalResponse)
+}
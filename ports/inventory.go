@@ -6,25 +6,30 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 )
 
-// InventoryRepository defines persistence operations for inventory.
+// InventoryRepository defines persistence operations for inventory. Every method is
+// scoped to the given tenant.
 type InventoryRepository interface {
-	GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
-	GetAllInventory() ([]*dto.EnhancedInventory, *responses.InternalResponse)
-	GetInventoryBySkuAndLocation(sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse)
-	CreateInventory(userId string, item *requests.CreateInventory) *responses.InternalResponse
-	UpdateInventory(item *requests.UpdateInventory) *responses.InternalResponse
-	DeleteInventory(sku, location string) *responses.InternalResponse
-	Trend(sku string) (*dto.ConsumptionTrend, *responses.InternalResponse)
-	ImportInventoryFromExcel(userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse)
-	ImportInventoryFromJSON(userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse)
-	ValidateImportRows(rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse)
-	ExportInventoryToExcel() ([]byte, *responses.InternalResponse)
-	GetInventoryLots(inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse)
-	GetInventorySerials(inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse)
-	CreateInventoryLot(id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse
-	DeleteInventoryLot(id string) *responses.InternalResponse
-	CreateInventorySerial(id string, input *requests.CreateInventorySerial) *responses.InternalResponse
-	DeleteInventorySerial(id string) *responses.InternalResponse
+	GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
+	// GetPickSuggestionsFewestStops keeps FEFO across expirations but, among locations with the
+	// same expiration, prefers the allocation that visits the fewest locations.
+	GetPickSuggestionsFewestStops(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
+	GetAllInventory(tenantID string) ([]*dto.EnhancedInventory, *responses.InternalResponse)
+	GetInventoryBySkuAndLocation(tenantID, sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse)
+	CreateInventory(tenantID, userId string, item *requests.CreateInventory) *responses.InternalResponse
+	UpdateInventory(tenantID string, item *requests.UpdateInventory) *responses.InternalResponse
+	DeleteInventory(tenantID, sku, location string) *responses.InternalResponse
+	Trend(tenantID, sku string) (*dto.ConsumptionTrend, *responses.InternalResponse)
+	ImportInventoryFromExcel(tenantID, userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse)
+	ImportInventoryFromJSON(tenantID, userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse)
+	ValidateImportRows(tenantID string, rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse)
+	ExportInventoryToExcel(tenantID string) ([]byte, *responses.InternalResponse)
+	GetInventoryLots(tenantID, inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse)
+	GetInventorySerials(tenantID, inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse)
+	CreateInventoryLot(tenantID, id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse
+	DeleteInventoryLot(tenantID, id string) *responses.InternalResponse
+	CreateInventorySerial(tenantID, id string, input *requests.CreateInventorySerial) *responses.InternalResponse
+	DeleteInventorySerial(tenantID, id string) *responses.InternalResponse
 	GenerateImportTemplate(language string) ([]byte, error)
-	GetValuation(groupBy string) (*responses.InventoryValuationResponse, *responses.InternalResponse)
+	GetValuation(tenantID, groupBy string) (*responses.InventoryValuationResponse, *responses.InternalResponse)
+	ExportValuationToExcel(tenantID, groupBy string) ([]byte, *responses.InternalResponse)
 }
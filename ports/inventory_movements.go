@@ -23,4 +23,5 @@ type MovementsFilter struct {
 type InventoryMovementsRepository interface {
 	GetAllInventoryMovements(sku string) ([]database.InventoryMovement, *responses.InternalResponse)
 	ListMovements(f MovementsFilter) ([]database.InventoryMovement, *responses.InternalResponse)
+	GetMovementCOGS(id string) (*responses.MovementCOGS, *responses.InternalResponse)
 }
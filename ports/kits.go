@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// KitsRepository defines persistence operations for kit bills of materials and assembly
+// orders. All operations are tenant-scoped.
+type KitsRepository interface {
+	// ListKits returns the tenant's kits with their components and stock.
+	ListKits(tenantID string) ([]responses.KitView, *responses.InternalResponse)
+
+	// GetKit returns the kit of an article with its components and stock.
+	GetKit(tenantID, kitSKU string) (*responses.KitView, *responses.InternalResponse)
+
+	// UpsertKit creates the kit of an article or replaces its components.
+	UpsertKit(tenantID, kitSKU, userID string, req *requests.UpsertKitRequest) (*responses.KitView, *responses.InternalResponse)
+
+	// DeleteKit removes a kit without draft assembly orders.
+	DeleteKit(tenantID, kitSKU string) *responses.InternalResponse
+
+	// ListAssemblyOrders returns a tenant's assembly orders with optional status / type / kit
+	// filters and pagination.
+	ListAssemblyOrders(tenantID string, status, orderType, kitSKU *string, limit, offset int) ([]database.AssemblyOrder, *responses.InternalResponse)
+
+	// GetAssemblyOrder returns an assembly order with its lines.
+	GetAssemblyOrder(id, tenantID string) (*responses.AssemblyOrderView, *responses.InternalResponse)
+
+	// CreateAssemblyOrder creates a draft order whose lines take the kit's current components.
+	CreateAssemblyOrder(tenantID, userID string, req *requests.CreateAssemblyOrderRequest) (*responses.AssemblyOrderView, *responses.InternalResponse)
+
+	// CompleteAssemblyOrder moves the stock of a draft order: an assembly consumes the
+	// components (FEFO lots) and produces the kits at their cost, a disassembly the reverse.
+	CompleteAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse)
+
+	// CancelAssemblyOrder cancels a draft order.
+	CancelAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/eflowcr/eSTOCK_backend/models/responses"
+
+// LabelsRepository loads the data printed on barcode labels. All lookups are tenant-scoped;
+// an id that does not exist in the tenant is a handled 404.
+type LabelsRepository interface {
+	// GetLabelItems returns one item per id, in the order requested.
+	GetLabelItems(tenantID, kind string, ids []string) ([]responses.LabelItem, *responses.InternalResponse)
+	// GetReceivingTaskLabelItems returns the labels for a completed receiving task: one per
+	// received lot and serial, and one article label for items tracked by neither.
+	GetReceivingTaskLabelItems(tenantID, taskID string) ([]responses.LabelItem, *responses.InternalResponse)
+}
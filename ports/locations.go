@@ -21,4 +21,7 @@ type LocationsRepository interface {
 	ValidateImportRows(tenantID string, rows []requests.LocationImportRow) ([]responses.LocationValidationResult, *responses.InternalResponse)
 	ExportLocationsToExcel(tenantID string) ([]byte, *responses.InternalResponse)
 	GenerateImportTemplate(language string) ([]byte, error)
+	// GetLocationUtilization returns every location with its effective capacity limits and
+	// the units, weight and volume currently stored in it (percentages are left to the service).
+	GetLocationUtilization(tenantID string) ([]responses.LocationUtilization, *responses.InternalResponse)
 }
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// LotRecallsRepository defines persistence operations for lot recalls. All operations are
+// tenant-scoped.
+type LotRecallsRepository interface {
+	// ListRecalls returns a tenant's recalls with optional status / SKU filters and pagination.
+	ListRecalls(tenantID string, status, sku *string, limit, offset int) ([]database.LotRecall, *responses.InternalResponse)
+
+	// GetRecall returns the recall with its tasks and the customers, sales orders, delivery
+	// notes and open picking tasks the lot reached.
+	GetRecall(id, tenantID string) (*responses.LotRecallView, *responses.InternalResponse)
+
+	// CreateRecall freezes the lot (status recalled) and creates a pick-back or quarantine
+	// task per location holding it.
+	CreateRecall(tenantID, userID string, req *requests.CreateLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse)
+
+	// CompleteTask completes a pending task of an open recall; pick-back tasks move the stock
+	// to the return location.
+	CompleteTask(id, tenantID, taskID, userID string, req *requests.CompleteRecallTaskRequest) (*responses.LotRecallView, *responses.InternalResponse)
+
+	// CloseRecall closes an open recall without pending tasks; the lot stays recalled.
+	CloseRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse)
+
+	// CancelRecall cancels an open recall with no completed task and gives the lot back its
+	// previous status.
+	CancelRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse)
+
+	// TenantAdminIDs returns the active admin users of the tenant (recall notifications).
+	TenantAdminIDs(tenantID string) ([]string, *responses.InternalResponse)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// NumberSeriesRepository reads the lot and serial number series of a tenant. Numbers are
+// taken by receiving inside its own transaction, not through this port.
+type NumberSeriesRepository interface {
+	Preview(tenantID string, req *requests.NumberSeriesPreviewRequest) (*responses.NumberSeriesPreview, *responses.InternalResponse)
+}
@@ -32,4 +32,7 @@ type PickingTaskRepository interface {
 	GenerateImportTemplate(language string) ([]byte, error)
 	// LinkCustomer links or unlinks a customer on a picking task (S2 R2 E1.7).
 	LinkCustomer(taskID string, customerID *string) *responses.InternalResponse
+	// GetLocationPickPaths returns the pick path of the tenant's locations among codes, keyed
+	// by location code; unknown codes are absent.
+	GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse)
 }
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// PickingWavesRepository defines persistence operations for picking waves. All operations
+// are tenant-scoped. Starting and completing the member tasks is NOT done here: the service
+// runs them through PickingTaskRepository so reservations, stock, sales order picked
+// quantities and delivery notes follow the per-task path.
+type PickingWavesRepository interface {
+	// CreateWave selects the member tasks (explicit ids or group_by criteria) among the tenant's
+	// open/in-progress tasks that are not in another live wave, and creates an open wave.
+	CreateWave(tenantID, createdBy string, req *requests.CreatePickingWaveRequest) (*database.PickingWave, *responses.InternalResponse)
+
+	// GetWaveByID returns a wave scoped to tenantID.
+	GetWaveByID(id, tenantID string) (*database.PickingWave, *responses.InternalResponse)
+
+	// ListWaves returns waves for a tenant with optional status filter and pagination.
+	ListWaves(tenantID string, status *string, limit, offset int) ([]database.PickingWave, *responses.InternalResponse)
+
+	// GetWaveMembers returns the member tasks of a wave in sequence order, including
+	// memberships released by a cancellation.
+	GetWaveMembers(waveID string) ([]database.PickingWaveMember, *responses.InternalResponse)
+
+	// TransitionWave moves a wave to next, validating the wave state machine and stamping
+	// started_at, completed_at or cancelled_at. Cancelling releases the member tasks.
+	TransitionWave(id, tenantID, next string) (*database.PickingWave, *responses.InternalResponse)
+
+	// RecordPick sorts the quantity picked for one consolidated pick line into the allocations
+	// of the in-progress member tasks (allocation picked_qty), in wave order.
+	RecordPick(waveID, tenantID string, req *requests.RecordWavePickRequest) *responses.InternalResponse
+
+	// GetLocationPickPaths returns the pick path of the tenant's locations among codes, keyed
+	// by location code; unknown codes are absent.
+	GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse)
+}
@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"encoding/json"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// LocationStock is the on-hand quantity of a SKU (and lot, when tracked) at a location.
+type LocationStock struct {
+	Location  string
+	SKU       string
+	LotNumber string
+	Quantity  float64
+}
+
+// LocationCapacity is what a location holds on each dimension and its effective limits
+// (nil = unlimited on that dimension).
+type LocationCapacity struct {
+	MaxUnits    *float64
+	MaxWeightKg *float64
+	MaxVolumeM3 *float64
+	Units       float64
+	WeightKg    float64
+	VolumeM3    float64
+}
+
+// PutawayRepository loads the data the putaway engine ranks and persists auto-filled
+// receiving task locations.
+type PutawayRepository interface {
+	GetReceivingTask(tenantID, taskID string) (*database.ReceivingTask, *responses.InternalResponse)
+	GetArticles(tenantID string, skus []string) ([]database.Article, *responses.InternalResponse)
+	// ListPutawayLocations returns active storage locations; dock / way-out locations are excluded.
+	ListPutawayLocations(tenantID string) ([]database.Location, *responses.InternalResponse)
+	ListLocationTypes() ([]database.LocationType, *responses.InternalResponse)
+	// GetLocationStock returns positive stock of skus per location, split by lot when the
+	// stock is lot-tracked (LotNumber is empty otherwise).
+	GetLocationStock(tenantID string, skus []string) ([]LocationStock, *responses.InternalResponse)
+	// GetLocationCapacity returns the effective limits and current load of each location code.
+	GetLocationCapacity(tenantID string, locationCodes []string) (map[string]LocationCapacity, *responses.InternalResponse)
+	UpdateReceivingTaskItems(tenantID, taskID string, items json.RawMessage) *responses.InternalResponse
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// QCHoldsRepository defines persistence operations for quarantine / QC holds. All operations
+// are tenant-scoped.
+type QCHoldsRepository interface {
+	// ListHolds returns a tenant's QC holds with optional status / scope / SKU filters and pagination.
+	ListHolds(tenantID string, status, scope, sku *string, limit, offset int) ([]database.QCHold, *responses.InternalResponse)
+
+	// GetHold returns the hold with its lot number, inspections and attachments.
+	GetHold(id, tenantID string) (*responses.QCHoldView, *responses.InternalResponse)
+
+	// CreateHold quarantines a lot or blocks a quantity of one inventory row.
+	CreateHold(tenantID, userID string, req *requests.CreateQCHoldRequest) (*responses.QCHoldView, *responses.InternalResponse)
+
+	// RecordInspection adds an inspection result to a hold in quarantine.
+	RecordInspection(id, tenantID, userID string, req *requests.RecordQCInspectionRequest) (*responses.QCHoldView, *responses.InternalResponse)
+
+	// AddAttachment records a file already stored in the document storage on the hold.
+	AddAttachment(id, tenantID string, attachment *database.QCAttachment) (*database.QCAttachment, *responses.InternalResponse)
+
+	// GetAttachment returns an attachment of the hold.
+	GetAttachment(id, tenantID, attachmentID string) (*database.QCAttachment, *responses.InternalResponse)
+
+	// ReleaseHold releases a hold in quarantine: the lot becomes released or the held quantity
+	// available again.
+	ReleaseHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse)
+
+	// RejectHold rejects a hold in quarantine: the lot becomes rejected (its stock stays
+	// blocked) or the held quantity is written off with a qc_reject movement.
+	RejectHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse)
+}
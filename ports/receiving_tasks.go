@@ -18,8 +18,10 @@ type ReceivingTasksRepository interface {
 	UpdateReceivingTask(id string, data map[string]interface{}) *responses.InternalResponse
 	ImportReceivingTaskFromExcel(userID string, tenantID string, fileBytes []byte) *responses.InternalResponse
 	ExportReceivingTaskToExcel(tenantID string) ([]byte, *responses.InternalResponse)
-	CompleteFullTask(id string, location, userId string) *responses.InternalResponse
-	CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse
+	// CompleteFullTask and CompleteReceivingLine reject receipts above the tenant's
+	// over-receipt allowance unless override is non-nil (supervisor authorization, audited).
+	CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse
+	CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse
 	GenerateImportTemplate(language string) ([]byte, error)
 	// LinkSupplier links or unlinks a supplier on a receiving task (S2 R2 E1.7).
 	LinkSupplier(taskID string, supplierID *string) *responses.InternalResponse
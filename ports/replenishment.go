@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/dto"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// ReplenishmentRepository loads the stock position the replenishment engine works on.
+// Draft purchase orders are created through PurchaseOrdersRepository, not here.
+type ReplenishmentRepository interface {
+	// ListCandidates returns every active article of the tenant that has a reorder policy
+	// (safety_stock > 0 or min_quantity set) with on-hand/reserved stock, quantity still open
+	// on draft/submitted/partial POs, average daily outbound demand over lookbackDays and the
+	// preferred supplier (falling back to the cheapest linked supplier).
+	ListCandidates(tenantID string, lookbackDays int) ([]dto.ReplenishmentCandidate, *responses.InternalResponse)
+}
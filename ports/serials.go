@@ -18,3 +18,11 @@ type SerialsRepository interface {
 	UpdateSerial(tenantID, id string, data map[string]interface{}) *responses.InternalResponse
 	DeleteSerial(tenantID, id string) *responses.InternalResponse
 }
+
+// SerialLifecycleRepository moves serials through their state machine and reads their
+// chain of custody. Documents (receiving, picking, delivery notes, returns) move serials
+// themselves; this port serves the manual transition and the lookup.
+type SerialLifecycleRepository interface {
+	TransitionSerial(tenantID, id, userID string, req *requests.SerialTransitionRequest) (*database.Serial, *responses.InternalResponse)
+	GetChainOfCustody(tenantID, serialNumber string) (*responses.SerialCustodyView, *responses.InternalResponse)
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// ShipmentsRepository defines persistence operations for the packing stage. All operations are
+// tenant-scoped. Shipments are opened by picking completion when the tenant requires packing;
+// packages and their contents can only change while the shipment is "packing".
+type ShipmentsRepository interface {
+	// ListShipments returns shipments for a tenant with optional status filter and pagination.
+	ListShipments(tenantID string, status *string, limit, offset int) ([]database.Shipment, *responses.InternalResponse)
+
+	// GetShipment returns the shipment with its lines (packed / remaining qty), packages,
+	// contents and totals.
+	GetShipment(id, tenantID string) (*responses.ShipmentView, *responses.InternalResponse)
+
+	// AddPackage creates the next package of the shipment with a new SSCC.
+	AddPackage(shipmentID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse)
+
+	// UpdatePackage changes the type, weight or dimensions of a package.
+	UpdatePackage(shipmentID, packageID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse)
+
+	// DeletePackage removes a package and its contents.
+	DeletePackage(shipmentID, packageID, tenantID string) *responses.InternalResponse
+
+	// AddPackageContent packs a quantity (and serials) of a shipment line into a package,
+	// rejecting more than what is left to pack.
+	AddPackageContent(shipmentID, packageID, tenantID string, req *requests.AddPackageContentRequest) (*database.PackageContent, *responses.InternalResponse)
+
+	// RemovePackageContent takes a content back out of a package.
+	RemovePackageContent(shipmentID, packageID, contentID, tenantID string) *responses.InternalResponse
+
+	// CloseShipment checks every line is fully packed and no package is empty, creates the
+	// delivery note with one item per package + SKU + lot and marks the shipment packed.
+	CloseShipment(id, tenantID, userID string, req *requests.CloseShipmentRequest) (*database.Shipment, *responses.InternalResponse)
+}
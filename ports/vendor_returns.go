@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+)
+
+// VendorReturnsRepository defines persistence operations for returns to vendor (RTV). All
+// operations are tenant-scoped.
+type VendorReturnsRepository interface {
+	// ListReturns returns a tenant's vendor returns with optional status / purchase order /
+	// supplier filters and pagination.
+	ListReturns(tenantID string, status, purchaseOrderID, supplierID *string, limit, offset int) ([]database.VendorReturn, *responses.InternalResponse)
+
+	// GetReturn returns the vendor return with its lines, PO / task numbers, supplier and credit totals.
+	GetReturn(id, tenantID string) (*responses.VendorReturnView, *responses.InternalResponse)
+
+	// CreateReturn drafts a vendor return from the rejections of a purchase order or receiving
+	// task. Lines are checked against rejected minus what other open vendor returns claim.
+	CreateReturn(tenantID, userID string, req *requests.CreateVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse)
+
+	// ShipReturn marks a draft return as shipped to the supplier.
+	ShipReturn(id, tenantID string, req *requests.ShipVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse)
+
+	// CreditReturn records the supplier's credit note on a shipped return.
+	CreditReturn(id, tenantID string, req *requests.CreditVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse)
+
+	// CancelReturn cancels a draft return, releasing its quantities.
+	CancelReturn(id, tenantID string) (*database.VendorReturn, *responses.InternalResponse)
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"gorm.io/gorm"
+)
+
+// allowanceColumns maps each allowance rule to its stock_settings column.
+var allowanceColumns = map[string]string{
+	responses.AllowanceOverReceipt:  "over_receipt_allowance_pct",
+	responses.AllowanceOverPicking:  "over_picking_allowance_pct",
+	responses.AllowanceOverDelivery: "over_delivery_allowance_pct",
+}
+
+// allowanceLimit returns the highest quantity accepted for a base quantity: base plus
+// allowancePct percent of it. Non-positive allowances mean no tolerance.
+func allowanceLimit(base, allowancePct float64) float64 {
+	if allowancePct <= 0 {
+		return base
+	}
+	return base * (1 + allowancePct/100)
+}
+
+// exceedsAllowance reports whether qty is above allowanceLimit.
+// A small epsilon absorbs float noise from the percentage math (e.g. 10 * 1.1).
+func exceedsAllowance(qty, base, allowancePct float64) bool {
+	return qty > allowanceLimit(base, allowancePct)+1e-9
+}
+
+// tenantAllowancePct reads the tenant's tolerance for rule from stock_settings.
+// Tenants without a stock_settings row get the column default (0%: no excess allowed).
+func tenantAllowancePct(tx *gorm.DB, tenantID, rule string) (float64, error) {
+	column, ok := allowanceColumns[rule]
+	if !ok {
+		return 0, fmt.Errorf("unknown allowance rule %q", rule)
+	}
+	var pct float64
+	if err := tx.Raw(`SELECT `+column+` FROM stock_settings WHERE tenant_id = ?`, tenantID).Scan(&pct).Error; err != nil {
+		return 0, fmt.Errorf("read %s: %w", column, err)
+	}
+	return pct, nil
+}
+
+// allowanceExceeded builds the handled 409 for a quantity above base plus its allowance.
+// The message states the excess; Details carries the same numbers for clients.
+func allowanceExceeded(rule, sku, location string, base, allowancePct, requested float64) *responses.InternalResponse {
+	limit := allowanceLimit(base, allowancePct)
+	detail := &responses.AllowanceExceeded{
+		Rule:          rule,
+		SKU:           sku,
+		Location:      location,
+		BaseQty:       base,
+		AllowancePct:  allowancePct,
+		MaxAllowedQty: limit,
+		RequestedQty:  requested,
+		ExcessQty:     requested - limit,
+	}
+
+	var msg string
+	switch rule {
+	case responses.AllowanceOverReceipt:
+		msg = fmt.Sprintf("La cantidad recibida (%.2f) para SKU %s excede en %.2f el máximo permitido (%.2f = esperada %.2f + tolerancia de sobre-recepción %.2f%%); se requiere autorización de supervisor",
+			requested, sku, detail.ExcessQty, limit, base, allowancePct)
+	case responses.AllowanceOverPicking:
+		msg = fmt.Sprintf("La cantidad pickeada (%.2f) para SKU %s en %s excede en %.2f el máximo permitido (%.2f = asignada %.2f + tolerancia de sobre-picking %.2f%%)",
+			requested, sku, location, detail.ExcessQty, limit, base, allowancePct)
+	default:
+		msg = fmt.Sprintf("La cantidad pickeada acumulada (%.2f) para SKU %s excede en %.2f el máximo permitido (%.2f = ordenada %.2f + tolerancia de sobre-entrega %.2f%%)",
+			requested, sku, detail.ExcessQty, limit, base, allowancePct)
+	}
+
+	return &responses.InternalResponse{
+		Message:    msg,
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+		Details:    detail,
+	}
+}
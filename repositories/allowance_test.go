@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExceedsAllowance(t *testing.T) {
+	tests := []struct {
+		name         string
+		qty          float64
+		base         float64
+		allowancePct float64
+		want         bool
+	}{
+		{"exact quantity", 10, 10, 0, false},
+		{"shortage", 8, 10, 0, false},
+		{"any excess with zero allowance", 11, 10, 0, true},
+		{"within allowance", 10.5, 10, 10, false},
+		{"exactly at allowance limit", 11, 10, 10, false},
+		{"above allowance", 12, 10, 10, true},
+		{"fractional allowance", 102.5, 100, 2.5, false},
+		{"negative allowance treated as zero", 11, 10, -5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exceedsAllowance(tt.qty, tt.base, tt.allowancePct))
+		})
+	}
+}
+
+func TestAllowanceLimit(t *testing.T) {
+	assert.InDelta(t, 10.0, allowanceLimit(10, 0), 1e-9)
+	assert.InDelta(t, 11.0, allowanceLimit(10, 10), 1e-9)
+	assert.InDelta(t, 250.0, allowanceLimit(100, 150), 1e-9)
+}
+
+func TestAllowanceExceeded_ReportsExcess(t *testing.T) {
+	resp := allowanceExceeded(responses.AllowanceOverPicking, "SKU-1", "A-01", 20, 5, 23)
+	require.NotNil(t, resp)
+	assert.True(t, resp.Handled)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Message, "SKU-1")
+	assert.Contains(t, resp.Message, "2.00")
+
+	detail, ok := resp.Details.(*responses.AllowanceExceeded)
+	require.True(t, ok)
+	assert.Equal(t, responses.AllowanceOverPicking, detail.Rule)
+	assert.Equal(t, "A-01", detail.Location)
+	assert.InDelta(t, 21.0, detail.MaxAllowedQty, 1e-9)
+	assert.InDelta(t, 2.0, detail.ExcessQty, 1e-9)
+}
+
+func TestAllowanceColumns_CoverAllRules(t *testing.T) {
+	for _, rule := range []string{responses.AllowanceOverReceipt, responses.AllowanceOverPicking, responses.AllowanceOverDelivery} {
+		assert.NotEmpty(t, allowanceColumns[rule], rule)
+	}
+}
+
+func TestCheckPickAllowances(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	line := func(sku string, allocated float64, picked *float64) requests.PickingTaskItemRequest {
+		return requests.PickingTaskItemRequest{
+			SKU:              sku,
+			ExpectedQuantity: allocated,
+			Allocations:      []database.LocationAllocation{{Location: "A-01", Quantity: allocated, PickedQty: picked}},
+		}
+	}
+
+	t.Run("exact pick passes", func(t *testing.T) {
+		assert.Nil(t, checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, nil)}, nil, 0, 0))
+	})
+
+	t.Run("short pick passes", func(t *testing.T) {
+		assert.Nil(t, checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, f(6))}, nil, 0, 0))
+	})
+
+	t.Run("over-pick within allowance passes", func(t *testing.T) {
+		assert.Nil(t, checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, f(11))}, nil, 10, 0))
+	})
+
+	t.Run("over-pick above allowance is rejected with excess", func(t *testing.T) {
+		resp := checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, f(13))}, nil, 10, 0)
+		require.NotNil(t, resp)
+		detail := resp.Details.(*responses.AllowanceExceeded)
+		assert.Equal(t, responses.AllowanceOverPicking, detail.Rule)
+		assert.Equal(t, "A-01", detail.Location)
+		assert.InDelta(t, 2.0, detail.ExcessQty, 1e-9)
+	})
+
+	t.Run("over-delivery counts previously picked qty", func(t *testing.T) {
+		soItems := []database.SalesOrderItem{{ArticleSKU: "SKU-1", ExpectedQty: 20, PickedQty: 15}}
+		resp := checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, nil)}, soItems, 0, 10)
+		require.NotNil(t, resp)
+		detail := resp.Details.(*responses.AllowanceExceeded)
+		assert.Equal(t, responses.AllowanceOverDelivery, detail.Rule)
+		assert.InDelta(t, 25.0, detail.RequestedQty, 1e-9)
+		assert.InDelta(t, 22.0, detail.MaxAllowedQty, 1e-9)
+		assert.InDelta(t, 3.0, detail.ExcessQty, 1e-9)
+	})
+
+	t.Run("over-delivery within allowance passes", func(t *testing.T) {
+		soItems := []database.SalesOrderItem{{ArticleSKU: "SKU-1", ExpectedQty: 20, PickedQty: 12}}
+		assert.Nil(t, checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, nil)}, soItems, 0, 10))
+	})
+
+	t.Run("SO lines not in this pick are ignored", func(t *testing.T) {
+		soItems := []database.SalesOrderItem{{ArticleSKU: "SKU-2", ExpectedQty: 1, PickedQty: 5}}
+		assert.Nil(t, checkPickAllowances([]requests.PickingTaskItemRequest{line("SKU-1", 10, nil)}, soItems, 0, 0))
+	})
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// ArticleBarcodesRepository implements ports.ArticleBarcodesRepository using GORM.
+type ArticleBarcodesRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.ArticleBarcodesRepository = (*ArticleBarcodesRepository)(nil)
+
+// barcodesWithSKU selects article_barcodes joined to their article's SKU.
+func (r *ArticleBarcodesRepository) barcodesWithSKU(tenantID string) *gorm.DB {
+	return r.DB.Table("article_barcodes").
+		Select("article_barcodes.*, articles.sku").
+		Joins("JOIN articles ON articles.id = article_barcodes.article_id AND articles.tenant_id = article_barcodes.tenant_id").
+		Where("article_barcodes.tenant_id = ?", tenantID)
+}
+
+func (r *ArticleBarcodesRepository) List(tenantID, sku string) ([]database.ArticleBarcode, *responses.InternalResponse) {
+	q := r.barcodesWithSKU(tenantID)
+	if sku != "" {
+		q = q.Where("articles.sku = ?", sku)
+	}
+	var rows []database.ArticleBarcode
+	if err := q.Order("articles.sku, article_barcodes.code").Scan(&rows).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los códigos de barras",
+			Handled: false,
+		}
+	}
+	return rows, nil
+}
+
+func (r *ArticleBarcodesRepository) Create(tenantID string, req *requests.CreateArticleBarcodeRequest) (*database.ArticleBarcode, *responses.InternalResponse) {
+	article, resp := r.FindArticleBySKU(tenantID, req.SKU)
+	if resp != nil {
+		return nil, resp
+	}
+	if article == nil {
+		return nil, &responses.InternalResponse{
+			Message:    "Artículo no encontrado",
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+
+	codeType := req.CodeType
+	if codeType == "" {
+		codeType = database.ArticleBarcodeGTIN
+	}
+	code := strings.TrimSpace(req.Code)
+	if codeType == database.ArticleBarcodeGTIN {
+		gtin, err := tools.NormalizeGTIN(code)
+		if err != nil {
+			return nil, &responses.InternalResponse{
+				Error:      err,
+				Message:    "GTIN inválido: " + err.Error(),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		code = gtin
+	}
+
+	existing, _, resp := r.FindByCode(tenantID, code)
+	if resp != nil {
+		return nil, resp
+	}
+	if existing != nil {
+		return nil, &responses.InternalResponse{
+			Message:    "El código " + code + " ya está asignado al artículo " + existing.SKU,
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+
+	packQty := 1.0
+	if req.PackQuantity != nil {
+		packQty = *req.PackQuantity
+	}
+	id, err := tools.GenerateNanoid(r.DB)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al generar el ID del código de barras",
+			Handled: false,
+		}
+	}
+	row := &database.ArticleBarcode{
+		ID:           id,
+		TenantID:     tenantID,
+		ArticleID:    article.ID,
+		Code:         code,
+		CodeType:     codeType,
+		PackQuantity: packQty,
+		CreatedAt:    tools.GetCurrentTime(),
+	}
+	if err := r.DB.Create(row).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al crear el código de barras",
+			Handled: false,
+		}
+	}
+	row.SKU = article.SKU
+	return row, nil
+}
+
+func (r *ArticleBarcodesRepository) Delete(tenantID, id string) *responses.InternalResponse {
+	res := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&database.ArticleBarcode{})
+	if res.Error != nil {
+		return &responses.InternalResponse{
+			Error:   res.Error,
+			Message: "Error al eliminar el código de barras",
+			Handled: false,
+		}
+	}
+	if res.RowsAffected == 0 {
+		return &responses.InternalResponse{
+			Message:    "Código de barras no encontrado",
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+func (r *ArticleBarcodesRepository) FindByCode(tenantID, code string) (*database.ArticleBarcode, *database.Article, *responses.InternalResponse) {
+	var rows []database.ArticleBarcode
+	if err := r.barcodesWithSKU(tenantID).Where("article_barcodes.code = ?", code).Limit(1).Scan(&rows).Error; err != nil {
+		return nil, nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al buscar el código de barras",
+			Handled: false,
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+	var article database.Article
+	if err := r.DB.Where("id = ? AND tenant_id = ?", rows[0].ArticleID, tenantID).First(&article).Error; err != nil {
+		return nil, nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el artículo del código de barras",
+			Handled: false,
+		}
+	}
+	return &rows[0], &article, nil
+}
+
+func (r *ArticleBarcodesRepository) FindArticleBySKU(tenantID, sku string) (*database.Article, *responses.InternalResponse) {
+	var article database.Article
+	if err := r.DB.Where("tenant_id = ? AND sku = ?", tenantID, sku).First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el artículo",
+			Handled: false,
+		}
+	}
+	return &article, nil
+}
+
+func (r *ArticleBarcodesRepository) FindLot(tenantID, sku, lotNumber string) (*database.Lot, *responses.InternalResponse) {
+	var lot database.Lot
+	err := r.DB.
+		Where("tenant_id = ? AND sku = ? AND lot_number = ?", tenantID, sku, lotNumber).
+		Where("status IS NULL OR status <> 'archived'").
+		First(&lot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el lote",
+			Handled: false,
+		}
+	}
+	return &lot, nil
+}
+
+func (r *ArticleBarcodesRepository) FindSerial(tenantID, sku, serialNumber string) (*database.Serial, *responses.InternalResponse) {
+	var serial database.Serial
+	if err := r.DB.Where("tenant_id = ? AND sku = ? AND serial_number = ?", tenantID, sku, serialNumber).First(&serial).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el número de serie",
+			Handled: false,
+		}
+	}
+	return &serial, nil
+}
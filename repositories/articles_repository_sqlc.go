@@ -64,7 +64,8 @@ func (r *ArticlesRepositorySQLC) GetAllArticlesForTenant(tenantID string) ([]dat
 			CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 			BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 			MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-			ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+			ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+			WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 		})
 	}
 	return out, nil
@@ -93,7 +94,8 @@ func (r *ArticlesRepositorySQLC) GetArticleByIDForTenant(id, tenantID string) (*
 		CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 		BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 		MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+		WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 	})
 	return &art, nil
 }
@@ -121,7 +123,8 @@ func (r *ArticlesRepositorySQLC) GetBySkuForTenant(sku, tenantID string) (*datab
 		CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 		BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 		MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+		WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 	})
 	return &art, nil
 }
@@ -170,6 +173,11 @@ func (r *ArticlesRepositorySQLC) CreateArticleForTenant(tenantID string, data *r
 		DefaultLocationID:  ptrStringToPgText(data.DefaultLocationID),
 		ReceivingNotes:     ptrStringToPgText(data.ReceivingNotes),
 		ShippingNotes:      ptrStringToPgText(data.ShippingNotes),
+		PriceCurrency:      ptrStringToPgText(data.PriceCurrency),
+		WeightKg:           ptrFloatToPgNumeric(data.WeightKg),
+		LengthCm:           ptrFloatToPgNumeric(data.LengthCm),
+		WidthCm:            ptrFloatToPgNumeric(data.WidthCm),
+		HeightCm:           ptrFloatToPgNumeric(data.HeightCm),
 	}
 
 	_, err = r.queries.CreateArticle(ctx, arg)
@@ -228,6 +236,11 @@ func (r *ArticlesRepositorySQLC) UpdateArticleForTenant(id, tenantID string, dat
 		DefaultLocationID:  ptrStringToPgText(data.DefaultLocationID),
 		ReceivingNotes:     ptrStringToPgText(data.ReceivingNotes),
 		ShippingNotes:      ptrStringToPgText(data.ShippingNotes),
+		PriceCurrency:      ptrStringToPgText(data.PriceCurrency),
+		WeightKg:           ptrFloatToPgNumeric(data.WeightKg),
+		LengthCm:           ptrFloatToPgNumeric(data.LengthCm),
+		WidthCm:            ptrFloatToPgNumeric(data.WidthCm),
+		HeightCm:           ptrFloatToPgNumeric(data.HeightCm),
 	}
 
 	updated, err := r.queries.UpdateArticle(ctx, arg)
@@ -243,7 +256,8 @@ func (r *ArticlesRepositorySQLC) UpdateArticleForTenant(id, tenantID string, dat
 		CategoryID: updated.CategoryID, ShelfLifeInDays: updated.ShelfLifeInDays, SafetyStock: updated.SafetyStock,
 		BatchNumberSeries: updated.BatchNumberSeries, SerialNumberSeries: updated.SerialNumberSeries,
 		MinOrderQty: updated.MinOrderQty, DefaultLocationID: updated.DefaultLocationID,
-		ReceivingNotes: updated.ReceivingNotes, ShippingNotes: updated.ShippingNotes,
+		ReceivingNotes: updated.ReceivingNotes, ShippingNotes: updated.ShippingNotes, PriceCurrency: updated.PriceCurrency,
+		WeightKg: updated.WeightKg, LengthCm: updated.LengthCm, WidthCm: updated.WidthCm, HeightCm: updated.HeightCm,
 	})
 	return &art, nil
 }
@@ -563,7 +577,8 @@ func (r *ArticlesRepositorySQLC) GetAllArticles() ([]database.Article, *response
 			CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 			BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 			MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-			ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+			ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+			WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 		})
 	}
 	return out, nil
@@ -588,7 +603,8 @@ func (r *ArticlesRepositorySQLC) GetArticleByID(id string) (*database.Article, *
 		CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 		BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 		MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+		WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 	})
 	return &art, nil
 }
@@ -612,7 +628,8 @@ func (r *ArticlesRepositorySQLC) GetBySku(sku string) (*database.Article, *respo
 		CategoryID: a.CategoryID, ShelfLifeInDays: a.ShelfLifeInDays, SafetyStock: a.SafetyStock,
 		BatchNumberSeries: a.BatchNumberSeries, SerialNumberSeries: a.SerialNumberSeries,
 		MinOrderQty: a.MinOrderQty, DefaultLocationID: a.DefaultLocationID,
-		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes,
+		ReceivingNotes: a.ReceivingNotes, ShippingNotes: a.ShippingNotes, PriceCurrency: a.PriceCurrency,
+		WeightKg: a.WeightKg, LengthCm: a.LengthCm, WidthCm: a.WidthCm, HeightCm: a.HeightCm,
 	})
 	return &art, nil
 }
@@ -675,6 +692,11 @@ type articleRowData struct {
 	DefaultLocationID  pgtype.Text
 	ReceivingNotes     pgtype.Text
 	ShippingNotes      pgtype.Text
+	PriceCurrency      pgtype.Text
+	WeightKg           pgtype.Numeric
+	LengthCm           pgtype.Numeric
+	WidthCm            pgtype.Numeric
+	HeightCm           pgtype.Numeric
 }
 
 func articleRowToDatabase(a articleRowData) database.Article {
@@ -709,6 +731,11 @@ func articleRowToDatabase(a articleRowData) database.Article {
 		DefaultLocationID:  pgTextToPtrString(a.DefaultLocationID),
 		ReceivingNotes:     pgTextToPtrString(a.ReceivingNotes),
 		ShippingNotes:      pgTextToPtrString(a.ShippingNotes),
+		PriceCurrency:      pgTextToPtrString(a.PriceCurrency),
+		WeightKg:           pgNumericToPtrFloat(a.WeightKg),
+		LengthCm:           pgNumericToPtrFloat(a.LengthCm),
+		WidthCm:            pgNumericToPtrFloat(a.WidthCm),
+		HeightCm:           pgNumericToPtrFloat(a.HeightCm),
 	}
 }
 
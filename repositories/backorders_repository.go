@@ -21,7 +21,7 @@ type BackordersRepository struct {
 
 // backorderInventorySuggestor is a narrow interface to get pick suggestions for BO2.
 type backorderInventorySuggestor interface {
-	GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
+	GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -160,7 +160,7 @@ func (r *BackordersRepository) Fulfill(id, tenantID, userID string) (*responses.
 		var allocs []database.LocationAllocation
 		available := 0.0
 		if r.InventorySvc != nil {
-			sugg, suggResp := r.InventorySvc.GetPickSuggestionsBySKU(bo.ArticleSKU, bo.RemainingQty)
+			sugg, suggResp := r.InventorySvc.GetPickSuggestionsBySKU(tenantID, bo.ArticleSKU, bo.RemainingQty)
 			if suggResp == nil && sugg != nil {
 				allocs = sugg.Allocations
 				available = sugg.TotalFound
@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// costEpsilon absorbs float noise when comparing quantities drawn from cost layers.
+const costEpsilon = 1e-9
+
+// layerDraw is the quantity planned to leave layers[Index] at that layer's unit cost.
+type layerDraw struct {
+	Index    int
+	Qty      float64
+	UnitCost float64
+}
+
+// planLayerConsumption decides which open layers (sorted oldest first) cover qty.
+// FIFO draws the oldest layers first and LIFO the newest. AVCO draws from every open layer
+// in proportion to its remaining quantity, so the outbound is costed at the weighted average
+// and the layers left behind keep that same average. Whatever the layers cannot cover is
+// returned as shortfall.
+func planLayerConsumption(method string, layers []database.CostLayer, qty float64) ([]layerDraw, float64) {
+	draws := make([]layerDraw, 0)
+	if qty <= costEpsilon {
+		return draws, 0
+	}
+
+	if method == database.ValuationAVCO {
+		total := 0.0
+		for _, l := range layers {
+			if l.RemainingQty > 0 {
+				total += l.RemainingQty
+			}
+		}
+		if total <= costEpsilon {
+			return draws, qty
+		}
+		take := math.Min(qty, total)
+		ratio := take / total
+		drawn := 0.0
+		last := -1
+		for i, l := range layers {
+			if l.RemainingQty <= 0 {
+				continue
+			}
+			d := l.RemainingQty * ratio
+			draws = append(draws, layerDraw{Index: i, Qty: d, UnitCost: l.UnitCost})
+			drawn += d
+			last = len(draws) - 1
+		}
+		// Put the rounding remainder on the last layer so the draws add up to take exactly.
+		if last >= 0 {
+			draws[last].Qty = math.Min(draws[last].Qty+take-drawn, layers[draws[last].Index].RemainingQty)
+		}
+		return draws, qty - take
+	}
+
+	order := make([]int, 0, len(layers))
+	for i := range layers {
+		order = append(order, i)
+	}
+	if method == database.ValuationLIFO {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	remaining := qty
+	for _, i := range order {
+		if remaining <= costEpsilon {
+			break
+		}
+		l := layers[i]
+		if l.RemainingQty <= 0 {
+			continue
+		}
+		d := math.Min(l.RemainingQty, remaining)
+		draws = append(draws, layerDraw{Index: i, Qty: d, UnitCost: l.UnitCost})
+		remaining -= d
+	}
+	if remaining < costEpsilon {
+		remaining = 0
+	}
+	return draws, remaining
+}
+
+// costOrZero dereferences an optional unit cost.
+func costOrZero(cost *float64) float64 {
+	if cost == nil {
+		return 0
+	}
+	return *cost
+}
+
+// skuTenantID resolves the tenant that owns a SKU through its article (articles.sku is unique).
+func skuTenantID(tx *gorm.DB, sku string) (string, error) {
+	var tenantID string
+	if err := tx.Raw(`SELECT tenant_id::text FROM articles WHERE sku = ? LIMIT 1`, sku).Scan(&tenantID).Error; err != nil {
+		return "", fmt.Errorf("resolve tenant for sku %s: %w", sku, err)
+	}
+	if tenantID == "" {
+		return "", fmt.Errorf("resolve tenant for sku %s: article not found", sku)
+	}
+	return tenantID, nil
+}
+
+// tenantValuationMethod reads stock_settings.valuation_method. Tenants without a
+// stock_settings row get the column default (avco).
+func tenantValuationMethod(tx *gorm.DB, tenantID string) (string, error) {
+	var method string
+	if err := tx.Raw(`SELECT valuation_method FROM stock_settings WHERE tenant_id = ?`, tenantID).Scan(&method).Error; err != nil {
+		return "", fmt.Errorf("read valuation_method: %w", err)
+	}
+	switch method {
+	case database.ValuationFIFO, database.ValuationLIFO:
+		return method, nil
+	default:
+		return database.ValuationAVCO, nil
+	}
+}
+
+// receiptUnitCost is the cost of received units in the tenant base currency: the purchase
+// order line unit_cost when the receiving task comes from a PO and the line has one (converted
+// from the line currency at the rate in effect today), otherwise fallback, the inventory or
+// article price, converted by basePriceCost. A currency without a rate returns *missingRateError.
+func receiptUnitCost(tx *gorm.DB, purchaseOrderID *string, sku string, fallback *float64) (float64, error) {
+	if purchaseOrderID != nil && *purchaseOrderID != "" {
+		var lines []struct {
+			UnitCost float64
+			Currency *string
+		}
+		if err := tx.Raw(`
+			SELECT unit_cost, currency
+			FROM purchase_order_items
+			WHERE purchase_order_id = ? AND article_sku = ? AND unit_cost IS NOT NULL
+			LIMIT 1
+		`, *purchaseOrderID, sku).Scan(&lines).Error; err != nil {
+			return 0, fmt.Errorf("read PO unit cost for sku %s: %w", sku, err)
+		}
+		if len(lines) > 0 {
+			line := lines[0]
+			if line.Currency == nil || *line.Currency == "" {
+				return line.UnitCost, nil
+			}
+			tenantID, err := skuTenantID(tx, sku)
+			if err != nil {
+				return 0, err
+			}
+			base, err := tenantBaseCurrency(tx, tenantID)
+			if err != nil {
+				return 0, err
+			}
+			rate, err := lookupExchangeRate(tx, tenantID, *line.Currency, base, tools.GetCurrentTime())
+			if err != nil {
+				return 0, err
+			}
+			return line.UnitCost * rate, nil
+		}
+	}
+	return basePriceCost(tx, sku, fallback)
+}
+
+// basePriceCost converts an inventory or article unit price of sku, kept in the article's
+// price_currency, into the tenant base currency at the rate in effect today. A nil or zero
+// price is 0 without a lookup; a currency without a rate returns *missingRateError.
+func basePriceCost(tx *gorm.DB, sku string, price *float64) (float64, error) {
+	amount := costOrZero(price)
+	if amount == 0 {
+		return 0, nil
+	}
+	var article struct {
+		TenantID      string
+		PriceCurrency *string
+	}
+	if err := tx.Raw(`SELECT tenant_id::text AS tenant_id, price_currency FROM articles WHERE sku = ? LIMIT 1`, sku).
+		Scan(&article).Error; err != nil {
+		return 0, fmt.Errorf("read price currency for sku %s: %w", sku, err)
+	}
+	if article.PriceCurrency == nil || strings.TrimSpace(*article.PriceCurrency) == "" {
+		return amount, nil
+	}
+	base, err := tenantBaseCurrency(tx, article.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	rate, err := lookupExchangeRate(tx, article.TenantID, strings.TrimSpace(*article.PriceCurrency), base, tools.GetCurrentTime())
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// recordCostLayer opens a cost layer for an inbound movement that was already inserted.
+// The layer takes the movement quantity and unit_cost (0 when the movement has no cost).
+func recordCostLayer(tx *gorm.DB, mov *database.InventoryMovement) error {
+	qty := math.Abs(mov.Quantity)
+	if qty <= costEpsilon {
+		return nil
+	}
+	tenantID, err := skuTenantID(tx, mov.SKU)
+	if err != nil {
+		return err
+	}
+	layerID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return fmt.Errorf("generate cost layer id: %w", err)
+	}
+
+	movID := mov.ID
+	layer := &database.CostLayer{
+		ID:            layerID,
+		TenantID:      tenantID,
+		SKU:           mov.SKU,
+		MovementID:    &movID,
+		ReferenceType: mov.ReferenceType,
+		ReferenceID:   mov.ReferenceID,
+		OriginalQty:   qty,
+		RemainingQty:  qty,
+		UnitCost:      costOrZero(mov.UnitCost),
+		ReceivedAt:    mov.CreatedAt,
+	}
+	if layer.ReceivedAt.IsZero() {
+		layer.ReceivedAt = tools.GetCurrentTime()
+	}
+	if err := tx.Create(layer).Error; err != nil {
+		return fmt.Errorf("create cost layer for sku %s: %w", mov.SKU, err)
+	}
+	return nil
+}
+
+// consumeCostLayers draws an outbound movement (already inserted) from the SKU's open layers
+// using the tenant valuation method, records each draw in cost_layer_consumptions and stores
+// the resulting COGS and effective unit cost on the movement. Quantity not covered by any
+// layer is costed at fallbackCost (the article/inventory price).
+func consumeCostLayers(tx *gorm.DB, mov *database.InventoryMovement, fallbackCost float64) error {
+	qty := math.Abs(mov.Quantity)
+	if qty <= costEpsilon {
+		return nil
+	}
+	tenantID, err := skuTenantID(tx, mov.SKU)
+	if err != nil {
+		return err
+	}
+	method, err := tenantValuationMethod(tx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	var layers []database.CostLayer
+	if err := tx.Raw(`
+		SELECT * FROM cost_layers
+		WHERE tenant_id = ? AND sku = ? AND remaining_qty > 0
+		ORDER BY received_at ASC, id ASC
+		FOR UPDATE
+	`, tenantID, mov.SKU).Scan(&layers).Error; err != nil {
+		return fmt.Errorf("load cost layers for sku %s: %w", mov.SKU, err)
+	}
+
+	draws, shortfall := planLayerConsumption(method, layers, qty)
+
+	cogs := 0.0
+	consume := func(layerID *string, drawQty, unitCost float64) error {
+		id, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate cost consumption id: %w", err)
+		}
+		if err := tx.Create(&database.CostLayerConsumption{
+			ID:         id,
+			TenantID:   tenantID,
+			MovementID: mov.ID,
+			LayerID:    layerID,
+			SKU:        mov.SKU,
+			Quantity:   drawQty,
+			UnitCost:   unitCost,
+		}).Error; err != nil {
+			return fmt.Errorf("create cost consumption for sku %s: %w", mov.SKU, err)
+		}
+		cogs += drawQty * unitCost
+		return nil
+	}
+
+	for _, d := range draws {
+		if d.Qty <= costEpsilon {
+			continue
+		}
+		layerID := layers[d.Index].ID
+		if err := tx.Exec(`UPDATE cost_layers SET remaining_qty = GREATEST(remaining_qty - ?, 0) WHERE id = ?`, d.Qty, layerID).Error; err != nil {
+			return fmt.Errorf("consume cost layer %s: %w", layerID, err)
+		}
+		if err := consume(&layerID, d.Qty, d.UnitCost); err != nil {
+			return err
+		}
+	}
+	if shortfall > costEpsilon {
+		if err := consume(nil, shortfall, fallbackCost); err != nil {
+			return err
+		}
+	}
+
+	unitCost := cogs / qty
+	if err := tx.Model(&database.InventoryMovement{}).Where("id = ?", mov.ID).
+		Updates(map[string]interface{}{"cogs": cogs, "unit_cost": unitCost}).Error; err != nil {
+		return fmt.Errorf("store movement cogs: %w", err)
+	}
+	mov.COGS = &cogs
+	mov.UnitCost = &unitCost
+	return nil
+}
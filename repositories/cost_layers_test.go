@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// costTestLayers are three open layers, oldest first: 10 @ 1, 10 @ 2, 10 @ 4.
+func costTestLayers() []database.CostLayer {
+	return []database.CostLayer{
+		{ID: "l1", RemainingQty: 10, UnitCost: 1},
+		{ID: "l2", RemainingQty: 10, UnitCost: 2},
+		{ID: "l3", RemainingQty: 10, UnitCost: 4},
+	}
+}
+
+func drawnCost(draws []layerDraw) (qty, cost float64) {
+	for _, d := range draws {
+		qty += d.Qty
+		cost += d.Qty * d.UnitCost
+	}
+	return qty, cost
+}
+
+func TestPlanLayerConsumption_FIFO_TakesOldestFirst(t *testing.T) {
+	draws, shortfall := planLayerConsumption(database.ValuationFIFO, costTestLayers(), 15)
+	require.Len(t, draws, 2)
+	assert.Equal(t, 0, draws[0].Index)
+	assert.Equal(t, 10.0, draws[0].Qty)
+	assert.Equal(t, 1, draws[1].Index)
+	assert.Equal(t, 5.0, draws[1].Qty)
+	assert.Zero(t, shortfall)
+
+	_, cost := drawnCost(draws)
+	assert.InDelta(t, 20.0, cost, 1e-9)
+}
+
+func TestPlanLayerConsumption_LIFO_TakesNewestFirst(t *testing.T) {
+	draws, shortfall := planLayerConsumption(database.ValuationLIFO, costTestLayers(), 15)
+	require.Len(t, draws, 2)
+	assert.Equal(t, 2, draws[0].Index)
+	assert.Equal(t, 10.0, draws[0].Qty)
+	assert.Equal(t, 1, draws[1].Index)
+	assert.Equal(t, 5.0, draws[1].Qty)
+	assert.Zero(t, shortfall)
+
+	_, cost := drawnCost(draws)
+	assert.InDelta(t, 50.0, cost, 1e-9)
+}
+
+func TestPlanLayerConsumption_AVCO_CostsAtWeightedAverage(t *testing.T) {
+	layers := []database.CostLayer{
+		{ID: "l1", RemainingQty: 10, UnitCost: 1},
+		{ID: "l2", RemainingQty: 30, UnitCost: 3},
+	}
+	draws, shortfall := planLayerConsumption(database.ValuationAVCO, layers, 8)
+	require.Len(t, draws, 2)
+	assert.Zero(t, shortfall)
+
+	// Proportional draw: a quarter from the first layer, three quarters from the second.
+	assert.InDelta(t, 2.0, draws[0].Qty, 1e-9)
+	assert.InDelta(t, 6.0, draws[1].Qty, 1e-9)
+
+	qty, cost := drawnCost(draws)
+	assert.InDelta(t, 8.0, qty, 1e-9)
+	assert.InDelta(t, 8*2.5, cost, 1e-9, "average cost is (10*1 + 30*3) / 40 = 2.5")
+}
+
+func TestPlanLayerConsumption_SkipsEmptyLayers(t *testing.T) {
+	layers := costTestLayers()
+	layers[0].RemainingQty = 0
+
+	draws, _ := planLayerConsumption(database.ValuationFIFO, layers, 5)
+	require.Len(t, draws, 1)
+	assert.Equal(t, 1, draws[0].Index)
+
+	draws, _ = planLayerConsumption(database.ValuationAVCO, layers, 5)
+	for _, d := range draws {
+		assert.NotEqual(t, 0, d.Index)
+	}
+}
+
+func TestPlanLayerConsumption_ReportsShortfall(t *testing.T) {
+	for _, method := range []string{database.ValuationFIFO, database.ValuationLIFO, database.ValuationAVCO} {
+		t.Run(method, func(t *testing.T) {
+			draws, shortfall := planLayerConsumption(method, costTestLayers(), 35)
+			qty, cost := drawnCost(draws)
+			assert.InDelta(t, 30.0, qty, 1e-9)
+			assert.InDelta(t, 70.0, cost, 1e-9)
+			assert.InDelta(t, 5.0, shortfall, 1e-9)
+		})
+	}
+}
+
+func TestPlanLayerConsumption_NoLayers(t *testing.T) {
+	draws, shortfall := planLayerConsumption(database.ValuationFIFO, nil, 4)
+	assert.Empty(t, draws)
+	assert.Equal(t, 4.0, shortfall)
+
+	draws, shortfall = planLayerConsumption(database.ValuationAVCO, nil, 4)
+	assert.Empty(t, draws)
+	assert.Equal(t, 4.0, shortfall)
+}
+
+func TestPlanLayerConsumption_ZeroQty(t *testing.T) {
+	draws, shortfall := planLayerConsumption(database.ValuationLIFO, costTestLayers(), 0)
+	assert.Empty(t, draws)
+	assert.Zero(t, shortfall)
+}
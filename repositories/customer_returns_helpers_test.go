@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleDelivered() map[string]*deliveredSKU {
+	return deliveredBySKU([]database.DeliveryNoteItem{
+		{ArticleSKU: "SKU-LOT", Qty: 6, LotNumbers: []string{"L1", "L2"}},
+		{ArticleSKU: "SKU-LOT", Qty: 4, LotNumbers: []string{"L1"}},
+		{ArticleSKU: "SKU-SER", Qty: 2},
+		{ArticleSKU: "SKU-PLAIN", Qty: 5},
+	})
+}
+
+func sampleReturnArticles() map[string]database.Article {
+	return map[string]database.Article{
+		"SKU-LOT":   {SKU: "SKU-LOT", TrackByLot: true},
+		"SKU-SER":   {SKU: "SKU-SER", TrackBySerial: true},
+		"SKU-PLAIN": {SKU: "SKU-PLAIN"},
+	}
+}
+
+func TestDeliveredBySKU(t *testing.T) {
+	d := sampleDelivered()
+	require.Len(t, d, 3)
+	assert.InDelta(t, 10, d["SKU-LOT"].Qty, 1e-9)
+	assert.True(t, d["SKU-LOT"].Lots["L1"])
+	assert.True(t, d["SKU-LOT"].Lots["L2"])
+	assert.Empty(t, d["SKU-PLAIN"].Lots)
+}
+
+func TestValidateReturnLines_OK(t *testing.T) {
+	lines := []requests.CustomerReturnLineRequest{
+		{ArticleSKU: "SKU-LOT", LotNumber: tools.StrPtr("L2"), Qty: tools.Float64Ptr(3), ReasonCode: "damaged"},
+		{ArticleSKU: "SKU-SER", SerialNumbers: []string{"S1", "S2"}, Qty: tools.Float64Ptr(2), ReasonCode: "defective"},
+		{ArticleSKU: "SKU-PLAIN", Qty: tools.Float64Ptr(1), ReasonCode: "not_ordered"},
+	}
+	assert.Nil(t, validateReturnLines(lines, sampleReturnArticles(), sampleDelivered(), map[string]float64{"SKU-LOT": 7}))
+}
+
+func TestValidateReturnLines_Rejections(t *testing.T) {
+	cases := []struct {
+		name   string
+		line   requests.CustomerReturnLineRequest
+		status int
+	}{
+		{"not delivered", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-X", Qty: tools.Float64Ptr(1)}, responses.StatusBadRequest},
+		{"unknown lot", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-LOT", LotNumber: tools.StrPtr("L9"), Qty: tools.Float64Ptr(1)}, responses.StatusBadRequest},
+		{"missing lot", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-LOT", Qty: tools.Float64Ptr(1)}, responses.StatusBadRequest},
+		{"missing serials", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-SER", SerialNumbers: []string{"S1"}, Qty: tools.Float64Ptr(2)}, responses.StatusBadRequest},
+		{"too many serials", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-PLAIN", SerialNumbers: []string{"S1", "S2"}, Qty: tools.Float64Ptr(1)}, responses.StatusBadRequest},
+		{"duplicate serial", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-SER", SerialNumbers: []string{"S1", "S1"}, Qty: tools.Float64Ptr(2)}, responses.StatusBadRequest},
+		{"more than delivered", requests.CustomerReturnLineRequest{ArticleSKU: "SKU-PLAIN", Qty: tools.Float64Ptr(6)}, responses.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := validateReturnLines([]requests.CustomerReturnLineRequest{tc.line}, sampleReturnArticles(), sampleDelivered(), nil)
+			require.NotNil(t, resp)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestValidateReturnLines_CountsOtherReturns(t *testing.T) {
+	lines := []requests.CustomerReturnLineRequest{
+		{ArticleSKU: "SKU-PLAIN", Qty: tools.Float64Ptr(2)},
+		{ArticleSKU: "SKU-PLAIN", Qty: tools.Float64Ptr(1)},
+	}
+	assert.Nil(t, validateReturnLines(lines, sampleReturnArticles(), sampleDelivered(), map[string]float64{"SKU-PLAIN": 2}))
+
+	resp := validateReturnLines(lines, sampleReturnArticles(), sampleDelivered(), map[string]float64{"SKU-PLAIN": 2.5})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+}
+
+func TestValidateInspection(t *testing.T) {
+	line := database.CustomerReturnLine{ID: "l1", ArticleSKU: "SKU-SER", Qty: 3, SerialNumbers: []string{"S1", "S2", "S3"}}
+	inspected := map[string]bool{"S1": true}
+
+	ok := requests.ReturnInspectionRequest{ReturnLineID: "l1", Qty: tools.Float64Ptr(2), Disposition: "restock", SerialNumbers: []string{"S2", "S3"}}
+	assert.Nil(t, validateInspection(line, 2, inspected, true, ok))
+
+	over := ok
+	over.Qty = tools.Float64Ptr(3)
+	over.SerialNumbers = []string{"S1", "S2", "S3"}
+	resp := validateInspection(line, 2, inspected, true, over)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+
+	again := ok
+	again.Qty = tools.Float64Ptr(1)
+	again.SerialNumbers = []string{"S1"}
+	resp = validateInspection(line, 2, inspected, true, again)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+
+	missing := ok
+	missing.SerialNumbers = []string{"S2"}
+	resp = validateInspection(line, 2, inspected, true, missing)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReturnStatusAfterInspection(t *testing.T) {
+	lines := []database.CustomerReturnLine{{Qty: 2, ReceivedQty: 2}, {Qty: 3, ReceivedQty: 1}}
+	assert.Equal(t, database.CustomerReturnPartiallyReceived, returnStatusAfterInspection(lines))
+	lines[1].ReceivedQty = 3
+	assert.Equal(t, database.CustomerReturnReceived, returnStatusAfterInspection(lines))
+}
+
+func TestReturnDispositionMapping(t *testing.T) {
+	assert.Equal(t, "return_restock", returnMovementType(database.ReturnDispositionRestock))
+	assert.Equal(t, "return_quarantine", returnMovementType(database.ReturnDispositionQuarantine))
+	assert.Equal(t, "return_scrap", returnMovementType(database.ReturnDispositionScrap))
+	assert.Equal(t, "in_stock", returnedSerialStatus(database.ReturnDispositionRestock))
+	assert.Equal(t, "returned", returnedSerialStatus(database.ReturnDispositionQuarantine))
+	assert.Equal(t, "scrapped", returnedSerialStatus(database.ReturnDispositionScrap))
+}
+
+func TestBuildCustomerReturnView(t *testing.T) {
+	ret := database.CustomerReturn{ID: "r1", ReturnNumber: "RMA-2026-0001", Status: database.CustomerReturnPartiallyReceived}
+	lines := []database.CustomerReturnLine{{ID: "l1", Qty: 3, ReceivedQty: 2}, {ID: "l2", Qty: 1}}
+	inspections := []database.CustomerReturnInspection{
+		{ID: "i1", ReturnLineID: "l1", Qty: 1, Disposition: "restock"},
+		{ID: "i2", ReturnLineID: "l1", Qty: 1, Disposition: "scrap"},
+	}
+
+	view := buildCustomerReturnView(ret, lines, inspections)
+	require.Len(t, view.Lines, 2)
+	assert.InDelta(t, 1, view.Lines[0].PendingQty, 1e-9)
+	assert.Len(t, view.Lines[0].Inspections, 2)
+	assert.InDelta(t, 1, view.Lines[1].PendingQty, 1e-9)
+	assert.NotNil(t, view.Lines[1].Inspections)
+	assert.Empty(t, view.Lines[1].Inspections)
+}
@@ -0,0 +1,92 @@
+// Integration tests for customer returns (RMA) receipt.
+// Requires Docker (testcontainers). Skipped automatically in -short mode.
+// Run: go test -v ./repositories/... -run TestCustomerReturns
+
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedReturnLocation inserts an active location for the tenant.
+func seedReturnLocation(t *testing.T, db *gorm.DB, tenantID, code string) {
+	t.Helper()
+	id, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO locations (id, tenant_id, location_code, type, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, 'shelf', true, NOW(), NOW())`, id, tenantID, code).Error)
+}
+
+// seedAuthorizedReturn inserts an authorized return with one line of qty units of sku.
+// Returns the return id and the line id.
+func seedAuthorizedReturn(t *testing.T, db *gorm.DB, tenantID, userID, sku string, qty float64) (string, string) {
+	t.Helper()
+	customerID := seedCustomer(t, db, tenantID)
+	soID := seedSalesOrder(t, db, tenantID, customerID, userID)
+	retID, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO customer_returns (id, tenant_id, return_number, sales_order_id, customer_id, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 'authorized', ?, NOW(), NOW())`,
+		retID, tenantID, "RMA-TEST-"+retID[:6], soID, customerID, userID).Error)
+	lineID, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO customer_return_lines (id, return_id, article_sku, qty, reason_code)
+		VALUES (?, ?, ?, ?, 'damaged')`, lineID, retID, sku, qty).Error)
+	return retID, lineID
+}
+
+// TestCustomerReturns_ReceiveReturn_RejectedInspectionRollsBack: the second inspection of
+// the request points at an unknown location → 400, and the stock, movement and cost layer
+// the first inspection had already written are rolled back with it.
+func TestCustomerReturns_ReceiveReturn_RejectedInspectionRollsBack(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	userID := seedUser(t, db)
+	seedArticleRow(t, db, testTenantA, "SKU-RMA-TX", "Returned article")
+	seedReturnLocation(t, db, testTenantA, "RMA-A")
+	retID, lineID := seedAuthorizedReturn(t, db, testTenantA, userID, "SKU-RMA-TX", 5)
+
+	repo := &CustomerReturnsRepository{DB: db}
+	_, resp := repo.ReceiveReturn(retID, testTenantA, userID, &requests.ReceiveCustomerReturnRequest{
+		Inspections: []requests.ReturnInspectionRequest{
+			{ReturnLineID: lineID, Qty: tools.Float64Ptr(2), Disposition: database.ReturnDispositionRestock, Location: "RMA-A"},
+			{ReturnLineID: lineID, Qty: tools.Float64Ptr(1), Disposition: database.ReturnDispositionRestock, Location: "NO-EXISTE"},
+		},
+	})
+	require.NotNil(t, resp)
+	assert.True(t, resp.Handled)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+
+	count := func(table string) int64 {
+		var n int64
+		require.NoError(t, db.Table(table).Where("sku = ?", "SKU-RMA-TX").Count(&n).Error)
+		return n
+	}
+	assert.Zero(t, count("inventory"), "first inspection's stock rolled back")
+	assert.Zero(t, count("inventory_movements"), "first inspection's movement rolled back")
+	assert.Zero(t, count("cost_layers"), "first inspection's cost layer rolled back")
+
+	var inspections int64
+	require.NoError(t, db.Table("customer_return_inspections").Where("return_line_id = ?", lineID).Count(&inspections).Error)
+	assert.Zero(t, inspections)
+
+	var line database.CustomerReturnLine
+	require.NoError(t, db.Where("id = ?", lineID).First(&line).Error)
+	assert.Equal(t, 0.0, line.ReceivedQty)
+
+	var ret database.CustomerReturn
+	require.NoError(t, db.Where("id = ?", retID).First(&ret).Error)
+	assert.Equal(t, database.CustomerReturnAuthorized, ret.Status)
+}
@@ -0,0 +1,1099 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomerReturnsRepository implements ports.CustomerReturnsRepository using GORM.
+type CustomerReturnsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.CustomerReturnsRepository = (*CustomerReturnsRepository)(nil)
+
+// returnQtyEpsilon absorbs float noise when comparing returned and delivered quantities.
+const returnQtyEpsilon = 1e-6
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextReturnNumber generates "RMA-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextReturnNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("RMA-%d-", year)
+
+	lockKey := fmt.Sprintf("rma-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire RMA number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(return_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM customer_returns
+		WHERE tenant_id = $2
+		  AND return_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate RMA number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// deliveredSKU is what delivery notes delivered of a SKU: the quantity and its lot numbers.
+type deliveredSKU struct {
+	Qty  float64
+	Lots map[string]bool
+}
+
+// deliveredBySKU sums delivery note items per SKU and collects their lot numbers.
+func deliveredBySKU(items []database.DeliveryNoteItem) map[string]*deliveredSKU {
+	out := make(map[string]*deliveredSKU)
+	for _, it := range items {
+		d, ok := out[it.ArticleSKU]
+		if !ok {
+			d = &deliveredSKU{Lots: make(map[string]bool)}
+			out[it.ArticleSKU] = d
+		}
+		d.Qty += it.Qty
+		for _, lot := range it.LotNumbers {
+			if lot != "" {
+				d.Lots[lot] = true
+			}
+		}
+	}
+	return out
+}
+
+// claimedBySKU sums the quantity per SKU of return lines.
+func claimedBySKU(lines []database.CustomerReturnLine) map[string]float64 {
+	out := make(map[string]float64)
+	for _, l := range lines {
+		out[l.ArticleSKU] += l.Qty
+	}
+	return out
+}
+
+// returnableExceeded checks the requested quantity per SKU against delivered minus claimed.
+// SKUs are reported in sorted order so the message is stable.
+func returnableExceeded(requested map[string]float64, delivered map[string]*deliveredSKU, claimed map[string]float64) *responses.InternalResponse {
+	skus := make([]string, 0, len(requested))
+	for sku := range requested {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	for _, sku := range skus {
+		var deliveredQty float64
+		if d, ok := delivered[sku]; ok {
+			deliveredQty = d.Qty
+		}
+		returnable := deliveredQty - claimed[sku]
+		if requested[sku] > returnable+returnQtyEpsilon {
+			return &responses.InternalResponse{
+				Message: fmt.Sprintf("Se solicita devolver %.3f de %s pero solo quedan %.3f por devolver (entregado %.3f)",
+					requested[sku], sku, math.Max(returnable, 0), deliveredQty),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
+	return nil
+}
+
+// validateReturnLines checks the requested lines against what was delivered: the SKU was
+// delivered, the lot is one of its delivered lots (required for lot-tracked articles), serials
+// are distinct, at most one per unit (exactly one per unit for serial-tracked articles), and
+// the quantity per SKU fits delivered minus what other returns already claim.
+func validateReturnLines(lines []requests.CustomerReturnLineRequest, articles map[string]database.Article, delivered map[string]*deliveredSKU, claimed map[string]float64) *responses.InternalResponse {
+	requested := make(map[string]float64)
+	seenSerials := make(map[string]bool)
+	for _, l := range lines {
+		qty := *l.Qty
+		d, ok := delivered[l.ArticleSKU]
+		if !ok {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("El artículo %s no fue entregado en el documento indicado", l.ArticleSKU),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		article := articles[l.ArticleSKU]
+		if l.LotNumber != nil {
+			if !d.Lots[*l.LotNumber] {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("El lote %s no fue entregado para el artículo %s", *l.LotNumber, l.ArticleSKU),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}
+			}
+		} else if article.TrackByLot && len(d.Lots) > 0 {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("El artículo %s se controla por lote; indique el lote devuelto", l.ArticleSKU),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+
+		if float64(len(l.SerialNumbers)) > qty+returnQtyEpsilon {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("Se indicaron %d series para una cantidad de %.3f", len(l.SerialNumbers), qty),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		if article.TrackBySerial && math.Abs(float64(len(l.SerialNumbers))-qty) > returnQtyEpsilon {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("El artículo %s se controla por serie; indique una serie por unidad devuelta", l.ArticleSKU),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		for _, s := range l.SerialNumbers {
+			if seenSerials[s] {
+				return &responses.InternalResponse{Message: fmt.Sprintf("Serie repetida: %s", s), Handled: true, StatusCode: responses.StatusBadRequest}
+			}
+			seenSerials[s] = true
+		}
+
+		requested[l.ArticleSKU] += qty
+	}
+	return returnableExceeded(requested, delivered, claimed)
+}
+
+// validateInspection checks an inspection against its line: qty at most what is pending, and
+// serials among the line's serials not inspected yet, at most one per unit (exactly one per
+// unit for serial-tracked articles).
+func validateInspection(line database.CustomerReturnLine, pending float64, inspectedSerials map[string]bool, trackBySerial bool, req requests.ReturnInspectionRequest) *responses.InternalResponse {
+	qty := *req.Qty
+	if qty > pending+returnQtyEpsilon {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("La cantidad inspeccionada (%.3f) excede lo pendiente de la línea %s (%.3f)", qty, line.ArticleSKU, math.Max(pending, 0)),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+	if float64(len(req.SerialNumbers)) > qty+returnQtyEpsilon {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("Se indicaron %d series para una cantidad de %.3f", len(req.SerialNumbers), qty),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	if trackBySerial && math.Abs(float64(len(req.SerialNumbers))-qty) > returnQtyEpsilon {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("El artículo %s se controla por serie; indique una serie por unidad inspeccionada", line.ArticleSKU),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	onLine := make(map[string]bool, len(line.SerialNumbers))
+	for _, s := range line.SerialNumbers {
+		onLine[s] = true
+	}
+	seen := make(map[string]bool, len(req.SerialNumbers))
+	for _, s := range req.SerialNumbers {
+		if seen[s] {
+			return &responses.InternalResponse{Message: fmt.Sprintf("Serie repetida: %s", s), Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+		seen[s] = true
+		if !onLine[s] || inspectedSerials[s] {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("La serie %s no está en la línea de devolución o ya fue inspeccionada", s),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
+	return nil
+}
+
+// returnStatusAfterInspection is received once every line is fully inspected,
+// partially_received otherwise.
+func returnStatusAfterInspection(lines []database.CustomerReturnLine) string {
+	for _, l := range lines {
+		if l.ReceivedQty < l.Qty-returnQtyEpsilon {
+			return database.CustomerReturnPartiallyReceived
+		}
+	}
+	return database.CustomerReturnReceived
+}
+
+// returnMovementType maps a disposition to the inventory movement type it posts.
+func returnMovementType(disposition string) string {
+	switch disposition {
+	case database.ReturnDispositionRestock:
+		return database.MovementReturnRestock
+	case database.ReturnDispositionQuarantine:
+		return database.MovementReturnQuarantine
+	default:
+		return database.MovementReturnScrap
+	}
+}
+
+// returnedSerialStatus is the serial status after a disposition. Quarantined units stay
+// 'returned' until their QC hold is decided.
+func returnedSerialStatus(disposition string) string {
+	switch disposition {
+	case database.ReturnDispositionRestock:
+		return database.SerialInStock
+	case database.ReturnDispositionQuarantine:
+		return database.SerialReturned
+	default:
+		return database.SerialScrapped
+	}
+}
+
+// buildCustomerReturnView assembles the return view: lines with pending qty and their
+// inspections in the order they were recorded.
+func buildCustomerReturnView(ret database.CustomerReturn, lines []database.CustomerReturnLine, inspections []database.CustomerReturnInspection) *responses.CustomerReturnView {
+	view := &responses.CustomerReturnView{
+		CustomerReturn: ret,
+		Lines:          make([]responses.CustomerReturnLineView, 0, len(lines)),
+	}
+	for _, l := range lines {
+		lv := responses.CustomerReturnLineView{CustomerReturnLine: l, Inspections: []database.CustomerReturnInspection{}}
+		lv.PendingQty = l.Qty - l.ReceivedQty
+		if lv.PendingQty < returnQtyEpsilon {
+			lv.PendingQty = 0
+		}
+		for _, in := range inspections {
+			if in.ReturnLineID == l.ID {
+				lv.Inspections = append(lv.Inspections, in)
+			}
+		}
+		view.Lines = append(view.Lines, lv)
+	}
+	return view
+}
+
+// lockCustomerReturn loads a tenant's return FOR UPDATE. A non-nil response is the handled 404.
+func lockCustomerReturn(tx *gorm.DB, id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse, error) {
+	var ret database.CustomerReturn
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&ret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Devolución no encontrada", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, fmt.Errorf("load customer return: %w", err)
+	}
+	return &ret, nil, nil
+}
+
+// loadReturnContents returns the lines of a return and their inspections.
+func loadReturnContents(tx *gorm.DB, returnID string) ([]database.CustomerReturnLine, []database.CustomerReturnInspection, error) {
+	var lines []database.CustomerReturnLine
+	if err := tx.Where("return_id = ?", returnID).Order("article_sku, lot_number NULLS FIRST, id").Find(&lines).Error; err != nil {
+		return nil, nil, fmt.Errorf("load return lines: %w", err)
+	}
+	var inspections []database.CustomerReturnInspection
+	if err := tx.Raw(`
+		SELECT i.*
+		  FROM customer_return_inspections i
+		  JOIN customer_return_lines l ON l.id = i.return_line_id
+		 WHERE l.return_id = ?
+		 ORDER BY i.inspected_at, i.id
+	`, returnID).Scan(&inspections).Error; err != nil {
+		return nil, nil, fmt.Errorf("load return inspections: %w", err)
+	}
+	return lines, inspections, nil
+}
+
+// openReturnLines returns the lines of a sales order's returns that are not cancelled,
+// optionally only those against one delivery note.
+func openReturnLines(tx *gorm.DB, salesOrderID string, deliveryNoteID *string) ([]database.CustomerReturnLine, error) {
+	query := tx.Table("customer_return_lines l").
+		Select("l.*").
+		Joins("JOIN customer_returns r ON r.id = l.return_id").
+		Where("r.sales_order_id = ? AND r.status <> ?", salesOrderID, database.CustomerReturnCancelled)
+	if deliveryNoteID != nil {
+		query = query.Where("r.delivery_note_id = ?", *deliveryNoteID)
+	}
+	var lines []database.CustomerReturnLine
+	if err := query.Scan(&lines).Error; err != nil {
+		return nil, fmt.Errorf("load open return lines: %w", err)
+	}
+	return lines, nil
+}
+
+// salesOrderDeliveredItems returns the items of every delivery note of a sales order.
+func salesOrderDeliveredItems(tx *gorm.DB, salesOrderID string) ([]database.DeliveryNoteItem, error) {
+	var items []database.DeliveryNoteItem
+	if err := tx.Raw(`
+		SELECT dni.*
+		  FROM delivery_note_items dni
+		  JOIN delivery_notes dn ON dn.id = dni.delivery_note_id
+		 WHERE dn.sales_order_id = ?
+	`, salesOrderID).Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("load delivered items: %w", err)
+	}
+	return items, nil
+}
+
+// checkReturnSerials verifies the serials of the requested lines exist for the tenant and SKU
+// and are not on another open return. A non-nil response is the handled 400 / 409.
+func checkReturnSerials(tx *gorm.DB, tenantID string, lines []requests.CustomerReturnLineRequest) (*responses.InternalResponse, error) {
+	for _, l := range lines {
+		if len(l.SerialNumbers) == 0 {
+			continue
+		}
+		var found []database.Serial
+		if err := tx.Where("tenant_id = ? AND sku = ? AND serial_number IN ?", tenantID, l.ArticleSKU, l.SerialNumbers).
+			Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("load serials: %w", err)
+		}
+		known := make(map[string]string, len(found))
+		for _, s := range found {
+			known[s.SerialNumber] = s.Status
+		}
+		for _, s := range l.SerialNumbers {
+			status, ok := known[s]
+			if !ok {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("La serie %s no existe para el artículo %s", s, l.ArticleSKU),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}, nil
+			}
+			if status != database.SerialShipped {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("La serie %s está en estado %s; solo se devuelven series despachadas", s, status),
+					Handled:    true,
+					StatusCode: responses.StatusConflict,
+				}, nil
+			}
+		}
+
+		var open []string
+		if err := tx.Raw(`
+			SELECT s.serial
+			  FROM customer_return_lines l
+			  JOIN customer_returns r ON r.id = l.return_id
+			 CROSS JOIN LATERAL unnest(l.serial_numbers) AS s(serial)
+			 WHERE r.tenant_id = ?
+			   AND r.status IN (?, ?)
+			   AND l.article_sku = ?
+			   AND s.serial IN ?
+		`, tenantID, database.CustomerReturnAuthorized, database.CustomerReturnPartiallyReceived, l.ArticleSKU, l.SerialNumbers).
+			Scan(&open).Error; err != nil {
+			return nil, fmt.Errorf("check open return serials: %w", err)
+		}
+		if len(open) > 0 {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("La serie %s ya está en otra devolución abierta", open[0]),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// returnLocationExists reports whether an active location with code exists for the tenant.
+func returnLocationExists(tx *gorm.DB, tenantID, code string) (bool, error) {
+	var count int64
+	if err := tx.Model(&database.Location{}).
+		Where("tenant_id = ? AND location_code = ? AND is_active = true", tenantID, code).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("check location %s: %w", code, err)
+	}
+	return count > 0, nil
+}
+
+// putReturnedStock adds qty of the line's SKU at location (creating the inventory row when
+// needed), restores the lot quantities and records the inbound cost layer. Returns the
+// inventory row and the movement, which the caller inserts.
+func putReturnedStock(tx *gorm.DB, ret *database.CustomerReturn, line database.CustomerReturnLine, article database.Article, qty float64, location, movementType, userID string) (*database.Inventory, *database.InventoryMovement, error) {
+	var inv database.Inventory
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND location = ?", ret.TenantID, line.ArticleSKU, location).
+		First(&inv).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		invID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate inventory id: %w", err)
+		}
+		inv = database.Inventory{
+			ID:           invID,
+			TenantID:     ret.TenantID,
+			SKU:          line.ArticleSKU,
+			Name:         article.Name,
+			Description:  article.Description,
+			Location:     location,
+			Status:       "available",
+			Presentation: article.Presentation,
+			UnitPrice:    article.UnitPrice,
+			CreatedAt:    tools.GetCurrentTime(),
+			UpdatedAt:    tools.GetCurrentTime(),
+		}
+		if err := tx.Create(&inv).Error; err != nil {
+			return nil, nil, fmt.Errorf("create inventory %s @ %s: %w", line.ArticleSKU, location, err)
+		}
+	case err != nil:
+		return nil, nil, fmt.Errorf("find inventory %s @ %s: %w", line.ArticleSKU, location, err)
+	}
+
+	beforeQty := inv.Quantity
+	afterQty := inv.Quantity + qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", inv.ID).
+		Updates(map[string]interface{}{"quantity": afterQty, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, nil, fmt.Errorf("update inventory %s @ %s: %w", line.ArticleSKU, location, err)
+	}
+	inv.Quantity = afterQty
+
+	var lotID *string
+	if line.LotNumber != nil {
+		id, err := restoreReturnedLot(tx, ret.TenantID, inv.ID, line.ArticleSKU, *line.LotNumber, location, qty)
+		if err != nil {
+			return nil, nil, err
+		}
+		lotID = &id
+	}
+
+	unitCost, err := receiptUnitCost(tx, nil, line.ArticleSKU, inv.UnitPrice)
+	if err != nil {
+		return nil, nil, err
+	}
+	movID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate return movement id: %w", err)
+	}
+	refType := "customer_return"
+	mov := &database.InventoryMovement{
+		ID:             movID,
+		SKU:            line.ArticleSKU,
+		Location:       location,
+		MovementType:   movementType,
+		Quantity:       qty,
+		RemainingStock: afterQty,
+		Reason:         tools.StrPtr("customer return " + ret.ReturnNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &refType,
+		ReferenceID:    &ret.ID,
+		LotID:          lotID,
+		UnitCost:       &unitCost,
+		BeforeQty:      &beforeQty,
+		AfterQty:       &afterQty,
+		UserID:         &userID,
+	}
+	return &inv, mov, nil
+}
+
+// restoreReturnedLot adds qty back to the lot and to its per-location quantity. Returns the lot ID.
+func restoreReturnedLot(tx *gorm.DB, tenantID, inventoryID, sku, lotNumber, location string, qty float64) (string, error) {
+	var lot database.Lot
+	if err := tx.Where("tenant_id = ? AND sku = ? AND lot_number = ? AND (status IS NULL OR status != 'archived')", tenantID, sku, lotNumber).
+		First(&lot).Error; err != nil {
+		return "", fmt.Errorf("find lot %s of %s: %w", lotNumber, sku, err)
+	}
+	if err := tx.Exec(`UPDATE lots SET quantity = quantity + ?, updated_at = NOW() WHERE id = ?`, qty, lot.ID).Error; err != nil {
+		return "", fmt.Errorf("restore lot %s: %w", lotNumber, err)
+	}
+
+	res := tx.Exec(`
+		UPDATE inventory_lots SET quantity = quantity + ?
+		 WHERE tenant_id = ? AND inventory_id = ? AND lot_id = ? AND location = ?
+	`, qty, tenantID, inventoryID, lot.ID, location)
+	if res.Error != nil {
+		return "", fmt.Errorf("restore inventory lot %s @ %s: %w", lotNumber, location, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		invLotID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return "", fmt.Errorf("generate inventory_lot id: %w", err)
+		}
+		if err := tx.Create(&database.InventoryLot{
+			ID:          invLotID,
+			TenantID:    tenantID,
+			InventoryID: inventoryID,
+			LotID:       lot.ID,
+			Quantity:    qty,
+			Location:    location,
+		}).Error; err != nil {
+			return "", fmt.Errorf("create inventory lot %s @ %s: %w", lotNumber, location, err)
+		}
+	}
+	return lot.ID, nil
+}
+
+// scrapReturnedStock builds the movement of scrapped units: no stock changes, so before and
+// after are the current quantity at the location.
+func scrapReturnedStock(tx *gorm.DB, ret *database.CustomerReturn, line database.CustomerReturnLine, qty float64, location, userID string) (*database.InventoryMovement, error) {
+	var current float64
+	if err := tx.Raw(`SELECT COALESCE(SUM(quantity), 0) FROM inventory WHERE tenant_id = ? AND sku = ? AND location = ?`,
+		ret.TenantID, line.ArticleSKU, location).Scan(&current).Error; err != nil {
+		return nil, fmt.Errorf("read inventory %s @ %s: %w", line.ArticleSKU, location, err)
+	}
+	var lotID *string
+	if line.LotNumber != nil {
+		var lot database.Lot
+		if err := tx.Where("tenant_id = ? AND sku = ? AND lot_number = ?", ret.TenantID, line.ArticleSKU, *line.LotNumber).First(&lot).Error; err == nil {
+			lotID = &lot.ID
+		}
+	}
+	movID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return nil, fmt.Errorf("generate return movement id: %w", err)
+	}
+	refType := "customer_return"
+	return &database.InventoryMovement{
+		ID:             movID,
+		SKU:            line.ArticleSKU,
+		Location:       location,
+		MovementType:   database.MovementReturnScrap,
+		Quantity:       qty,
+		RemainingStock: current,
+		Reason:         tools.StrPtr("customer return " + ret.ReturnNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &refType,
+		ReferenceID:    &ret.ID,
+		LotID:          lotID,
+		BeforeQty:      &current,
+		AfterQty:       &current,
+		UserID:         &userID,
+	}, nil
+}
+
+// holdReturnedStock places a QC hold on quarantined returned units at inv, unless their lot is
+// already on hold.
+func holdReturnedStock(tx *gorm.DB, ret *database.CustomerReturn, line database.CustomerReturnLine, inv *database.Inventory, qty float64, serials []string, userID string) (*responses.InternalResponse, error) {
+	var lot *database.Lot
+	if line.LotNumber != nil {
+		held, err := tools.HeldLots(tx, ret.TenantID, line.ArticleSKU, []string{*line.LotNumber})
+		if err != nil {
+			return nil, err
+		}
+		if len(held) > 0 {
+			return nil, nil
+		}
+		var resp *responses.InternalResponse
+		lot, resp, err = findHoldLot(tx, ret.TenantID, line.ArticleSKU, *line.LotNumber)
+		if err != nil || resp != nil {
+			return resp, err
+		}
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", inv.ID).First(inv).Error; err != nil {
+		return nil, fmt.Errorf("lock inventory %s @ %s: %w", inv.SKU, inv.Location, err)
+	}
+	sourceType := "customer_return"
+	_, resp, err := placeInventoryHold(tx, ret.TenantID, userID, inv, inventoryHoldParams{
+		Qty:           qty,
+		Lot:           lot,
+		SerialNumbers: serials,
+		Reason:        "customer return " + ret.ReturnNumber + " (" + line.ReasonCode + ")",
+		SourceType:    &sourceType,
+		SourceID:      &ret.ID,
+	})
+	return resp, err
+}
+
+// setReturnedSerials records the inspected serials coming back (shipped → returned) and moves
+// them to the disposition's status. Serials put back in stock or quarantined are linked to
+// the inventory row; scrapped ones are unlinked. A non-nil response is the handled 400 / 409.
+func setReturnedSerials(tx *gorm.DB, ret *database.CustomerReturn, sku string, serials []string, disposition, location, userID string, inv *database.Inventory) (*responses.InternalResponse, error) {
+	ev := serialEvent{Location: location, DocumentType: "customer_return", DocumentID: ret.ID, DocumentNumber: ret.ReturnNumber, UserID: userID}
+	for _, sn := range serials {
+		serial, err := lockSerialByNumber(tx, ret.TenantID, sku, sn)
+		if err != nil {
+			return nil, err
+		}
+		if serial == nil {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("La serie %s no existe para el artículo %s", sn, sku),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}, nil
+		}
+		for _, next := range []string{database.SerialReturned, returnedSerialStatus(disposition)} {
+			if resp, err := transitionSerial(tx, serial, next, ev); resp != nil || err != nil {
+				return resp, err
+			}
+		}
+		if err := tx.Where("serial_id = ?", serial.ID).Delete(&database.InventorySerial{}).Error; err != nil {
+			return nil, fmt.Errorf("unlink serial %s: %w", sn, err)
+		}
+		if inv == nil {
+			continue
+		}
+		linkID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, fmt.Errorf("generate inventory serial id: %w", err)
+		}
+		if err := tx.Create(&database.InventorySerial{ID: linkID, InventoryID: inv.ID, SerialID: serial.ID, Location: inv.Location}).Error; err != nil {
+			return nil, fmt.Errorf("link serial %s: %w", sn, err)
+		}
+	}
+	return nil, nil
+}
+
+// returnArticles loads the tenant's articles of the given SKUs keyed by SKU.
+func returnArticles(tx *gorm.DB, tenantID string, skus []string) (map[string]database.Article, error) {
+	var articles []database.Article
+	if err := tx.Where("tenant_id = ? AND sku IN ?", tenantID, skus).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("load articles: %w", err)
+	}
+	out := make(map[string]database.Article, len(articles))
+	for _, a := range articles {
+		out[a.SKU] = a
+	}
+	return out, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *CustomerReturnsRepository) ListReturns(tenantID string, status, salesOrderID *string, limit, offset int) ([]database.CustomerReturn, *responses.InternalResponse) {
+	query := r.DB.Model(&database.CustomerReturn{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+	if salesOrderID != nil && *salesOrderID != "" {
+		query = query.Where("sales_order_id = ?", *salesOrderID)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var returns []database.CustomerReturn
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&returns).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las devoluciones"}
+	}
+	return returns, nil
+}
+
+func (r *CustomerReturnsRepository) GetReturn(id, tenantID string) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	var ret database.CustomerReturn
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&ret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Devolución no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la devolución"}
+	}
+
+	lines, inspections, err := loadReturnContents(r.DB, ret.ID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las líneas de la devolución"}
+	}
+	view := buildCustomerReturnView(ret, lines, inspections)
+
+	var header struct {
+		SONumber     string  `gorm:"column:so_number"`
+		DNNumber     *string `gorm:"column:dn_number"`
+		CustomerName *string `gorm:"column:customer_name"`
+	}
+	if err := r.DB.Raw(`
+		SELECT so.so_number, dn.dn_number, c.name AS customer_name
+		  FROM customer_returns cr
+		  LEFT JOIN sales_orders so ON so.id = cr.sales_order_id
+		  LEFT JOIN delivery_notes dn ON dn.id = cr.delivery_note_id
+		  LEFT JOIN clients c ON c.id = cr.customer_id
+		 WHERE cr.id = ?
+	`, ret.ID).Scan(&header).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la devolución"}
+	}
+	view.SONumber = header.SONumber
+	view.DNNumber = header.DNNumber
+	view.CustomerName = header.CustomerName
+	return view, nil
+}
+
+func (r *CustomerReturnsRepository) CreateReturn(tenantID, userID string, req *requests.CreateCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	var returnID string
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var salesOrderID, customerID string
+		var dnItems []database.DeliveryNoteItem
+		if req.DeliveryNoteID != nil {
+			var dn database.DeliveryNote
+			if err := tx.Where("id = ? AND tenant_id = ?", *req.DeliveryNoteID, tenantID).First(&dn).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					*handledResp = responses.InternalResponse{Message: "Nota de entrega no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+					return nil
+				}
+				return fmt.Errorf("load delivery note: %w", err)
+			}
+			if err := tx.Where("delivery_note_id = ?", dn.ID).Find(&dnItems).Error; err != nil {
+				return fmt.Errorf("load delivery note items: %w", err)
+			}
+			salesOrderID, customerID = dn.SalesOrderID, dn.CustomerID
+		} else {
+			salesOrderID = *req.SalesOrderID
+		}
+
+		// Lock the sales order so concurrent returns of the same order see each other's claims.
+		var so database.SalesOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ? AND deleted_at IS NULL", salesOrderID, tenantID).First(&so).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{Message: "Orden de venta no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return fmt.Errorf("load sales order: %w", err)
+		}
+		if customerID == "" {
+			customerID = so.CustomerID
+		}
+
+		soItems, err := salesOrderDeliveredItems(tx, so.ID)
+		if err != nil {
+			return err
+		}
+		if len(soItems) == 0 {
+			*handledResp = responses.InternalResponse{Message: "La orden de venta no tiene entregas que devolver", Handled: true, StatusCode: responses.StatusConflict}
+			return nil
+		}
+
+		skus := make([]string, 0, len(req.Lines))
+		for _, l := range req.Lines {
+			skus = append(skus, l.ArticleSKU)
+		}
+		articles, err := returnArticles(tx, tenantID, skus)
+		if err != nil {
+			return err
+		}
+
+		soClaimed, err := openReturnLines(tx, so.ID, nil)
+		if err != nil {
+			return err
+		}
+		if req.DeliveryNoteID != nil {
+			dnClaimed, err := openReturnLines(tx, so.ID, req.DeliveryNoteID)
+			if err != nil {
+				return err
+			}
+			if resp := validateReturnLines(req.Lines, articles, deliveredBySKU(dnItems), claimedBySKU(dnClaimed)); resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+		}
+		// Order-wide: returns against the order and against each of its delivery notes share
+		// what the order delivered.
+		if resp := validateReturnLines(req.Lines, articles, deliveredBySKU(soItems), claimedBySKU(soClaimed)); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		resp, err := checkReturnSerials(tx, tenantID, req.Lines)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		number, err := nextReturnNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		returnID, err = tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate return id: %w", err)
+		}
+		ret := &database.CustomerReturn{
+			ID:             returnID,
+			TenantID:       tenantID,
+			ReturnNumber:   number,
+			SalesOrderID:   so.ID,
+			DeliveryNoteID: req.DeliveryNoteID,
+			Status:         database.CustomerReturnAuthorized,
+			Notes:          req.Notes,
+		}
+		if customerID != "" {
+			ret.CustomerID = &customerID
+		}
+		if userID != "" {
+			ret.CreatedBy = &userID
+		}
+		if err := tx.Create(ret).Error; err != nil {
+			return fmt.Errorf("create customer return: %w", err)
+		}
+
+		for _, l := range req.Lines {
+			lineID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate return line id: %w", err)
+			}
+			serials := pq.StringArray{}
+			serials = append(serials, l.SerialNumbers...)
+			line := &database.CustomerReturnLine{
+				ID:            lineID,
+				ReturnID:      returnID,
+				ArticleSKU:    l.ArticleSKU,
+				LotNumber:     l.LotNumber,
+				SerialNumbers: serials,
+				Qty:           *l.Qty,
+				ReasonCode:    l.ReasonCode,
+				ReasonNotes:   l.ReasonNotes,
+			}
+			if err := tx.Create(line).Error; err != nil {
+				return fmt.Errorf("create return line: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la devolución"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetReturn(returnID, tenantID)
+}
+
+func (r *CustomerReturnsRepository) ReceiveReturn(id, tenantID, userID string, req *requests.ReceiveCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		ret, resp, err := lockCustomerReturn(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return fmt.Errorf("return receipt rejected")
+		}
+		if ret.Status != database.CustomerReturnAuthorized && ret.Status != database.CustomerReturnPartiallyReceived {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("No se puede recibir una devolución en estado '%s'", ret.Status),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return fmt.Errorf("return receipt rejected")
+		}
+
+		lines, inspections, err := loadReturnContents(tx, ret.ID)
+		if err != nil {
+			return err
+		}
+		lineIdx := make(map[string]int, len(lines))
+		skus := make([]string, 0, len(lines))
+		for i, l := range lines {
+			lineIdx[l.ID] = i
+			skus = append(skus, l.ArticleSKU)
+		}
+		inspectedSerials := make(map[string]map[string]bool)
+		for _, in := range inspections {
+			if inspectedSerials[in.ReturnLineID] == nil {
+				inspectedSerials[in.ReturnLineID] = make(map[string]bool)
+			}
+			for _, s := range in.SerialNumbers {
+				inspectedSerials[in.ReturnLineID][s] = true
+			}
+		}
+		articles, err := returnArticles(tx, tenantID, skus)
+		if err != nil {
+			return err
+		}
+
+		for _, inReq := range req.Inspections {
+			i, ok := lineIdx[inReq.ReturnLineID]
+			if !ok {
+				*handledResp = responses.InternalResponse{
+					Message:    fmt.Sprintf("La línea %s no pertenece a la devolución", inReq.ReturnLineID),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}
+				return fmt.Errorf("return receipt rejected")
+			}
+			line := lines[i]
+			article := articles[line.ArticleSKU]
+			if resp := validateInspection(line, line.Qty-line.ReceivedQty, inspectedSerials[line.ID], article.TrackBySerial, inReq); resp != nil {
+				*handledResp = *resp
+				return fmt.Errorf("return receipt rejected")
+			}
+			location := strings.TrimSpace(inReq.Location)
+			exists, err := returnLocationExists(tx, tenantID, location)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				*handledResp = responses.InternalResponse{
+					Message:    fmt.Sprintf("La ubicación %s no existe o está inactiva", location),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}
+				return fmt.Errorf("return receipt rejected")
+			}
+			qty := *inReq.Qty
+
+			var inv *database.Inventory
+			var mov *database.InventoryMovement
+			if inReq.Disposition == database.ReturnDispositionScrap {
+				mov, err = scrapReturnedStock(tx, ret, line, qty, location, userID)
+				if err != nil {
+					return err
+				}
+			} else {
+				exceeded, err := tools.CheckLocationCapacity(tx, tenantID, location, []tools.CapacityAddition{{SKU: line.ArticleSKU, Quantity: qty}})
+				if err != nil {
+					return err
+				}
+				if exceeded != nil {
+					*handledResp = *tools.CapacityExceededResponse(exceeded)
+					return fmt.Errorf("return receipt rejected")
+				}
+				inv, mov, err = putReturnedStock(tx, ret, line, article, qty, location, returnMovementType(inReq.Disposition), userID)
+				if err != nil {
+					return err
+				}
+			}
+			if err := tx.Create(mov).Error; err != nil {
+				return fmt.Errorf("create return movement: %w", err)
+			}
+			if inv != nil {
+				if err := recordCostLayer(tx, mov); err != nil {
+					return err
+				}
+			}
+			resp, err := setReturnedSerials(tx, ret, line.ArticleSKU, inReq.SerialNumbers, inReq.Disposition, location, userID, inv)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				*handledResp = *resp
+				return fmt.Errorf("return receipt rejected")
+			}
+			// Quarantined goods go on QC hold until quality releases or rejects them; a lot
+			// already on hold blocks them on its own.
+			if inReq.Disposition == database.ReturnDispositionQuarantine {
+				resp, err := holdReturnedStock(tx, ret, line, inv, qty, inReq.SerialNumbers, userID)
+				if err != nil {
+					return err
+				}
+				if resp != nil {
+					*handledResp = *resp
+					return fmt.Errorf("return receipt rejected")
+				}
+			}
+
+			inspectionID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate inspection id: %w", err)
+			}
+			serials := pq.StringArray{}
+			serials = append(serials, inReq.SerialNumbers...)
+			inspection := &database.CustomerReturnInspection{
+				ID:            inspectionID,
+				ReturnLineID:  line.ID,
+				Qty:           qty,
+				Disposition:   inReq.Disposition,
+				Location:      location,
+				SerialNumbers: serials,
+				Notes:         inReq.Notes,
+				MovementID:    &mov.ID,
+				InspectedAt:   tools.GetCurrentTime(),
+			}
+			if userID != "" {
+				inspection.InspectedBy = &userID
+			}
+			if err := tx.Create(inspection).Error; err != nil {
+				return fmt.Errorf("create inspection: %w", err)
+			}
+
+			lines[i].ReceivedQty += qty
+			if err := tx.Model(&database.CustomerReturnLine{}).Where("id = ?", line.ID).
+				Update("received_qty", lines[i].ReceivedQty).Error; err != nil {
+				return fmt.Errorf("update return line: %w", err)
+			}
+			if inspectedSerials[line.ID] == nil {
+				inspectedSerials[line.ID] = make(map[string]bool)
+			}
+			for _, s := range inReq.SerialNumbers {
+				inspectedSerials[line.ID][s] = true
+			}
+		}
+
+		now := tools.GetCurrentTime()
+		status := returnStatusAfterInspection(lines)
+		updates := map[string]interface{}{"status": status, "updated_at": now}
+		if status == database.CustomerReturnReceived {
+			updates["received_at"] = now
+		}
+		if err := tx.Model(&database.CustomerReturn{}).Where("id = ?", ret.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update customer return: %w", err)
+		}
+		return nil
+	})
+	// Handled rejections return an error from the transaction so nothing an earlier
+	// inspection of the same request wrote is committed.
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return nil, resp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al recibir la devolución"}
+	}
+	return r.GetReturn(id, tenantID)
+}
+
+func (r *CustomerReturnsRepository) CancelReturn(id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse) {
+	var result *database.CustomerReturn
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		ret, resp, err := lockCustomerReturn(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		if ret.Status != database.CustomerReturnAuthorized {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Solo se puede cancelar una devolución autorizada sin recepciones (estado actual: '%s')", ret.Status),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		now := tools.GetCurrentTime()
+		if err := tx.Model(&database.CustomerReturn{}).Where("id = ?", ret.ID).Updates(map[string]interface{}{
+			"status":       database.CustomerReturnCancelled,
+			"cancelled_at": now,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return fmt.Errorf("cancel customer return: %w", err)
+		}
+		if err := tx.Where("id = ?", ret.ID).First(ret).Error; err != nil {
+			return fmt.Errorf("reload customer return: %w", err)
+		}
+		result = ret
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al cancelar la devolución"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
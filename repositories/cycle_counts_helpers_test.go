@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidCountTaskTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		want    bool
+	}{
+		{"open → in_progress", "open", "in_progress", true},
+		{"open → cancelled", "open", "cancelled", true},
+		{"in_progress → pending_approval", "in_progress", "pending_approval", true},
+		{"in_progress → cancelled", "in_progress", "cancelled", true},
+		{"pending_approval → approved", "pending_approval", "approved", true},
+		{"pending_approval → in_progress (reject)", "pending_approval", "in_progress", true},
+		{"pending_approval → cancelled", "pending_approval", "cancelled", true},
+
+		{"no-op approved", "approved", "approved", true},
+
+		{"open → approved (skips counting)", "open", "approved", false},
+		{"open → pending_approval", "open", "pending_approval", false},
+		{"in_progress → approved (skips approval)", "in_progress", "approved", false},
+		{"approved → cancelled (final)", "approved", "cancelled", false},
+		{"cancelled → open (final)", "cancelled", "open", false},
+		{"unknown origin", "foo", "open", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidCountTaskTransition(tt.current, tt.next))
+		})
+	}
+}
+
+func TestClassifyABC(t *testing.T) {
+	t.Run("pareto split", func(t *testing.T) {
+		classes := classifyABC(map[string]float64{
+			"SKU-A1": 700,
+			"SKU-A2": 150, // cumulative before = 700 (70%) → A
+			"SKU-B1": 100, // cumulative before = 850 (85%) → B
+			"SKU-C1": 40,  // cumulative before = 950 (95%) → C
+			"SKU-C2": 10,
+			"SKU-0":  0,
+		})
+		assert.Equal(t, "A", classes["SKU-A1"])
+		assert.Equal(t, "A", classes["SKU-A2"])
+		assert.Equal(t, "B", classes["SKU-B1"])
+		assert.Equal(t, "C", classes["SKU-C1"])
+		assert.Equal(t, "C", classes["SKU-C2"])
+		assert.Equal(t, "C", classes["SKU-0"])
+	})
+
+	t.Run("no movement value", func(t *testing.T) {
+		classes := classifyABC(map[string]float64{"SKU-1": 0, "SKU-2": 0})
+		assert.Equal(t, "C", classes["SKU-1"])
+		assert.Equal(t, "C", classes["SKU-2"])
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		assert.Empty(t, classifyABC(nil))
+	})
+}
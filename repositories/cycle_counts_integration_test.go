@@ -0,0 +1,96 @@
+// Integration tests for cycle count approval.
+// Requires Docker (testcontainers). Skipped automatically in -short mode.
+// Run: go test -v ./repositories/... -run TestCycleCounts
+
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedPendingCountTask inserts a pending_approval task with one line counted as counted for
+// sku@location, expecting expected. Returns the task id and the line id.
+func seedPendingCountTask(t *testing.T, db *gorm.DB, tenantID, sku, location string, expected, counted float64) (string, string) {
+	t.Helper()
+	taskID, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO count_tasks (id, tenant_id, task_number, status, blind, created_at, updated_at)
+		VALUES (?, ?::uuid, ?, 'pending_approval', true, NOW(), NOW())`,
+		taskID, tenantID, "CC-TEST-"+taskID[:6]).Error)
+	lineID, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO count_task_lines (id, count_task_id, sku, location, expected_qty, counted_qty, status)
+		VALUES (?, ?, ?, ?, ?, ?, 'counted')`,
+		lineID, taskID, sku, location, expected, counted).Error)
+	return taskID, lineID
+}
+
+// TestCycleCounts_ApproveTask_PostsAgainstCurrentOnHand: 5 units are picked after the task
+// was generated (snapshot 100, on hand 95) and the count finds 97. The approval adjusts by
+// +2 so the location ends at the counted 97, not at 92.
+func TestCycleCounts_ApproveTask_PostsAgainstCurrentOnHand(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	userID := seedUser(t, db)
+	seedArticleRow(t, db, testTenantA, "SKU-CC-APPROVE", "Counted article")
+	seedInventoryRowForTenant(t, db, testTenantA, "SKU-CC-APPROVE", "CC-A", 95)
+	taskID, lineID := seedPendingCountTask(t, db, testTenantA, "SKU-CC-APPROVE", "CC-A", 100, 97)
+
+	repo := &CycleCountsRepository{DB: db}
+	task, lines, resp := repo.ApproveTask(taskID, testTenantA, userID, "cycle_count_gain", "cycle_count_loss")
+	require.Nil(t, resp)
+	assert.Equal(t, "approved", task.Status)
+	require.Len(t, lines, 1)
+	require.NotNil(t, lines[0].AdjustmentID)
+
+	var inv database.Inventory
+	require.NoError(t, db.Where("tenant_id = ? AND sku = ? AND location = ?", testTenantA, "SKU-CC-APPROVE", "CC-A").First(&inv).Error)
+	assert.InDelta(t, 97, inv.Quantity, 1e-9)
+
+	var adj database.Adjustment
+	require.NoError(t, db.Where("id = ?", *lines[0].AdjustmentID).First(&adj).Error)
+	assert.Equal(t, 2, adj.AdjustmentQty)
+	assert.Equal(t, "cycle_count_gain", adj.Reason)
+
+	var line database.CountTaskLine
+	require.NoError(t, db.Where("id = ?", lineID).First(&line).Error)
+	assert.Equal(t, adj.ID, *line.AdjustmentID)
+}
+
+// TestCycleCounts_ApproveTask_SecondApprovalRejected: once approved, a repeated approval is
+// refused and posts nothing.
+func TestCycleCounts_ApproveTask_SecondApprovalRejected(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	userID := seedUser(t, db)
+	seedArticleRow(t, db, testTenantA, "SKU-CC-TWICE", "Counted article")
+	seedInventoryRowForTenant(t, db, testTenantA, "SKU-CC-TWICE", "CC-B", 10)
+	taskID, _ := seedPendingCountTask(t, db, testTenantA, "SKU-CC-TWICE", "CC-B", 10, 8)
+
+	repo := &CycleCountsRepository{DB: db}
+	_, _, resp := repo.ApproveTask(taskID, testTenantA, userID, "cycle_count_gain", "cycle_count_loss")
+	require.Nil(t, resp)
+
+	_, _, resp = repo.ApproveTask(taskID, testTenantA, userID, "cycle_count_gain", "cycle_count_loss")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+
+	var adjustments int64
+	require.NoError(t, db.Table("adjustments").Where("sku = ?", "SKU-CC-TWICE").Count(&adjustments).Error)
+	assert.Equal(t, int64(1), adjustments)
+
+	var inv database.Inventory
+	require.NoError(t, db.Where("tenant_id = ? AND sku = ? AND location = ?", testTenantA, "SKU-CC-TWICE", "CC-B").First(&inv).Error)
+	assert.InDelta(t, 8, inv.Quantity, 1e-9)
+}
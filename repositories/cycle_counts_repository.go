@@ -0,0 +1,695 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CycleCountsRepository implements ports.CycleCountsRepository using GORM.
+// Consistent with PurchaseOrdersRepository (GORM-based, raw SQL where needed).
+type CycleCountsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.CycleCountsRepository = (*CycleCountsRepository)(nil)
+
+// abcLookbackDays is the movement window used to rank SKUs by outbound value
+// when a plan is scoped by ABC class.
+const abcLookbackDays = 90
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// validCountTaskTransitions declares the allowed count task status changes.
+// pending_approval → in_progress is the supervisor rejecting lines for recount.
+// Final states (approved, cancelled) have no outgoing transition.
+var validCountTaskTransitions = map[string]map[string]bool{
+	"open":             {"in_progress": true, "cancelled": true},
+	"in_progress":      {"pending_approval": true, "cancelled": true},
+	"pending_approval": {"approved": true, "in_progress": true, "cancelled": true},
+}
+
+// isValidCountTaskTransition returns true when current → next is allowed.
+// No-op (same → same) is always true; final states have no outgoing transition.
+func isValidCountTaskTransition(current, next string) bool {
+	if current == next {
+		return true
+	}
+	if allowed, ok := validCountTaskTransitions[current]; ok {
+		return allowed[next]
+	}
+	return false
+}
+
+// nextCountTaskNumber generates "CC-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber so the empty-table case is serialized too.
+func nextCountTaskNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("CC-%d-", year)
+
+	lockKey := fmt.Sprintf("cc-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire CC number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(task_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM count_tasks
+		WHERE tenant_id = $2
+		  AND task_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate CC number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// classifyABC ranks SKUs by value (descending) and assigns Pareto classes:
+// the SKUs that make up the first 80% of the total value are "A", the next 15% "B",
+// and the rest "C". A SKU straddling a boundary takes the class where its value starts.
+// When the total value is zero every SKU is "C".
+func classifyABC(values map[string]float64) map[string]string {
+	type skuValue struct {
+		SKU   string
+		Value float64
+	}
+	ranked := make([]skuValue, 0, len(values))
+	total := 0.0
+	for sku, v := range values {
+		if v < 0 {
+			v = 0
+		}
+		ranked = append(ranked, skuValue{SKU: sku, Value: v})
+		total += v
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Value != ranked[j].Value {
+			return ranked[i].Value > ranked[j].Value
+		}
+		return ranked[i].SKU < ranked[j].SKU
+	})
+
+	classes := make(map[string]string, len(ranked))
+	cumulative := 0.0
+	for _, r := range ranked {
+		switch {
+		case total == 0 || r.Value == 0:
+			classes[r.SKU] = "C"
+		case cumulative < 0.80*total:
+			classes[r.SKU] = "A"
+		case cumulative < 0.95*total:
+			classes[r.SKU] = "B"
+		default:
+			classes[r.SKU] = "C"
+		}
+		cumulative += r.Value
+	}
+	return classes
+}
+
+// skusByABCClass returns the tenant SKUs whose ABC class is in wanted.
+func skusByABCClass(tx *gorm.DB, tenantID string, wanted []string) ([]string, error) {
+	type usageRow struct {
+		SKU   string  `gorm:"column:sku"`
+		Value float64 `gorm:"column:value"`
+	}
+	var rows []usageRow
+	if err := tx.Raw(`
+		SELECT a.sku,
+		       COALESCE(SUM(ABS(m.quantity) * COALESCE(m.unit_cost, a.unit_price, 0)), 0) AS value
+		  FROM articles a
+		  LEFT JOIN inventory_movements m
+		    ON m.sku = a.sku
+		   AND m.movement_type = 'outbound'
+		   AND m.created_at >= NOW() - make_interval(days => ?)
+		 WHERE a.tenant_id = ?
+		 GROUP BY a.sku
+	`, abcLookbackDays, tenantID).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("compute ABC usage: %w", err)
+	}
+
+	values := make(map[string]float64, len(rows))
+	for _, r := range rows {
+		values[r.SKU] = r.Value
+	}
+	classes := classifyABC(values)
+
+	want := make(map[string]bool, len(wanted))
+	for _, w := range wanted {
+		want[w] = true
+	}
+	skus := make([]string, 0)
+	for sku, class := range classes {
+		if want[class] {
+			skus = append(skus, sku)
+		}
+	}
+	sort.Strings(skus)
+	return skus, nil
+}
+
+// countScopeRow is an inventory row resolved from a plan scope.
+type countScopeRow struct {
+	SKU      string  `gorm:"column:sku"`
+	Location string  `gorm:"column:location"`
+	Quantity float64 `gorm:"column:quantity"`
+}
+
+// resolveCountScope returns the inventory rows covered by the plan scope, ordered by
+// location then SKU (walk order for the counter). Tenant scoping goes through articles
+// because inventory rows are matched to the tenant catalog by SKU.
+func resolveCountScope(tx *gorm.DB, plan *database.CountPlan) ([]countScopeRow, error) {
+	q := tx.Table("inventory i").
+		Select("i.sku, i.location, i.quantity").
+		Joins("JOIN articles a ON a.sku = i.sku AND a.tenant_id = ?", plan.TenantID)
+
+	values := []string(plan.ScopeValues)
+	switch plan.ScopeType {
+	case "zone":
+		q = q.Joins("JOIN locations l ON l.location_code = i.location AND l.tenant_id = ?", plan.TenantID).
+			Where("l.zone IN ?", values)
+	case "location":
+		q = q.Where("i.location IN ?", values)
+	case "category":
+		q = q.Where("a.category_id IN ?", values)
+	case "abc_class":
+		skus, err := skusByABCClass(tx, plan.TenantID, values)
+		if err != nil {
+			return nil, err
+		}
+		if len(skus) == 0 {
+			return nil, nil
+		}
+		q = q.Where("i.sku IN ?", skus)
+	default:
+		return nil, fmt.Errorf("unsupported scope_type %q", plan.ScopeType)
+	}
+
+	var rows []countScopeRow
+	if err := q.Order("i.location ASC, i.sku ASC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("resolve count scope: %w", err)
+	}
+	return rows, nil
+}
+
+// loadCountPlan fetches a non-deleted plan scoped to tenantID.
+func (r *CycleCountsRepository) loadCountPlan(db *gorm.DB, id, tenantID string) (*database.CountPlan, *responses.InternalResponse) {
+	var plan database.CountPlan
+	if err := db.Where("id = ? AND tenant_id = ? AND deleted_at IS NULL", id, tenantID).First(&plan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Plan de conteo no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el plan de conteo"}
+	}
+	return &plan, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count plans
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *CycleCountsRepository) CreatePlan(tenantID, createdBy string, req *requests.CreateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	id, err := tools.GenerateNanoid(r.DB)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al generar el ID del plan de conteo"}
+	}
+
+	blind := true
+	if req.Blind != nil {
+		blind = *req.Blind
+	}
+
+	now := tools.GetCurrentTime()
+	plan := database.CountPlan{
+		ID:                  id,
+		TenantID:            tenantID,
+		Name:                req.Name,
+		Description:         req.Description,
+		ScopeType:           req.ScopeType,
+		ScopeValues:         pq.StringArray(req.ScopeValues),
+		Blind:               blind,
+		RecountThresholdPct: req.RecountThresholdPct,
+		RecountThresholdQty: req.RecountThresholdQty,
+		ReasonCode:          req.ReasonCode,
+		IsActive:            true,
+		CreatedBy:           &createdBy,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	if err := r.DB.Create(&plan).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear el plan de conteo"}
+	}
+	return &plan, nil
+}
+
+func (r *CycleCountsRepository) GetPlanByID(id, tenantID string) (*database.CountPlan, *responses.InternalResponse) {
+	return r.loadCountPlan(r.DB, id, tenantID)
+}
+
+func (r *CycleCountsRepository) ListPlans(tenantID string) ([]database.CountPlan, *responses.InternalResponse) {
+	var plans []database.CountPlan
+	if err := r.DB.Where("tenant_id = ? AND deleted_at IS NULL", tenantID).
+		Order("created_at DESC").
+		Find(&plans).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los planes de conteo"}
+	}
+	return plans, nil
+}
+
+func (r *CycleCountsRepository) UpdatePlan(id, tenantID string, req *requests.UpdateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	plan, resp := r.loadCountPlan(r.DB, id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	updates := map[string]interface{}{
+		"updated_at": tools.GetCurrentTime(),
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = req.Description
+	}
+	if req.ScopeType != nil {
+		updates["scope_type"] = *req.ScopeType
+	}
+	if req.ScopeValues != nil {
+		updates["scope_values"] = pq.StringArray(req.ScopeValues)
+	}
+	if req.Blind != nil {
+		updates["blind"] = *req.Blind
+	}
+	if req.RecountThresholdPct != nil {
+		updates["recount_threshold_pct"] = req.RecountThresholdPct
+	}
+	if req.RecountThresholdQty != nil {
+		updates["recount_threshold_qty"] = req.RecountThresholdQty
+	}
+	if req.ReasonCode != nil {
+		updates["reason_code"] = req.ReasonCode
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if err := r.DB.Model(plan).Updates(updates).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al actualizar el plan de conteo"}
+	}
+	return r.loadCountPlan(r.DB, id, tenantID)
+}
+
+func (r *CycleCountsRepository) SoftDeletePlan(id, tenantID string) *responses.InternalResponse {
+	plan, resp := r.loadCountPlan(r.DB, id, tenantID)
+	if resp != nil {
+		return resp
+	}
+
+	now := tools.GetCurrentTime()
+	if err := r.DB.Model(plan).Updates(map[string]interface{}{
+		"deleted_at": now,
+		"updated_at": now,
+	}).Error; err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al eliminar el plan de conteo"}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count tasks
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *CycleCountsRepository) GenerateTask(plan *database.CountPlan, createdBy string, req *requests.GenerateCountTaskRequest) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	var task *database.CountTask
+	var lines []database.CountTaskLine
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := resolveCountScope(tx, plan)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    "El alcance del plan no contiene inventario para contar",
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+
+		taskNumber, err := nextCountTaskNumber(tx, plan.TenantID)
+		if err != nil {
+			return err
+		}
+
+		taskID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate count task id: %w", err)
+		}
+
+		now := tools.GetCurrentTime()
+		planID := plan.ID
+		t := database.CountTask{
+			ID:                  taskID,
+			TenantID:            plan.TenantID,
+			TaskNumber:          taskNumber,
+			CountPlanID:         &planID,
+			Status:              "open",
+			Blind:               plan.Blind,
+			RecountThresholdPct: plan.RecountThresholdPct,
+			RecountThresholdQty: plan.RecountThresholdQty,
+			ReasonCode:          plan.ReasonCode,
+			CreatedBy:           &createdBy,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if req != nil {
+			t.AssignedTo = req.AssignedTo
+			t.Notes = req.Notes
+		}
+		if err := tx.Create(&t).Error; err != nil {
+			return fmt.Errorf("create count task: %w", err)
+		}
+
+		lines = make([]database.CountTaskLine, 0, len(rows))
+		for _, row := range rows {
+			lineID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate count line id: %w", err)
+			}
+			line := database.CountTaskLine{
+				ID:          lineID,
+				CountTaskID: taskID,
+				SKU:         row.SKU,
+				Location:    row.Location,
+				ExpectedQty: row.Quantity,
+				Status:      "pending",
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := tx.Create(&line).Error; err != nil {
+				return fmt.Errorf("create count line %s@%s: %w", row.SKU, row.Location, err)
+			}
+			lines = append(lines, line)
+		}
+
+		task = &t
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, &responses.InternalResponse{Error: err, Message: "Error al generar la tarea de conteo"}
+	}
+	if handledResp.Handled {
+		return nil, nil, handledResp
+	}
+	return task, lines, nil
+}
+
+func (r *CycleCountsRepository) GetTaskByID(id, tenantID string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	var task database.CountTask
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, &responses.InternalResponse{Message: "Tarea de conteo no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la tarea de conteo"}
+	}
+
+	var lines []database.CountTaskLine
+	if err := r.DB.Where("count_task_id = ?", id).
+		Order("location ASC, sku ASC").
+		Find(&lines).Error; err != nil {
+		return nil, nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las líneas de la tarea de conteo"}
+	}
+	return &task, lines, nil
+}
+
+func (r *CycleCountsRepository) ListTasks(tenantID string, status *string, limit, offset int) ([]database.CountTask, *responses.InternalResponse) {
+	query := r.DB.Model(&database.CountTask{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var tasks []database.CountTask
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las tareas de conteo"}
+	}
+	return tasks, nil
+}
+
+func (r *CycleCountsRepository) TransitionTask(id, tenantID, next, userID string) (*database.CountTask, *responses.InternalResponse) {
+	var result *database.CountTask
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var task database.CountTask
+		if err := tx.Where("id = ? AND tenant_id = ?", id, tenantID).First(&task).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{Message: "Tarea de conteo no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return fmt.Errorf("load count task: %w", err)
+		}
+
+		if task.Status == next || !isValidCountTaskTransition(task.Status, next) {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Transición inválida: %s → %s", task.Status, next),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{
+			"status":     next,
+			"updated_at": now,
+		}
+		switch next {
+		case "in_progress":
+			if task.StartedAt == nil {
+				updates["started_at"] = now
+			}
+		case "pending_approval":
+			updates["submitted_at"] = now
+		case "approved":
+			updates["approved_at"] = now
+			updates["approved_by"] = userID
+		case "cancelled":
+			updates["cancelled_at"] = now
+		}
+
+		// Optimistic guard: only move from the status we just validated.
+		res := tx.Model(&database.CountTask{}).
+			Where("id = ? AND status = ?", task.ID, task.Status).
+			Updates(updates)
+		if res.Error != nil {
+			return fmt.Errorf("update count task status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    "La tarea de conteo fue modificada por otro usuario; recargue e intente de nuevo",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		if err := tx.Where("id = ?", task.ID).First(&task).Error; err != nil {
+			return fmt.Errorf("reload count task: %w", err)
+		}
+		result = &task
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al actualizar el estado de la tarea de conteo"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
+
+func (r *CycleCountsRepository) SaveLineCount(line *database.CountTaskLine) *responses.InternalResponse {
+	if err := r.DB.Model(&database.CountTaskLine{}).
+		Where("id = ?", line.ID).
+		Updates(map[string]interface{}{
+			"counted_qty":  line.CountedQty,
+			"recount_qty":  line.RecountQty,
+			"status":       line.Status,
+			"counted_by":   line.CountedBy,
+			"counted_at":   line.CountedAt,
+			"recounted_by": line.RecountedBy,
+			"recounted_at": line.RecountedAt,
+			"notes":        line.Notes,
+			"updated_at":   tools.GetCurrentTime(),
+		}).Error; err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al registrar el conteo"}
+	}
+	return nil
+}
+
+func (r *CycleCountsRepository) FlagLinesForRecount(taskID string, lineIDs []string) *responses.InternalResponse {
+	res := r.DB.Model(&database.CountTaskLine{}).
+		Where("count_task_id = ? AND id IN ?", taskID, lineIDs).
+		Updates(map[string]interface{}{
+			"status":       "recount_required",
+			"recount_qty":  nil,
+			"recounted_by": nil,
+			"recounted_at": nil,
+			"updated_at":   tools.GetCurrentTime(),
+		})
+	if res.Error != nil {
+		return &responses.InternalResponse{Error: res.Error, Message: "Error al solicitar el reconteo"}
+	}
+	if res.RowsAffected != int64(len(lineIDs)) {
+		return &responses.InternalResponse{
+			Message:    "Una o más líneas no pertenecen a la tarea de conteo",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+// countQtyEpsilon absorbs float noise when comparing NUMERIC(12,3) counts with on-hand quantities.
+const countQtyEpsilon = 0.0005
+
+func (r *CycleCountsRepository) ApproveTask(id, tenantID, userID, gainReason, lossReason string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	var task database.CountTask
+	var lines []database.CountTaskLine
+	var handledResp *responses.InternalResponse
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the task so concurrent approvals serialize and only the first one posts.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).First(&task).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				handledResp = &responses.InternalResponse{Message: "Tarea de conteo no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return fmt.Errorf("count task not found")
+			}
+			return fmt.Errorf("lock count task: %w", err)
+		}
+		if task.Status != "pending_approval" {
+			handledResp = &responses.InternalResponse{
+				Message:    fmt.Sprintf("Solo se pueden aprobar tareas pendientes de aprobación (estado actual: %q)", task.Status),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return fmt.Errorf("count task not pending approval")
+		}
+
+		if err := tx.Where("count_task_id = ?", id).
+			Order("location ASC, sku ASC").
+			Find(&lines).Error; err != nil {
+			return fmt.Errorf("load count task lines: %w", err)
+		}
+
+		for i := range lines {
+			line := &lines[i]
+			if line.AdjustmentID != nil {
+				continue
+			}
+			final, ok := line.FinalQty()
+			if !ok {
+				continue
+			}
+
+			// The variance is taken against the stock on hand now, not the snapshot of task
+			// generation, so picks and receipts made since then are not undone.
+			var onHand float64
+			if err := tx.Raw(
+				"SELECT quantity FROM inventory WHERE tenant_id = ? AND sku = ? AND location = ? FOR UPDATE",
+				tenantID, line.SKU, line.Location,
+			).Scan(&onHand).Error; err != nil {
+				return fmt.Errorf("lock inventory %s@%s: %w", line.SKU, line.Location, err)
+			}
+			variance := final - onHand
+			if math.Abs(variance) < countQtyEpsilon {
+				continue
+			}
+
+			reason := gainReason
+			if variance < 0 {
+				reason = lossReason
+			}
+			if task.ReasonCode != nil && *task.ReasonCode != "" {
+				reason = *task.ReasonCode
+			}
+
+			adj, err := createAdjustment(tx, userID, tenantID, requests.CreateAdjustment{
+				SKU:                line.SKU,
+				Location:           line.Location,
+				AdjustmentQuantity: variance,
+				Reason:             reason,
+				Notes:              fmt.Sprintf("Conteo cíclico %s", task.TaskNumber),
+				AdjustmentType:     "count_reconcile",
+			})
+			if err != nil {
+				if resp := adjustmentErrorResponse(err); resp.Handled {
+					handledResp = resp
+				}
+				return err
+			}
+			if err := tx.Model(&database.CountTaskLine{}).
+				Where("id = ?", line.ID).
+				Updates(map[string]interface{}{
+					"adjustment_id": adj.ID,
+					"updated_at":    tools.GetCurrentTime(),
+				}).Error; err != nil {
+				return fmt.Errorf("link adjustment to count line: %w", err)
+			}
+			line.AdjustmentID = &adj.ID
+		}
+
+		now := tools.GetCurrentTime()
+		if err := tx.Model(&database.CountTask{}).
+			Where("id = ?", task.ID).
+			Updates(map[string]interface{}{
+				"status":      "approved",
+				"approved_at": now,
+				"approved_by": userID,
+				"updated_at":  now,
+			}).Error; err != nil {
+			return fmt.Errorf("approve count task: %w", err)
+		}
+		if err := tx.Where("id = ?", task.ID).First(&task).Error; err != nil {
+			return fmt.Errorf("reload count task: %w", err)
+		}
+		return nil
+	})
+
+	if handledResp != nil {
+		return nil, nil, handledResp
+	}
+	if err != nil {
+		return nil, nil, &responses.InternalResponse{Error: err, Message: "Error al aprobar la tarea de conteo"}
+	}
+	return &task, lines, nil
+}
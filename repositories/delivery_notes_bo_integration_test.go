@@ -31,11 +31,11 @@ func seedInventoryForDN(t *testing.T, db *gorm.DB, sku, location string, qty flo
 	invID, err := tools.GenerateNanoid(db)
 	require.NoError(t, err)
 	require.NoError(t, db.Exec(`
-		INSERT INTO inventory (id, sku, location, quantity, reserved_qty, created_at, updated_at)
-		VALUES (?, ?, ?, ?, 0, NOW(), NOW())
+		INSERT INTO inventory (id, tenant_id, sku, location, quantity, reserved_qty, created_at, updated_at)
+		VALUES (?, (SELECT tenant_id FROM articles WHERE sku = ?), ?, ?, ?, 0, NOW(), NOW())
 		ON CONFLICT (sku, location) DO UPDATE
 		SET quantity = EXCLUDED.quantity, reserved_qty = 0, updated_at = NOW()`,
-		invID, sku, location, qty).Error)
+		invID, sku, sku, location, qty).Error)
 }
 
 // seedSOForDN inserts a sales order with tenant_id set.
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesOrderDelivered(t *testing.T) {
+	assert.True(t, salesOrderDelivered("completed", 0, 0))
+	assert.False(t, salesOrderDelivered("completed", 1, 0), "another delivery note is still undelivered")
+	assert.False(t, salesOrderDelivered("completed", 0, 1), "a shipment is still being packed")
+	assert.False(t, salesOrderDelivered("partial", 0, 0), "backordered lines are still open")
+	assert.False(t, salesOrderDelivered("delivered", 0, 0))
+}
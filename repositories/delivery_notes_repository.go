@@ -8,7 +8,10 @@ import (
 
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DeliveryNotesRepository implements ports.DeliveryNotesRepository using GORM.
@@ -56,23 +59,35 @@ func toDNItemResponse(item *database.DeliveryNoteItem) responses.DeliveryNoteIte
 	lots := make([]string, len(item.LotNumbers))
 	copy(lots, item.LotNumbers)
 	return responses.DeliveryNoteItemResponse{
-		ID:             item.ID,
-		DeliveryNoteID: item.DeliveryNoteID,
-		ArticleSKU:     item.ArticleSKU,
-		Qty:            item.Qty,
-		LotNumbers:     lots,
-		Notes:          item.Notes,
-		CreatedAt:      item.CreatedAt,
+		ID:                 item.ID,
+		DeliveryNoteID:     item.DeliveryNoteID,
+		ArticleSKU:         item.ArticleSKU,
+		Qty:                item.Qty,
+		LotNumbers:         lots,
+		Notes:              item.Notes,
+		CreatedAt:          item.CreatedAt,
+		PackageID:          item.PackageID,
+		Presentation:       item.Presentation,
+		PresentationFactor: item.PresentationFactor,
 	}
 }
 
+// dnPDFURL returns the API download URL of a DN's PDF once it has been stored.
+func dnPDFURL(dnID string, storageKey *string) *string {
+	if storageKey == nil {
+		return nil
+	}
+	url := services.PDFAPIURL(dnID)
+	return &url
+}
+
 // toDNResponse builds the full response from header + items.
 func toDNResponse(dn *database.DeliveryNote, items []database.DeliveryNoteItem, customerName *string) *responses.DeliveryNoteResponse {
 	itemsResp := make([]responses.DeliveryNoteItemResponse, 0, len(items))
 	for i := range items {
 		itemsResp = append(itemsResp, toDNItemResponse(&items[i]))
 	}
-	return &responses.DeliveryNoteResponse{
+	resp := &responses.DeliveryNoteResponse{
 		ID:             dn.ID,
 		DNNumber:       dn.DNNumber,
 		SalesOrderID:   dn.SalesOrderID,
@@ -80,14 +95,22 @@ func toDNResponse(dn *database.DeliveryNote, items []database.DeliveryNoteItem,
 		CustomerID:     dn.CustomerID,
 		CustomerName:   customerName,
 		TotalItems:     dn.TotalItems,
-		PdfURL:         dn.PdfURL,
+		PdfURL:         dnPDFURL(dn.ID, dn.PdfStorageKey),
 		PdfGeneratedAt: dn.PdfGeneratedAt,
 		DeliveredAt:    dn.DeliveredAt,
 		SignedBy:       dn.SignedBy,
 		Items:          itemsResp,
 		CreatedAt:      dn.CreatedAt,
 		UpdatedAt:      dn.UpdatedAt,
+		PODGPS:         dn.PODGPS,
+		PODNotes:       dn.PODNotes,
+		PODRecordedBy:  dn.PODRecordedBy,
 	}
+	resp.PdfSHA256 = dn.PdfSHA256
+	resp.PdfStorageKey = dn.PdfStorageKey
+	resp.PODSignaturePath = dn.PODSignaturePath
+	resp.PODPhotoPaths = append([]string(nil), dn.PODPhotoPaths...)
+	return resp
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -103,11 +126,15 @@ type DNCreationParams struct {
 	Items         []DNItemCreationParam
 }
 
-// DNItemCreationParam is one line item for DN creation.
+// DNItemCreationParam is one line item for DN creation. PackageID is set when the item was
+// packed (one item per package + SKU + lot). SerialNumbers are the units that leave on the
+// item; they move to 'shipped' with the delivery note as document.
 type DNItemCreationParam struct {
-	ArticleSKU string
-	Qty        float64
-	LotNumbers []string
+	ArticleSKU    string
+	Qty           float64
+	LotNumbers    []string
+	PackageID     *string
+	SerialNumbers []string
 }
 
 // CreateDeliveryNote inserts a delivery_note header + items in a transaction.
@@ -133,11 +160,18 @@ func CreateDeliveryNote(tx *gorm.DB, params DNCreationParams) (string, error) {
 		PickingTaskID: &ptID,
 		CustomerID:    params.CustomerID,
 		TotalItems:    len(params.Items),
+		PODPhotoPaths: pq.StringArray{},
 	}
 	if err := tx.Create(dn).Error; err != nil {
 		return "", fmt.Errorf("create delivery_note: %w", err)
 	}
 
+	// Lines keep the presentation the sales order line was entered in (Qty stays in base units).
+	entered, err := salesOrderLinePresentations(tx, params.SalesOrderID)
+	if err != nil {
+		return "", err
+	}
+
 	for _, it := range params.Items {
 		var itemID string
 		if err := tx.Raw("SELECT nanoid()").Scan(&itemID).Error; err != nil {
@@ -151,10 +185,20 @@ func CreateDeliveryNote(tx *gorm.DB, params DNCreationParams) (string, error) {
 			ArticleSKU:     it.ArticleSKU,
 			Qty:            it.Qty,
 			LotNumbers:     lots,
+			PackageID:      it.PackageID,
+		}
+		if uom, ok := entered[it.ArticleSKU]; ok {
+			dni.Presentation = uom.Presentation
+			dni.PresentationFactor = uom.Factor
 		}
 		if err := tx.Create(dni).Error; err != nil {
 			return "", fmt.Errorf("create delivery_note_item %s: %w", it.ArticleSKU, err)
 		}
+		if err := shipSerials(tx, params.TenantID, it.ArticleSKU, it.SerialNumbers, serialEvent{
+			DocumentType: "delivery_note", DocumentID: dnID, DocumentNumber: dnNumber,
+		}); err != nil {
+			return "", err
+		}
 	}
 
 	return dnID, nil
@@ -231,7 +275,7 @@ func (r *DeliveryNotesRepository) List(tenantID string, customerID, soNumber *st
 			CustomerID:     row.CustomerID,
 			CustomerName:   namePtr,
 			TotalItems:     row.TotalItems,
-			PdfURL:         row.PdfURL,
+			PdfURL:         dnPDFURL(row.ID, row.PdfStorageKey),
 			PdfGeneratedAt: row.PdfGeneratedAt,
 			DeliveredAt:    row.DeliveredAt,
 			CreatedAt:      row.CreatedAt,
@@ -281,20 +325,48 @@ func (r *DeliveryNotesRepository) GetByID(id, tenantID string) (*responses.Deliv
 		customerName = &client.Name
 	}
 
-	return toDNResponse(&dn, items, customerName), nil
+	resp := toDNResponse(&dn, items, customerName)
+
+	// Packed items show the SSCC of their package.
+	var packageIDs []string
+	for _, it := range items {
+		if it.PackageID != nil {
+			packageIDs = append(packageIDs, *it.PackageID)
+		}
+	}
+	if len(packageIDs) > 0 {
+		var pkgs []database.Package
+		if err := r.DB.Select("id, sscc").Where("id IN ?", packageIDs).Find(&pkgs).Error; err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al cargar bultos de nota de entrega"}
+		}
+		sscc := make(map[string]string, len(pkgs))
+		for _, p := range pkgs {
+			sscc[p.ID] = p.SSCC
+		}
+		for i := range resp.Items {
+			if id := resp.Items[i].PackageID; id != nil {
+				if code, ok := sscc[*id]; ok {
+					resp.Items[i].SSCC = &code
+				}
+			}
+		}
+	}
+
+	return resp, nil
 }
 
-// UpdatePDFURL sets pdf_url and pdf_generated_at on a delivery note.
-func (r *DeliveryNotesRepository) UpdatePDFURL(id, pdfURL string) *responses.InternalResponse {
+// UpdatePDFStorageKey records the storage key and checksum of a delivery note's PDF.
+func (r *DeliveryNotesRepository) UpdatePDFStorageKey(id, storageKey, checksum string) *responses.InternalResponse {
 	now := time.Now()
 	if err := r.DB.Table("delivery_notes").
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"pdf_url":          pdfURL,
+			"pdf_storage_key":  storageKey,
+			"pdf_sha256":       checksum,
 			"pdf_generated_at": now,
 			"updated_at":       now,
 		}).Error; err != nil {
-		return &responses.InternalResponse{Error: err, Message: "Error al actualizar PDF URL"}
+		return &responses.InternalResponse{Error: err, Message: "Error al actualizar el PDF de la nota de entrega"}
 	}
 	return nil
 }
@@ -319,3 +391,95 @@ func (r *DeliveryNotesRepository) GetDNNumber(id, tenantID string) (string, *res
 	}
 	return result.DNNumber, nil
 }
+
+// salesOrderDelivered reports whether a sales order reaches the delivered status: it must be
+// completed, with every delivery note delivered and no shipment still being packed (its
+// delivery note does not exist yet).
+func salesOrderDelivered(status string, undeliveredNotes, packingShipments int64) bool {
+	return status == "completed" && undeliveredNotes == 0 && packingShipments == 0
+}
+
+// RecordPOD stores the proof of delivery on a delivery note and, when it was the last
+// undelivered note of a completed sales order, moves the order to delivered. A note keeps its
+// first proof of delivery: recording another one is a conflict.
+func (r *DeliveryNotesRepository) RecordPOD(id, tenantID string, pod *database.ProofOfDelivery) (*database.SalesOrder, *responses.InternalResponse) {
+	var so database.SalesOrder
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var dn database.DeliveryNote
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).First(&dn).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{
+					Message:    "Nota de entrega no encontrada",
+					Handled:    true,
+					StatusCode: responses.StatusNotFound,
+				}
+				return nil
+			}
+			return err
+		}
+		if dn.DeliveredAt != nil {
+			*handledResp = responses.InternalResponse{
+				Message:    "La nota de entrega ya tiene prueba de entrega registrada",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		photos := pq.StringArray(append([]string{}, pod.PhotoPaths...))
+		if err := tx.Model(&database.DeliveryNote{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"delivered_at":       pod.DeliveredAt,
+			"signed_by":          pod.ReceiverName,
+			"pod_signature_path": pod.SignaturePath,
+			"pod_photo_paths":    photos,
+			"pod_gps":            pod.GPS,
+			"pod_notes":          pod.Notes,
+			"pod_recorded_by":    pod.RecordedBy,
+			"updated_at":         time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", dn.SalesOrderID, tenantID).First(&so).Error; err != nil {
+			return err
+		}
+
+		var undelivered, packing int64
+		if err := tx.Model(&database.DeliveryNote{}).
+			Where("sales_order_id = ? AND tenant_id = ? AND delivered_at IS NULL", so.ID, tenantID).
+			Count(&undelivered).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&database.Shipment{}).
+			Where("sales_order_id = ? AND tenant_id = ? AND status = ?", so.ID, tenantID, "packing").
+			Count(&packing).Error; err != nil {
+			return err
+		}
+		if !salesOrderDelivered(so.Status, undelivered, packing) {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(&database.SalesOrder{}).Where("id = ?", so.ID).Updates(map[string]interface{}{
+			"status":       "delivered",
+			"delivered_at": pod.DeliveredAt,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		so.Status = "delivered"
+		so.DeliveredAt = &pod.DeliveredAt
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al registrar la prueba de entrega"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return &so, nil
+}
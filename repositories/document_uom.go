@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// lineUoM is a document line converted to the article's base presentation. Presentation and
+// Factor are nil for lines entered in base units.
+type lineUoM struct {
+	Qty          float64
+	Price        *float64
+	Presentation *string
+	Factor       *float64
+}
+
+// convertLineUoM converts a line entered in presentation: the quantity is multiplied by the
+// conversion factor and the unit price (or cost) divided by it.
+func convertLineUoM(conv *tools.UoMConverter, sku string, presentation *string, qty float64, price *float64) (lineUoM, *responses.InternalResponse) {
+	out := lineUoM{Qty: qty, Price: price}
+	if presentation == nil || strings.TrimSpace(*presentation) == "" {
+		return out, nil
+	}
+	factor, resp := conv.Factor(sku, presentation)
+	if resp != nil {
+		return out, resp
+	}
+	pres := strings.TrimSpace(*presentation)
+	out.Qty = qty * factor
+	if price != nil {
+		p := *price / factor
+		out.Price = &p
+	}
+	out.Presentation = &pres
+	out.Factor = &factor
+	return out, nil
+}
+
+// salesOrderLinePresentations returns, per SKU, the presentation the sales order lines were
+// entered in. SKUs ordered in base units are left out.
+func salesOrderLinePresentations(tx *gorm.DB, salesOrderID string) (map[string]lineUoM, error) {
+	var rows []struct {
+		ArticleSKU         string
+		Presentation       *string
+		PresentationFactor *float64
+	}
+	if err := tx.Table("sales_order_items").
+		Select("article_sku, presentation, presentation_factor").
+		Where("sales_order_id = ? AND presentation IS NOT NULL", salesOrderID).
+		Order("created_at ASC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load sales order presentations: %w", err)
+	}
+	out := make(map[string]lineUoM, len(rows))
+	for _, r := range rows {
+		if _, seen := out[r.ArticleSKU]; !seen {
+			out[r.ArticleSKU] = lineUoM{Presentation: r.Presentation, Factor: r.PresentationFactor}
+		}
+	}
+	return out, nil
+}
+
+// enteredPresentation resolves the presentation of a task line. It returns ok=false for lines in
+// base units (no presentation).
+func enteredPresentation(conv *tools.UoMConverter, sku string, presentation *string) (factor float64, ok bool, resp *responses.InternalResponse) {
+	if presentation == nil || strings.TrimSpace(*presentation) == "" {
+		return 0, false, nil
+	}
+	factor, resp = conv.Factor(sku, presentation)
+	if resp != nil {
+		return 0, false, resp
+	}
+	return factor, true, nil
+}
+
+// convertReceivingItemUoM converts a receiving line entered in a presentation to base units.
+// The line is kept in base units without a presentation, so a line that carries one is always
+// in that presentation.
+func convertReceivingItemUoM(conv *tools.UoMConverter, item *requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+	factor, ok, resp := enteredPresentation(conv, item.SKU, item.Presentation)
+	if resp != nil {
+		return resp
+	}
+	item.Presentation, item.PresentationFactor = nil, nil
+	if !ok {
+		return nil
+	}
+	if resp := scaleReceivingItem(item, factor); resp != nil {
+		return resp
+	}
+	item.PresentationFactor = &factor
+	return nil
+}
+
+// scaleReceivingItem multiplies every quantity of a receiving line by factor: expected and
+// received units (which must stay whole), the accepted / rejected split and the lot quantities.
+func scaleReceivingItem(item *requests.ReceivingTaskItemRequest, factor float64) *responses.InternalResponse {
+	expected, ok := tools.UoMWholeQty(float64(item.ExpectedQuantity) * factor)
+	if !ok {
+		return fractionalBaseQty(item.SKU, float64(item.ExpectedQuantity)*factor)
+	}
+	item.ExpectedQuantity = expected
+	if item.ReceivedQuantity != nil {
+		received, ok := tools.UoMWholeQty(float64(*item.ReceivedQuantity) * factor)
+		if !ok {
+			return fractionalBaseQty(item.SKU, float64(*item.ReceivedQuantity)*factor)
+		}
+		item.ReceivedQuantity = &received
+	}
+	item.AcceptedQty = scaledQty(item.AcceptedQty, factor)
+	item.RejectedQty = scaledQty(item.RejectedQty, factor)
+	for i := range item.LotNumbers {
+		item.LotNumbers[i].Quantity *= factor
+		item.LotNumbers[i].ReceivedQuantity = scaledQty(item.LotNumbers[i].ReceivedQuantity, factor)
+	}
+	return nil
+}
+
+// convertPickingItemUoM converts a picking line entered in a presentation to base units.
+// The line is kept in base units without a presentation, so a line that carries one is always
+// in that presentation.
+func convertPickingItemUoM(conv *tools.UoMConverter, item *requests.PickingTaskItemRequest) *responses.InternalResponse {
+	factor, ok, resp := enteredPresentation(conv, item.SKU, item.Presentation)
+	if resp != nil {
+		return resp
+	}
+	item.Presentation, item.PresentationFactor = nil, nil
+	if !ok {
+		return nil
+	}
+	scalePickingItem(item, factor)
+	item.PresentationFactor = &factor
+	return nil
+}
+
+// scalePickingItem multiplies every quantity of a picking line by factor: required and picked
+// quantities, each allocation and the lot quantities.
+func scalePickingItem(item *requests.PickingTaskItemRequest, factor float64) {
+	item.ExpectedQuantity *= factor
+	item.PickedQty = scaledQty(item.PickedQty, factor)
+	for i := range item.Allocations {
+		item.Allocations[i].Quantity *= factor
+		item.Allocations[i].PickedQty = scaledQty(item.Allocations[i].PickedQty, factor)
+	}
+	for i := range item.LotNumbers {
+		item.LotNumbers[i].Quantity *= factor
+	}
+}
+
+// convertPickingItemsUoM converts every line of a picking task; changed reports whether any
+// line was entered in a presentation.
+func convertPickingItemsUoM(conv *tools.UoMConverter, items []requests.PickingTaskItemRequest) (bool, *responses.InternalResponse) {
+	changed := false
+	for i := range items {
+		if items[i].Presentation != nil {
+			changed = true
+		}
+		if resp := convertPickingItemUoM(conv, &items[i]); resp != nil {
+			return false, resp
+		}
+	}
+	return changed, nil
+}
+
+// convertReceivingItemsUoM is convertPickingItemsUoM for receiving task lines.
+func convertReceivingItemsUoM(conv *tools.UoMConverter, items []requests.ReceivingTaskItemRequest) (bool, *responses.InternalResponse) {
+	changed := false
+	for i := range items {
+		if items[i].Presentation != nil {
+			changed = true
+		}
+		if resp := convertReceivingItemUoM(conv, &items[i]); resp != nil {
+			return false, resp
+		}
+	}
+	return changed, nil
+}
+
+func scaledQty(qty *float64, factor float64) *float64 {
+	if qty == nil {
+		return nil
+	}
+	v := *qty * factor
+	return &v
+}
+
+func fractionalBaseQty(sku string, qty float64) *responses.InternalResponse {
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("La cantidad de %s convertida a la presentación base (%.6g) no es entera", sku, qty),
+		Handled:    true,
+		StatusCode: responses.StatusBadRequest,
+	}
+}
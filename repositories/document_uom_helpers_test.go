@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleReceivingItem(t *testing.T) {
+	item := requests.ReceivingTaskItemRequest{
+		SKU:              "SKU-1",
+		ExpectedQuantity: 2,
+		ReceivedQuantity: tools.IntToPtr(2),
+		AcceptedQty:      tools.Float64Ptr(1.5),
+		RejectedQty:      tools.Float64Ptr(0.5),
+		LotNumbers:       []requests.CreateLotRequest{{LotNumber: "L1", Quantity: 2, ReceivedQuantity: tools.Float64Ptr(1)}},
+	}
+	require.Nil(t, scaleReceivingItem(&item, 12))
+
+	assert.Equal(t, 24, item.ExpectedQuantity)
+	assert.Equal(t, 24, *item.ReceivedQuantity)
+	assert.InDelta(t, 18, *item.AcceptedQty, 1e-9)
+	assert.InDelta(t, 6, *item.RejectedQty, 1e-9)
+	assert.InDelta(t, 24, item.LotNumbers[0].Quantity, 1e-9)
+	assert.InDelta(t, 12, *item.LotNumbers[0].ReceivedQuantity, 1e-9)
+}
+
+func TestScaleReceivingItem_FractionalBaseUnits(t *testing.T) {
+	// 1 UNIDAD of an article kept in CAJA of 12 is 1/12 of a box.
+	item := requests.ReceivingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 5}
+	resp := scaleReceivingItem(&item, 1.0/12)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, 5, item.ExpectedQuantity, "item is left untouched")
+}
+
+func TestScaleReceivingItem_WholeAfterFloatNoise(t *testing.T) {
+	item := requests.ReceivingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 24}
+	require.Nil(t, scaleReceivingItem(&item, 1.0/12))
+	assert.Equal(t, 2, item.ExpectedQuantity)
+}
+
+func TestScalePickingItem(t *testing.T) {
+	item := requests.PickingTaskItemRequest{
+		SKU:              "SKU-1",
+		ExpectedQuantity: 3,
+		PickedQty:        tools.Float64Ptr(3),
+		Allocations: []database.LocationAllocation{
+			{Location: "A-1", Quantity: 2, PickedQty: tools.Float64Ptr(2)},
+			{Location: "A-2", Quantity: 1},
+		},
+		LotNumbers: []database.LotEntry{{LotNumber: "L1", Quantity: 3}},
+	}
+	scalePickingItem(&item, 20)
+
+	assert.InDelta(t, 60, item.ExpectedQuantity, 1e-9)
+	assert.InDelta(t, 60, *item.PickedQty, 1e-9)
+	assert.InDelta(t, 40, item.Allocations[0].Quantity, 1e-9)
+	assert.InDelta(t, 40, *item.Allocations[0].PickedQty, 1e-9)
+	assert.InDelta(t, 20, item.Allocations[1].Quantity, 1e-9)
+	assert.Nil(t, item.Allocations[1].PickedQty)
+	assert.InDelta(t, 60, item.LotNumbers[0].Quantity, 1e-9)
+	assert.NoError(t, item.ValidateAllocationSum(), "allocations still add up to required_qty")
+}
+
+func TestConvertItemsUoM_BaseUnitLines(t *testing.T) {
+	// Lines without a presentation never reach the converter; a stray factor is dropped.
+	picking := []requests.PickingTaskItemRequest{{SKU: "SKU-1", ExpectedQuantity: 5, PresentationFactor: tools.Float64Ptr(12)}}
+	changed, resp := convertPickingItemsUoM(nil, picking)
+	require.Nil(t, resp)
+	assert.False(t, changed)
+	assert.Equal(t, 5.0, picking[0].ExpectedQuantity)
+	assert.Nil(t, picking[0].PresentationFactor)
+
+	blank := "  "
+	receiving := []requests.ReceivingTaskItemRequest{{SKU: "SKU-1", ExpectedQuantity: 5, Presentation: &blank}}
+	_, resp = convertReceivingItemsUoM(nil, receiving)
+	require.Nil(t, resp)
+	assert.Equal(t, 5, receiving[0].ExpectedQuantity)
+	assert.Nil(t, receiving[0].Presentation)
+}
+
+func TestTaskItemPresentationFactor_NotReadFromClient(t *testing.T) {
+	// The factor is set server-side; one sent in the body is ignored.
+	var picking requests.PickingTaskItemRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"sku":"SKU-1","required_qty":2,"presentation":"CAJA","presentation_factor":12}`), &picking))
+	assert.Nil(t, picking.PresentationFactor)
+
+	var receiving requests.ReceivingTaskItemRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"sku":"SKU-1","expected_qty":2,"presentation":"CAJA","presentation_factor":12}`), &receiving))
+	assert.Nil(t, receiving.PresentationFactor)
+}
+
+func TestConvertLineUoM_BaseUnitLine(t *testing.T) {
+	price := 10.0
+	uom, resp := convertLineUoM(nil, "SKU-1", nil, 4, &price)
+	require.Nil(t, resp)
+	assert.Equal(t, 4.0, uom.Qty)
+	assert.Equal(t, &price, uom.Price)
+	assert.Nil(t, uom.Presentation)
+	assert.Nil(t, uom.Factor)
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// ExchangeRatesRepository implements ports.ExchangeRatesRepository using GORM.
+type ExchangeRatesRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.ExchangeRatesRepository = (*ExchangeRatesRepository)(nil)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers (also used inside inventory transactions)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// missingRateError reports a currency pair without any rate effective on the lookup day.
+type missingRateError struct {
+	From, To string
+	AsOf     time.Time
+}
+
+func (e *missingRateError) Error() string {
+	return fmt.Sprintf("no exchange rate from %s to %s effective on %s", e.From, e.To, e.AsOf.Format("2006-01-02"))
+}
+
+// missingRateResponse turns a missingRateError (possibly wrapped by a transaction) into a
+// handled 400; it returns nil for any other error.
+func missingRateResponse(err error) *responses.InternalResponse {
+	var rateErr *missingRateError
+	if !errors.As(err, &rateErr) {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Error:      err,
+		Message:    fmt.Sprintf("No hay tipo de cambio de %s a %s vigente al %s", rateErr.From, rateErr.To, rateErr.AsOf.Format("2006-01-02")),
+		Handled:    true,
+		StatusCode: responses.StatusBadRequest,
+	}
+}
+
+// tenantBaseCurrency reads stock_settings.base_currency. Tenants without a stock_settings
+// row get the column default (DefaultCurrency).
+func tenantBaseCurrency(tx *gorm.DB, tenantID string) (string, error) {
+	var currency string
+	if err := tx.Raw(`SELECT base_currency FROM stock_settings WHERE tenant_id = ?`, tenantID).Scan(&currency).Error; err != nil {
+		return "", fmt.Errorf("read base_currency: %w", err)
+	}
+	if currency == "" {
+		return database.DefaultCurrency, nil
+	}
+	return currency, nil
+}
+
+// lookupExchangeRate returns how many units of to one unit of from was worth on asOf.
+// The most recent rate effective that day or earlier wins, whether it was entered for the
+// pair itself or for the reverse pair (then its inverse is used); on the same day the direct
+// rate is preferred. A pair without any rate returns *missingRateError.
+func lookupExchangeRate(tx *gorm.DB, tenantID, from, to string, asOf time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	day := asOf.Format("2006-01-02")
+
+	var rates []float64
+	if err := tx.Raw(`
+		SELECT rate FROM (
+			SELECT rate, effective_date, 0 AS inverse
+			FROM exchange_rates
+			WHERE tenant_id = ? AND from_currency = ? AND to_currency = ? AND effective_date <= ?
+			UNION ALL
+			SELECT 1 / rate, effective_date, 1 AS inverse
+			FROM exchange_rates
+			WHERE tenant_id = ? AND from_currency = ? AND to_currency = ? AND effective_date <= ?
+		) r
+		ORDER BY effective_date DESC, inverse ASC
+		LIMIT 1
+	`, tenantID, from, to, day, tenantID, to, from, day).Scan(&rates).Error; err != nil {
+		return 0, fmt.Errorf("read exchange rate %s/%s: %w", from, to, err)
+	}
+	if len(rates) == 0 || rates[0] <= 0 {
+		return 0, &missingRateError{From: from, To: to, AsOf: asOf}
+	}
+	return rates[0], nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// CRUD
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *ExchangeRatesRepository) List(tenantID string) ([]database.ExchangeRate, *responses.InternalResponse) {
+	var rates []database.ExchangeRate
+	if err := r.DB.Where("tenant_id = ?", tenantID).
+		Order("from_currency ASC, to_currency ASC, effective_date DESC").
+		Find(&rates).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los tipos de cambio"}
+	}
+	return rates, nil
+}
+
+func (r *ExchangeRatesRepository) Upsert(tenantID, createdBy string, req *requests.UpsertExchangeRateRequest) (*database.ExchangeRate, *responses.InternalResponse) {
+	effective, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Fecha de vigencia inválida", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+
+	var createdByPtr *string
+	if createdBy != "" {
+		createdByPtr = &createdBy
+	}
+
+	var result database.ExchangeRate
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("tenant_id = ? AND from_currency = ? AND to_currency = ? AND effective_date = ?",
+			tenantID, req.FromCurrency, req.ToCurrency, req.EffectiveDate).First(&result).Error
+		if err == nil {
+			result.Rate = req.Rate
+			result.CreatedBy = createdByPtr
+			return tx.Model(&result).Updates(map[string]interface{}{"rate": req.Rate, "created_by": createdByPtr}).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		id, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate exchange rate id: %w", err)
+		}
+		result = database.ExchangeRate{
+			ID:            id,
+			TenantID:      tenantID,
+			FromCurrency:  req.FromCurrency,
+			ToCurrency:    req.ToCurrency,
+			Rate:          req.Rate,
+			EffectiveDate: effective,
+			CreatedBy:     createdByPtr,
+			CreatedAt:     tools.GetCurrentTime(),
+		}
+		return tx.Create(&result).Error
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al guardar el tipo de cambio"}
+	}
+	return &result, nil
+}
+
+func (r *ExchangeRatesRepository) Delete(id, tenantID string) *responses.InternalResponse {
+	res := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&database.ExchangeRate{})
+	if res.Error != nil {
+		return &responses.InternalResponse{Error: res.Error, Message: "Error al eliminar el tipo de cambio"}
+	}
+	if res.RowsAffected == 0 {
+		return &responses.InternalResponse{Message: "Tipo de cambio no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Lookups
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *ExchangeRatesRepository) BaseCurrency(tenantID string) (string, *responses.InternalResponse) {
+	currency, err := tenantBaseCurrency(r.DB, tenantID)
+	if err != nil {
+		return "", &responses.InternalResponse{Error: err, Message: "Error al obtener la moneda base"}
+	}
+	return currency, nil
+}
+
+func (r *ExchangeRatesRepository) GetRate(tenantID, from, to string, asOf time.Time) (float64, *responses.InternalResponse) {
+	rate, err := lookupExchangeRate(r.DB, tenantID, from, to, asOf)
+	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return 0, resp
+		}
+		return 0, &responses.InternalResponse{Error: err, Message: "Error al obtener el tipo de cambio"}
+	}
+	return rate, nil
+}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
@@ -77,3 +80,73 @@ func (r *InventoryMovementsRepository) ListMovements(f ports.MovementsFilter) ([
 	}
 	return movements, nil
 }
+
+// GetMovementCOGS returns the cost of goods of a movement and the cost layers it consumed.
+// Movements that did not consume layers (inbound, transfers) come back with no consumptions.
+func (r *InventoryMovementsRepository) GetMovementCOGS(id string) (*responses.MovementCOGS, *responses.InternalResponse) {
+	var mov database.InventoryMovement
+	if err := r.DB.Table(database.InventoryMovement{}.TableName()).Where("id = ?", id).First(&mov).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{
+				Message:    "Movimiento de inventario no encontrado",
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el movimiento de inventario",
+			Handled: false,
+		}
+	}
+
+	type consumptionRow struct {
+		LayerID         *string
+		LayerReceivedAt *time.Time
+		Quantity        float64
+		UnitCost        float64
+	}
+	var rows []consumptionRow
+	if err := r.DB.Raw(`
+		SELECT c.layer_id, l.received_at AS layer_received_at, c.quantity, c.unit_cost
+		FROM cost_layer_consumptions c
+		LEFT JOIN cost_layers l ON l.id = c.layer_id
+		WHERE c.movement_id = ?
+		ORDER BY c.created_at, c.id
+	`, id).Scan(&rows).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el costo del movimiento",
+			Handled: false,
+		}
+	}
+
+	result := &responses.MovementCOGS{
+		MovementID:   mov.ID,
+		SKU:          mov.SKU,
+		MovementType: mov.MovementType,
+		Quantity:     mov.Quantity,
+		Consumptions: make([]responses.MovementCostConsumption, 0, len(rows)),
+	}
+	for _, row := range rows {
+		c := responses.MovementCostConsumption{
+			LayerID:  row.LayerID,
+			Quantity: row.Quantity,
+			UnitCost: row.UnitCost,
+			Total:    row.Quantity * row.UnitCost,
+		}
+		if row.LayerReceivedAt != nil {
+			ts := row.LayerReceivedAt.Format(time.RFC3339)
+			c.LayerReceivedAt = &ts
+		}
+		result.Consumptions = append(result.Consumptions, c)
+		result.COGS += c.Total
+	}
+	if mov.COGS != nil {
+		result.COGS = *mov.COGS
+	}
+	if mov.UnitCost != nil {
+		result.UnitCost = *mov.UnitCost
+	}
+	return result, nil
+}
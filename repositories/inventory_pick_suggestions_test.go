@@ -129,3 +129,52 @@ func TestAllocatePickRows_MultipleLotsInSameLocation(t *testing.T) {
 	assert.Equal(t, 10.0, resp.Allocations[0].Quantity) // full lot-1
 	assert.Equal(t, 10.0, resp.Allocations[1].Quantity) // remaining from lot-2
 }
+
+func TestPreferFewerStops_SingleCoveringLocation(t *testing.T) {
+	// Same (no) expiration: FIFO would take A, B and C; C alone covers the request.
+	rows := []pickRow{
+		{Location: "A-01", InvQty: 4},
+		{Location: "B-01", InvQty: 5},
+		{Location: "C-01", InvQty: 12},
+		{Location: "D-01", InvQty: 30},
+	}
+	resp := allocatePickRows(preferFewerStops(rows, 10), 10)
+	require.Len(t, resp.Allocations, 1)
+	assert.Equal(t, "C-01", resp.Allocations[0].Location, "smallest location that covers the quantity")
+	assert.True(t, resp.Sufficient)
+}
+
+func TestPreferFewerStops_LargestFirstWhenNoneCovers(t *testing.T) {
+	rows := []pickRow{
+		{Location: "A-01", InvQty: 2},
+		{Location: "B-01", InvQty: 3},
+		{Location: "C-01", InvQty: 6},
+		{Location: "D-01", InvQty: 5},
+	}
+	resp := allocatePickRows(preferFewerStops(rows, 10), 10)
+	require.Len(t, resp.Allocations, 2)
+	assert.Equal(t, "C-01", resp.Allocations[0].Location)
+	assert.Equal(t, "D-01", resp.Allocations[1].Location)
+}
+
+func TestPreferFewerStops_KeepsFEFOAcrossExpirations(t *testing.T) {
+	exp1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	exp2 := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	rows := []pickRow{
+		{Location: "A-01", InvQty: 23, LotNumber: strPtr("L1"), ExpirationDate: timePtr(exp1), LotQtyInLoc: 3},
+		{Location: "B-01", InvQty: 50, LotNumber: strPtr("L2"), ExpirationDate: timePtr(exp2), LotQtyInLoc: 50},
+		{Location: "A-01", InvQty: 23, LotNumber: strPtr("L3"), ExpirationDate: timePtr(exp2), LotQtyInLoc: 20},
+	}
+	resp := allocatePickRows(preferFewerStops(rows, 10), 10)
+	require.Len(t, resp.Allocations, 2)
+	// The earliest expiration is still picked first; for the later one, A-01 (already a stop)
+	// beats the larger B-01.
+	assert.Equal(t, "L1", *resp.Allocations[0].LotNumber)
+	assert.Equal(t, "A-01", resp.Allocations[1].Location)
+	assert.Equal(t, "L3", *resp.Allocations[1].LotNumber)
+}
+
+func TestPreferFewerStops_ZeroQtyUnchanged(t *testing.T) {
+	rows := []pickRow{{Location: "B-01", InvQty: 1}, {Location: "A-01", InvQty: 9}}
+	assert.Equal(t, rows, preferFewerStops(rows, 0))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,18 +21,16 @@ import (
 
 // InventoryRepository owns inventory + inventory_lots persistence.
 //
-// S3.5 W2-A: TenantID is set at construction via wire and is used to populate
-// the inventory_lots.tenant_id column on every junction insert (the column is
-// NOT NULL after migration 000034). The parent inventory table itself is not
-// yet tenant-scoped — that is a future S3.5 wave (operational tables).
+// Every method takes the caller's tenant ID: inventory rows are filtered by
+// inventory.tenant_id (migration 000040) and new inventory, lot, serial and
+// inventory_lots rows are stamped with it.
 type InventoryRepository struct {
-	DB       *gorm.DB
-	TenantID string
+	DB *gorm.DB
 }
 
-func (r *InventoryRepository) GetAllInventory() ([]*dto.EnhancedInventory, *responses.InternalResponse) {
+func (r *InventoryRepository) GetAllInventory(tenantID string) ([]*dto.EnhancedInventory, *responses.InternalResponse) {
 	var items []database.Inventory
-	err := r.DB.Where("quantity > 0").
+	err := r.DB.Where("tenant_id = ? AND quantity > 0", tenantID).
 		Order("sku ASC").
 		Find(&items).Error
 
@@ -53,7 +52,7 @@ func (r *InventoryRepository) GetAllInventory() ([]*dto.EnhancedInventory, *resp
 	}
 
 	var articleRows []database.Article
-	if err = r.DB.Where("sku IN ?", skus).Find(&articleRows).Error; err != nil {
+	if err = r.DB.Where("sku IN ? AND tenant_id = ?", skus, tenantID).Find(&articleRows).Error; err != nil {
 		return nil, &responses.InternalResponse{
 			Error:   err,
 			Message: "Error al obtener artículos del inventario",
@@ -97,7 +96,21 @@ func (r *InventoryRepository) GetAllInventory() ([]*dto.EnhancedInventory, *resp
 		}
 	}
 
-	// --- Batch query 3: serials for inventory IDs that track by serial ---
+	// --- Batch query 3: quantities on QC hold ---
+	invIDs := make([]string, 0, len(items))
+	for _, it := range items {
+		invIDs = append(invIDs, it.ID)
+	}
+	heldMap, err := heldQtyByInventory(r.DB, invIDs)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el stock retenido del inventario",
+			Handled: false,
+		}
+	}
+
+	// --- Batch query 4: serials for inventory IDs that track by serial ---
 	var serialInvIDs []string
 	for _, it := range items {
 		if a, ok := articleMap[it.SKU]; ok && a.TrackBySerial {
@@ -156,7 +169,8 @@ func (r *InventoryRepository) GetAllInventory() ([]*dto.EnhancedInventory, *resp
 			Location:        item.Location,
 			Quantity:        item.Quantity,
 			ReservedQty:     item.ReservedQty,
-			AvailableQty:    item.Quantity - item.ReservedQty,
+			HeldQty:         heldMap[item.ID],
+			AvailableQty:    item.Quantity - item.ReservedQty - heldMap[item.ID],
 			Status:          item.Status,
 			UnitPrice:       item.UnitPrice,
 			CreatedAt:       item.CreatedAt,
@@ -179,9 +193,9 @@ func (r *InventoryRepository) GetAllInventory() ([]*dto.EnhancedInventory, *resp
 }
 
 // GetInventoryBySkuAndLocation returns a single inventory record by SKU and location, or nil if not found.
-func (r *InventoryRepository) GetInventoryBySkuAndLocation(sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
+func (r *InventoryRepository) GetInventoryBySkuAndLocation(tenantID, sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
 	var item database.Inventory
-	err := r.DB.Where("sku = ? AND location = ?", sku, location).First(&item).Error
+	err := r.DB.Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, sku, location).First(&item).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -194,7 +208,7 @@ func (r *InventoryRepository) GetInventoryBySkuAndLocation(sku, location string)
 	}
 
 	var article database.Article
-	err = r.DB.Where("sku = ?", item.SKU).First(&article).Error
+	err = r.DB.Where("sku = ? AND tenant_id = ?", item.SKU, tenantID).First(&article).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, &responses.InternalResponse{
 			Error:   err,
@@ -221,6 +235,15 @@ func (r *InventoryRepository) GetInventoryBySkuAndLocation(sku, location string)
 			Find(&serials).Error
 	}
 
+	heldMap, err := heldQtyByInventory(r.DB, []string{item.ID})
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el stock retenido del inventario",
+			Handled: false,
+		}
+	}
+
 	imageURL := ""
 	if article.ImageURL != nil {
 		imageURL = *article.ImageURL
@@ -245,7 +268,8 @@ func (r *InventoryRepository) GetInventoryBySkuAndLocation(sku, location string)
 		Location:        item.Location,
 		Quantity:        item.Quantity,
 		ReservedQty:     item.ReservedQty,
-		AvailableQty:    item.Quantity - item.ReservedQty,
+		HeldQty:         heldMap[item.ID],
+		AvailableQty:    item.Quantity - item.ReservedQty - heldMap[item.ID],
 		Status:          item.Status,
 		UnitPrice:       item.UnitPrice,
 		CreatedAt:       item.CreatedAt,
@@ -264,12 +288,13 @@ func (r *InventoryRepository) GetInventoryBySkuAndLocation(sku, location string)
 	}, nil
 }
 
-func (r *InventoryRepository) CreateInventory(userId string, item *requests.CreateInventory) *responses.InternalResponse {
+func (r *InventoryRepository) CreateInventory(tenantID, userId string, item *requests.CreateInventory) *responses.InternalResponse {
+	handledResp := &responses.InternalResponse{}
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		// 1 - Check if sku exists in the location
 		var inventoryCount int64
 		err := tx.Model(&database.Inventory{}).
-			Where("sku = ? AND location = ?", item.SKU, item.Location).
+			Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, item.SKU, item.Location).
 			Count(&inventoryCount).Error
 
 		if err != nil {
@@ -282,7 +307,10 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 
 		// 2 - Get article information
 		var article database.Article
-		err = tx.Where("sku = ?", item.SKU).First(&article).Error
+		err = tx.Where("sku = ? AND tenant_id = ?", item.SKU, tenantID).First(&article).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("artículo no encontrado para el SKU proporcionado")
+		}
 		if err != nil {
 			return errors.New("error al obtener artículo para la creación de inventario")
 		}
@@ -291,6 +319,15 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 			return errors.New("artículo no encontrado para el SKU proporcionado")
 		}
 
+		exceeded, err := tools.CheckLocationCapacity(tx, tenantID, item.Location, []tools.CapacityAddition{{SKU: item.SKU, Quantity: item.Quantity}})
+		if err != nil {
+			return err
+		}
+		if exceeded != nil {
+			*handledResp = *tools.CapacityExceededResponse(exceeded)
+			return errors.New(handledResp.Message)
+		}
+
 		inventoryID, err := tools.GenerateNanoid(tx)
 		if err != nil {
 			return fmt.Errorf("generar id inventario: %w", err)
@@ -298,6 +335,7 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 
 		var inventory database.Inventory
 		inventory.ID = inventoryID
+		inventory.TenantID = tenantID
 		inventory.SKU = item.SKU
 		inventory.Name = item.Name
 		inventory.Description = item.Description
@@ -331,7 +369,7 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 				var lotCount int64
 
 				err := tx.Model(&database.Lot{}).
-					Where("lot_number = ? AND sku = ?", item.Lots[i].LotNumber, item.SKU).
+					Where("tenant_id = ? AND lot_number = ? AND sku = ?", tenantID, item.Lots[i].LotNumber, item.SKU).
 					Count(&lotCount).Error
 
 				if err != nil {
@@ -353,6 +391,7 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 					}
 					lot := &database.Lot{
 						ID:             lotID,
+						TenantID:       tenantID,
 						LotNumber:      item.Lots[i].LotNumber,
 						SKU:            item.SKU,
 						Quantity:       item.Lots[i].Quantity,
@@ -372,7 +411,7 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 					}
 					inventoryLot := &database.InventoryLot{
 						ID:          invLotID,
-						TenantID:    r.TenantID, // S3.5 W2-A: required by NOT NULL after 000034
+						TenantID:    tenantID,
 						InventoryID: inventory.ID,
 						LotID:       lot.ID,
 						Quantity:    item.Lots[i].Quantity,
@@ -392,7 +431,7 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 				// Check if serial already exists
 				var serialCount int64
 				err := tx.Model(&database.Serial{}).
-					Where("serial_number = ? AND sku = ?", item.Serials[i].SerialNumber, item.SKU).
+					Where("tenant_id = ? AND serial_number = ? AND sku = ?", tenantID, item.Serials[i].SerialNumber, item.SKU).
 					Count(&serialCount).Error
 
 				if err != nil {
@@ -400,21 +439,17 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 				}
 
 				if serialCount == 0 {
-					// Create new serial
-					serialID, err := tools.GenerateNanoid(tx)
-					if err != nil {
-						return fmt.Errorf("generar id serial: %w", err)
-					}
+					// Create new serial, in stock at the inventory location
 					newSerial := &database.Serial{
-						ID:           serialID,
+						TenantID:     tenantID,
 						SerialNumber: item.Serials[i].SerialNumber,
 						SKU:          item.SKU,
-						CreatedAt:    tools.GetCurrentTime(),
-						UpdatedAt:    tools.GetCurrentTime(),
-						Status:       "available",
+						Status:       database.SerialInStock,
 					}
 
-					if err := tx.Create(newSerial).Error; err != nil {
+					if err := createSerial(tx, newSerial, serialEvent{
+						Location: item.Location, DocumentType: "inventory", DocumentID: inventory.ID, UserID: userId,
+					}); err != nil {
 						return errors.New("error al crear serial")
 					}
 
@@ -439,6 +474,10 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 
 		// Reason
 		reason := "in"
+		unitCost, err := basePriceCost(tx, item.SKU, item.UnitPrice)
+		if err != nil {
+			return err
+		}
 
 		// 5 - Create inventory movement
 		movementID, err := tools.GenerateNanoid(tx)
@@ -455,16 +494,27 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 			Reason:         &reason,
 			CreatedBy:      userId,
 			CreatedAt:      tools.GetCurrentTime(),
+			UnitCost:       &unitCost,
 		}
 
 		if err := tx.Create(inventoryMovement).Error; err != nil {
 			return errors.New("error al crear movimiento de inventario")
 		}
 
+		if err := recordCostLayer(tx, inventoryMovement); err != nil {
+			return err
+		}
+
 		return nil
 	})
 
+	if handledResp.Handled {
+		return handledResp
+	}
 	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return resp
+		}
 		handledErrors := map[string]bool{
 			"el inventario con este SKU ya existe en la ubicación especificada": true,
 			"artículo no encontrado para el SKU proporcionado":                  true,
@@ -497,10 +547,10 @@ func (r *InventoryRepository) CreateInventory(userId string, item *requests.Crea
 	return nil
 }
 
-func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *responses.InternalResponse {
+func (r *InventoryRepository) UpdateInventory(tenantID string, item *requests.UpdateInventory) *responses.InternalResponse {
 	// 1 - Get the current inventory item
 	var inventory database.Inventory
-	err := r.DB.Where("sku = ? AND location = ?", item.SKU, item.Location).First(&inventory).Error
+	err := r.DB.Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, item.SKU, item.Location).First(&inventory).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &responses.InternalResponse{
@@ -518,7 +568,7 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 
 	var count int64
 	if err := r.DB.Model(&database.Inventory{}).
-		Where("sku = ? AND location = ? AND id <> ?", item.SKU, item.Location, inventory.ID).
+		Where("tenant_id = ? AND sku = ? AND location = ? AND id <> ?", tenantID, item.SKU, item.Location, inventory.ID).
 		Count(&count).Error; err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
@@ -554,7 +604,7 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 
 	// Handle lots and serials updates if necessary (similar logic to creation)
 	var article database.Article
-	err = r.DB.Where("sku = ?", item.SKU).First(&article).Error
+	err = r.DB.Where("sku = ? AND tenant_id = ?", item.SKU, tenantID).First(&article).Error
 	if err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
@@ -576,7 +626,7 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 		// Check if the lot already exists
 		var lotCount int64
 		err := r.DB.Model(&database.Lot{}).
-			Where("lot_number = ? AND sku = ?", *item.DefaultLotNumber, item.SKU).
+			Where("tenant_id = ? AND lot_number = ? AND sku = ?", tenantID, *item.DefaultLotNumber, item.SKU).
 			Count(&lotCount).Error
 
 		if err != nil {
@@ -599,6 +649,7 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 			}
 			lot := &database.Lot{
 				ID:        invLotID,
+				TenantID:  tenantID,
 				LotNumber: *item.DefaultLotNumber,
 				SKU:       item.SKU,
 				Quantity:  item.Quantity,
@@ -621,7 +672,7 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 		// Check if the serial already exists
 		var serialCount int64
 		err := r.DB.Model(&database.Serial{}).
-			Where("serial_number LIKE ? AND sku = ?", fmt.Sprintf("%s%%", *item.SerialNumberPrefix), item.SKU).
+			Where("tenant_id = ? AND serial_number LIKE ? AND sku = ?", tenantID, fmt.Sprintf("%s%%", *item.SerialNumberPrefix), item.SKU).
 			Count(&serialCount).Error
 
 		if err != nil {
@@ -635,14 +686,15 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 		if serialCount == 0 {
 			// Create new serial
 			newSerial := &database.Serial{
+				TenantID:     tenantID,
 				SerialNumber: *item.SerialNumberPrefix, // Assuming prefix is the full serial number for simplicity
 				SKU:          item.SKU,
-				CreatedAt:    tools.GetCurrentTime(),
-				UpdatedAt:    tools.GetCurrentTime(),
-				Status:       "available",
+				Status:       database.SerialInStock,
 			}
 
-			if err := r.DB.Create(newSerial).Error; err != nil {
+			if err := r.DB.Transaction(func(tx *gorm.DB) error {
+				return createSerial(tx, newSerial, serialEvent{Location: item.Location, DocumentType: "inventory"})
+			}); err != nil {
 				return &responses.InternalResponse{
 					Error:   err,
 					Message: "Error al crear número de serie",
@@ -655,10 +707,10 @@ func (r *InventoryRepository) UpdateInventory(item *requests.UpdateInventory) *r
 	return nil
 }
 
-func (s *InventoryRepository) DeleteInventory(sku, location string) *responses.InternalResponse {
+func (s *InventoryRepository) DeleteInventory(tenantID, sku, location string) *responses.InternalResponse {
 	// Get the inventory item
 	var inventory database.Inventory
-	err := s.DB.Where("sku = ? AND location = ?", sku, location).First(&inventory).Error
+	err := s.DB.Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, sku, location).First(&inventory).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &responses.InternalResponse{
@@ -761,7 +813,7 @@ func (s *InventoryRepository) DeleteInventory(sku, location string) *responses.I
 	}
 
 	// Finally, delete the inventory item itself (explicit WHERE to avoid GORM batch-delete safety when primary key is empty)
-	if err := s.DB.Where("sku = ? AND location = ?", sku, location).Delete(&database.Inventory{}).Error; err != nil {
+	if err := s.DB.Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, sku, location).Delete(&database.Inventory{}).Error; err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
 			Message: "Error al eliminar artículo de inventario",
@@ -772,7 +824,7 @@ func (s *InventoryRepository) DeleteInventory(sku, location string) *responses.I
 	return nil
 }
 
-func (r *InventoryRepository) Trend(sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
+func (r *InventoryRepository) Trend(tenantID, sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
 	days := 30
 
 	if days <= 0 {
@@ -785,6 +837,7 @@ func (r *InventoryRepository) Trend(sku string) (*dto.ConsumptionTrend, *respons
 	var movements []database.InventoryMovement
 	if err := r.DB.
 		Where("sku = ? AND movement_type = ? AND created_at >= ?", sku, "outbound", cutoffDate).
+		Where("EXISTS (SELECT 1 FROM articles a WHERE a.sku = inventory_movements.sku AND a.tenant_id = ?)", tenantID).
 		Order("created_at ASC").
 		Find(&movements).Error; err != nil {
 		return nil, &responses.InternalResponse{
@@ -840,7 +893,7 @@ func (r *InventoryRepository) Trend(sku string) (*dto.ConsumptionTrend, *respons
 
 	currentStock := 0.0
 	var inv database.Inventory
-	if err := r.DB.Where("sku = ?", sku).First(&inv).Error; err == nil {
+	if err := r.DB.Where("tenant_id = ? AND sku = ?", tenantID, sku).First(&inv).Error; err == nil {
 		currentStock = inv.Quantity
 	}
 
@@ -856,7 +909,7 @@ func (r *InventoryRepository) Trend(sku string) (*dto.ConsumptionTrend, *respons
 	}, nil
 }
 
-func (r *InventoryRepository) ImportInventoryFromExcel(userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
+func (r *InventoryRepository) ImportInventoryFromExcel(tenantID, userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
 	imported := []string{}
 	skipped := []string{}
 
@@ -976,14 +1029,14 @@ func (r *InventoryRepository) ImportInventoryFromExcel(userId string, fileBytes
 				serials = append(serials, database.Serial{
 					SerialNumber: strings.TrimSpace(rows[j][14]),
 					SKU:          sku,
-					Status:       "available",
+					Status:       database.SerialInStock,
 				})
 			}
 			item.Serials = serials
 		}
 
 		// Crear el inventario
-		resp := r.CreateInventory(userId, item)
+		resp := r.CreateInventory(tenantID, userId, item)
 		if resp != nil {
 			return imported, skipped, &responses.InternalResponse{
 				Error:   resp.Error,
@@ -998,7 +1051,7 @@ func (r *InventoryRepository) ImportInventoryFromExcel(userId string, fileBytes
 	return imported, skipped, nil
 }
 
-func (r *InventoryRepository) ImportInventoryFromJSON(userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
+func (r *InventoryRepository) ImportInventoryFromJSON(tenantID, userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
 	imported := []string{}
 	skipped := []string{}
 
@@ -1044,7 +1097,7 @@ func (r *InventoryRepository) ImportInventoryFromJSON(userId string, rows []requ
 			UnitPrice:   unitPrice,
 		}
 
-		resp := r.CreateInventory(userId, item)
+		resp := r.CreateInventory(tenantID, userId, item)
 		if resp != nil {
 			return imported, skipped, &responses.InternalResponse{
 				Error: resp.Error, Message: fmt.Sprintf("Fila %d: %s", i+1, resp.Message), Handled: resp.Handled,
@@ -1055,7 +1108,7 @@ func (r *InventoryRepository) ImportInventoryFromJSON(userId string, rows []requ
 	return imported, skipped, nil
 }
 
-func (r *InventoryRepository) ValidateImportRows(rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
+func (r *InventoryRepository) ValidateImportRows(tenantID string, rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
 	results := make([]responses.InventoryValidationResult, 0, len(rows))
 	seenKeys := make(map[string]bool)
 
@@ -1098,7 +1151,7 @@ func (r *InventoryRepository) ValidateImportRows(rows []requests.InventoryImport
 		seenKeys[key] = true
 
 		// Exact SKU+location match in DB
-		existing, _ := r.GetInventoryBySkuAndLocation(sku, location)
+		existing, _ := r.GetInventoryBySkuAndLocation(tenantID, sku, location)
 		if existing != nil {
 			result.Status = responses.InventoryStatusExists
 			result.ExistingInventory = &responses.InventoryValidationMatch{
@@ -1113,7 +1166,7 @@ func (r *InventoryRepository) ValidateImportRows(rows []requests.InventoryImport
 		}
 
 		// Same SKU at different location (similar)
-		all, _ := r.GetAllInventory()
+		all, _ := r.GetAllInventory(tenantID)
 		var similar []responses.InventoryValidationMatch
 		for _, inv := range all {
 			if strings.EqualFold(inv.SKU, sku) && !strings.EqualFold(inv.Location, location) {
@@ -1138,8 +1191,8 @@ func (r *InventoryRepository) ValidateImportRows(rows []requests.InventoryImport
 	return results, nil
 }
 
-func (r *InventoryRepository) ExportInventoryToExcel() ([]byte, *responses.InternalResponse) {
-	inventory, errResp := r.GetAllInventory()
+func (r *InventoryRepository) ExportInventoryToExcel(tenantID string) ([]byte, *responses.InternalResponse) {
+	inventory, errResp := r.GetAllInventory(tenantID)
 	if errResp != nil {
 		return nil, errResp
 	}
@@ -1216,6 +1269,29 @@ func (r *InventoryRepository) ExportInventoryToExcel() ([]byte, *responses.Inter
 	return buf.Bytes(), nil
 }
 
+// heldQtyByInventory returns the quantity on QC hold of each inventory row: its held_qty plus
+// what it holds of held lots. Rows with nothing held are omitted.
+func heldQtyByInventory(db *gorm.DB, inventoryIDs []string) (map[string]float64, error) {
+	var rows []struct {
+		ID   string  `gorm:"column:id"`
+		Held float64 `gorm:"column:held"`
+	}
+	if err := db.Raw(`
+		SELECT i.id, i.held_qty + `+tools.HeldLotQtySQL("i")+` AS held
+		  FROM inventory i
+		 WHERE i.id IN ?
+	`, inventoryIDs).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load held quantities: %w", err)
+	}
+	out := make(map[string]float64, len(rows))
+	for _, r := range rows {
+		if r.Held > 0 {
+			out[r.ID] = r.Held
+		}
+	}
+	return out, nil
+}
+
 // pickRow is a raw result row from the pick-suggestions SQL query.
 // Unexported so unit tests in this package can access it for allocatePickRows tests.
 type pickRow struct {
@@ -1295,48 +1371,198 @@ func allocatePickRows(rows []pickRow, qty float64) *dto.PickSuggestionResponse {
 // GetPickSuggestionsBySKU returns FEFO-ordered pick allocations for a SKU.
 // Uses inventory_lots to resolve which lots are present in each location.
 // If qty is 0, all available allocations are returned (Sufficient = true).
-func (r *InventoryRepository) GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+func (r *InventoryRepository) GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	rows, resp := r.loadPickRows(tenantID, sku)
+	if resp != nil {
+		return nil, resp
+	}
+	return allocatePickRows(rows, qty), nil
+}
+
+// GetPickSuggestionsFewestStops is GetPickSuggestionsBySKU tuned to visit as few locations as
+// possible: FEFO still decides which expiration is picked first, but locations sharing an
+// expiration are reordered by preferFewerStops.
+func (r *InventoryRepository) GetPickSuggestionsFewestStops(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	rows, resp := r.loadPickRows(tenantID, sku)
+	if resp != nil {
+		return nil, resp
+	}
+	return allocatePickRows(preferFewerStops(rows, qty), qty), nil
+}
+
+// loadPickRows returns the FEFO-ordered pick candidates of a SKU.
+func (r *InventoryRepository) loadPickRows(tenantID, sku string) ([]pickRow, *responses.InternalResponse) {
+	rows, err := queryPickRows(r.DB, tenantID, sku, "")
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener sugerencias de picking",
+			Handled: false,
+		}
+	}
+	return rows, nil
+}
+
+// queryPickRows runs the FEFO pick candidate query of a SKU, restricted to one location when
+// location is not empty.
+func queryPickRows(db *gorm.DB, tenantID, sku, location string) ([]pickRow, error) {
+	// Stock on QC hold counts as reserved: inventory holds (held_qty) and held lots. Rows of
+	// held lots are dropped, and lot rows lose what inventory holds block of that lot.
 	var rows []pickRow
-	err := r.DB.Raw(`
+	err := db.Raw(`
 		SELECT
 		    i.location                     AS location,
 		    i.quantity                     AS inv_qty,
-		    i.reserved_qty                 AS inv_reserved,
+		    i.quantity - `+tools.AvailableQtySQL("i")+` AS inv_reserved,
 		    l.lot_number                   AS lot_number,
 		    l.expiration_date              AS expiration_date,
-		    COALESCE(il.quantity, 0)       AS lot_qty_in_loc,
+		    COALESCE(il.quantity, 0) - CASE WHEN l.id IS NULL THEN 0 ELSE `+tools.HeldInLotQtySQL("i", "l")+` END AS lot_qty_in_loc,
 		    i.created_at                   AS inv_created_at
 		FROM inventory i
 		LEFT JOIN inventory_lots il ON il.inventory_id = i.id
 		LEFT JOIN lots l
 		       ON l.id = il.lot_id
 		      AND (l.status IS NULL OR l.status != 'archived')
-		WHERE i.sku = ?
-		  AND (i.quantity - i.reserved_qty) > 0
+		WHERE i.tenant_id = ?
+		  AND i.sku = ?
+		  AND (? = '' OR i.location = ?)
+		  AND `+tools.AvailableQtySQL("i")+` > 0
+		  AND (l.id IS NULL OR NOT `+tools.LotHeldSQL("l")+`)
 		ORDER BY
 		    COALESCE(l.expiration_date, '9999-12-31'::date) ASC,
 		    i.created_at ASC
-	`, sku).Scan(&rows).Error
+	`, tenantID, sku, location, location).Scan(&rows).Error
+	return rows, err
+}
 
-	if err != nil {
-		return nil, &responses.InternalResponse{
+// preferFewerStops reorders FEFO-sorted rows so allocatePickRows needs fewer locations.
+// Rows are only moved within a run of equal expiration dates, so FEFO is preserved. Inside a
+// run, locations already visited for an earlier expiration come first (no extra stop), then
+// the smallest location that covers what is still needed on its own, then the rest by
+// available quantity, largest first. Ties keep the FIFO order. With qty 0 (all stock) the
+// rows are returned unchanged.
+func preferFewerStops(rows []pickRow, qty float64) []pickRow {
+	if qty <= 0 || len(rows) < 2 {
+		return rows
+	}
+	out := make([]pickRow, 0, len(rows))
+	visited := make(map[string]bool)
+	remaining := qty
+
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && sameExpiration(rows[start].ExpirationDate, rows[end].ExpirationDate) {
+			end++
+		}
+
+		// Group the run by location, in first-seen order.
+		type locGroup struct {
+			location string
+			rows     []pickRow
+			capacity float64
+		}
+		var groups []*locGroup
+		byLoc := make(map[string]*locGroup)
+		for _, row := range rows[start:end] {
+			g, ok := byLoc[row.Location]
+			if !ok {
+				g = &locGroup{location: row.Location}
+				byLoc[row.Location] = g
+				groups = append(groups, g)
+			}
+			g.rows = append(g.rows, row)
+		}
+		for _, g := range groups {
+			available := g.rows[0].InvQty - g.rows[0].InvReserved
+			lotQty, lotted := 0.0, false
+			for _, row := range g.rows {
+				if row.LotNumber != nil {
+					lotted = true
+					lotQty += row.LotQtyInLoc
+				}
+			}
+			if lotted && lotQty < available {
+				available = lotQty
+			}
+			if available < 0 {
+				available = 0
+			}
+			g.capacity = available
+		}
+
+		var covering *locGroup
+		for _, g := range groups {
+			if !visited[g.location] && g.capacity >= remaining && (covering == nil || g.capacity < covering.capacity) {
+				covering = g
+			}
+		}
+		rank := func(g *locGroup) int {
+			switch {
+			case visited[g.location]:
+				return 0
+			case g == covering:
+				return 1
+			default:
+				return 2
+			}
+		}
+		sort.SliceStable(groups, func(i, j int) bool {
+			ri, rj := rank(groups[i]), rank(groups[j])
+			if ri != rj {
+				return ri < rj
+			}
+			return ri == 2 && groups[i].capacity > groups[j].capacity
+		})
+
+		for _, g := range groups {
+			out = append(out, g.rows...)
+			if remaining > 0 && g.capacity > 0 {
+				visited[g.location] = true
+				remaining -= g.capacity
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+func sameExpiration(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// requireTenantInventory returns a handled 404 unless inventoryID is an inventory row of tenantID.
+func (r *InventoryRepository) requireTenantInventory(tenantID, inventoryID string) *responses.InternalResponse {
+	var count int64
+	if err := r.DB.Model(&database.Inventory{}).
+		Where("tenant_id = ? AND id = ?", tenantID, inventoryID).
+		Count(&count).Error; err != nil {
+		return &responses.InternalResponse{
 			Error:   err,
-			Message: "Error al obtener sugerencias de picking",
+			Message: "Error al obtener artículo de inventario",
 			Handled: false,
 		}
 	}
-
-	return allocatePickRows(rows, qty), nil
+	if count == 0 {
+		return &responses.InternalResponse{
+			Message:    "Artículo de inventario no encontrado",
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+	return nil
 }
 
-func (r *InventoryRepository) GetInventoryLots(inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
+func (r *InventoryRepository) GetInventoryLots(tenantID, inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
 	var result []responses.InventoryLot
 
 	err := r.DB.
 		Table("inventory_lots").
 		Select("inventory_lots.*, lots.id as lot_id, lots.lot_number, lots.sku, lots.quantity as lot_quantity, lots.expiration_date, lots.created_at as lot_created_at, lots.updated_at as lot_updated_at").
 		Joins("INNER JOIN lots ON inventory_lots.lot_id = lots.id").
-		Where("inventory_lots.inventory_id = ?", inventoryID).
+		Where("inventory_lots.tenant_id = ? AND inventory_lots.inventory_id = ?", tenantID, inventoryID).
 		Scan(&result).Error
 
 	if err != nil {
@@ -1350,7 +1576,7 @@ func (r *InventoryRepository) GetInventoryLots(inventoryID string) ([]responses.
 	return result, nil
 }
 
-func (r *InventoryRepository) GetInventorySerials(inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
+func (r *InventoryRepository) GetInventorySerials(tenantID, inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
 	var result []responses.InventorySerialWithSerial
 
 	err := r.DB.
@@ -1362,7 +1588,8 @@ func (r *InventoryRepository) GetInventorySerials(inventoryID string) ([]respons
 			serials.created_at as serial_created_at, serials.updated_at as serial_updated_at
 		`).
 		Joins("INNER JOIN serials ON inventory_serials.serial_id = serials.id").
-		Where("inventory_serials.inventory_id = ?", inventoryID).
+		Joins("INNER JOIN inventory ON inventory.id = inventory_serials.inventory_id").
+		Where("inventory.tenant_id = ? AND inventory_serials.inventory_id = ?", tenantID, inventoryID).
 		Scan(&result).Error
 
 	if err != nil {
@@ -1376,7 +1603,10 @@ func (r *InventoryRepository) GetInventorySerials(inventoryID string) ([]respons
 	return result, nil
 }
 
-func (r *InventoryRepository) CreateInventoryLot(id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
+func (r *InventoryRepository) CreateInventoryLot(tenantID, id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
+	if resp := r.requireTenantInventory(tenantID, id); resp != nil {
+		return resp
+	}
 	invLotID, idErr := tools.GenerateNanoid(r.DB)
 	if idErr != nil {
 		return &responses.InternalResponse{
@@ -1387,7 +1617,7 @@ func (r *InventoryRepository) CreateInventoryLot(id string, input *requests.Crea
 	}
 	inventoryLot := &database.InventoryLot{
 		ID:          invLotID,
-		TenantID:    r.TenantID, // S3.5 W2-A: required by NOT NULL after 000034
+		TenantID:    tenantID,
 		InventoryID: id,
 		LotID:       input.LotID,
 		Quantity:    input.Quantity,
@@ -1406,8 +1636,8 @@ func (r *InventoryRepository) CreateInventoryLot(id string, input *requests.Crea
 	return nil
 }
 
-func (r *InventoryRepository) DeleteInventoryLot(id string) *responses.InternalResponse {
-	if err := r.DB.Where("id = ?", id).Delete(&database.InventoryLot{}).Error; err != nil {
+func (r *InventoryRepository) DeleteInventoryLot(tenantID, id string) *responses.InternalResponse {
+	if err := r.DB.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&database.InventoryLot{}).Error; err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
 			Message: "Error al eliminar lote de inventario",
@@ -1418,7 +1648,10 @@ func (r *InventoryRepository) DeleteInventoryLot(id string) *responses.InternalR
 	return nil
 }
 
-func (r *InventoryRepository) CreateInventorySerial(id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
+func (r *InventoryRepository) CreateInventorySerial(tenantID, id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
+	if resp := r.requireTenantInventory(tenantID, id); resp != nil {
+		return resp
+	}
 	inventorySerial := &database.InventorySerial{
 		InventoryID: id,
 		SerialID:    input.SerialID,
@@ -1437,8 +1670,10 @@ func (r *InventoryRepository) CreateInventorySerial(id string, input *requests.C
 	return nil
 }
 
-func (r *InventoryRepository) DeleteInventorySerial(id string) *responses.InternalResponse {
-	if err := r.DB.Where("id = ?", id).Delete(&database.InventorySerial{}).Error; err != nil {
+func (r *InventoryRepository) DeleteInventorySerial(tenantID, id string) *responses.InternalResponse {
+	if err := r.DB.
+		Where("id = ? AND inventory_id IN (SELECT id FROM inventory WHERE tenant_id = ?)", id, tenantID).
+		Delete(&database.InventorySerial{}).Error; err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
 			Message: "Error al eliminar número de serie de inventario",
@@ -1531,9 +1766,13 @@ func (r *InventoryRepository) GenerateImportTemplate(language string) ([]byte, e
 	return BuildModuleImportTemplate(cfg)
 }
 
-// GetValuation returns AVCO-based inventory valuation grouped by article, location, or category.
-// AVCO unit_cost is computed from inventory_movements (weighted average of inbound/adjustment qty*cost).
-func (r *InventoryRepository) GetValuation(groupBy string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+// GetValuation returns inventory valuation grouped by article, location, or category, read
+// from the open cost layers. Each SKU is valued at the average unit cost of its remaining
+// layers; because layers are drawn down with the tenant valuation method (FIFO, LIFO or
+// AVCO), that average is exactly what the method leaves on hand. Inventory quantities are
+// multiplied by it so per-location breakdowns work even though layers are per SKU.
+// Only the tenant's articles are valued; amounts are in the tenant base currency.
+func (r *InventoryRepository) GetValuation(tenantID, groupBy string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
 	type breakdownRow struct {
 		Key   string
 		Label string
@@ -1541,14 +1780,11 @@ func (r *InventoryRepository) GetValuation(groupBy string) (*responses.Inventory
 		Qty   float64
 	}
 
-	avcoSubquery := `
+	layerCostSubquery := `
 		SELECT sku,
-		       CASE WHEN SUM(CASE WHEN movement_type IN ('inbound','INBOUND','ADJUSTMENT','TRANSFER_IN') AND quantity > 0 THEN quantity ELSE 0 END) = 0
-		            THEN 0
-		            ELSE SUM(CASE WHEN movement_type IN ('inbound','INBOUND','ADJUSTMENT','TRANSFER_IN') AND quantity > 0 THEN quantity * COALESCE(unit_cost, 0) ELSE 0 END) /
-		                 SUM(CASE WHEN movement_type IN ('inbound','INBOUND','ADJUSTMENT','TRANSFER_IN') AND quantity > 0 THEN quantity ELSE 0 END)
-		       END AS avco
-		FROM inventory_movements
+		       SUM(remaining_qty * unit_cost) / NULLIF(SUM(remaining_qty), 0) AS unit_cost
+		FROM cost_layers
+		WHERE tenant_id = ? AND remaining_qty > 0
 		GROUP BY sku`
 
 	var rows []breakdownRow
@@ -1560,47 +1796,53 @@ func (r *InventoryRepository) GetValuation(groupBy string) (*responses.Inventory
 			SELECT inv.location AS key,
 			       inv.location AS label,
 			       COALESCE(SUM(inv.quantity), 0) AS qty,
-			       COALESCE(SUM(inv.quantity * COALESCE(m.avco, 0)), 0) AS value
+			       COALESCE(SUM(inv.quantity * COALESCE(m.unit_cost, 0)), 0) AS value
 			FROM inventory inv
-			LEFT JOIN (`+avcoSubquery+`) m ON m.sku = inv.sku
-			WHERE inv.quantity > 0
+			JOIN articles a ON a.sku = inv.sku AND a.tenant_id = inv.tenant_id
+			LEFT JOIN (`+layerCostSubquery+`) m ON m.sku = inv.sku
+			WHERE inv.tenant_id = ? AND inv.quantity > 0
 			GROUP BY inv.location
 			ORDER BY value DESC
-		`).Scan(&rows).Error
+		`, tenantID, tenantID).Scan(&rows).Error
 	case "category":
 		err = r.DB.Raw(`
 			SELECT COALESCE(a.category_id, 'uncategorized') AS key,
 			       COALESCE(c.name, 'Uncategorized') AS label,
 			       COALESCE(SUM(inv.quantity), 0) AS qty,
-			       COALESCE(SUM(inv.quantity * COALESCE(m.avco, 0)), 0) AS value
+			       COALESCE(SUM(inv.quantity * COALESCE(m.unit_cost, 0)), 0) AS value
 			FROM inventory inv
-			JOIN articles a ON a.sku = inv.sku
+			JOIN articles a ON a.sku = inv.sku AND a.tenant_id = inv.tenant_id
 			LEFT JOIN categories c ON c.id = a.category_id
-			LEFT JOIN (`+avcoSubquery+`) m ON m.sku = inv.sku
-			WHERE inv.quantity > 0
+			LEFT JOIN (`+layerCostSubquery+`) m ON m.sku = inv.sku
+			WHERE inv.tenant_id = ? AND inv.quantity > 0
 			GROUP BY a.category_id, c.name
 			ORDER BY value DESC
-		`).Scan(&rows).Error
+		`, tenantID, tenantID).Scan(&rows).Error
 	default: // article
 		groupBy = "article"
 		err = r.DB.Raw(`
 			SELECT inv.sku AS key,
 			       COALESCE(a.name, inv.sku) AS label,
 			       COALESCE(SUM(inv.quantity), 0) AS qty,
-			       COALESCE(SUM(inv.quantity * COALESCE(m.avco, 0)), 0) AS value
+			       COALESCE(SUM(inv.quantity * COALESCE(m.unit_cost, 0)), 0) AS value
 			FROM inventory inv
-			LEFT JOIN articles a ON a.sku = inv.sku
-			LEFT JOIN (`+avcoSubquery+`) m ON m.sku = inv.sku
-			WHERE inv.quantity > 0
+			JOIN articles a ON a.sku = inv.sku AND a.tenant_id = inv.tenant_id
+			LEFT JOIN (`+layerCostSubquery+`) m ON m.sku = inv.sku
+			WHERE inv.tenant_id = ? AND inv.quantity > 0
 			GROUP BY inv.sku, a.name
 			ORDER BY value DESC
-		`).Scan(&rows).Error
+		`, tenantID, tenantID).Scan(&rows).Error
 	}
 
 	if err != nil {
 		return nil, &responses.InternalResponse{Error: err, Message: "Error al calcular valuación de inventario", Handled: false}
 	}
 
+	currency, err := tenantBaseCurrency(r.DB, tenantID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la moneda base", Handled: false}
+	}
+
 	breakdown := make([]responses.ValuationBreakdownItem, len(rows))
 	var total float64
 	for i, r := range rows {
@@ -1610,8 +1852,54 @@ func (r *InventoryRepository) GetValuation(groupBy string) (*responses.Inventory
 
 	return &responses.InventoryValuationResponse{
 		TotalValue: total,
-		Currency:   "CRC",
+		Currency:   currency,
 		GroupBy:    groupBy,
 		Breakdown:  breakdown,
 	}, nil
 }
+
+// ExportValuationToExcel writes the layer-based valuation (see GetValuation) to a workbook:
+// one row per group plus a total row.
+func (r *InventoryRepository) ExportValuationToExcel(tenantID, groupBy string) ([]byte, *responses.InternalResponse) {
+	valuation, errResp := r.GetValuation(tenantID, groupBy)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+
+	headers := []string{"Key", "Label", "Quantity", "Unit Cost", "Value (" + valuation.Currency + ")"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, header)
+	}
+
+	for i, item := range valuation.Breakdown {
+		row := i + 2
+		unitCost := 0.0
+		if item.Qty != 0 {
+			unitCost = item.Value / item.Qty
+		}
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), item.Key)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), item.Label)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), item.Qty)
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), unitCost)
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), item.Value)
+	}
+
+	totalRow := len(valuation.Breakdown) + 2
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", totalRow), "Total")
+	f.SetCellValue(sheet, fmt.Sprintf("E%d", totalRow), valuation.TotalValue)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al escribir archivo Excel",
+			Handled: false,
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+// inventory_tenant_isolation_test.go — multi-tenant isolation for the inventory table
+// (migration 000040). Companion to tenant_isolation_test.go and uses the same
+// testcontainers setup (setupGORMTestDB) and tenant constants.
+//
+// Each test seeds two tenants whose inventory shares a location code — and, for the
+// collision row, a SKU — then checks that every InventoryRepository read and write
+// only touches the caller's tenant. Removing a `tenant_id = ?` clause from the
+// production queries makes these tests fail.
+//
+// Run: go test -v ./repositories/... -run TestInventoryTenantIsolation
+// Requires Docker (testcontainers). Automatically skipped in -short mode.
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	isoInvLocation = "ISO-INV-LOC"
+	isoInvSKUA     = "ISO-INV-SKU-A"
+	isoInvSKUB     = "ISO-INV-SKU-B"
+)
+
+// seedInventoryRowForTenant inserts an inventory row with an explicit tenant_id via SQL,
+// so the test can build cross-tenant collisions the repository itself would refuse.
+func seedInventoryRowForTenant(t *testing.T, db *gorm.DB, tenantID, sku, location string, qty float64) string {
+	t.Helper()
+	id, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO inventory
+			(id, tenant_id, sku, name, location, quantity, reserved_qty, status, presentation, created_at, updated_at)
+		VALUES (?, ?::uuid, ?, ?, ?, ?, 0, 'available', 'unit', NOW(), NOW())`,
+		id, tenantID, sku, sku, location, qty).Error)
+	return id
+}
+
+// seedInventoryTenants seeds one article per tenant, one inventory row per tenant at the
+// shared location, and a tenant-B row for tenant A's SKU at that same location.
+// Returns the inventory ids as (A, B, B-collision).
+func seedInventoryTenants(t *testing.T, db *gorm.DB) (string, string, string) {
+	t.Helper()
+	seedArticleRow(t, db, testTenantA, isoInvSKUA, "Inventory A")
+	seedArticleRow(t, db, testTenantB, isoInvSKUB, "Inventory B")
+
+	invA := seedInventoryRowForTenant(t, db, testTenantA, isoInvSKUA, isoInvLocation, 10)
+	invB := seedInventoryRowForTenant(t, db, testTenantB, isoInvSKUB, isoInvLocation, 7)
+	invBCollision := seedInventoryRowForTenant(t, db, testTenantB, isoInvSKUA, isoInvLocation, 99)
+	return invA, invB, invBCollision
+}
+
+func TestInventoryTenantIsolation_GetAllInventory(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	invA, invB, invBCollision := seedInventoryTenants(t, db)
+	repo := &InventoryRepository{DB: db}
+
+	rowsA, resp := repo.GetAllInventory(testTenantA)
+	require.Nil(t, resp)
+	idsA := make([]string, 0, len(rowsA))
+	for _, r := range rowsA {
+		idsA = append(idsA, r.ID)
+	}
+	assert.Contains(t, idsA, invA)
+	assert.NotContains(t, idsA, invB, "tenant A must not see tenant B's inventory")
+	assert.NotContains(t, idsA, invBCollision, "tenant A must not see tenant B's row for the same SKU and location")
+
+	rowsB, resp := repo.GetAllInventory(testTenantB)
+	require.Nil(t, resp)
+	idsB := make([]string, 0, len(rowsB))
+	for _, r := range rowsB {
+		idsB = append(idsB, r.ID)
+	}
+	assert.ElementsMatch(t, []string{invB, invBCollision}, idsB)
+}
+
+func TestInventoryTenantIsolation_GetBySkuAndLocation(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	invA, _, invBCollision := seedInventoryTenants(t, db)
+	repo := &InventoryRepository{DB: db}
+
+	itemA, resp := repo.GetInventoryBySkuAndLocation(testTenantA, isoInvSKUA, isoInvLocation)
+	require.Nil(t, resp)
+	require.NotNil(t, itemA)
+	assert.Equal(t, invA, itemA.ID)
+	assert.Equal(t, 10.0, itemA.Quantity)
+
+	itemB, resp := repo.GetInventoryBySkuAndLocation(testTenantB, isoInvSKUA, isoInvLocation)
+	require.Nil(t, resp)
+	require.NotNil(t, itemB)
+	assert.Equal(t, invBCollision, itemB.ID)
+	assert.Equal(t, 99.0, itemB.Quantity)
+
+	missing, resp := repo.GetInventoryBySkuAndLocation(testTenantA, isoInvSKUB, isoInvLocation)
+	require.Nil(t, resp)
+	assert.Nil(t, missing, "tenant A must not resolve tenant B's SKU")
+}
+
+func TestInventoryTenantIsolation_PickSuggestions(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	seedInventoryTenants(t, db)
+	repo := &InventoryRepository{DB: db}
+
+	suggA, resp := repo.GetPickSuggestionsBySKU(testTenantA, isoInvSKUA, 0)
+	require.Nil(t, resp)
+	assert.Equal(t, 10.0, suggA.TotalFound, "tenant A must only pick from its own stock")
+
+	suggB, resp := repo.GetPickSuggestionsBySKU(testTenantB, isoInvSKUA, 0)
+	require.Nil(t, resp)
+	assert.Equal(t, 99.0, suggB.TotalFound)
+}
+
+func TestInventoryTenantIsolation_CreateAndDelete(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	invA, invB, _ := seedInventoryTenants(t, db)
+	repo := &InventoryRepository{DB: db}
+
+	// Tenant A cannot create stock for tenant B's article.
+	resp := repo.CreateInventory(testTenantA, "user-1", &requests.CreateInventory{
+		SKU: isoInvSKUB, Name: "Cross", Location: "ISO-INV-LOC-2", Quantity: 1,
+	})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+
+	// The same SKU at the same location in another tenant is not a duplicate.
+	seedArticleRow(t, db, testTenantA, "ISO-INV-SKU-A2", "Inventory A2")
+	seedInventoryRowForTenant(t, db, testTenantB, "ISO-INV-SKU-A2", isoInvLocation, 3)
+	resp = repo.CreateInventory(testTenantA, "user-1", &requests.CreateInventory{
+		SKU: "ISO-INV-SKU-A2", Name: "A2", Location: isoInvLocation, Quantity: 5,
+	})
+	require.Nil(t, resp)
+	var created database.Inventory
+	require.NoError(t, db.Where("tenant_id = ? AND sku = ?", testTenantA, "ISO-INV-SKU-A2").First(&created).Error)
+	assert.Equal(t, 5.0, created.Quantity)
+
+	// Tenant A cannot delete tenant B's row.
+	resp = repo.DeleteInventory(testTenantA, isoInvSKUB, isoInvLocation)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+	var count int64
+	require.NoError(t, db.Model(&database.Inventory{}).Where("id = ?", invB).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Deleting tenant A's row leaves tenant B's row for the same SKU and location.
+	require.Nil(t, repo.DeleteInventory(testTenantA, isoInvSKUA, isoInvLocation))
+	require.NoError(t, db.Model(&database.Inventory{}).Where("id = ?", invA).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+	require.NoError(t, db.Model(&database.Inventory{}).
+		Where("tenant_id = ? AND sku = ? AND location = ?", testTenantB, isoInvSKUA, isoInvLocation).
+		Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestInventoryTenantIsolation_InventoryLotsRequireOwnInventory(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	_, invB, _ := seedInventoryTenants(t, db)
+	repo := &InventoryRepository{DB: db}
+
+	resp := repo.CreateInventoryLot(testTenantA, invB, &requests.CreateInventoryLotRequest{
+		LotID: "missing", Quantity: 1, Location: isoInvLocation,
+	})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+}
+
+func TestInventoryTenantIsolation_InventoryModelHasTenantIDField(t *testing.T) {
+	inv := database.Inventory{ID: "test", TenantID: testTenantA}
+	assert.Equal(t, testTenantA, inv.TenantID)
+}
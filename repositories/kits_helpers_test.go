@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func firstAidKit() []database.KitComponent {
+	return []database.KitComponent{
+		{ComponentSKU: "GAUZE", Quantity: 2},
+		{ComponentSKU: "BANDAGE", Quantity: 1},
+		{ComponentSKU: "GLOVES", Quantity: 0.5},
+	}
+}
+
+func TestKitBuildableQty(t *testing.T) {
+	assert.Equal(t, 3.0, kitBuildableQty(firstAidKit(), map[string]float64{"GAUZE": 7, "BANDAGE": 10, "GLOVES": 4}))
+	assert.Equal(t, 0.0, kitBuildableQty(firstAidKit(), map[string]float64{"GAUZE": 7, "BANDAGE": 10}), "a missing component blocks the kit")
+	assert.Equal(t, 5.0, kitBuildableQty(firstAidKit(), map[string]float64{"GAUZE": 10.0000001, "BANDAGE": 5, "GLOVES": 2.5}))
+	assert.Equal(t, 0.0, kitBuildableQty(nil, map[string]float64{"GAUZE": 7}))
+}
+
+func TestKitExplodableQty(t *testing.T) {
+	available := map[string]float64{"GAUZE": 20, "BANDAGE": 10, "GLOVES": 5}
+	assert.Equal(t, 4.0, kitExplodableQty(4, firstAidKit(), available), "limited by the shortfall")
+	assert.Equal(t, 10.0, kitExplodableQty(12, firstAidKit(), available), "limited by the components")
+	assert.Equal(t, 2.0, kitExplodableQty(2.5, firstAidKit(), available), "only whole kits")
+}
+
+func TestSliceAllocations(t *testing.T) {
+	l1, l2 := "L1", "L2"
+	allocs := []database.LocationAllocation{
+		{Location: "A-01", Quantity: 4, LotNumber: &l1},
+		{Location: "A-02", Quantity: 6, LotNumber: &l2},
+	}
+
+	got := sliceAllocations(allocs, 3, 5)
+	require.Len(t, got, 2)
+	assert.Equal(t, "A-01", got[0].Location)
+	assert.Equal(t, 1.0, got[0].Quantity)
+	assert.Equal(t, "A-02", got[1].Location)
+	assert.Equal(t, 4.0, got[1].Quantity)
+	assert.Equal(t, 4.0, allocs[0].Quantity, "input untouched")
+
+	got = sliceAllocations(allocs, 4, 2)
+	require.Len(t, got, 1)
+	assert.Equal(t, "L2", *got[0].LotNumber)
+	assert.Equal(t, 2.0, got[0].Quantity)
+
+	assert.Empty(t, sliceAllocations(allocs, 10, 2))
+}
+
+func TestSplitKitCost(t *testing.T) {
+	costs := splitKitCost(100, []float64{2, 1}, []float64{10, 30})
+	assert.InDelta(t, 40, costs[0], 1e-9)
+	assert.InDelta(t, 60, costs[1], 1e-9)
+
+	costs = splitKitCost(90, []float64{2, 1}, []float64{0, 0})
+	assert.InDelta(t, 60, costs[0], 1e-9, "without reference costs the split follows the quantities")
+	assert.InDelta(t, 30, costs[1], 1e-9)
+
+	assert.Equal(t, []float64{0}, splitKitCost(10, []float64{0}, []float64{0}))
+}
+
+func TestKitLotExpiration(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	d1, d2, bad := "2027-03-01", "2026-12-15", "n/a"
+	expiry := earliestAllocationExpiry([]database.LocationAllocation{
+		{ExpirationDate: &d1}, {ExpirationDate: nil}, {ExpirationDate: &d2}, {ExpirationDate: &bad},
+	})
+	require.NotNil(t, expiry)
+	assert.Equal(t, "2026-12-15", expiry.Format("2006-01-02"))
+
+	days := 30
+	got := kitLotExpiration(expiry, &days, now)
+	assert.Equal(t, "2026-10-31", got.Format("2006-01-02"), "the kit shelf life is shorter")
+	days = 365
+	got = kitLotExpiration(expiry, &days, now)
+	assert.Equal(t, "2026-12-15", got.Format("2006-01-02"), "the first component to expire wins")
+	assert.Nil(t, kitLotExpiration(nil, nil, now))
+}
+
+func TestAssemblyOrderIsDraft(t *testing.T) {
+	assert.Nil(t, assemblyOrderIsDraft(&database.AssemblyOrder{Status: database.AssemblyOrderDraft}))
+	resp := assemblyOrderIsDraft(&database.AssemblyOrder{OrderNumber: "ASM-2026-0001", Status: database.AssemblyOrderCompleted})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Message, "ASM-2026-0001")
+}
+
+func TestSerialTrackedKitArticle(t *testing.T) {
+	assert.Nil(t, serialTrackedKitArticle(map[string]database.Article{"KIT": {SKU: "KIT"}, "GAUZE": {SKU: "GAUZE", TrackByLot: true}}))
+	resp := serialTrackedKitArticle(map[string]database.Article{"AED": {SKU: "AED", TrackBySerial: true}})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func kitComponentItem(sku string, qtyPer float64, picked ...float64) requests.PickingTaskItemRequest {
+	item := requests.PickingTaskItemRequest{SKU: sku, KitSKU: tools.StrPtr("KIT"), KitQtyPer: &qtyPer}
+	for _, p := range picked {
+		item.Allocations = append(item.Allocations, database.LocationAllocation{Location: "A-01", Quantity: p, PickedQty: &p})
+	}
+	return item
+}
+
+func TestSalesOrderPickedQty_FoldsKitComponents(t *testing.T) {
+	items := []requests.PickingTaskItemRequest{
+		{SKU: "KIT", Allocations: []database.LocationAllocation{{Location: "B-01", Quantity: 2}}},
+		kitComponentItem("GAUZE", 2, 4, 2),
+		kitComponentItem("BANDAGE", 1, 2),
+		{SKU: "GAUZE", Allocations: []database.LocationAllocation{{Location: "A-02", Quantity: 5}}},
+	}
+	got := salesOrderPickedQty(items)
+	assert.Equal(t, 4.0, got["KIT"], "2 assembled + 2 complete kits of components (bandages limit)")
+	assert.Equal(t, 5.0, got["GAUZE"], "component picks do not count as the component's own line")
+	_, ok := got["BANDAGE"]
+	assert.False(t, ok)
+}
+
+func TestCheckPickAllowances_CountsExplodedKits(t *testing.T) {
+	items := []requests.PickingTaskItemRequest{
+		kitComponentItem("GAUZE", 2, 6),
+		kitComponentItem("BANDAGE", 1, 3),
+	}
+	soItems := []database.SalesOrderItem{{ArticleSKU: "KIT", ExpectedQty: 3}}
+	assert.Nil(t, checkPickAllowances(items, soItems, 0, 0))
+
+	soItems[0].PickedQty = 1
+	resp := checkPickAllowances(items, soItems, 0, 0)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+}
@@ -0,0 +1,1149 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KitsRepository implements ports.KitsRepository using GORM.
+type KitsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.KitsRepository = (*KitsRepository)(nil)
+
+// kitQtyEpsilon absorbs float noise when whole kits are counted from component quantities.
+const kitQtyEpsilon = 1e-6
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextAssemblyNumber generates "ASM-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextAssemblyNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("ASM-%d-", year)
+
+	lockKey := fmt.Sprintf("asm-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire assembly number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(order_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM assembly_orders
+		WHERE tenant_id = $2
+		  AND order_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate assembly number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// kitBuildableQty is how many whole kits the available component quantities allow (0 for a
+// kit without components).
+func kitBuildableQty(components []database.KitComponent, available map[string]float64) float64 {
+	if len(components) == 0 {
+		return 0
+	}
+	buildable := math.Inf(1)
+	for _, c := range components {
+		if c.Quantity <= 0 {
+			continue
+		}
+		n := math.Floor(available[c.ComponentSKU]/c.Quantity + kitQtyEpsilon)
+		if n < buildable {
+			buildable = n
+		}
+	}
+	if math.IsInf(buildable, 1) || buildable < 0 {
+		return 0
+	}
+	return buildable
+}
+
+// kitExplodableQty is how many of the shortfall kits can be picked as their components.
+func kitExplodableQty(shortfall float64, components []database.KitComponent, available map[string]float64) float64 {
+	return math.Min(math.Floor(shortfall+kitQtyEpsilon), kitBuildableQty(components, available))
+}
+
+// sliceAllocations returns take units of the FEFO allocations after skipping the first skip
+// units (already allocated to an earlier line of the same document).
+func sliceAllocations(allocs []database.LocationAllocation, skip, take float64) []database.LocationAllocation {
+	out := make([]database.LocationAllocation, 0, len(allocs))
+	for _, a := range allocs {
+		if take <= kitQtyEpsilon {
+			break
+		}
+		qty := a.Quantity
+		if skip > 0 {
+			used := math.Min(skip, qty)
+			skip -= used
+			qty -= used
+		}
+		if qty <= kitQtyEpsilon {
+			continue
+		}
+		if qty > take {
+			qty = take
+		}
+		a.Quantity = qty
+		out = append(out, a)
+		take -= qty
+	}
+	return out
+}
+
+// splitKitCost spreads the cost of disassembled kits over the component lines in proportion
+// to their quantity times their reference cost, or to their quantity alone when no component
+// has a reference cost.
+func splitKitCost(total float64, quantities, refCosts []float64) []float64 {
+	weights := make([]float64, len(quantities))
+	sum := 0.0
+	for i, q := range quantities {
+		weights[i] = q * refCosts[i]
+		sum += weights[i]
+	}
+	if sum <= costEpsilon {
+		sum = 0
+		for i, q := range quantities {
+			weights[i] = q
+			sum += q
+		}
+	}
+	out := make([]float64, len(quantities))
+	if sum <= costEpsilon {
+		return out
+	}
+	for i, w := range weights {
+		out[i] = total * w / sum
+	}
+	return out
+}
+
+// earliestAllocationExpiry returns the earliest expiration date of the allocations, if any.
+func earliestAllocationExpiry(allocs []database.LocationAllocation) *time.Time {
+	var earliest *time.Time
+	for _, a := range allocs {
+		if a.ExpirationDate == nil {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", *a.ExpirationDate)
+		if err != nil {
+			continue
+		}
+		if earliest == nil || d.Before(*earliest) {
+			earliest = &d
+		}
+	}
+	return earliest
+}
+
+// earlierTime returns the earlier of two optional times.
+func earlierTime(a, b *time.Time) *time.Time {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case b.Before(*a):
+		return b
+	}
+	return a
+}
+
+// kitLotExpiration is the expiration of a lot of assembled kits: the first component to
+// expire, capped by the kit's own shelf life.
+func kitLotExpiration(componentExpiry *time.Time, shelfLifeDays *int, now time.Time) *time.Time {
+	var shelfLife *time.Time
+	if shelfLifeDays != nil && *shelfLifeDays > 0 {
+		d := now.AddDate(0, 0, *shelfLifeDays)
+		shelfLife = &d
+	}
+	return earlierTime(componentExpiry, shelfLife)
+}
+
+// assemblyOrderIsDraft checks the order still accepts changes (409 otherwise).
+func assemblyOrderIsDraft(order *database.AssemblyOrder) *responses.InternalResponse {
+	if order.Status == database.AssemblyOrderDraft {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("La orden %s ya no está en borrador (%s)", order.OrderNumber, order.Status),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+	}
+}
+
+// serialTrackedKitArticle rejects kits and components tracked by serial number: assembly
+// orders move quantities, not individual serials.
+func serialTrackedKitArticle(articles map[string]database.Article) *responses.InternalResponse {
+	for sku, a := range articles {
+		if a.TrackBySerial {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("Los artículos con número de serie no pueden formar parte de un kit (%s)", sku),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+// loadKit returns the kit of an article and its components; nil when the article has no kit.
+func loadKit(tx *gorm.DB, tenantID, kitSKU string) (*database.Kit, []database.KitComponent, error) {
+	var kit database.Kit
+	err := tx.Where("tenant_id = ? AND kit_sku = ?", tenantID, kitSKU).First(&kit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("load kit %s: %w", kitSKU, err)
+	}
+	var components []database.KitComponent
+	if err := tx.Where("kit_id = ?", kit.ID).Order("component_sku ASC").Find(&components).Error; err != nil {
+		return nil, nil, fmt.Errorf("load components of kit %s: %w", kitSKU, err)
+	}
+	return &kit, components, nil
+}
+
+// explodableKitComponents returns the components of a kit that sales orders may pick as
+// components (explode_on_pick); nil for any other article.
+func explodableKitComponents(tx *gorm.DB, tenantID, sku string) ([]database.KitComponent, error) {
+	kit, components, err := loadKit(tx, tenantID, sku)
+	if err != nil || kit == nil || !kit.ExplodeOnPick {
+		return nil, err
+	}
+	return components, nil
+}
+
+// availableQtyBySKU returns the stock available to pick (net of reservations and holds) of
+// the given SKUs.
+func availableQtyBySKU(tx *gorm.DB, tenantID string, skus []string) (map[string]float64, error) {
+	out := make(map[string]float64, len(skus))
+	if len(skus) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		SKU string  `gorm:"column:sku"`
+		Qty float64 `gorm:"column:qty"`
+	}
+	if err := tx.Raw(`
+		SELECT i.sku, SUM(GREATEST(`+tools.AvailableQtySQL("i")+`, 0)) AS qty
+		  FROM inventory i
+		 WHERE i.tenant_id = ? AND i.sku IN ?
+		 GROUP BY i.sku
+	`, tenantID, skus).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load available stock: %w", err)
+	}
+	for _, r := range rows {
+		out[r.SKU] = r.Qty
+	}
+	return out, nil
+}
+
+// buildKitViews adds the components, names and stock to kits.
+func buildKitViews(tx *gorm.DB, tenantID string, kits []database.Kit) ([]responses.KitView, error) {
+	views := make([]responses.KitView, 0, len(kits))
+	if len(kits) == 0 {
+		return views, nil
+	}
+	kitIDs := make([]string, 0, len(kits))
+	skus := make([]string, 0, len(kits))
+	for _, k := range kits {
+		kitIDs = append(kitIDs, k.ID)
+		skus = append(skus, k.KitSKU)
+	}
+	var components []database.KitComponent
+	if err := tx.Where("kit_id IN ?", kitIDs).Order("component_sku ASC").Find(&components).Error; err != nil {
+		return nil, fmt.Errorf("load kit components: %w", err)
+	}
+	byKit := make(map[string][]database.KitComponent, len(kits))
+	for _, c := range components {
+		byKit[c.KitID] = append(byKit[c.KitID], c)
+		skus = append(skus, c.ComponentSKU)
+	}
+	articles, err := returnArticles(tx, tenantID, skus)
+	if err != nil {
+		return nil, err
+	}
+	available, err := availableQtyBySKU(tx, tenantID, skus)
+	if err != nil {
+		return nil, err
+	}
+	articleName := func(sku string) *string {
+		if a, ok := articles[sku]; ok {
+			name := a.Name
+			return &name
+		}
+		return nil
+	}
+
+	for _, k := range kits {
+		view := responses.KitView{
+			Kit:          k,
+			KitName:      articleName(k.KitSKU),
+			Components:   []responses.KitComponentView{},
+			AvailableQty: available[k.KitSKU],
+			BuildableQty: kitBuildableQty(byKit[k.ID], available),
+		}
+		for _, c := range byKit[k.ID] {
+			view.Components = append(view.Components, responses.KitComponentView{
+				KitComponent:  c,
+				ComponentName: articleName(c.ComponentSKU),
+				AvailableQty:  available[c.ComponentSKU],
+			})
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// lockAssemblyOrder loads a tenant's assembly order FOR UPDATE.
+func lockAssemblyOrder(tx *gorm.DB, id, tenantID string) (*database.AssemblyOrder, *responses.InternalResponse, error) {
+	var order database.AssemblyOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &responses.InternalResponse{Message: "Orden de ensamble no encontrada", Handled: true, StatusCode: responses.StatusNotFound}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("load assembly order: %w", err)
+	}
+	return &order, nil, nil
+}
+
+// loadAssemblyLines returns the component lines of an order.
+func loadAssemblyLines(tx *gorm.DB, orderID string) ([]database.AssemblyOrderLine, error) {
+	var lines []database.AssemblyOrderLine
+	if err := tx.Where("order_id = ?", orderID).Order("component_sku ASC").Find(&lines).Error; err != nil {
+		return nil, fmt.Errorf("load assembly order lines: %w", err)
+	}
+	return lines, nil
+}
+
+// assemblyOrderArticles loads the kit and component articles of an order and rejects serial
+// tracked ones.
+func assemblyOrderArticles(tx *gorm.DB, tenantID, kitSKU string, componentSKUs []string) (map[string]database.Article, *responses.InternalResponse, error) {
+	articles, err := returnArticles(tx, tenantID, append([]string{kitSKU}, componentSKUs...))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sku := range append([]string{kitSKU}, componentSKUs...) {
+		if _, ok := articles[sku]; !ok {
+			return nil, &responses.InternalResponse{
+				Message:    fmt.Sprintf("Artículo %s no encontrado", sku),
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}, nil
+		}
+	}
+	return articles, serialTrackedKitArticle(articles), nil
+}
+
+// ensureProducedLot returns the lot that produced units enter, creating it (available, empty)
+// with the given expiration when it does not exist yet.
+func ensureProducedLot(tx *gorm.DB, tenantID, sku, lotNumber string, expiration *time.Time) (*database.Lot, error) {
+	var lot database.Lot
+	err := tx.Where("tenant_id = ? AND sku = ? AND lot_number = ? AND (status IS NULL OR status != 'archived')", tenantID, sku, lotNumber).
+		First(&lot).Error
+	if err == nil {
+		return &lot, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find lot %s of %s: %w", lotNumber, sku, err)
+	}
+	id, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return nil, fmt.Errorf("generate lot id for %s/%s: %w", sku, lotNumber, err)
+	}
+	now := tools.GetCurrentTime()
+	lot = database.Lot{
+		ID:             id,
+		TenantID:       tenantID,
+		LotNumber:      lotNumber,
+		SKU:            sku,
+		ExpirationDate: expiration,
+		Status:         tools.StrPtr("available"),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := tx.Create(&lot).Error; err != nil {
+		return nil, fmt.Errorf("create lot %s/%s: %w", sku, lotNumber, err)
+	}
+	return &lot, nil
+}
+
+// consumeAssemblyStock takes one FEFO allocation of sku out of the order location and posts
+// its outbound movement, costed from the cost layers.
+func consumeAssemblyStock(tx *gorm.DB, order *database.AssemblyOrder, sku string, alloc database.LocationAllocation, movementType, userID string) (*database.InventoryMovement, *responses.InternalResponse, error) {
+	var inv database.Inventory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND location = ?", order.TenantID, sku, alloc.Location).First(&inv).Error; err != nil {
+		return nil, nil, fmt.Errorf("load inventory %s @ %s: %w", sku, alloc.Location, err)
+	}
+	qty := alloc.Quantity
+	if inv.Quantity-inv.ReservedQty-inv.HeldQty < qty-qcQtyEpsilon {
+		return nil, &responses.InternalResponse{
+			Message: fmt.Sprintf("Stock insuficiente de %s en %s: se requieren %.3f y hay %.3f disponibles",
+				sku, alloc.Location, qty, inv.Quantity-inv.ReservedQty-inv.HeldQty),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}, nil
+	}
+	beforeQty := inv.Quantity
+	afterQty := inv.Quantity - qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", inv.ID).
+		Updates(map[string]interface{}{"quantity": afterQty, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, nil, fmt.Errorf("update inventory %s @ %s: %w", sku, alloc.Location, err)
+	}
+
+	var lotID *string
+	if alloc.LotNumber != nil && *alloc.LotNumber != "" {
+		var lot database.Lot
+		if err := tx.Where("tenant_id = ? AND sku = ? AND lot_number = ?", order.TenantID, sku, *alloc.LotNumber).First(&lot).Error; err != nil {
+			return nil, nil, fmt.Errorf("find lot %s of %s: %w", *alloc.LotNumber, sku, err)
+		}
+		if err := tx.Exec(`UPDATE inventory_lots SET quantity = GREATEST(0, quantity - ?) WHERE inventory_id = ? AND lot_id = ?`,
+			qty, inv.ID, lot.ID).Error; err != nil {
+			return nil, nil, fmt.Errorf("update inventory lot %s @ %s: %w", lot.LotNumber, alloc.Location, err)
+		}
+		if err := tx.Exec(`UPDATE lots SET quantity = GREATEST(0, quantity - ?), updated_at = NOW() WHERE id = ?`, qty, lot.ID).Error; err != nil {
+			return nil, nil, fmt.Errorf("update lot %s: %w", lot.LotNumber, err)
+		}
+		lotID = &lot.ID
+	}
+
+	// The inventory price is in the article's currency; cost the consumption in the base currency.
+	priceCost, err := basePriceCost(tx, sku, inv.UnitPrice)
+	if err != nil {
+		return nil, nil, err
+	}
+	movID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate assembly movement id: %w", err)
+	}
+	refType := "assembly_order"
+	mov := &database.InventoryMovement{
+		ID:             movID,
+		SKU:            sku,
+		Location:       alloc.Location,
+		MovementType:   movementType,
+		Quantity:       -qty,
+		RemainingStock: afterQty,
+		Reason:         tools.StrPtr(order.OrderType + " order " + order.OrderNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &refType,
+		ReferenceID:    &order.ID,
+		LotID:          lotID,
+		UnitCost:       &priceCost,
+		BeforeQty:      &beforeQty,
+		AfterQty:       &afterQty,
+		UserID:         &userID,
+	}
+	if err := tx.Create(mov).Error; err != nil {
+		return nil, nil, fmt.Errorf("create %s movement: %w", movementType, err)
+	}
+	if err := consumeCostLayers(tx, mov, priceCost); err != nil {
+		return nil, nil, err
+	}
+	return mov, nil, nil
+}
+
+// produceAssemblyStock adds qty of the article at the order location (creating the inventory
+// row when needed), into lot when given, and posts its inbound movement at unitCost with its
+// cost layer. A location without room for qty is the handled capacity 409.
+func produceAssemblyStock(tx *gorm.DB, order *database.AssemblyOrder, article database.Article, lot *database.Lot, qty, unitCost float64, movementType, userID string) (*responses.InternalResponse, error) {
+	location := order.Location
+	exceeded, err := tools.CheckLocationCapacity(tx, order.TenantID, location, []tools.CapacityAddition{{SKU: article.SKU, Quantity: qty}})
+	if err != nil {
+		return nil, err
+	}
+	if exceeded != nil {
+		return tools.CapacityExceededResponse(exceeded), nil
+	}
+
+	var inv database.Inventory
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND location = ?", order.TenantID, article.SKU, location).First(&inv).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		invID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, fmt.Errorf("generate inventory id: %w", err)
+		}
+		inv = database.Inventory{
+			ID:           invID,
+			TenantID:     order.TenantID,
+			SKU:          article.SKU,
+			Name:         article.Name,
+			Description:  article.Description,
+			Location:     location,
+			Status:       "available",
+			Presentation: article.Presentation,
+			UnitPrice:    article.UnitPrice,
+			CreatedAt:    tools.GetCurrentTime(),
+			UpdatedAt:    tools.GetCurrentTime(),
+		}
+		if err := tx.Create(&inv).Error; err != nil {
+			return nil, fmt.Errorf("create inventory %s @ %s: %w", article.SKU, location, err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("find inventory %s @ %s: %w", article.SKU, location, err)
+	}
+
+	beforeQty := inv.Quantity
+	afterQty := inv.Quantity + qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", inv.ID).
+		Updates(map[string]interface{}{"quantity": afterQty, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, fmt.Errorf("update inventory %s @ %s: %w", article.SKU, location, err)
+	}
+
+	var lotID *string
+	if lot != nil {
+		if _, err := restoreReturnedLot(tx, order.TenantID, inv.ID, article.SKU, lot.LotNumber, location, qty); err != nil {
+			return nil, err
+		}
+		lotID = &lot.ID
+	}
+
+	movID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return nil, fmt.Errorf("generate assembly movement id: %w", err)
+	}
+	refType := "assembly_order"
+	mov := &database.InventoryMovement{
+		ID:             movID,
+		SKU:            article.SKU,
+		Location:       location,
+		MovementType:   movementType,
+		Quantity:       qty,
+		RemainingStock: afterQty,
+		Reason:         tools.StrPtr(order.OrderType + " order " + order.OrderNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &refType,
+		ReferenceID:    &order.ID,
+		LotID:          lotID,
+		UnitCost:       &unitCost,
+		BeforeQty:      &beforeQty,
+		AfterQty:       &afterQty,
+		UserID:         &userID,
+	}
+	if err := tx.Create(mov).Error; err != nil {
+		return nil, fmt.Errorf("create %s movement: %w", movementType, err)
+	}
+	return nil, recordCostLayer(tx, mov)
+}
+
+// allocateAtLocation runs FEFO over the stock of sku at the order location, optionally limited
+// to one lot; 409 when it does not cover qty.
+func allocateAtLocation(tx *gorm.DB, order *database.AssemblyOrder, sku string, lotNumber *string, qty float64) ([]database.LocationAllocation, *responses.InternalResponse, error) {
+	rows, err := queryPickRows(tx, order.TenantID, sku, order.Location)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load stock of %s @ %s: %w", sku, order.Location, err)
+	}
+	if lotNumber != nil {
+		filtered := rows[:0]
+		for _, r := range rows {
+			if r.LotNumber != nil && *r.LotNumber == *lotNumber {
+				filtered = append(filtered, r)
+			}
+		}
+		rows = filtered
+	}
+	sugg := allocatePickRows(rows, qty)
+	if sugg.TotalFound < qty-kitQtyEpsilon {
+		return nil, &responses.InternalResponse{
+			Message: fmt.Sprintf("Stock insuficiente de %s en %s: se requieren %.3f y hay %.3f disponibles",
+				sku, order.Location, qty, sugg.TotalFound),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}, nil
+	}
+	return sugg.Allocations, nil, nil
+}
+
+// consumeAllocations consumes every allocation of sku and returns their total cost.
+func consumeAllocations(tx *gorm.DB, order *database.AssemblyOrder, sku string, allocs []database.LocationAllocation, movementType, userID string) (float64, *responses.InternalResponse, error) {
+	total := 0.0
+	for _, alloc := range allocs {
+		mov, resp, err := consumeAssemblyStock(tx, order, sku, alloc, movementType, userID)
+		if err != nil || resp != nil {
+			return 0, resp, err
+		}
+		total += costOrZero(mov.COGS)
+	}
+	return total, nil, nil
+}
+
+// completeAssembly consumes the component lines (FEFO) and produces the kits at the summed
+// component cost. Returns the kit unit cost.
+func completeAssembly(tx *gorm.DB, order *database.AssemblyOrder, lines []database.AssemblyOrderLine, articles map[string]database.Article, userID string) (float64, *responses.InternalResponse, error) {
+	total := 0.0
+	var componentExpiry *time.Time
+	for i := range lines {
+		line := &lines[i]
+		allocs, resp, err := allocateAtLocation(tx, order, line.ComponentSKU, nil, line.Quantity)
+		if err != nil || resp != nil {
+			return 0, resp, err
+		}
+		cost, resp, err := consumeAllocations(tx, order, line.ComponentSKU, allocs, database.MovementAssemblyConsume, userID)
+		if err != nil || resp != nil {
+			return 0, resp, err
+		}
+		componentExpiry = earlierTime(componentExpiry, earliestAllocationExpiry(allocs))
+		if err := saveAssemblyLineResult(tx, line, allocs, cost); err != nil {
+			return 0, nil, err
+		}
+		total += cost
+	}
+
+	kit := articles[order.KitSKU]
+	var lot *database.Lot
+	if order.KitLotNumber != nil {
+		var err error
+		expiration := kitLotExpiration(componentExpiry, kit.ShelfLifeInDays, tools.GetCurrentTime())
+		if lot, err = ensureProducedLot(tx, order.TenantID, kit.SKU, *order.KitLotNumber, expiration); err != nil {
+			return 0, nil, err
+		}
+	}
+	unitCost := total / order.Quantity
+	if resp, err := produceAssemblyStock(tx, order, kit, lot, order.Quantity, unitCost, database.MovementAssemblyProduce, userID); err != nil || resp != nil {
+		return 0, resp, err
+	}
+	return unitCost, nil, nil
+}
+
+// completeDisassembly consumes the kits (FEFO, or the order's kit lot) and produces the
+// component lines, splitting the kit cost over them. Returns the kit unit cost.
+func completeDisassembly(tx *gorm.DB, order *database.AssemblyOrder, lines []database.AssemblyOrderLine, articles map[string]database.Article, userID string) (float64, *responses.InternalResponse, error) {
+	allocs, resp, err := allocateAtLocation(tx, order, order.KitSKU, order.KitLotNumber, order.Quantity)
+	if err != nil || resp != nil {
+		return 0, resp, err
+	}
+	kitCost, resp, err := consumeAllocations(tx, order, order.KitSKU, allocs, database.MovementDisassemblyConsume, userID)
+	if err != nil || resp != nil {
+		return 0, resp, err
+	}
+	kitExpiry := earliestAllocationExpiry(allocs)
+
+	quantities := make([]float64, len(lines))
+	refCosts := make([]float64, len(lines))
+	for i, line := range lines {
+		quantities[i] = line.Quantity
+		if refCosts[i], err = basePriceCost(tx, line.ComponentSKU, articles[line.ComponentSKU].UnitPrice); err != nil {
+			return 0, nil, err
+		}
+	}
+	costs := splitKitCost(kitCost, quantities, refCosts)
+
+	for i := range lines {
+		line := &lines[i]
+		var lot *database.Lot
+		if line.LotNumber != nil {
+			if lot, err = ensureProducedLot(tx, order.TenantID, line.ComponentSKU, *line.LotNumber, kitExpiry); err != nil {
+				return 0, nil, err
+			}
+		}
+		resp, err := produceAssemblyStock(tx, order, articles[line.ComponentSKU], lot, line.Quantity, costs[i]/line.Quantity,
+			database.MovementDisassemblyProduce, userID)
+		if err != nil || resp != nil {
+			return 0, resp, err
+		}
+		produced := []database.LocationAllocation{{Location: order.Location, Quantity: line.Quantity, LotNumber: line.LotNumber}}
+		if err := saveAssemblyLineResult(tx, line, produced, costs[i]); err != nil {
+			return 0, nil, err
+		}
+	}
+	return kitCost / order.Quantity, nil, nil
+}
+
+// saveAssemblyLineResult stores the allocations moved for a line and their cost.
+func saveAssemblyLineResult(tx *gorm.DB, line *database.AssemblyOrderLine, allocs []database.LocationAllocation, cost float64) error {
+	raw, err := json.Marshal(allocs)
+	if err != nil {
+		return fmt.Errorf("marshal allocations of %s: %w", line.ComponentSKU, err)
+	}
+	unitCost := cost / line.Quantity
+	line.Allocations = raw
+	line.UnitCost = &unitCost
+	line.TotalCost = &cost
+	if err := tx.Model(&database.AssemblyOrderLine{}).Where("id = ?", line.ID).Updates(map[string]interface{}{
+		"allocations": raw,
+		"unit_cost":   unitCost,
+		"total_cost":  cost,
+	}).Error; err != nil {
+		return fmt.Errorf("update assembly line %s: %w", line.ComponentSKU, err)
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods — kits
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *KitsRepository) ListKits(tenantID string) ([]responses.KitView, *responses.InternalResponse) {
+	var kits []database.Kit
+	if err := r.DB.Where("tenant_id = ?", tenantID).Order("kit_sku ASC").Find(&kits).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los kits"}
+	}
+	views, err := buildKitViews(r.DB, tenantID, kits)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los kits"}
+	}
+	return views, nil
+}
+
+func (r *KitsRepository) GetKit(tenantID, kitSKU string) (*responses.KitView, *responses.InternalResponse) {
+	var kit database.Kit
+	if err := r.DB.Where("tenant_id = ? AND kit_sku = ?", tenantID, kitSKU).First(&kit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Kit no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el kit"}
+	}
+	views, err := buildKitViews(r.DB, tenantID, []database.Kit{kit})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el kit"}
+	}
+	return &views[0], nil
+}
+
+func (r *KitsRepository) UpsertKit(tenantID, kitSKU, userID string, req *requests.UpsertKitRequest) (*responses.KitView, *responses.InternalResponse) {
+	var handledResp *responses.InternalResponse
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		componentSKUs := make([]string, 0, len(req.Components))
+		for _, c := range req.Components {
+			componentSKUs = append(componentSKUs, c.SKU)
+		}
+		if _, resp, err := assemblyOrderArticles(tx, tenantID, kitSKU, componentSKUs); err != nil || resp != nil {
+			handledResp = resp
+			if err == nil {
+				err = fmt.Errorf("invalid kit articles")
+			}
+			return err
+		}
+
+		// Kits do not nest: a component cannot be a kit, and a kit cannot be a component.
+		var nested []string
+		if err := tx.Model(&database.Kit{}).Where("tenant_id = ? AND kit_sku IN ?", tenantID, componentSKUs).
+			Pluck("kit_sku", &nested).Error; err != nil {
+			return fmt.Errorf("check nested kits: %w", err)
+		}
+		if len(nested) > 0 {
+			handledResp = &responses.InternalResponse{
+				Message:    fmt.Sprintf("El componente %s es un kit; los kits no pueden anidarse", nested[0]),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return fmt.Errorf("nested kit")
+		}
+		var parents []string
+		if err := tx.Raw(`
+			SELECT k.kit_sku FROM kit_components kc JOIN kits k ON k.id = kc.kit_id
+			 WHERE k.tenant_id = ? AND kc.component_sku = ?
+			 ORDER BY k.kit_sku
+		`, tenantID, kitSKU).Scan(&parents).Error; err != nil {
+			return fmt.Errorf("check parent kits: %w", err)
+		}
+		if len(parents) > 0 {
+			handledResp = &responses.InternalResponse{
+				Message:    fmt.Sprintf("%s es componente del kit %s; los kits no pueden anidarse", kitSKU, parents[0]),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return fmt.Errorf("nested kit")
+		}
+
+		var kit database.Kit
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND kit_sku = ?", tenantID, kitSKU).First(&kit).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			id, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate kit id: %w", err)
+			}
+			kit = database.Kit{
+				ID:            id,
+				TenantID:      tenantID,
+				KitSKU:        kitSKU,
+				ExplodeOnPick: req.ExplodeOnPick,
+				Notes:         req.Notes,
+				CreatedBy:     tools.StrPtr(userID),
+			}
+			if err := tx.Create(&kit).Error; err != nil {
+				return fmt.Errorf("create kit: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("load kit: %w", err)
+		default:
+			if err := tx.Model(&database.Kit{}).Where("id = ?", kit.ID).Updates(map[string]interface{}{
+				"explode_on_pick": req.ExplodeOnPick,
+				"notes":           req.Notes,
+				"updated_at":      tools.GetCurrentTime(),
+			}).Error; err != nil {
+				return fmt.Errorf("update kit: %w", err)
+			}
+			if err := tx.Where("kit_id = ?", kit.ID).Delete(&database.KitComponent{}).Error; err != nil {
+				return fmt.Errorf("replace kit components: %w", err)
+			}
+		}
+
+		for _, c := range req.Components {
+			id, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate kit component id: %w", err)
+			}
+			if err := tx.Create(&database.KitComponent{
+				ID:           id,
+				KitID:        kit.ID,
+				ComponentSKU: c.SKU,
+				Quantity:     c.Quantity,
+			}).Error; err != nil {
+				return fmt.Errorf("create kit component %s: %w", c.SKU, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if handledResp != nil {
+			return nil, handledResp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al guardar el kit"}
+	}
+	return r.GetKit(tenantID, kitSKU)
+}
+
+func (r *KitsRepository) DeleteKit(tenantID, kitSKU string) *responses.InternalResponse {
+	var kit database.Kit
+	if err := r.DB.Where("tenant_id = ? AND kit_sku = ?", tenantID, kitSKU).First(&kit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &responses.InternalResponse{Message: "Kit no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return &responses.InternalResponse{Error: err, Message: "Error al eliminar el kit"}
+	}
+	var drafts int64
+	if err := r.DB.Model(&database.AssemblyOrder{}).
+		Where("tenant_id = ? AND kit_sku = ? AND status = ?", tenantID, kitSKU, database.AssemblyOrderDraft).
+		Count(&drafts).Error; err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al eliminar el kit"}
+	}
+	if drafts > 0 {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("El kit %s tiene %d órdenes de ensamble en borrador", kitSKU, drafts),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+	if err := r.DB.Delete(&database.Kit{}, "id = ?", kit.ID).Error; err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al eliminar el kit"}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods — assembly orders
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *KitsRepository) ListAssemblyOrders(tenantID string, status, orderType, kitSKU *string, limit, offset int) ([]database.AssemblyOrder, *responses.InternalResponse) {
+	query := r.DB.Model(&database.AssemblyOrder{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+	if orderType != nil && *orderType != "" {
+		query = query.Where("order_type = ?", *orderType)
+	}
+	if kitSKU != nil && *kitSKU != "" {
+		query = query.Where("kit_sku = ?", *kitSKU)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var orders []database.AssemblyOrder
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&orders).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las órdenes de ensamble"}
+	}
+	return orders, nil
+}
+
+func (r *KitsRepository) GetAssemblyOrder(id, tenantID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	var order database.AssemblyOrder
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Orden de ensamble no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la orden de ensamble"}
+	}
+	lines, err := loadAssemblyLines(r.DB, order.ID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la orden de ensamble"}
+	}
+	if lines == nil {
+		lines = []database.AssemblyOrderLine{}
+	}
+	view := &responses.AssemblyOrderView{AssemblyOrder: order, Lines: lines}
+
+	var names []string
+	if err := r.DB.Model(&database.Article{}).Where("tenant_id = ? AND sku = ?", tenantID, order.KitSKU).
+		Pluck("name", &names).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la orden de ensamble"}
+	}
+	if len(names) > 0 {
+		view.KitName = &names[0]
+	}
+	return view, nil
+}
+
+func (r *KitsRepository) CreateAssemblyOrder(tenantID, userID string, req *requests.CreateAssemblyOrderRequest) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	var orderID string
+	var handledResp *responses.InternalResponse
+	fail := func(status int, format string, args ...interface{}) error {
+		handledResp = &responses.InternalResponse{Message: fmt.Sprintf(format, args...), Handled: true, StatusCode: status}
+		return fmt.Errorf("invalid assembly order")
+	}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		kit, components, err := loadKit(tx, tenantID, req.KitSKU)
+		if err != nil {
+			return err
+		}
+		if kit == nil || len(components) == 0 {
+			return fail(responses.StatusNotFound, "El artículo %s no tiene kit definido", req.KitSKU)
+		}
+		if ok, err := returnLocationExists(tx, tenantID, req.Location); err != nil {
+			return err
+		} else if !ok {
+			return fail(responses.StatusBadRequest, "La ubicación %s no existe o está inactiva", req.Location)
+		}
+
+		componentSKUs := make([]string, 0, len(components))
+		for _, c := range components {
+			componentSKUs = append(componentSKUs, c.ComponentSKU)
+		}
+		articles, resp, err := assemblyOrderArticles(tx, tenantID, req.KitSKU, componentSKUs)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("invalid kit articles")
+		}
+
+		kitArticle := articles[req.KitSKU]
+		if req.KitLotNumber != nil && !kitArticle.TrackByLot {
+			return fail(responses.StatusBadRequest, "El kit %s no se controla por lote", req.KitSKU)
+		}
+		kitLot := req.KitLotNumber
+		if req.OrderType == database.AssemblyOrderAssembly && kitLot == nil && kitArticle.TrackByLot {
+			numbers, err := nextSeriesNumbers(tx, tenantID, database.NumberSeriesLot, &kitArticle, 1)
+			if err != nil {
+				return err
+			}
+			kitLot = &numbers[0]
+		}
+
+		componentLots := make(map[string]string, len(req.ComponentLots))
+		for _, cl := range req.ComponentLots {
+			componentLots[cl.SKU] = cl.LotNumber
+		}
+		if req.OrderType == database.AssemblyOrderAssembly && len(componentLots) > 0 {
+			return fail(responses.StatusBadRequest, "component_lots solo aplica a órdenes de desensamble")
+		}
+		for sku := range componentLots {
+			a, ok := articles[sku]
+			if !ok || sku == req.KitSKU {
+				return fail(responses.StatusBadRequest, "%s no es componente del kit %s", sku, req.KitSKU)
+			}
+			if !a.TrackByLot {
+				return fail(responses.StatusBadRequest, "El componente %s no se controla por lote", sku)
+			}
+		}
+		if req.OrderType == database.AssemblyOrderDisassembly {
+			for _, c := range components {
+				if _, ok := componentLots[c.ComponentSKU]; articles[c.ComponentSKU].TrackByLot && !ok {
+					return fail(responses.StatusBadRequest, "Indique en component_lots el lote en que vuelve el componente %s", c.ComponentSKU)
+				}
+			}
+		}
+
+		number, err := nextAssemblyNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		if orderID, err = tools.GenerateNanoid(tx); err != nil {
+			return fmt.Errorf("generate assembly order id: %w", err)
+		}
+		order := database.AssemblyOrder{
+			ID:           orderID,
+			TenantID:     tenantID,
+			OrderNumber:  number,
+			OrderType:    req.OrderType,
+			KitSKU:       req.KitSKU,
+			Quantity:     req.Quantity,
+			Location:     req.Location,
+			KitLotNumber: kitLot,
+			Status:       database.AssemblyOrderDraft,
+			Notes:        req.Notes,
+			CreatedBy:    tools.StrPtr(userID),
+		}
+		if err := tx.Create(&order).Error; err != nil {
+			return fmt.Errorf("create assembly order: %w", err)
+		}
+		for _, c := range components {
+			lineID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate assembly line id: %w", err)
+			}
+			line := database.AssemblyOrderLine{
+				ID:           lineID,
+				OrderID:      orderID,
+				ComponentSKU: c.ComponentSKU,
+				QtyPerKit:    c.Quantity,
+				Quantity:     c.Quantity * req.Quantity,
+			}
+			if lot, ok := componentLots[c.ComponentSKU]; ok {
+				line.LotNumber = tools.StrPtr(lot)
+			}
+			if err := tx.Create(&line).Error; err != nil {
+				return fmt.Errorf("create assembly line %s: %w", c.ComponentSKU, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if handledResp != nil {
+			return nil, handledResp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la orden de ensamble"}
+	}
+	return r.GetAssemblyOrder(orderID, tenantID)
+}
+
+func (r *KitsRepository) CompleteAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	var handledResp *responses.InternalResponse
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		order, resp, err := lockAssemblyOrder(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = assemblyOrderIsDraft(order)
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("assembly order not draft")
+		}
+
+		lines, err := loadAssemblyLines(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		componentSKUs := make([]string, 0, len(lines))
+		for _, l := range lines {
+			componentSKUs = append(componentSKUs, l.ComponentSKU)
+		}
+		articles, resp, err := assemblyOrderArticles(tx, tenantID, order.KitSKU, componentSKUs)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("invalid kit articles")
+		}
+
+		var unitCost float64
+		if order.OrderType == database.AssemblyOrderDisassembly {
+			unitCost, resp, err = completeDisassembly(tx, order, lines, articles, userID)
+		} else {
+			unitCost, resp, err = completeAssembly(tx, order, lines, articles, userID)
+		}
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("assembly stock")
+		}
+
+		now := tools.GetCurrentTime()
+		if err := tx.Model(&database.AssemblyOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"status":        database.AssemblyOrderCompleted,
+			"kit_unit_cost": unitCost,
+			"completed_by":  userID,
+			"completed_at":  now,
+			"updated_at":    now,
+		}).Error; err != nil {
+			return fmt.Errorf("complete assembly order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if handledResp != nil {
+			return nil, handledResp
+		}
+		if resp := missingRateResponse(err); resp != nil {
+			return nil, resp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al completar la orden de ensamble"}
+	}
+	return r.GetAssemblyOrder(id, tenantID)
+}
+
+func (r *KitsRepository) CancelAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	var handledResp *responses.InternalResponse
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		order, resp, err := lockAssemblyOrder(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = assemblyOrderIsDraft(order)
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("assembly order not draft")
+		}
+		now := tools.GetCurrentTime()
+		if err := tx.Model(&database.AssemblyOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"status":       database.AssemblyOrderCancelled,
+			"cancelled_at": now,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return fmt.Errorf("cancel assembly order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if handledResp != nil {
+			return nil, handledResp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al cancelar la orden de ensamble"}
+	}
+	return r.GetAssemblyOrder(id, tenantID)
+}
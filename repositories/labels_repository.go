@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// LabelsRepository implements ports.LabelsRepository using GORM.
+type LabelsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.LabelsRepository = (*LabelsRepository)(nil)
+
+func (r *LabelsRepository) GetLabelItems(tenantID, kind string, ids []string) ([]responses.LabelItem, *responses.InternalResponse) {
+	var (
+		byID map[string]responses.LabelItem
+		err  error
+	)
+	switch kind {
+	case responses.LabelKindLocation:
+		byID, err = r.locationLabels(tenantID, ids)
+	case responses.LabelKindArticle:
+		byID, err = r.articleLabels(tenantID, ids)
+	case responses.LabelKindLot:
+		byID, err = r.lotLabels(tenantID, ids)
+	case responses.LabelKindSerial:
+		byID, err = r.serialLabels(tenantID, ids)
+	default:
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Tipo de etiqueta inválido: %s", kind),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los datos de las etiquetas",
+			Handled: false,
+		}
+	}
+
+	items := make([]responses.LabelItem, 0, len(ids))
+	var missing []string
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(missing) > 0 {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("No se encontraron registros para las etiquetas: %s", strings.Join(missing, ", ")),
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+	return items, nil
+}
+
+func (r *LabelsRepository) GetReceivingTaskLabelItems(tenantID, taskID string) ([]responses.LabelItem, *responses.InternalResponse) {
+	var task database.ReceivingTask
+	if err := r.DB.Where("id = ? AND tenant_id = ?", taskID, tenantID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{
+				Message:    "Tarea de recepción no encontrada",
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener la tarea de recepción",
+			Handled: false,
+		}
+	}
+	if task.Status != "completed" && task.Status != "completed_with_differences" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Solo se imprimen etiquetas de tareas de recepción completadas (estado actual: %s)", task.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	var items []requests.ReceivingTaskItemRequest
+	if err := json.Unmarshal(task.Items, &items); err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al leer los artículos de la tarea de recepción",
+			Handled: false,
+		}
+	}
+
+	skus := make([]string, 0, len(items))
+	for _, it := range items {
+		skus = append(skus, it.SKU)
+	}
+	names, err := r.articleNamesBySKU(tenantID, skus)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los artículos de la tarea de recepción",
+			Handled: false,
+		}
+	}
+	var lots []database.Lot
+	if err := r.DB.Where("tenant_id = ? AND sku IN ?", tenantID, skus).Find(&lots).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los lotes de la tarea de recepción",
+			Handled: false,
+		}
+	}
+	lotsByKey := make(map[string]database.Lot, len(lots))
+	for _, l := range lots {
+		lotsByKey[l.SKU+"\x00"+l.LotNumber] = l
+	}
+
+	labels := make([]responses.LabelItem, 0, len(items))
+	for _, it := range items {
+		for _, lr := range it.LotNumbers {
+			item := responses.LabelItem{Kind: responses.LabelKindLot, Code: lr.LotNumber, SKU: it.SKU, Name: names[it.SKU]}
+			if lot, ok := lotsByKey[it.SKU+"\x00"+lr.LotNumber]; ok {
+				item.ID = lot.ID
+				item.ExpirationDate = lot.ExpirationDate
+			} else if lr.ExpirationDate != nil {
+				item.ExpirationDate = tools.ParseDate(*lr.ExpirationDate)
+			}
+			labels = append(labels, item)
+		}
+		for _, s := range it.SerialNumbers {
+			labels = append(labels, responses.LabelItem{Kind: responses.LabelKindSerial, ID: s.ID, Code: s.SerialNumber, SKU: it.SKU, Name: names[it.SKU]})
+		}
+		if len(it.LotNumbers) == 0 && len(it.SerialNumbers) == 0 {
+			labels = append(labels, responses.LabelItem{Kind: responses.LabelKindArticle, Code: it.SKU, SKU: it.SKU, Name: names[it.SKU]})
+		}
+	}
+	return labels, nil
+}
+
+func (r *LabelsRepository) locationLabels(tenantID string, ids []string) (map[string]responses.LabelItem, error) {
+	var locations []database.Location
+	if err := r.DB.Where("tenant_id = ? AND id IN ?", tenantID, ids).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]responses.LabelItem, len(locations))
+	for _, l := range locations {
+		item := responses.LabelItem{Kind: responses.LabelKindLocation, ID: l.ID, Code: l.LocationCode}
+		if l.Description != nil {
+			item.Name = *l.Description
+		}
+		if l.Zone != nil {
+			item.Zone = *l.Zone
+		}
+		out[l.ID] = item
+	}
+	return out, nil
+}
+
+func (r *LabelsRepository) articleLabels(tenantID string, ids []string) (map[string]responses.LabelItem, error) {
+	var articles []database.Article
+	if err := r.DB.Where("tenant_id = ? AND id IN ?", tenantID, ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]responses.LabelItem, len(articles))
+	for _, a := range articles {
+		out[a.ID] = responses.LabelItem{Kind: responses.LabelKindArticle, ID: a.ID, Code: a.SKU, SKU: a.SKU, Name: a.Name}
+	}
+	return out, nil
+}
+
+func (r *LabelsRepository) lotLabels(tenantID string, ids []string) (map[string]responses.LabelItem, error) {
+	var lots []database.Lot
+	if err := r.DB.Where("tenant_id = ? AND id IN ?", tenantID, ids).Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	skus := make([]string, 0, len(lots))
+	for _, l := range lots {
+		skus = append(skus, l.SKU)
+	}
+	names, err := r.articleNamesBySKU(tenantID, skus)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]responses.LabelItem, len(lots))
+	for _, l := range lots {
+		out[l.ID] = responses.LabelItem{
+			Kind:           responses.LabelKindLot,
+			ID:             l.ID,
+			Code:           l.LotNumber,
+			SKU:            l.SKU,
+			Name:           names[l.SKU],
+			ExpirationDate: l.ExpirationDate,
+		}
+	}
+	return out, nil
+}
+
+func (r *LabelsRepository) serialLabels(tenantID string, ids []string) (map[string]responses.LabelItem, error) {
+	var serials []database.Serial
+	if err := r.DB.Where("tenant_id = ? AND id IN ?", tenantID, ids).Find(&serials).Error; err != nil {
+		return nil, err
+	}
+	skus := make([]string, 0, len(serials))
+	for _, s := range serials {
+		skus = append(skus, s.SKU)
+	}
+	names, err := r.articleNamesBySKU(tenantID, skus)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]responses.LabelItem, len(serials))
+	for _, s := range serials {
+		out[s.ID] = responses.LabelItem{Kind: responses.LabelKindSerial, ID: s.ID, Code: s.SerialNumber, SKU: s.SKU, Name: names[s.SKU]}
+	}
+	return out, nil
+}
+
+func (r *LabelsRepository) articleNamesBySKU(tenantID string, skus []string) (map[string]string, error) {
+	names := make(map[string]string, len(skus))
+	if len(skus) == 0 {
+		return names, nil
+	}
+	var articles []database.Article
+	if err := r.DB.Select("sku", "name").Where("tenant_id = ? AND sku IN ?", tenantID, skus).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range articles {
+		names[a.SKU] = a.Name
+	}
+	return names, nil
+}
@@ -89,10 +89,13 @@ func (r *LocationTypesRepositorySQLC) CreateLocationType(req *requests.LocationT
 		isActive = *req.IsActive
 	}
 	arg := sqlc.CreateLocationTypeParams{
-		Code:      req.Code,
-		Name:      req.Name,
-		SortOrder: req.SortOrder,
-		IsActive:  isActive,
+		Code:        req.Code,
+		Name:        req.Name,
+		SortOrder:   req.SortOrder,
+		IsActive:    isActive,
+		MaxUnits:    ptrFloatToPgNumeric(req.MaxUnits),
+		MaxWeightKg: ptrFloatToPgNumeric(req.MaxWeightKg),
+		MaxVolumeM3: ptrFloatToPgNumeric(req.MaxVolumeM3),
 	}
 	row, err := r.queries.CreateLocationType(ctx, arg)
 	if err != nil {
@@ -116,11 +119,14 @@ func (r *LocationTypesRepositorySQLC) UpdateLocationType(id string, req *request
 		isActive = *req.IsActive
 	}
 	arg := sqlc.UpdateLocationTypeParams{
-		ID:        id,
-		Code:      req.Code,
-		Name:      req.Name,
-		SortOrder: req.SortOrder,
-		IsActive:  isActive,
+		ID:          id,
+		Code:        req.Code,
+		Name:        req.Name,
+		SortOrder:   req.SortOrder,
+		IsActive:    isActive,
+		MaxUnits:    ptrFloatToPgNumeric(req.MaxUnits),
+		MaxWeightKg: ptrFloatToPgNumeric(req.MaxWeightKg),
+		MaxVolumeM3: ptrFloatToPgNumeric(req.MaxVolumeM3),
 	}
 	row, err := r.queries.UpdateLocationType(ctx, arg)
 	if err != nil {
@@ -149,12 +155,15 @@ func sqlcLocationTypeToDatabase(row sqlc.LocationType) database.LocationType {
 		uAt = row.UpdatedAt.Time
 	}
 	return database.LocationType{
-		ID:        row.ID,
-		Code:      row.Code,
-		Name:      row.Name,
-		SortOrder: row.SortOrder,
-		IsActive:  row.IsActive,
-		CreatedAt: cAt,
-		UpdatedAt: uAt,
+		ID:          row.ID,
+		Code:        row.Code,
+		Name:        row.Name,
+		SortOrder:   row.SortOrder,
+		IsActive:    row.IsActive,
+		CreatedAt:   cAt,
+		UpdatedAt:   uAt,
+		MaxUnits:    pgNumericToPtrFloat(row.MaxUnits),
+		MaxWeightKg: pgNumericToPtrFloat(row.MaxWeightKg),
+		MaxVolumeM3: pgNumericToPtrFloat(row.MaxVolumeM3),
 	}
 }
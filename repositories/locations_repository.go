@@ -97,6 +97,13 @@ func (r *LocationsRepository) CreateLocation(tenantID string, input *requests.Lo
 		IsWayOut:     input.IsWayOut,
 		CreatedAt:    tools.GetCurrentTime(),
 		UpdatedAt:    tools.GetCurrentTime(),
+		MaxUnits:     input.MaxUnits,
+		MaxWeightKg:  input.MaxWeightKg,
+		MaxVolumeM3:  input.MaxVolumeM3,
+		Aisle:        input.Aisle,
+		Rack:         input.Rack,
+		Level:        input.Level,
+		PickSequence: input.PickSequence,
 	}
 
 	err = r.DB.Omit("id").Create(location).Error
@@ -363,6 +370,42 @@ func (r *LocationsRepository) ValidateImportRows(tenantID string, rows []request
 	return results, nil
 }
 
+// GetLocationUtilization joins each location to its type's limits and to the load of the
+// inventory stored in it.
+func (r *LocationsRepository) GetLocationUtilization(tenantID string) ([]responses.LocationUtilization, *responses.InternalResponse) {
+	var rows []responses.LocationUtilization
+	err := r.DB.Raw(`
+		SELECT l.id AS location_id, l.location_code, l.zone, l.type, l.is_active,
+		       COALESCE(l.max_units, lt.max_units) AS max_units,
+		       COALESCE(l.max_weight_kg, lt.max_weight_kg) AS max_weight_kg,
+		       COALESCE(l.max_volume_m3, lt.max_volume_m3) AS max_volume_m3,
+		       COALESCE(s.units, 0) AS used_units,
+		       COALESCE(s.weight_kg, 0) AS used_weight_kg,
+		       COALESCE(s.volume_m3, 0) AS used_volume_m3
+		FROM locations l
+		LEFT JOIN location_types lt ON UPPER(lt.code) = UPPER(l.type)
+		LEFT JOIN (
+			SELECT i.location,
+			       SUM(i.quantity) AS units,
+			       SUM(i.quantity * `+tools.ArticleUnitWeightSQL+`) AS weight_kg,
+			       SUM(i.quantity * `+tools.ArticleUnitVolumeSQL+`) AS volume_m3
+			FROM inventory i
+			LEFT JOIN articles a ON a.sku = i.sku AND a.tenant_id = i.tenant_id
+			WHERE i.tenant_id = ?
+			GROUP BY i.location
+		) s ON s.location = l.location_code
+		WHERE l.tenant_id = ?
+		ORDER BY l.location_code`, tenantID, tenantID).Scan(&rows).Error
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener la utilización de ubicaciones",
+			Handled: false,
+		}
+	}
+	return rows, nil
+}
+
 func (l *LocationsRepository) ExportLocationsToExcel(tenantID string) ([]byte, *responses.InternalResponse) {
 	locations, errResp := l.GetAllLocations(tenantID)
 	if errResp != nil {
@@ -42,7 +42,7 @@ func (r *LocationsRepositorySQLC) GetAllLocations(tenantID string) ([]database.L
 	}
 	out := make([]database.Location, len(list))
 	for i, loc := range list {
-		out[i] = locationRowToDatabase(loc.ID, loc.LocationCode, loc.Description, loc.Zone, loc.Type, loc.IsActive, loc.IsWayOut, loc.CreatedAt, loc.UpdatedAt, loc.TenantID)
+		out[i] = locationRowToDatabase(loc.ID, loc.LocationCode, loc.Description, loc.Zone, loc.Type, loc.IsActive, loc.IsWayOut, loc.CreatedAt, loc.UpdatedAt, loc.TenantID, loc.MaxUnits, loc.MaxWeightKg, loc.MaxVolumeM3, loc.Aisle, loc.Rack, loc.Level, loc.PickSequence)
 	}
 	return out, nil
 }
@@ -59,7 +59,7 @@ func (r *LocationsRepositorySQLC) GetLocationByID(tenantID, id string) (*databas
 			// Backward-compat fallback: caller may have passed a location_code.
 			loc2, err2 := r.queries.GetLocationByLocationCodeForTenant(ctx, sqlc.GetLocationByLocationCodeForTenantParams{LocationCode: id, TenantID: tid})
 			if err2 == nil {
-				l := locationRowToDatabase(loc2.ID, loc2.LocationCode, loc2.Description, loc2.Zone, loc2.Type, loc2.IsActive, loc2.IsWayOut, loc2.CreatedAt, loc2.UpdatedAt, loc2.TenantID)
+				l := locationRowToDatabase(loc2.ID, loc2.LocationCode, loc2.Description, loc2.Zone, loc2.Type, loc2.IsActive, loc2.IsWayOut, loc2.CreatedAt, loc2.UpdatedAt, loc2.TenantID, loc2.MaxUnits, loc2.MaxWeightKg, loc2.MaxVolumeM3, loc2.Aisle, loc2.Rack, loc2.Level, loc2.PickSequence)
 				return &l, nil
 			}
 			if errors.Is(err2, pgx.ErrNoRows) {
@@ -72,7 +72,7 @@ func (r *LocationsRepositorySQLC) GetLocationByID(tenantID, id string) (*databas
 		}
 		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la ubicación", Handled: false}
 	}
-	l := locationRowToDatabase(loc.ID, loc.LocationCode, loc.Description, loc.Zone, loc.Type, loc.IsActive, loc.IsWayOut, loc.CreatedAt, loc.UpdatedAt, loc.TenantID)
+	l := locationRowToDatabase(loc.ID, loc.LocationCode, loc.Description, loc.Zone, loc.Type, loc.IsActive, loc.IsWayOut, loc.CreatedAt, loc.UpdatedAt, loc.TenantID, loc.MaxUnits, loc.MaxWeightKg, loc.MaxVolumeM3, loc.Aisle, loc.Rack, loc.Level, loc.PickSequence)
 	return &l, nil
 }
 
@@ -100,6 +100,13 @@ func (r *LocationsRepositorySQLC) CreateLocation(tenantID string, input *request
 		IsActive:     true,
 		IsWayOut:     input.IsWayOut,
 		TenantID:     tid,
+		MaxUnits:     ptrFloatToPgNumeric(input.MaxUnits),
+		MaxWeightKg:  ptrFloatToPgNumeric(input.MaxWeightKg),
+		MaxVolumeM3:  ptrFloatToPgNumeric(input.MaxVolumeM3),
+		Aisle:        ptrStringToPgText(input.Aisle),
+		Rack:         ptrStringToPgText(input.Rack),
+		Level:        ptrStringToPgText(input.Level),
+		PickSequence: ptrIntToPgInt4(input.PickSequence),
 	}
 	_, err = r.queries.CreateLocation(ctx, arg)
 	if err != nil {
@@ -144,6 +151,15 @@ func (r *LocationsRepositorySQLC) UpdateLocation(tenantID, id string, data map[s
 	if v, ok := data["is_way_out"].(bool); ok {
 		loc.IsWayOut = v
 	}
+	// Capacity: a number sets the limit, an explicit null clears it (inherit from type).
+	mergeCapacityField(data, "max_units", &loc.MaxUnits)
+	mergeCapacityField(data, "max_weight_kg", &loc.MaxWeightKg)
+	mergeCapacityField(data, "max_volume_m3", &loc.MaxVolumeM3)
+	// Pick path: same rule — a value sets it, an explicit null clears it.
+	mergePickPathText(data, "aisle", &loc.Aisle)
+	mergePickPathText(data, "rack", &loc.Rack)
+	mergePickPathText(data, "level", &loc.Level)
+	mergePickSequence(data, &loc.PickSequence)
 	arg := sqlc.UpdateLocationForTenantParams{
 		ID:           loc.ID,
 		LocationCode: loc.LocationCode,
@@ -153,6 +169,13 @@ func (r *LocationsRepositorySQLC) UpdateLocation(tenantID, id string, data map[s
 		IsActive:     loc.IsActive,
 		IsWayOut:     loc.IsWayOut,
 		TenantID:     tid,
+		MaxUnits:     loc.MaxUnits,
+		MaxWeightKg:  loc.MaxWeightKg,
+		MaxVolumeM3:  loc.MaxVolumeM3,
+		Aisle:        loc.Aisle,
+		Rack:         loc.Rack,
+		Level:        loc.Level,
+		PickSequence: loc.PickSequence,
 	}
 	_, err = r.queries.UpdateLocationForTenant(ctx, arg)
 	if err != nil {
@@ -196,11 +219,15 @@ func (r *LocationsRepositorySQLC) ValidateImportRows(tenantID string, rows []req
 	return r.gorm.ValidateImportRows(tenantID, rows)
 }
 
+func (r *LocationsRepositorySQLC) GetLocationUtilization(tenantID string) ([]responses.LocationUtilization, *responses.InternalResponse) {
+	return r.gorm.GetLocationUtilization(tenantID)
+}
+
 func (r *LocationsRepositorySQLC) ExportLocationsToExcel(tenantID string) ([]byte, *responses.InternalResponse) {
 	return r.gorm.ExportLocationsToExcel(tenantID)
 }
 
-func locationRowToDatabase(id, locationCode string, description, zone pgtype.Text, locType string, isActive, isWayOut bool, createdAt, updatedAt pgtype.Timestamp, tenantID pgtype.UUID) database.Location {
+func locationRowToDatabase(id, locationCode string, description, zone pgtype.Text, locType string, isActive, isWayOut bool, createdAt, updatedAt pgtype.Timestamp, tenantID pgtype.UUID, maxUnits, maxWeightKg, maxVolumeM3 pgtype.Numeric, aisle, rack, level pgtype.Text, pickSequence pgtype.Int4) database.Location {
 	return database.Location{
 		ID:           id,
 		TenantID:     pgUUIDToString(tenantID),
@@ -212,6 +239,58 @@ func locationRowToDatabase(id, locationCode string, description, zone pgtype.Tex
 		IsWayOut:     isWayOut,
 		CreatedAt:    pgTimestampToTime(createdAt),
 		UpdatedAt:    pgTimestampToTime(updatedAt),
+		MaxUnits:     pgNumericToPtrFloat(maxUnits),
+		MaxWeightKg:  pgNumericToPtrFloat(maxWeightKg),
+		MaxVolumeM3:  pgNumericToPtrFloat(maxVolumeM3),
+		Aisle:        pgTextToPtrString(aisle),
+		Rack:         pgTextToPtrString(rack),
+		Level:        pgTextToPtrString(level),
+		PickSequence: pgInt4ToPtrInt(pickSequence),
+	}
+}
+
+// mergeCapacityField applies a capacity key from a JSON update map onto n.
+func mergeCapacityField(data map[string]interface{}, key string, n *pgtype.Numeric) {
+	v, present := data[key]
+	if !present {
+		return
+	}
+	switch f := v.(type) {
+	case nil:
+		*n = pgtype.Numeric{}
+	case float64:
+		*n = ptrFloatToPgNumeric(&f)
+	}
+}
+
+// mergePickPathText applies an aisle/rack/level key from a JSON update map onto t.
+func mergePickPathText(data map[string]interface{}, key string, t *pgtype.Text) {
+	v, present := data[key]
+	if !present {
+		return
+	}
+	switch s := v.(type) {
+	case nil:
+		*t = pgtype.Text{}
+	case string:
+		*t = ptrStringToPgText(&s)
+	}
+}
+
+// mergePickSequence applies pick_sequence from a JSON update map; negatives are ignored.
+func mergePickSequence(data map[string]interface{}, n *pgtype.Int4) {
+	v, present := data["pick_sequence"]
+	if !present {
+		return
+	}
+	switch f := v.(type) {
+	case nil:
+		*n = pgtype.Int4{}
+	case float64:
+		if f >= 0 {
+			i := int(f)
+			*n = ptrIntToPgInt4(&i)
+		}
 	}
 }
 
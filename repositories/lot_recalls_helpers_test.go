@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecallIsOpen(t *testing.T) {
+	assert.Nil(t, recallIsOpen(&database.LotRecall{Status: database.LotRecallOpen}))
+	for _, status := range []string{database.LotRecallClosed, database.LotRecallCancelled} {
+		resp := recallIsOpen(&database.LotRecall{RecallNumber: "RCL-2026-0001", Status: status})
+		require.NotNil(t, resp, status)
+		assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	}
+}
+
+func TestRecallTaskType(t *testing.T) {
+	assert.Equal(t, database.RecallTaskQuarantine, recallTaskType(nil, "A-01"))
+	assert.Equal(t, database.RecallTaskPickBack, recallTaskType(tools.StrPtr("RECALL-01"), "A-01"))
+	// Stock already at the return location is quarantined in place.
+	assert.Equal(t, database.RecallTaskQuarantine, recallTaskType(tools.StrPtr("RECALL-01"), "RECALL-01"))
+}
+
+func TestRecallCloseAndCancelConflicts(t *testing.T) {
+	recall := &database.LotRecall{RecallNumber: "RCL-2026-0001"}
+	pending := []database.LotRecallTask{{Status: database.RecallTaskCompleted}, {Status: database.RecallTaskPending}}
+	done := []database.LotRecallTask{{Status: database.RecallTaskCompleted}, {Status: database.RecallTaskCancelled}}
+
+	resp := recallCloseConflict(recall, pending)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Nil(t, recallCloseConflict(recall, done))
+	assert.Nil(t, recallCloseConflict(recall, nil))
+
+	resp = recallCancelConflict(recall, done)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Nil(t, recallCancelConflict(recall, []database.LotRecallTask{{Status: database.RecallTaskPending}}))
+}
+
+func TestRecallTaskQty(t *testing.T) {
+	task := &database.LotRecallTask{Location: "A-01", Qty: 10}
+
+	qty, resp := recallTaskQty(task, nil, 12)
+	require.Nil(t, resp)
+	assert.InDelta(t, 10, qty, 1e-9, "defaults to the task qty")
+
+	qty, resp = recallTaskQty(task, nil, 4)
+	require.Nil(t, resp)
+	assert.InDelta(t, 4, qty, 1e-9, "defaults to what is left at the location")
+
+	qty, resp = recallTaskQty(task, tools.Float64Ptr(0), 4)
+	require.Nil(t, resp)
+	assert.Zero(t, qty)
+
+	_, resp = recallTaskQty(task, tools.Float64Ptr(5), 4)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+}
+
+func TestGroupRecallAffected(t *testing.T) {
+	deliveries := []responses.RecallDelivery{
+		{DeliveryNoteID: "dn-1", SalesOrderID: "so-1", CustomerID: "c-1", CustomerName: tools.StrPtr("Farmacia Sol"), Qty: 5},
+		{DeliveryNoteID: "dn-2", SalesOrderID: "so-2", CustomerID: "c-2", Qty: 2, MixedLots: true},
+		{DeliveryNoteID: "dn-3", SalesOrderID: "so-1", CustomerID: "c-1", Qty: 3},
+	}
+	affected := groupRecallAffected(deliveries, nil)
+
+	assert.InDelta(t, 10, affected.ShippedQty, 1e-9)
+	require.Len(t, affected.Customers, 2)
+	assert.Equal(t, "c-1", affected.Customers[0].CustomerID)
+	assert.InDelta(t, 8, affected.Customers[0].Qty, 1e-9)
+	assert.Equal(t, 2, affected.Customers[0].DeliveryNotes)
+	assert.Equal(t, "Farmacia Sol", *affected.Customers[0].Name)
+	require.Len(t, affected.SalesOrders, 2)
+	assert.InDelta(t, 8, affected.SalesOrders[0].Qty, 1e-9)
+	assert.Len(t, affected.DeliveryNotes, 3)
+	assert.NotNil(t, affected.PickingTasks)
+}
+
+func TestGroupRecallAffected_Empty(t *testing.T) {
+	view := buildLotRecallView(database.LotRecall{ID: "r1"}, nil, groupRecallAffected(nil, nil))
+	assert.NotNil(t, view.Tasks)
+	assert.NotNil(t, view.Affected.Customers)
+	assert.NotNil(t, view.Affected.SalesOrders)
+	assert.NotNil(t, view.Affected.DeliveryNotes)
+	assert.NotNil(t, view.Affected.PickingTasks)
+	assert.Zero(t, view.Affected.ShippedQty)
+}
@@ -0,0 +1,755 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LotRecallsRepository implements ports.LotRecallsRepository using GORM.
+type LotRecallsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.LotRecallsRepository = (*LotRecallsRepository)(nil)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextRecallNumber generates "RCL-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextRecallNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("RCL-%d-", year)
+
+	lockKey := fmt.Sprintf("rcl-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire recall number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(recall_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM lot_recalls
+		WHERE tenant_id = $2
+		  AND recall_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate recall number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// recallIsOpen checks the recall still accepts changes (409 otherwise).
+func recallIsOpen(recall *database.LotRecall) *responses.InternalResponse {
+	if recall.Status == database.LotRecallOpen {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("El retiro %s ya no está abierto (%s)", recall.RecallNumber, recall.Status),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+	}
+}
+
+// recallTaskType picks the task for the lot's stock at location: pick it back to the return
+// location when the recall has one (and the stock is elsewhere), quarantine it in place otherwise.
+func recallTaskType(returnLocation *string, location string) string {
+	if returnLocation != nil && *returnLocation != location {
+		return database.RecallTaskPickBack
+	}
+	return database.RecallTaskQuarantine
+}
+
+// recallCloseConflict checks every task of the recall is done before closing it.
+func recallCloseConflict(recall *database.LotRecall, tasks []database.LotRecallTask) *responses.InternalResponse {
+	pending := 0
+	for _, t := range tasks {
+		if t.Status == database.RecallTaskPending {
+			pending++
+		}
+	}
+	if pending == 0 {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("El retiro %s tiene %d tarea(s) pendiente(s)", recall.RecallNumber, pending),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+	}
+}
+
+// recallCancelConflict refuses cancelling a recall once stock was retrieved.
+func recallCancelConflict(recall *database.LotRecall, tasks []database.LotRecallTask) *responses.InternalResponse {
+	for _, t := range tasks {
+		if t.Status == database.RecallTaskCompleted {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("El retiro %s ya tiene tareas completadas; ciérrelo en lugar de cancelarlo", recall.RecallNumber),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
+	return nil
+}
+
+// recallTaskQty resolves the quantity a task completes with: the requested qty, which cannot
+// exceed what the location still holds of the lot, or by default that stock up to the task qty.
+func recallTaskQty(task *database.LotRecallTask, requested *float64, lotQtyInLocation float64) (float64, *responses.InternalResponse) {
+	if requested == nil {
+		if lotQtyInLocation < task.Qty {
+			return lotQtyInLocation, nil
+		}
+		return task.Qty, nil
+	}
+	if *requested > lotQtyInLocation+qcQtyEpsilon {
+		return 0, &responses.InternalResponse{
+			Message:    fmt.Sprintf("%s solo tiene %.3f uds del lote (solicitado: %.3f)", task.Location, lotQtyInLocation, *requested),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+	return *requested, nil
+}
+
+// groupRecallAffected aggregates the deliveries of the lot per customer and sales order, in
+// order of first shipment.
+func groupRecallAffected(deliveries []responses.RecallDelivery, pickingTasks []responses.RecallPickingTask) responses.LotRecallAffected {
+	affected := responses.LotRecallAffected{
+		Customers:     []responses.RecallCustomer{},
+		SalesOrders:   []responses.RecallSalesOrder{},
+		DeliveryNotes: deliveries,
+		PickingTasks:  pickingTasks,
+	}
+	if affected.DeliveryNotes == nil {
+		affected.DeliveryNotes = []responses.RecallDelivery{}
+	}
+	if affected.PickingTasks == nil {
+		affected.PickingTasks = []responses.RecallPickingTask{}
+	}
+
+	customerIdx := make(map[string]int)
+	customerDNs := make(map[string]map[string]bool)
+	orderIdx := make(map[string]int)
+	for _, d := range deliveries {
+		affected.ShippedQty += d.Qty
+
+		i, ok := customerIdx[d.CustomerID]
+		if !ok {
+			i = len(affected.Customers)
+			customerIdx[d.CustomerID] = i
+			customerDNs[d.CustomerID] = make(map[string]bool)
+			affected.Customers = append(affected.Customers, responses.RecallCustomer{
+				CustomerID: d.CustomerID,
+				Code:       d.CustomerCode,
+				Name:       d.CustomerName,
+				Email:      d.CustomerEmail,
+				Phone:      d.CustomerPhone,
+			})
+		}
+		affected.Customers[i].Qty += d.Qty
+		if !customerDNs[d.CustomerID][d.DeliveryNoteID] {
+			customerDNs[d.CustomerID][d.DeliveryNoteID] = true
+			affected.Customers[i].DeliveryNotes++
+		}
+
+		j, ok := orderIdx[d.SalesOrderID]
+		if !ok {
+			j = len(affected.SalesOrders)
+			orderIdx[d.SalesOrderID] = j
+			affected.SalesOrders = append(affected.SalesOrders, responses.RecallSalesOrder{
+				SalesOrderID: d.SalesOrderID,
+				SONumber:     d.SONumber,
+				CustomerID:   d.CustomerID,
+			})
+		}
+		affected.SalesOrders[j].Qty += d.Qty
+	}
+	return affected
+}
+
+// buildLotRecallView assembles the recall view.
+func buildLotRecallView(recall database.LotRecall, tasks []database.LotRecallTask, affected responses.LotRecallAffected) *responses.LotRecallView {
+	if tasks == nil {
+		tasks = []database.LotRecallTask{}
+	}
+	return &responses.LotRecallView{LotRecall: recall, Tasks: tasks, Affected: affected}
+}
+
+// lockLotRecall loads a tenant's recall FOR UPDATE.
+func lockLotRecall(tx *gorm.DB, id, tenantID string) (*database.LotRecall, *responses.InternalResponse, error) {
+	var recall database.LotRecall
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&recall).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &responses.InternalResponse{Message: "Retiro no encontrado", Handled: true, StatusCode: responses.StatusNotFound}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("load lot recall: %w", err)
+	}
+	return &recall, nil, nil
+}
+
+// loadRecallTasks returns the tasks of a recall by location.
+func loadRecallTasks(tx *gorm.DB, recallID string) ([]database.LotRecallTask, error) {
+	var tasks []database.LotRecallTask
+	if err := tx.Where("recall_id = ?", recallID).Order("location ASC, id ASC").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("load recall tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// loadRecallDeliveries returns the delivery note lines that shipped the lot, oldest first.
+func loadRecallDeliveries(tx *gorm.DB, tenantID, sku, lotNumber string) ([]responses.RecallDelivery, error) {
+	var rows []responses.RecallDelivery
+	if err := tx.Raw(`
+		SELECT dn.id AS delivery_note_id, dn.dn_number, dn.sales_order_id, so.so_number,
+		       dn.customer_id, c.code AS customer_code, c.name AS customer_name,
+		       c.email AS customer_email, c.phone AS customer_phone,
+		       SUM(dni.qty) AS qty,
+		       BOOL_OR(COALESCE(array_length(dni.lot_numbers, 1), 0) > 1) AS mixed_lots,
+		       dn.created_at AS shipped_at, dn.delivered_at
+		  FROM delivery_note_items dni
+		  JOIN delivery_notes dn ON dn.id = dni.delivery_note_id
+		  LEFT JOIN sales_orders so ON so.id = dn.sales_order_id
+		  LEFT JOIN clients c ON c.id = dn.customer_id
+		 WHERE dn.tenant_id = ? AND dni.article_sku = ? AND ? = ANY(dni.lot_numbers)
+		 GROUP BY dn.id, so.so_number, c.code, c.name, c.email, c.phone
+		 ORDER BY dn.created_at ASC, dn.dn_number ASC
+	`, tenantID, sku, lotNumber).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load deliveries of lot %s: %w", lotNumber, err)
+	}
+	return rows, nil
+}
+
+// loadRecallPickingTasks returns the open picking tasks that list or allocate the lot.
+func loadRecallPickingTasks(tx *gorm.DB, tenantID, sku, lotNumber string) ([]responses.RecallPickingTask, error) {
+	var rows []responses.RecallPickingTask
+	if err := tx.Raw(`
+		SELECT pt.id, pt.task_id, pt.status, pt.sales_order_id, so.so_number
+		  FROM picking_tasks pt
+		  LEFT JOIN sales_orders so ON so.id = pt.sales_order_id
+		 WHERE pt.tenant_id = ?
+		   AND pt.status NOT IN ('completed', 'completed_with_differences', 'cancelled')
+		   AND EXISTS (
+		       SELECT 1 FROM jsonb_array_elements(COALESCE(pt.items, '[]'::jsonb)) it
+		        WHERE it->>'sku' = ?
+		          AND (EXISTS (SELECT 1 FROM jsonb_array_elements(COALESCE(it->'lots', '[]'::jsonb)) ln
+		                        WHERE ln->>'lot_number' = ?)
+		               OR EXISTS (SELECT 1 FROM jsonb_array_elements(COALESCE(it->'allocations', '[]'::jsonb)) al
+		                           WHERE al->>'lot_number' = ?)))
+		 ORDER BY pt.created_at ASC
+	`, tenantID, sku, lotNumber, lotNumber).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load picking tasks of lot %s: %w", lotNumber, err)
+	}
+	return rows, nil
+}
+
+// lotQtyInInventory returns the quantity of the lot in one inventory row.
+func lotQtyInInventory(tx *gorm.DB, inventoryID, lotID string) (float64, error) {
+	var qty float64
+	if err := tx.Raw(`SELECT COALESCE(SUM(quantity), 0) FROM inventory_lots WHERE inventory_id = ? AND lot_id = ?`,
+		inventoryID, lotID).Scan(&qty).Error; err != nil {
+		return 0, fmt.Errorf("read lot qty: %w", err)
+	}
+	return qty, nil
+}
+
+// pickBackRecalledStock moves qty of the recalled lot from the task's inventory row to its
+// target location with a recall_out / recall_in movement pair. Reserved and held units of the
+// source row (other lots) must stay covered and the target must have capacity for qty.
+func pickBackRecalledStock(tx *gorm.DB, recall *database.LotRecall, task *database.LotRecallTask, qty float64, userID string) (*responses.InternalResponse, error) {
+	var from database.Inventory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", task.InventoryID).First(&from).Error; err != nil {
+		return nil, fmt.Errorf("load inventory %s @ %s: %w", recall.SKU, task.Location, err)
+	}
+	fromAfter := from.Quantity - qty
+	if fromAfter < from.ReservedQty+from.HeldQty-qcQtyEpsilon {
+		return &responses.InternalResponse{
+			Message: fmt.Sprintf("No puede retirar %.3f de %s en %s: hay %.3f reservadas y %.3f retenidas de otros lotes",
+				qty, recall.SKU, task.Location, from.ReservedQty, from.HeldQty),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}, nil
+	}
+	// The target must have room for the lot before anything moves.
+	if *task.TargetLocation != task.Location {
+		exceeded, err := tools.CheckLocationCapacity(tx, recall.TenantID, *task.TargetLocation, []tools.CapacityAddition{{SKU: recall.SKU, Quantity: qty}})
+		if err != nil {
+			return nil, err
+		}
+		if exceeded != nil {
+			return tools.CapacityExceededResponse(exceeded), nil
+		}
+	}
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", from.ID).
+		Updates(map[string]interface{}{"quantity": fromAfter, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, fmt.Errorf("update inventory %s @ %s: %w", recall.SKU, task.Location, err)
+	}
+	if err := tx.Exec(`UPDATE inventory_lots SET quantity = GREATEST(0, quantity - ?) WHERE inventory_id = ? AND lot_id = ?`,
+		qty, from.ID, recall.LotID).Error; err != nil {
+		return nil, fmt.Errorf("update inventory lot @ %s: %w", task.Location, err)
+	}
+
+	target := *task.TargetLocation
+	var to database.Inventory
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND location = ?", recall.TenantID, recall.SKU, target).First(&to).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		invID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, fmt.Errorf("generate inventory id: %w", err)
+		}
+		to = database.Inventory{
+			ID:           invID,
+			TenantID:     recall.TenantID,
+			SKU:          recall.SKU,
+			Name:         from.Name,
+			Description:  from.Description,
+			Location:     target,
+			Status:       "available",
+			Presentation: from.Presentation,
+			UnitPrice:    from.UnitPrice,
+			CreatedAt:    tools.GetCurrentTime(),
+			UpdatedAt:    tools.GetCurrentTime(),
+		}
+		if err := tx.Create(&to).Error; err != nil {
+			return nil, fmt.Errorf("create inventory %s @ %s: %w", recall.SKU, target, err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("find inventory %s @ %s: %w", recall.SKU, target, err)
+	}
+	toBefore := to.Quantity
+	toAfter := to.Quantity + qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", to.ID).
+		Updates(map[string]interface{}{"quantity": toAfter, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, fmt.Errorf("update inventory %s @ %s: %w", recall.SKU, target, err)
+	}
+	res := tx.Exec(`
+		UPDATE inventory_lots SET quantity = quantity + ?
+		 WHERE tenant_id = ? AND inventory_id = ? AND lot_id = ? AND location = ?
+	`, qty, recall.TenantID, to.ID, recall.LotID, target)
+	if res.Error != nil {
+		return nil, fmt.Errorf("update inventory lot @ %s: %w", target, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		invLotID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, fmt.Errorf("generate inventory_lot id: %w", err)
+		}
+		if err := tx.Create(&database.InventoryLot{
+			ID:          invLotID,
+			TenantID:    recall.TenantID,
+			InventoryID: to.ID,
+			LotID:       recall.LotID,
+			Quantity:    qty,
+			Location:    target,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("create inventory lot @ %s: %w", target, err)
+		}
+	}
+
+	refType := "lot_recall"
+	reason := tools.StrPtr("lot recall " + recall.RecallNumber)
+	fromBefore := from.Quantity
+	for _, m := range []struct {
+		location, movementType string
+		qty                    float64
+		before, after          *float64
+		unitCost               *float64
+	}{
+		{task.Location, database.MovementRecallOut, -qty, &fromBefore, &fromAfter, from.UnitPrice},
+		{target, database.MovementRecallIn, qty, &toBefore, &toAfter, from.UnitPrice},
+	} {
+		movID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return nil, fmt.Errorf("generate recall movement id: %w", err)
+		}
+		if err := tx.Create(&database.InventoryMovement{
+			ID:             movID,
+			SKU:            recall.SKU,
+			Location:       m.location,
+			MovementType:   m.movementType,
+			Quantity:       m.qty,
+			RemainingStock: *m.after,
+			Reason:         reason,
+			CreatedBy:      userID,
+			CreatedAt:      tools.GetCurrentTime(),
+			ReferenceType:  &refType,
+			ReferenceID:    &recall.ID,
+			LotID:          &recall.LotID,
+			UnitCost:       m.unitCost,
+			BeforeQty:      m.before,
+			AfterQty:       m.after,
+			UserID:         &userID,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("create %s movement: %w", m.movementType, err)
+		}
+	}
+	return nil, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *LotRecallsRepository) ListRecalls(tenantID string, status, sku *string, limit, offset int) ([]database.LotRecall, *responses.InternalResponse) {
+	query := r.DB.Model(&database.LotRecall{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+	if sku != nil && *sku != "" {
+		query = query.Where("sku = ?", *sku)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var recalls []database.LotRecall
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&recalls).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los retiros de lote"}
+	}
+	return recalls, nil
+}
+
+func (r *LotRecallsRepository) GetRecall(id, tenantID string) (*responses.LotRecallView, *responses.InternalResponse) {
+	var recall database.LotRecall
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&recall).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Retiro no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el retiro"}
+	}
+
+	tasks, err := loadRecallTasks(r.DB, recall.ID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las tareas del retiro"}
+	}
+	deliveries, err := loadRecallDeliveries(r.DB, tenantID, recall.SKU, recall.LotNumber)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las entregas del lote"}
+	}
+	var pickingTasks []responses.RecallPickingTask
+	if recall.Status == database.LotRecallOpen {
+		if pickingTasks, err = loadRecallPickingTasks(r.DB, tenantID, recall.SKU, recall.LotNumber); err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener los pickings del lote"}
+		}
+	}
+	view := buildLotRecallView(recall, tasks, groupRecallAffected(deliveries, pickingTasks))
+
+	var header struct {
+		ArticleName    *string    `gorm:"column:article_name"`
+		ExpirationDate *time.Time `gorm:"column:expiration_date"`
+	}
+	if err := r.DB.Raw(`
+		SELECT a.name AS article_name, l.expiration_date
+		  FROM lots l
+		  LEFT JOIN articles a ON a.sku = l.sku AND a.tenant_id = l.tenant_id
+		 WHERE l.id = ?
+	`, recall.LotID).Scan(&header).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el retiro"}
+	}
+	view.ArticleName = header.ArticleName
+	view.ExpirationDate = header.ExpirationDate
+	return view, nil
+}
+
+func (r *LotRecallsRepository) CreateRecall(tenantID, userID string, req *requests.CreateLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	var recallID string
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var lot database.Lot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", req.LotID, tenantID).First(&lot).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{Message: "Lote no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return fmt.Errorf("load lot: %w", err)
+		}
+		if lot.Status != nil && *lot.Status == database.LotStatusRecalled {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("El lote %s ya está en retiro de mercado", lot.LotNumber),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+		if req.ReturnLocation != nil {
+			ok, err := returnLocationExists(tx, tenantID, *req.ReturnLocation)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				*handledResp = responses.InternalResponse{
+					Message:    fmt.Sprintf("La ubicación de retorno %s no existe o está inactiva", *req.ReturnLocation),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}
+				return nil
+			}
+		}
+
+		if err := tx.Model(&database.Lot{}).Where("id = ?", lot.ID).
+			Updates(map[string]interface{}{"status": database.LotStatusRecalled, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+			return fmt.Errorf("freeze recalled lot: %w", err)
+		}
+
+		number, err := nextRecallNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		recallID, err = tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate recall id: %w", err)
+		}
+		recall := &database.LotRecall{
+			ID:                recallID,
+			TenantID:          tenantID,
+			RecallNumber:      number,
+			LotID:             lot.ID,
+			SKU:               lot.SKU,
+			LotNumber:         lot.LotNumber,
+			Status:            database.LotRecallOpen,
+			Classification:    req.Classification,
+			Reason:            req.Reason,
+			ReturnLocation:    req.ReturnLocation,
+			PreviousLotStatus: lot.Status,
+			Notes:             req.Notes,
+		}
+		if userID != "" {
+			recall.CreatedBy = &userID
+		}
+		if err := tx.Create(recall).Error; err != nil {
+			return fmt.Errorf("create lot recall: %w", err)
+		}
+
+		var stock []struct {
+			InventoryID string
+			Location    string
+			Qty         float64
+		}
+		if err := tx.Raw(`
+			SELECT inventory_id, location, SUM(quantity) AS qty
+			  FROM inventory_lots
+			 WHERE tenant_id = ? AND lot_id = ?
+			 GROUP BY inventory_id, location
+			HAVING SUM(quantity) > 0
+			 ORDER BY location
+		`, tenantID, lot.ID).Scan(&stock).Error; err != nil {
+			return fmt.Errorf("load stock of lot %s: %w", lot.LotNumber, err)
+		}
+		for _, s := range stock {
+			taskID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate recall task id: %w", err)
+			}
+			task := &database.LotRecallTask{
+				ID:          taskID,
+				RecallID:    recallID,
+				TaskType:    recallTaskType(req.ReturnLocation, s.Location),
+				InventoryID: s.InventoryID,
+				Location:    s.Location,
+				Qty:         s.Qty,
+				Status:      database.RecallTaskPending,
+			}
+			if task.TaskType == database.RecallTaskPickBack {
+				task.TargetLocation = req.ReturnLocation
+			}
+			if err := tx.Create(task).Error; err != nil {
+				return fmt.Errorf("create recall task @ %s: %w", s.Location, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear el retiro de lote"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetRecall(recallID, tenantID)
+}
+
+func (r *LotRecallsRepository) CompleteTask(id, tenantID, taskID, userID string, req *requests.CompleteRecallTaskRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		recall, resp, err := lockLotRecall(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = recallIsOpen(recall)
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		var task database.LotRecallTask
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND recall_id = ?", taskID, recall.ID).First(&task).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{Message: "Tarea de retiro no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return fmt.Errorf("load recall task: %w", err)
+		}
+		if task.Status != database.RecallTaskPending {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("La tarea de %s ya está %s", task.Location, task.Status),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		lotQty, err := lotQtyInInventory(tx, task.InventoryID, recall.LotID)
+		if err != nil {
+			return err
+		}
+		qty, resp := recallTaskQty(&task, req.Qty, lotQty)
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		if task.TaskType == database.RecallTaskPickBack && qty > 0 {
+			resp, err := pickBackRecalledStock(tx, recall, &task, qty, userID)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{
+			"status":        database.RecallTaskCompleted,
+			"completed_qty": qty,
+			"completed_at":  now,
+		}
+		if userID != "" {
+			updates["completed_by"] = userID
+		}
+		if req.Notes != nil {
+			updates["notes"] = *req.Notes
+		}
+		if err := tx.Model(&database.LotRecallTask{}).Where("id = ?", task.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("complete recall task: %w", err)
+		}
+		return tx.Model(&database.LotRecall{}).Where("id = ?", recall.ID).Update("updated_at", now).Error
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al completar la tarea de retiro"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetRecall(id, tenantID)
+}
+
+func (r *LotRecallsRepository) CloseRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	return r.endRecall(id, tenantID, userID, database.LotRecallClosed, req)
+}
+
+func (r *LotRecallsRepository) CancelRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	return r.endRecall(id, tenantID, userID, database.LotRecallCancelled, req)
+}
+
+// endRecall closes (lot stays recalled) or cancels (pending tasks cancelled, lot back to its
+// previous status) an open recall.
+func (r *LotRecallsRepository) endRecall(id, tenantID, userID, target string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		recall, resp, err := lockLotRecall(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = recallIsOpen(recall)
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		tasks, err := loadRecallTasks(tx, recall.ID)
+		if err != nil {
+			return err
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{"status": target, "updated_at": now}
+		if req != nil && req.Notes != nil {
+			updates["notes"] = gorm.Expr("COALESCE(notes || E'\\n', '') || ?", *req.Notes)
+		}
+		if target == database.LotRecallClosed {
+			if resp := recallCloseConflict(recall, tasks); resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			updates["closed_at"] = now
+			if userID != "" {
+				updates["closed_by"] = userID
+			}
+		} else {
+			if resp := recallCancelConflict(recall, tasks); resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			updates["cancelled_at"] = now
+			if err := tx.Model(&database.LotRecallTask{}).
+				Where("recall_id = ? AND status = ?", recall.ID, database.RecallTaskPending).
+				Update("status", database.RecallTaskCancelled).Error; err != nil {
+				return fmt.Errorf("cancel recall tasks: %w", err)
+			}
+			if err := tx.Model(&database.Lot{}).
+				Where("id = ? AND status = ?", recall.LotID, database.LotStatusRecalled).
+				Updates(map[string]interface{}{"status": recall.PreviousLotStatus, "updated_at": now}).Error; err != nil {
+				return fmt.Errorf("restore recalled lot: %w", err)
+			}
+		}
+
+		if err := tx.Model(&database.LotRecall{}).Where("id = ?", recall.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update lot recall: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al actualizar el retiro de lote"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetRecall(id, tenantID)
+}
+
+func (r *LotRecallsRepository) TenantAdminIDs(tenantID string) ([]string, *responses.InternalResponse) {
+	var adminIDs []string
+	if err := r.DB.Table("users").
+		Joins("JOIN roles ON users.role_id = roles.id").
+		Where("users.tenant_id = ? AND LOWER(roles.name) = 'admin' AND users.is_active = true AND users.deleted_at IS NULL", tenantID).
+		Pluck("users.id", &adminIDs).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al buscar los administradores del tenant"}
+	}
+	return adminIDs, nil
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesCandidates(t *testing.T) {
+	pattern, err := tools.ParseNumberPattern("{SKU}-{YY}-{###}")
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []string{"ABC-26-008", "ABC-26-009", "ABC-26-010"}, seriesCandidates(pattern, now, "ABC", 7, 3))
+	assert.Empty(t, seriesCandidates(pattern, now, "ABC", 7, 0))
+}
+
+func TestReceivingLineQty(t *testing.T) {
+	line := requests.ReceivingTaskItemRequest{ExpectedQuantity: 10}
+	accepted, received := 6.0, 8
+
+	assert.Equal(t, 6, receivingLineQty(requests.ReceivingTaskItemRequest{AcceptedQty: &accepted, ReceivedQuantity: &received}, line))
+	assert.Equal(t, 8, receivingLineQty(requests.ReceivingTaskItemRequest{ReceivedQuantity: &received}, line))
+	assert.Equal(t, 4, receivingLineQty(requests.ReceivingTaskItemRequest{ExpectedQuantity: 4}, line))
+	assert.Equal(t, 10, receivingLineQty(requests.ReceivingTaskItemRequest{}, line))
+}
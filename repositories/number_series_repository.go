@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// NumberSeriesRepository previews the lot and serial numbers the tenant series will hand out.
+// Receiving takes the numbers through nextSeriesNumbers inside its own transaction.
+type NumberSeriesRepository struct {
+	DB *gorm.DB
+}
+
+// Where the pattern of a series came from.
+const (
+	seriesSourceRequest = "request"
+	seriesSourceArticle = "article"
+	seriesSourceTenant  = "tenant"
+	seriesSourceDefault = "default"
+)
+
+// maxSeriesRounds bounds how many times a series skips numbers already taken by hand before
+// giving up.
+const maxSeriesRounds = 10
+
+// resolveSeriesPattern returns the pattern for kind that applies to article (nil when no
+// article): its own series, else the tenant pattern, else the built-in default.
+func resolveSeriesPattern(tx *gorm.DB, tenantID, kind string, article *database.Article) (*tools.NumberPattern, string, error) {
+	var settings struct {
+		LotNumberPattern    *string `gorm:"column:lot_number_pattern"`
+		SerialNumberPattern *string `gorm:"column:serial_number_pattern"`
+	}
+	if err := tx.Raw(`SELECT lot_number_pattern, serial_number_pattern FROM stock_settings WHERE tenant_id = ?`, tenantID).
+		Scan(&settings).Error; err != nil {
+		return nil, "", fmt.Errorf("load number series settings: %w", err)
+	}
+
+	tenantPattern, fallback := settings.LotNumberPattern, database.DefaultLotNumberPattern
+	var articleSeries *string
+	if kind == database.NumberSeriesSerial {
+		tenantPattern, fallback = settings.SerialNumberPattern, database.DefaultSerialNumberPattern
+	}
+	if article != nil {
+		articleSeries = article.BatchNumberSeries
+		if kind == database.NumberSeriesSerial {
+			articleSeries = article.SerialNumberSeries
+		}
+	}
+
+	raw, source := fallback, seriesSourceDefault
+	if s := tools.ResolveNumberPattern(articleSeries, nil, ""); s != "" {
+		raw, source = s, seriesSourceArticle
+	} else if s := tools.ResolveNumberPattern(tenantPattern, nil, ""); s != "" {
+		raw, source = s, seriesSourceTenant
+	}
+	pattern, err := tools.ParseNumberPattern(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	return pattern, source, nil
+}
+
+// takenSeriesNumbers returns which candidates already exist: lot numbers of the SKU, or
+// serial numbers of the tenant.
+func takenSeriesNumbers(tx *gorm.DB, tenantID, kind, sku string, candidates []string) (map[string]bool, error) {
+	taken := make(map[string]bool)
+	if len(candidates) == 0 {
+		return taken, nil
+	}
+	var existing []string
+	q := tx.Model(&database.Serial{}).Where("tenant_id = ? AND serial_number IN ?", tenantID, candidates)
+	column := "serial_number"
+	if kind == database.NumberSeriesLot {
+		q = tx.Model(&database.Lot{}).Where("tenant_id = ? AND sku = ? AND lot_number IN ?", tenantID, sku, candidates)
+		column = "lot_number"
+	}
+	if err := q.Pluck(column, &existing).Error; err != nil {
+		return nil, fmt.Errorf("check taken %s numbers: %w", kind, err)
+	}
+	for _, n := range existing {
+		taken[n] = true
+	}
+	return taken, nil
+}
+
+// seriesCandidates formats the numbers from+1 … from+count of a series.
+func seriesCandidates(pattern *tools.NumberPattern, now time.Time, sku string, from int64, count int) []string {
+	out := make([]string, count)
+	for i := range out {
+		out[i] = pattern.Format(now, sku, from+int64(i)+1)
+	}
+	return out
+}
+
+// nextSeriesNumbers hands out n new numbers of kind for article, advancing the tenant counter
+// inside tx. The UPSERT locks the counter row until tx ends, so concurrent receipts on the
+// same series wait for each other instead of taking the same numbers. Numbers that already
+// exist (typed in by hand) are skipped.
+func nextSeriesNumbers(tx *gorm.DB, tenantID, kind string, article *database.Article, n int) ([]string, error) {
+	pattern, _, err := resolveSeriesPattern(tx, tenantID, kind, article)
+	if err != nil {
+		return nil, err
+	}
+	now := tools.GetCurrentTime()
+	key := pattern.Key(now, article.SKU)
+
+	out := make([]string, 0, n)
+	for round := 0; len(out) < n; round++ {
+		if round == maxSeriesRounds {
+			return nil, fmt.Errorf("series %s: too many numbers already taken", key)
+		}
+		need := n - len(out)
+		var last int64
+		if err := tx.Raw(`
+			INSERT INTO number_series_counters (tenant_id, kind, series_key, last_value, updated_at)
+			VALUES (?, ?, ?, ?, NOW())
+			ON CONFLICT (tenant_id, kind, series_key) DO UPDATE SET
+				last_value = number_series_counters.last_value + EXCLUDED.last_value,
+				updated_at = NOW()
+			RETURNING last_value
+		`, tenantID, kind, key, need).Scan(&last).Error; err != nil {
+			return nil, fmt.Errorf("advance series %s: %w", key, err)
+		}
+		candidates := seriesCandidates(pattern, now, article.SKU, last-int64(need), need)
+		taken, err := takenSeriesNumbers(tx, tenantID, kind, article.SKU, candidates)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range candidates {
+			if !taken[c] {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Preview returns the next numbers of a series without taking them. A pattern in the
+// request is tried as is (to check it before saving it in the settings or the article).
+func (r *NumberSeriesRepository) Preview(tenantID string, req *requests.NumberSeriesPreviewRequest) (*responses.NumberSeriesPreview, *responses.InternalResponse) {
+	var article *database.Article
+	if req.SKU != "" {
+		var a database.Article
+		if err := r.DB.Where("tenant_id = ? AND sku = ?", tenantID, req.SKU).First(&a).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &responses.InternalResponse{Message: "Artículo no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+			}
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el artículo"}
+		}
+		article = &a
+	}
+
+	var pattern *tools.NumberPattern
+	var source string
+	var err error
+	if req.Pattern != "" {
+		pattern, err = tools.ParseNumberPattern(req.Pattern)
+		source = seriesSourceRequest
+	} else {
+		pattern, source, err = resolveSeriesPattern(r.DB, tenantID, req.Kind, article)
+	}
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: err.Error(), Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+
+	now := tools.GetCurrentTime()
+	key := pattern.Key(now, req.SKU)
+	var counters []int64
+	if err := r.DB.Table("number_series_counters").
+		Where("tenant_id = ? AND kind = ? AND series_key = ?", tenantID, req.Kind, key).
+		Pluck("last_value", &counters).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el contador de la serie"}
+	}
+	var last int64
+	if len(counters) > 0 {
+		last = counters[0]
+	}
+
+	preview := &responses.NumberSeriesPreview{
+		Kind:      req.Kind,
+		SKU:       req.SKU,
+		Pattern:   pattern.String(),
+		Source:    source,
+		SeriesKey: key,
+		LastValue: last,
+		Next:      make([]string, 0, req.Count),
+	}
+	from := last
+	for round := 0; len(preview.Next) < req.Count && round < maxSeriesRounds; round++ {
+		need := req.Count - len(preview.Next)
+		candidates := seriesCandidates(pattern, now, req.SKU, from, need)
+		taken, err := takenSeriesNumbers(r.DB, tenantID, req.Kind, req.SKU, candidates)
+		if err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al calcular los próximos números"}
+		}
+		for _, c := range candidates {
+			if !taken[c] {
+				preview.Next = append(preview.Next, c)
+			}
+		}
+		from += int64(need)
+	}
+	return preview, nil
+}
+
+// generateReceivingNumbers fills a receiving line flagged generate_numbers with qty units of
+// a tracked article: one generated lot holding them, or one generated serial per unit.
+// Numbers captured by the operator are kept; nothing is generated then.
+func generateReceivingNumbers(tx *gorm.DB, tenantID string, article *database.Article, item *requests.ReceivingTaskItemRequest, qty int) error {
+	if !item.GenerateNumbers || qty <= 0 {
+		return nil
+	}
+	if article.TrackByLot && len(item.LotNumbers) == 0 {
+		numbers, err := nextSeriesNumbers(tx, tenantID, database.NumberSeriesLot, article, 1)
+		if err != nil {
+			return err
+		}
+		item.LotNumbers = []requests.CreateLotRequest{{LotNumber: numbers[0], SKU: article.SKU, Quantity: float64(qty)}}
+	}
+	if article.TrackBySerial && len(item.SerialNumbers) == 0 {
+		numbers, err := nextSeriesNumbers(tx, tenantID, database.NumberSeriesSerial, article, qty)
+		if err != nil {
+			return err
+		}
+		for _, n := range numbers {
+			item.SerialNumbers = append(item.SerialNumbers, database.Serial{SerialNumber: n, SKU: article.SKU})
+		}
+	}
+	return nil
+}
+
+// createGeneratedLot creates the lot generated for a line completed with the whole task,
+// pending like the lots declared when the task is created; completing the task activates it.
+func createGeneratedLot(tx *gorm.DB, tenantID string, article *database.Article, lot requests.CreateLotRequest) error {
+	id, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return fmt.Errorf("generate lot id for %s/%s: %w", article.SKU, lot.LotNumber, err)
+	}
+	now := tools.GetCurrentTime()
+	row := database.Lot{
+		ID:        id,
+		TenantID:  tenantID,
+		LotNumber: lot.LotNumber,
+		SKU:       article.SKU,
+		Quantity:  lot.Quantity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if article.ShelfLifeInDays != nil && *article.ShelfLifeInDays > 0 {
+		exp := now.AddDate(0, 0, *article.ShelfLifeInDays)
+		row.ExpirationDate = &exp
+	}
+	if err := tx.Create(&row).Error; err != nil {
+		return fmt.Errorf("create lot %s/%s: %w", article.SKU, lot.LotNumber, err)
+	}
+	return nil
+}
+
+// receivingLineQty is the quantity a line completed on its own enters with: the accepted
+// quantity, else the received count, else the quantity of the request (the task line's
+// expected quantity when the request has none).
+func receivingLineQty(item, line requests.ReceivingTaskItemRequest) int {
+	switch {
+	case item.AcceptedQty != nil:
+		return int(*item.AcceptedQty)
+	case item.ReceivedQuantity != nil:
+		return *item.ReceivedQuantity
+	case item.ExpectedQuantity > 0:
+		return item.ExpectedQuantity
+	default:
+		return line.ExpectedQuantity
+	}
+}
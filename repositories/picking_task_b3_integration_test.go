@@ -34,9 +34,9 @@ func seedInventory(t *testing.T, db *gorm.DB, sku, location string, qty, reserve
 	id, err := tools.GenerateNanoid(db)
 	require.NoError(t, err)
 	require.NoError(t, db.Exec(`
-		INSERT INTO inventory (id, sku, location, quantity, reserved_qty, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, 'active', NOW(), NOW())`,
-		id, sku, location, qty, reserved).Error)
+		INSERT INTO inventory (id, tenant_id, sku, location, quantity, reserved_qty, status, created_at, updated_at)
+		VALUES (?, (SELECT tenant_id FROM articles WHERE sku = ?), ?, ?, ?, ?, 'active', NOW(), NOW())`,
+		id, sku, sku, location, qty, reserved).Error)
 	return id
 }
 
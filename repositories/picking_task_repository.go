@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -70,30 +71,10 @@ func isValidPickingTransition(current, next string) bool {
 // parsePickingItemsWithLegacyFallback accepts both the new format (items with
 // allocations) and the old format (item with a single "location" string but no
 // allocations). Legacy items get a synthetic single-allocation so the rest of
-// the code can treat all items uniformly.
+// the code can treat all items uniformly. The parsing itself lives in
+// requests.ParsePickingTaskItems so services (picking waves) share it.
 func parsePickingItemsWithLegacyFallback(raw json.RawMessage) ([]requests.PickingTaskItemRequest, error) {
-	var items []requests.PickingTaskItemRequest
-	if err := json.Unmarshal(raw, &items); err != nil {
-		return nil, err
-	}
-
-	// Second pass: check for legacy "location" field (items with no allocations).
-	var legacyPayload []map[string]interface{}
-	if err := json.Unmarshal(raw, &legacyPayload); err != nil {
-		// If this fails the first unmarshal was sufficient.
-		return items, nil
-	}
-
-	for i := range items {
-		if len(items[i].Allocations) == 0 && i < len(legacyPayload) {
-			if location, ok := legacyPayload[i]["location"].(string); ok && location != "" {
-				items[i].Allocations = []database.LocationAllocation{
-					{Location: location, Quantity: items[i].ExpectedQuantity},
-				}
-			}
-		}
-	}
-	return items, nil
+	return requests.ParsePickingTaskItems(raw)
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -102,7 +83,7 @@ func parsePickingItemsWithLegacyFallback(raw json.RawMessage) ([]requests.Pickin
 
 // applyReservations increments reserved_qty for every allocation within tx.
 // Uses a conditional UPDATE so it fails fast (RowsAffected == 0) when there
-// is not enough available stock, without ever leaving inventory in a bad state.
+// is not enough available stock (net of QC holds), without ever leaving inventory in a bad state.
 // The caller must propagate a sentinel error to trigger rollback.
 func (r *PickingTaskRepository) applyReservations(tx *gorm.DB, items []requests.PickingTaskItemRequest) *responses.InternalResponse {
 	for _, item := range items {
@@ -112,7 +93,7 @@ func (r *PickingTaskRepository) applyReservations(tx *gorm.DB, items []requests.
 				   SET reserved_qty = reserved_qty + ?,
 				       updated_at   = NOW()
 				 WHERE sku = ? AND location = ?
-				   AND (quantity - reserved_qty) >= ?
+				   AND `+tools.AvailableQtySQL("inventory")+` >= ?
 			`, alloc.Quantity, item.SKU, alloc.Location, alloc.Quantity)
 
 			if result.Error != nil {
@@ -199,6 +180,143 @@ func (r *PickingTaskRepository) validateNoExpiredLots(items []requests.PickingTa
 	return nil
 }
 
+// validateNoHeldLots rejects items referencing lots on QC hold (quarantined, rejected or, for
+// tenants requiring lot release, not released yet): held stock cannot be reserved or shipped.
+func validateNoHeldLots(tx *gorm.DB, tenantID string, items []requests.PickingTaskItemRequest) (*responses.InternalResponse, error) {
+	lotsBySKU := make(map[string][]string)
+	var skus []string
+	for _, item := range items {
+		seen := make(map[string]bool)
+		add := func(lot string) {
+			if lot == "" || seen[lot] {
+				return
+			}
+			seen[lot] = true
+			if _, ok := lotsBySKU[item.SKU]; !ok {
+				skus = append(skus, item.SKU)
+			}
+			lotsBySKU[item.SKU] = append(lotsBySKU[item.SKU], lot)
+		}
+		for _, lot := range item.LotNumbers {
+			add(lot.LotNumber)
+		}
+		for _, alloc := range item.Allocations {
+			if alloc.LotNumber != nil {
+				add(*alloc.LotNumber)
+			}
+		}
+	}
+
+	for _, sku := range skus {
+		held, err := tools.HeldLots(tx, tenantID, sku, lotsBySKU[sku])
+		if err != nil {
+			return nil, err
+		}
+		if len(held) > 0 {
+			return &responses.InternalResponse{
+				Message: fmt.Sprintf(
+					"Lote %s (SKU %s) está retenido por calidad o en retiro de mercado. No puede pickearse.",
+					held[0], sku,
+				),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// allocationPickedQty is the quantity actually picked for an allocation (the allocated
+// quantity when the operator did not report a picked_qty).
+func allocationPickedQty(alloc database.LocationAllocation) float64 {
+	if alloc.PickedQty != nil {
+		return *alloc.PickedQty
+	}
+	return alloc.Quantity
+}
+
+// salesOrderPickedQty returns the picked quantity per sales order SKU. Component lines of a
+// kit picked as its components count as the whole kits they complete.
+func salesOrderPickedQty(items []requests.PickingTaskItemRequest) map[string]float64 {
+	out := make(map[string]float64)
+	kitParts := make(map[string]map[string]float64)
+	for _, item := range items {
+		if len(item.Allocations) == 0 {
+			continue
+		}
+		picked := 0.0
+		for _, alloc := range item.Allocations {
+			picked += allocationPickedQty(alloc)
+		}
+		if item.KitSKU == nil || *item.KitSKU == "" || item.KitQtyPer == nil || *item.KitQtyPer <= 0 {
+			out[item.SKU] += picked
+			continue
+		}
+		if kitParts[*item.KitSKU] == nil {
+			kitParts[*item.KitSKU] = make(map[string]float64)
+		}
+		kitParts[*item.KitSKU][item.SKU] += picked / *item.KitQtyPer
+	}
+	for kitSKU, parts := range kitParts {
+		kits := math.Inf(1)
+		for _, n := range parts {
+			kits = math.Min(kits, n)
+		}
+		out[kitSKU] += math.Floor(kits + kitQtyEpsilon)
+	}
+	return out
+}
+
+// checkPickAllowances applies the tenant tolerances to the items about to be picked:
+// every allocation's picked qty against its allocated qty (over-picking), and, when soItems
+// is non-empty, each SO line's cumulative picked qty against the ordered qty (over-delivery).
+// Returns the first violation as a 409 with an AllowanceExceeded detail.
+func checkPickAllowances(items []requests.PickingTaskItemRequest, soItems []database.SalesOrderItem, pickingPct, deliveryPct float64) *responses.InternalResponse {
+	for _, item := range items {
+		for _, alloc := range item.Allocations {
+			picked := allocationPickedQty(alloc)
+			if exceedsAllowance(picked, alloc.Quantity, pickingPct) {
+				return allowanceExceeded(responses.AllowanceOverPicking, item.SKU, alloc.Location, alloc.Quantity, pickingPct, picked)
+			}
+		}
+	}
+	pickedPerSKU := salesOrderPickedQty(items)
+
+	for _, soItem := range soItems {
+		picked, ok := pickedPerSKU[soItem.ArticleSKU]
+		if !ok || picked <= 0 {
+			continue
+		}
+		total := soItem.PickedQty + picked
+		if exceedsAllowance(total, soItem.ExpectedQty, deliveryPct) {
+			return allowanceExceeded(responses.AllowanceOverDelivery, soItem.ArticleSKU, "", soItem.ExpectedQty, deliveryPct, total)
+		}
+	}
+	return nil
+}
+
+// validatePickAllowances loads the tenant's over-picking/over-delivery allowances and the
+// linked sales order lines, then runs checkPickAllowances inside the picking transaction.
+func validatePickAllowances(tx *gorm.DB, task *database.PickingTask, items []requests.PickingTaskItemRequest) (*responses.InternalResponse, error) {
+	pickingPct, err := tenantAllowancePct(tx, task.TenantID, responses.AllowanceOverPicking)
+	if err != nil {
+		return nil, err
+	}
+
+	var soItems []database.SalesOrderItem
+	deliveryPct := 0.0
+	if task.SalesOrderID != nil && *task.SalesOrderID != "" {
+		if deliveryPct, err = tenantAllowancePct(tx, task.TenantID, responses.AllowanceOverDelivery); err != nil {
+			return nil, err
+		}
+		if err := tx.Where("sales_order_id = ?", *task.SalesOrderID).Find(&soItems).Error; err != nil {
+			return nil, fmt.Errorf("load so items for allowance check: %w", err)
+		}
+	}
+
+	return checkPickAllowances(items, soItems, pickingPct, deliveryPct), nil
+}
+
 // sanitizePickingUpdatePayload applies the whitelist and key normalisation that
 // UpdatePickingTask used inline. Extracted as a helper so it can be reused.
 func sanitizePickingUpdatePayload(data map[string]interface{}) map[string]interface{} {
@@ -456,10 +574,17 @@ func (r *PickingTaskRepository) CreatePickingTask(userId string, tenantID string
 		taskID := fmt.Sprintf("PICK-%06d", nowMillis%1_000_000)
 
 		articleCache := make(map[string]database.Article)
+		conv := tools.NewUoMConverter(tx, tenantID)
 		for i := range items {
 			items[i].Status = tools.StrPtr("open")
 			sku := items[i].SKU
 
+			// Lines entered in another presentation are kept in base units.
+			if resp := convertPickingItemUoM(conv, &items[i]); resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+
 			art, ok := articleCache[sku]
 			if !ok {
 				if err := tx.Where("sku = ?", sku).First(&art).Error; err != nil {
@@ -567,11 +692,28 @@ func (r *PickingTaskRepository) StartPickingTask(ctx context.Context, id, userId
 			return fmt.Errorf("expired lot")
 		}
 
+		resp, err := validateNoHeldLots(tx, task.TenantID, items)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("held lot")
+		}
+
 		// Apply lazy reservations.
 		if resp := r.applyReservations(tx, items); resp != nil {
 			handledResp = resp
 			return fmt.Errorf("reservation failed")
 		}
+		resp, err = reservePickingSerials(tx, &task, items, userId)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("serial reservation failed")
+		}
 
 		if err := tx.Exec(
 			`UPDATE picking_tasks SET status = 'in_progress', updated_at = NOW() WHERE id = ?`, id,
@@ -643,6 +785,9 @@ func (r *PickingTaskRepository) UpdatePickingTask(ctx context.Context, id string
 					handledResp = resp
 					return fmt.Errorf("release failed")
 				}
+				if err := releasePickingSerials(tx, &task, oldItems, userId); err != nil {
+					return err
+				}
 			}
 
 			// Set completed_at based on terminal status.
@@ -665,6 +810,9 @@ func (r *PickingTaskRepository) UpdatePickingTask(ctx context.Context, id string
 				handledResp = resp
 				return fmt.Errorf("release old failed")
 			}
+			if err := releasePickingSerials(tx, &task, oldItems, userId); err != nil {
+				return err
+			}
 
 			// Convert clean["items"] ([]interface{} from JSON decode) → typed slice
 			// via re-marshal to avoid unsafe type assertions.
@@ -676,16 +824,44 @@ func (r *PickingTaskRepository) UpdatePickingTask(ctx context.Context, id string
 			if err := json.Unmarshal(newItemsBytes, &newItems); err != nil {
 				return fmt.Errorf("parse new items: %w", err)
 			}
+			// Lines newly entered in another presentation are reserved and stored in base units.
+			changed, convResp := convertPickingItemsUoM(tools.NewUoMConverter(tx, task.TenantID), newItems)
+			if convResp != nil {
+				handledResp = convResp
+				return fmt.Errorf("presentation conversion failed")
+			}
+			if changed {
+				if newItemsBytes, err = json.Marshal(newItems); err != nil {
+					return fmt.Errorf("marshal new items: %w", err)
+				}
+			}
 
 			if resp := r.validateNoExpiredLots(newItems); resp != nil {
 				handledResp = resp
 				return fmt.Errorf("expired lot in update")
 			}
 
+			resp, err := validateNoHeldLots(tx, task.TenantID, newItems)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				handledResp = resp
+				return fmt.Errorf("held lot in update")
+			}
+
 			if resp := r.applyReservations(tx, newItems); resp != nil {
 				handledResp = resp
 				return fmt.Errorf("apply new reservations failed")
 			}
+			resp, err = reservePickingSerials(tx, &task, newItems, userId)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				handledResp = resp
+				return fmt.Errorf("apply new serial reservations failed")
+			}
 
 			// Store the serialised items.
 			clean["items"] = json.RawMessage(newItemsBytes)
@@ -695,6 +871,19 @@ func (r *PickingTaskRepository) UpdatePickingTask(ctx context.Context, id string
 			if err != nil {
 				return fmt.Errorf("marshal items: %w", err)
 			}
+			var newItems []requests.PickingTaskItemRequest
+			if json.Unmarshal(newItemsBytes, &newItems) == nil {
+				changed, resp := convertPickingItemsUoM(tools.NewUoMConverter(tx, task.TenantID), newItems)
+				if resp != nil {
+					handledResp = resp
+					return fmt.Errorf("presentation conversion failed")
+				}
+				if changed {
+					if newItemsBytes, err = json.Marshal(newItems); err != nil {
+						return fmt.Errorf("marshal items: %w", err)
+					}
+				}
+			}
 			clean["items"] = json.RawMessage(newItemsBytes)
 		}
 
@@ -750,12 +939,27 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 			return fmt.Errorf("parse task items: %w", err)
 		}
 
+		// Quantities entered in another presentation are picked in base units.
+		if resp := convertPickingItemUoM(tools.NewUoMConverter(tx, task.TenantID), &item); resp != nil {
+			handledResp = resp
+			return fmt.Errorf("presentation conversion failed")
+		}
+
 		// Validate lots before touching inventory.
 		if resp := r.validateNoExpiredLots([]requests.PickingTaskItemRequest{item}); resp != nil {
 			handledResp = resp
 			return fmt.Errorf("expired lot in complete line")
 		}
 
+		resp, err := validateNoHeldLots(tx, task.TenantID, []requests.PickingTaskItemRequest{item})
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("held lot in complete line")
+		}
+
 		// Find the matching item by SKU.
 		foundIdx := -1
 		for i := range existingItems {
@@ -769,6 +973,16 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 			return fmt.Errorf("item not found")
 		}
 
+		// Over-picking / over-delivery tolerances (stock_settings) before touching inventory.
+		resp, err = validatePickAllowances(tx, &task, []requests.PickingTaskItemRequest{item})
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("pick allowance exceeded")
+		}
+
 		// Decrement inventory, reserved_qty, and lot quantities per allocation.
 		for _, alloc := range item.Allocations {
 			pickedQty := alloc.Quantity
@@ -825,6 +1039,11 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 			beforeQty := inv.Quantity
 			afterQty := inv.Quantity - pickedQty
 			refType := "picking_task"
+			// The inventory price is in the article's currency; cost it in the base currency.
+			priceCost, err := basePriceCost(tx, item.SKU, inv.UnitPrice)
+			if err != nil {
+				return err
+			}
 			mov := &database.InventoryMovement{
 				ID:             movID,
 				SKU:            item.SKU,
@@ -837,7 +1056,7 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 				ReferenceType:  &refType,
 				ReferenceID:    &id,
 				LotID:          lotID,
-				UnitCost:       inv.UnitPrice,
+				UnitCost:       &priceCost,
 				BeforeQty:      &beforeQty,
 				AfterQty:       &afterQty,
 				UserID:         &userId,
@@ -845,6 +1064,9 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 			if err := tx.Create(mov).Error; err != nil {
 				return fmt.Errorf("create outbound movement %s @ %s: %w", item.SKU, alloc.Location, err)
 			}
+			if err := consumeCostLayers(tx, mov, priceCost); err != nil {
+				return err
+			}
 		}
 
 		// Update item status in the task's JSONB.
@@ -883,6 +1105,9 @@ func (r *PickingTaskRepository) CompletePickingLine(ctx context.Context, id, use
 		if handledResp != nil {
 			return handledResp
 		}
+		if resp := missingRateResponse(txErr); resp != nil {
+			return resp
+		}
 		return &responses.InternalResponse{Error: txErr, Message: "Error al completar línea de picking"}
 	}
 
@@ -906,12 +1131,16 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 	var taskCustomerID string
 	var sourceBackorderID *string // set when task was created from a backorder (max depth=1 guard)
 	type pickedItemSnapshot struct {
-		SKU        string
-		Qty        float64
-		LotNumbers []string
+		SKU           string
+		Qty           float64
+		LotNumbers    []string
+		SerialNumbers []string
 	}
 	var pickedItems []pickedItemSnapshot
 	var soItems []database.SalesOrderItem // loaded for BO1 computation
+	// PK1 — packing stage: when the tenant requires packing, a shipment is opened instead of the DN.
+	var requirePacking bool
+	var shipmentLines []ShipmentLineCreationParam
 
 	txErr := r.DB.Transaction(func(tx *gorm.DB) error {
 		var task database.PickingTask
@@ -937,6 +1166,36 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 			return fmt.Errorf("parse items: %w", err)
 		}
 
+		// Lots put on QC hold after the task started cannot ship.
+		resp, err := validateNoHeldLots(tx, task.TenantID, items)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("held lot")
+		}
+
+		// Over-picking / over-delivery tolerances (stock_settings) before touching inventory.
+		resp, err = validatePickAllowances(tx, &task, items)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("pick allowance exceeded")
+		}
+
+		// Picked serials must still be in the warehouse to leave on the delivery note.
+		resp, err = checkPickedSerials(tx, task.TenantID, items)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return fmt.Errorf("serial cannot ship")
+		}
+
 		hasDifferences := false
 		// perSKULots collects lot numbers picked per SKU for DN snapshot.
 		perSKULots := make(map[string][]string)
@@ -1003,6 +1262,11 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 				beforeQty := inv.Quantity
 				afterQty := inv.Quantity - pickedQty
 				refType := "picking_task"
+				// The inventory price is in the article's currency; cost it in the base currency.
+				priceCost, err := basePriceCost(tx, item.SKU, inv.UnitPrice)
+				if err != nil {
+					return err
+				}
 				mov := &database.InventoryMovement{
 					ID:             movID,
 					SKU:            item.SKU,
@@ -1015,7 +1279,7 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 					ReferenceType:  &refType,
 					ReferenceID:    &id,
 					LotID:          lotID,
-					UnitCost:       inv.UnitPrice,
+					UnitCost:       &priceCost,
 					BeforeQty:      &beforeQty,
 					AfterQty:       &afterQty,
 					UserID:         &userId,
@@ -1023,6 +1287,9 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 				if err := tx.Create(mov).Error; err != nil {
 					return fmt.Errorf("create outbound movement %s @ %s: %w", item.SKU, alloc.Location, err)
 				}
+				if err := consumeCostLayers(tx, mov, priceCost); err != nil {
+					return err
+				}
 
 				perSKUPickedQty[item.SKU] += pickedQty
 			}
@@ -1043,16 +1310,8 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 		// SO3 — capture SO ID and picked quantities for post-tx update (avoids nested tx deadlock).
 		if task.SalesOrderID != nil && *task.SalesOrderID != "" {
 			linkedSOID = *task.SalesOrderID
-			soPickedPerSKU = make(map[string]float64)
-			for _, item := range items {
-				for _, alloc := range item.Allocations {
-					pickedQty := alloc.Quantity
-					if alloc.PickedQty != nil {
-						pickedQty = *alloc.PickedQty
-					}
-					soPickedPerSKU[item.SKU] += pickedQty
-				}
-			}
+			// Kit components picked in place of assembled kits count as the kits they complete.
+			soPickedPerSKU = salesOrderPickedQty(items)
 		}
 
 		// DN1/BO1 — capture context for post-tx generation.
@@ -1063,16 +1322,39 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 		sourceBackorderID = task.SourceBackorderID
 
 		// Build picked item snapshots for DN creation.
+		pickedSerials := pickingItemSerials(items)
 		for sku, qty := range perSKUPickedQty {
 			if qty > 0 {
 				pickedItems = append(pickedItems, pickedItemSnapshot{
-					SKU:        sku,
-					Qty:        qty,
-					LotNumbers: perSKULots[sku],
+					SKU:           sku,
+					Qty:           qty,
+					LotNumbers:    perSKULots[sku],
+					SerialNumbers: pickedSerials[sku],
 				})
 			}
 		}
 
+		// Without a sales order no delivery note follows: the serials leave with the picking.
+		if task.SalesOrderID == nil || *task.SalesOrderID == "" {
+			for sku, serials := range pickedSerials {
+				if err := shipSerials(tx, task.TenantID, sku, serials, serialEvent{
+					DocumentType: "picking_task", DocumentID: task.ID, DocumentNumber: task.TaskID, UserID: userId,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		// PK1 — shipment lines per SKU + lot (with serials) for the packing stage.
+		if linkedSOID != "" {
+			if requirePacking, err = tenantRequiresPacking(tx, task.TenantID); err != nil {
+				return err
+			}
+			if requirePacking {
+				shipmentLines = shipmentLinesFromItems(items)
+			}
+		}
+
 		// Load SO items for BO1 backorder computation (need expected vs picked).
 		if linkedSOID != "" && task.SourceBackorderID == nil {
 			if err := tx.Where("sales_order_id = ?", linkedSOID).Find(&soItems).Error; err != nil {
@@ -1087,6 +1369,9 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 		if handledResp != nil {
 			return handledResp
 		}
+		if resp := missingRateResponse(txErr); resp != nil {
+			return resp
+		}
 		return &responses.InternalResponse{Error: txErr, Message: "Error al completar picking"}
 	}
 
@@ -1098,11 +1383,38 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 	var newSOStatus string
 	if linkedSOID != "" && r.SORepository != nil && len(soPickedPerSKU) > 0 {
 		// Returns new SO status ('completed' | 'partial') — used for DN1/BO1 routing.
-		newSOStatus, _ = r.SORepository.UpdatePickedQty(linkedSOID, soPickedPerSKU) //nolint:errcheck
+		// Over-delivery was validated inside the picking tx; a rejection here means the SO
+		// changed concurrently, so it is logged rather than failing the committed picking.
+		var soResp *responses.InternalResponse
+		newSOStatus, soResp = r.SORepository.UpdatePickedQty(linkedSOID, soPickedPerSKU)
+		if soResp != nil {
+			fmt.Printf("[WARN] CompletePickingTask: failed to update picked qty on SO %s: %s\n", linkedSOID, soResp.Message)
+		}
+	}
+
+	// PK1 — tenants that require packing get a shipment to pack; its delivery note is created
+	// when the shipment is closed (ShipmentsRepository.CloseShipment).
+	if requirePacking && linkedSOID != "" && taskTenantID != "" && (newSOStatus == "completed" || newSOStatus == "partial") && len(shipmentLines) > 0 {
+		shTx := r.DB.Begin()
+		if shTx.Error == nil {
+			if _, err := CreateShipment(shTx, ShipmentCreationParams{
+				TenantID:      taskTenantID,
+				SalesOrderID:  linkedSOID,
+				PickingTaskID: id,
+				CustomerID:    taskCustomerID,
+				Lines:         shipmentLines,
+			}); err != nil {
+				shTx.Rollback()
+				// Log but don't fail — picking is already committed.
+				fmt.Printf("[WARN] CompletePickingTask: failed to create shipment for picking %s: %v\n", id, err)
+			} else {
+				shTx.Commit()
+			}
+		}
 	}
 
 	// DN1 — generate delivery note when SO has been advanced (completed or partial).
-	if linkedSOID != "" && taskTenantID != "" && (newSOStatus == "completed" || newSOStatus == "partial") {
+	if !requirePacking && linkedSOID != "" && taskTenantID != "" && (newSOStatus == "completed" || newSOStatus == "partial") {
 		dnParams := DNCreationParams{
 			TenantID:      taskTenantID,
 			SalesOrderID:  linkedSOID,
@@ -1111,9 +1423,10 @@ func (r *PickingTaskRepository) CompletePickingTask(ctx context.Context, id, use
 		}
 		for _, pi := range pickedItems {
 			dnParams.Items = append(dnParams.Items, DNItemCreationParam{
-				ArticleSKU: pi.SKU,
-				Qty:        pi.Qty,
-				LotNumbers: pi.LotNumbers,
+				ArticleSKU:    pi.SKU,
+				Qty:           pi.Qty,
+				LotNumbers:    pi.LotNumbers,
+				SerialNumbers: pi.SerialNumbers,
 			})
 		}
 		if len(dnParams.Items) > 0 {
@@ -1519,3 +1832,12 @@ func (r *PickingTaskRepository) LinkCustomer(taskID string, customerID *string)
 	}
 	return nil
 }
+
+// GetLocationPickPaths returns the pick path of the task's locations (see tools.OrderPickPath).
+func (r *PickingTaskRepository) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	paths, err := tools.GetLocationPickPaths(r.DB, tenantID, codes)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la ruta de picking de las ubicaciones"}
+	}
+	return paths, nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidWaveTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		want    bool
+	}{
+		{"open → in_progress", "open", "in_progress", true},
+		{"open → cancelled", "open", "cancelled", true},
+		{"in_progress → completed", "in_progress", "completed", true},
+		{"in_progress → completed_with_differences", "in_progress", "completed_with_differences", true},
+		{"in_progress → cancelled", "in_progress", "cancelled", true},
+
+		{"open → completed (skips picking)", "open", "completed", false},
+		{"in_progress → in_progress", "in_progress", "in_progress", false},
+		{"completed → cancelled (final)", "completed", "cancelled", false},
+		{"cancelled → open (final)", "cancelled", "open", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidWaveTransition(tt.current, tt.next))
+		})
+	}
+}
+
+func wavePicked(allocs []*database.LocationAllocation) []float64 {
+	out := make([]float64, len(allocs))
+	for i, a := range allocs {
+		out[i] = *a.PickedQty
+	}
+	return out
+}
+
+func TestDistributeWavePick(t *testing.T) {
+	newTargets := func() []*database.LocationAllocation {
+		return []*database.LocationAllocation{
+			{Location: "A-01", Quantity: 5},
+			{Location: "A-01", Quantity: 3},
+			{Location: "A-01", Quantity: 2},
+		}
+	}
+
+	t.Run("exact quantity fills every allocation", func(t *testing.T) {
+		targets := newTargets()
+		distributeWavePick(targets, 10)
+		assert.Equal(t, []float64{5, 3, 2}, wavePicked(targets))
+		for _, a := range targets {
+			require.NotNil(t, a.Status)
+			assert.Equal(t, "picked", *a.Status)
+		}
+	})
+
+	t.Run("short pick fills in wave order", func(t *testing.T) {
+		targets := newTargets()
+		distributeWavePick(targets, 6)
+		assert.Equal(t, []float64{5, 1, 0}, wavePicked(targets))
+		assert.Equal(t, "picked", *targets[1].Status)
+		assert.Equal(t, "skipped", *targets[2].Status)
+	})
+
+	t.Run("over pick goes to the last allocation", func(t *testing.T) {
+		targets := newTargets()
+		distributeWavePick(targets, 12)
+		assert.Equal(t, []float64{5, 3, 4}, wavePicked(targets))
+	})
+
+	t.Run("zero skips everything", func(t *testing.T) {
+		targets := newTargets()
+		distributeWavePick(targets, 0)
+		assert.Equal(t, []float64{0, 0, 0}, wavePicked(targets))
+		assert.Equal(t, "skipped", *targets[0].Status)
+	})
+}
+
+func TestSameLot(t *testing.T) {
+	lot := "L1"
+	empty := ""
+	other := "L2"
+	assert.True(t, sameLot(nil, nil))
+	assert.True(t, sameLot(nil, &empty))
+	assert.True(t, sameLot(&lot, &lot))
+	assert.False(t, sameLot(&lot, nil))
+	assert.False(t, sameLot(&lot, &other))
+}
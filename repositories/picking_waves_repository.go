@@ -0,0 +1,500 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// PickingWavesRepository implements ports.PickingWavesRepository using GORM.
+// Consistent with CycleCountsRepository (GORM-based, raw SQL where needed).
+type PickingWavesRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.PickingWavesRepository = (*PickingWavesRepository)(nil)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// validWaveTransitions declares the allowed wave status changes.
+// Final states (completed, completed_with_differences, cancelled) have no outgoing transition.
+var validWaveTransitions = map[string]map[string]bool{
+	"open":        {"in_progress": true, "cancelled": true},
+	"in_progress": {"completed": true, "completed_with_differences": true, "cancelled": true},
+}
+
+// isValidWaveTransition returns true when current → next is allowed.
+func isValidWaveTransition(current, next string) bool {
+	if allowed, ok := validWaveTransitions[current]; ok {
+		return allowed[next]
+	}
+	return false
+}
+
+// nextWaveNumber generates "WV-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextCountTaskNumber. The lock is held until commit, so it
+// also serializes member selection between concurrent wave creations of the tenant.
+func nextWaveNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("WV-%d-", year)
+
+	lockKey := fmt.Sprintf("wv-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire WV number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(wave_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM picking_waves
+		WHERE tenant_id = $2
+		  AND wave_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate WV number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// waveGroupValue is the group_value stored on the wave for the selection criteria.
+func waveGroupValue(groupBy string, req *requests.CreatePickingWaveRequest) *string {
+	var v string
+	switch groupBy {
+	case "customer":
+		v = strings.Join(req.CustomerIDs, ",")
+	case "priority":
+		if req.Priority != nil {
+			v = *req.Priority
+		}
+	case "expected_date":
+		if req.ExpectedDate != nil {
+			v = *req.ExpectedDate
+		}
+	}
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// selectWaveCandidates returns the ids of the tenant tasks matching the wave criteria, in
+// wave order: priority (high, normal, low), then sales order expected date, then age.
+// Only open/in-progress tasks that are not in another live wave are eligible; max_tasks
+// does not apply to an explicit list.
+func selectWaveCandidates(tx *gorm.DB, tenantID, groupBy string, req *requests.CreatePickingWaveRequest) ([]string, error) {
+	q := tx.Table("picking_tasks pt").
+		Select("pt.id").
+		Joins("LEFT JOIN sales_orders so ON so.id = pt.sales_order_id").
+		Where("pt.tenant_id = ? AND pt.status IN ?", tenantID, []string{"open", "in_progress"}).
+		Where("NOT EXISTS (SELECT 1 FROM picking_wave_tasks wt WHERE wt.picking_task_id = pt.id AND wt.active)")
+
+	switch groupBy {
+	case "manual":
+		q = q.Where("pt.id IN ?", req.PickingTaskIDs)
+	case "customer":
+		q = q.Where("pt.customer_id IN ?", req.CustomerIDs)
+	case "priority":
+		q = q.Where("pt.priority = ?", *req.Priority)
+	case "expected_date":
+		q = q.Where("so.expected_date::date = ?::date", *req.ExpectedDate)
+	default:
+		return nil, fmt.Errorf("unsupported group_by %q", groupBy)
+	}
+
+	q = q.Order(`CASE pt.priority WHEN 'high' THEN 0 WHEN 'low' THEN 2 ELSE 1 END, so.expected_date ASC NULLS LAST, pt.created_at ASC`)
+	if groupBy != "manual" && req.MaxTasks != nil {
+		q = q.Limit(*req.MaxTasks)
+	}
+
+	var ids []string
+	if err := q.Pluck("pt.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("select wave candidates: %w", err)
+	}
+	return ids, nil
+}
+
+// sameLot reports whether an allocation lot matches the lot of a pick line (no lot matches
+// no lot).
+func sameLot(a, b *string) bool {
+	av, bv := "", ""
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	return av == bv
+}
+
+// distributeWavePick sorts picked into the target allocations in order: each allocation gets
+// up to its allocated quantity and any excess goes to the last one, so the over-pick is
+// checked against the tolerances when that task completes. Allocations that get nothing are
+// marked skipped.
+func distributeWavePick(targets []*database.LocationAllocation, picked float64) {
+	remaining := picked
+	for i, alloc := range targets {
+		share := alloc.Quantity
+		if remaining < share {
+			share = remaining
+		}
+		if i == len(targets)-1 {
+			share = remaining
+		}
+		if share < 0 {
+			share = 0
+		}
+		remaining -= share
+
+		qty := share
+		alloc.PickedQty = &qty
+		if qty > 0 {
+			alloc.Status = tools.StrPtr("picked")
+		} else {
+			alloc.Status = tools.StrPtr("skipped")
+		}
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Waves
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *PickingWavesRepository) CreateWave(tenantID, createdBy string, req *requests.CreatePickingWaveRequest) (*database.PickingWave, *responses.InternalResponse) {
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = "manual"
+	}
+
+	var wave *database.PickingWave
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		waveNumber, err := nextWaveNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		taskIDs, err := selectWaveCandidates(tx, tenantID, groupBy, req)
+		if err != nil {
+			return err
+		}
+		if groupBy == "manual" && len(taskIDs) != len(req.PickingTaskIDs) {
+			found := make(map[string]bool, len(taskIDs))
+			for _, id := range taskIDs {
+				found[id] = true
+			}
+			missing := make([]string, 0)
+			for _, id := range req.PickingTaskIDs {
+				if !found[id] {
+					missing = append(missing, id)
+				}
+			}
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Tareas no elegibles para la ola (no existen, no están abiertas o ya pertenecen a otra ola): %s", strings.Join(missing, ", ")),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+		if len(taskIDs) == 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    "No hay tareas de picking abiertas que cumplan el criterio de la ola",
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+
+		waveID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate wave id: %w", err)
+		}
+
+		now := tools.GetCurrentTime()
+		w := database.PickingWave{
+			ID:         waveID,
+			TenantID:   tenantID,
+			WaveNumber: waveNumber,
+			Status:     "open",
+			GroupBy:    groupBy,
+			GroupValue: waveGroupValue(groupBy, req),
+			AssignedTo: req.AssignedTo,
+			Notes:      req.Notes,
+			CreatedBy:  &createdBy,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := tx.Create(&w).Error; err != nil {
+			return fmt.Errorf("create wave: %w", err)
+		}
+
+		for i, taskID := range taskIDs {
+			member := database.PickingWaveTask{
+				WaveID:        waveID,
+				PickingTaskID: taskID,
+				Sequence:      i + 1,
+				Active:        true,
+			}
+			if err := tx.Create(&member).Error; err != nil {
+				return fmt.Errorf("add task %s to wave: %w", taskID, err)
+			}
+		}
+
+		wave = &w
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la ola de picking"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return wave, nil
+}
+
+func (r *PickingWavesRepository) GetWaveByID(id, tenantID string) (*database.PickingWave, *responses.InternalResponse) {
+	var wave database.PickingWave
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&wave).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Ola de picking no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la ola de picking"}
+	}
+	return &wave, nil
+}
+
+func (r *PickingWavesRepository) ListWaves(tenantID string, status *string, limit, offset int) ([]database.PickingWave, *responses.InternalResponse) {
+	query := r.DB.Model(&database.PickingWave{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var waves []database.PickingWave
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&waves).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las olas de picking"}
+	}
+	return waves, nil
+}
+
+func (r *PickingWavesRepository) GetWaveMembers(waveID string) ([]database.PickingWaveMember, *responses.InternalResponse) {
+	var members []database.PickingWaveMember
+	if err := r.DB.Raw(`
+		SELECT pt.*, wt.sequence, wt.active, so.expected_date
+		  FROM picking_wave_tasks wt
+		  JOIN picking_tasks pt ON pt.id = wt.picking_task_id
+		  LEFT JOIN sales_orders so ON so.id = pt.sales_order_id
+		 WHERE wt.wave_id = ?
+		 ORDER BY wt.sequence ASC
+	`, waveID).Scan(&members).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las tareas de la ola de picking"}
+	}
+	return members, nil
+}
+
+func (r *PickingWavesRepository) TransitionWave(id, tenantID, next string) (*database.PickingWave, *responses.InternalResponse) {
+	var result *database.PickingWave
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var wave database.PickingWave
+		if err := tx.Where("id = ? AND tenant_id = ?", id, tenantID).First(&wave).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				*handledResp = responses.InternalResponse{Message: "Ola de picking no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return fmt.Errorf("load wave: %w", err)
+		}
+
+		if !isValidWaveTransition(wave.Status, next) {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Transición inválida: %s → %s", wave.Status, next),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{
+			"status":     next,
+			"updated_at": now,
+		}
+		switch next {
+		case "in_progress":
+			updates["started_at"] = now
+		case "completed", "completed_with_differences":
+			updates["completed_at"] = now
+		case "cancelled":
+			updates["cancelled_at"] = now
+		}
+
+		// Optimistic guard: only move from the status we just validated.
+		res := tx.Model(&database.PickingWave{}).
+			Where("id = ? AND status = ?", wave.ID, wave.Status).
+			Updates(updates)
+		if res.Error != nil {
+			return fmt.Errorf("update wave status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    "La ola de picking fue modificada por otro usuario; recargue e intente de nuevo",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		// A cancelled wave releases its tasks so they can join another wave. Completed waves
+		// keep them: their tasks are finished and can no longer be grouped.
+		if next == "cancelled" {
+			if err := tx.Model(&database.PickingWaveTask{}).
+				Where("wave_id = ? AND active", wave.ID).
+				Update("active", false).Error; err != nil {
+				return fmt.Errorf("release wave tasks: %w", err)
+			}
+		}
+
+		if err := tx.Where("id = ?", wave.ID).First(&wave).Error; err != nil {
+			return fmt.Errorf("reload wave: %w", err)
+		}
+		result = &wave
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al actualizar el estado de la ola de picking"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
+
+func (r *PickingWavesRepository) RecordPick(waveID, tenantID string, req *requests.RecordWavePickRequest) *responses.InternalResponse {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var status string
+		if err := tx.Raw("SELECT status FROM picking_waves WHERE id = ? AND tenant_id = ? FOR UPDATE", waveID, tenantID).
+			Scan(&status).Error; err != nil {
+			return fmt.Errorf("lock wave: %w", err)
+		}
+		if status == "" {
+			*handledResp = responses.InternalResponse{Message: "Ola de picking no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+			return nil
+		}
+		if status != "in_progress" {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Solo se pueden registrar picks en olas en proceso (estado actual: %q)", status),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+			return nil
+		}
+
+		// Lock the in-progress member tasks so a concurrent pick or completion of the same
+		// task cannot overwrite the items we are about to write.
+		var tasks []database.PickingTask
+		if err := tx.Raw(`
+			SELECT pt.*
+			  FROM picking_wave_tasks wt
+			  JOIN picking_tasks pt ON pt.id = wt.picking_task_id
+			 WHERE wt.wave_id = ? AND wt.active AND pt.status = 'in_progress'
+			 ORDER BY wt.sequence ASC
+			 FOR UPDATE OF pt
+		`, waveID).Scan(&tasks).Error; err != nil {
+			return fmt.Errorf("lock wave tasks: %w", err)
+		}
+
+		taskItems := make([][]requests.PickingTaskItemRequest, len(tasks))
+		touched := make([]bool, len(tasks))
+		var targets []*database.LocationAllocation
+		for t := range tasks {
+			items, err := parsePickingItemsWithLegacyFallback(tasks[t].Items)
+			if err != nil {
+				return fmt.Errorf("parse items of task %s: %w", tasks[t].TaskID, err)
+			}
+			taskItems[t] = items
+			for i := range items {
+				if items[i].SKU != req.SKU {
+					continue
+				}
+				for a := range items[i].Allocations {
+					alloc := &items[i].Allocations[a]
+					if alloc.Location == req.Location && sameLot(alloc.LotNumber, req.LotNumber) {
+						targets = append(targets, alloc)
+						touched[t] = true
+					}
+				}
+			}
+		}
+		if len(targets) == 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("La línea %s @ %s no está pendiente en ninguna tarea en proceso de la ola", req.SKU, req.Location),
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}
+			return nil
+		}
+
+		distributeWavePick(targets, *req.PickedQty)
+
+		now := tools.GetCurrentTime()
+		for t := range tasks {
+			if !touched[t] {
+				continue
+			}
+			raw, err := json.Marshal(taskItems[t])
+			if err != nil {
+				return fmt.Errorf("marshal items of task %s: %w", tasks[t].TaskID, err)
+			}
+			if err := tx.Model(&database.PickingTask{}).
+				Where("id = ?", tasks[t].ID).
+				Updates(map[string]interface{}{"items": raw, "updated_at": now}).Error; err != nil {
+				return fmt.Errorf("update items of task %s: %w", tasks[t].TaskID, err)
+			}
+		}
+		if err := tx.Model(&database.PickingWave{}).Where("id = ?", waveID).Update("updated_at", now).Error; err != nil {
+			return fmt.Errorf("touch wave: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al registrar el pick de la ola"}
+	}
+	if handledResp.Handled {
+		return handledResp
+	}
+	return nil
+}
+
+// GetLocationPickPaths returns the pick path of the wave's locations (see tools.OrderPickPath).
+func (r *PickingWavesRepository) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	paths, err := tools.GetLocationPickPaths(r.DB, tenantID, codes)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la ruta de picking de las ubicaciones"}
+	}
+	return paths, nil
+}
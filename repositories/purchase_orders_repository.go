@@ -71,14 +71,17 @@ func poToView(po database.PurchaseOrder, items []database.PurchaseOrderItem) res
 	}
 	for _, it := range items {
 		v.Items = append(v.Items, responses.PurchaseOrderItemView{
-			ID:          it.ID,
-			ArticleSKU:  it.ArticleSKU,
-			ExpectedQty: it.ExpectedQty,
-			ReceivedQty: it.ReceivedQty,
-			RejectedQty: it.RejectedQty,
-			Discrepancy: it.Discrepancy,
-			UnitCost:    it.UnitCost,
-			Notes:       it.Notes,
+			ID:                 it.ID,
+			ArticleSKU:         it.ArticleSKU,
+			ExpectedQty:        it.ExpectedQty,
+			ReceivedQty:        it.ReceivedQty,
+			RejectedQty:        it.RejectedQty,
+			Discrepancy:        it.Discrepancy,
+			UnitCost:           it.UnitCost,
+			Currency:           it.Currency,
+			Notes:              it.Notes,
+			Presentation:       it.Presentation,
+			PresentationFactor: it.PresentationFactor,
 		})
 	}
 	return v
@@ -99,6 +102,7 @@ func (r *PurchaseOrdersRepository) loadItems(poID string) ([]database.PurchaseOr
 
 func (r *PurchaseOrdersRepository) Create(tenantID, createdBy string, req *requests.CreatePurchaseOrderRequest) (*responses.PurchaseOrderView, *responses.InternalResponse) {
 	var result *responses.PurchaseOrderView
+	var handledResp *responses.InternalResponse
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		// Auto-generate PO number (row-locked per tenant+year).
@@ -112,6 +116,12 @@ func (r *PurchaseOrdersRepository) Create(tenantID, createdBy string, req *reque
 			return fmt.Errorf("generate PO id: %w", err)
 		}
 
+		// Empty createdBy = system-generated PO (replenishment cron); created_by stays NULL.
+		var createdByPtr *string
+		if createdBy != "" {
+			createdByPtr = &createdBy
+		}
+
 		now := tools.GetCurrentTime()
 		po := database.PurchaseOrder{
 			ID:           poID,
@@ -121,7 +131,7 @@ func (r *PurchaseOrdersRepository) Create(tenantID, createdBy string, req *reque
 			Status:       "draft",
 			ExpectedDate: req.ExpectedDate,
 			Notes:        req.Notes,
-			CreatedBy:    &createdBy,
+			CreatedBy:    createdByPtr,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -130,23 +140,32 @@ func (r *PurchaseOrdersRepository) Create(tenantID, createdBy string, req *reque
 			return fmt.Errorf("create purchase_order: %w", err)
 		}
 
-		// Insert items.
+		// Insert items, converted to the base presentation of each article.
+		conv := tools.NewUoMConverter(tx, tenantID)
 		items := make([]database.PurchaseOrderItem, 0, len(req.Items))
 		for _, it := range req.Items {
+			uom, resp := convertLineUoM(conv, it.ArticleSKU, it.Presentation, it.ExpectedQty, it.UnitCost)
+			if resp != nil {
+				handledResp = resp
+				return errors.New(resp.Message)
+			}
 			itemID, err := tools.GenerateNanoid(tx)
 			if err != nil {
 				return fmt.Errorf("generate PO item id: %w", err)
 			}
 			item := database.PurchaseOrderItem{
-				ID:              itemID,
-				PurchaseOrderID: poID,
-				ArticleSKU:      it.ArticleSKU,
-				ExpectedQty:     it.ExpectedQty,
-				ReceivedQty:     0,
-				RejectedQty:     0,
-				UnitCost:        it.UnitCost,
-				Notes:           it.Notes,
-				CreatedAt:       now,
+				ID:                 itemID,
+				PurchaseOrderID:    poID,
+				ArticleSKU:         it.ArticleSKU,
+				ExpectedQty:        uom.Qty,
+				ReceivedQty:        0,
+				RejectedQty:        0,
+				UnitCost:           uom.Price,
+				Currency:           it.Currency,
+				Notes:              it.Notes,
+				Presentation:       uom.Presentation,
+				PresentationFactor: uom.Factor,
+				CreatedAt:          now,
 			}
 			if err := tx.Create(&item).Error; err != nil {
 				return fmt.Errorf("create PO item for SKU %s: %w", it.ArticleSKU, err)
@@ -159,6 +178,9 @@ func (r *PurchaseOrdersRepository) Create(tenantID, createdBy string, req *reque
 		return nil
 	})
 
+	if handledResp != nil {
+		return nil, handledResp
+	}
 	if err != nil {
 		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la orden de compra"}
 	}
@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// PutawayRepository implements ports.PutawayRepository using GORM.
+type PutawayRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.PutawayRepository = (*PutawayRepository)(nil)
+
+func (r *PutawayRepository) GetReceivingTask(tenantID, taskID string) (*database.ReceivingTask, *responses.InternalResponse) {
+	var task database.ReceivingTask
+	if err := r.DB.Where("id = ? AND tenant_id = ?", taskID, tenantID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{
+				Message:    "Tarea de recepción no encontrada",
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}
+		}
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener la tarea de recepción",
+			Handled: false,
+		}
+	}
+	return &task, nil
+}
+
+func (r *PutawayRepository) GetArticles(tenantID string, skus []string) ([]database.Article, *responses.InternalResponse) {
+	var articles []database.Article
+	if len(skus) == 0 {
+		return articles, nil
+	}
+	if err := r.DB.Where("tenant_id = ? AND sku IN ?", tenantID, skus).Find(&articles).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los artículos",
+			Handled: false,
+		}
+	}
+	return articles, nil
+}
+
+func (r *PutawayRepository) ListPutawayLocations(tenantID string) ([]database.Location, *responses.InternalResponse) {
+	var locations []database.Location
+	err := r.DB.
+		Where("tenant_id = ? AND is_active = true AND is_way_out = false", tenantID).
+		Order("location_code").
+		Find(&locations).Error
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener las ubicaciones",
+			Handled: false,
+		}
+	}
+	return locations, nil
+}
+
+func (r *PutawayRepository) ListLocationTypes() ([]database.LocationType, *responses.InternalResponse) {
+	var types []database.LocationType
+	if err := r.DB.Table("location_types").Order("sort_order, code").Find(&types).Error; err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener los tipos de ubicación",
+			Handled: false,
+		}
+	}
+	return types, nil
+}
+
+func (r *PutawayRepository) GetLocationStock(tenantID string, skus []string) ([]ports.LocationStock, *responses.InternalResponse) {
+	var rows []ports.LocationStock
+	if len(skus) == 0 {
+		return rows, nil
+	}
+	// Lot-tracked stock is reported per lot from inventory_lots; the remainder of each
+	// inventory row (untracked stock) is reported without a lot.
+	err := r.DB.Raw(`
+		SELECT il.location AS location, i.sku AS sku, l.lot_number AS lot_number, SUM(il.quantity) AS quantity
+		FROM inventory_lots il
+		JOIN inventory i ON i.id = il.inventory_id AND i.tenant_id = il.tenant_id
+		JOIN lots l ON l.id = il.lot_id
+		WHERE il.tenant_id = ? AND i.sku IN ? AND il.quantity > 0
+		GROUP BY il.location, i.sku, l.lot_number
+		UNION ALL
+		SELECT i.location, i.sku, '', i.quantity - COALESCE(SUM(il.quantity), 0)
+		FROM inventory i
+		LEFT JOIN inventory_lots il ON il.inventory_id = i.id AND il.tenant_id = i.tenant_id
+		WHERE i.tenant_id = ? AND i.sku IN ?
+		GROUP BY i.id, i.location, i.sku, i.quantity
+		HAVING i.quantity - COALESCE(SUM(il.quantity), 0) > 0`,
+		tenantID, skus, tenantID, skus).Scan(&rows).Error
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener el inventario por ubicación",
+			Handled: false,
+		}
+	}
+	return rows, nil
+}
+
+func (r *PutawayRepository) GetLocationCapacity(tenantID string, locationCodes []string) (map[string]ports.LocationCapacity, *responses.InternalResponse) {
+	out := make(map[string]ports.LocationCapacity, len(locationCodes))
+	if len(locationCodes) == 0 {
+		return out, nil
+	}
+	// Same effective limits and load as tools.GetLocationLimits / tools.GetLocationLoad, read for
+	// every location in one pass.
+	var rows []struct {
+		LocationCode string   `gorm:"column:location_code"`
+		MaxUnits     *float64 `gorm:"column:max_units"`
+		MaxWeightKg  *float64 `gorm:"column:max_weight_kg"`
+		MaxVolumeM3  *float64 `gorm:"column:max_volume_m3"`
+		Units        float64  `gorm:"column:units"`
+		WeightKg     float64  `gorm:"column:weight_kg"`
+		VolumeM3     float64  `gorm:"column:volume_m3"`
+	}
+	err := r.DB.Raw(`
+		SELECT l.location_code,
+		       COALESCE(l.max_units, lt.max_units) AS max_units,
+		       COALESCE(l.max_weight_kg, lt.max_weight_kg) AS max_weight_kg,
+		       COALESCE(l.max_volume_m3, lt.max_volume_m3) AS max_volume_m3,
+		       COALESCE(s.units, 0) AS units,
+		       COALESCE(s.weight_kg, 0) AS weight_kg,
+		       COALESCE(s.volume_m3, 0) AS volume_m3
+		FROM locations l
+		LEFT JOIN location_types lt ON UPPER(lt.code) = UPPER(l.type)
+		LEFT JOIN (
+			SELECT i.location,
+			       SUM(i.quantity) AS units,
+			       SUM(i.quantity * `+tools.ArticleUnitWeightSQL+`) AS weight_kg,
+			       SUM(i.quantity * `+tools.ArticleUnitVolumeSQL+`) AS volume_m3
+			FROM inventory i
+			LEFT JOIN articles a ON a.sku = i.sku AND a.tenant_id = i.tenant_id
+			WHERE i.tenant_id = ? AND i.location IN ?
+			GROUP BY i.location
+		) s ON s.location = l.location_code
+		WHERE l.tenant_id = ? AND l.location_code IN ?`,
+		tenantID, locationCodes, tenantID, locationCodes).Scan(&rows).Error
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al obtener la capacidad de las ubicaciones",
+			Handled: false,
+		}
+	}
+	for _, row := range rows {
+		out[row.LocationCode] = ports.LocationCapacity{
+			MaxUnits:    row.MaxUnits,
+			MaxWeightKg: row.MaxWeightKg,
+			MaxVolumeM3: row.MaxVolumeM3,
+			Units:       row.Units,
+			WeightKg:    row.WeightKg,
+			VolumeM3:    row.VolumeM3,
+		}
+	}
+	return out, nil
+}
+
+func (r *PutawayRepository) UpdateReceivingTaskItems(tenantID, taskID string, items json.RawMessage) *responses.InternalResponse {
+	err := r.DB.Model(&database.ReceivingTask{}).
+		Where("id = ? AND tenant_id = ?", taskID, tenantID).
+		Updates(map[string]interface{}{"items": items, "updated_at": tools.GetCurrentTime()}).Error
+	if err != nil {
+		return &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al actualizar las ubicaciones de la tarea de recepción",
+			Handled: false,
+		}
+	}
+	return nil
+}
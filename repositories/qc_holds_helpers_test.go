@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQCHoldDecision(t *testing.T) {
+	assert.Nil(t, qcHoldDecision(&database.QCHold{HoldNumber: "QC-2026-0001", Status: database.QCHoldQuarantine}))
+
+	for _, status := range []string{database.QCHoldReleased, database.QCHoldRejected} {
+		resp := qcHoldDecision(&database.QCHold{HoldNumber: "QC-2026-0001", Status: status})
+		require.NotNil(t, resp, status)
+		assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+		assert.Contains(t, resp.Message, "QC-2026-0001")
+	}
+}
+
+func TestLotHoldConflict(t *testing.T) {
+	status := func(v string) *string { return &v }
+
+	assert.Nil(t, lotHoldConflict(&database.Lot{LotNumber: "L1"}))
+	assert.Nil(t, lotHoldConflict(&database.Lot{LotNumber: "L1", Status: status("pending")}))
+	assert.Nil(t, lotHoldConflict(&database.Lot{LotNumber: "L1", Status: status(database.QCHoldReleased)}))
+
+	for _, s := range []string{database.QCHoldQuarantine, database.QCHoldRejected} {
+		resp := lotHoldConflict(&database.Lot{LotNumber: "L1", Status: status(s)})
+		require.NotNil(t, resp, s)
+		assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	}
+}
+
+func TestBuildQCHoldView_EmptySlices(t *testing.T) {
+	lot := "L1"
+	view := buildQCHoldView(database.QCHold{ID: "h1", Scope: database.QCHoldScopeLot}, &lot, nil, nil)
+	assert.Equal(t, "h1", view.ID)
+	assert.Equal(t, &lot, view.LotNumber)
+	assert.NotNil(t, view.Inspections)
+	assert.NotNil(t, view.Attachments)
+}
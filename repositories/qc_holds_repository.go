@@ -0,0 +1,683 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QCHoldsRepository implements ports.QCHoldsRepository using GORM.
+type QCHoldsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.QCHoldsRepository = (*QCHoldsRepository)(nil)
+
+// qcQtyEpsilon absorbs float noise when comparing held and available quantities.
+const qcQtyEpsilon = 1e-6
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextQCHoldNumber generates "QC-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextQCHoldNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("QC-%d-", year)
+
+	lockKey := fmt.Sprintf("qc-hold-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire QC hold number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(hold_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM qc_holds
+		WHERE tenant_id = $2
+		  AND hold_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate QC hold number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// qcHoldDecision checks a hold still awaits its decision: only holds in quarantine take
+// inspections and are released or rejected (409 otherwise).
+func qcHoldDecision(hold *database.QCHold) *responses.InternalResponse {
+	if hold.Status == database.QCHoldQuarantine {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("La retención %s ya fue resuelta (%s)", hold.HoldNumber, hold.Status),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+	}
+}
+
+// lotHoldConflict checks a lot can be put in quarantine: rejected lots stay rejected, recalled
+// lots are already frozen by their recall and a lot has at most one open hold.
+func lotHoldConflict(lot *database.Lot) *responses.InternalResponse {
+	status := ""
+	if lot.Status != nil {
+		status = *lot.Status
+	}
+	switch status {
+	case database.QCHoldQuarantine:
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("El lote %s ya está en cuarentena", lot.LotNumber),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	case database.QCHoldRejected:
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("El lote %s fue rechazado por calidad", lot.LotNumber),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	case database.LotStatusRecalled:
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("El lote %s está en retiro de mercado", lot.LotNumber),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+	return nil
+}
+
+// buildQCHoldView assembles the hold view.
+func buildQCHoldView(hold database.QCHold, lotNumber *string, inspections []database.QCInspection, attachments []database.QCAttachment) *responses.QCHoldView {
+	if inspections == nil {
+		inspections = []database.QCInspection{}
+	}
+	if attachments == nil {
+		attachments = []database.QCAttachment{}
+	}
+	return &responses.QCHoldView{
+		QCHold:      hold,
+		LotNumber:   lotNumber,
+		Inspections: inspections,
+		Attachments: attachments,
+	}
+}
+
+// lockQCHold loads a tenant's hold FOR UPDATE.
+func lockQCHold(tx *gorm.DB, id, tenantID string) (*database.QCHold, *responses.InternalResponse, error) {
+	var hold database.QCHold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Retención de calidad no encontrada", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, fmt.Errorf("load QC hold: %w", err)
+	}
+	return &hold, nil, nil
+}
+
+// findHoldLot loads a tenant's (non archived) lot of sku FOR UPDATE.
+func findHoldLot(tx *gorm.DB, tenantID, sku, lotNumber string) (*database.Lot, *responses.InternalResponse, error) {
+	var lot database.Lot
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND lot_number = ? AND (status IS NULL OR status != 'archived')", tenantID, sku, lotNumber).
+		First(&lot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{
+				Message:    fmt.Sprintf("Lote %s del SKU %s no encontrado", lotNumber, sku),
+				Handled:    true,
+				StatusCode: responses.StatusNotFound,
+			}, nil
+		}
+		return nil, nil, fmt.Errorf("load lot %s: %w", lotNumber, err)
+	}
+	return &lot, nil, nil
+}
+
+// inventoryHoldParams describes an inventory hold to place on a locked inventory row.
+type inventoryHoldParams struct {
+	Qty           float64
+	Lot           *database.Lot
+	SerialNumbers []string
+	Reason        string
+	SourceType    *string
+	SourceID      *string
+}
+
+// placeInventoryHold blocks p.Qty of inv (locked by the caller) with a new hold. The quantity
+// must be available (not reserved nor already held) and, when the hold names a lot, present
+// in that lot at the location. Returns the hold ID.
+func placeInventoryHold(tx *gorm.DB, tenantID, userID string, inv *database.Inventory, p inventoryHoldParams) (string, *responses.InternalResponse, error) {
+	var available float64
+	if err := tx.Raw(`SELECT `+tools.AvailableQtySQL("i")+` FROM inventory i WHERE i.id = ?`, inv.ID).Scan(&available).Error; err != nil {
+		return "", nil, fmt.Errorf("read available qty of %s @ %s: %w", inv.SKU, inv.Location, err)
+	}
+	if p.Qty > available+qcQtyEpsilon {
+		return "", &responses.InternalResponse{
+			Message:    fmt.Sprintf("No hay %.3f uds disponibles de %s en %s para retener (disponible: %.3f)", p.Qty, inv.SKU, inv.Location, available),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}, nil
+	}
+
+	var lotID *string
+	if p.Lot != nil {
+		held, err := tools.HeldLots(tx, tenantID, inv.SKU, []string{p.Lot.LotNumber})
+		if err != nil {
+			return "", nil, err
+		}
+		if len(held) > 0 {
+			return "", &responses.InternalResponse{
+				Message:    fmt.Sprintf("El lote %s ya está retenido por calidad", p.Lot.LotNumber),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}, nil
+		}
+		var lotAvailable float64
+		if err := tx.Raw(`
+			SELECT COALESCE(SUM(il.quantity), 0) - `+tools.HeldInLotQtySQL("i", "l")+`
+			  FROM inventory i
+			  JOIN lots l ON l.id = ?
+			  LEFT JOIN inventory_lots il ON il.inventory_id = i.id AND il.lot_id = l.id
+			 WHERE i.id = ?
+			 GROUP BY i.id, l.id
+		`, p.Lot.ID, inv.ID).Scan(&lotAvailable).Error; err != nil {
+			return "", nil, fmt.Errorf("read lot qty of %s @ %s: %w", p.Lot.LotNumber, inv.Location, err)
+		}
+		if p.Qty > lotAvailable+qcQtyEpsilon {
+			return "", &responses.InternalResponse{
+				Message:    fmt.Sprintf("El lote %s solo tiene %.3f uds sin retener en %s", p.Lot.LotNumber, lotAvailable, inv.Location),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}, nil
+		}
+		lotID = &p.Lot.ID
+	}
+
+	if err := tx.Exec(`UPDATE inventory SET held_qty = held_qty + ?, updated_at = NOW() WHERE id = ?`, p.Qty, inv.ID).Error; err != nil {
+		return "", nil, fmt.Errorf("hold inventory %s @ %s: %w", inv.SKU, inv.Location, err)
+	}
+
+	number, err := nextQCHoldNumber(tx, tenantID)
+	if err != nil {
+		return "", nil, err
+	}
+	holdID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return "", nil, fmt.Errorf("generate QC hold id: %w", err)
+	}
+	serials := pq.StringArray{}
+	serials = append(serials, p.SerialNumbers...)
+	qty := p.Qty
+	location := inv.Location
+	hold := &database.QCHold{
+		ID:            holdID,
+		TenantID:      tenantID,
+		HoldNumber:    number,
+		Scope:         database.QCHoldScopeInventory,
+		SKU:           inv.SKU,
+		LotID:         lotID,
+		InventoryID:   &inv.ID,
+		Location:      &location,
+		Qty:           &qty,
+		SerialNumbers: serials,
+		Status:        database.QCHoldQuarantine,
+		Reason:        p.Reason,
+		SourceType:    p.SourceType,
+		SourceID:      p.SourceID,
+	}
+	if userID != "" {
+		hold.CreatedBy = &userID
+	}
+	if err := tx.Create(hold).Error; err != nil {
+		return "", nil, fmt.Errorf("create QC hold: %w", err)
+	}
+	return holdID, nil, nil
+}
+
+// setHeldSerials applies a QC decision (database.SerialInStock or database.SerialScrapped) to
+// the serials of an inventory hold. On release only units back from a return go in stock;
+// units already in the warehouse keep their status, reservations included. Rejected units are
+// scrapped and unlinked from the inventory. A serial the decision cannot move is logged.
+func setHeldSerials(tx *gorm.DB, hold *database.QCHold, status, userID string) error {
+	ev := serialEvent{DocumentType: "qc_hold", DocumentID: hold.ID, DocumentNumber: hold.HoldNumber, UserID: userID}
+	if hold.Location != nil {
+		ev.Location = *hold.Location
+	}
+	for _, sn := range hold.SerialNumbers {
+		serial, err := lockSerialByNumber(tx, hold.TenantID, hold.SKU, sn)
+		if err != nil {
+			return err
+		}
+		if serial == nil {
+			continue
+		}
+		if status == database.SerialInStock && serial.Status != database.SerialReturned {
+			continue
+		}
+		resp, err := transitionSerial(tx, serial, status, ev)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			log.Warn().Str("serial", sn).Str("hold", hold.HoldNumber).Msg(resp.Message)
+			continue
+		}
+		if status == database.SerialScrapped {
+			if err := tx.Where("serial_id = ?", serial.ID).Delete(&database.InventorySerial{}).Error; err != nil {
+				return fmt.Errorf("unlink serial %s: %w", sn, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rejectHeldInventory writes off the quantity of a rejected inventory hold: the inventory row
+// (and its lot) lose the units and a qc_reject movement consumes their cost layers.
+func rejectHeldInventory(tx *gorm.DB, hold *database.QCHold, userID string) (string, error) {
+	var inv database.Inventory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *hold.InventoryID).First(&inv).Error; err != nil {
+		return "", fmt.Errorf("load held inventory: %w", err)
+	}
+	qty := *hold.Qty
+	beforeQty := inv.Quantity
+	afterQty := inv.Quantity - qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", inv.ID).Updates(map[string]interface{}{
+		"quantity":   afterQty,
+		"held_qty":   gorm.Expr("GREATEST(0, held_qty - ?)", qty),
+		"updated_at": tools.GetCurrentTime(),
+	}).Error; err != nil {
+		return "", fmt.Errorf("write off held inventory %s @ %s: %w", inv.SKU, inv.Location, err)
+	}
+
+	if hold.LotID != nil {
+		if err := tx.Exec(`
+			UPDATE inventory_lots SET quantity = GREATEST(0, quantity - ?)
+			 WHERE inventory_id = ? AND lot_id = ?
+		`, qty, inv.ID, *hold.LotID).Error; err != nil {
+			return "", fmt.Errorf("write off held inventory lot: %w", err)
+		}
+		if err := tx.Exec(`UPDATE lots SET quantity = GREATEST(0, quantity - ?), updated_at = NOW() WHERE id = ?`, qty, *hold.LotID).Error; err != nil {
+			return "", fmt.Errorf("write off held lot: %w", err)
+		}
+	}
+
+	// The inventory price is in the article's currency; cost the write-off in the base currency.
+	priceCost, err := basePriceCost(tx, inv.SKU, inv.UnitPrice)
+	if err != nil {
+		return "", err
+	}
+	movID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return "", fmt.Errorf("generate QC movement id: %w", err)
+	}
+	refType := "qc_hold"
+	mov := &database.InventoryMovement{
+		ID:             movID,
+		SKU:            inv.SKU,
+		Location:       inv.Location,
+		MovementType:   database.MovementQCReject,
+		Quantity:       -qty,
+		RemainingStock: afterQty,
+		Reason:         tools.StrPtr("QC hold " + hold.HoldNumber + " rejected"),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &refType,
+		ReferenceID:    &hold.ID,
+		LotID:          hold.LotID,
+		UnitCost:       &priceCost,
+		BeforeQty:      &beforeQty,
+		AfterQty:       &afterQty,
+		UserID:         &userID,
+	}
+	if err := tx.Create(mov).Error; err != nil {
+		return "", fmt.Errorf("create QC reject movement: %w", err)
+	}
+	if err := consumeCostLayers(tx, mov, priceCost); err != nil {
+		return "", err
+	}
+	if err := setHeldSerials(tx, hold, database.SerialScrapped, userID); err != nil {
+		return "", err
+	}
+	return movID, nil
+}
+
+// decideQCHold releases or rejects a hold in quarantine (target is QCHoldReleased or
+// QCHoldRejected).
+func (r *QCHoldsRepository) decideQCHold(id, tenantID, userID, target string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		hold, resp, err := lockQCHold(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		if resp := qcHoldDecision(hold); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{"status": target, "decided_at": now, "updated_at": now}
+		if userID != "" {
+			updates["decided_by"] = userID
+		}
+		if req != nil && req.Notes != nil {
+			updates["decision_notes"] = *req.Notes
+		}
+
+		switch {
+		case hold.Scope == database.QCHoldScopeLot:
+			// A recall opened meanwhile keeps the lot frozen whatever the QC decision.
+			if err := tx.Model(&database.Lot{}).Where("id = ? AND status = ?", *hold.LotID, database.QCHoldQuarantine).
+				Updates(map[string]interface{}{"status": target, "updated_at": now}).Error; err != nil {
+				return fmt.Errorf("update held lot: %w", err)
+			}
+		case target == database.QCHoldReleased:
+			if err := tx.Exec(`UPDATE inventory SET held_qty = GREATEST(0, held_qty - ?), updated_at = NOW() WHERE id = ?`,
+				*hold.Qty, *hold.InventoryID).Error; err != nil {
+				return fmt.Errorf("release held inventory: %w", err)
+			}
+			if err := setHeldSerials(tx, hold, database.SerialInStock, userID); err != nil {
+				return err
+			}
+		default:
+			movID, err := rejectHeldInventory(tx, hold, userID)
+			if err != nil {
+				return err
+			}
+			updates["movement_id"] = movID
+		}
+
+		if err := tx.Model(&database.QCHold{}).Where("id = ?", hold.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update QC hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return nil, resp
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al resolver la retención de calidad"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetHold(id, tenantID)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *QCHoldsRepository) ListHolds(tenantID string, status, scope, sku *string, limit, offset int) ([]database.QCHold, *responses.InternalResponse) {
+	query := r.DB.Model(&database.QCHold{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+	if scope != nil && *scope != "" {
+		query = query.Where("scope = ?", *scope)
+	}
+	if sku != nil && *sku != "" {
+		query = query.Where("sku = ?", *sku)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var holds []database.QCHold
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&holds).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las retenciones de calidad"}
+	}
+	return holds, nil
+}
+
+func (r *QCHoldsRepository) GetHold(id, tenantID string) (*responses.QCHoldView, *responses.InternalResponse) {
+	var hold database.QCHold
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Retención de calidad no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la retención de calidad"}
+	}
+
+	var lotNumber *string
+	if hold.LotID != nil {
+		var lot database.Lot
+		if err := r.DB.Select("lot_number").Where("id = ?", *hold.LotID).First(&lot).Error; err == nil {
+			lotNumber = &lot.LotNumber
+		}
+	}
+	var inspections []database.QCInspection
+	if err := r.DB.Where("hold_id = ?", hold.ID).Order("inspected_at ASC, id ASC").Find(&inspections).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las inspecciones de la retención"}
+	}
+	var attachments []database.QCAttachment
+	if err := r.DB.Where("hold_id = ?", hold.ID).Order("created_at ASC, id ASC").Find(&attachments).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener los adjuntos de la retención"}
+	}
+	return buildQCHoldView(hold, lotNumber, inspections, attachments), nil
+}
+
+func (r *QCHoldsRepository) CreateHold(tenantID, userID string, req *requests.CreateQCHoldRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	var holdID string
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var lot *database.Lot
+		if req.LotNumber != nil {
+			var resp *responses.InternalResponse
+			var err error
+			lot, resp, err = findHoldLot(tx, tenantID, req.SKU, *req.LotNumber)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+		}
+
+		if req.Scope == database.QCHoldScopeInventory {
+			var inv database.Inventory
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, req.SKU, *req.Location).
+				First(&inv).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					*handledResp = responses.InternalResponse{
+						Message:    fmt.Sprintf("No hay inventario de %s en %s", req.SKU, *req.Location),
+						Handled:    true,
+						StatusCode: responses.StatusNotFound,
+					}
+					return nil
+				}
+				return fmt.Errorf("load inventory: %w", err)
+			}
+			id, resp, err := placeInventoryHold(tx, tenantID, userID, &inv, inventoryHoldParams{
+				Qty:        *req.Qty,
+				Lot:        lot,
+				Reason:     req.Reason,
+				SourceType: req.SourceType,
+				SourceID:   req.SourceID,
+			})
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			holdID = id
+			return nil
+		}
+
+		if resp := lotHoldConflict(lot); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		if err := tx.Model(&database.Lot{}).Where("id = ?", lot.ID).
+			Updates(map[string]interface{}{"status": database.QCHoldQuarantine, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+			return fmt.Errorf("quarantine lot: %w", err)
+		}
+
+		number, err := nextQCHoldNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		holdID, err = tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate QC hold id: %w", err)
+		}
+		hold := &database.QCHold{
+			ID:            holdID,
+			TenantID:      tenantID,
+			HoldNumber:    number,
+			Scope:         database.QCHoldScopeLot,
+			SKU:           req.SKU,
+			LotID:         &lot.ID,
+			SerialNumbers: pq.StringArray{},
+			Status:        database.QCHoldQuarantine,
+			Reason:        req.Reason,
+			SourceType:    req.SourceType,
+			SourceID:      req.SourceID,
+		}
+		if userID != "" {
+			hold.CreatedBy = &userID
+		}
+		if err := tx.Create(hold).Error; err != nil {
+			return fmt.Errorf("create QC hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la retención de calidad"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetHold(holdID, tenantID)
+}
+
+func (r *QCHoldsRepository) RecordInspection(id, tenantID, userID string, req *requests.RecordQCInspectionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		hold, resp, err := lockQCHold(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		// Inspections are recorded while the hold awaits its decision.
+		if resp := qcHoldDecision(hold); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		inspectionID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate QC inspection id: %w", err)
+		}
+		inspection := &database.QCInspection{
+			ID:           inspectionID,
+			HoldID:       hold.ID,
+			Result:       req.Result,
+			Notes:        req.Notes,
+			Measurements: req.Measurements,
+			InspectedAt:  tools.GetCurrentTime(),
+		}
+		if req.InspectedAt != nil {
+			inspection.InspectedAt = *req.InspectedAt
+		}
+		if userID != "" {
+			inspection.InspectedBy = &userID
+		}
+		if err := tx.Create(inspection).Error; err != nil {
+			return fmt.Errorf("create QC inspection: %w", err)
+		}
+		return tx.Model(&database.QCHold{}).Where("id = ?", hold.ID).Update("updated_at", tools.GetCurrentTime()).Error
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al registrar la inspección"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetHold(id, tenantID)
+}
+
+func (r *QCHoldsRepository) AddAttachment(id, tenantID string, attachment *database.QCAttachment) (*database.QCAttachment, *responses.InternalResponse) {
+	var hold database.QCHold
+	if err := r.DB.Select("id").Where("id = ? AND tenant_id = ?", id, tenantID).First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Retención de calidad no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la retención de calidad"}
+	}
+	if attachment.InspectionID != nil {
+		var count int64
+		if err := r.DB.Model(&database.QCInspection{}).Where("id = ? AND hold_id = ?", *attachment.InspectionID, hold.ID).Count(&count).Error; err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la inspección"}
+		}
+		if count == 0 {
+			return nil, &responses.InternalResponse{Message: "La inspección no pertenece a la retención", Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+	}
+
+	attachmentID, err := tools.GenerateNanoid(r.DB)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al generar el identificador del adjunto"}
+	}
+	attachment.ID = attachmentID
+	attachment.HoldID = hold.ID
+	attachment.FileName = strings.TrimSpace(attachment.FileName)
+	if err := r.DB.Create(attachment).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al guardar el adjunto"}
+	}
+	return attachment, nil
+}
+
+func (r *QCHoldsRepository) GetAttachment(id, tenantID, attachmentID string) (*database.QCAttachment, *responses.InternalResponse) {
+	var attachment database.QCAttachment
+	if err := r.DB.Table("qc_attachments a").
+		Select("a.*").
+		Joins("JOIN qc_holds h ON h.id = a.hold_id").
+		Where("a.id = ? AND a.hold_id = ? AND h.tenant_id = ?", attachmentID, id, tenantID).
+		Take(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Adjunto no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el adjunto"}
+	}
+	return &attachment, nil
+}
+
+func (r *QCHoldsRepository) ReleaseHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	return r.decideQCHold(id, tenantID, userID, database.QCHoldReleased, req)
+}
+
+func (r *QCHoldsRepository) RejectHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	return r.decideQCHold(id, tenantID, userID, database.QCHoldRejected, req)
+}
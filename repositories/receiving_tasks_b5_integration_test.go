@@ -12,6 +12,7 @@ import (
 
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,7 +68,7 @@ func TestReceivingB5_CompleteFullTask_NoDifferences(t *testing.T) {
 	taskID := seedReceivingTask(t, db, userID, "in_progress", items)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
 	assert.Nil(t, resp, "CompleteFullTask should succeed when received == expected")
 
 	task := getReceivingTask(t, db, taskID)
@@ -90,7 +91,7 @@ func TestReceivingB5_CompleteFullTask_Shortage(t *testing.T) {
 	taskID := seedReceivingTask(t, db, userID, "in_progress", items)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
 	assert.Nil(t, resp, "CompleteFullTask should succeed even with shortage")
 
 	task := getReceivingTask(t, db, taskID)
@@ -98,27 +99,99 @@ func TestReceivingB5_CompleteFullTask_Shortage(t *testing.T) {
 	assert.NotNil(t, task.CompletedAt)
 }
 
-// TestReceivingB5_CompleteFullTask_OverReceipt: received > expected → "completed_with_differences"
-// Decisión: over-receipts se aceptan pero se marcan como diferencia.
-func TestReceivingB5_CompleteFullTask_OverReceipt(t *testing.T) {
+// linkReceivingTaskToPO creates a submitted purchase order with one line for sku in the
+// task's tenant and links it to the receiving task. Returns the PO id.
+func linkReceivingTaskToPO(t *testing.T, db *gorm.DB, taskID, userID, sku string, expectedQty float64) string {
+	t.Helper()
+	var tenantID string
+	require.NoError(t, db.Raw("SELECT tenant_id FROM receiving_tasks WHERE id = ?", taskID).Scan(&tenantID).Error)
+	supplierID := seedSupplier(t, db, tenantID)
+	poID, err := tools.GenerateNanoid(db)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`
+		INSERT INTO purchase_orders (id, tenant_id, po_number, supplier_id, status, created_by, receiving_task_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 'submitted', ?, ?, NOW(), NOW())`,
+		poID, tenantID, "PO-"+poID[:6], supplierID, userID, taskID).Error)
+	require.NoError(t, db.Exec(`
+		INSERT INTO purchase_order_items (purchase_order_id, article_sku, expected_qty)
+		VALUES (?, ?, ?)`, poID, sku, expectedQty).Error)
+	require.NoError(t, db.Exec("UPDATE receiving_tasks SET purchase_order_id = ? WHERE id = ?", poID, taskID).Error)
+	return poID
+}
+
+// getPOLineQty reads received_qty and discrepancy of a purchase order line.
+func getPOLineQty(t *testing.T, db *gorm.DB, poID, sku string) (received, discrepancy float64) {
+	t.Helper()
+	var row struct {
+		ReceivedQty float64
+		Discrepancy float64
+	}
+	require.NoError(t, db.Raw(`
+		SELECT received_qty, discrepancy FROM purchase_order_items
+		WHERE purchase_order_id = ? AND article_sku = ?`, poID, sku).Scan(&row).Error)
+	return row.ReceivedQty, row.Discrepancy
+}
+
+// TestReceivingB5_CompleteFullTask_OverReceiptRejected: a pending line counted above expected
+// with no allowance configured → 409, nothing is received and the PO line is untouched.
+func TestReceivingB5_CompleteFullTask_OverReceiptRejected(t *testing.T) {
 	db, cleanup := setupGORMTestDB(t)
 	defer cleanup()
 
 	userID := seedUser(t, db)
 	seedArticle(t, db, "SKU-B5-OVER")
 
-	received := tools.IntToPtr(15) // received 15, expected 10
 	items := []requests.ReceivingTaskItemRequest{
-		{SKU: "SKU-B5-OVER", ExpectedQuantity: 10, Location: "LOC-1", ReceivedQuantity: received, Status: tools.StrPtr("partial")},
+		{SKU: "SKU-B5-OVER", ExpectedQuantity: 10, Location: "LOC-1", ReceivedQuantity: tools.IntToPtr(15)},
 	}
 	taskID := seedReceivingTask(t, db, userID, "in_progress", items)
+	poID := linkReceivingTaskToPO(t, db, taskID, userID, "SKU-B5-OVER", 10)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
-	assert.Nil(t, resp, "CompleteFullTask should accept over-receipts")
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
+	require.NotNil(t, resp, "over-receipt without override must be rejected")
+	assert.True(t, resp.Handled)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+
+	task := getReceivingTask(t, db, taskID)
+	assert.Equal(t, "in_progress", task.Status)
+
+	var invCount int64
+	db.Model(&database.Inventory{}).Where("sku = ?", "SKU-B5-OVER").Count(&invCount)
+	assert.Zero(t, invCount, "no stock enters on a rejected over-receipt")
+
+	received, discrepancy := getPOLineQty(t, db, poID, "SKU-B5-OVER")
+	assert.Equal(t, 0.0, received)
+	assert.Equal(t, 10.0, discrepancy)
+}
+
+// TestReceivingB5_CompleteFullTask_OverReceiptOverride: same over-receipt with a supervisor
+// override → accepted as "completed_with_differences"; the PO line records the full 15 (no
+// clamp to expected), so discrepancy goes negative.
+func TestReceivingB5_CompleteFullTask_OverReceiptOverride(t *testing.T) {
+	db, cleanup := setupGORMTestDB(t)
+	defer cleanup()
+
+	userID := seedUser(t, db)
+	seedArticle(t, db, "SKU-B5-OVER")
+
+	items := []requests.ReceivingTaskItemRequest{
+		{SKU: "SKU-B5-OVER", ExpectedQuantity: 10, Location: "LOC-1", ReceivedQuantity: tools.IntToPtr(15)},
+	}
+	taskID := seedReceivingTask(t, db, userID, "in_progress", items)
+	poID := linkReceivingTaskToPO(t, db, taskID, userID, "SKU-B5-OVER", 10)
+
+	repo := newReceivingRepo(db)
+	override := &requests.OverReceiptOverride{Reason: "Proveedor envió caja adicional"}
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, override)
+	require.Nil(t, resp, "supervisor override accepts the over-receipt")
 
 	task := getReceivingTask(t, db, taskID)
 	assert.Equal(t, "completed_with_differences", task.Status)
+
+	received, discrepancy := getPOLineQty(t, db, poID, "SKU-B5-OVER")
+	assert.Equal(t, 15.0, received)
+	assert.Equal(t, -5.0, discrepancy)
 }
 
 // TestReceivingB5_CompleteFullTask_MixedDiff: multiple items, one differs → "completed_with_differences"
@@ -141,7 +214,7 @@ func TestReceivingB5_CompleteFullTask_MixedDiff(t *testing.T) {
 	taskID := seedReceivingTask(t, db, userID, "in_progress", items)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
 	assert.Nil(t, resp)
 
 	task := getReceivingTask(t, db, taskID)
@@ -162,7 +235,7 @@ func TestReceivingB5_CompleteFullTask_InvalidTransition(t *testing.T) {
 	taskID := seedReceivingTask(t, db, userID, "open", items)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
 	require.NotNil(t, resp, "should reject completion from 'open' state")
 	assert.True(t, resp.Handled)
 	assert.Contains(t, resp.Message, "Transición inválida")
@@ -182,7 +255,7 @@ func TestReceivingB5_CompleteFullTask_AlreadyCompleted(t *testing.T) {
 	taskID := seedReceivingTask(t, db, userID, "completed", items)
 
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteFullTask(taskID, "LOC-1", userID)
+	resp := repo.CompleteFullTask(taskID, "LOC-1", userID, nil)
 	require.NotNil(t, resp, "should reject double-completion")
 	assert.True(t, resp.Handled)
 }
@@ -214,7 +287,7 @@ func TestReceivingB5_CompleteReceivingLine_AutoClose_NoDiff(t *testing.T) {
 		Location:         "LOC-2",
 	}
 	repo := newReceivingRepo(db)
-	resp := repo.CompleteReceivingLine(taskID, "LOC-2", userID, lineItem)
+	resp := repo.CompleteReceivingLine(taskID, "LOC-2", userID, lineItem, nil)
 	assert.Nil(t, resp)
 
 	task := getReceivingTask(t, db, taskID)
@@ -255,7 +328,7 @@ func TestReceivingB5_CompleteReceivingLine_AutoClose_WithDiff(t *testing.T) {
 	repo := newReceivingRepo(db)
 	_ = lineItem
 	// Adjusted: verify no-diff case with single item
-	resp := repo.CompleteReceivingLine(taskID, "LOC-1", userID, lineItem)
+	resp := repo.CompleteReceivingLine(taskID, "LOC-1", userID, lineItem, nil)
 	assert.Nil(t, resp)
 
 	task := getReceivingTask(t, db, taskID)
@@ -22,9 +22,31 @@ import (
 
 type ReceivingTasksRepository struct {
 	DB               *gorm.DB
+	AuditService     *services.AuditService         // optional: audit supervisor over-receipt overrides
 	NotificationsSvc *services.NotificationsService // optional: emit task events
 }
 
+// overReceiptLine is the audit payload for a line received above the over-receipt allowance.
+type overReceiptLine struct {
+	SKU          string  `json:"sku"`
+	ExpectedQty  float64 `json:"expected_qty"`
+	ReceivedQty  float64 `json:"received_qty"`
+	AllowancePct float64 `json:"allowance_pct"`
+}
+
+// auditOverReceipt records a supervisor override of the over-receipt allowance.
+func (r *ReceivingTasksRepository) auditOverReceipt(taskID, location, userId string, override *requests.OverReceiptOverride, lines []overReceiptLine) {
+	if r.AuditService == nil || override == nil || len(lines) == 0 {
+		return
+	}
+	newVal, _ := json.Marshal(map[string]interface{}{
+		"reason":   override.Reason,
+		"location": location,
+		"lines":    lines,
+	})
+	r.AuditService.Log(context.Background(), &userId, tools.ActionOverride, tools.ResourceReceivingTask, taskID, nil, newVal, "", "")
+}
+
 // updatePOFromReceivingItems updates purchase_order_items.received_qty/rejected_qty for any
 // receiving task that is linked to a PO (purchase_order_id IS NOT NULL).
 // Called at the end of CompleteFullTask and CompleteReceivingLine transactions (PO3 auto-link).
@@ -46,14 +68,16 @@ func updatePOFromReceivingItems(tx *gorm.DB, purchaseOrderID string, items []req
 			continue
 		}
 
-		// Clamp: do not exceed expected_qty in purchase_order_items.
-		// Use raw SQL to stay inside the tx and leverage GENERATED discrepancy column.
+		// No clamp to expected_qty: over-receipts reach this point only within the tenant
+		// allowance or with a supervisor override, and must show up in the GENERATED
+		// discrepancy column (negative = more received than ordered).
+		// Use raw SQL to stay inside the tx.
 		if err := tx.Exec(`
 			UPDATE purchase_order_items
-			SET received_qty = LEAST(received_qty + ?, expected_qty),
-			    rejected_qty = LEAST(rejected_qty + ?, expected_qty - LEAST(received_qty + ?, expected_qty))
+			SET received_qty = received_qty + ?,
+			    rejected_qty = rejected_qty + ?
 			WHERE purchase_order_id = ? AND article_sku = ?
-		`, receivedQty, rejectedQty, receivedQty, purchaseOrderID, it.SKU).Error; err != nil {
+		`, receivedQty, rejectedQty, purchaseOrderID, it.SKU).Error; err != nil {
 			return fmt.Errorf("update PO item qty for sku %s: %w", it.SKU, err)
 		}
 	}
@@ -368,6 +392,7 @@ func (r *ReceivingTasksRepository) CreateReceivingTask(userId string, tenantID s
 
 		// 1) Validate items
 		articleCache := make(map[string]database.Article)
+		conv := tools.NewUoMConverter(tx, tenantID)
 
 		for idx := range items {
 			sku := items[idx].SKU
@@ -387,6 +412,12 @@ func (r *ReceivingTasksRepository) CreateReceivingTask(userId string, tenantID s
 				articleCache[sku] = art
 			}
 
+			// Lines entered in another presentation are kept in base units.
+			if resp := convertReceivingItemUoM(conv, &items[idx]); resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+
 			// If tracked by lot: sum of lots must equal expected
 			if art.TrackByLot {
 				for i := 0; i < len(items[idx].LotNumbers); i++ {
@@ -468,19 +499,34 @@ func (r *ReceivingTasksRepository) CreateReceivingTask(userId string, tenantID s
 				}
 			}
 
-			// Serials
+			// Serials — declared on the task, they stay 'received' until put away.
 			if art.TrackBySerial && len(items[i].SerialNumbers) > 0 {
 				for j := 0; j < len(items[i].SerialNumbers); j++ {
+					serialID, err := tools.GenerateNanoid(tx)
+					if err != nil {
+						return fmt.Errorf("generate serial id: %w", err)
+					}
 					serial := database.Serial{
+						ID:           serialID,
+						TenantID:     tenantID,
 						SerialNumber: items[i].SerialNumbers[j].SerialNumber,
 						SKU:          sku,
-						Status:       "available",
+						Status:       database.SerialReceived,
 						CreatedAt:    tools.GetCurrentTime(),
 						UpdatedAt:    tools.GetCurrentTime(),
 					}
 
-					if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&serial).Error; err != nil {
-						return fmt.Errorf("create serial %s: %w", serial.SerialNumber, err)
+					result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&serial)
+					if result.Error != nil {
+						return fmt.Errorf("create serial %s: %w", serial.SerialNumber, result.Error)
+					}
+					if result.RowsAffected == 0 {
+						continue
+					}
+					if err := recordSerialHistory(tx, &serial, nil, database.SerialReceived, serialEvent{
+						DocumentType: "receiving_task", DocumentID: id, DocumentNumber: taskID, UserID: userId,
+					}); err != nil {
+						return err
 					}
 				}
 			}
@@ -580,6 +626,20 @@ func (r *ReceivingTasksRepository) UpdateReceivingTask(id string, data map[strin
 					*handledResp = responses.InternalResponse{Error: err, Message: "Formato de items inválido", Handled: true}
 					return nil
 				}
+				// Lines newly entered in another presentation are converted to base units.
+				var typed []requests.ReceivingTaskItemRequest
+				if json.Unmarshal(b, &typed) == nil {
+					changed, resp := convertReceivingItemsUoM(tools.NewUoMConverter(tx, task.TenantID), typed)
+					if resp != nil {
+						handledResp = resp
+						return errors.New(resp.Message)
+					}
+					if changed {
+						if b, err = json.Marshal(typed); err != nil {
+							return fmt.Errorf("marshal items: %w", err)
+						}
+					}
+				}
 				clean["items"] = b
 			}
 		}
@@ -592,6 +652,9 @@ func (r *ReceivingTasksRepository) UpdateReceivingTask(id string, data map[strin
 		return nil
 	})
 
+	if handledResp != nil {
+		return handledResp
+	}
 	if err != nil {
 		return &responses.InternalResponse{Error: err, Message: "Error en la transacción"}
 	}
@@ -844,8 +907,12 @@ func (r *ReceivingTasksRepository) ExportReceivingTaskToExcel(tenantID string) (
 	return buf.Bytes(), nil
 }
 
-func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId string) *responses.InternalResponse {
+// CompleteFullTask receives every pending line at its expected quantity, or at its recorded
+// received_qty when a higher count was captured on the task. Counts above the tenant's
+// over-receipt allowance are rejected (409) unless override carries a supervisor reason.
+func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	handledResp := &responses.InternalResponse{}
+	var overridden []overReceiptLine
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		// Get the task
@@ -882,17 +949,76 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 			return nil
 		}
 
+		isPending := func(it requests.ReceivingTaskItemRequest) bool {
+			return it.Status == nil || (*it.Status != "completed" && *it.Status != "partial")
+		}
+		// receiveQty is the quantity a pending line enters stock with: the expected quantity,
+		// or a higher count already recorded on the line (over-receipt).
+		receiveQty := func(it requests.ReceivingTaskItemRequest) int {
+			if it.ReceivedQuantity != nil && *it.ReceivedQuantity > it.ExpectedQuantity {
+				return *it.ReceivedQuantity
+			}
+			return it.ExpectedQuantity
+		}
+
+		// Validate over-receipts before touching stock so a rejection leaves nothing half-applied.
+		allowancePct := -1.0
+		for _, it := range items {
+			if !isPending(it) || receiveQty(it) <= it.ExpectedQuantity {
+				continue
+			}
+			if allowancePct < 0 {
+				pct, err := tenantAllowancePct(tx, task.TenantID, responses.AllowanceOverReceipt)
+				if err != nil {
+					return err
+				}
+				allowancePct = pct
+			}
+			received, expected := float64(receiveQty(it)), float64(it.ExpectedQuantity)
+			if !exceedsAllowance(received, expected, allowancePct) {
+				continue
+			}
+			if override == nil {
+				*handledResp = *allowanceExceeded(responses.AllowanceOverReceipt, it.SKU, location, expected, allowancePct, received)
+				return nil
+			}
+			overridden = append(overridden, overReceiptLine{SKU: it.SKU, ExpectedQty: expected, ReceivedQty: received, AllowancePct: allowancePct})
+		}
+
+		// Capacity: every pending line lands in the same location, so check them together.
+		var additions []tools.CapacityAddition
+		for _, it := range items {
+			if isPending(it) {
+				additions = append(additions, tools.CapacityAddition{SKU: it.SKU, Quantity: float64(receiveQty(it))})
+			}
+		}
+		if len(additions) > 0 {
+			exceeded, err := tools.CheckLocationCapacity(tx, task.TenantID, location, additions)
+			if err != nil {
+				return err
+			}
+			if exceeded != nil {
+				*handledResp = *tools.CapacityExceededResponse(exceeded)
+				return nil
+			}
+		}
+
+		// Lines received by this call; only these are applied to the linked PO.
+		var receivedNow []requests.ReceivingTaskItemRequest
+
 		// Create inventory
 		for i := 0; i < len(items); i++ {
 			// Skip if item is already completed or closed
-			if items[i].Status != nil && (*items[i].Status == "completed" || *items[i].Status == "partial") {
+			if !isPending(items[i]) {
 				continue
 			}
 
 			sku := items[i].SKU
+			lineQty := receiveQty(items[i])
 
 			items[i].Status = tools.StrPtr("completed")
-			items[i].ReceivedQuantity = tools.IntToPtr(int(items[i].ExpectedQuantity))
+			items[i].ReceivedQuantity = tools.IntToPtr(lineQty)
+			receivedNow = append(receivedNow, items[i])
 
 			var article database.Article
 			if err := tx.Where("sku = ?", sku).First(&article).Error; err != nil {
@@ -902,17 +1028,28 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 				return fmt.Errorf("find article %s: %w", sku, err)
 			}
 
+			// Lines flagged generate_numbers get their lot / serial numbers from the series.
+			hadLots := len(items[i].LotNumbers) > 0
+			if err := generateReceivingNumbers(tx, task.TenantID, &article, &items[i], lineQty); err != nil {
+				return err
+			}
+			if !hadLots && len(items[i].LotNumbers) > 0 {
+				if err := createGeneratedLot(tx, task.TenantID, &article, items[i].LotNumbers[0]); err != nil {
+					return err
+				}
+			}
+
 			var inventory database.Inventory
 
 			inventoryCount := int64(0)
 
-			err := tx.Model(&database.Inventory{}).Where("sku = ? AND location = ?", sku, location).Count(&inventoryCount).Error
+			err := tx.Model(&database.Inventory{}).Where("tenant_id = ? AND sku = ? AND location = ?", task.TenantID, sku, location).Count(&inventoryCount).Error
 
 			if err != nil {
 				return fmt.Errorf("check inventory for SKU %s and location %s: %w", sku, location, err)
 			}
 
-			itemQty := tools.IntToFloat64(items[i].ExpectedQuantity)
+			itemQty := tools.IntToFloat64(lineQty)
 			var beforeQty float64
 
 			if inventoryCount == 0 {
@@ -922,6 +1059,7 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 				}
 				beforeQty = 0
 				inventory.ID = invID
+				inventory.TenantID = task.TenantID
 				inventory.SKU = sku
 				inventory.Name = article.Name
 				inventory.Description = article.Description
@@ -937,7 +1075,7 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 					return errors.New("failed to create inventory")
 				}
 			} else {
-				if err := tx.First(&inventory, "sku = ? AND location = ?", sku, location).Error; err != nil {
+				if err := tx.First(&inventory, "tenant_id = ? AND sku = ? AND location = ?", task.TenantID, sku, location).Error; err != nil {
 					return fmt.Errorf("find inventory for SKU %s and location %s: %w", sku, location, err)
 				}
 
@@ -967,6 +1105,10 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 			}
 			afterQty := inventory.Quantity
 			refType := "receiving_task"
+			unitCost, err := receiptUnitCost(tx, task.PurchaseOrderID, sku, inventory.UnitPrice)
+			if err != nil {
+				return err
+			}
 			mov := &database.InventoryMovement{
 				ID:             movID,
 				SKU:            sku,
@@ -980,7 +1122,7 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 				ReferenceType:  &refType,
 				ReferenceID:    &id,
 				LotID:          movLotID,
-				UnitCost:       inventory.UnitPrice,
+				UnitCost:       &unitCost,
 				BeforeQty:      &beforeQty,
 				AfterQty:       &afterQty,
 				UserID:         &userId,
@@ -989,35 +1131,31 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 			if err := tx.Create(mov).Error; err != nil {
 				return fmt.Errorf("create inventory movement: %w", err)
 			}
+			if err := recordCostLayer(tx, mov); err != nil {
+				return err
+			}
 
 			if article.TrackBySerial && items[i].SerialNumbers != nil {
-				// Check if given serials count matches expected quantity
-				if len(items[i].SerialNumbers) != items[i].ExpectedQuantity {
+				// Check if given serials count matches the received quantity
+				if len(items[i].SerialNumbers) != lineQty {
 					// If not, then this task can't be completed fully
-					*handledResp = responses.InternalResponse{Message: fmt.Sprintf("Serial numbers count (%d) does not match expected quantity (%d) for SKU %s", len(items[i].SerialNumbers), items[i].ExpectedQuantity, sku), Handled: true}
+					*handledResp = responses.InternalResponse{Message: fmt.Sprintf("Serial numbers count (%d) does not match expected quantity (%d) for SKU %s", len(items[i].SerialNumbers), lineQty, sku), Handled: true}
 					return nil
 				}
 
 				for k := 0; k < len(items[i].SerialNumbers); k++ {
 					serial := items[i].SerialNumbers[k]
 
-					// Check if serial was created before
-					var serialItem database.Serial
-
-					if err := tx.Where("serial_number = ? AND sku = ?", serial.SerialNumber, items[i].SKU).First(&serialItem).Error; err != nil {
-						if errors.Is(err, gorm.ErrRecordNotFound) {
-							// Create new serial
-							serialItem = database.Serial{
-								SerialNumber: serial.SerialNumber,
-								SKU:          items[i].SKU,
-								CreatedAt:    tools.GetCurrentTime(),
-								UpdatedAt:    tools.GetCurrentTime(),
-								Status:       "available",
-							}
-							if err := tx.Create(&serialItem).Error; err != nil {
-								return errors.New("failed to create serial")
-							}
-						}
+					// Serial declared on the task (or new) goes in stock.
+					serialItem, resp, err := receiveSerial(tx, task.TenantID, items[i].SKU, serial.SerialNumber, serialEvent{
+						Location: items[i].Location, DocumentType: "receiving_task", DocumentID: task.ID, DocumentNumber: task.TaskID, UserID: userId,
+					})
+					if err != nil {
+						return err
+					}
+					if resp != nil {
+						*handledResp = *resp
+						return nil
 					}
 
 					inventorySerial := &database.InventorySerial{
@@ -1046,9 +1184,9 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 					totalLotQty += lot.Quantity
 				}
 
-				if totalLotQty != float64(items[i].ExpectedQuantity) {
+				if totalLotQty != float64(lineQty) {
 					// If not, then this task can't be completed fully
-					*handledResp = responses.InternalResponse{Message: fmt.Sprintf("La suma de las cantidades de lotes (%.2f) no coincide con la cantidad esperada (%d) para SKU %s", totalLotQty, items[i].ExpectedQuantity, sku), Handled: true}
+					*handledResp = responses.InternalResponse{Message: fmt.Sprintf("La suma de las cantidades de lotes (%.2f) no coincide con la cantidad esperada (%d) para SKU %s", totalLotQty, lineQty, sku), Handled: true}
 
 					return nil
 				}
@@ -1149,8 +1287,9 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 		}
 
 		// PO3 auto-link: if this receiving task was generated by a PO, update PO item qtys.
+		// Lines completed earlier through CompleteReceivingLine already updated the PO.
 		if task.PurchaseOrderID != nil && *task.PurchaseOrderID != "" {
-			if err := updatePOFromReceivingItems(tx, *task.PurchaseOrderID, items); err != nil {
+			if err := updatePOFromReceivingItems(tx, *task.PurchaseOrderID, receivedNow); err != nil {
 				return fmt.Errorf("update PO from receiving (CompleteFullTask): %w", err)
 			}
 		}
@@ -1159,6 +1298,9 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 	})
 
 	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return resp
+		}
 		return &responses.InternalResponse{Error: err, Message: "Transaction failed"}
 	}
 
@@ -1166,6 +1308,8 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 		return handledResp
 	}
 
+	r.auditOverReceipt(id, location, userId, override, overridden)
+
 	// Emit task_completed notification to the assigned user (fire-and-forget).
 	if r.NotificationsSvc != nil {
 		var task database.ReceivingTask
@@ -1179,8 +1323,12 @@ func (r *ReceivingTasksRepository) CompleteFullTask(id string, location, userId
 	return nil
 }
 
-func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+// CompleteReceivingLine receives one task line. Accepted quantities above the expected
+// quantity plus the tenant's over-receipt allowance are rejected (409) unless override
+// carries a supervisor reason; the override is written to the audit log.
+func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	handledResp := &responses.InternalResponse{}
+	var overridden []overReceiptLine
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		var task database.ReceivingTask
@@ -1226,11 +1374,33 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			return fmt.Errorf("find article %s: %w", item.SKU, err)
 		}
 
+		// Quantities entered in another presentation are received in base units.
+		if resp := convertReceivingItemUoM(tools.NewUoMConverter(tx, task.TenantID), &item); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
 		if foundItem.Status != nil && (*foundItem.Status == "completed" || *foundItem.Status == "closed" || *foundItem.Status == "partial") {
 			*handledResp = responses.InternalResponse{Message: "La línea de recepción ya ha sido procesada", Handled: true}
 			return nil
 		}
 
+		// Lines flagged generate_numbers (on the task or in the request) get their lot / serial
+		// numbers from the series; generated lots are kept on the task line.
+		item.GenerateNumbers = item.GenerateNumbers || foundItem.GenerateNumbers
+		hadLots := len(item.LotNumbers) > 0
+		if err := generateReceivingNumbers(tx, task.TenantID, &article, &item, receivingLineQty(item, foundItem)); err != nil {
+			return err
+		}
+		if !hadLots && len(item.LotNumbers) > 0 {
+			for i := range items {
+				if items[i].SKU == item.SKU {
+					items[i].LotNumbers = append(items[i].LotNumbers, item.LotNumbers...)
+					break
+				}
+			}
+		}
+
 		var qty float64
 		if article.TrackByLot && item.LotNumbers != nil {
 			for _, lot := range item.LotNumbers {
@@ -1252,6 +1422,34 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			rejectedQty = *item.RejectedQty
 		}
 
+		// Over-receipt: accepted units above expected must fit the tenant allowance or be
+		// authorised by a supervisor. Checked before any stock is written.
+		if expected := float64(foundItem.ExpectedQuantity); acceptedQty > expected {
+			allowancePct, err := tenantAllowancePct(tx, task.TenantID, responses.AllowanceOverReceipt)
+			if err != nil {
+				return err
+			}
+			if exceedsAllowance(acceptedQty, expected, allowancePct) {
+				if override == nil {
+					*handledResp = *allowanceExceeded(responses.AllowanceOverReceipt, item.SKU, location, expected, allowancePct, acceptedQty)
+					return nil
+				}
+				overridden = append(overridden, overReceiptLine{SKU: item.SKU, ExpectedQty: expected, ReceivedQty: acceptedQty, AllowancePct: allowancePct})
+			}
+		}
+
+		// Capacity: the accepted units must fit the destination location.
+		if acceptedQty > 0 {
+			exceeded, err := tools.CheckLocationCapacity(tx, task.TenantID, location, []tools.CapacityAddition{{SKU: item.SKU, Quantity: acceptedQty}})
+			if err != nil {
+				return err
+			}
+			if exceeded != nil {
+				*handledResp = *tools.CapacityExceededResponse(exceeded)
+				return nil
+			}
+		}
+
 		// Determine item line status: partial if (accepted+rejected) < expected, else completed.
 		totalProcessed := acceptedQty + rejectedQty
 		if totalProcessed <= 0 || totalProcessed < float64(foundItem.ExpectedQuantity) {
@@ -1298,7 +1496,7 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 
 		inventoryCount := int64(0)
 
-		err := tx.Model(&database.Inventory{}).Where("sku = ? AND location = ?", item.SKU, location).Count(&inventoryCount).Error
+		err := tx.Model(&database.Inventory{}).Where("tenant_id = ? AND sku = ? AND location = ?", task.TenantID, item.SKU, location).Count(&inventoryCount).Error
 
 		if err != nil {
 			return fmt.Errorf("check inventory for SKU %s and location %s: %w", item.SKU, location, err)
@@ -1313,6 +1511,7 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			}
 			lineBeforeQty = 0
 			inventory.ID = lineInvID
+			inventory.TenantID = task.TenantID
 			inventory.SKU = item.SKU
 			inventory.Name = article.Name
 			inventory.Description = article.Description
@@ -1328,7 +1527,7 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 				return errors.New("failed to create inventory")
 			}
 		} else {
-			if err := tx.Where("sku = ? AND location = ?", item.SKU, location).First(&inventory).Error; err != nil {
+			if err := tx.Where("tenant_id = ? AND sku = ? AND location = ?", task.TenantID, item.SKU, location).First(&inventory).Error; err != nil {
 				return fmt.Errorf("find inventory for SKU %s and location %s: %w", item.SKU, location, err)
 			}
 
@@ -1358,6 +1557,10 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 		}
 		lineAfterQty := inventory.Quantity
 		lineRefType := "receiving_task"
+		lineUnitCost, err := receiptUnitCost(tx, task.PurchaseOrderID, item.SKU, inventory.UnitPrice)
+		if err != nil {
+			return err
+		}
 		mov := &database.InventoryMovement{
 			ID:             movLineID,
 			SKU:            item.SKU,
@@ -1371,7 +1574,7 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			ReferenceType:  &lineRefType,
 			ReferenceID:    &id,
 			LotID:          lineLotID,
-			UnitCost:       inventory.UnitPrice,
+			UnitCost:       &lineUnitCost,
 			BeforeQty:      &lineBeforeQty,
 			AfterQty:       &lineAfterQty,
 			UserID:         &userId,
@@ -1380,6 +1583,9 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 		if err := tx.Create(mov).Error; err != nil {
 			return fmt.Errorf("create inventory movement: %w", err)
 		}
+		if err := recordCostLayer(tx, mov); err != nil {
+			return err
+		}
 
 		// R1: create REJECTED movement if any units were rejected.
 		if rejectedQty > 0 {
@@ -1413,37 +1619,40 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			for k := 0; k < len(item.SerialNumbers); k++ {
 				serial := item.SerialNumbers[k]
 
-				var serialItem database.Serial
+				serialItem, resp, err := receiveSerial(tx, task.TenantID, item.SKU, serial.SerialNumber, serialEvent{
+					Location: item.Location, DocumentType: "receiving_task", DocumentID: task.ID, DocumentNumber: task.TaskID, UserID: userId,
+				})
+				if err != nil {
+					return err
+				}
+				if resp != nil {
+					*handledResp = *resp
+					return nil
+				}
 
-				if err := tx.Where("serial_number = ? AND sku = ?", serial.SerialNumber, item.SKU).First(&serialItem).Error; err != nil {
-					if errors.Is(err, gorm.ErrRecordNotFound) {
-						// Create new serial
-						serialItem = database.Serial{
+				// A serial not declared on the task is added to its line.
+				for i := 0; i < len(items); i++ {
+					if items[i].SKU != item.SKU {
+						continue
+					}
+					listed := false
+					for _, s := range items[i].SerialNumbers {
+						if s.SerialNumber == serial.SerialNumber {
+							listed = true
+							break
+						}
+					}
+					if !listed {
+						items[i].SerialNumbers = append(items[i].SerialNumbers, database.Serial{
+							ID:           serialItem.ID,
 							SerialNumber: serial.SerialNumber,
 							SKU:          item.SKU,
-							CreatedAt:    tools.GetCurrentTime(),
-							UpdatedAt:    tools.GetCurrentTime(),
-							Status:       "available",
-						}
-
-						if err := tx.Create(&serialItem).Error; err != nil {
-							return errors.New("failed to create serial")
-						}
-
-						for i := 0; i < len(items); i++ {
-							if items[i].SKU == item.SKU {
-								items[i].SerialNumbers = append(items[i].SerialNumbers, database.Serial{
-									ID:           serialItem.ID,
-									SerialNumber: serial.SerialNumber,
-									SKU:          item.SKU,
-									Status:       "completed",
-									CreatedAt:    serialItem.CreatedAt,
-									UpdatedAt:    serialItem.UpdatedAt,
-								})
-								break
-							}
-						}
+							Status:       "completed",
+							CreatedAt:    serialItem.CreatedAt,
+							UpdatedAt:    serialItem.UpdatedAt,
+						})
 					}
+					break
 				}
 
 				inventorySerial := &database.InventorySerial{
@@ -1596,7 +1805,8 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 			}
 		}
 		if allProcessed {
-			// R1 status: completed_with_differences if any item has rejections OR accepted < expected.
+			// R1 status: completed_with_differences if any item has rejections OR accepted != expected
+			// (shortage or over-receipt).
 			lineDiff := false
 			for _, it := range items {
 				itAccepted := float64(it.ExpectedQuantity)
@@ -1607,7 +1817,7 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 				if it.RejectedQty != nil {
 					itRejected = *it.RejectedQty
 				}
-				if itRejected > 0 || itAccepted < float64(it.ExpectedQuantity) {
+				if itRejected > 0 || itAccepted != float64(it.ExpectedQuantity) {
 					lineDiff = true
 					break
 				}
@@ -1629,8 +1839,9 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 
 		// PO3 auto-link: if this receiving task was generated by a PO, update PO item qtys.
 		if task.PurchaseOrderID != nil && *task.PurchaseOrderID != "" {
-			// Build a single-item update list for just the completed line.
-			lineUpdate := []requests.ReceivingTaskItemRequest{item}
+			// Build a single-item update list for just the completed line, with the
+			// accepted/rejected split resolved above (the request may omit accepted_qty).
+			lineUpdate := []requests.ReceivingTaskItemRequest{{SKU: item.SKU, AcceptedQty: &acceptedQty, RejectedQty: &rejectedQty}}
 			if err := updatePOFromReceivingItems(tx, *task.PurchaseOrderID, lineUpdate); err != nil {
 				return fmt.Errorf("update PO from receiving line (CompleteReceivingLine): %w", err)
 			}
@@ -1640,6 +1851,9 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 	})
 
 	if err != nil {
+		if resp := missingRateResponse(err); resp != nil {
+			return resp
+		}
 		return &responses.InternalResponse{Error: err, Message: "Error en la transacción"}
 	}
 
@@ -1647,6 +1861,8 @@ func (r *ReceivingTasksRepository) CompleteReceivingLine(id string, location, us
 		return handledResp
 	}
 
+	r.auditOverReceipt(id, location, userId, override, overridden)
+
 	return nil
 }
 
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/dto"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+)
+
+// ReplenishmentRepository implements ports.ReplenishmentRepository using GORM raw SQL.
+type ReplenishmentRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.ReplenishmentRepository = (*ReplenishmentRepository)(nil)
+
+func (r *ReplenishmentRepository) ListCandidates(tenantID string, lookbackDays int) ([]dto.ReplenishmentCandidate, *responses.InternalResponse) {
+	if lookbackDays <= 0 {
+		lookbackDays = 30
+	}
+
+	var rows []dto.ReplenishmentCandidate
+	// Inventory and movements are matched to the tenant catalog by SKU (articles.tenant_id).
+	// Open PO quantity counts draft POs too, so a re-run never drafts the same need twice.
+	// Stock on QC hold is not available, so it counts as reserved.
+	err := r.DB.Raw(`
+		WITH stock AS (
+			SELECT i.sku,
+			       COALESCE(SUM(i.quantity), 0)     AS on_hand,
+			       COALESCE(SUM(i.quantity - `+tools.AvailableQtySQL("i")+`), 0) AS reserved
+			  FROM inventory i
+			  JOIN articles a ON a.sku = i.sku AND a.tenant_id = ?
+			 GROUP BY i.sku
+		),
+		open_po AS (
+			SELECT poi.article_sku AS sku,
+			       COALESCE(SUM(GREATEST(poi.expected_qty - poi.received_qty - poi.rejected_qty, 0)), 0) AS qty
+			  FROM purchase_order_items poi
+			  JOIN purchase_orders po ON po.id = poi.purchase_order_id
+			 WHERE po.tenant_id = ?
+			   AND po.deleted_at IS NULL
+			   AND po.status IN ('draft', 'submitted', 'partial')
+			 GROUP BY poi.article_sku
+		),
+		demand AS (
+			SELECT m.sku,
+			       COALESCE(SUM(ABS(m.quantity)), 0) / ?::numeric AS daily
+			  FROM inventory_movements m
+			  JOIN articles a ON a.sku = m.sku AND a.tenant_id = ?
+			 WHERE m.movement_type = 'outbound'
+			   AND m.created_at >= NOW() - make_interval(days => ?)
+			 GROUP BY m.sku
+		),
+		supplier AS (
+			SELECT DISTINCT ON (s.article_sku)
+			       s.article_sku, s.supplier_id, c.name AS supplier_name, s.lead_time_days, s.unit_cost
+			  FROM article_suppliers s
+			  JOIN clients c ON c.id = s.supplier_id
+			 WHERE s.tenant_id = ?
+			   AND s.deleted_at IS NULL
+			   AND c.is_active = true
+			 ORDER BY s.article_sku, s.is_preferred DESC, s.unit_cost ASC NULLS LAST, s.created_at ASC
+		)
+		SELECT a.sku,
+		       a.name,
+		       COALESCE(a.safety_stock, 0)   AS safety_stock,
+		       a.min_quantity,
+		       a.max_quantity,
+		       COALESCE(a.min_order_qty, 0)  AS min_order_qty,
+		       COALESCE(st.on_hand, 0)       AS on_hand,
+		       COALESCE(st.reserved, 0)      AS reserved,
+		       COALESCE(op.qty, 0)           AS open_po_qty,
+		       COALESCE(d.daily, 0)          AS daily_demand,
+		       sp.supplier_id,
+		       sp.supplier_name,
+		       sp.lead_time_days,
+		       sp.unit_cost
+		  FROM articles a
+		  LEFT JOIN stock st    ON st.sku = a.sku
+		  LEFT JOIN open_po op  ON op.sku = a.sku
+		  LEFT JOIN demand d    ON d.sku = a.sku
+		  LEFT JOIN supplier sp ON sp.article_sku = a.sku
+		 WHERE a.tenant_id = ?
+		   AND (a.is_active IS NULL OR a.is_active = true)
+		   AND (COALESCE(a.safety_stock, 0) > 0 OR a.min_quantity IS NOT NULL)
+		 ORDER BY a.sku
+	`, tenantID, tenantID, lookbackDays, tenantID, lookbackDays, tenantID, tenantID).Scan(&rows).Error
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al calcular las sugerencias de reabastecimiento"}
+	}
+	return rows, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -23,7 +24,7 @@ type SalesOrdersRepository struct {
 
 // inventoryPickSuggestor is a narrow interface for FEFO pick suggestions (avoids import cycle).
 type inventoryPickSuggestor interface {
-	GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
+	GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse)
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -67,6 +68,7 @@ func toSalesOrderResponse(so *database.SalesOrder, items []database.SalesOrderIt
 		CreatedBy:     so.CreatedBy,
 		SubmittedAt:   so.SubmittedAt,
 		CompletedAt:   so.CompletedAt,
+		DeliveredAt:   so.DeliveredAt,
 		CancelledAt:   so.CancelledAt,
 		PickingTaskID: so.PickingTaskID,
 		CreatedAt:     so.CreatedAt,
@@ -93,6 +95,7 @@ func (r *SalesOrdersRepository) loadItems(soID string) ([]database.SalesOrderIte
 
 func (r *SalesOrdersRepository) Create(tenantID, userID string, req *requests.CreateSalesOrderRequest) (*responses.SalesOrderResponse, *responses.InternalResponse) {
 	var result *responses.SalesOrderResponse
+	var handledResp *responses.InternalResponse
 
 	txErr := r.DB.Transaction(func(tx *gorm.DB) error {
 		soNumber, err := nextSONumber(tx, tenantID)
@@ -121,20 +124,29 @@ func (r *SalesOrdersRepository) Create(tenantID, userID string, req *requests.Cr
 			return fmt.Errorf("create sales_order: %w", err)
 		}
 
+		conv := tools.NewUoMConverter(tx, tenantID)
 		items := make([]database.SalesOrderItem, 0, len(req.Items))
 		for _, line := range req.Items {
+			uom, resp := convertLineUoM(conv, line.ArticleSKU, line.Presentation, line.ExpectedQty, line.UnitPrice)
+			if resp != nil {
+				handledResp = resp
+				return errors.New(resp.Message)
+			}
 			itemID, err := tools.GenerateNanoid(tx)
 			if err != nil {
 				return fmt.Errorf("generate item id: %w", err)
 			}
 			items = append(items, database.SalesOrderItem{
-				ID:           itemID,
-				SalesOrderID: id,
-				ArticleSKU:   line.ArticleSKU,
-				ExpectedQty:  line.ExpectedQty,
-				PickedQty:    0,
-				UnitPrice:    line.UnitPrice,
-				Notes:        line.Notes,
+				ID:                 itemID,
+				SalesOrderID:       id,
+				ArticleSKU:         line.ArticleSKU,
+				ExpectedQty:        uom.Qty,
+				PickedQty:          0,
+				UnitPrice:          uom.Price,
+				Currency:           line.Currency,
+				Notes:              line.Notes,
+				Presentation:       uom.Presentation,
+				PresentationFactor: uom.Factor,
 			})
 		}
 
@@ -146,6 +158,9 @@ func (r *SalesOrdersRepository) Create(tenantID, userID string, req *requests.Cr
 		return nil
 	})
 
+	if handledResp != nil {
+		return nil, handledResp
+	}
 	if txErr != nil {
 		return nil, &responses.InternalResponse{Error: txErr, Message: "Error al crear la orden de venta"}
 	}
@@ -230,6 +245,7 @@ func (r *SalesOrdersRepository) List(tenantID string, status, customerID, search
 			ExpectedDate:  row.ExpectedDate,
 			SubmittedAt:   row.SubmittedAt,
 			CompletedAt:   row.CompletedAt,
+			DeliveredAt:   row.DeliveredAt,
 			CancelledAt:   row.CancelledAt,
 			PickingTaskID: row.PickingTaskID,
 			CreatedAt:     row.CreatedAt,
@@ -275,6 +291,7 @@ func (r *SalesOrdersRepository) GetByID(id, tenantID string) (*responses.SalesOr
 
 func (r *SalesOrdersRepository) Update(id, tenantID string, req *requests.UpdateSalesOrderRequest) (*responses.SalesOrderResponse, *responses.InternalResponse) {
 	var result *responses.SalesOrderResponse
+	var handledResp *responses.InternalResponse
 
 	txErr := r.DB.Transaction(func(tx *gorm.DB) error {
 		var so database.SalesOrder
@@ -307,19 +324,28 @@ func (r *SalesOrdersRepository) Update(id, tenantID string, req *requests.Update
 			if err := tx.Where("sales_order_id = ?", id).Delete(&database.SalesOrderItem{}).Error; err != nil {
 				return fmt.Errorf("delete old items: %w", err)
 			}
+			conv := tools.NewUoMConverter(tx, tenantID)
 			newItems := make([]database.SalesOrderItem, 0, len(req.Items))
 			for _, line := range req.Items {
+				uom, resp := convertLineUoM(conv, line.ArticleSKU, line.Presentation, line.ExpectedQty, line.UnitPrice)
+				if resp != nil {
+					handledResp = resp
+					return errors.New(resp.Message)
+				}
 				itemID, err := tools.GenerateNanoid(tx)
 				if err != nil {
 					return fmt.Errorf("generate item id: %w", err)
 				}
 				newItems = append(newItems, database.SalesOrderItem{
-					ID:           itemID,
-					SalesOrderID: id,
-					ArticleSKU:   line.ArticleSKU,
-					ExpectedQty:  line.ExpectedQty,
-					UnitPrice:    line.UnitPrice,
-					Notes:        line.Notes,
+					ID:                 itemID,
+					SalesOrderID:       id,
+					ArticleSKU:         line.ArticleSKU,
+					ExpectedQty:        uom.Qty,
+					UnitPrice:          uom.Price,
+					Currency:           line.Currency,
+					Notes:              line.Notes,
+					Presentation:       uom.Presentation,
+					PresentationFactor: uom.Factor,
 				})
 			}
 			if err := tx.Create(&newItems).Error; err != nil {
@@ -335,6 +361,9 @@ func (r *SalesOrdersRepository) Update(id, tenantID string, req *requests.Update
 		return nil
 	})
 
+	if handledResp != nil {
+		return nil, handledResp
+	}
 	if txErr != nil {
 		msg := txErr.Error()
 		if msg == "not_found" {
@@ -413,20 +442,29 @@ func (r *SalesOrdersRepository) Submit(id, tenantID, userID string) (*responses.
 
 		// 3. For each SO item, get FEFO pick suggestions.
 		type pickItem struct {
-			SKU        string
-			Qty        float64
-			Allocs     []database.LocationAllocation
-			Available  float64
+			SKU       string
+			Qty       float64
+			Allocs    []database.LocationAllocation
+			Available float64
+			KitSKU    *string
+			KitQtyPer *float64
 		}
 		pickItems := make([]pickItem, 0, len(soItems))
 		var backorderCandidates []responses.BackorderCandidate
+		// Kits short of assembled stock whose BOM allows picking them as components.
+		type kitShortfall struct {
+			SKU        string
+			Qty        float64
+			Components []database.KitComponent
+		}
+		var kitShortfalls []kitShortfall
 
 		for _, soItem := range soItems {
 			var allocs []database.LocationAllocation
 			available := 0.0
 
 			if r.InventorySvc != nil {
-				sugg, suggResp := r.InventorySvc.GetPickSuggestionsBySKU(soItem.ArticleSKU, soItem.ExpectedQty)
+				sugg, suggResp := r.InventorySvc.GetPickSuggestionsBySKU(tenantID, soItem.ArticleSKU, soItem.ExpectedQty)
 				if suggResp == nil && sugg != nil {
 					allocs = sugg.Allocations
 					available = sugg.TotalFound
@@ -446,6 +484,20 @@ func (r *SalesOrdersRepository) Submit(id, tenantID, userID string) (*responses.
 				available = 0
 			}
 
+			if r.InventorySvc != nil && available < soItem.ExpectedQty {
+				components, err := explodableKitComponents(tx, tenantID, soItem.ArticleSKU)
+				if err != nil {
+					return err
+				}
+				if len(components) > 0 {
+					kitShortfalls = append(kitShortfalls, kitShortfall{
+						SKU:        soItem.ArticleSKU,
+						Qty:        soItem.ExpectedQty - available,
+						Components: components,
+					})
+				}
+			}
+
 			// Only include in picking task if there is available stock.
 			if available > 0 {
 				pickItems = append(pickItems, pickItem{
@@ -474,12 +526,63 @@ func (r *SalesOrdersRepository) Submit(id, tenantID, userID string) (*responses.
 			}
 		}
 
+		// 3b. Explode the missing kits into component picks, after the regular lines so the
+		// components keep the stock those lines were allocated (FEFO skips it).
+		if len(kitShortfalls) > 0 {
+			allocated := make(map[string]float64)
+			for _, pi := range pickItems {
+				allocated[pi.SKU] += pi.Qty
+			}
+			for _, ks := range kitShortfalls {
+				found := make(map[string]float64, len(ks.Components))
+				suggestions := make(map[string][]database.LocationAllocation, len(ks.Components))
+				for _, c := range ks.Components {
+					sugg, suggResp := r.InventorySvc.GetPickSuggestionsBySKU(tenantID, c.ComponentSKU, allocated[c.ComponentSKU]+ks.Qty*c.Quantity)
+					if suggResp != nil || sugg == nil {
+						continue
+					}
+					found[c.ComponentSKU] = math.Max(0, sugg.TotalFound-allocated[c.ComponentSKU])
+					suggestions[c.ComponentSKU] = sugg.Allocations
+				}
+				kits := kitExplodableQty(ks.Qty, ks.Components, found)
+				if kits <= 0 {
+					continue
+				}
+				for _, c := range ks.Components {
+					qty := kits * c.Quantity
+					kitSKU, qtyPer := ks.SKU, c.Quantity
+					pickItems = append(pickItems, pickItem{
+						SKU:       c.ComponentSKU,
+						Qty:       qty,
+						Allocs:    sliceAllocations(suggestions[c.ComponentSKU], allocated[c.ComponentSKU], qty),
+						Available: found[c.ComponentSKU],
+						KitSKU:    &kitSKU,
+						KitQtyPer: &qtyPer,
+					})
+					allocated[c.ComponentSKU] += qty
+				}
+				for i := range backorderCandidates {
+					if backorderCandidates[i].ArticleSKU != ks.SKU {
+						continue
+					}
+					backorderCandidates[i].AvailableQty += kits
+					backorderCandidates[i].BackorderQty -= kits
+					if backorderCandidates[i].BackorderQty <= kitQtyEpsilon {
+						backorderCandidates = append(backorderCandidates[:i], backorderCandidates[i+1:]...)
+					}
+					break
+				}
+			}
+		}
+
 		// 4. Build picking task items JSON.
 		type pickingItemJSON struct {
 			SKU              string                        `json:"sku"`
 			ExpectedQuantity float64                       `json:"required_qty"`
 			Allocations      []database.LocationAllocation `json:"allocations"`
 			Status           string                        `json:"status"`
+			KitSKU           *string                       `json:"kit_sku,omitempty"`
+			KitQtyPer        *float64                      `json:"kit_qty_per,omitempty"`
 		}
 		taskItems := make([]pickingItemJSON, 0, len(pickItems))
 		for _, pi := range pickItems {
@@ -488,6 +591,8 @@ func (r *SalesOrdersRepository) Submit(id, tenantID, userID string) (*responses.
 				ExpectedQuantity: pi.Qty,
 				Allocations:      pi.Allocs,
 				Status:           "open",
+				KitSKU:           pi.KitSKU,
+				KitQtyPer:        pi.KitQtyPer,
 			})
 		}
 
@@ -597,7 +702,7 @@ func (r *SalesOrdersRepository) Cancel(id, tenantID, userID string) *responses.I
 			}
 			return err
 		}
-		if so.Status == "completed" {
+		if so.Status == "completed" || so.Status == "delivered" {
 			return fmt.Errorf("already_completed")
 		}
 		if so.Status == "cancelled" {
@@ -666,8 +771,11 @@ func (r *SalesOrdersRepository) Cancel(id, tenantID, userID string) *responses.I
 
 // UpdatePickedQty updates sales_order_items.picked_qty and advances SO status.
 // Returns the new SO status ('completed' | 'partial' | '') so CompletePickingTask can trigger DN/BO.
+// A line whose cumulative picked qty would exceed the ordered qty plus the tenant's
+// OverDeliveryAllowancePct is rejected with a 409 (AllowanceExceeded detail) and nothing is saved.
 func (r *SalesOrdersRepository) UpdatePickedQty(salesOrderID string, pickedPerSKU map[string]float64) (string, *responses.InternalResponse) {
 	var finalStatus string
+	var handledResp *responses.InternalResponse
 
 	txErr := r.DB.Transaction(func(tx *gorm.DB) error {
 		var soItems []database.SalesOrderItem
@@ -675,12 +783,25 @@ func (r *SalesOrdersRepository) UpdatePickedQty(salesOrderID string, pickedPerSK
 			return fmt.Errorf("load so items: %w", err)
 		}
 
+		var tenantID string
+		if err := tx.Raw(`SELECT tenant_id FROM sales_orders WHERE id = ?`, salesOrderID).Scan(&tenantID).Error; err != nil {
+			return fmt.Errorf("load so tenant: %w", err)
+		}
+		deliveryPct, err := tenantAllowancePct(tx, tenantID, responses.AllowanceOverDelivery)
+		if err != nil {
+			return err
+		}
+
 		allFulfilled := true
 		anyPicked := false
 
 		for i := range soItems {
 			additional := pickedPerSKU[soItems[i].ArticleSKU]
 			if additional > 0 {
+				if total := soItems[i].PickedQty + additional; exceedsAllowance(total, soItems[i].ExpectedQty, deliveryPct) {
+					handledResp = allowanceExceeded(responses.AllowanceOverDelivery, soItems[i].ArticleSKU, "", soItems[i].ExpectedQty, deliveryPct, total)
+					return fmt.Errorf("over-delivery allowance exceeded")
+				}
 				soItems[i].PickedQty += additional
 				anyPicked = true
 				if err := tx.Exec(`
@@ -721,6 +842,9 @@ func (r *SalesOrdersRepository) UpdatePickedQty(salesOrderID string, pickedPerSK
 	})
 
 	if txErr != nil {
+		if handledResp != nil {
+			return "", handledResp
+		}
 		return "", &responses.InternalResponse{Error: txErr, Message: "Error al actualizar cantidades pickeadas"}
 	}
 	return finalStatus, nil
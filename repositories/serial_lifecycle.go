@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Serial state machine — shared by receiving, picking, delivery notes, returns,
+// QC holds, adjustments and the manual transition endpoint.
+// ─────────────────────────────────────────────────────────────────────────────
+
+// validSerialTransitions declares the allowed serial status changes.
+// reserved → in_stock is a released reservation; returned → in_stock a restocked return.
+// A shipped unit only comes back through a return; scrapped is final.
+var validSerialTransitions = map[string]map[string]bool{
+	database.SerialReceived: {database.SerialInStock: true, database.SerialScrapped: true},
+	database.SerialInStock:  {database.SerialReserved: true, database.SerialShipped: true, database.SerialScrapped: true},
+	database.SerialReserved: {database.SerialInStock: true, database.SerialShipped: true, database.SerialScrapped: true},
+	database.SerialShipped:  {database.SerialReturned: true},
+	database.SerialReturned: {database.SerialInStock: true, database.SerialScrapped: true},
+}
+
+// isValidSerialTransition returns true when current → next is allowed.
+// No-op (same → same) is always true; scrapped has no outgoing transition.
+func isValidSerialTransition(current, next string) bool {
+	if current == next {
+		return true
+	}
+	if allowed, ok := validSerialTransitions[current]; ok {
+		return allowed[next]
+	}
+	return false
+}
+
+// serialTransitionConflict is the handled 409 for a serial that cannot move to next, nil
+// when the change is allowed.
+func serialTransitionConflict(serial *database.Serial, next string) *responses.InternalResponse {
+	if isValidSerialTransition(serial.Status, next) {
+		return nil
+	}
+	return &responses.InternalResponse{
+		Message:    fmt.Sprintf("La serie %s está en estado %s; no puede pasar a %s", serial.SerialNumber, serial.Status, next),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+	}
+}
+
+// serialEvent is what a transition records besides the status change. DocumentType follows
+// the inventory_movements.reference_type names (receiving_task, picking_task, ...).
+type serialEvent struct {
+	Location       string
+	DocumentType   string
+	DocumentID     string
+	DocumentNumber string
+	UserID         string
+	Notes          string
+}
+
+// newSerialHistory builds the history row of a change from → to. Empty event fields are
+// stored as NULL.
+func newSerialHistory(serial *database.Serial, from *string, to string, ev serialEvent) database.SerialHistory {
+	return database.SerialHistory{
+		TenantID:       serial.TenantID,
+		SerialID:       serial.ID,
+		FromStatus:     from,
+		ToStatus:       to,
+		Location:       nonEmpty(ev.Location),
+		DocumentType:   nonEmpty(ev.DocumentType),
+		DocumentID:     nonEmpty(ev.DocumentID),
+		DocumentNumber: nonEmpty(ev.DocumentNumber),
+		UserID:         nonEmpty(ev.UserID),
+		Notes:          nonEmpty(ev.Notes),
+	}
+}
+
+// nonEmpty returns nil for an empty string and a pointer to it otherwise.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// recordSerialHistory inserts one history row.
+func recordSerialHistory(tx *gorm.DB, serial *database.Serial, from *string, to string, ev serialEvent) error {
+	row := newSerialHistory(serial, from, to, ev)
+	id, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return fmt.Errorf("generate serial history id: %w", err)
+	}
+	row.ID = id
+	if err := tx.Create(&row).Error; err != nil {
+		return fmt.Errorf("create serial history %s: %w", serial.SerialNumber, err)
+	}
+	return nil
+}
+
+// createSerial inserts a new serial in its initial status and records its first history row.
+func createSerial(tx *gorm.DB, serial *database.Serial, ev serialEvent) error {
+	if serial.ID == "" {
+		id, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate serial id: %w", err)
+		}
+		serial.ID = id
+	}
+	now := tools.GetCurrentTime()
+	serial.CreatedAt = now
+	serial.UpdatedAt = now
+	if err := tx.Create(serial).Error; err != nil {
+		return fmt.Errorf("create serial %s: %w", serial.SerialNumber, err)
+	}
+	return recordSerialHistory(tx, serial, nil, serial.Status, ev)
+}
+
+// transitionSerial moves serial to next and records the change. Moving to the current status
+// changes nothing. A non-nil response is the handled 409 of a forbidden transition.
+func transitionSerial(tx *gorm.DB, serial *database.Serial, next string, ev serialEvent) (*responses.InternalResponse, error) {
+	if resp := serialTransitionConflict(serial, next); resp != nil {
+		return resp, nil
+	}
+	if serial.Status == next {
+		return nil, nil
+	}
+	if err := tx.Model(&database.Serial{}).Where("id = ?", serial.ID).
+		Updates(map[string]interface{}{"status": next, "updated_at": tools.GetCurrentTime()}).Error; err != nil {
+		return nil, fmt.Errorf("update serial %s: %w", serial.SerialNumber, err)
+	}
+	from := serial.Status
+	serial.Status = next
+	return nil, recordSerialHistory(tx, serial, &from, next, ev)
+}
+
+// lockSerialByNumber loads a tenant's serial of sku FOR UPDATE; nil when it does not exist.
+func lockSerialByNumber(tx *gorm.DB, tenantID, sku, serialNumber string) (*database.Serial, error) {
+	var serial database.Serial
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND sku = ? AND serial_number = ?", tenantID, sku, serialNumber).
+		First(&serial).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find serial %s: %w", serialNumber, err)
+	}
+	return &serial, nil
+}
+
+// transitionSerialNumbers moves the serials of sku to next. An unknown serial is a handled
+// 400 and a forbidden transition a handled 409.
+func transitionSerialNumbers(tx *gorm.DB, tenantID, sku string, serialNumbers []string, next string, ev serialEvent) (*responses.InternalResponse, error) {
+	for _, sn := range serialNumbers {
+		serial, err := lockSerialByNumber(tx, tenantID, sku, sn)
+		if err != nil {
+			return nil, err
+		}
+		if serial == nil {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("La serie %s no existe para el artículo %s", sn, sku),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}, nil
+		}
+		if resp, err := transitionSerial(tx, serial, next, ev); resp != nil || err != nil {
+			return resp, err
+		}
+	}
+	return nil, nil
+}
+
+// receiveSerial puts a received unit in stock: a serial declared on the receiving task (or
+// coming back after a return) moves to in_stock, an unknown one is created in stock. A serial
+// already in the warehouse or shipped is a handled 409.
+func receiveSerial(tx *gorm.DB, tenantID, sku, serialNumber string, ev serialEvent) (*database.Serial, *responses.InternalResponse, error) {
+	serial, err := lockSerialByNumber(tx, tenantID, sku, serialNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if serial == nil {
+		serial = &database.Serial{TenantID: tenantID, SerialNumber: serialNumber, SKU: sku, Status: database.SerialInStock}
+		if err := createSerial(tx, serial, ev); err != nil {
+			return nil, nil, err
+		}
+		return serial, nil, nil
+	}
+	if serial.Status == database.SerialInStock || serial.Status == database.SerialReserved {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("La serie %s ya está en stock", serialNumber),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}, nil
+	}
+	resp, err := transitionSerial(tx, serial, database.SerialInStock, ev)
+	if resp != nil || err != nil {
+		return nil, resp, err
+	}
+	return serial, nil, nil
+}
+
+// pickingItemSerials returns the serial numbers listed on picking items per SKU, in order.
+func pickingItemSerials(items []requests.PickingTaskItemRequest) map[string][]string {
+	out := make(map[string][]string)
+	for _, item := range items {
+		for _, s := range item.SerialNumbers {
+			if s.SerialNumber != "" {
+				out[item.SKU] = append(out[item.SKU], s.SerialNumber)
+			}
+		}
+	}
+	return out
+}
+
+// reservePickingSerials reserves the serials a picking task lists. Reserving a serial that
+// another task already holds is a handled 409.
+func reservePickingSerials(tx *gorm.DB, task *database.PickingTask, items []requests.PickingTaskItemRequest, userID string) (*responses.InternalResponse, error) {
+	ev := serialEvent{DocumentType: "picking_task", DocumentID: task.ID, DocumentNumber: task.TaskID, UserID: userID}
+	for sku, serials := range pickingItemSerials(items) {
+		for _, sn := range serials {
+			serial, err := lockSerialByNumber(tx, task.TenantID, sku, sn)
+			if err != nil {
+				return nil, err
+			}
+			if serial == nil {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("La serie %s no existe para el artículo %s", sn, sku),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}, nil
+			}
+			if serial.Status != database.SerialInStock {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("La serie %s está en estado %s; solo se reservan series en stock", sn, serial.Status),
+					Handled:    true,
+					StatusCode: responses.StatusConflict,
+				}, nil
+			}
+			ev.Location = serialLocation(tx, serial.ID)
+			if resp, err := transitionSerial(tx, serial, database.SerialReserved, ev); resp != nil || err != nil {
+				return resp, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// releasePickingSerials puts the reserved serials of a picking task back in stock. Serials
+// that are no longer reserved are left alone.
+func releasePickingSerials(tx *gorm.DB, task *database.PickingTask, items []requests.PickingTaskItemRequest, userID string) error {
+	ev := serialEvent{DocumentType: "picking_task", DocumentID: task.ID, DocumentNumber: task.TaskID, UserID: userID}
+	for sku, serials := range pickingItemSerials(items) {
+		for _, sn := range serials {
+			serial, err := lockSerialByNumber(tx, task.TenantID, sku, sn)
+			if err != nil {
+				return err
+			}
+			if serial == nil || serial.Status != database.SerialReserved {
+				continue
+			}
+			ev.Location = serialLocation(tx, serial.ID)
+			if _, err := transitionSerial(tx, serial, database.SerialInStock, ev); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkPickedSerials verifies the serials of a picking task about to complete are in the
+// warehouse (in stock or reserved). A non-nil response is the handled 400 / 409.
+func checkPickedSerials(tx *gorm.DB, tenantID string, items []requests.PickingTaskItemRequest) (*responses.InternalResponse, error) {
+	for sku, serials := range pickingItemSerials(items) {
+		for _, sn := range serials {
+			serial, err := lockSerialByNumber(tx, tenantID, sku, sn)
+			if err != nil {
+				return nil, err
+			}
+			if serial == nil {
+				return &responses.InternalResponse{
+					Message:    fmt.Sprintf("La serie %s no existe para el artículo %s", sn, sku),
+					Handled:    true,
+					StatusCode: responses.StatusBadRequest,
+				}, nil
+			}
+			if resp := serialTransitionConflict(serial, database.SerialShipped); resp != nil {
+				return resp, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// shipSerials marks the serials that left on a document as shipped and unlinks them from
+// inventory. It runs after the picking committed, so a serial that cannot ship (unknown or
+// moved meanwhile) is logged and skipped instead of losing the document.
+func shipSerials(tx *gorm.DB, tenantID, sku string, serialNumbers []string, ev serialEvent) error {
+	for _, sn := range serialNumbers {
+		serial, err := lockSerialByNumber(tx, tenantID, sku, sn)
+		if err != nil {
+			return err
+		}
+		if serial == nil {
+			log.Warn().Str("serial", sn).Str("sku", sku).Str("document", ev.DocumentNumber).Msg("shipSerials: serial not found")
+			continue
+		}
+		if serialTransitionConflict(serial, database.SerialShipped) != nil {
+			log.Warn().Str("serial", sn).Str("status", serial.Status).Str("document", ev.DocumentNumber).Msg("shipSerials: serial cannot ship")
+			continue
+		}
+		ev.Location = serialLocation(tx, serial.ID)
+		if _, err := transitionSerial(tx, serial, database.SerialShipped, ev); err != nil {
+			return err
+		}
+		if err := tx.Where("serial_id = ?", serial.ID).Delete(&database.InventorySerial{}).Error; err != nil {
+			return fmt.Errorf("unlink serial %s: %w", sn, err)
+		}
+	}
+	return nil
+}
+
+// serialLocation is the location of the inventory row a serial is linked to, "" when the
+// serial is not in inventory.
+func serialLocation(tx *gorm.DB, serialID string) string {
+	var locations []string
+	if err := tx.Model(&database.InventorySerial{}).Where("serial_id = ?", serialID).
+		Order("created_at DESC").Limit(1).Pluck("location", &locations).Error; err != nil || len(locations) == 0 {
+		return ""
+	}
+	return locations[0]
+}
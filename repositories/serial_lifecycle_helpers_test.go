@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidSerialTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{database.SerialReceived, database.SerialInStock, true},
+		{database.SerialReceived, database.SerialShipped, false},
+		{database.SerialInStock, database.SerialReserved, true},
+		{database.SerialInStock, database.SerialShipped, true},
+		{database.SerialReserved, database.SerialInStock, true},
+		{database.SerialReserved, database.SerialShipped, true},
+		{database.SerialShipped, database.SerialReturned, true},
+		{database.SerialShipped, database.SerialInStock, false},
+		{database.SerialReturned, database.SerialInStock, true},
+		{database.SerialReturned, database.SerialScrapped, true},
+		{database.SerialReturned, database.SerialShipped, false},
+		{database.SerialScrapped, database.SerialInStock, false},
+		{database.SerialScrapped, database.SerialScrapped, true},
+		{"available", database.SerialInStock, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, isValidSerialTransition(c.from, c.to), "%s → %s", c.from, c.to)
+	}
+}
+
+func TestSerialTransitionConflict(t *testing.T) {
+	serial := &database.Serial{SerialNumber: "SN-1", Status: database.SerialShipped}
+	assert.Nil(t, serialTransitionConflict(serial, database.SerialReturned))
+
+	resp := serialTransitionConflict(serial, database.SerialReserved)
+	require.NotNil(t, resp)
+	assert.True(t, resp.Handled)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Message, "SN-1")
+}
+
+func TestNewSerialHistory_EmptyFieldsAreNull(t *testing.T) {
+	serial := &database.Serial{ID: "s1", TenantID: "t1", Status: database.SerialInStock}
+	from := database.SerialReceived
+	row := newSerialHistory(serial, &from, database.SerialInStock, serialEvent{
+		Location: "A-01", DocumentType: "receiving_task", DocumentID: "rt1", UserID: "u1",
+	})
+	assert.Equal(t, "s1", row.SerialID)
+	assert.Equal(t, "t1", row.TenantID)
+	assert.Equal(t, database.SerialReceived, *row.FromStatus)
+	assert.Equal(t, database.SerialInStock, row.ToStatus)
+	assert.Equal(t, "A-01", *row.Location)
+	assert.Equal(t, "rt1", *row.DocumentID)
+	assert.Nil(t, row.DocumentNumber)
+	assert.Nil(t, row.Notes)
+}
+
+func TestPickingItemSerials_GroupsBySKU(t *testing.T) {
+	items := []requests.PickingTaskItemRequest{
+		{SKU: "SKU-A", SerialNumbers: []database.Serial{{SerialNumber: "A1"}, {SerialNumber: ""}}},
+		{SKU: "SKU-B"},
+		{SKU: "SKU-A", SerialNumbers: []database.Serial{{SerialNumber: "A2"}}},
+	}
+	got := pickingItemSerials(items)
+	assert.Equal(t, map[string][]string{"SKU-A": {"A1", "A2"}}, got)
+}
+
+func TestAttachUnpackedSerials_AddsOnlyMissingOnes(t *testing.T) {
+	items := []DNItemCreationParam{
+		{ArticleSKU: "SKU-A", SerialNumbers: []string{"A1"}},
+		{ArticleSKU: "SKU-A"},
+		{ArticleSKU: "SKU-B"},
+	}
+	lines := map[string]database.ShipmentLine{
+		"l2": {ArticleSKU: "SKU-B", SerialNumbers: []string{"B1"}},
+		"l1": {ArticleSKU: "SKU-A", SerialNumbers: []string{"A1", "A2"}},
+		"l3": {ArticleSKU: "SKU-C", SerialNumbers: []string{"C1"}},
+	}
+	attachUnpackedSerials(items, lines)
+	assert.Equal(t, []string{"A1", "A2"}, items[0].SerialNumbers)
+	assert.Empty(t, items[1].SerialNumbers)
+	assert.Equal(t, []string{"B1"}, items[2].SerialNumbers)
+}
+
+func TestLastSerialDelivery(t *testing.T) {
+	dn := "delivery_note"
+	picking := "picking_task"
+	ids := []string{"dn1", "dn2", "pt1"}
+	number := "DN-0002"
+	customer := "Cliente Uno"
+	shippedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	history := []responses.SerialHistoryEntry{
+		{SerialHistory: database.SerialHistory{ToStatus: database.SerialShipped, DocumentType: &dn, DocumentID: &ids[0]}},
+		{SerialHistory: database.SerialHistory{ToStatus: database.SerialReturned}},
+		{SerialHistory: database.SerialHistory{ToStatus: database.SerialInStock}},
+		{SerialHistory: database.SerialHistory{ToStatus: database.SerialShipped, DocumentType: &dn, DocumentID: &ids[1], DocumentNumber: &number, CreatedAt: shippedAt}, CustomerName: &customer},
+	}
+	got := lastSerialDelivery(history)
+	require.NotNil(t, got)
+	assert.Equal(t, "dn2", got.DeliveryNoteID)
+	assert.Equal(t, "DN-0002", *got.DNNumber)
+	assert.Equal(t, "Cliente Uno", *got.CustomerName)
+	assert.Equal(t, shippedAt, got.ShippedAt)
+
+	// Shipped by a picking without a sales order: no delivery note to show.
+	assert.Nil(t, lastSerialDelivery([]responses.SerialHistoryEntry{
+		{SerialHistory: database.SerialHistory{ToStatus: database.SerialShipped, DocumentType: &picking, DocumentID: &ids[2]}},
+	}))
+}
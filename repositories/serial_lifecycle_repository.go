@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SerialLifecycleRepository serves the manual serial transition and the chain-of-custody
+// lookup. The state machine itself lives in serial_lifecycle.go.
+type SerialLifecycleRepository struct {
+	DB *gorm.DB
+}
+
+// serialDocumentManual is the document type of a transition made by hand without a document.
+const serialDocumentManual = "manual"
+
+// TransitionSerial moves a serial to req.Status following the state machine. A serial that
+// leaves the warehouse (shipped, scrapped) is unlinked from its inventory row; quantities are
+// not touched, they move through their own documents (adjustments, returns).
+func (r *SerialLifecycleRepository) TransitionSerial(tenantID, id, userID string, req *requests.SerialTransitionRequest) (*database.Serial, *responses.InternalResponse) {
+	var serial database.Serial
+	var handledResp *responses.InternalResponse
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).First(&serial).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				handledResp = &responses.InternalResponse{Message: "Serie no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+				return nil
+			}
+			return err
+		}
+
+		ev := serialEvent{DocumentType: serialDocumentManual, UserID: userID}
+		if req.DocumentType != nil && *req.DocumentType != "" {
+			ev.DocumentType = *req.DocumentType
+		}
+		if req.DocumentID != nil {
+			ev.DocumentID = *req.DocumentID
+		}
+		if req.DocumentNumber != nil {
+			ev.DocumentNumber = *req.DocumentNumber
+		}
+		if req.Notes != nil {
+			ev.Notes = *req.Notes
+		}
+		if req.Location != nil && *req.Location != "" {
+			ev.Location = *req.Location
+		} else {
+			ev.Location = serialLocation(tx, serial.ID)
+		}
+
+		resp, err := transitionSerial(tx, &serial, req.Status, ev)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			handledResp = resp
+			return nil
+		}
+		if req.Status == database.SerialShipped || req.Status == database.SerialScrapped {
+			if err := tx.Where("serial_id = ?", serial.ID).Delete(&database.InventorySerial{}).Error; err != nil {
+				return fmt.Errorf("unlink serial %s: %w", serial.SerialNumber, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al cambiar el estado de la serie"}
+	}
+	if handledResp != nil {
+		return nil, handledResp
+	}
+	return &serial, nil
+}
+
+// GetChainOfCustody returns a serial looked up by its number with every status change, who
+// made it, the document behind it and, for delivery notes, the customer it shipped to.
+func (r *SerialLifecycleRepository) GetChainOfCustody(tenantID, serialNumber string) (*responses.SerialCustodyView, *responses.InternalResponse) {
+	var serial database.Serial
+	if err := r.DB.Where("tenant_id = ? AND serial_number = ?", tenantID, serialNumber).First(&serial).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Serie no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la serie"}
+	}
+
+	history, err := loadSerialHistory(r.DB, serial.ID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el historial de la serie"}
+	}
+
+	view := &responses.SerialCustodyView{Serial: serial, History: history, LastDelivery: lastSerialDelivery(history)}
+	if location := serialLocation(r.DB, serial.ID); location != "" {
+		view.CurrentLocation = &location
+	}
+	var names []string
+	if err := r.DB.Model(&database.Article{}).Where("tenant_id = ? AND sku = ?", tenantID, serial.SKU).
+		Limit(1).Pluck("name", &names).Error; err == nil && len(names) > 0 {
+		view.ArticleName = &names[0]
+	}
+	return view, nil
+}
+
+// loadSerialHistory returns a serial's history oldest first, joined to the user and, for
+// delivery note entries, to the sales order and customer.
+func loadSerialHistory(tx *gorm.DB, serialID string) ([]responses.SerialHistoryEntry, error) {
+	rows := []responses.SerialHistoryEntry{}
+	if err := tx.Raw(`
+		SELECT h.*,
+		       NULLIF(TRIM(COALESCE(u.first_name, '') || ' ' || COALESCE(u.last_name, '')), '') AS user_name,
+		       dn.sales_order_id, so.so_number,
+		       dn.customer_id, c.code AS customer_code, c.name AS customer_name,
+		       dn.delivered_at
+		  FROM serial_history h
+		  LEFT JOIN users u ON u.id = h.user_id
+		  LEFT JOIN delivery_notes dn ON h.document_type = 'delivery_note' AND dn.id = h.document_id
+		  LEFT JOIN sales_orders so ON so.id = dn.sales_order_id
+		  LEFT JOIN clients c ON c.id = dn.customer_id
+		 WHERE h.serial_id = ?
+		 ORDER BY h.created_at ASC, h.id ASC
+	`, serialID).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load history of serial %s: %w", serialID, err)
+	}
+	return rows, nil
+}
+
+// lastSerialDelivery is the last delivery note the serial shipped on, nil when it never left
+// on one (history oldest first).
+func lastSerialDelivery(history []responses.SerialHistoryEntry) *responses.SerialDelivery {
+	for i := len(history) - 1; i >= 0; i-- {
+		h := history[i]
+		if h.ToStatus != database.SerialShipped || h.DocumentType == nil || *h.DocumentType != "delivery_note" || h.DocumentID == nil {
+			continue
+		}
+		return &responses.SerialDelivery{
+			DeliveryNoteID: *h.DocumentID,
+			DNNumber:       h.DocumentNumber,
+			SalesOrderID:   h.SalesOrderID,
+			SONumber:       h.SONumber,
+			CustomerID:     h.CustomerID,
+			CustomerCode:   h.CustomerCode,
+			CustomerName:   h.CustomerName,
+			ShippedAt:      h.CreatedAt,
+			DeliveredAt:    h.DeliveredAt,
+		}
+	}
+	return nil
+}
@@ -61,18 +61,19 @@ func (r *SerialsRepository) GetSerialsBySKU(tenantID, sku string) ([]database.Se
 	return serials, nil
 }
 
+// CreateSerial registers a serial as received and opens its chain of custody.
 func (r *SerialsRepository) CreateSerial(tenantID string, data *requests.CreateSerialRequest) *responses.InternalResponse {
-	now := tools.GetCurrentTime()
-
 	serial := &database.Serial{
 		TenantID:     tenantID,
 		SerialNumber: data.SerialNumber,
 		SKU:          data.SKU,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+		Status:       database.SerialReceived,
 	}
 
-	if err := r.DB.Create(serial).Error; err != nil {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		return createSerial(tx, serial, serialEvent{DocumentType: serialDocumentManual})
+	})
+	if err != nil {
 		return &responses.InternalResponse{
 			Error:   err,
 			Message: "Error al crear la serie",
@@ -11,6 +11,7 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/ports"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/gorm"
 )
 
 // SerialsRepositorySQLC implements ports.SerialsRepository using sqlc-generated queries.
@@ -19,6 +20,7 @@ import (
 // WHERE tenant_id = $N and inserts include tenant_id explicitly.
 type SerialsRepositorySQLC struct {
 	queries *sqlc.Queries
+	DB      *gorm.DB
 }
 
 // NewSerialsRepositorySQLC returns a serials repository backed by sqlc.
@@ -26,6 +28,12 @@ func NewSerialsRepositorySQLC(queries *sqlc.Queries) *SerialsRepositorySQLC {
 	return &SerialsRepositorySQLC{queries: queries}
 }
 
+// NewSerialsRepositorySQLCWithGORM returns a serials repository backed by sqlc, with GORM for
+// CreateSerial (which records the first serial_history row through the serial lifecycle).
+func NewSerialsRepositorySQLCWithGORM(queries *sqlc.Queries, db *gorm.DB) *SerialsRepositorySQLC {
+	return &SerialsRepositorySQLC{queries: queries, DB: db}
+}
+
 var _ ports.SerialsRepository = (*SerialsRepositorySQLC)(nil)
 
 func (r *SerialsRepositorySQLC) GetSerialByID(tenantID, id string) (*database.Serial, *responses.InternalResponse) {
@@ -66,24 +74,18 @@ func (r *SerialsRepositorySQLC) GetSerialsBySKU(tenantID, sku string) ([]databas
 	return out, nil
 }
 
+// CreateSerial delegates to the GORM-based SerialsRepository so the serial and the start of
+// its chain of custody are written together.
 func (r *SerialsRepositorySQLC) CreateSerial(tenantID string, data *requests.CreateSerialRequest) *responses.InternalResponse {
-	ctx := context.Background()
-	tid, err := stringToPgUUID(tenantID)
-	if err != nil {
-		return &responses.InternalResponse{Error: err, Message: "tenant_id inválido", Handled: true, StatusCode: responses.StatusBadRequest}
-	}
-	status := "available"
-	arg := sqlc.CreateSerialParams{
-		SerialNumber: data.SerialNumber,
-		Sku:          data.SKU,
-		Status:       status,
-		TenantID:     tid,
-	}
-	_, err = r.queries.CreateSerial(ctx, arg)
-	if err != nil {
-		return &responses.InternalResponse{Error: err, Message: "Error al crear la serie", Handled: false}
+	if r.DB == nil {
+		return &responses.InternalResponse{
+			Message:    "CreateSerial requiere conexión GORM — configure DB en el repositorio SQLC",
+			Handled:    true,
+			StatusCode: responses.StatusInternalServerError,
+		}
 	}
-	return nil
+	gormRepo := &SerialsRepository{DB: r.DB}
+	return gormRepo.CreateSerial(tenantID, data)
 }
 
 func (r *SerialsRepositorySQLC) UpdateSerial(tenantID, id string, data map[string]interface{}) *responses.InternalResponse {
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipmentLinesFromItems(t *testing.T) {
+	items := []requests.PickingTaskItemRequest{
+		{
+			SKU: "SKU-1",
+			Allocations: []database.LocationAllocation{
+				{Location: "A-01", Quantity: 4, LotNumber: tools.StrPtr("L1")},
+				{Location: "A-02", Quantity: 3, LotNumber: tools.StrPtr("L2"), PickedQty: tools.Float64Ptr(2)},
+				{Location: "A-03", Quantity: 1, LotNumber: tools.StrPtr("L1")},
+			},
+			SerialNumbers: []database.Serial{{SerialNumber: "S1"}, {SerialNumber: "S2"}},
+		},
+		{
+			SKU: "SKU-2",
+			Allocations: []database.LocationAllocation{
+				{Location: "B-01", Quantity: 5},
+				{Location: "B-02", Quantity: 5, PickedQty: tools.Float64Ptr(0)},
+			},
+		},
+	}
+
+	lines := shipmentLinesFromItems(items)
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, "SKU-1", lines[0].ArticleSKU)
+	assert.Equal(t, "L1", *lines[0].LotNumber)
+	assert.Equal(t, 5.0, lines[0].Qty, "allocations of the same lot are merged")
+	assert.Equal(t, []string{"S1", "S2"}, lines[0].SerialNumbers, "serials go on the SKU's first line")
+
+	assert.Equal(t, "L2", *lines[1].LotNumber)
+	assert.Equal(t, 2.0, lines[1].Qty, "picked quantity wins over the allocated one")
+	assert.Empty(t, lines[1].SerialNumbers)
+
+	assert.Equal(t, "SKU-2", lines[2].ArticleSKU)
+	assert.Nil(t, lines[2].LotNumber)
+	assert.Equal(t, 5.0, lines[2].Qty, "allocations picked short to zero are skipped")
+}
+
+func TestValidatePackContent(t *testing.T) {
+	available := map[string]bool{"S1": true, "S2": true}
+
+	assert.Nil(t, validatePackContent(3, available, 3, []string{"S1", "S2"}))
+
+	resp := validatePackContent(2, available, 2.5, nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode, "more than what is left to pack")
+
+	resp = validatePackContent(3, available, 1, []string{"S1", "S2"})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode, "more serials than quantity")
+
+	resp = validatePackContent(3, available, 2, []string{"S1", "S1"})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode, "repeated serial")
+
+	resp = validatePackContent(3, available, 1, []string{"S9"})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode, "serial not picked")
+}
+
+func TestAvailableSerials(t *testing.T) {
+	lines := []database.ShipmentLine{
+		{ID: "l1", ArticleSKU: "SKU-1", SerialNumbers: []string{"S1", "S2", "S3"}},
+		{ID: "l2", ArticleSKU: "SKU-1", LotNumber: tools.StrPtr("L2")},
+		{ID: "l3", ArticleSKU: "SKU-2", SerialNumbers: []string{"X1"}},
+	}
+	contents := []database.PackageContent{
+		{PackageID: "p1", ShipmentLineID: "l2", Qty: 1, SerialNumbers: []string{"S2"}},
+		{PackageID: "p1", ShipmentLineID: "l3", Qty: 1, SerialNumbers: []string{"X1"}},
+	}
+	assert.Equal(t, map[string]bool{"S1": true, "S3": true}, availableSerials(lines, contents, "SKU-1"))
+	assert.Empty(t, availableSerials(lines, contents, "SKU-2"))
+}
+
+func TestCloseChecks(t *testing.T) {
+	lines := []database.ShipmentLine{
+		{ID: "l1", ArticleSKU: "SKU-1", LotNumber: tools.StrPtr("L1"), Qty: 5},
+		{ID: "l2", ArticleSKU: "SKU-2", Qty: 2},
+	}
+	packages := []database.Package{{ID: "p1", SSCC: "000000000000000017"}, {ID: "p2", SSCC: "000000000000000024"}}
+	contents := []database.PackageContent{{PackageID: "p1", ShipmentLineID: "l1", Qty: 3}}
+
+	assert.Equal(t, []string{"SKU-1 (lote L1): 2.000", "SKU-2: 2.000"}, unpackedLines(lines, packedQtyByLine(contents)))
+	assert.Equal(t, []string{"000000000000000024"}, emptyPackages(packages, contents))
+
+	contents = append(contents,
+		database.PackageContent{PackageID: "p2", ShipmentLineID: "l1", Qty: 2},
+		database.PackageContent{PackageID: "p2", ShipmentLineID: "l2", Qty: 2},
+	)
+	assert.Empty(t, unpackedLines(lines, packedQtyByLine(contents)))
+	assert.Empty(t, emptyPackages(packages, contents))
+}
+
+func TestPackedDNItems(t *testing.T) {
+	lines := map[string]database.ShipmentLine{
+		"l1": {ID: "l1", ArticleSKU: "SKU-1", LotNumber: tools.StrPtr("L1"), Qty: 5},
+		"l2": {ID: "l2", ArticleSKU: "SKU-2", Qty: 2},
+	}
+	packages := []database.Package{{ID: "p2", Sequence: 2}, {ID: "p1", Sequence: 1}}
+	contents := []database.PackageContent{
+		{PackageID: "p2", ShipmentLineID: "l2", Qty: 2},
+		{PackageID: "p1", ShipmentLineID: "l1", Qty: 2},
+		{PackageID: "p1", ShipmentLineID: "l1", Qty: 1},
+		{PackageID: "p2", ShipmentLineID: "l1", Qty: 2},
+	}
+
+	items := packedDNItems(packages, contents, lines)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, "p1", *items[0].PackageID, "packages in sequence order")
+	assert.Equal(t, "SKU-1", items[0].ArticleSKU)
+	assert.Equal(t, 3.0, items[0].Qty, "contents of the same package + SKU + lot are merged")
+	assert.Equal(t, []string{"L1"}, items[0].LotNumbers)
+
+	assert.Equal(t, "p2", *items[1].PackageID)
+	assert.Equal(t, "SKU-2", items[1].ArticleSKU)
+	assert.Empty(t, items[1].LotNumbers)
+
+	assert.Equal(t, "p2", *items[2].PackageID)
+	assert.Equal(t, 2.0, items[2].Qty)
+}
+
+func TestBuildShipmentView(t *testing.T) {
+	s := database.Shipment{ID: "sh-1", ShipmentNumber: "SH-2026-0001", Status: "packing"}
+	lines := []database.ShipmentLine{{ID: "l1", ArticleSKU: "SKU-1", Qty: 5}}
+	packages := []database.Package{
+		{ID: "p2", Sequence: 2, WeightKg: tools.Float64Ptr(1.5)},
+		{ID: "p1", Sequence: 1, WeightKg: tools.Float64Ptr(10), LengthCm: tools.Float64Ptr(100), WidthCm: tools.Float64Ptr(50), HeightCm: tools.Float64Ptr(40)},
+	}
+	contents := []database.PackageContent{{ID: "c1", PackageID: "p1", ShipmentLineID: "l1", Qty: 4}}
+
+	view := buildShipmentView(s, lines, packages, contents)
+	require.Len(t, view.Lines, 1)
+	assert.Equal(t, 4.0, view.Lines[0].PackedQty)
+	assert.Equal(t, 1.0, view.Lines[0].RemainingQty)
+
+	require.Len(t, view.Packages, 2)
+	assert.Equal(t, "p1", view.Packages[0].ID)
+	require.NotNil(t, view.Packages[0].VolumeM3)
+	assert.InDelta(t, 0.2, *view.Packages[0].VolumeM3, 1e-9)
+	require.Len(t, view.Packages[0].Contents, 1)
+	assert.Equal(t, "SKU-1", view.Packages[0].Contents[0].ArticleSKU)
+	assert.Nil(t, view.Packages[1].VolumeM3, "missing dimensions give no volume")
+	assert.NotNil(t, view.Packages[1].Contents, "empty packages serialize an empty list")
+
+	assert.Equal(t, 2, view.TotalPackages)
+	assert.InDelta(t, 11.5, view.TotalWeightKg, 1e-9)
+	assert.InDelta(t, 0.2, view.TotalVolumeM3, 1e-9)
+}
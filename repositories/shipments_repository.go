@@ -0,0 +1,896 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShipmentsRepository implements ports.ShipmentsRepository using GORM.
+// GS1CompanyPrefix is the prefix the package SSCCs are built with (config GS1_COMPANY_PREFIX,
+// one for all tenants; validated at startup).
+type ShipmentsRepository struct {
+	DB               *gorm.DB
+	GS1CompanyPrefix string
+}
+
+var _ ports.ShipmentsRepository = (*ShipmentsRepository)(nil)
+
+// packQtyEpsilon absorbs float noise when comparing packed and picked quantities.
+const packQtyEpsilon = 1e-6
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextShipmentNumber generates "SH-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextShipmentNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("SH-%d-", year)
+
+	lockKey := fmt.Sprintf("sh-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire SH number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(shipment_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM shipments
+		WHERE tenant_id = $2
+		  AND shipment_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate SH number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// nextSSCC builds the SSCC of a new package from package_sscc_serial_seq. Pallets use
+// extension digit 1, cartons 0.
+func nextSSCC(tx *gorm.DB, companyPrefix, packageType string) (string, error) {
+	var serial int64
+	if err := tx.Raw(`SELECT nextval('package_sscc_serial_seq')`).Scan(&serial).Error; err != nil {
+		return "", fmt.Errorf("next sscc serial: %w", err)
+	}
+	var extension byte
+	if packageType == "pallet" {
+		extension = 1
+	}
+	return tools.BuildSSCC(extension, companyPrefix, serial)
+}
+
+// tenantRequiresPacking reads stock_settings.require_packing (false when the tenant has no settings).
+func tenantRequiresPacking(tx *gorm.DB, tenantID string) (bool, error) {
+	var required bool
+	if err := tx.Raw(`SELECT require_packing FROM stock_settings WHERE tenant_id = ?`, tenantID).Scan(&required).Error; err != nil {
+		return false, fmt.Errorf("read require_packing: %w", err)
+	}
+	return required, nil
+}
+
+// shipmentLinesFromItems aggregates the picked quantity of a picking task per SKU + lot, in
+// the order the lines first appear. Picking tracks serials per SKU, so they go on the SKU's
+// first line.
+func shipmentLinesFromItems(items []requests.PickingTaskItemRequest) []ShipmentLineCreationParam {
+	var lines []ShipmentLineCreationParam
+	index := make(map[string]int)
+	firstLine := make(map[string]int)
+	for _, item := range items {
+		for _, alloc := range item.Allocations {
+			qty := alloc.Quantity
+			if alloc.PickedQty != nil {
+				qty = *alloc.PickedQty
+			}
+			if qty <= 0 {
+				continue
+			}
+			var lot *string
+			key := item.SKU + "\x00"
+			if alloc.LotNumber != nil && *alloc.LotNumber != "" {
+				l := *alloc.LotNumber
+				lot = &l
+				key += l
+			}
+			if i, ok := index[key]; ok {
+				lines[i].Qty += qty
+				continue
+			}
+			index[key] = len(lines)
+			if _, ok := firstLine[item.SKU]; !ok {
+				firstLine[item.SKU] = len(lines)
+			}
+			lines = append(lines, ShipmentLineCreationParam{ArticleSKU: item.SKU, LotNumber: lot, Qty: qty})
+		}
+	}
+	for _, item := range items {
+		i, ok := firstLine[item.SKU]
+		if !ok {
+			continue
+		}
+		for _, s := range item.SerialNumbers {
+			if s.SerialNumber != "" {
+				lines[i].SerialNumbers = append(lines[i].SerialNumbers, s.SerialNumber)
+			}
+		}
+	}
+	return lines
+}
+
+// packedQtyByLine sums the packed quantity per shipment line.
+func packedQtyByLine(contents []database.PackageContent) map[string]float64 {
+	out := make(map[string]float64)
+	for _, c := range contents {
+		out[c.ShipmentLineID] += c.Qty
+	}
+	return out
+}
+
+// availableSerials returns the serials picked for sku that are not packed yet.
+func availableSerials(lines []database.ShipmentLine, contents []database.PackageContent, sku string) map[string]bool {
+	skuLines := make(map[string]bool)
+	out := make(map[string]bool)
+	for _, l := range lines {
+		if l.ArticleSKU != sku {
+			continue
+		}
+		skuLines[l.ID] = true
+		for _, s := range l.SerialNumbers {
+			out[s] = true
+		}
+	}
+	for _, c := range contents {
+		if !skuLines[c.ShipmentLineID] {
+			continue
+		}
+		for _, s := range c.SerialNumbers {
+			delete(out, s)
+		}
+	}
+	return out
+}
+
+// validatePackContent checks a content against what is left of its line: qty at most the
+// remaining quantity, and serials distinct, not more than qty, and among the available ones.
+func validatePackContent(remaining float64, available map[string]bool, qty float64, serials []string) *responses.InternalResponse {
+	if qty > remaining+packQtyEpsilon {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("La cantidad a empacar (%.3f) excede lo pendiente de la línea (%.3f)", qty, math.Max(remaining, 0)),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+	if float64(len(serials)) > qty+packQtyEpsilon {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("Se indicaron %d series para una cantidad de %.3f", len(serials), qty),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	seen := make(map[string]bool, len(serials))
+	for _, s := range serials {
+		if seen[s] {
+			return &responses.InternalResponse{Message: fmt.Sprintf("Serie repetida: %s", s), Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+		seen[s] = true
+		if !available[s] {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("La serie %s no fue pickeada para este artículo o ya está empacada", s),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
+	return nil
+}
+
+// unpackedLines describes the lines that still have quantity to pack.
+func unpackedLines(lines []database.ShipmentLine, packed map[string]float64) []string {
+	var out []string
+	for _, l := range lines {
+		remaining := l.Qty - packed[l.ID]
+		if remaining <= packQtyEpsilon {
+			continue
+		}
+		label := l.ArticleSKU
+		if l.LotNumber != nil {
+			label += " (lote " + *l.LotNumber + ")"
+		}
+		out = append(out, fmt.Sprintf("%s: %.3f", label, remaining))
+	}
+	return out
+}
+
+// emptyPackages returns the SSCCs of the packages without contents.
+func emptyPackages(packages []database.Package, contents []database.PackageContent) []string {
+	filled := make(map[string]bool)
+	for _, c := range contents {
+		filled[c.PackageID] = true
+	}
+	var out []string
+	for _, p := range packages {
+		if !filled[p.ID] {
+			out = append(out, p.SSCC)
+		}
+	}
+	return out
+}
+
+// packedDNItems builds the delivery note items of a packed shipment: one item per package +
+// SKU + lot, packages in sequence order.
+func packedDNItems(packages []database.Package, contents []database.PackageContent, lines map[string]database.ShipmentLine) []DNItemCreationParam {
+	ordered := make([]database.Package, len(packages))
+	copy(ordered, packages)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Sequence < ordered[j].Sequence })
+
+	var items []DNItemCreationParam
+	for _, p := range ordered {
+		pkgID := p.ID
+		index := make(map[string]int)
+		for _, c := range contents {
+			if c.PackageID != p.ID {
+				continue
+			}
+			line, ok := lines[c.ShipmentLineID]
+			if !ok {
+				continue
+			}
+			key := line.ArticleSKU + "\x00"
+			if line.LotNumber != nil {
+				key += *line.LotNumber
+			}
+			if i, ok := index[key]; ok {
+				items[i].Qty += c.Qty
+				items[i].SerialNumbers = append(items[i].SerialNumbers, c.SerialNumbers...)
+				continue
+			}
+			index[key] = len(items)
+			item := DNItemCreationParam{ArticleSKU: line.ArticleSKU, Qty: c.Qty, PackageID: &pkgID}
+			if line.LotNumber != nil {
+				item.LotNumbers = []string{*line.LotNumber}
+			}
+			item.SerialNumbers = append(item.SerialNumbers, c.SerialNumbers...)
+			items = append(items, item)
+		}
+	}
+	attachUnpackedSerials(items, lines)
+	return items
+}
+
+// attachUnpackedSerials puts the picked serials that were not assigned to a package on the
+// first delivery note item of their SKU, so every picked serial ships with the note.
+func attachUnpackedSerials(items []DNItemCreationParam, lines map[string]database.ShipmentLine) {
+	packed := make(map[string]bool)
+	firstItem := make(map[string]int)
+	for i, it := range items {
+		for _, sn := range it.SerialNumbers {
+			packed[it.ArticleSKU+"\x00"+sn] = true
+		}
+		if _, ok := firstItem[it.ArticleSKU]; !ok {
+			firstItem[it.ArticleSKU] = i
+		}
+	}
+	ids := make([]string, 0, len(lines))
+	for id := range lines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		line := lines[id]
+		i, ok := firstItem[line.ArticleSKU]
+		if !ok {
+			continue
+		}
+		for _, sn := range line.SerialNumbers {
+			if key := line.ArticleSKU + "\x00" + sn; !packed[key] {
+				packed[key] = true
+				items[i].SerialNumbers = append(items[i].SerialNumbers, sn)
+			}
+		}
+	}
+}
+
+// packageVolumeM3 returns length × width × height in m³, or nil when a dimension is missing.
+func packageVolumeM3(p database.Package) *float64 {
+	if p.LengthCm == nil || p.WidthCm == nil || p.HeightCm == nil {
+		return nil
+	}
+	v := *p.LengthCm * *p.WidthCm * *p.HeightCm / 1e6
+	return &v
+}
+
+// buildShipmentView assembles the shipment view: lines with packed / remaining qty, packages
+// in sequence order with their contents, and the weight and volume totals.
+func buildShipmentView(s database.Shipment, lines []database.ShipmentLine, packages []database.Package, contents []database.PackageContent) *responses.ShipmentView {
+	packed := packedQtyByLine(contents)
+	linesByID := make(map[string]database.ShipmentLine, len(lines))
+	view := &responses.ShipmentView{
+		Shipment: s,
+		Lines:    make([]responses.ShipmentLineView, 0, len(lines)),
+		Packages: make([]responses.PackageView, 0, len(packages)),
+	}
+	for _, l := range lines {
+		linesByID[l.ID] = l
+		remaining := l.Qty - packed[l.ID]
+		if remaining < packQtyEpsilon {
+			remaining = 0
+		}
+		view.Lines = append(view.Lines, responses.ShipmentLineView{ShipmentLine: l, PackedQty: packed[l.ID], RemainingQty: remaining})
+	}
+
+	ordered := make([]database.Package, len(packages))
+	copy(ordered, packages)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Sequence < ordered[j].Sequence })
+	for _, p := range ordered {
+		pv := responses.PackageView{Package: p, VolumeM3: packageVolumeM3(p), Contents: []responses.PackageContentView{}}
+		for _, c := range contents {
+			if c.PackageID != p.ID {
+				continue
+			}
+			line := linesByID[c.ShipmentLineID]
+			pv.Contents = append(pv.Contents, responses.PackageContentView{PackageContent: c, ArticleSKU: line.ArticleSKU, LotNumber: line.LotNumber})
+		}
+		if p.WeightKg != nil {
+			view.TotalWeightKg += *p.WeightKg
+		}
+		if pv.VolumeM3 != nil {
+			view.TotalVolumeM3 += *pv.VolumeM3
+		}
+		view.Packages = append(view.Packages, pv)
+	}
+	view.TotalPackages = len(view.Packages)
+	return view
+}
+
+// lockPackingShipment loads a tenant's shipment FOR UPDATE and checks it is still packing.
+// A non-nil response is the handled 404 / 409.
+func lockPackingShipment(tx *gorm.DB, id, tenantID string) (*database.Shipment, *responses.InternalResponse, error) {
+	var s database.Shipment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Envío no encontrado", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, fmt.Errorf("load shipment: %w", err)
+	}
+	if s.Status != "packing" {
+		return nil, &responses.InternalResponse{Message: "El envío ya fue cerrado; no se pueden modificar sus bultos", Handled: true, StatusCode: responses.StatusConflict}, nil
+	}
+	return &s, nil, nil
+}
+
+// loadShipmentPackage loads a package of the shipment. A non-nil response is the handled 404.
+func loadShipmentPackage(tx *gorm.DB, shipmentID, packageID string) (*database.Package, *responses.InternalResponse, error) {
+	var p database.Package
+	if err := tx.Where("id = ? AND shipment_id = ?", packageID, shipmentID).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Bulto no encontrado", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, fmt.Errorf("load package: %w", err)
+	}
+	return &p, nil, nil
+}
+
+// loadShipmentContents returns the lines, packages and contents of a shipment.
+func loadShipmentContents(tx *gorm.DB, shipmentID string) ([]database.ShipmentLine, []database.Package, []database.PackageContent, error) {
+	var lines []database.ShipmentLine
+	if err := tx.Where("shipment_id = ?", shipmentID).Order("article_sku, lot_number NULLS FIRST, id").Find(&lines).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("load shipment lines: %w", err)
+	}
+	var packages []database.Package
+	if err := tx.Where("shipment_id = ?", shipmentID).Order("sequence").Find(&packages).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("load packages: %w", err)
+	}
+	var contents []database.PackageContent
+	if err := tx.Raw(`
+		SELECT pc.*
+		  FROM package_contents pc
+		  JOIN packages p ON p.id = pc.package_id
+		 WHERE p.shipment_id = ?
+		 ORDER BY pc.created_at, pc.id
+	`, shipmentID).Scan(&contents).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("load package contents: %w", err)
+	}
+	return lines, packages, contents, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Shipment creation (called from picking_task_repository — not a port method)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ShipmentCreationParams groups everything needed to open a shipment inside a tx.
+type ShipmentCreationParams struct {
+	TenantID      string
+	SalesOrderID  string
+	PickingTaskID string
+	CustomerID    string
+	Lines         []ShipmentLineCreationParam
+}
+
+// ShipmentLineCreationParam is one picked SKU + lot of the shipment.
+type ShipmentLineCreationParam struct {
+	ArticleSKU    string
+	LotNumber     *string
+	Qty           float64
+	SerialNumbers []string
+}
+
+// CreateShipment inserts a shipment in "packing" with its lines. Called after a picking task
+// completes when the tenant requires packing. Returns the created shipment ID.
+func CreateShipment(tx *gorm.DB, params ShipmentCreationParams) (string, error) {
+	number, err := nextShipmentNumber(tx, params.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("nextShipmentNumber: %w", err)
+	}
+
+	shipmentID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return "", fmt.Errorf("generate shipment id: %w", err)
+	}
+
+	s := &database.Shipment{
+		ID:             shipmentID,
+		TenantID:       params.TenantID,
+		ShipmentNumber: number,
+		PickingTaskID:  params.PickingTaskID,
+		SalesOrderID:   params.SalesOrderID,
+		Status:         "packing",
+	}
+	if params.CustomerID != "" {
+		customerID := params.CustomerID
+		s.CustomerID = &customerID
+	}
+	if err := tx.Create(s).Error; err != nil {
+		return "", fmt.Errorf("create shipment: %w", err)
+	}
+
+	for _, l := range params.Lines {
+		lineID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return "", fmt.Errorf("generate shipment line id: %w", err)
+		}
+		serials := make([]string, len(l.SerialNumbers))
+		copy(serials, l.SerialNumbers)
+		line := &database.ShipmentLine{
+			ID:            lineID,
+			ShipmentID:    shipmentID,
+			ArticleSKU:    l.ArticleSKU,
+			LotNumber:     l.LotNumber,
+			Qty:           l.Qty,
+			SerialNumbers: serials,
+		}
+		if err := tx.Create(line).Error; err != nil {
+			return "", fmt.Errorf("create shipment line %s: %w", l.ArticleSKU, err)
+		}
+	}
+
+	return shipmentID, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// ports.ShipmentsRepository implementation
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *ShipmentsRepository) ListShipments(tenantID string, status *string, limit, offset int) ([]database.Shipment, *responses.InternalResponse) {
+	query := r.DB.Model(&database.Shipment{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var shipments []database.Shipment
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&shipments).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar los envíos"}
+	}
+	return shipments, nil
+}
+
+func (r *ShipmentsRepository) GetShipment(id, tenantID string) (*responses.ShipmentView, *responses.InternalResponse) {
+	var s database.Shipment
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Envío no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el envío"}
+	}
+
+	lines, packages, contents, err := loadShipmentContents(r.DB, s.ID)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el contenido del envío"}
+	}
+	view := buildShipmentView(s, lines, packages, contents)
+
+	var header struct {
+		SONumber     string  `gorm:"column:so_number"`
+		CustomerName *string `gorm:"column:customer_name"`
+		DNNumber     *string `gorm:"column:dn_number"`
+	}
+	if err := r.DB.Raw(`
+		SELECT so.so_number, c.name AS customer_name, dn.dn_number
+		  FROM shipments sh
+		  LEFT JOIN sales_orders so ON so.id = sh.sales_order_id
+		  LEFT JOIN clients c ON c.id = sh.customer_id
+		  LEFT JOIN delivery_notes dn ON dn.id = sh.delivery_note_id
+		 WHERE sh.id = ?
+	`, s.ID).Scan(&header).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener el envío"}
+	}
+	view.SONumber = header.SONumber
+	view.CustomerName = header.CustomerName
+	view.DNNumber = header.DNNumber
+	return view, nil
+}
+
+func (r *ShipmentsRepository) AddPackage(shipmentID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	packageType := req.PackageType
+	if packageType == "" {
+		packageType = "carton"
+	}
+
+	var result *database.Package
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, shipmentID, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		var sequence int
+		if err := tx.Raw(`SELECT COALESCE(MAX(sequence), 0) + 1 FROM packages WHERE shipment_id = ?`, s.ID).Scan(&sequence).Error; err != nil {
+			return fmt.Errorf("next package sequence: %w", err)
+		}
+		sscc, err := nextSSCC(tx, r.GS1CompanyPrefix, packageType)
+		if err != nil {
+			return err
+		}
+		packageID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate package id: %w", err)
+		}
+
+		p := database.Package{
+			ID:          packageID,
+			TenantID:    tenantID,
+			ShipmentID:  s.ID,
+			SSCC:        sscc,
+			PackageType: packageType,
+			Sequence:    sequence,
+			WeightKg:    req.WeightKg,
+			LengthCm:    req.LengthCm,
+			WidthCm:     req.WidthCm,
+			HeightCm:    req.HeightCm,
+		}
+		if err := tx.Create(&p).Error; err != nil {
+			return fmt.Errorf("create package: %w", err)
+		}
+		result = &p
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear el bulto"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
+
+func (r *ShipmentsRepository) UpdatePackage(shipmentID, packageID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	var result *database.Package
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, shipmentID, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		p, resp, err := loadShipmentPackage(tx, s.ID, packageID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		// The SSCC keeps the extension digit it was issued with; the type is informational.
+		updates := map[string]interface{}{"updated_at": tools.GetCurrentTime()}
+		if req.PackageType != "" {
+			updates["package_type"] = req.PackageType
+		}
+		if req.WeightKg != nil {
+			updates["weight_kg"] = *req.WeightKg
+		}
+		if req.LengthCm != nil {
+			updates["length_cm"] = *req.LengthCm
+		}
+		if req.WidthCm != nil {
+			updates["width_cm"] = *req.WidthCm
+		}
+		if req.HeightCm != nil {
+			updates["height_cm"] = *req.HeightCm
+		}
+		if err := tx.Model(&database.Package{}).Where("id = ?", p.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update package: %w", err)
+		}
+		if err := tx.Where("id = ?", p.ID).First(p).Error; err != nil {
+			return fmt.Errorf("reload package: %w", err)
+		}
+		result = p
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al actualizar el bulto"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
+
+func (r *ShipmentsRepository) DeletePackage(shipmentID, packageID, tenantID string) *responses.InternalResponse {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, shipmentID, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		p, resp, err := loadShipmentPackage(tx, s.ID, packageID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		// Contents go with the package (ON DELETE CASCADE).
+		if err := tx.Delete(&database.Package{}, "id = ?", p.ID).Error; err != nil {
+			return fmt.Errorf("delete package: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al eliminar el bulto"}
+	}
+	if handledResp.Handled {
+		return handledResp
+	}
+	return nil
+}
+
+func (r *ShipmentsRepository) AddPackageContent(shipmentID, packageID, tenantID string, req *requests.AddPackageContentRequest) (*database.PackageContent, *responses.InternalResponse) {
+	var result *database.PackageContent
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, shipmentID, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		p, resp, err := loadShipmentPackage(tx, s.ID, packageID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		lines, _, contents, err := loadShipmentContents(tx, s.ID)
+		if err != nil {
+			return err
+		}
+		var line *database.ShipmentLine
+		for i := range lines {
+			if lines[i].ID == req.ShipmentLineID {
+				line = &lines[i]
+				break
+			}
+		}
+		if line == nil {
+			*handledResp = responses.InternalResponse{Message: "Línea del envío no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+			return nil
+		}
+
+		remaining := line.Qty - packedQtyByLine(contents)[line.ID]
+		if resp := validatePackContent(remaining, availableSerials(lines, contents, line.ArticleSKU), *req.Qty, req.SerialNumbers); resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		contentID, err := tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate package content id: %w", err)
+		}
+		serials := make([]string, len(req.SerialNumbers))
+		copy(serials, req.SerialNumbers)
+		c := database.PackageContent{
+			ID:             contentID,
+			PackageID:      p.ID,
+			ShipmentLineID: line.ID,
+			Qty:            *req.Qty,
+			SerialNumbers:  serials,
+		}
+		if err := tx.Create(&c).Error; err != nil {
+			return fmt.Errorf("create package content: %w", err)
+		}
+		result = &c
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al empacar en el bulto"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
+
+func (r *ShipmentsRepository) RemovePackageContent(shipmentID, packageID, contentID, tenantID string) *responses.InternalResponse {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, shipmentID, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		p, resp, err := loadShipmentPackage(tx, s.ID, packageID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+		res := tx.Delete(&database.PackageContent{}, "id = ? AND package_id = ?", contentID, p.ID)
+		if res.Error != nil {
+			return fmt.Errorf("delete package content: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			*handledResp = responses.InternalResponse{Message: "Contenido del bulto no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return &responses.InternalResponse{Error: err, Message: "Error al quitar el contenido del bulto"}
+	}
+	if handledResp.Handled {
+		return handledResp
+	}
+	return nil
+}
+
+func (r *ShipmentsRepository) CloseShipment(id, tenantID, userID string, req *requests.CloseShipmentRequest) (*database.Shipment, *responses.InternalResponse) {
+	var result *database.Shipment
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		s, resp, err := lockPackingShipment(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		lines, packages, contents, err := loadShipmentContents(tx, s.ID)
+		if err != nil {
+			return err
+		}
+		if len(packages) == 0 {
+			*handledResp = responses.InternalResponse{Message: "El envío no tiene bultos", Handled: true, StatusCode: responses.StatusConflict}
+			return nil
+		}
+		if empty := emptyPackages(packages, contents); len(empty) > 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Hay bultos vacíos: %s", strings.Join(empty, ", ")),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+		if gaps := unpackedLines(lines, packedQtyByLine(contents)); len(gaps) > 0 {
+			*handledResp = responses.InternalResponse{
+				Message:    fmt.Sprintf("Faltan por empacar: %s", strings.Join(gaps, "; ")),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+			return nil
+		}
+
+		linesByID := make(map[string]database.ShipmentLine, len(lines))
+		for _, l := range lines {
+			linesByID[l.ID] = l
+		}
+		dnParams := DNCreationParams{
+			TenantID:      s.TenantID,
+			SalesOrderID:  s.SalesOrderID,
+			PickingTaskID: s.PickingTaskID,
+			Items:         packedDNItems(packages, contents, linesByID),
+		}
+		if s.CustomerID != nil {
+			dnParams.CustomerID = *s.CustomerID
+		}
+		dnID, err := CreateDeliveryNote(tx, dnParams)
+		if err != nil {
+			return err
+		}
+
+		now := tools.GetCurrentTime()
+		updates := map[string]interface{}{
+			"status":           "packed",
+			"delivery_note_id": dnID,
+			"packed_at":        now,
+			"updated_at":       now,
+		}
+		if userID != "" {
+			updates["packed_by"] = userID
+		}
+		if req != nil && req.Notes != nil {
+			updates["notes"] = *req.Notes
+		}
+		if err := tx.Model(&database.Shipment{}).Where("id = ?", s.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("close shipment: %w", err)
+		}
+		if err := tx.Where("id = ?", s.ID).First(s).Error; err != nil {
+			return fmt.Errorf("reload shipment: %w", err)
+		}
+		result = s
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al cerrar el envío"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return result, nil
+}
@@ -25,17 +25,16 @@ import (
 	"gorm.io/gorm"
 )
 
-// seedInventoryRow inserts an inventory row for a SKU. Note that inventory itself
-// has no tenant_id column — multi-tenancy is enforced by the FK to articles.sku.
+// seedInventoryRow inserts an inventory row for a SKU, owned by the SKU's article tenant.
 func seedInventoryRow(t *testing.T, db *gorm.DB, sku, location string, qty float64) string {
 	t.Helper()
 	id, err := tools.GenerateNanoid(db)
 	require.NoError(t, err)
 	require.NoError(t, db.Exec(`
 		INSERT INTO inventory
-			(id, sku, name, location, quantity, status, presentation, unit_price, created_at, updated_at)
-		VALUES (?, ?, 'Test Item', ?, ?, 'available', 'unit', 1.50, NOW(), NOW())`,
-		id, sku, location, qty).Error)
+			(id, tenant_id, sku, name, location, quantity, status, presentation, unit_price, created_at, updated_at)
+		VALUES (?, (SELECT tenant_id FROM articles WHERE sku = ?), ?, 'Test Item', ?, ?, 'available', 'unit', 1.50, NOW(), NOW())`,
+		id, sku, sku, location, qty).Error)
 	return id
 }
 
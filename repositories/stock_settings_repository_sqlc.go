@@ -51,6 +51,7 @@ func (r *StockSettingsRepositorySQLC) GetOrCreate(tenantID string) (*database.St
 			ExpiryAlertDays:           30,
 			AutoCreateMaterialRequest: false,
 			PartialDeliveryPolicy:     "immediate",
+			BaseCurrency:              database.DefaultCurrency,
 		}
 		s, err = r.queries.UpsertStockSettings(ctx, defaults)
 		if err != nil {
@@ -81,6 +82,14 @@ func (r *StockSettingsRepositorySQLC) Upsert(tenantID string, data *requests.Upd
 		ExpiryAlertDays:           int32(data.ExpiryAlertDays),
 		AutoCreateMaterialRequest: data.AutoCreateMaterialRequest,
 		PartialDeliveryPolicy:     data.PartialDeliveryPolicy,
+		BaseCurrency:              data.BaseCurrency,
+		RequirePacking:            data.RequirePacking,
+		RequireLotRelease:         data.RequireLotRelease,
+		LotNumberPattern:          ptrStringToPgText(data.LotNumberPattern),
+		SerialNumberPattern:       ptrStringToPgText(data.SerialNumberPattern),
+	}
+	if arg.BaseCurrency == "" {
+		arg.BaseCurrency = database.DefaultCurrency
 	}
 	s, err := r.queries.UpsertStockSettings(ctx, arg)
 	if err != nil {
@@ -104,6 +113,11 @@ func sqlcStockSettingToDatabase(s sqlc.StockSetting) database.StockSetting {
 		AutoCreateMaterialRequest: s.AutoCreateMaterialRequest,
 		PartialDeliveryPolicy:     s.PartialDeliveryPolicy,
 		UpdatedAt:                 s.UpdatedAt,
+		BaseCurrency:              s.BaseCurrency,
+		RequirePacking:            s.RequirePacking,
+		RequireLotRelease:         s.RequireLotRelease,
+		LotNumberPattern:          pgTextToPtrString(s.LotNumberPattern),
+		SerialNumberPattern:       pgTextToPtrString(s.SerialNumberPattern),
 	}
 }
 
@@ -106,11 +106,12 @@ func (r *StockTransfersRepositorySQLC) CreateStockTransfer(req *requests.StockTr
 
 	for _, line := range req.Lines {
 		lineArg := sqlc.CreateStockTransferLineParams{
-			StockTransferID: transfer.ID,
-			Sku:             line.Sku,
-			Quantity:        floatToPgNumericStockTransfer(line.Quantity),
-			Presentation:    textToPgType(line.Presentation),
-			LineStatus:      "pending",
+			StockTransferID:    transfer.ID,
+			Sku:                line.Sku,
+			Quantity:           floatToPgNumericStockTransfer(line.Quantity),
+			Presentation:       textToPgType(line.Presentation),
+			LineStatus:         "pending",
+			PresentationFactor: ptrFloatToPgNumeric(line.PresentationFactor),
 		}
 		_, err = r.queries.CreateStockTransferLine(ctx, lineArg)
 		if err != nil {
@@ -189,11 +190,12 @@ func (r *StockTransfersRepositorySQLC) ListStockTransferLines(transferID string)
 func (r *StockTransfersRepositorySQLC) CreateStockTransferLine(transferID string, req *requests.StockTransferLineInput) (*database.StockTransferLine, *responses.InternalResponse) {
 	ctx := context.Background()
 	arg := sqlc.CreateStockTransferLineParams{
-		StockTransferID: transferID,
-		Sku:             req.Sku,
-		Quantity:        floatToPgNumericStockTransfer(req.Quantity),
-		Presentation:    textToPgType(req.Presentation),
-		LineStatus:      "pending",
+		StockTransferID:    transferID,
+		Sku:                req.Sku,
+		Quantity:           floatToPgNumericStockTransfer(req.Quantity),
+		Presentation:       textToPgType(req.Presentation),
+		LineStatus:         "pending",
+		PresentationFactor: ptrFloatToPgNumeric(req.PresentationFactor),
 	}
 	row, err := r.queries.CreateStockTransferLine(ctx, arg)
 	if err != nil {
@@ -206,10 +208,11 @@ func (r *StockTransfersRepositorySQLC) CreateStockTransferLine(transferID string
 func (r *StockTransfersRepositorySQLC) UpdateStockTransferLine(lineID string, req *requests.StockTransferLineUpdate) (*database.StockTransferLine, *responses.InternalResponse) {
 	ctx := context.Background()
 	arg := sqlc.UpdateStockTransferLineParams{
-		ID:           lineID,
-		Quantity:     floatToPgNumericStockTransfer(req.Quantity),
-		Presentation: textToPgType(req.Presentation),
-		LineStatus:   req.LineStatus,
+		ID:                 lineID,
+		Quantity:           floatToPgNumericStockTransfer(req.Quantity),
+		Presentation:       textToPgType(req.Presentation),
+		LineStatus:         req.LineStatus,
+		PresentationFactor: ptrFloatToPgNumeric(req.PresentationFactor),
 	}
 	if arg.LineStatus == "" {
 		arg.LineStatus = "pending"
@@ -262,13 +265,18 @@ func stockTransferRowToDatabase(id, transferNumber, fromLocationID, toLocationID
 
 func sqlcTransferLineToDatabase(row sqlc.StockTransferLine) database.StockTransferLine {
 	return database.StockTransferLine{
-		ID:              row.ID,
-		StockTransferID: row.StockTransferID,
-		Sku:             row.Sku,
-		Quantity:        pgNumericToFloat(row.Quantity),
-		Presentation:   pgTextToPtrString(row.Presentation),
-		LineStatus:      row.LineStatus,
-		CreatedAt:       pgTimestampToTime(row.CreatedAt),
+		ID:                 row.ID,
+		StockTransferID:    row.StockTransferID,
+		Sku:                row.Sku,
+		Quantity:           pgNumericToFloat(row.Quantity),
+		Presentation:       pgTextToPtrString(row.Presentation),
+		LineStatus:         row.LineStatus,
+		CreatedAt:          pgTimestampToTime(row.CreatedAt),
+		ShippedQty:         pgNumericToFloat(row.ShippedQty),
+		ReceivedQty:        pgNumericToFloat(row.ReceivedQty),
+		DiscrepancyReason:  pgTextToPtrString(row.DiscrepancyReason),
+		ReceivedAt:         pgTimestampToPtrTime(row.ReceivedAt),
+		PresentationFactor: pgNumericToPtrFloat(row.PresentationFactor),
 	}
 }
 
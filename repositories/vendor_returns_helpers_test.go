@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPORejectedBySKU(t *testing.T) {
+	rejected := poRejectedBySKU([]database.PurchaseOrderItem{
+		{ArticleSKU: "A", RejectedQty: 2},
+		{ArticleSKU: "A", RejectedQty: 1.5},
+		{ArticleSKU: "B"},
+	})
+	assert.Equal(t, map[string]float64{"A": 3.5}, rejected)
+}
+
+func TestTaskRejectedBySKU(t *testing.T) {
+	rejected := taskRejectedBySKU([]database.ReceivingTaskItem{
+		{SKU: "A", RejectedQty: 4},
+		{SKU: "B", AcceptedQty: 10},
+	})
+	assert.Equal(t, map[string]float64{"A": 4}, rejected)
+}
+
+func TestResolveVendorReturnLines_DefaultsToOpenRejections(t *testing.T) {
+	lines, resp := resolveVendorReturnLines(nil,
+		map[string]float64{"B": 3, "A": 5, "C": 1},
+		vendorClaimedBySKU([]database.VendorReturnLine{{ArticleSKU: "A", Qty: 2}, {ArticleSKU: "C", Qty: 1}}))
+	require.Nil(t, resp)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "A", lines[0].ArticleSKU)
+	assert.InDelta(t, 3, *lines[0].Qty, 1e-9)
+	assert.Equal(t, "B", lines[1].ArticleSKU)
+	assert.InDelta(t, 3, *lines[1].Qty, 1e-9)
+}
+
+func TestResolveVendorReturnLines_NothingOpen(t *testing.T) {
+	_, resp := resolveVendorReturnLines(nil, map[string]float64{"A": 2}, map[string]float64{"A": 2})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+
+	_, resp = resolveVendorReturnLines(nil, map[string]float64{}, nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+}
+
+func TestResolveVendorReturnLines_Requested(t *testing.T) {
+	rejected := map[string]float64{"A": 5}
+	claimed := map[string]float64{"A": 2}
+
+	ok := []requests.VendorReturnLineRequest{
+		{ArticleSKU: "A", Qty: tools.Float64Ptr(1), Reason: tools.StrPtr("damaged")},
+		{ArticleSKU: "A", Qty: tools.Float64Ptr(2)},
+	}
+	lines, resp := resolveVendorReturnLines(ok, rejected, claimed)
+	require.Nil(t, resp)
+	assert.Equal(t, ok, lines)
+
+	_, resp = resolveVendorReturnLines([]requests.VendorReturnLineRequest{{ArticleSKU: "A", Qty: tools.Float64Ptr(3.5)}}, rejected, claimed)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+
+	_, resp = resolveVendorReturnLines([]requests.VendorReturnLineRequest{{ArticleSKU: "Z", Qty: tools.Float64Ptr(1)}}, rejected, claimed)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestBuildVendorReturnView_Totals(t *testing.T) {
+	credited := 20.0
+	view := buildVendorReturnView(
+		database.VendorReturn{ID: "r1", Status: database.VendorReturnCredited, CreditedAmount: &credited},
+		[]database.VendorReturnLine{
+			{ArticleSKU: "A", Qty: 2, UnitCost: tools.Float64Ptr(5), Currency: tools.StrPtr("USD")},
+			{ArticleSKU: "B", Qty: 1, UnitCost: tools.Float64Ptr(12), Currency: tools.StrPtr("usd")},
+			{ArticleSKU: "C", Qty: 3},
+			{ArticleSKU: "D", Qty: 1, UnitCost: tools.Float64Ptr(100), Currency: tools.StrPtr("EUR")},
+		})
+	assert.InDelta(t, 7, view.TotalQty, 1e-9)
+	assert.InDelta(t, 22, view.ExpectedCredit, 1e-9)
+	require.NotNil(t, view.Currency)
+	assert.Equal(t, "USD", *view.Currency)
+	require.NotNil(t, view.CreditDifference)
+	assert.InDelta(t, -2, *view.CreditDifference, 1e-9)
+
+	empty := buildVendorReturnView(database.VendorReturn{ID: "r2"}, nil)
+	assert.NotNil(t, empty.Lines)
+	assert.Nil(t, empty.CreditDifference)
+}
+
+func TestVendorReturnTransition(t *testing.T) {
+	assert.Nil(t, vendorReturnTransition(database.VendorReturnDraft, database.VendorReturnShipped))
+	assert.Nil(t, vendorReturnTransition(database.VendorReturnShipped, database.VendorReturnCredited))
+	assert.Nil(t, vendorReturnTransition(database.VendorReturnDraft, database.VendorReturnCancelled))
+
+	for _, tc := range [][2]string{
+		{database.VendorReturnDraft, database.VendorReturnCredited},
+		{database.VendorReturnShipped, database.VendorReturnShipped},
+		{database.VendorReturnShipped, database.VendorReturnCancelled},
+		{database.VendorReturnCredited, database.VendorReturnCancelled},
+		{database.VendorReturnCancelled, database.VendorReturnShipped},
+	} {
+		resp := vendorReturnTransition(tc[0], tc[1])
+		require.NotNil(t, resp, "%s -> %s", tc[0], tc[1])
+		assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	}
+}
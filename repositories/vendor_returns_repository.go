@@ -0,0 +1,572 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VendorReturnsRepository implements ports.VendorReturnsRepository using GORM.
+type VendorReturnsRepository struct {
+	DB *gorm.DB
+}
+
+var _ ports.VendorReturnsRepository = (*VendorReturnsRepository)(nil)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// nextVendorReturnNumber generates "RTV-YYYY-NNNN" unique per tenant per year inside tx.
+// Uses pg_advisory_xact_lock like nextDNNumber.
+func nextVendorReturnNumber(tx *gorm.DB, tenantID string) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("RTV-%d-", year)
+
+	lockKey := fmt.Sprintf("rtv-number-%s-%d", tenantID, year)
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey).Error; err != nil {
+		return "", fmt.Errorf("acquire RTV number lock: %w", err)
+	}
+
+	var maxNum int
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(return_number FROM LENGTH($1)+1) AS INTEGER)
+		), 0)
+		FROM vendor_returns
+		WHERE tenant_id = $2
+		  AND return_number LIKE $3
+	`, prefix, tenantID, prefix+"%").Scan(&maxNum).Error; err != nil {
+		return "", fmt.Errorf("generate RTV number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, maxNum+1), nil
+}
+
+// poRejectedBySKU sums the rejected quantity of purchase order items per SKU.
+func poRejectedBySKU(items []database.PurchaseOrderItem) map[string]float64 {
+	out := make(map[string]float64)
+	for _, it := range items {
+		if it.RejectedQty > 0 {
+			out[it.ArticleSKU] += it.RejectedQty
+		}
+	}
+	return out
+}
+
+// taskRejectedBySKU sums the rejected quantity of receiving task items per SKU.
+func taskRejectedBySKU(items []database.ReceivingTaskItem) map[string]float64 {
+	out := make(map[string]float64)
+	for _, it := range items {
+		if it.RejectedQty > 0 {
+			out[it.SKU] += it.RejectedQty
+		}
+	}
+	return out
+}
+
+// vendorClaimedBySKU sums the quantity per SKU of vendor return lines.
+func vendorClaimedBySKU(lines []database.VendorReturnLine) map[string]float64 {
+	out := make(map[string]float64)
+	for _, l := range lines {
+		out[l.ArticleSKU] += l.Qty
+	}
+	return out
+}
+
+// resolveVendorReturnLines checks the requested lines against rejected minus claimed per SKU:
+// every SKU must have rejections (400) and the quantity per SKU must fit what is still open
+// (409). Without requested lines it returns one line per SKU with everything still open,
+// 409 when nothing is. SKUs are iterated in sorted order so lines and messages are stable.
+func resolveVendorReturnLines(lines []requests.VendorReturnLineRequest, rejected, claimed map[string]float64) ([]requests.VendorReturnLineRequest, *responses.InternalResponse) {
+	skus := make([]string, 0, len(rejected))
+	for sku := range rejected {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	if len(lines) == 0 {
+		var out []requests.VendorReturnLineRequest
+		for _, sku := range skus {
+			open := rejected[sku] - claimed[sku]
+			if open <= returnQtyEpsilon {
+				continue
+			}
+			out = append(out, requests.VendorReturnLineRequest{ArticleSKU: sku, Qty: &open})
+		}
+		if len(out) == 0 {
+			return nil, &responses.InternalResponse{
+				Message:    "No hay cantidades rechazadas pendientes de devolver al proveedor",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+		return out, nil
+	}
+
+	requested := make(map[string]float64)
+	for _, l := range lines {
+		if rejected[l.ArticleSKU] <= 0 {
+			return nil, &responses.InternalResponse{
+				Message:    fmt.Sprintf("El artículo %s no tiene cantidades rechazadas", l.ArticleSKU),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		requested[l.ArticleSKU] += *l.Qty
+	}
+	for _, sku := range skus {
+		qty, ok := requested[sku]
+		if !ok {
+			continue
+		}
+		open := rejected[sku] - claimed[sku]
+		if qty > open+returnQtyEpsilon {
+			return nil, &responses.InternalResponse{
+				Message:    fmt.Sprintf("Cantidad a devolver de %s (%.3f) excede lo rechazado pendiente de devolver (%.3f)", sku, qty, open),
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
+	return lines, nil
+}
+
+// buildVendorReturnView assembles the return view with its totals. The currency is the one of
+// the first costed line; lines in another currency are left out of the expected credit.
+func buildVendorReturnView(ret database.VendorReturn, lines []database.VendorReturnLine) *responses.VendorReturnView {
+	view := &responses.VendorReturnView{VendorReturn: ret, Lines: lines}
+	if view.Lines == nil {
+		view.Lines = []database.VendorReturnLine{}
+	}
+	costed := false
+	for _, l := range lines {
+		view.TotalQty += l.Qty
+		if l.UnitCost == nil {
+			continue
+		}
+		if !costed {
+			view.Currency = l.Currency
+			costed = true
+		} else if !sameCurrency(view.Currency, l.Currency) {
+			continue
+		}
+		view.ExpectedCredit += l.Qty * *l.UnitCost
+	}
+	if ret.CreditedAmount != nil {
+		diff := *ret.CreditedAmount - view.ExpectedCredit
+		view.CreditDifference = &diff
+	}
+	return view
+}
+
+// sameCurrency compares two optional currency codes; nil is the tenant base currency.
+func sameCurrency(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return strings.EqualFold(*a, *b)
+}
+
+// vendorReturnTransition checks a status change: ship needs a draft, credit a shipped return
+// and cancel a draft. A non-nil response is the handled 409.
+func vendorReturnTransition(current, target string) *responses.InternalResponse {
+	from := map[string]string{
+		database.VendorReturnShipped:   database.VendorReturnDraft,
+		database.VendorReturnCredited:  database.VendorReturnShipped,
+		database.VendorReturnCancelled: database.VendorReturnDraft,
+	}
+	if from[target] == current {
+		return nil
+	}
+	msg := map[string]string{
+		database.VendorReturnShipped:   "Solo se puede despachar una devolución en borrador",
+		database.VendorReturnCredited:  "Solo se puede acreditar una devolución despachada",
+		database.VendorReturnCancelled: "Solo se puede cancelar una devolución en borrador",
+	}[target]
+	return &responses.InternalResponse{Message: msg, Handled: true, StatusCode: responses.StatusConflict}
+}
+
+// lockVendorReturn loads a tenant's vendor return FOR UPDATE. A non-nil response is the handled 404.
+func lockVendorReturn(tx *gorm.DB, id, tenantID string) (*database.VendorReturn, *responses.InternalResponse, error) {
+	var ret database.VendorReturn
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&ret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Devolución a proveedor no encontrada", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, fmt.Errorf("load vendor return: %w", err)
+	}
+	return &ret, nil, nil
+}
+
+// openVendorReturnLines returns the lines of vendor returns that are not cancelled, linked to
+// the given purchase order (column "purchase_order_id") or receiving task ("receiving_task_id").
+func openVendorReturnLines(tx *gorm.DB, column, id string) ([]database.VendorReturnLine, error) {
+	var lines []database.VendorReturnLine
+	if err := tx.Table("vendor_return_lines l").
+		Select("l.*").
+		Joins("JOIN vendor_returns r ON r.id = l.return_id").
+		Where("r."+column+" = ? AND r.status <> ?", id, database.VendorReturnCancelled).
+		Scan(&lines).Error; err != nil {
+		return nil, fmt.Errorf("load open vendor return lines: %w", err)
+	}
+	return lines, nil
+}
+
+// lockReceivingTaskRejections loads a tenant's receiving task FOR UPDATE with its rejected
+// quantities per SKU. A non-nil response is the handled 404.
+func lockReceivingTaskRejections(tx *gorm.DB, id, tenantID string) (*database.ReceivingTask, map[string]float64, *responses.InternalResponse, error) {
+	var task database.ReceivingTask
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND tenant_id = ?", id, tenantID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, &responses.InternalResponse{Message: "Tarea de recepción no encontrada", Handled: true, StatusCode: responses.StatusNotFound}, nil
+		}
+		return nil, nil, nil, fmt.Errorf("load receiving task: %w", err)
+	}
+	var items []database.ReceivingTaskItem
+	if len(task.Items) > 0 {
+		if err := json.Unmarshal(task.Items, &items); err != nil {
+			return nil, nil, nil, fmt.Errorf("unmarshal receiving task items: %w", err)
+		}
+	}
+	return &task, taskRejectedBySKU(items), nil, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Port methods
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (r *VendorReturnsRepository) ListReturns(tenantID string, status, purchaseOrderID, supplierID *string, limit, offset int) ([]database.VendorReturn, *responses.InternalResponse) {
+	query := r.DB.Model(&database.VendorReturn{}).Where("tenant_id = ?", tenantID)
+	if status != nil && *status != "" {
+		query = query.Where("status = ?", *status)
+	}
+	if purchaseOrderID != nil && *purchaseOrderID != "" {
+		query = query.Where("purchase_order_id = ?", *purchaseOrderID)
+	}
+	if supplierID != nil && *supplierID != "" {
+		query = query.Where("supplier_id = ?", *supplierID)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var returns []database.VendorReturn
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&returns).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al listar las devoluciones a proveedor"}
+	}
+	return returns, nil
+}
+
+func (r *VendorReturnsRepository) GetReturn(id, tenantID string) (*responses.VendorReturnView, *responses.InternalResponse) {
+	var ret database.VendorReturn
+	if err := r.DB.Where("id = ? AND tenant_id = ?", id, tenantID).First(&ret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &responses.InternalResponse{Message: "Devolución a proveedor no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la devolución a proveedor"}
+	}
+
+	var lines []database.VendorReturnLine
+	if err := r.DB.Where("return_id = ?", ret.ID).Order("article_sku, id").Find(&lines).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener las líneas de la devolución a proveedor"}
+	}
+	view := buildVendorReturnView(ret, lines)
+
+	var header struct {
+		PONumber        *string `gorm:"column:po_number"`
+		InboundNumber   *string `gorm:"column:inbound_number"`
+		SupplierName    string  `gorm:"column:supplier_name"`
+		SupplierAddress *string `gorm:"column:supplier_address"`
+		SupplierTaxID   *string `gorm:"column:supplier_tax_id"`
+	}
+	if err := r.DB.Raw(`
+		SELECT po.po_number, rt.inbound_number,
+		       c.name AS supplier_name, c.address AS supplier_address, c.tax_id AS supplier_tax_id
+		  FROM vendor_returns vr
+		  LEFT JOIN purchase_orders po ON po.id = vr.purchase_order_id
+		  LEFT JOIN receiving_tasks rt ON rt.id = vr.receiving_task_id
+		  LEFT JOIN clients c ON c.id = vr.supplier_id
+		 WHERE vr.id = ?
+	`, ret.ID).Scan(&header).Error; err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al obtener la devolución a proveedor"}
+	}
+	view.PONumber = header.PONumber
+	view.InboundNumber = header.InboundNumber
+	view.SupplierName = header.SupplierName
+	view.SupplierAddress = header.SupplierAddress
+	view.SupplierTaxID = header.SupplierTaxID
+	return view, nil
+}
+
+func (r *VendorReturnsRepository) CreateReturn(tenantID, userID string, req *requests.CreateVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	var returnID string
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var task *database.ReceivingTask
+		var taskRejected map[string]float64
+		purchaseOrderID := req.PurchaseOrderID
+		if req.ReceivingTaskID != nil {
+			t, rejected, resp, err := lockReceivingTaskRejections(tx, *req.ReceivingTaskID, tenantID)
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			task, taskRejected = t, rejected
+			purchaseOrderID = task.PurchaseOrderID
+		}
+
+		// Lock the purchase order so concurrent returns of its rejections see each other's claims.
+		var po *database.PurchaseOrder
+		var poItems []database.PurchaseOrderItem
+		if purchaseOrderID != nil {
+			var order database.PurchaseOrder
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND tenant_id = ? AND deleted_at IS NULL", *purchaseOrderID, tenantID).First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					*handledResp = responses.InternalResponse{Message: "Orden de compra no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+					return nil
+				}
+				return fmt.Errorf("load purchase order: %w", err)
+			}
+			if err := tx.Where("purchase_order_id = ?", order.ID).Order("created_at, id").Find(&poItems).Error; err != nil {
+				return fmt.Errorf("load purchase order items: %w", err)
+			}
+			po = &order
+		}
+
+		var supplierID string
+		switch {
+		case po != nil:
+			supplierID = po.SupplierID
+		case task.SupplierID != nil:
+			supplierID = *task.SupplierID
+		default:
+			*handledResp = responses.InternalResponse{Message: "La tarea de recepción no tiene proveedor ni orden de compra", Handled: true, StatusCode: responses.StatusConflict}
+			return nil
+		}
+
+		lines := req.Lines
+		if task != nil {
+			claimed, err := openVendorReturnLines(tx, "receiving_task_id", task.ID)
+			if err != nil {
+				return err
+			}
+			resolved, resp := resolveVendorReturnLines(lines, taskRejected, vendorClaimedBySKU(claimed))
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			lines = resolved
+		}
+		// PO-wide: returns against the order and against its receiving tasks share what the
+		// order rejected.
+		if po != nil {
+			claimed, err := openVendorReturnLines(tx, "purchase_order_id", po.ID)
+			if err != nil {
+				return err
+			}
+			resolved, resp := resolveVendorReturnLines(lines, poRejectedBySKU(poItems), vendorClaimedBySKU(claimed))
+			if resp != nil {
+				*handledResp = *resp
+				return nil
+			}
+			lines = resolved
+		}
+
+		number, err := nextVendorReturnNumber(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		returnID, err = tools.GenerateNanoid(tx)
+		if err != nil {
+			return fmt.Errorf("generate vendor return id: %w", err)
+		}
+		ret := database.VendorReturn{
+			ID:           returnID,
+			TenantID:     tenantID,
+			ReturnNumber: number,
+			SupplierID:   supplierID,
+			Status:       database.VendorReturnDraft,
+			Notes:        req.Notes,
+		}
+		if po != nil {
+			ret.PurchaseOrderID = &po.ID
+		}
+		if task != nil {
+			ret.ReceivingTaskID = &task.ID
+		}
+		if userID != "" {
+			ret.CreatedBy = &userID
+		}
+		if err := tx.Create(&ret).Error; err != nil {
+			return fmt.Errorf("create vendor return: %w", err)
+		}
+
+		for _, l := range lines {
+			lineID, err := tools.GenerateNanoid(tx)
+			if err != nil {
+				return fmt.Errorf("generate vendor return line id: %w", err)
+			}
+			line := database.VendorReturnLine{
+				ID:         lineID,
+				ReturnID:   returnID,
+				ArticleSKU: l.ArticleSKU,
+				Qty:        *l.Qty,
+				Reason:     l.Reason,
+			}
+			for _, it := range poItems {
+				if it.ArticleSKU == l.ArticleSKU {
+					line.PurchaseOrderItemID = &it.ID
+					line.UnitCost = it.UnitCost
+					line.Currency = it.Currency
+					break
+				}
+			}
+			if err := tx.Create(&line).Error; err != nil {
+				return fmt.Errorf("create vendor return line: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al crear la devolución a proveedor"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetReturn(returnID, tenantID)
+}
+
+func (r *VendorReturnsRepository) ShipReturn(id, tenantID string, req *requests.ShipVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		ret, resp, err := lockVendorReturn(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = vendorReturnTransition(ret.Status, database.VendorReturnShipped)
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		shippedAt := tools.GetCurrentTime()
+		if req.ShippedAt != nil {
+			shippedAt = *req.ShippedAt
+		}
+		return tx.Model(&database.VendorReturn{}).Where("id = ?", ret.ID).Updates(map[string]interface{}{
+			"status":          database.VendorReturnShipped,
+			"carrier":         req.Carrier,
+			"tracking_number": req.TrackingNumber,
+			"shipped_at":      shippedAt,
+			"updated_at":      tools.GetCurrentTime(),
+		}).Error
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al despachar la devolución a proveedor"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetReturn(id, tenantID)
+}
+
+func (r *VendorReturnsRepository) CreditReturn(id, tenantID string, req *requests.CreditVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		ret, resp, err := lockVendorReturn(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = vendorReturnTransition(ret.Status, database.VendorReturnCredited)
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		creditedAt := tools.GetCurrentTime()
+		if req.CreditedAt != nil {
+			creditedAt = *req.CreditedAt
+		}
+		return tx.Model(&database.VendorReturn{}).Where("id = ?", ret.ID).Updates(map[string]interface{}{
+			"status":             database.VendorReturnCredited,
+			"credit_note_number": req.CreditNoteNumber,
+			"credited_amount":    *req.CreditedAmount,
+			"credited_at":        creditedAt,
+			"updated_at":         tools.GetCurrentTime(),
+		}).Error
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al registrar el crédito de la devolución a proveedor"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return r.GetReturn(id, tenantID)
+}
+
+func (r *VendorReturnsRepository) CancelReturn(id, tenantID string) (*database.VendorReturn, *responses.InternalResponse) {
+	var ret *database.VendorReturn
+	handledResp := &responses.InternalResponse{}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		locked, resp, err := lockVendorReturn(tx, id, tenantID)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			resp = vendorReturnTransition(locked.Status, database.VendorReturnCancelled)
+		}
+		if resp != nil {
+			*handledResp = *resp
+			return nil
+		}
+
+		now := tools.GetCurrentTime()
+		if err := tx.Model(&database.VendorReturn{}).Where("id = ?", locked.ID).Updates(map[string]interface{}{
+			"status":       database.VendorReturnCancelled,
+			"cancelled_at": now,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return fmt.Errorf("cancel vendor return: %w", err)
+		}
+		locked.Status = database.VendorReturnCancelled
+		locked.CancelledAt = &now
+		locked.UpdatedAt = now
+		ret = locked
+		return nil
+	})
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al cancelar la devolución a proveedor"}
+	}
+	if handledResp.Handled {
+		return nil, handledResp
+	}
+	return ret, nil
+}
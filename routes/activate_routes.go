@@ -31,8 +31,9 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, pool *pgxpool.Pool, config confi
 	RegisterDashboardRoutes(api, db, config, rolesRepo)
 	RegisterInventoryRoutes(api, db, pool, config, rolesRepo)
 	RegisterSerialRoutes(api, db, pool, config, rolesRepo)
-	RegisterReceivingTasksRoutes(api, db, config, notifSvc, pool, rolesRepo)
+	RegisterReceivingTasksRoutes(api, db, config, auditSvc, notifSvc, pool, rolesRepo)
 	RegisterPickingTasksRoutes(api, db, config, auditSvc, notifSvc, pool, rolesRepo)
+	RegisterPickingWavesRoutes(api, db, config, auditSvc, notifSvc, rolesRepo)
 	RegisterAdjustmentsRoutes(api, db, pool, config, auditSvc, rolesRepo)
 	RegisterStockAlertsRoutes(api, db, config, redisClient, rolesRepo)
 	RegisterInventoryMovementsRoutes(api, db, config)
@@ -47,20 +48,35 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, pool *pgxpool.Pool, config confi
 	RegisterPresentationConversionsRoutes(api, pool, config, rolesRepo)
 	RegisterStockTransfersRoutes(api, db, pool, config, rolesRepo, auditSvc)
 	RegisterLotsRoutes(api, db, pool, config, rolesRepo)
+	RegisterQCHoldsRoutes(api, db, config, auditSvc, rolesRepo)
+	RegisterLotRecallsRoutes(api, db, config, auditSvc, notifSvc, rolesRepo)
+	RegisterKitsRoutes(api, db, config, auditSvc, rolesRepo)
+	RegisterLabelsRoutes(api, db, config, rolesRepo)
+	RegisterArticleBarcodesRoutes(api, db, config, rolesRepo)
+	RegisterPutawayRoutes(api, db, config, rolesRepo)
 	RegisterRolesRoutes(api, config, rolesRepo)
-	RegisterAdminCronRoutes(api, db, config, rolesRepo)
+	RegisterAdminCronRoutes(api, db, pool, config, rolesRepo)
 	RegisterClientsRoutes(api, pool, config, rolesRepo)
 	RegisterCategoriesRoutes(api, pool, config, rolesRepo)
 	RegisterStockSettingsRoutes(api, pool, config, rolesRepo)
+	RegisterExchangeRatesRoutes(api, db, config, rolesRepo)
+	RegisterNumberSeriesRoutes(api, db, config, rolesRepo)
 	RegisterNotificationsRoutes(api, db, config, notifSvc)
 	RegisterPurchaseOrdersRoutes(api, db, config, rolesRepo)
+	RegisterVendorReturnsRoutes(api, db, config, auditSvc, rolesRepo)
+	RegisterReplenishmentRoutes(api, db, pool, config, rolesRepo)
 
 	// S3-W2-B: Sales Orders
 	RegisterSalesOrdersRoutes(api, db, config, rolesRepo)
 
 	// S3-W3-A: Delivery Notes + Backorders
-	RegisterDeliveryNotesRoutes(api, db, config, rolesRepo)
+	RegisterDeliveryNotesRoutes(api, db, config, auditSvc, notifSvc, rolesRepo)
+	RegisterShipmentsRoutes(api, db, config, auditSvc, rolesRepo)
 	RegisterBackordersRoutes(api, db, config, rolesRepo)
+	RegisterCustomerReturnsRoutes(api, db, config, auditSvc, rolesRepo)
+
+	// Cycle counting (count plans, blind counts, approval → count_reconcile adjustments)
+	RegisterCycleCountsRoutes(api, db, pool, config, rolesRepo, auditSvc)
 
 	// S3-W5-B: Stripe Billing
 	RegisterBillingRoutes(api, db, config, notifSvc, rolesRepo)
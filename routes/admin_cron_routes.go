@@ -5,14 +5,21 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/controllers"
 	"github.com/eflowcr/eSTOCK_backend/ports"
 	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gorm.io/gorm"
 )
 
 // RegisterAdminCronRoutes registers POST /api/admin/cron/trigger.
 // Requires JWT authentication and "cron":"trigger" permission (admin roles with {"all":true} qualify).
-func RegisterAdminCronRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
-	ctrl := controllers.NewAdminCronController(db)
+func RegisterAdminCronRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Pool, config configuration.Config, rolesRepo ports.RolesRepository) {
+	var replenisher func(tenantID string) error
+	if db != nil {
+		_, replenishmentSvc := wire.NewReplenishment(db, pool)
+		replenisher = replenishmentSvc.RunScheduled
+	}
+	ctrl := controllers.NewAdminCronController(db, replenisher)
 
 	route := router.Group("/admin/cron")
 	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterArticleBarcodesRoutes wires alternate article codes (/article-barcodes) and the
+// handheld scan resolver (/scan/parse).
+func RegisterArticleBarcodesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewArticleBarcodes(db)
+	ctrl := controllers.NewArticleBarcodesController(svc, config.TenantID)
+
+	read := tools.RequirePermission(rolesRepo, "inventory", "read")
+	update := tools.RequirePermission(rolesRepo, "inventory", "update")
+
+	barcodes := router.Group("/article-barcodes")
+	barcodes.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		barcodes.GET("/", read, ctrl.ListArticleBarcodes)
+		barcodes.POST("/", update, ctrl.CreateArticleBarcode)
+		barcodes.DELETE("/:id", update, ctrl.DeleteArticleBarcode)
+	}
+
+	scan := router.Group("/scan")
+	scan.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		scan.POST("/parse", read, ctrl.ParseScan)
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterCustomerReturnsRoutes wires customer returns (/api/customer-returns). Returns belong
+// to the sales order flow, so they use the sales_orders permissions.
+func RegisterCustomerReturnsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewCustomerReturns(db)
+	ctrl := controllers.NewCustomerReturnsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/customer-returns")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "sales_orders", "read")
+		write := tools.RequirePermission(rolesRepo, "sales_orders", "write")
+
+		route.GET("/", read, ctrl.ListReturns)
+		route.GET("/:id", read, ctrl.GetReturn)
+		route.POST("/", write, ctrl.CreateReturn)
+		route.POST("/:id/receive", write, ctrl.ReceiveReturn)
+		route.PATCH("/:id/cancel", write, ctrl.CancelReturn)
+	}
+}
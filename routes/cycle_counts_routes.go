@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gorm.io/gorm"
+)
+
+// RegisterCycleCountsRoutes wires count plan CRUD, task generation and the count task lifecycle.
+// Approving a task posts inventory adjustments, so it has its own "approve" permission.
+func RegisterCycleCountsRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Pool, config configuration.Config, rolesRepo ports.RolesRepository, auditSvc *services.AuditService) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewCycleCounts(db, pool)
+	ctrl := controllers.NewCycleCountsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/cycle-counts")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "cycle_counts", "read")
+		create := tools.RequirePermission(rolesRepo, "cycle_counts", "create")
+		update := tools.RequirePermission(rolesRepo, "cycle_counts", "update")
+		delete := tools.RequirePermission(rolesRepo, "cycle_counts", "delete")
+		approve := tools.RequirePermission(rolesRepo, "cycle_counts", "approve")
+
+		route.GET("/plans", read, ctrl.ListPlans)
+		route.GET("/plans/:id", read, ctrl.GetPlan)
+		route.POST("/plans", create, ctrl.CreatePlan)
+		route.PATCH("/plans/:id", update, ctrl.UpdatePlan)
+		route.DELETE("/plans/:id", delete, ctrl.DeletePlan)
+		route.POST("/plans/:id/generate", create, ctrl.GenerateTask)
+
+		route.GET("/tasks", read, ctrl.ListTasks)
+		route.GET("/tasks/:id", read, ctrl.GetTask)
+		route.GET("/tasks/:id/review", approve, ctrl.ReviewTask)
+		route.PATCH("/tasks/:id/start", update, ctrl.StartTask)
+		route.POST("/tasks/:id/lines/:lineId/count", update, ctrl.RecordCount)
+		route.PATCH("/tasks/:id/submit", update, ctrl.SubmitTask)
+		route.PATCH("/tasks/:id/approve", approve, ctrl.ApproveTask)
+		route.PATCH("/tasks/:id/reject", approve, ctrl.RejectTask)
+		route.PATCH("/tasks/:id/cancel", update, ctrl.CancelTask)
+	}
+}
@@ -4,24 +4,26 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/configuration"
 	"github.com/eflowcr/eSTOCK_backend/controllers"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
 	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/eflowcr/eSTOCK_backend/wire"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// RegisterDeliveryNotesRoutes wires /api/delivery-notes endpoints (DN3).
-func RegisterDeliveryNotesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+// RegisterDeliveryNotesRoutes wires /api/delivery-notes endpoints (DN3 + proof of delivery).
+func RegisterDeliveryNotesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, rolesRepo ports.RolesRepository) {
 	if db == nil {
 		return
 	}
-	_, svc := wire.NewDeliveryNotes(db)
-	ctrl := controllers.NewDeliveryNotesController(svc, config.TenantID)
+	_, svc := wire.NewDeliveryNotesWithNotifications(db, config, notifSvc)
+	ctrl := controllers.NewDeliveryNotesController(svc, config.TenantID, auditSvc)
 
 	route := router.Group("/delivery-notes")
 	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
 	{
 		read := tools.RequirePermission(rolesRepo, "delivery_notes", "read")
+		update := tools.RequirePermission(rolesRepo, "delivery_notes", "update")
 
 		// DN3 — list + detail + PDF download
 		route.GET("", read, ctrl.List)
@@ -29,5 +31,9 @@ func RegisterDeliveryNotesRoutes(router *gin.RouterGroup, db *gorm.DB, config co
 		// NOTE: /:id/pdf must be registered before /:id to avoid Gin ambiguity.
 		// Use separate route group to avoid conflicts.
 		route.GET("/:id/pdf", read, ctrl.DownloadPDF)
+
+		// Proof of delivery — record once, then serve its signature / photos.
+		route.POST("/:id/pod", update, ctrl.RecordPOD)
+		route.GET("/:id/pod/:file", read, ctrl.DownloadPODFile)
 	}
 }
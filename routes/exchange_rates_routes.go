@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterExchangeRatesRoutes wires the tenant's local exchange rate table.
+// Permissions reuse the settings resource, like the stock settings the base currency lives in.
+func RegisterExchangeRatesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewExchangeRates(db)
+	ctrl := controllers.NewExchangeRatesController(svc, config.TenantID)
+
+	route := router.Group("/settings/exchange-rates")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "settings", "read")
+		write := tools.RequirePermission(rolesRepo, "settings", "write")
+
+		route.GET("", read, ctrl.List)
+		route.GET("/convert", read, ctrl.Convert)
+		route.POST("", write, ctrl.Upsert)
+		route.DELETE("/:id", write, ctrl.Delete)
+	}
+}
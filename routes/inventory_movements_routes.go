@@ -29,5 +29,6 @@ func RegisterInventoryMovementsRoutes(router *gin.RouterGroup, db *gorm.DB, conf
 	globalRoute.Use(tools.JWTAuthMiddleware(config.JWTSecret))
 	{
 		globalRoute.GET("/", inventoryMovementsController.ListMovements)
+		globalRoute.GET("/:id/cogs", inventoryMovementsController.GetMovementCOGS)
 	}
 }
@@ -15,9 +15,8 @@ import (
 var _ ports.InventoryRepository = (*repositories.InventoryRepository)(nil)
 
 func RegisterInventoryRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Pool, config configuration.Config, rolesRepo ports.RolesRepository) {
-	// S3.5 W2-A: pass config so InventoryRepository stamps tenant_id on inventory_lots inserts.
-	_, inventoryService := wire.NewInventoryWithConfig(db, pool, config)
-	inventoryController := controllers.NewInventoryController(*inventoryService, config.JWTSecret)
+	_, inventoryService := wire.NewInventory(db, pool)
+	inventoryController := controllers.NewInventoryController(*inventoryService, config.JWTSecret, config.TenantID)
 
 	route := router.Group("/inventory")
 	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
@@ -30,6 +29,7 @@ func RegisterInventoryRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool
 
 		route.GET("/", inventoryController.GetAllInventory)
 		route.GET("/valuation", tools.RequirePermission(rolesRepo, "inventory", "read"), inventoryController.GetInventoryValuation)
+		route.GET("/valuation/export", tools.RequirePermission(rolesRepo, "inventory", "read"), inventoryController.ExportInventoryValuation)
 		route.GET("/pick-suggestions/:sku", inventoryController.GetPickSuggestions)
 		route.GET("/sku/:sku/location/:location", inventoryController.GetInventoryBySkuAndLocation)
 		route.POST("/", inventoryController.CreateInventory)
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterKitsRoutes wires kit bills of materials (/api/kits) and assembly orders
+// (/api/assembly-orders). Completing or cancelling an order requires assembly_orders "update".
+func RegisterKitsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewKits(db)
+	ctrl := controllers.NewKitsController(svc, config.TenantID, auditSvc)
+
+	kits := router.Group("/kits")
+	kits.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "kits", "read")
+		update := tools.RequirePermission(rolesRepo, "kits", "update")
+		remove := tools.RequirePermission(rolesRepo, "kits", "delete")
+
+		kits.GET("/", read, ctrl.ListKits)
+		kits.GET("/:sku", read, ctrl.GetKit)
+		kits.PUT("/:sku", update, ctrl.UpsertKit)
+		kits.DELETE("/:sku", remove, ctrl.DeleteKit)
+	}
+
+	orders := router.Group("/assembly-orders")
+	orders.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "assembly_orders", "read")
+		create := tools.RequirePermission(rolesRepo, "assembly_orders", "create")
+		update := tools.RequirePermission(rolesRepo, "assembly_orders", "update")
+
+		orders.GET("/", read, ctrl.ListAssemblyOrders)
+		orders.GET("/:id", read, ctrl.GetAssemblyOrder)
+		orders.POST("/", create, ctrl.CreateAssemblyOrder)
+		orders.POST("/:id/complete", update, ctrl.CompleteAssemblyOrder)
+		orders.PATCH("/:id/cancel", update, ctrl.CancelAssemblyOrder)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterLabelsRoutes wires barcode label printing (PDF and ZPL). Printing only reads
+// inventory master data, so it requires inventory read permission.
+func RegisterLabelsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewLabels(db)
+	ctrl := controllers.NewLabelsController(svc, config.TenantID)
+
+	route := router.Group("/labels")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "inventory", "read")
+
+		route.GET("/locations/:id", read, ctrl.LocationLabel)
+		route.GET("/articles/:id", read, ctrl.ArticleLabel)
+		route.GET("/lots/:id", read, ctrl.LotLabel)
+		route.GET("/serials/:id", read, ctrl.SerialLabel)
+		route.GET("/receiving-tasks/:id", read, ctrl.ReceivingTaskLabels)
+		route.POST("/batch", read, ctrl.BatchLabels)
+	}
+}
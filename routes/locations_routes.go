@@ -34,6 +34,7 @@ func RegisterLocationRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.
 			route.GET("/table", read, tools.GenericListHandler(pool, cfg))
 			route.GET("/table/export", read, tools.GenericExportHandler(pool, cfg, "locations.csv"))
 		}
+		route.GET("/utilization", read, locationController.GetLocationUtilization)
 		route.GET("/:id", read, locationController.GetLocationByID)
 		route.POST("/", create, locationController.CreateLocation)
 		route.PUT("/:id", update, locationController.UpdateLocation)
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterLotRecallsRoutes wires lot recalls (/api/lot-recalls). Operators complete the recall
+// tasks ("update"); opening a recall ("create") and closing or cancelling it ("close") stay with
+// Admin by default.
+func RegisterLotRecallsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewLotRecalls(db, notifSvc)
+	ctrl := controllers.NewLotRecallsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/lot-recalls")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "lot_recalls", "read")
+		create := tools.RequirePermission(rolesRepo, "lot_recalls", "create")
+		update := tools.RequirePermission(rolesRepo, "lot_recalls", "update")
+		closeRecall := tools.RequirePermission(rolesRepo, "lot_recalls", "close")
+
+		route.GET("/", read, ctrl.ListRecalls)
+		route.GET("/:id", read, ctrl.GetRecall)
+		route.GET("/:id/report", read, ctrl.DownloadReport)
+		route.POST("/", create, ctrl.CreateRecall)
+		route.PATCH("/:id/tasks/:taskId/complete", update, ctrl.CompleteTask)
+		route.PATCH("/:id/close", closeRecall, ctrl.CloseRecall)
+		route.PATCH("/:id/cancel", closeRecall, ctrl.CancelRecall)
+	}
+}
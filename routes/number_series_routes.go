@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/repositories"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var _ ports.NumberSeriesRepository = (*repositories.NumberSeriesRepository)(nil)
+
+// RegisterNumberSeriesRoutes wires the lot and serial number series preview. Permissions reuse
+// the settings resource, like the stock settings the tenant patterns live in.
+func RegisterNumberSeriesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewNumberSeries(db)
+	ctrl := controllers.NewNumberSeriesController(svc, config.TenantID)
+
+	route := router.Group("/settings/number-series")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "settings", "read")
+
+		route.GET("/preview", read, ctrl.Preview)
+	}
+}
@@ -19,7 +19,7 @@ func RegisterPickingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config con
 	_, clientsSvc := wire.NewClients(pool)
 	// SO3+DN1+BO1 — inject SalesOrdersRepository + DN PDF generator.
 	soRepo, _ := wire.NewSalesOrders(db, config)
-	_, pickingTasksService := wire.NewPickingTaskWithDN(db, auditSvc, notifSvc, soRepo)
+	_, pickingTasksService := wire.NewPickingTaskWithDN(db, config, auditSvc, notifSvc, soRepo)
 	if clientsSvc != nil {
 		pickingTasksService.WithClientsService(clientsSvc)
 	}
@@ -35,6 +35,7 @@ func RegisterPickingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config con
 
 		route.GET("/", read, pickingTasksController.GetAllPickingTasks)
 		route.GET("/:id", read, pickingTasksController.GetPickingTaskByID)
+		route.GET("/:id/route", read, pickingTasksController.GetPickRoute)
 		route.POST("/", create, pickingTasksController.CreatePickingTask)
 		route.PUT("/:id", update, pickingTasksController.UpdatePickingTask)
 		route.PATCH("/:id/start", update, pickingTasksController.StartPickingTask)
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterPickingWavesRoutes wires wave (batch) picking. Waves act on picking tasks, so they
+// use the picking_tasks permissions.
+func RegisterPickingWavesRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	soRepo, _ := wire.NewSalesOrders(db, config)
+	_, svc := wire.NewPickingWaves(db, config, auditSvc, notifSvc, soRepo)
+	ctrl := controllers.NewPickingWavesController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/picking-waves")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "picking_tasks", "read")
+		create := tools.RequirePermission(rolesRepo, "picking_tasks", "create")
+		update := tools.RequirePermission(rolesRepo, "picking_tasks", "update")
+
+		route.GET("/", read, ctrl.ListWaves)
+		route.GET("/:id", read, ctrl.GetWave)
+		route.GET("/:id/pick-list", read, ctrl.GetPickList)
+		route.POST("/", create, ctrl.CreateWave)
+		route.PATCH("/:id/start", update, ctrl.StartWave)
+		route.POST("/:id/picks", update, ctrl.RecordPick)
+		route.PATCH("/:id/complete", update, ctrl.CompleteWave)
+		route.PATCH("/:id/cancel", update, ctrl.CancelWave)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterPutawayRoutes wires putaway suggestions. Suggestions are read-only; auto-fill
+// rewrites the receiving task's line locations and needs receiving_tasks update.
+func RegisterPutawayRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewPutaway(db)
+	ctrl := controllers.NewPutawayController(svc, config.TenantID)
+
+	route := router.Group("/putaway")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "receiving_tasks", "read")
+		update := tools.RequirePermission(rolesRepo, "receiving_tasks", "update")
+
+		route.GET("/receiving-tasks/:id", read, ctrl.SuggestReceivingTask)
+		route.POST("/receiving-tasks/:id/apply", update, ctrl.ApplyReceivingTask)
+	}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterQCHoldsRoutes wires quarantine / QC holds (/api/qc-holds). Releasing or rejecting a
+// hold needs the qc_holds "release" permission, which only Admin has by default.
+func RegisterQCHoldsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewQCHolds(db, config)
+	ctrl := controllers.NewQCHoldsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/qc-holds")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "qc_holds", "read")
+		create := tools.RequirePermission(rolesRepo, "qc_holds", "create")
+		release := tools.RequirePermission(rolesRepo, "qc_holds", "release")
+
+		route.GET("/", read, ctrl.ListHolds)
+		route.GET("/:id", read, ctrl.GetHold)
+		route.GET("/:id/attachments/:attachmentId", read, ctrl.DownloadAttachment)
+		route.POST("/", create, ctrl.CreateHold)
+		route.POST("/:id/inspections", create, ctrl.RecordInspection)
+		route.POST("/:id/attachments", create, ctrl.AddAttachment)
+		route.PATCH("/:id/release", release, ctrl.ReleaseHold)
+		route.PATCH("/:id/reject", release, ctrl.RejectHold)
+	}
+}
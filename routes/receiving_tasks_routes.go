@@ -15,9 +15,9 @@ import (
 
 var _ ports.ReceivingTasksRepository = (*repositories.ReceivingTasksRepository)(nil)
 
-func RegisterReceivingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, notifSvc *services.NotificationsService, pool *pgxpool.Pool, rolesRepo ports.RolesRepository) {
+func RegisterReceivingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, pool *pgxpool.Pool, rolesRepo ports.RolesRepository) {
 	_, clientsSvc := wire.NewClients(pool)
-	_, receivingTasksService := wire.NewReceivingTasks(db, notifSvc)
+	_, receivingTasksService := wire.NewReceivingTasks(db, auditSvc, notifSvc)
 	if clientsSvc != nil {
 		receivingTasksService.WithClientsService(clientsSvc)
 	}
@@ -30,6 +30,8 @@ func RegisterReceivingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config c
 		read := tools.RequirePermission(rolesRepo, "receiving_tasks", "read")
 		create := tools.RequirePermission(rolesRepo, "receiving_tasks", "create")
 		update := tools.RequirePermission(rolesRepo, "receiving_tasks", "update")
+		// Receiving above StockSetting.OverReceiptAllowancePct needs a supervisor override.
+		override := tools.RequirePermission(rolesRepo, "receiving_tasks", "override")
 
 		route.GET("/", read, receivingTasksController.GetAllReceivingTasks)
 		route.GET("/:id", read, receivingTasksController.GetReceivingTaskByID)
@@ -40,6 +42,8 @@ func RegisterReceivingTasksRoutes(router *gin.RouterGroup, db *gorm.DB, config c
 		route.GET("/export", read, receivingTasksController.ExportReceivingTaskToExcel)
 		route.PATCH("/complete-full-task/:id/:location", update, receivingTasksController.CompleteFullTask)
 		route.PATCH("/complete-receiving-line/:id/:location", update, receivingTasksController.CompleteReceivingLine)
+		route.PATCH("/complete-full-task/:id/:location/override", override, receivingTasksController.OverrideCompleteFullTask)
+		route.PATCH("/complete-receiving-line/:id/:location/override", override, receivingTasksController.OverrideCompleteReceivingLine)
 		route.PATCH("/:id/supplier", update, receivingTasksController.LinkSupplier) // S2 R2 E1.7
 	}
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gorm.io/gorm"
+)
+
+// RegisterReplenishmentRoutes wires the replenishment preview and on-demand draft PO generation.
+// Permissions reuse the purchase_orders resource: reading suggestions needs read, drafting needs create.
+func RegisterReplenishmentRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Pool, config configuration.Config, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewReplenishment(db, pool)
+	ctrl := controllers.NewReplenishmentController(svc, config.TenantID)
+
+	route := router.Group("/replenishment")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "purchase_orders", "read")
+		create := tools.RequirePermission(rolesRepo, "purchase_orders", "create")
+
+		route.GET("/suggestions", read, ctrl.Preview)
+		route.POST("/generate", create, ctrl.Generate)
+	}
+}
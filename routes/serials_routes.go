@@ -14,6 +14,7 @@ import (
 
 var _ ports.SerialsRepository = (*repositories.SerialsRepository)(nil)
 var _ ports.SerialsRepository = (*repositories.SerialsRepositorySQLC)(nil)
+var _ ports.SerialLifecycleRepository = (*repositories.SerialLifecycleRepository)(nil)
 
 func RegisterSerialRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Pool, config configuration.Config, rolesRepo ports.RolesRepository) {
 	_, serialService := wire.NewSerials(db, pool)
@@ -28,10 +29,12 @@ func RegisterSerialRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pgxpool.Po
 		update := tools.RequirePermission(rolesRepo, "serials", "update")
 		delete := tools.RequirePermission(rolesRepo, "serials", "delete")
 
+		route.GET("/custody/:serialNumber", read, serialController.GetChainOfCustody)
 		route.GET("/:id", read, serialController.GetSerialByID)
 		route.GET("/by-sku/:sku", read, serialController.GetSerialsBySKU)
 		route.POST("/", create, serialController.CreateSerial)
 		route.PUT("/:id", update, serialController.UpdateSerial)
+		route.POST("/:id/transition", update, serialController.TransitionSerial)
 		route.DELETE("/:id", delete, serialController.DeleteSerial)
 	}
 }
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterShipmentsRoutes wires the packing stage (/api/shipments). Packing continues the
+// outbound flow of a picking task, so it uses the picking_tasks permissions.
+func RegisterShipmentsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewShipments(db, config)
+	ctrl := controllers.NewShipmentsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/shipments")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "picking_tasks", "read")
+		update := tools.RequirePermission(rolesRepo, "picking_tasks", "update")
+
+		route.GET("/", read, ctrl.ListShipments)
+		route.GET("/:id", read, ctrl.GetShipment)
+		route.GET("/:id/packing-list", read, ctrl.GetPackingList)
+		route.POST("/:id/packages", update, ctrl.AddPackage)
+		route.PATCH("/:id/packages/:packageId", update, ctrl.UpdatePackage)
+		route.DELETE("/:id/packages/:packageId", update, ctrl.DeletePackage)
+		route.POST("/:id/packages/:packageId/contents", update, ctrl.AddPackageContent)
+		route.DELETE("/:id/packages/:packageId/contents/:contentId", update, ctrl.RemovePackageContent)
+		route.PATCH("/:id/close", update, ctrl.CloseShipment)
+	}
+}
@@ -47,6 +47,9 @@ func RegisterStockTransfersRoutes(router *gin.RouterGroup, db *gorm.DB, pool *pg
 		route.PUT("/:id", updateInventory, ctrl.UpdateStockTransfer)
 		route.DELETE("/:id", updateInventory, ctrl.DeleteStockTransfer)
 		route.POST("/:id/execute", updateInventory, ctrl.ExecuteStockTransfer)
+		route.POST("/:id/ship", updateInventory, ctrl.ShipStockTransfer)
+		route.POST("/:id/receive", updateInventory, ctrl.ReceiveStockTransfer)
+		route.GET("/:id/discrepancies", readInventory, ctrl.GetStockTransferDiscrepancies)
 
 		route.GET("/:id/lines", readInventory, ctrl.ListStockTransferLines)
 		route.POST("/:id/lines", updateInventory, ctrl.CreateStockTransferLine)
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/configuration"
+	"github.com/eflowcr/eSTOCK_backend/controllers"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/services"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/eflowcr/eSTOCK_backend/wire"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterVendorReturnsRoutes wires returns to vendor (/api/vendor-returns). They come from
+// purchase order rejections, so they use the purchase_orders permissions.
+func RegisterVendorReturnsRoutes(router *gin.RouterGroup, db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, rolesRepo ports.RolesRepository) {
+	if db == nil {
+		return
+	}
+	_, svc := wire.NewVendorReturns(db)
+	ctrl := controllers.NewVendorReturnsController(svc, config.TenantID, auditSvc)
+
+	route := router.Group("/vendor-returns")
+	route.Use(tools.JWTAuthMiddleware(config.JWTSecret))
+	{
+		read := tools.RequirePermission(rolesRepo, "purchase_orders", "read")
+		create := tools.RequirePermission(rolesRepo, "purchase_orders", "create")
+		update := tools.RequirePermission(rolesRepo, "purchase_orders", "update")
+
+		route.GET("/", read, ctrl.ListReturns)
+		route.GET("/:id", read, ctrl.GetReturn)
+		route.GET("/:id/pdf", read, ctrl.DownloadPDF)
+		route.POST("/", create, ctrl.CreateReturn)
+		route.PATCH("/:id/ship", update, ctrl.ShipReturn)
+		route.PATCH("/:id/credit", update, ctrl.CreditReturn)
+		route.PATCH("/:id/cancel", update, ctrl.CancelReturn)
+	}
+}
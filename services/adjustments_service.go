@@ -70,7 +70,7 @@ func (s *AdjustmentsService) CreateAdjustment(userId string, tenantID string, ad
 	switch adjType {
 	case "decrease":
 		signedQuantity = -adjustment.AdjustmentQuantity
-		// Validate that the decrease won't violate reserved_qty.
+		// Validate that the decrease won't violate reserved_qty nor stock on QC hold.
 		inv, resp := s.Repository.GetInventoryForAdjustment(adjustment.SKU, adjustment.Location)
 		if resp != nil {
 			return nil, resp
@@ -87,6 +87,16 @@ func (s *AdjustmentsService) CreateAdjustment(userId string, tenantID string, ad
 				StatusCode: responses.StatusBadRequest,
 			}
 		}
+		if newQty < inv.ReservedQty+inv.HeldQty {
+			return nil, &responses.InternalResponse{
+				Message: fmt.Sprintf(
+					"no puede disminuir %.2f — hay %.2f uds retenidas por calidad (qty: %.2f, reservado: %.2f). Libere o rechace la retención",
+					math.Abs(signedQuantity), inv.HeldQty, inv.Quantity, inv.ReservedQty,
+				),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
 
 	case "count_reconcile":
 		// Target qty (absolute physical count). Compute delta = target - current.
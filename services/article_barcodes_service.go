@@ -0,0 +1,271 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+)
+
+// Scan purposes (requests.ScanParseRequest.Purpose).
+const (
+	ScanPurposeReceiving = "receiving"
+	ScanPurposePicking   = "picking"
+)
+
+// ArticleBarcodesService manages alternate article codes and resolves handheld scans
+// (GS1-128 / GS1 DataMatrix / plain codes) into receiving and picking line data.
+type ArticleBarcodesService struct {
+	Repository ports.ArticleBarcodesRepository
+}
+
+func NewArticleBarcodesService(repo ports.ArticleBarcodesRepository) *ArticleBarcodesService {
+	return &ArticleBarcodesService{Repository: repo}
+}
+
+func (s *ArticleBarcodesService) List(tenantID, sku string) ([]database.ArticleBarcode, *responses.InternalResponse) {
+	return s.Repository.List(tenantID, sku)
+}
+
+func (s *ArticleBarcodesService) Create(tenantID string, req *requests.CreateArticleBarcodeRequest) (*database.ArticleBarcode, *responses.InternalResponse) {
+	return s.Repository.Create(tenantID, req)
+}
+
+func (s *ArticleBarcodesService) Delete(tenantID, id string) *responses.InternalResponse {
+	return s.Repository.Delete(tenantID, id)
+}
+
+// ParseScan decodes a scan and resolves it to an article: GS1 data by GTIN (AI 01/02) through
+// article_barcodes or by SKU (AI 240, printed on eSTOCK labels); plain codes by registered
+// barcode, then by SKU. An unresolved scan is not an error — the decoded data is returned
+// with Resolved=false and a warning so the operator can pick the article by hand.
+func (s *ArticleBarcodesService) ParseScan(tenantID string, req *requests.ScanParseRequest) (*responses.ScanParseResult, *responses.InternalResponse) {
+	scan, isGS1, err := tools.ParseGS1(req.Code)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:      err,
+			Message:    "Código GS1 inválido: " + err.Error(),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	result := &responses.ScanParseResult{
+		Raw:       req.Code,
+		IsGS1:     isGS1,
+		Symbology: scan.Symbology,
+		Elements:  make([]responses.ScanElement, 0, len(scan.Elements)),
+		Quantity:  1,
+		Warnings:  []string{},
+	}
+	for _, e := range scan.Elements {
+		result.Elements = append(result.Elements, responses.ScanElement{AI: e.AI, Value: e.Value})
+	}
+
+	var (
+		article *database.Article
+		resp    *responses.InternalResponse
+		packQty = 1.0
+	)
+	if isGS1 {
+		article, packQty, resp = s.resolveGS1(tenantID, scan, result)
+	} else {
+		article, packQty, resp = s.resolvePlainCode(tenantID, strings.TrimSpace(req.Code), result)
+	}
+	if resp != nil {
+		return nil, resp
+	}
+	result.Quantity *= packQty
+
+	if article == nil {
+		if result.SSCC != nil && result.GTIN == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("El código es una unidad logística (SSCC %s), no un artículo", *result.SSCC))
+		} else {
+			result.Warnings = append(result.Warnings, "El código no está asociado a ningún artículo")
+		}
+		return result, nil
+	}
+
+	result.Resolved = true
+	result.Article = &responses.ScanArticle{
+		ID:              article.ID,
+		SKU:             article.SKU,
+		Name:            article.Name,
+		Presentation:    article.Presentation,
+		TrackByLot:      article.TrackByLot,
+		TrackBySerial:   article.TrackBySerial,
+		TrackExpiration: article.TrackExpiration,
+	}
+	if resp := s.fillLot(tenantID, req.Purpose, article, result); resp != nil {
+		return nil, resp
+	}
+	if resp := s.fillSerial(tenantID, req.Purpose, article, result); resp != nil {
+		return nil, resp
+	}
+	return result, nil
+}
+
+// resolveGS1 copies the known AIs into result and looks the article up.
+func (s *ArticleBarcodesService) resolveGS1(tenantID string, scan tools.GS1Scan, result *responses.ScanParseResult) (*database.Article, float64, *responses.InternalResponse) {
+	gtin := scan.Value(tools.GS1AIGTIN)
+	if gtin == "" {
+		gtin = scan.Value(tools.GS1AIContentGTIN)
+	}
+	if gtin != "" {
+		result.GTIN = &gtin
+	}
+	if sscc := scan.Value(tools.GS1AISSCC); sscc != "" {
+		result.SSCC = &sscc
+	}
+	if lot := scan.Value(tools.GS1AILot); lot != "" {
+		result.LotNumber = &lot
+	}
+	if serial := scan.Value(tools.GS1AISerial); serial != "" {
+		result.SerialNumber = &serial
+	}
+	if exp := scan.Value(tools.GS1AIExpiry); exp != "" {
+		// Already validated by ParseGS1.
+		d, _ := tools.ParseGS1Date(exp, time.Now())
+		formatted := d.Format("2006-01-02")
+		result.ExpirationDate = &formatted
+	}
+	count := scan.Value(tools.GS1AICount)
+	if count == "" {
+		count = scan.Value(tools.GS1AIVariableCount)
+	}
+	if count != "" {
+		if n, err := strconv.ParseFloat(count, 64); err == nil && n > 0 {
+			result.Quantity = n
+		}
+	}
+
+	if gtin != "" {
+		barcode, article, resp := s.Repository.FindByCode(tenantID, gtin)
+		if resp != nil {
+			return nil, 1, resp
+		}
+		if article != nil {
+			result.MatchedBy = "gtin"
+			return article, barcode.PackQuantity, nil
+		}
+	}
+	if sku := scan.Value(tools.GS1AIProductID); sku != "" {
+		article, resp := s.Repository.FindArticleBySKU(tenantID, sku)
+		if article != nil {
+			result.MatchedBy = "sku"
+		}
+		return article, 1, resp
+	}
+	return nil, 1, nil
+}
+
+// resolvePlainCode matches a registered barcode (a bare EAN/UPC is normalized to GTIN-14
+// first), then a SKU.
+func (s *ArticleBarcodesService) resolvePlainCode(tenantID, code string, result *responses.ScanParseResult) (*database.Article, float64, *responses.InternalResponse) {
+	candidates := []string{code}
+	if gtin, err := tools.NormalizeGTIN(code); err == nil && gtin != code {
+		candidates = append([]string{gtin}, candidates...)
+	}
+	for _, c := range candidates {
+		barcode, article, resp := s.Repository.FindByCode(tenantID, c)
+		if resp != nil {
+			return nil, 1, resp
+		}
+		if article != nil {
+			result.MatchedBy = "barcode"
+			if barcode.CodeType == database.ArticleBarcodeGTIN {
+				result.GTIN = &barcode.Code
+			}
+			return article, barcode.PackQuantity, nil
+		}
+	}
+	article, resp := s.Repository.FindArticleBySKU(tenantID, code)
+	if article != nil {
+		result.MatchedBy = "sku"
+	}
+	return article, 1, resp
+}
+
+func (s *ArticleBarcodesService) fillLot(tenantID, purpose string, article *database.Article, result *responses.ScanParseResult) *responses.InternalResponse {
+	if result.LotNumber == nil {
+		if article.TrackByLot {
+			result.Warnings = append(result.Warnings, "El artículo se controla por lote y el código no incluye lote (10)")
+		}
+		return nil
+	}
+	if !article.TrackByLot {
+		result.Warnings = append(result.Warnings, "El artículo no se controla por lote; se ignora el lote escaneado")
+		return nil
+	}
+
+	lot, resp := s.Repository.FindLot(tenantID, article.SKU, *result.LotNumber)
+	if resp != nil {
+		return resp
+	}
+	expiration := result.ExpirationDate
+	if lot != nil && lot.ExpirationDate != nil {
+		known := lot.ExpirationDate.Format("2006-01-02")
+		if expiration == nil {
+			expiration = &known
+		} else if *expiration != known {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("El vencimiento escaneado (%s) difiere del registrado para el lote (%s)", *expiration, known))
+		}
+	}
+	if lot == nil && purpose == ScanPurposePicking {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("El lote %s no existe para %s", *result.LotNumber, article.SKU))
+	}
+	if expiration == nil && article.TrackExpiration {
+		result.Warnings = append(result.Warnings, "El artículo controla vencimiento y el código no incluye vencimiento (17)")
+	}
+
+	result.LotRequest = &requests.CreateLotRequest{
+		LotNumber:      *result.LotNumber,
+		SKU:            article.SKU,
+		Quantity:       result.Quantity,
+		ExpirationDate: expiration,
+	}
+	result.LotEntry = &database.LotEntry{
+		LotNumber:      *result.LotNumber,
+		SKU:            article.SKU,
+		Quantity:       result.Quantity,
+		ExpirationDate: expiration,
+	}
+	return nil
+}
+
+func (s *ArticleBarcodesService) fillSerial(tenantID, purpose string, article *database.Article, result *responses.ScanParseResult) *responses.InternalResponse {
+	if result.SerialNumber == nil {
+		if article.TrackBySerial {
+			result.Warnings = append(result.Warnings, "El artículo se controla por serie y el código no incluye serie (21)")
+		}
+		return nil
+	}
+	if !article.TrackBySerial {
+		result.Warnings = append(result.Warnings, "El artículo no se controla por serie; se ignora la serie escaneada")
+		return nil
+	}
+
+	existing, resp := s.Repository.FindSerial(tenantID, article.SKU, *result.SerialNumber)
+	if resp != nil {
+		return resp
+	}
+	switch {
+	case purpose == ScanPurposeReceiving && existing != nil:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("La serie %s ya existe (estado: %s)", existing.SerialNumber, existing.Status))
+	case purpose == ScanPurposePicking && existing == nil:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("La serie %s no existe para %s", *result.SerialNumber, article.SKU))
+	}
+
+	result.Serial = &database.Serial{SerialNumber: *result.SerialNumber, SKU: article.SKU}
+	if existing != nil {
+		result.Serial.ID = existing.ID
+		result.Serial.Status = existing.Status
+	}
+	return nil
+}
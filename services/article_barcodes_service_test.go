@@ -0,0 +1,165 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockArticleBarcodesRepo struct {
+	barcodes map[string]database.ArticleBarcode
+	articles map[string]database.Article
+	lots     map[string]database.Lot
+	serials  map[string]database.Serial
+}
+
+func (m *mockArticleBarcodesRepo) List(_, _ string) ([]database.ArticleBarcode, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepo) Create(_ string, _ *requests.CreateArticleBarcodeRequest) (*database.ArticleBarcode, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepo) Delete(_, _ string) *responses.InternalResponse {
+	return nil
+}
+
+func (m *mockArticleBarcodesRepo) FindByCode(_, code string) (*database.ArticleBarcode, *database.Article, *responses.InternalResponse) {
+	b, ok := m.barcodes[code]
+	if !ok {
+		return nil, nil, nil
+	}
+	a := m.articles[b.SKU]
+	return &b, &a, nil
+}
+
+func (m *mockArticleBarcodesRepo) FindArticleBySKU(_, sku string) (*database.Article, *responses.InternalResponse) {
+	if a, ok := m.articles[sku]; ok {
+		return &a, nil
+	}
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepo) FindLot(_, _, lotNumber string) (*database.Lot, *responses.InternalResponse) {
+	if l, ok := m.lots[lotNumber]; ok {
+		return &l, nil
+	}
+	return nil, nil
+}
+
+func (m *mockArticleBarcodesRepo) FindSerial(_, _, serialNumber string) (*database.Serial, *responses.InternalResponse) {
+	if s, ok := m.serials[serialNumber]; ok {
+		return &s, nil
+	}
+	return nil, nil
+}
+
+func newScanRepo() *mockArticleBarcodesRepo {
+	return &mockArticleBarcodesRepo{
+		barcodes: map[string]database.ArticleBarcode{
+			"09506000134352": {Code: "09506000134352", CodeType: database.ArticleBarcodeGTIN, SKU: "AMOX-500", PackQuantity: 1},
+			"19506000134359": {Code: "19506000134359", CodeType: database.ArticleBarcodeGTIN, SKU: "AMOX-500", PackQuantity: 12},
+		},
+		articles: map[string]database.Article{
+			"AMOX-500": {ID: "art-1", SKU: "AMOX-500", Name: "Amoxicilina 500mg", TrackByLot: true, TrackBySerial: true, TrackExpiration: true},
+			"BOX-1":    {ID: "art-2", SKU: "BOX-1", Name: "Caja"},
+		},
+		lots:    map[string]database.Lot{},
+		serials: map[string]database.Serial{},
+	}
+}
+
+func TestArticleBarcodesService_ParseScan_GS1Receiving(t *testing.T) {
+	svc := NewArticleBarcodesService(newScanRepo())
+	raw := "]d2" + "0109506000134352" + "17270630" + "10L-77" + tools.GS1GroupSeparator + "21SN-1"
+	res, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: raw, Purpose: ScanPurposeReceiving})
+	require.Nil(t, resp)
+
+	assert.True(t, res.IsGS1)
+	assert.True(t, res.Resolved)
+	assert.Equal(t, "gtin", res.MatchedBy)
+	assert.Equal(t, "gs1-datamatrix", res.Symbology)
+	assert.Equal(t, "AMOX-500", res.Article.SKU)
+	assert.Equal(t, 1.0, res.Quantity)
+	require.NotNil(t, res.LotRequest)
+	assert.Equal(t, "L-77", res.LotRequest.LotNumber)
+	assert.Equal(t, "AMOX-500", res.LotRequest.SKU)
+	assert.Equal(t, "2027-06-30", *res.LotRequest.ExpirationDate)
+	require.NotNil(t, res.LotEntry)
+	assert.Equal(t, "L-77", res.LotEntry.LotNumber)
+	require.NotNil(t, res.Serial)
+	assert.Equal(t, "SN-1", res.Serial.SerialNumber)
+	assert.Empty(t, res.Warnings)
+}
+
+func TestArticleBarcodesService_ParseScan_CaseGTINMultipliesPackQuantity(t *testing.T) {
+	svc := NewArticleBarcodesService(newScanRepo())
+	res, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "(01)19506000134359(37)3(10)L-1(17)270600"})
+	require.Nil(t, resp)
+	assert.Equal(t, 36.0, res.Quantity, "3 cases × 12 units")
+	assert.Equal(t, 36.0, res.LotEntry.Quantity)
+	assert.Equal(t, "2027-06-30", *res.LotEntry.ExpirationDate)
+}
+
+func TestArticleBarcodesService_ParseScan_ExistingLotAndSerial(t *testing.T) {
+	repo := newScanRepo()
+	exp := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	repo.lots["L-1"] = database.Lot{LotNumber: "L-1", SKU: "AMOX-500", ExpirationDate: &exp}
+	repo.serials["SN-1"] = database.Serial{ID: "ser-1", SerialNumber: "SN-1", SKU: "AMOX-500", Status: "available"}
+	svc := NewArticleBarcodesService(repo)
+
+	res, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "(01)09506000134352(10)L-1(21)SN-1", Purpose: ScanPurposeReceiving})
+	require.Nil(t, resp)
+	assert.Equal(t, "2027-01-31", *res.LotRequest.ExpirationDate, "expiry taken from the existing lot")
+	assert.Equal(t, "ser-1", res.Serial.ID)
+	assert.Len(t, res.Warnings, 1, "serial already exists on receiving")
+
+	res, resp = svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "(01)09506000134352(17)270228(10)L-1(21)SN-1", Purpose: ScanPurposePicking})
+	require.Nil(t, resp)
+	assert.Equal(t, "2027-02-28", *res.LotEntry.ExpirationDate)
+	assert.Len(t, res.Warnings, 1, "scanned expiry differs from the lot")
+}
+
+func TestArticleBarcodesService_ParseScan_PickingMissingLot(t *testing.T) {
+	svc := NewArticleBarcodesService(newScanRepo())
+	res, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "(01)09506000134352(17)270630(10)NOPE(21)SN-9", Purpose: ScanPurposePicking})
+	require.Nil(t, resp)
+	assert.True(t, res.Resolved)
+	assert.Len(t, res.Warnings, 2, "lot and serial unknown")
+}
+
+func TestArticleBarcodesService_ParseScan_PlainCodes(t *testing.T) {
+	svc := NewArticleBarcodesService(newScanRepo())
+
+	res, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "9506000134352"})
+	require.Nil(t, resp)
+	assert.False(t, res.IsGS1)
+	assert.Equal(t, "barcode", res.MatchedBy, "EAN-13 normalized to GTIN-14")
+	assert.Equal(t, "09506000134352", *res.GTIN)
+
+	res, resp = svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "BOX-1"})
+	require.Nil(t, resp)
+	assert.Equal(t, "sku", res.MatchedBy)
+	assert.Nil(t, res.LotRequest)
+	assert.Empty(t, res.Warnings)
+
+	res, resp = svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "UNKNOWN"})
+	require.Nil(t, resp)
+	assert.False(t, res.Resolved)
+	assert.Nil(t, res.Article)
+	assert.Len(t, res.Warnings, 1)
+}
+
+func TestArticleBarcodesService_ParseScan_InvalidGS1(t *testing.T) {
+	svc := NewArticleBarcodesService(newScanRepo())
+	_, resp := svc.ParseScan("tenant-1", &requests.ScanParseRequest{Code: "]C1" + "0109506000134353"})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
@@ -98,6 +98,10 @@ func (s *ArticlesService) EnrichArticle(art *database.Article) *responses.Articl
 		DefaultLocationID:  art.DefaultLocationID,
 		ReceivingNotes:     art.ReceivingNotes,
 		ShippingNotes:      art.ShippingNotes,
+		WeightKg:           art.WeightKg,
+		LengthCm:           art.LengthCm,
+		WidthCm:            art.WidthCm,
+		HeightCm:           art.HeightCm,
 	}
 	if art.CategoryID != nil && s.CategoriesRepo != nil {
 		cat, _ := s.CategoriesRepo.GetByID(*art.CategoryID)
@@ -220,6 +224,19 @@ func (s *ArticlesService) validateArticleFields(data *requests.Article) *respons
 			StatusCode: responses.StatusBadRequest,
 		}
 	}
+	for _, series := range []*string{data.BatchNumberSeries, data.SerialNumberSeries} {
+		if series == nil || strings.TrimSpace(*series) == "" {
+			continue
+		}
+		if _, err := tools.ParseNumberPattern(*series); err != nil {
+			return &responses.InternalResponse{
+				Error:      err,
+				Message:    err.Error(),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+	}
 	if data.CategoryID != nil && *data.CategoryID != "" && s.CategoriesRepo != nil {
 		cat, resp := s.CategoriesRepo.GetByID(*data.CategoryID)
 		if resp != nil || cat == nil {
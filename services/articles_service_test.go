@@ -378,3 +378,19 @@ func TestArticlesService_EnrichArticle_WithCategory(t *testing.T) {
 }
 
 
+
+func TestArticlesService_CreateArticle_NumberSeries(t *testing.T) {
+	repo := &mockArticlesRepo{articles: []database.Article{}}
+	svc := NewArticlesService(repo)
+
+	prefix := "ACME-"
+	pattern := "SN-{YYYY}-{#####}"
+	req := &requests.Article{SKU: "SER-001", Name: "Serial", Presentation: "unit", BatchNumberSeries: &prefix, SerialNumberSeries: &pattern}
+	require.Nil(t, svc.CreateArticle(testTenantID, req))
+
+	bad := "SN-{WEEK}-{###}"
+	req = &requests.Article{SKU: "SER-002", Name: "Serial", Presentation: "unit", SerialNumberSeries: &bad}
+	errResp := svc.CreateArticle(testTenantID, req)
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
+}
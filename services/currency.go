@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// currencyAmount is an amount in Currency; a nil or empty Currency means the tenant base currency.
+type currencyAmount struct {
+	Amount   float64
+	Currency *string
+}
+
+// normalizeCurrency upper-cases and trims a currency code taken from a query string.
+func normalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// sumInCurrency adds amounts up in target, converting each foreign currency with rateFrom
+// (called once per distinct currency).
+func sumInCurrency(target string, amounts []currencyAmount, rateFrom func(from string) (float64, *responses.InternalResponse)) (float64, *responses.InternalResponse) {
+	rates := map[string]float64{target: 1}
+	total := 0.0
+	for _, a := range amounts {
+		from := target
+		if a.Currency != nil && *a.Currency != "" {
+			from = *a.Currency
+		}
+		rate, ok := rates[from]
+		if !ok {
+			var resp *responses.InternalResponse
+			rate, resp = rateFrom(from)
+			if resp != nil {
+				return 0, resp
+			}
+			rates[from] = rate
+		}
+		total += a.Amount * rate
+	}
+	return total, nil
+}
+
+// orderTotals values order lines in the tenant base currency at the rates in effect on asOf
+// (the order date) and, when requested names another currency, also in that currency.
+func orderTotals(rates ports.ExchangeRatesRepository, tenantID string, asOf time.Time, amounts []currencyAmount, requested string) (*responses.OrderTotals, *responses.InternalResponse) {
+	base, resp := rates.BaseCurrency(tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	total, resp := sumInCurrency(base, amounts, func(from string) (float64, *responses.InternalResponse) {
+		return rates.GetRate(tenantID, from, base, asOf)
+	})
+	if resp != nil {
+		return nil, resp
+	}
+
+	totals := &responses.OrderTotals{BaseCurrency: base, Total: total}
+	requested = normalizeCurrency(requested)
+	if requested == "" || requested == base {
+		return totals, nil
+	}
+	rate, resp := rates.GetRate(tenantID, base, requested, asOf)
+	if resp != nil {
+		return nil, resp
+	}
+	converted := total * rate
+	totals.RequestedCurrency = requested
+	totals.ExchangeRate = &rate
+	totals.RequestedTotal = &converted
+	return totals, nil
+}
+
+// currencyUnavailable is returned when a conversion is requested but no exchange rate
+// repository is configured.
+func currencyUnavailable() *responses.InternalResponse {
+	return &responses.InternalResponse{Message: "La conversión de moneda no está disponible", Handled: true, StatusCode: responses.StatusBadRequest}
+}
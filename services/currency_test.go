@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mocks
+// ─────────────────────────────────────────────────────────────────────────────
+
+// mockExchangeRatesRepo resolves rates from a "FROM/TO" map and falls back to the inverse pair,
+// like the real lookup.
+type mockExchangeRatesRepo struct {
+	base  string
+	rates map[string]float64
+	asOf  []time.Time
+}
+
+func (m *mockExchangeRatesRepo) List(tenantID string) ([]database.ExchangeRate, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockExchangeRatesRepo) Upsert(tenantID, createdBy string, req *requests.UpsertExchangeRateRequest) (*database.ExchangeRate, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func (m *mockExchangeRatesRepo) Delete(id, tenantID string) *responses.InternalResponse {
+	return nil
+}
+
+func (m *mockExchangeRatesRepo) BaseCurrency(tenantID string) (string, *responses.InternalResponse) {
+	return m.base, nil
+}
+
+func (m *mockExchangeRatesRepo) GetRate(tenantID, from, to string, asOf time.Time) (float64, *responses.InternalResponse) {
+	m.asOf = append(m.asOf, asOf)
+	if from == to {
+		return 1, nil
+	}
+	if r, ok := m.rates[from+"/"+to]; ok {
+		return r, nil
+	}
+	if r, ok := m.rates[to+"/"+from]; ok {
+		return 1 / r, nil
+	}
+	return 0, &responses.InternalResponse{Message: fmt.Sprintf("sin tipo de cambio %s/%s", from, to), Handled: true, StatusCode: responses.StatusBadRequest}
+}
+
+func crcUSDRates() *mockExchangeRatesRepo {
+	return &mockExchangeRatesRepo{base: "CRC", rates: map[string]float64{"USD/CRC": 500}}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// orderTotals
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestOrderTotals_MixedCurrenciesInBase(t *testing.T) {
+	rates := crcUSDRates()
+	orderDate := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	totals, resp := orderTotals(rates, ccTenant, orderDate, []currencyAmount{
+		{Amount: 10, Currency: tools.StrPtr("USD")},
+		{Amount: 1000}, // base currency (nil)
+		{Amount: 2, Currency: tools.StrPtr("USD")}, // rate looked up once
+	}, "")
+	require.Nil(t, resp)
+	assert.Equal(t, "CRC", totals.BaseCurrency)
+	assert.InDelta(t, 7000, totals.Total, 0.0001)
+	assert.Empty(t, totals.RequestedCurrency)
+	assert.Nil(t, totals.RequestedTotal)
+	require.Len(t, rates.asOf, 1)
+	assert.Equal(t, orderDate, rates.asOf[0], "lines are converted at the order date")
+}
+
+func TestOrderTotals_RequestedCurrency(t *testing.T) {
+	totals, resp := orderTotals(crcUSDRates(), ccTenant, time.Now(), []currencyAmount{{Amount: 10000}}, " usd ")
+	require.Nil(t, resp)
+	assert.Equal(t, "USD", totals.RequestedCurrency)
+	require.NotNil(t, totals.ExchangeRate)
+	assert.InDelta(t, 0.002, *totals.ExchangeRate, 1e-9)
+	require.NotNil(t, totals.RequestedTotal)
+	assert.InDelta(t, 20, *totals.RequestedTotal, 0.0001)
+}
+
+func TestOrderTotals_RequestedBaseIsNoConversion(t *testing.T) {
+	totals, resp := orderTotals(crcUSDRates(), ccTenant, time.Now(), []currencyAmount{{Amount: 5}}, "CRC")
+	require.Nil(t, resp)
+	assert.Empty(t, totals.RequestedCurrency)
+	assert.Nil(t, totals.RequestedTotal)
+}
+
+func TestOrderTotals_MissingRate(t *testing.T) {
+	_, resp := orderTotals(crcUSDRates(), ccTenant, time.Now(), []currencyAmount{{Amount: 5, Currency: tools.StrPtr("EUR")}}, "")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Valuation and order detail
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestInventoryService_GetValuation_RequestedCurrency(t *testing.T) {
+	repo := &mockInventoryRepo{valuation: &responses.InventoryValuationResponse{
+		TotalValue: 15000,
+		Currency:   "CRC",
+		GroupBy:    "article",
+		Breakdown: []responses.ValuationBreakdownItem{
+			{Key: "A", Value: 10000, Qty: 2},
+			{Key: "B", Value: 5000, Qty: 1},
+		},
+	}}
+	svc := NewInventoryService(repo, nil)
+	svc.ExchangeRates = crcUSDRates()
+
+	v, resp := svc.GetValuation(ccTenant, "article", "USD")
+	require.Nil(t, resp)
+	assert.Equal(t, "CRC", v.Currency)
+	assert.Equal(t, "USD", v.RequestedCurrency)
+	require.NotNil(t, v.RequestedTotalValue)
+	assert.InDelta(t, 30, *v.RequestedTotalValue, 0.0001)
+	require.NotNil(t, v.Breakdown[0].RequestedValue)
+	assert.InDelta(t, 20, *v.Breakdown[0].RequestedValue, 0.0001)
+	assert.InDelta(t, 10, *v.Breakdown[1].RequestedValue, 0.0001)
+}
+
+func TestInventoryService_GetValuation_RequestedCurrencyWithoutRates(t *testing.T) {
+	repo := &mockInventoryRepo{valuation: &responses.InventoryValuationResponse{Currency: "CRC"}}
+	svc := NewInventoryService(repo, nil)
+
+	_, resp := svc.GetValuation(ccTenant, "article", "USD")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPurchaseOrdersService_GetByIDWithTotals(t *testing.T) {
+	// newSvc returns a service over a fresh PO so subtests do not see each other's Totals.
+	newSvc := func(rates *mockExchangeRatesRepo) *PurchaseOrdersService {
+		cost := 4.0
+		svc := NewPurchaseOrdersService(&mockPORepo{byID: map[string]*responses.PurchaseOrderView{
+			"po-1": {ID: "po-1", Items: []responses.PurchaseOrderItemView{
+				{ArticleSKU: "A", ExpectedQty: 5, UnitCost: &cost, Currency: tools.StrPtr("USD")},
+				{ArticleSKU: "B", ExpectedQty: 3}, // no cost: not valued
+			}},
+		}})
+		svc.ExchangeRates = rates
+		return svc
+	}
+
+	t.Run("base currency", func(t *testing.T) {
+		po, resp := newSvc(crcUSDRates()).GetByIDWithTotals("po-1", ccTenant, "")
+		require.Nil(t, resp)
+		require.NotNil(t, po.Totals)
+		assert.InDelta(t, 10000, po.Totals.Total, 0.0001)
+	})
+
+	t.Run("requested currency", func(t *testing.T) {
+		po, resp := newSvc(crcUSDRates()).GetByIDWithTotals("po-1", ccTenant, "USD")
+		require.Nil(t, resp)
+		require.NotNil(t, po.Totals.RequestedTotal)
+		assert.InDelta(t, 20, *po.Totals.RequestedTotal, 0.0001)
+	})
+
+	t.Run("missing rate only drops totals", func(t *testing.T) {
+		po, resp := newSvc(&mockExchangeRatesRepo{base: "CRC"}).GetByIDWithTotals("po-1", ccTenant, "")
+		require.Nil(t, resp)
+		assert.Nil(t, po.Totals)
+	})
+
+	t.Run("missing requested rate is an error", func(t *testing.T) {
+		_, resp := newSvc(crcUSDRates()).GetByIDWithTotals("po-1", ccTenant, "EUR")
+		require.NotNil(t, resp)
+		assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestSalesOrdersService_GetByIDWithTotals(t *testing.T) {
+	price := 2500.0
+	repo := &mockSalesOrdersRepo{getResult: &responses.SalesOrderResponse{ID: "so-1", Items: []database.SalesOrderItem{
+		{ArticleSKU: "A", ExpectedQty: 4, UnitPrice: &price},
+	}}}
+	svc := NewSalesOrdersService(repo)
+	svc.ExchangeRates = crcUSDRates()
+
+	so, resp := svc.GetByIDWithTotals("so-1", ccTenant, "USD")
+	require.Nil(t, resp)
+	require.NotNil(t, so.Totals)
+	assert.InDelta(t, 10000, so.Totals.Total, 0.0001)
+	assert.InDelta(t, 20, *so.Totals.RequestedTotal, 0.0001)
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// CustomerReturnsService provides business logic for customer returns (RMA): authorizing a
+// return against a delivery note or sales order, receiving it through an inspection whose
+// dispositions (restock, quarantine, scrap) post the inventory movements, and cancelling it.
+type CustomerReturnsService struct {
+	Repository ports.CustomerReturnsRepository
+}
+
+func NewCustomerReturnsService(repo ports.CustomerReturnsRepository) *CustomerReturnsService {
+	return &CustomerReturnsService{Repository: repo}
+}
+
+func (s *CustomerReturnsService) ListReturns(tenantID string, status, salesOrderID *string, limit, offset int) ([]database.CustomerReturn, *responses.InternalResponse) {
+	return s.Repository.ListReturns(tenantID, status, salesOrderID, limit, offset)
+}
+
+func (s *CustomerReturnsService) GetReturn(id, tenantID string) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	return s.Repository.GetReturn(id, tenantID)
+}
+
+// CreateReturn checks the return refers to exactly one delivery note or sales order, then
+// authorizes it.
+func (s *CustomerReturnsService) CreateReturn(tenantID, userID string, req *requests.CreateCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	req.DeliveryNoteID = trimmedOrNil(req.DeliveryNoteID)
+	req.SalesOrderID = trimmedOrNil(req.SalesOrderID)
+	if (req.DeliveryNoteID == nil) == (req.SalesOrderID == nil) {
+		return nil, &responses.InternalResponse{
+			Message:    "Indique la nota de entrega o la orden de venta de la devolución (solo una)",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	for i := range req.Lines {
+		req.Lines[i].ArticleSKU = strings.TrimSpace(req.Lines[i].ArticleSKU)
+		req.Lines[i].LotNumber = trimmedOrNil(req.Lines[i].LotNumber)
+	}
+	return s.Repository.CreateReturn(tenantID, userID, req)
+}
+
+func (s *CustomerReturnsService) ReceiveReturn(id, tenantID, userID string, req *requests.ReceiveCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	return s.Repository.ReceiveReturn(id, tenantID, userID, req)
+}
+
+func (s *CustomerReturnsService) CancelReturn(id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse) {
+	return s.Repository.CancelReturn(id, tenantID)
+}
+
+// trimmedOrNil trims v and returns nil when it is nil or blank.
+func trimmedOrNil(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*v)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCustomerReturnsRepo struct {
+	created *requests.CreateCustomerReturnRequest
+}
+
+func (m *mockCustomerReturnsRepo) ListReturns(tenantID string, status, salesOrderID *string, limit, offset int) ([]database.CustomerReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCustomerReturnsRepo) GetReturn(id, tenantID string) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCustomerReturnsRepo) CreateReturn(tenantID, userID string, req *requests.CreateCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	m.created = req
+	return &responses.CustomerReturnView{CustomerReturn: database.CustomerReturn{ID: "r1", Status: database.CustomerReturnAuthorized}}, nil
+}
+func (m *mockCustomerReturnsRepo) ReceiveReturn(id, tenantID, userID string, req *requests.ReceiveCustomerReturnRequest) (*responses.CustomerReturnView, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockCustomerReturnsRepo) CancelReturn(id, tenantID string) (*database.CustomerReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func returnLine() []requests.CustomerReturnLineRequest {
+	qty := 1.0
+	lot := " L1 "
+	return []requests.CustomerReturnLineRequest{{ArticleSKU: " SKU-1 ", LotNumber: &lot, Qty: &qty, ReasonCode: "damaged"}}
+}
+
+func TestCustomerReturnsService_CreateReturn_RequiresOneSource(t *testing.T) {
+	dn, so, blank := "dn-1", "so-1", "  "
+	for name, req := range map[string]*requests.CreateCustomerReturnRequest{
+		"none":  {Lines: returnLine()},
+		"blank": {DeliveryNoteID: &blank, Lines: returnLine()},
+		"both":  {DeliveryNoteID: &dn, SalesOrderID: &so, Lines: returnLine()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockCustomerReturnsRepo{}
+			_, resp := NewCustomerReturnsService(repo).CreateReturn("tenant-1", "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestCustomerReturnsService_CreateReturn_TrimsAndDelegates(t *testing.T) {
+	repo := &mockCustomerReturnsRepo{}
+	dn := " dn-1 "
+	view, resp := NewCustomerReturnsService(repo).CreateReturn("tenant-1", "user-1",
+		&requests.CreateCustomerReturnRequest{DeliveryNoteID: &dn, Lines: returnLine()})
+	require.Nil(t, resp)
+	require.NotNil(t, view)
+	require.NotNil(t, repo.created)
+	assert.Equal(t, "dn-1", *repo.created.DeliveryNoteID)
+	assert.Equal(t, "SKU-1", repo.created.Lines[0].ArticleSKU)
+	assert.Equal(t, "L1", *repo.created.Lines[0].LotNumber)
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+)
+
+// Default reason codes (seeded by migration 000037) used when a plan has no reason_code.
+const (
+	cycleCountGainReason = "cycle_count_gain"
+	cycleCountLossReason = "cycle_count_loss"
+)
+
+// CycleCountsService provides business logic for cycle counting: count plans, task
+// generation, blind counts with recount thresholds and supervisor approval.
+// Approved variances are posted by the repository as count_reconcile adjustments so
+// inventory, movements and lots follow the same path as manual adjustments.
+type CycleCountsService struct {
+	Repository            ports.CycleCountsRepository
+	ReasonCodesRepository ports.AdjustmentReasonCodesRepository
+}
+
+func NewCycleCountsService(repo ports.CycleCountsRepository, reasonCodesRepo ports.AdjustmentReasonCodesRepository) *CycleCountsService {
+	return &CycleCountsService{
+		Repository:            repo,
+		ReasonCodesRepository: reasonCodesRepo,
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// needsRecount reports whether the first count deviates from the expected quantity by
+// more than either threshold. pct is a percentage of expected (when expected is 0 any
+// difference exceeds it); qty is an absolute unit difference. Nil thresholds are ignored.
+func needsRecount(expected, counted float64, pct, qty *float64) bool {
+	diff := math.Abs(counted - expected)
+	if diff == 0 {
+		return false
+	}
+	if qty != nil && diff > *qty {
+		return true
+	}
+	if pct != nil {
+		if expected == 0 {
+			return true
+		}
+		if diff/math.Abs(expected)*100 > *pct {
+			return true
+		}
+	}
+	return false
+}
+
+// validateScope checks scope values that the request validator cannot (ABC classes).
+func validateScope(scopeType string, values []string) *responses.InternalResponse {
+	if scopeType != "abc_class" {
+		return nil
+	}
+	for _, v := range values {
+		if v != "A" && v != "B" && v != "C" {
+			return &responses.InternalResponse{
+				Message:    fmt.Sprintf("Clase ABC inválida: %q (use A, B o C)", v),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+// validateReasonCode ensures a plan reason code exists and is active.
+func (s *CycleCountsService) validateReasonCode(code *string) *responses.InternalResponse {
+	if code == nil || *code == "" || s.ReasonCodesRepository == nil {
+		return nil
+	}
+	reasonCode, resp := s.ReasonCodesRepository.GetAdjustmentReasonCodeByCode(*code)
+	if resp != nil {
+		return resp
+	}
+	if reasonCode == nil {
+		return &responses.InternalResponse{
+			Message:    "Código de motivo inválido o inactivo",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+// buildCountTaskView maps a task and its lines to the response shape. When reveal is false
+// and the task is blind and still being counted, expected quantities and variances are hidden.
+func buildCountTaskView(task *database.CountTask, lines []database.CountTaskLine, reveal bool) *responses.CountTaskView {
+	hide := !reveal && task.Blind && (task.Status == "open" || task.Status == "in_progress")
+
+	view := &responses.CountTaskView{
+		ID:          task.ID,
+		TaskNumber:  task.TaskNumber,
+		CountPlanID: task.CountPlanID,
+		Status:      task.Status,
+		Blind:       task.Blind,
+		AssignedTo:  task.AssignedTo,
+		Notes:       task.Notes,
+		CreatedBy:   task.CreatedBy,
+		StartedAt:   task.StartedAt,
+		SubmittedAt: task.SubmittedAt,
+		ApprovedBy:  task.ApprovedBy,
+		ApprovedAt:  task.ApprovedAt,
+		CancelledAt: task.CancelledAt,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+		TotalLines:  len(lines),
+		Lines:       make([]responses.CountTaskLineView, 0, len(lines)),
+	}
+	for _, l := range lines {
+		view.Lines = append(view.Lines, buildCountTaskLineView(l, hide))
+	}
+	return view
+}
+
+func buildCountTaskLineView(l database.CountTaskLine, hide bool) responses.CountTaskLineView {
+	lv := responses.CountTaskLineView{
+		ID:           l.ID,
+		SKU:          l.SKU,
+		Location:     l.Location,
+		CountedQty:   l.CountedQty,
+		RecountQty:   l.RecountQty,
+		Status:       l.Status,
+		CountedBy:    l.CountedBy,
+		CountedAt:    l.CountedAt,
+		RecountedBy:  l.RecountedBy,
+		RecountedAt:  l.RecountedAt,
+		Notes:        l.Notes,
+		AdjustmentID: l.AdjustmentID,
+	}
+	if hide {
+		return lv
+	}
+	expected := l.ExpectedQty
+	lv.ExpectedQty = &expected
+	if final, ok := l.FinalQty(); ok {
+		variance := final - expected
+		lv.Variance = &variance
+	}
+	return lv
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count plans
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CreatePlan validates scope and reason code, then creates the plan scoped to tenantID.
+func (s *CycleCountsService) CreatePlan(tenantID, createdBy string, req *requests.CreateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	if resp := validateScope(req.ScopeType, req.ScopeValues); resp != nil {
+		return nil, resp
+	}
+	if resp := s.validateReasonCode(req.ReasonCode); resp != nil {
+		return nil, resp
+	}
+	return s.Repository.CreatePlan(tenantID, createdBy, req)
+}
+
+func (s *CycleCountsService) GetPlanByID(id, tenantID string) (*database.CountPlan, *responses.InternalResponse) {
+	return s.Repository.GetPlanByID(id, tenantID)
+}
+
+func (s *CycleCountsService) ListPlans(tenantID string) ([]database.CountPlan, *responses.InternalResponse) {
+	return s.Repository.ListPlans(tenantID)
+}
+
+// UpdatePlan patches a plan. The resulting scope (type + values) is validated as a whole.
+func (s *CycleCountsService) UpdatePlan(id, tenantID string, req *requests.UpdateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	if req.ScopeType != nil || req.ScopeValues != nil {
+		current, resp := s.Repository.GetPlanByID(id, tenantID)
+		if resp != nil {
+			return nil, resp
+		}
+		scopeType := current.ScopeType
+		if req.ScopeType != nil {
+			scopeType = *req.ScopeType
+		}
+		values := []string(current.ScopeValues)
+		if req.ScopeValues != nil {
+			values = req.ScopeValues
+		}
+		if resp := validateScope(scopeType, values); resp != nil {
+			return nil, resp
+		}
+	}
+	if resp := s.validateReasonCode(req.ReasonCode); resp != nil {
+		return nil, resp
+	}
+	return s.Repository.UpdatePlan(id, tenantID, req)
+}
+
+func (s *CycleCountsService) DeletePlan(id, tenantID string) *responses.InternalResponse {
+	return s.Repository.SoftDeletePlan(id, tenantID)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Count tasks
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GenerateTask creates an open count task from an active plan.
+func (s *CycleCountsService) GenerateTask(planID, tenantID, userID string, req *requests.GenerateCountTaskRequest) (*responses.CountTaskView, *responses.InternalResponse) {
+	plan, resp := s.Repository.GetPlanByID(planID, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if !plan.IsActive {
+		return nil, &responses.InternalResponse{
+			Message:    "El plan de conteo está inactivo",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	task, lines, resp := s.Repository.GenerateTask(plan, userID, req)
+	if resp != nil {
+		return nil, resp
+	}
+	return buildCountTaskView(task, lines, false), nil
+}
+
+// GetTask returns a task for the counter: blind tasks hide expected quantities while counting.
+func (s *CycleCountsService) GetTask(id, tenantID string) (*responses.CountTaskView, *responses.InternalResponse) {
+	task, lines, resp := s.Repository.GetTaskByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	return buildCountTaskView(task, lines, false), nil
+}
+
+// ReviewTask returns a task with expected quantities and variances always visible (approvers).
+func (s *CycleCountsService) ReviewTask(id, tenantID string) (*responses.CountTaskView, *responses.InternalResponse) {
+	task, lines, resp := s.Repository.GetTaskByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	return buildCountTaskView(task, lines, true), nil
+}
+
+func (s *CycleCountsService) ListTasks(tenantID string, status *string, limit, offset int) ([]database.CountTask, *responses.InternalResponse) {
+	return s.Repository.ListTasks(tenantID, status, limit, offset)
+}
+
+// StartTask moves an open task to in_progress.
+func (s *CycleCountsService) StartTask(id, tenantID, userID string) (*database.CountTask, *responses.InternalResponse) {
+	return s.Repository.TransitionTask(id, tenantID, "in_progress", userID)
+}
+
+// RecordCount records the count of one line. The first count of a pending (or already
+// counted) line is compared against the task thresholds and flagged recount_required when
+// exceeded; a line already flagged stores the value as its recount. Counting an open task
+// starts it implicitly.
+func (s *CycleCountsService) RecordCount(taskID, lineID, tenantID, userID string, req *requests.RecordCountRequest) (*responses.CountTaskLineView, *responses.InternalResponse) {
+	task, lines, resp := s.Repository.GetTaskByID(taskID, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	if task.Status == "open" {
+		started, resp := s.Repository.TransitionTask(taskID, tenantID, "in_progress", userID)
+		if resp != nil {
+			return nil, resp
+		}
+		task = started
+	}
+	if task.Status != "in_progress" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("No se puede registrar conteos en una tarea con estado %q", task.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	var line *database.CountTaskLine
+	for i := range lines {
+		if lines[i].ID == lineID {
+			line = &lines[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, &responses.InternalResponse{
+			Message:    "Línea de conteo no encontrada",
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+
+	qty := *req.CountedQty
+	now := tools.GetCurrentTime()
+	switch line.Status {
+	case "pending", "counted":
+		line.CountedQty = &qty
+		line.CountedBy = &userID
+		line.CountedAt = &now
+		if needsRecount(line.ExpectedQty, qty, task.RecountThresholdPct, task.RecountThresholdQty) {
+			line.Status = "recount_required"
+		} else {
+			line.Status = "counted"
+		}
+	case "recount_required", "recounted":
+		line.RecountQty = &qty
+		line.RecountedBy = &userID
+		line.RecountedAt = &now
+		line.Status = "recounted"
+	}
+	if req.Notes != nil {
+		line.Notes = req.Notes
+	}
+
+	if resp := s.Repository.SaveLineCount(line); resp != nil {
+		return nil, resp
+	}
+
+	lv := buildCountTaskLineView(*line, task.Blind)
+	return &lv, nil
+}
+
+// SubmitTask sends a fully counted task to pending_approval.
+func (s *CycleCountsService) SubmitTask(id, tenantID, userID string) (*database.CountTask, *responses.InternalResponse) {
+	_, lines, resp := s.Repository.GetTaskByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	pending := 0
+	for _, l := range lines {
+		if l.Status != "counted" && l.Status != "recounted" {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Hay %d líneas sin contar o pendientes de reconteo", pending),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return s.Repository.TransitionTask(id, tenantID, "pending_approval", userID)
+}
+
+// ApproveTask approves a pending_approval task. The repository locks the task and posts, in
+// the same transaction, each final count against the stock on hand at approval time as a
+// count_reconcile adjustment; lines that already carry an adjustment_id are skipped.
+func (s *CycleCountsService) ApproveTask(id, tenantID, userID string) (*responses.CountTaskView, *responses.InternalResponse) {
+	approved, lines, resp := s.Repository.ApproveTask(id, tenantID, userID, cycleCountGainReason, cycleCountLossReason)
+	if resp != nil {
+		return nil, resp
+	}
+	return buildCountTaskView(approved, lines, true), nil
+}
+
+// RejectTask sends the selected lines back for recount and returns the task to in_progress.
+func (s *CycleCountsService) RejectTask(id, tenantID, userID string, req *requests.RejectCountTaskRequest) (*database.CountTask, *responses.InternalResponse) {
+	task, _, resp := s.Repository.GetTaskByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if task.Status != "pending_approval" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Solo se pueden rechazar tareas pendientes de aprobación (estado actual: %q)", task.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	if resp := s.Repository.FlagLinesForRecount(id, req.LineIDs); resp != nil {
+		return nil, resp
+	}
+	return s.Repository.TransitionTask(id, tenantID, "in_progress", userID)
+}
+
+// CancelTask cancels a task that has not been approved. No inventory is touched.
+func (s *CycleCountsService) CancelTask(id, tenantID, userID string) (*database.CountTask, *responses.InternalResponse) {
+	return s.Repository.TransitionTask(id, tenantID, "cancelled", userID)
+}
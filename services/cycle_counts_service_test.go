@@ -0,0 +1,350 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mock repositories
+// ─────────────────────────────────────────────────────────────────────────────
+
+// mockCycleCountsRepo keeps one task and its lines in memory and applies the same
+// transition rules as the GORM repository.
+type mockCycleCountsRepo struct {
+	plan        *database.CountPlan
+	task        *database.CountTask
+	lines       []database.CountTaskLine
+	createdPlan *requests.CreateCountPlanRequest
+	flagged     []string
+	// approveReasons are the gain / loss reasons ApproveTask was called with.
+	approveReasons []string
+}
+
+var mockCountTransitions = map[string]map[string]bool{
+	"open":             {"in_progress": true, "cancelled": true},
+	"in_progress":      {"pending_approval": true, "cancelled": true},
+	"pending_approval": {"approved": true, "in_progress": true, "cancelled": true},
+}
+
+func (m *mockCycleCountsRepo) CreatePlan(tenantID, createdBy string, req *requests.CreateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	m.createdPlan = req
+	return &database.CountPlan{ID: "plan-1", TenantID: tenantID, Name: req.Name, ScopeType: req.ScopeType, IsActive: true}, nil
+}
+
+func (m *mockCycleCountsRepo) GetPlanByID(id, tenantID string) (*database.CountPlan, *responses.InternalResponse) {
+	if m.plan == nil || m.plan.ID != id {
+		return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.plan, nil
+}
+
+func (m *mockCycleCountsRepo) ListPlans(tenantID string) ([]database.CountPlan, *responses.InternalResponse) {
+	if m.plan == nil {
+		return nil, nil
+	}
+	return []database.CountPlan{*m.plan}, nil
+}
+
+func (m *mockCycleCountsRepo) UpdatePlan(id, tenantID string, req *requests.UpdateCountPlanRequest) (*database.CountPlan, *responses.InternalResponse) {
+	return m.plan, nil
+}
+
+func (m *mockCycleCountsRepo) SoftDeletePlan(id, tenantID string) *responses.InternalResponse {
+	return nil
+}
+
+func (m *mockCycleCountsRepo) GenerateTask(plan *database.CountPlan, createdBy string, req *requests.GenerateCountTaskRequest) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	return m.task, m.lines, nil
+}
+
+func (m *mockCycleCountsRepo) GetTaskByID(id, tenantID string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	if m.task == nil || m.task.ID != id {
+		return nil, nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	t := *m.task
+	lines := make([]database.CountTaskLine, len(m.lines))
+	copy(lines, m.lines)
+	return &t, lines, nil
+}
+
+func (m *mockCycleCountsRepo) ListTasks(tenantID string, status *string, limit, offset int) ([]database.CountTask, *responses.InternalResponse) {
+	return []database.CountTask{*m.task}, nil
+}
+
+func (m *mockCycleCountsRepo) TransitionTask(id, tenantID, next, userID string) (*database.CountTask, *responses.InternalResponse) {
+	if !mockCountTransitions[m.task.Status][next] {
+		return nil, &responses.InternalResponse{Message: "invalid transition", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	m.task.Status = next
+	if next == "approved" {
+		m.task.ApprovedBy = &userID
+	}
+	t := *m.task
+	return &t, nil
+}
+
+func (m *mockCycleCountsRepo) SaveLineCount(line *database.CountTaskLine) *responses.InternalResponse {
+	for i := range m.lines {
+		if m.lines[i].ID == line.ID {
+			m.lines[i] = *line
+		}
+	}
+	return nil
+}
+
+func (m *mockCycleCountsRepo) FlagLinesForRecount(taskID string, lineIDs []string) *responses.InternalResponse {
+	m.flagged = lineIDs
+	for _, id := range lineIDs {
+		for i := range m.lines {
+			if m.lines[i].ID == id {
+				m.lines[i].Status = "recount_required"
+				m.lines[i].RecountQty = nil
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockCycleCountsRepo) ApproveTask(id, tenantID, userID, gainReason, lossReason string) (*database.CountTask, []database.CountTaskLine, *responses.InternalResponse) {
+	m.approveReasons = []string{gainReason, lossReason}
+	if m.task.Status != "pending_approval" {
+		return nil, nil, &responses.InternalResponse{Message: "not pending approval", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	m.task.Status = "approved"
+	m.task.ApprovedBy = &userID
+	t := *m.task
+	return &t, m.lines, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+const ccTenant = "00000000-0000-0000-0000-000000000001"
+
+func newCycleCountTestRepo(status string, blind bool) *mockCycleCountsRepo {
+	return &mockCycleCountsRepo{
+		plan: &database.CountPlan{ID: "plan-1", TenantID: ccTenant, ScopeType: "location", IsActive: true, Blind: blind},
+		task: &database.CountTask{
+			ID: "task-1", TenantID: ccTenant, TaskNumber: "CC-2026-0001", Status: status, Blind: blind,
+			RecountThresholdPct: tools.Float64Ptr(10),
+		},
+		lines: []database.CountTaskLine{
+			{ID: "line-1", CountTaskID: "task-1", SKU: "SKU-1", Location: "A-01", ExpectedQty: 100, Status: "pending"},
+			{ID: "line-2", CountTaskID: "task-1", SKU: "SKU-2", Location: "A-02", ExpectedQty: 20, Status: "pending"},
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// needsRecount
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestNeedsRecount(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected float64
+		counted  float64
+		pct      *float64
+		qty      *float64
+		want     bool
+	}{
+		{"exact match never recounts", 100, 100, tools.Float64Ptr(0), tools.Float64Ptr(0), false},
+		{"no thresholds", 100, 50, nil, nil, false},
+		{"within pct", 100, 95, tools.Float64Ptr(10), nil, false},
+		{"above pct", 100, 85, tools.Float64Ptr(10), nil, true},
+		{"pct with zero expected", 0, 1, tools.Float64Ptr(50), nil, true},
+		{"within qty", 100, 103, nil, tools.Float64Ptr(5), false},
+		{"above qty", 100, 110, nil, tools.Float64Ptr(5), true},
+		{"either threshold triggers", 1000, 1010, tools.Float64Ptr(5), tools.Float64Ptr(5), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, needsRecount(tt.expected, tt.counted, tt.pct, tt.qty))
+		})
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Plans
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestCycleCountsService_CreatePlan_InvalidABCClass(t *testing.T) {
+	repo := newCycleCountTestRepo("open", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.CreatePlan(ccTenant, "user-1", &requests.CreateCountPlanRequest{
+		Name: "ABC", ScopeType: "abc_class", ScopeValues: []string{"A", "D"},
+	})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	assert.Nil(t, repo.createdPlan)
+}
+
+func TestCycleCountsService_CreatePlan_UnknownReasonCode(t *testing.T) {
+	repo := newCycleCountTestRepo("open", true)
+	svc := NewCycleCountsService(repo, &mockAdjustmentReasonCodesRepo{})
+
+	code := "nope"
+	_, resp := svc.CreatePlan(ccTenant, "user-1", &requests.CreateCountPlanRequest{
+		Name: "Zona A", ScopeType: "zone", ScopeValues: []string{"A"}, ReasonCode: &code,
+	})
+	require.NotNil(t, resp)
+	assert.Nil(t, repo.createdPlan)
+}
+
+func TestCycleCountsService_GenerateTask_InactivePlan(t *testing.T) {
+	repo := newCycleCountTestRepo("open", true)
+	repo.plan.IsActive = false
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.GenerateTask("plan-1", ccTenant, "user-1", &requests.GenerateCountTaskRequest{})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Blind counts
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestCycleCountsService_GetTask_BlindHidesExpected(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	view, resp := svc.GetTask("task-1", ccTenant)
+	require.Nil(t, resp)
+	require.Len(t, view.Lines, 2)
+	assert.Nil(t, view.Lines[0].ExpectedQty)
+	assert.Nil(t, view.Lines[0].Variance)
+
+	review, resp := svc.ReviewTask("task-1", ccTenant)
+	require.Nil(t, resp)
+	require.NotNil(t, review.Lines[0].ExpectedQty)
+	assert.Equal(t, 100.0, *review.Lines[0].ExpectedQty)
+}
+
+func TestCycleCountsService_GetTask_NonBlindShowsExpected(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", false)
+	svc := NewCycleCountsService(repo, nil)
+
+	view, resp := svc.GetTask("task-1", ccTenant)
+	require.Nil(t, resp)
+	require.NotNil(t, view.Lines[0].ExpectedQty)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// RecordCount
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestCycleCountsService_RecordCount_StartsOpenTask(t *testing.T) {
+	repo := newCycleCountTestRepo("open", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	line, resp := svc.RecordCount("task-1", "line-1", ccTenant, "user-1", &requests.RecordCountRequest{CountedQty: tools.Float64Ptr(98)})
+	require.Nil(t, resp)
+	assert.Equal(t, "counted", line.Status)
+	assert.Nil(t, line.ExpectedQty, "blind task must not reveal expected qty to the counter")
+	assert.Equal(t, "in_progress", repo.task.Status)
+}
+
+func TestCycleCountsService_RecordCount_ThresholdRequiresRecount(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	line, resp := svc.RecordCount("task-1", "line-1", ccTenant, "user-1", &requests.RecordCountRequest{CountedQty: tools.Float64Ptr(80)})
+	require.Nil(t, resp)
+	assert.Equal(t, "recount_required", line.Status)
+
+	line, resp = svc.RecordCount("task-1", "line-1", ccTenant, "user-2", &requests.RecordCountRequest{CountedQty: tools.Float64Ptr(82)})
+	require.Nil(t, resp)
+	assert.Equal(t, "recounted", line.Status)
+	require.NotNil(t, repo.lines[0].RecountQty)
+	assert.Equal(t, 82.0, *repo.lines[0].RecountQty)
+	assert.Equal(t, 80.0, *repo.lines[0].CountedQty, "first count is kept for audit")
+}
+
+func TestCycleCountsService_RecordCount_RejectsPendingApproval(t *testing.T) {
+	repo := newCycleCountTestRepo("pending_approval", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.RecordCount("task-1", "line-1", ccTenant, "user-1", &requests.RecordCountRequest{CountedQty: tools.Float64Ptr(1)})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCycleCountsService_RecordCount_UnknownLine(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.RecordCount("task-1", "line-x", ccTenant, "user-1", &requests.RecordCountRequest{CountedQty: tools.Float64Ptr(1)})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Submit / approve / reject
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestCycleCountsService_SubmitTask_RequiresAllLinesCounted(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", true)
+	repo.lines[0].Status = "counted"
+	repo.lines[0].CountedQty = tools.Float64Ptr(100)
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.SubmitTask("task-1", ccTenant, "user-1")
+	require.NotNil(t, resp)
+	assert.Equal(t, "in_progress", repo.task.Status)
+
+	repo.lines[1].Status = "recounted"
+	repo.lines[1].CountedQty = tools.Float64Ptr(30)
+	repo.lines[1].RecountQty = tools.Float64Ptr(25)
+	task, resp := svc.SubmitTask("task-1", ccTenant, "user-1")
+	require.Nil(t, resp)
+	assert.Equal(t, "pending_approval", task.Status)
+}
+
+func TestCycleCountsService_ApproveTask_RevealsCounts(t *testing.T) {
+	repo := newCycleCountTestRepo("pending_approval", true)
+	repo.lines[0].Status = "counted"
+	repo.lines[0].CountedQty = tools.Float64Ptr(100)
+	repo.lines[1].Status = "counted"
+	repo.lines[1].CountedQty = tools.Float64Ptr(17)
+	svc := NewCycleCountsService(repo, nil)
+
+	view, resp := svc.ApproveTask("task-1", ccTenant, "approver-1")
+	require.Nil(t, resp)
+	assert.Equal(t, "approved", view.Status)
+	assert.Equal(t, []string{cycleCountGainReason, cycleCountLossReason}, repo.approveReasons)
+	require.Len(t, view.Lines, 2)
+	require.NotNil(t, view.Lines[1].ExpectedQty, "expected quantities are shown once approved")
+}
+
+func TestCycleCountsService_ApproveTask_WrongStatus(t *testing.T) {
+	repo := newCycleCountTestRepo("in_progress", true)
+	svc := NewCycleCountsService(repo, nil)
+
+	_, resp := svc.ApproveTask("task-1", ccTenant, "approver-1")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "in_progress", repo.task.Status)
+}
+
+func TestCycleCountsService_RejectTask_FlagsLinesAndReopens(t *testing.T) {
+	repo := newCycleCountTestRepo("pending_approval", true)
+	repo.lines[0].Status = "counted"
+	repo.lines[0].CountedQty = tools.Float64Ptr(50)
+	svc := NewCycleCountsService(repo, nil)
+
+	task, resp := svc.RejectTask("task-1", ccTenant, "approver-1", &requests.RejectCountTaskRequest{LineIDs: []string{"line-1"}})
+	require.Nil(t, resp)
+	assert.Equal(t, "in_progress", task.Status)
+	assert.Equal(t, []string{"line-1"}, repo.flagged)
+	assert.Equal(t, "recount_required", repo.lines[0].Status)
+}
@@ -2,26 +2,45 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/jung-kurt/gofpdf"
 	"gorm.io/gorm"
 )
 
-// DeliveryNotesService provides business logic for delivery notes (DN1-DN3).
+// DeliveryNotesService provides business logic for delivery notes (DN1-DN3) and their
+// proof of delivery. PDFs and POD images live in Storage under tenant-prefixed keys.
+// Notifications is optional; when set, the sales order creator is notified when a proof of
+// delivery is recorded.
 type DeliveryNotesService struct {
-	Repository ports.DeliveryNotesRepository
-	DB         *gorm.DB
+	Repository    ports.DeliveryNotesRepository
+	DB            *gorm.DB
+	Storage       tools.DocumentStorage
+	Notifications *NotificationsService
 }
 
-func NewDeliveryNotesService(repo ports.DeliveryNotesRepository, db *gorm.DB) *DeliveryNotesService {
-	return &DeliveryNotesService{Repository: repo, DB: db}
+const (
+	// MaxPODImageBytes is the size limit of the signature and each delivery photo.
+	MaxPODImageBytes = 5 << 20
+	// MaxPODPhotos is the number of delivery photos accepted per proof of delivery.
+	MaxPODPhotos = 10
+)
+
+func NewDeliveryNotesService(repo ports.DeliveryNotesRepository, db *gorm.DB, storage tools.DocumentStorage) *DeliveryNotesService {
+	return &DeliveryNotesService{Repository: repo, DB: db, Storage: storage}
 }
 
 // List returns paginated delivery notes for a tenant.
@@ -29,9 +48,14 @@ func (s *DeliveryNotesService) List(tenantID string, customerID, soNumber *strin
 	return s.Repository.List(tenantID, customerID, soNumber, from, to, page, limit)
 }
 
-// GetByID returns a full delivery note by ID.
+// GetByID returns a full delivery note by ID, with the URLs of its POD images.
 func (s *DeliveryNotesService) GetByID(id, tenantID string) (*responses.DeliveryNoteResponse, *responses.InternalResponse) {
-	return s.Repository.GetByID(id, tenantID)
+	dn, resp := s.Repository.GetByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	setPODURLs(dn)
+	return dn, nil
 }
 
 // GetDNNumber returns the dn_number for a given delivery note (used for PDF download filename).
@@ -39,9 +63,26 @@ func (s *DeliveryNotesService) GetDNNumber(id, tenantID string) (string, *respon
 	return s.Repository.GetDNNumber(id, tenantID)
 }
 
-// PDFLocalPath returns the filesystem path for a DN's PDF.
-func PDFLocalPath(dnID string) string {
-	return filepath.Join("/tmp/estock-pdfs", dnID+".pdf")
+// DNPDFKey returns the document storage key of a DN's PDF.
+func DNPDFKey(tenantID, dnID string) string {
+	return tools.TenantDocumentKey(tenantID, "delivery-notes", dnID+".pdf")
+}
+
+// PODFileURL returns the API URL serving a POD image of a DN.
+func PODFileURL(dnID, key string) string {
+	return "/api/delivery-notes/" + dnID + "/pod/" + path.Base(key)
+}
+
+// setPODURLs fills the signature and photo URLs of a delivery note from their storage keys.
+func setPODURLs(dn *responses.DeliveryNoteResponse) {
+	if dn.PODSignaturePath != nil {
+		url := PODFileURL(dn.ID, *dn.PODSignaturePath)
+		dn.PODSignatureURL = &url
+	}
+	dn.PODPhotoURLs = nil
+	for _, key := range dn.PODPhotoPaths {
+		dn.PODPhotoURLs = append(dn.PODPhotoURLs, PODFileURL(dn.ID, key))
+	}
 }
 
 // PDFAPIURL returns the API URL for downloading a DN's PDF.
@@ -49,12 +90,175 @@ func PDFAPIURL(dnID string) string {
 	return "/api/delivery-notes/" + dnID + "/pdf"
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// Proof of delivery
+// ─────────────────────────────────────────────────────────────────────────────
+
+// podImageExt checks a POD image (PNG or JPEG, at most MaxPODImageBytes) and returns its file
+// extension.
+func podImageExt(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("la imagen está vacía")
+	}
+	if len(data) > MaxPODImageBytes {
+		return "", fmt.Errorf("la imagen supera el máximo de %d MB", MaxPODImageBytes>>20)
+	}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png", nil
+	case "image/jpeg":
+		return ".jpg", nil
+	}
+	return "", fmt.Errorf("la imagen debe ser PNG o JPEG")
+}
+
+// parsePODDeliveredAt parses delivered_at (RFC3339, defaults to now), which cannot be in the future.
+func parsePODDeliveredAt(v *string, now time.Time) (time.Time, error) {
+	if v == nil || strings.TrimSpace(*v) == "" {
+		return now, nil
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(*v))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("delivered_at debe tener formato RFC3339")
+	}
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("delivered_at no puede estar en el futuro")
+	}
+	return t, nil
+}
+
+// RecordPOD records the proof of delivery of a delivery note: receiver name, delivery time,
+// optional signature and photos (PNG/JPEG), GPS position and notes. The PDF is regenerated with
+// the POD block, the sales order moves to delivered when this was its last undelivered note,
+// and the sales order creator is notified.
+func (s *DeliveryNotesService) RecordPOD(id, tenantID, userID string, req *requests.RecordPODRequest, signature []byte, photos [][]byte) (*responses.DeliveryNoteResponse, *responses.InternalResponse) {
+	badRequest := func(msg string) *responses.InternalResponse {
+		return &responses.InternalResponse{Message: msg, Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+
+	if strings.TrimSpace(req.ReceiverName) == "" {
+		return nil, badRequest("receiver_name es requerido")
+	}
+	deliveredAt, err := parsePODDeliveredAt(req.DeliveredAt, time.Now())
+	if err != nil {
+		return nil, badRequest(err.Error())
+	}
+	if len(photos) > MaxPODPhotos {
+		return nil, badRequest(fmt.Sprintf("Se permiten como máximo %d fotos", MaxPODPhotos))
+	}
+
+	// Keys carry a timestamp so a rejected attempt never overwrites the images of a recorded POD.
+	stamp := time.Now().UnixNano()
+	files := map[string][]byte{}
+	pod := &database.ProofOfDelivery{
+		DeliveredAt:  deliveredAt,
+		ReceiverName: strings.TrimSpace(req.ReceiverName),
+		GPS:          req.GPS,
+		Notes:        req.Notes,
+		RecordedBy:   userID,
+	}
+	if signature != nil {
+		ext, err := podImageExt(signature)
+		if err != nil {
+			return nil, badRequest("Firma inválida: " + err.Error())
+		}
+		key := tools.TenantDocumentKey(tenantID, "pod", id, fmt.Sprintf("%d-signature%s", stamp, ext))
+		pod.SignaturePath = &key
+		files[key] = signature
+	}
+	for i, photo := range photos {
+		ext, err := podImageExt(photo)
+		if err != nil {
+			return nil, badRequest(fmt.Sprintf("Foto %d inválida: %s", i+1, err.Error()))
+		}
+		key := tools.TenantDocumentKey(tenantID, "pod", id, fmt.Sprintf("%d-photo-%d%s", stamp, i+1, ext))
+		pod.PhotoPaths = append(pod.PhotoPaths, key)
+		files[key] = photo
+	}
+
+	ctx := context.Background()
+	removeFiles := func() {
+		for key := range files {
+			if err := s.Storage.Delete(ctx, key); err != nil {
+				fmt.Printf("[WARN] RecordPOD: delete %s: %v\n", key, err)
+			}
+		}
+	}
+	for key, data := range files {
+		if _, err := s.Storage.Put(ctx, key, data, http.DetectContentType(data)); err != nil {
+			removeFiles()
+			return nil, &responses.InternalResponse{Error: err, Message: "Error al guardar las imágenes de la prueba de entrega"}
+		}
+	}
+
+	so, resp := s.Repository.RecordPOD(id, tenantID, pod)
+	if resp != nil {
+		removeFiles()
+		return nil, resp
+	}
+
+	s.GeneratePDFAsync(id, tenantID)
+	s.notifyPOD(id, tenantID, so)
+
+	return s.GetByID(id, tenantID)
+}
+
+// notifyPOD tells the sales order creator that a delivery note was delivered (fire-and-forget).
+func (s *DeliveryNotesService) notifyPOD(dnID, tenantID string, so *database.SalesOrder) {
+	if s.Notifications == nil || so == nil || so.CreatedBy == nil || *so.CreatedBy == "" {
+		return
+	}
+	dnNumber, resp := s.Repository.GetDNNumber(dnID, tenantID)
+	if resp != nil {
+		dnNumber = dnID
+	}
+	title := "Nota de entrega entregada"
+	body := fmt.Sprintf("La nota de entrega %s de la orden de venta %s fue entregada.", dnNumber, so.SONumber)
+	if so.Status == "delivered" {
+		title = "Orden de venta entregada"
+		body = fmt.Sprintf("La orden de venta %s fue entregada (nota de entrega %s).", so.SONumber, dnNumber)
+	}
+	_ = s.Notifications.Send(context.Background(), *so.CreatedBy, "order_delivered", title, body, "sales_order", so.ID)
+}
+
+// PODFile returns a POD image of a delivery note and its content type; name must be the file
+// name of the DN's signature or one of its photos.
+func (s *DeliveryNotesService) PODFile(id, tenantID, name string) ([]byte, string, *responses.InternalResponse) {
+	notFound := &responses.InternalResponse{
+		Message:    "Archivo de prueba de entrega no encontrado",
+		Handled:    true,
+		StatusCode: responses.StatusNotFound,
+	}
+	dn, resp := s.Repository.GetByID(id, tenantID)
+	if resp != nil {
+		return nil, "", resp
+	}
+	keys := append([]string{}, dn.PODPhotoPaths...)
+	if dn.PODSignaturePath != nil {
+		keys = append(keys, *dn.PODSignaturePath)
+	}
+	for _, key := range keys {
+		if path.Base(key) != name {
+			continue
+		}
+		data, _, err := s.Storage.Get(context.Background(), key)
+		if errors.Is(err, tools.ErrDocumentNotFound) {
+			return nil, "", notFound
+		}
+		if err != nil {
+			return nil, "", &responses.InternalResponse{Error: err, Message: "Error al leer el archivo de prueba de entrega"}
+		}
+		return data, http.DetectContentType(data), nil
+	}
+	return nil, "", notFound
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // DN2 — PDF generation (async goroutine, implements repositories.DNPDFGenerator)
 // ─────────────────────────────────────────────────────────────────────────────
 
 // GeneratePDFAsync implements repositories.DNPDFGenerator.
-// Spawns a goroutine that generates the PDF and writes it to local FS.
+// Spawns a goroutine that generates the PDF and stores it in the document storage.
 func (s *DeliveryNotesService) GeneratePDFAsync(dnID, tenantID string) {
 	go s.generatePDF(dnID, tenantID)
 }
@@ -65,38 +269,77 @@ func (s *DeliveryNotesService) generatePDF(dnID, tenantID string) {
 		fmt.Printf("[WARN] GeneratePDF: fetch DN %s: %s\n", dnID, resp.Message)
 		return
 	}
+	if _, err := s.storePDF(dn, tenantID); err != nil {
+		fmt.Printf("[WARN] GeneratePDF: %s: %v\n", dnID, err)
+	}
+}
 
-	pdfBytes, err := buildDNPDF(dn)
-	if err != nil {
-		fmt.Printf("[WARN] GeneratePDF: build PDF for %s: %v\n", dnID, err)
-		return
+// storePDF renders the PDF of a delivery note, stores it under DNPDFKey and records its key and
+// checksum on the delivery note. The signature image of a recorded POD is embedded.
+func (s *DeliveryNotesService) storePDF(dn *responses.DeliveryNoteResponse, tenantID string) ([]byte, error) {
+	ctx := context.Background()
+	var signature []byte
+	if dn.PODSignaturePath != nil {
+		data, _, err := s.Storage.Get(ctx, *dn.PODSignaturePath)
+		if err != nil {
+			fmt.Printf("[WARN] GeneratePDF: read signature of %s: %v\n", dn.ID, err)
+		}
+		signature = data
 	}
 
-	// Ensure directory exists.
-	dir := "/tmp/estock-pdfs"
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		fmt.Printf("[WARN] GeneratePDF: mkdir %s: %v\n", dir, err)
-		return
+	pdfBytes, err := buildDNPDF(dn, signature)
+	if err != nil {
+		return nil, fmt.Errorf("build PDF: %w", err)
 	}
+	obj, err := s.Storage.Put(ctx, DNPDFKey(tenantID, dn.ID), pdfBytes, "application/pdf")
+	if err != nil {
+		return nil, fmt.Errorf("store PDF: %w", err)
+	}
+	if resp := s.Repository.UpdatePDFStorageKey(dn.ID, obj.Key, obj.SHA256); resp != nil {
+		return nil, fmt.Errorf("update pdf_storage_key: %s", resp.Message)
+	}
+	return pdfBytes, nil
+}
 
-	outPath := PDFLocalPath(dnID)
-	if err := os.WriteFile(outPath, pdfBytes, 0644); err != nil {
-		fmt.Printf("[WARN] GeneratePDF: write %s: %v\n", outPath, err)
-		return
+// GetPDF returns the PDF of a delivery note with its DN number. A nil PDF without error means
+// it has not been generated yet; its generation is started. A stored PDF that is missing or
+// fails its checksum is regenerated on the spot.
+func (s *DeliveryNotesService) GetPDF(id, tenantID string) ([]byte, string, *responses.InternalResponse) {
+	dnNumber, resp := s.Repository.GetDNNumber(id, tenantID)
+	if resp != nil {
+		return nil, "", resp
+	}
+	dn, resp := s.Repository.GetByID(id, tenantID)
+	if resp != nil {
+		return nil, "", resp
+	}
+	if dn.PdfStorageKey == nil {
+		s.GeneratePDFAsync(id, tenantID)
+		return nil, dnNumber, nil
 	}
 
-	pdfURL := PDFAPIURL(dnID)
-	if resp := s.Repository.UpdatePDFURL(dnID, pdfURL); resp != nil {
-		fmt.Printf("[WARN] GeneratePDF: update pdf_url %s: %s\n", dnID, resp.Message)
+	data, _, err := s.Storage.Get(context.Background(), *dn.PdfStorageKey)
+	if err == nil {
+		return data, dnNumber, nil
+	}
+	if !errors.Is(err, tools.ErrDocumentNotFound) && !errors.Is(err, tools.ErrDocumentChecksum) {
+		return nil, "", &responses.InternalResponse{Error: err, Message: "Error al leer el PDF de la nota de entrega"}
+	}
+	fmt.Printf("[WARN] GetPDF: %s: %v — regenerating\n", id, err)
+	data, err = s.storePDF(dn, tenantID)
+	if err != nil {
+		return nil, "", &responses.InternalResponse{Error: err, Message: "Error al regenerar el PDF de la nota de entrega"}
 	}
+	return data, dnNumber, nil
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
 // buildDNPDF — PDF layout with gofpdf
 // ─────────────────────────────────────────────────────────────────────────────
 
-// buildDNPDF constructs PDF bytes for a delivery note.
-func buildDNPDF(dn *responses.DeliveryNoteResponse) ([]byte, error) {
+// buildDNPDF constructs PDF bytes for a delivery note. Delivered notes get the proof of delivery
+// block (with the signature image when given) instead of the blank signature lines.
+func buildDNPDF(dn *responses.DeliveryNoteResponse, signature []byte) ([]byte, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(15, 20, 15)
 	pdf.AddPage()
@@ -124,11 +367,23 @@ func buildDNPDF(dn *responses.DeliveryNoteResponse) ([]byte, error) {
 	pdf.Ln(4)
 
 	// ── Items table ──────────────────────────────────────────────────────────
+	// Packed delivery notes get a package (SSCC) column.
+	packed := false
+	for _, item := range dn.Items {
+		if item.SSCC != nil {
+			packed = true
+			break
+		}
+	}
+
 	pdf.SetFont("Helvetica", "B", 10)
 	pdf.SetFillColor(220, 220, 220)
 	pdf.CellFormat(20, 8, "#", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(50, 8, "SKU", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(30, 8, "Qty", "1", 0, "C", true, 0, "")
+	if packed {
+		pdf.CellFormat(45, 8, "Package (SSCC)", "1", 0, "L", true, 0, "")
+	}
+	pdf.CellFormat(45, 8, "SKU", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 8, "Qty", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(0, 8, "Lot Numbers", "1", 1, "L", true, 0, "")
 
 	pdf.SetFont("Helvetica", "", 9)
@@ -136,8 +391,15 @@ func buildDNPDF(dn *responses.DeliveryNoteResponse) ([]byte, error) {
 	for i, item := range dn.Items {
 		lots := strings.Join(item.LotNumbers, ", ")
 		pdf.CellFormat(20, 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(50, 7, item.ArticleSKU, "1", 0, "L", false, 0, "")
-		pdf.CellFormat(30, 7, fmt.Sprintf("%.3f", item.Qty), "1", 0, "C", false, 0, "")
+		if packed {
+			sscc := ""
+			if item.SSCC != nil {
+				sscc = *item.SSCC
+			}
+			pdf.CellFormat(45, 7, sscc, "1", 0, "L", false, 0, "")
+		}
+		pdf.CellFormat(45, 7, item.ArticleSKU, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, dnItemQtyLabel(item), "1", 0, "C", false, 0, "")
 		pdf.CellFormat(0, 7, lots, "1", 1, "L", false, 0, "")
 	}
 	pdf.Ln(4)
@@ -147,14 +409,18 @@ func buildDNPDF(dn *responses.DeliveryNoteResponse) ([]byte, error) {
 	pdf.Cell(0, 7, fmt.Sprintf("Total Items: %d", dn.TotalItems))
 	pdf.Ln(14)
 
-	// ── Signature line ────────────────────────────────────────────────────────
-	pdf.SetFont("Helvetica", "", 10)
-	pdf.Cell(80, 7, "_______________________________")
-	pdf.Cell(0, 7, "_______________________________")
-	pdf.Ln(6)
-	pdf.Cell(80, 7, "Received By (Signature)")
-	pdf.Cell(0, 7, "Delivered By (Signature)")
-	pdf.Ln(8)
+	if dn.DeliveredAt != nil {
+		writePODBlock(pdf, dn, signature)
+	} else {
+		// ── Signature line ────────────────────────────────────────────────────────
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.Cell(80, 7, "_______________________________")
+		pdf.Cell(0, 7, "_______________________________")
+		pdf.Ln(6)
+		pdf.Cell(80, 7, "Received By (Signature)")
+		pdf.Cell(0, 7, "Delivered By (Signature)")
+		pdf.Ln(8)
+	}
 	pdf.SetFont("Helvetica", "I", 8)
 	pdf.Cell(0, 6, fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)))
 
@@ -165,6 +431,62 @@ func buildDNPDF(dn *responses.DeliveryNoteResponse) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// dnItemQtyLabel is the quantity cell of a DN line: base units, followed by the quantity in the
+// presentation the order line was entered in, e.g. "24.000 (2 CAJA)".
+func dnItemQtyLabel(item responses.DeliveryNoteItemResponse) string {
+	label := fmt.Sprintf("%.3f", item.Qty)
+	if item.Presentation == nil || item.PresentationFactor == nil || *item.PresentationFactor <= 0 {
+		return label
+	}
+	entered := math.Round(item.Qty / *item.PresentationFactor * 1000) / 1000
+	return fmt.Sprintf("%s (%s %s)", label, strconv.FormatFloat(entered, 'f', -1, 64), *item.Presentation)
+}
+
+// writePODBlock renders the proof of delivery: delivery time, receiver, GPS, notes, photo count
+// and the signature image.
+func writePODBlock(pdf *gofpdf.Fpdf, dn *responses.DeliveryNoteResponse, signature []byte) {
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.Cell(0, 8, "Proof of Delivery")
+	pdf.Ln(9)
+
+	pdf.SetFont("Helvetica", "", 10)
+	receiver := ""
+	if dn.SignedBy != nil {
+		receiver = *dn.SignedBy
+	}
+	pdf.Cell(90, 7, fmt.Sprintf("Delivered At: %s", dn.DeliveredAt.Format("2006-01-02 15:04 MST")))
+	pdf.Cell(0, 7, fmt.Sprintf("Received By: %s", receiver))
+	pdf.Ln(7)
+	if dn.PODGPS != nil && *dn.PODGPS != "" {
+		pdf.Cell(0, 7, fmt.Sprintf("GPS: %s", *dn.PODGPS))
+		pdf.Ln(7)
+	}
+	if dn.PODNotes != nil && *dn.PODNotes != "" {
+		pdf.MultiCell(0, 6, fmt.Sprintf("Notes: %s", *dn.PODNotes), "", "L", false)
+	}
+	if len(dn.PODPhotoPaths) > 0 {
+		pdf.Cell(0, 7, fmt.Sprintf("Photos: %d", len(dn.PODPhotoPaths)))
+		pdf.Ln(7)
+	}
+
+	if len(signature) > 0 {
+		imageType := "PNG"
+		if http.DetectContentType(signature) == "image/jpeg" {
+			imageType = "JPG"
+		}
+		opts := gofpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+		pdf.RegisterImageOptionsReader("pod-signature", opts, bytes.NewReader(signature))
+		pdf.ImageOptions("pod-signature", pdf.GetX(), pdf.GetY()+2, 60, 0, true, opts, 0, "")
+	} else {
+		pdf.Ln(8)
+		pdf.Cell(80, 7, "_______________________________")
+		pdf.Ln(6)
+	}
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.Cell(80, 7, "Received By (Signature)")
+	pdf.Ln(8)
+}
+
 // compile-time check: DeliveryNotesService satisfies ports.DeliveryNotesRepository indirectly
 // and repositories.DNPDFGenerator via GeneratePDFAsync.
 var _ interface{ GeneratePDFAsync(dnID, tenantID string) } = (*DeliveryNotesService)(nil)
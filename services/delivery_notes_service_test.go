@@ -1,10 +1,20 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"image"
+	"image/png"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,6 +30,11 @@ type mockDNRepo struct {
 	dnNumber    string
 	dnNumberErr *responses.InternalResponse
 	updateErr   *responses.InternalResponse
+	storedKey   string
+	storedSHA   string
+	pod         *database.ProofOfDelivery
+	podErr      *responses.InternalResponse
+	pdfStored   chan struct{} // when set, signalled after the PDF key is recorded
 }
 
 func (m *mockDNRepo) List(_ string, _, _ *string, _, _ *string, _, _ int) (*responses.DeliveryNoteListResponse, *responses.InternalResponse) {
@@ -28,12 +43,23 @@ func (m *mockDNRepo) List(_ string, _, _ *string, _, _ *string, _, _ int) (*resp
 func (m *mockDNRepo) GetByID(_, _ string) (*responses.DeliveryNoteResponse, *responses.InternalResponse) {
 	return m.getResult, m.getErr
 }
-func (m *mockDNRepo) UpdatePDFURL(_, _ string) *responses.InternalResponse {
+func (m *mockDNRepo) UpdatePDFStorageKey(_, storageKey, checksum string) *responses.InternalResponse {
+	m.storedKey, m.storedSHA = storageKey, checksum
+	if m.pdfStored != nil {
+		m.pdfStored <- struct{}{}
+	}
 	return m.updateErr
 }
 func (m *mockDNRepo) GetDNNumber(_, _ string) (string, *responses.InternalResponse) {
 	return m.dnNumber, m.dnNumberErr
 }
+func (m *mockDNRepo) RecordPOD(_, _ string, pod *database.ProofOfDelivery) (*database.SalesOrder, *responses.InternalResponse) {
+	if m.podErr != nil {
+		return nil, m.podErr
+	}
+	m.pod = pod
+	return &database.SalesOrder{ID: "so-1", SONumber: "SO-2026-0001", Status: "completed"}, nil
+}
 
 // ─────────────────────────────────────────────────────────────────────────────
 // tests
@@ -42,7 +68,7 @@ func (m *mockDNRepo) GetDNNumber(_, _ string) (string, *responses.InternalRespon
 func TestDeliveryNotesService_List_OK(t *testing.T) {
 	expected := &responses.DeliveryNoteListResponse{Total: 2, Page: 1, Limit: 50}
 	repo := &mockDNRepo{listResult: expected}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 
 	result, resp := svc.List("tenant-1", nil, nil, nil, nil, 1, 50)
 	require.Nil(t, resp)
@@ -53,7 +79,7 @@ func TestDeliveryNotesService_List_Error(t *testing.T) {
 	repo := &mockDNRepo{listErr: &responses.InternalResponse{
 		Error: errors.New("db error"), Message: "Error",
 	}}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 
 	result, resp := svc.List("tenant-1", nil, nil, nil, nil, 1, 50)
 	require.Nil(t, result)
@@ -63,7 +89,7 @@ func TestDeliveryNotesService_List_Error(t *testing.T) {
 func TestDeliveryNotesService_GetByID_OK(t *testing.T) {
 	expected := &responses.DeliveryNoteResponse{ID: "dn-1", DNNumber: "DN-2026-0001"}
 	repo := &mockDNRepo{getResult: expected}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 
 	dn, resp := svc.GetByID("dn-1", "tenant-1")
 	require.Nil(t, resp)
@@ -76,7 +102,7 @@ func TestDeliveryNotesService_GetByID_NotFound(t *testing.T) {
 		Handled:    true,
 		StatusCode: responses.StatusNotFound,
 	}}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 
 	dn, resp := svc.GetByID("nonexistent", "tenant-1")
 	require.Nil(t, dn)
@@ -86,16 +112,15 @@ func TestDeliveryNotesService_GetByID_NotFound(t *testing.T) {
 
 func TestDeliveryNotesService_GetDNNumber_OK(t *testing.T) {
 	repo := &mockDNRepo{dnNumber: "DN-2026-0001"}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 
 	num, resp := svc.GetDNNumber("dn-1", "tenant-1")
 	require.Nil(t, resp)
 	require.Equal(t, "DN-2026-0001", num)
 }
 
-func TestPDFLocalPath(t *testing.T) {
-	path := PDFLocalPath("abc123")
-	require.Equal(t, "/tmp/estock-pdfs/abc123.pdf", path)
+func TestDNPDFKey(t *testing.T) {
+	require.Equal(t, "tenant-1/delivery-notes/abc123.pdf", DNPDFKey("tenant-1", "abc123"))
 }
 
 func TestPDFAPIURL(t *testing.T) {
@@ -114,17 +139,202 @@ func TestBuildDNPDF_OK(t *testing.T) {
 			{ArticleSKU: "SKU-B", Qty: 5},
 		},
 	}
-	pdfBytes, err := buildDNPDF(dn)
+	pdfBytes, err := buildDNPDF(dn, nil)
 	require.NoError(t, err)
 	require.Greater(t, len(pdfBytes), 100, "PDF should not be empty")
 	// Basic PDF magic bytes check.
 	require.Equal(t, "%PDF", string(pdfBytes[:4]))
 }
 
+func TestDNItemQtyLabel(t *testing.T) {
+	caja, factor := "CAJA", 12.0
+	require.Equal(t, "10.000", dnItemQtyLabel(responses.DeliveryNoteItemResponse{Qty: 10}))
+	require.Equal(t, "24.000 (2 CAJA)", dnItemQtyLabel(responses.DeliveryNoteItemResponse{Qty: 24, Presentation: &caja, PresentationFactor: &factor}))
+	require.Equal(t, "30.000 (2.5 CAJA)", dnItemQtyLabel(responses.DeliveryNoteItemResponse{Qty: 30, Presentation: &caja, PresentationFactor: &factor}))
+}
+
 func TestDeliveryNotesService_GeneratePDFAsync_NoRepo(t *testing.T) {
 	// Should not panic with nil repo (repo.GetByID returns err, goroutine logs and exits).
 	repo := &mockDNRepo{getErr: &responses.InternalResponse{Message: "not found", Handled: true}}
-	svc := NewDeliveryNotesService(repo, nil)
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
 	// Fire async and give it a moment to run.
 	svc.GeneratePDFAsync("dn-1", "tenant-1")
 }
+
+// pngHeader is enough for http.DetectContentType to report image/png.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestPODImageExt(t *testing.T) {
+	ext, err := podImageExt(pngHeader)
+	require.NoError(t, err)
+	require.Equal(t, ".png", ext)
+
+	ext, err = podImageExt([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	require.NoError(t, err)
+	require.Equal(t, ".jpg", ext)
+
+	_, err = podImageExt([]byte("%PDF-1.4"))
+	require.Error(t, err)
+	_, err = podImageExt(nil)
+	require.Error(t, err)
+	_, err = podImageExt(append(pngHeader, make([]byte, MaxPODImageBytes)...))
+	require.Error(t, err)
+}
+
+func TestParsePODDeliveredAt(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	got, err := parsePODDeliveredAt(nil, now)
+	require.NoError(t, err)
+	require.Equal(t, now, got)
+
+	past := "2026-10-18T09:15:00Z"
+	got, err = parsePODDeliveredAt(&past, now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 10, 18, 9, 15, 0, 0, time.UTC), got)
+
+	future := "2026-10-19T09:15:00Z"
+	_, err = parsePODDeliveredAt(&future, now)
+	require.Error(t, err)
+
+	bad := "18/10/2026"
+	_, err = parsePODDeliveredAt(&bad, now)
+	require.Error(t, err)
+}
+
+func TestDeliveryNotesService_RecordPOD_RejectsInvalidSignature(t *testing.T) {
+	repo := &mockDNRepo{}
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
+
+	_, resp := svc.RecordPOD("dn-1", "tenant-1", "user-1",
+		&requests.RecordPODRequest{ReceiverName: "Ana Mora"}, []byte("not an image"), nil)
+	require.NotNil(t, resp)
+	require.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	require.Nil(t, repo.pod)
+}
+
+func TestDeliveryNotesService_RecordPOD_TooManyPhotos(t *testing.T) {
+	repo := &mockDNRepo{}
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(t.TempDir()))
+
+	photos := make([][]byte, MaxPODPhotos+1)
+	for i := range photos {
+		photos[i] = pngHeader
+	}
+	_, resp := svc.RecordPOD("dn-1", "tenant-1", "user-1",
+		&requests.RecordPODRequest{ReceiverName: "Ana Mora"}, nil, photos)
+	require.NotNil(t, resp)
+	require.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	require.Nil(t, repo.pod)
+}
+
+func TestDeliveryNotesService_RecordPOD_OK(t *testing.T) {
+	repo := &mockDNRepo{getResult: &responses.DeliveryNoteResponse{ID: "dn-1"}, pdfStored: make(chan struct{}, 1)}
+	storage := tools.NewLocalDocumentStorage(t.TempDir())
+	svc := NewDeliveryNotesService(repo, nil, storage)
+
+	dn, resp := svc.RecordPOD("dn-1", "tenant-1", "user-1",
+		&requests.RecordPODRequest{ReceiverName: " Ana Mora "}, pngHeader, [][]byte{pngHeader})
+	require.Nil(t, resp)
+	require.NotNil(t, dn)
+	require.NotNil(t, repo.pod)
+	require.Equal(t, "Ana Mora", repo.pod.ReceiverName)
+	require.Equal(t, "user-1", repo.pod.RecordedBy)
+	require.NotNil(t, repo.pod.SignaturePath)
+	require.Len(t, repo.pod.PhotoPaths, 1)
+	require.True(t, strings.HasPrefix(*repo.pod.SignaturePath, "tenant-1/pod/dn-1/"))
+	_, _, err := storage.Get(context.Background(), *repo.pod.SignaturePath)
+	require.NoError(t, err)
+	_, _, err = storage.Get(context.Background(), repo.pod.PhotoPaths[0])
+	require.NoError(t, err)
+
+	// The PDF is regenerated in the background; let it finish before TempDir is removed.
+	select {
+	case <-repo.pdfStored:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery note PDF was not regenerated after the POD")
+	}
+}
+
+func TestDeliveryNotesService_RecordPOD_RemovesFilesOnConflict(t *testing.T) {
+	repo := &mockDNRepo{podErr: &responses.InternalResponse{
+		Message: "ya entregada", Handled: true, StatusCode: responses.StatusConflict,
+	}}
+	root := t.TempDir()
+	svc := NewDeliveryNotesService(repo, nil, tools.NewLocalDocumentStorage(root))
+
+	_, resp := svc.RecordPOD("dn-conflict", "tenant-1", "user-1",
+		&requests.RecordPODRequest{ReceiverName: "Ana Mora"}, pngHeader, nil)
+	require.NotNil(t, resp)
+	require.Equal(t, responses.StatusConflict, resp.StatusCode)
+	files, _ := filepath.Glob(filepath.Join(root, "tenant-1", "pod", "dn-conflict", "*"))
+	require.Empty(t, files)
+}
+
+func TestBuildDNPDF_WithPOD(t *testing.T) {
+	delivered := time.Date(2026, 10, 18, 9, 15, 0, 0, time.UTC)
+	receiver := "Ana Mora"
+	gps := "9.93,-84.08"
+	dn := &responses.DeliveryNoteResponse{
+		ID:          "dn-1",
+		DNNumber:    "DN-2026-0001",
+		CustomerID:  "client-1",
+		DeliveredAt: &delivered,
+		SignedBy:    &receiver,
+		PODGPS:      &gps,
+		Items:       []responses.DeliveryNoteItemResponse{{ArticleSKU: "SKU-A", Qty: 1}},
+	}
+	var signature bytes.Buffer
+	require.NoError(t, png.Encode(&signature, image.NewGray(image.Rect(0, 0, 40, 20))))
+
+	pdfBytes, err := buildDNPDF(dn, signature.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "%PDF", string(pdfBytes[:4]))
+}
+
+func TestSetPODURLs(t *testing.T) {
+	sig := "pod/dn-1/1-signature.png"
+	dn := &responses.DeliveryNoteResponse{
+		ID:               "dn-1",
+		PODSignaturePath: &sig,
+		PODPhotoPaths:    []string{"pod/dn-1/1-photo-1.jpg"},
+	}
+	setPODURLs(dn)
+	require.Equal(t, "/api/delivery-notes/dn-1/pod/1-signature.png", *dn.PODSignatureURL)
+	require.Equal(t, []string{"/api/delivery-notes/dn-1/pod/1-photo-1.jpg"}, dn.PODPhotoURLs)
+}
+
+func TestDeliveryNotesService_GetPDF_Stored(t *testing.T) {
+	key := DNPDFKey("tenant-1", "dn-1")
+	repo := &mockDNRepo{dnNumber: "DN-2026-0001", getResult: &responses.DeliveryNoteResponse{ID: "dn-1", PdfStorageKey: &key}}
+	storage := tools.NewLocalDocumentStorage(t.TempDir())
+	_, err := storage.Put(context.Background(), key, []byte("%PDF-stored"), "application/pdf")
+	require.NoError(t, err)
+	svc := NewDeliveryNotesService(repo, nil, storage)
+
+	pdf, dnNumber, resp := svc.GetPDF("dn-1", "tenant-1")
+	require.Nil(t, resp)
+	require.Equal(t, "DN-2026-0001", dnNumber)
+	require.Equal(t, "%PDF-stored", string(pdf))
+	require.Empty(t, repo.storedKey)
+}
+
+func TestDeliveryNotesService_GetPDF_RegeneratesMissingFile(t *testing.T) {
+	key := DNPDFKey("tenant-1", "dn-1")
+	repo := &mockDNRepo{dnNumber: "DN-2026-0001", getResult: &responses.DeliveryNoteResponse{
+		ID: "dn-1", DNNumber: "DN-2026-0001", CustomerID: "client-1", PdfStorageKey: &key,
+		Items: []responses.DeliveryNoteItemResponse{{ArticleSKU: "SKU-A", Qty: 1}},
+	}}
+	storage := tools.NewLocalDocumentStorage(t.TempDir())
+	svc := NewDeliveryNotesService(repo, nil, storage)
+
+	pdf, _, resp := svc.GetPDF("dn-1", "tenant-1")
+	require.Nil(t, resp)
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+	require.Equal(t, key, repo.storedKey)
+	require.Equal(t, tools.DocumentChecksum(pdf), repo.storedSHA)
+
+	stored, _, err := storage.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, pdf, stored)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// ExchangeRatesService manages the tenant's local exchange rates.
+type ExchangeRatesService struct {
+	Repository ports.ExchangeRatesRepository
+}
+
+func NewExchangeRatesService(repo ports.ExchangeRatesRepository) *ExchangeRatesService {
+	return &ExchangeRatesService{Repository: repo}
+}
+
+func (s *ExchangeRatesService) List(tenantID string) ([]database.ExchangeRate, *responses.InternalResponse) {
+	return s.Repository.List(tenantID)
+}
+
+// Upsert sets the rate of a currency pair from the given date on (replacing the rate already
+// entered for that pair and date, if any).
+func (s *ExchangeRatesService) Upsert(tenantID, createdBy string, req *requests.UpsertExchangeRateRequest) (*database.ExchangeRate, *responses.InternalResponse) {
+	return s.Repository.Upsert(tenantID, createdBy, req)
+}
+
+func (s *ExchangeRatesService) Delete(id, tenantID string) *responses.InternalResponse {
+	return s.Repository.Delete(id, tenantID)
+}
+
+// GetRate returns the from→to rate in effect on asOf.
+func (s *ExchangeRatesService) GetRate(tenantID, from, to string, asOf time.Time) (float64, *responses.InternalResponse) {
+	return s.Repository.GetRate(tenantID, normalizeCurrency(from), normalizeCurrency(to), asOf)
+}
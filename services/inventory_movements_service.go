@@ -23,3 +23,7 @@ func (s *InventoryMovementsService) GetAllInventoryMovements(sku string) ([]data
 func (s *InventoryMovementsService) ListMovements(f ports.MovementsFilter) ([]database.InventoryMovement, *responses.InternalResponse) {
 	return s.Repository.ListMovements(f)
 }
+
+func (s *InventoryMovementsService) GetMovementCOGS(id string) (*responses.MovementCOGS, *responses.InternalResponse) {
+	return s.Repository.GetMovementCOGS(id)
+}
@@ -5,11 +5,14 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 )
 
 type InventoryService struct {
 	Repository   ports.InventoryRepository
 	ArticlesRepo ports.ArticlesRepository // optional: when set, GetPickSuggestionsBySKU sorts by rotation (FIFO/FEFO) then quantity
+	// ExchangeRates is optional: when set, GetValuation can also report a requested currency.
+	ExchangeRates ports.ExchangeRatesRepository
 }
 
 func NewInventoryService(repo ports.InventoryRepository, articlesRepo ports.ArticlesRepository) *InventoryService {
@@ -19,86 +22,129 @@ func NewInventoryService(repo ports.InventoryRepository, articlesRepo ports.Arti
 	}
 }
 
-func (s *InventoryService) GetAllInventory() ([]*dto.EnhancedInventory, *responses.InternalResponse) {
-	return s.Repository.GetAllInventory()
+func (s *InventoryService) GetAllInventory(tenantID string) ([]*dto.EnhancedInventory, *responses.InternalResponse) {
+	return s.Repository.GetAllInventory(tenantID)
 }
 
-func (s *InventoryService) GetInventoryBySkuAndLocation(sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
-	return s.Repository.GetInventoryBySkuAndLocation(sku, location)
+func (s *InventoryService) GetInventoryBySkuAndLocation(tenantID, sku, location string) (*dto.EnhancedInventory, *responses.InternalResponse) {
+	return s.Repository.GetInventoryBySkuAndLocation(tenantID, sku, location)
 }
 
-func (s *InventoryService) CreateInventory(userId string, item *requests.CreateInventory) *responses.InternalResponse {
-	return s.Repository.CreateInventory(userId, item)
+func (s *InventoryService) CreateInventory(tenantID, userId string, item *requests.CreateInventory) *responses.InternalResponse {
+	return s.Repository.CreateInventory(tenantID, userId, item)
 }
 
-func (s *InventoryService) UpdateInventory(item *requests.UpdateInventory) *responses.InternalResponse {
-	return s.Repository.UpdateInventory(item)
+func (s *InventoryService) UpdateInventory(tenantID string, item *requests.UpdateInventory) *responses.InternalResponse {
+	return s.Repository.UpdateInventory(tenantID, item)
 }
 
-func (s *InventoryService) DeleteInventory(sku, location string) *responses.InternalResponse {
-	return s.Repository.DeleteInventory(sku, location)
+func (s *InventoryService) DeleteInventory(tenantID, sku, location string) *responses.InternalResponse {
+	return s.Repository.DeleteInventory(tenantID, sku, location)
 }
 
-func (s *InventoryService) Trend(sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
-	return s.Repository.Trend(sku)
+func (s *InventoryService) Trend(tenantID, sku string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
+	return s.Repository.Trend(tenantID, sku)
 }
 
-func (s *InventoryService) ImportInventoryFromExcel(userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
-	return s.Repository.ImportInventoryFromExcel(userId, fileBytes)
+func (s *InventoryService) ImportInventoryFromExcel(tenantID, userId string, fileBytes []byte) ([]string, []string, *responses.InternalResponse) {
+	return s.Repository.ImportInventoryFromExcel(tenantID, userId, fileBytes)
 }
 
-func (s *InventoryService) ImportInventoryFromJSON(userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
-	return s.Repository.ImportInventoryFromJSON(userId, rows)
+func (s *InventoryService) ImportInventoryFromJSON(tenantID, userId string, rows []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
+	return s.Repository.ImportInventoryFromJSON(tenantID, userId, rows)
 }
 
-func (s *InventoryService) ValidateImportRows(rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
-	return s.Repository.ValidateImportRows(rows)
+func (s *InventoryService) ValidateImportRows(tenantID string, rows []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
+	return s.Repository.ValidateImportRows(tenantID, rows)
 }
 
-func (s *InventoryService) ExportInventoryToExcel() ([]byte, *responses.InternalResponse) {
-	return s.Repository.ExportInventoryToExcel()
+func (s *InventoryService) ExportInventoryToExcel(tenantID string) ([]byte, *responses.InternalResponse) {
+	return s.Repository.ExportInventoryToExcel(tenantID)
 }
 
-func (s *InventoryService) GetInventoryLots(inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
-	return s.Repository.GetInventoryLots(inventoryID)
+func (s *InventoryService) GetInventoryLots(tenantID, inventoryID string) ([]responses.InventoryLot, *responses.InternalResponse) {
+	return s.Repository.GetInventoryLots(tenantID, inventoryID)
 }
 
-func (s *InventoryService) GetInventorySerials(inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
-	return s.Repository.GetInventorySerials(inventoryID)
+func (s *InventoryService) GetInventorySerials(tenantID, inventoryID string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) {
+	return s.Repository.GetInventorySerials(tenantID, inventoryID)
 }
 
-func (s *InventoryService) CreateInventoryLot(id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
-	return s.Repository.CreateInventoryLot(id, input)
+func (s *InventoryService) CreateInventoryLot(tenantID, id string, input *requests.CreateInventoryLotRequest) *responses.InternalResponse {
+	return s.Repository.CreateInventoryLot(tenantID, id, input)
 }
 
-func (s *InventoryService) DeleteInventoryLot(id string) *responses.InternalResponse {
-	return s.Repository.DeleteInventoryLot(id)
+func (s *InventoryService) DeleteInventoryLot(tenantID, id string) *responses.InternalResponse {
+	return s.Repository.DeleteInventoryLot(tenantID, id)
 }
 
-func (s *InventoryService) CreateInventorySerial(id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
-	return s.Repository.CreateInventorySerial(id, input)
+func (s *InventoryService) CreateInventorySerial(tenantID, id string, input *requests.CreateInventorySerial) *responses.InternalResponse {
+	return s.Repository.CreateInventorySerial(tenantID, id, input)
 }
 
-func (s *InventoryService) DeleteInventorySerial(id string) *responses.InternalResponse {
-	return s.Repository.DeleteInventorySerial(id)
+func (s *InventoryService) DeleteInventorySerial(tenantID, id string) *responses.InternalResponse {
+	return s.Repository.DeleteInventorySerial(tenantID, id)
 }
 
 // GetPickSuggestionsBySKU returns FEFO-ordered pick allocations for a SKU.
 // Sorting is done in SQL (FEFO cross-location). If qty is 0, all available stock is returned.
-func (s *InventoryService) GetPickSuggestionsBySKU(sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
-	return s.Repository.GetPickSuggestionsBySKU(sku, qty)
+func (s *InventoryService) GetPickSuggestionsBySKU(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	return s.Repository.GetPickSuggestionsBySKU(tenantID, sku, qty)
+}
+
+// GetPickSuggestionsFewestStops is GetPickSuggestionsBySKU preferring fewer locations among
+// stock with the same expiration.
+func (s *InventoryService) GetPickSuggestionsFewestStops(tenantID, sku string, qty float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	return s.Repository.GetPickSuggestionsFewestStops(tenantID, sku, qty)
 }
 
 func (s *InventoryService) GenerateImportTemplate(language string) ([]byte, error) {
 	return s.Repository.GenerateImportTemplate(language)
 }
 
-// GetValuation returns AVCO-based inventory valuation grouped by article, location, or category.
-func (s *InventoryService) GetValuation(groupBy string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+// normalizeValuationGroupBy maps unknown group_by values to "article".
+func normalizeValuationGroupBy(groupBy string) string {
 	switch groupBy {
 	case "article", "location", "category":
+		return groupBy
 	default:
-		groupBy = "article"
+		return "article"
+	}
+}
+
+// GetValuation returns cost-layer inventory valuation grouped by article, location, or category
+// in the tenant base currency. A non-empty currency other than the base also converts the total
+// and every breakdown value at today's rate (handled 400 when no rate exists).
+func (s *InventoryService) GetValuation(tenantID, groupBy, currency string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+	valuation, resp := s.Repository.GetValuation(tenantID, normalizeValuationGroupBy(groupBy))
+	if resp != nil {
+		return nil, resp
+	}
+
+	currency = normalizeCurrency(currency)
+	if currency == "" || currency == valuation.Currency {
+		return valuation, nil
 	}
-	return s.Repository.GetValuation(groupBy)
+	if s.ExchangeRates == nil {
+		return nil, currencyUnavailable()
+	}
+	rate, resp := s.ExchangeRates.GetRate(tenantID, valuation.Currency, currency, tools.GetCurrentTime())
+	if resp != nil {
+		return nil, resp
+	}
+
+	total := valuation.TotalValue * rate
+	valuation.RequestedCurrency = currency
+	valuation.ExchangeRate = &rate
+	valuation.RequestedTotalValue = &total
+	for i := range valuation.Breakdown {
+		value := valuation.Breakdown[i].Value * rate
+		valuation.Breakdown[i].RequestedValue = &value
+	}
+	return valuation, nil
+}
+
+// ExportValuationToExcel returns the valuation for groupBy as an Excel workbook.
+func (s *InventoryService) ExportValuationToExcel(tenantID, groupBy string) ([]byte, *responses.InternalResponse) {
+	return s.Repository.ExportValuationToExcel(tenantID, normalizeValuationGroupBy(groupBy))
 }
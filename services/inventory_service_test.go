@@ -16,45 +16,52 @@ type mockInventoryRepo struct {
 	all        []*dto.EnhancedInventory
 	bySkuLoc   *dto.EnhancedInventory
 	createErr  *responses.InternalResponse
+	valuation  *responses.InventoryValuationResponse
 }
 
-func (m *mockInventoryRepo) GetAllInventory() ([]*dto.EnhancedInventory, *responses.InternalResponse) {
+func (m *mockInventoryRepo) GetAllInventory(_ string) ([]*dto.EnhancedInventory, *responses.InternalResponse) {
 	return m.all, nil
 }
-func (m *mockInventoryRepo) GetPickSuggestionsBySKU(_ string, _ float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+func (m *mockInventoryRepo) GetPickSuggestionsBySKU(_, _ string, _ float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
 	return nil, nil
 }
-func (m *mockInventoryRepo) GetInventoryBySkuAndLocation(_, _ string) (*dto.EnhancedInventory, *responses.InternalResponse) {
+func (m *mockInventoryRepo) GetPickSuggestionsFewestStops(_, _ string, _ float64) (*dto.PickSuggestionResponse, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockInventoryRepo) GetInventoryBySkuAndLocation(_, _, _ string) (*dto.EnhancedInventory, *responses.InternalResponse) {
 	return m.bySkuLoc, nil
 }
-func (m *mockInventoryRepo) CreateInventory(_ string, _ *requests.CreateInventory) *responses.InternalResponse {
+func (m *mockInventoryRepo) CreateInventory(_, _ string, _ *requests.CreateInventory) *responses.InternalResponse {
 	return m.createErr
 }
-func (m *mockInventoryRepo) UpdateInventory(_ *requests.UpdateInventory) *responses.InternalResponse {
+func (m *mockInventoryRepo) UpdateInventory(_ string, _ *requests.UpdateInventory) *responses.InternalResponse {
 	return nil
 }
-func (m *mockInventoryRepo) DeleteInventory(_, _ string) *responses.InternalResponse { return nil }
-func (m *mockInventoryRepo) Trend(_ string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
+func (m *mockInventoryRepo) DeleteInventory(_, _, _ string) *responses.InternalResponse { return nil }
+func (m *mockInventoryRepo) Trend(_, _ string) (*dto.ConsumptionTrend, *responses.InternalResponse) {
 	return nil, nil
 }
-func (m *mockInventoryRepo) ImportInventoryFromExcel(_ string, _ []byte) ([]string, []string, *responses.InternalResponse) {
+func (m *mockInventoryRepo) ImportInventoryFromExcel(_, _ string, _ []byte) ([]string, []string, *responses.InternalResponse) {
 	return nil, nil, nil
 }
-func (m *mockInventoryRepo) ImportInventoryFromJSON(_ string, _ []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
+func (m *mockInventoryRepo) ImportInventoryFromJSON(_, _ string, _ []requests.InventoryImportRow) ([]string, []string, *responses.InternalResponse) {
 	return nil, nil, nil
 }
-func (m *mockInventoryRepo) ValidateImportRows(_ []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
+func (m *mockInventoryRepo) ValidateImportRows(_ string, _ []requests.InventoryImportRow) ([]responses.InventoryValidationResult, *responses.InternalResponse) {
 	return nil, nil
 }
-func (m *mockInventoryRepo) ExportInventoryToExcel() ([]byte, *responses.InternalResponse) { return nil, nil }
-func (m *mockInventoryRepo) GetInventoryLots(_ string) ([]responses.InventoryLot, *responses.InternalResponse) { return nil, nil }
-func (m *mockInventoryRepo) GetInventorySerials(_ string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) { return nil, nil }
-func (m *mockInventoryRepo) CreateInventoryLot(_ string, _ *requests.CreateInventoryLotRequest) *responses.InternalResponse { return nil }
-func (m *mockInventoryRepo) DeleteInventoryLot(_ string) *responses.InternalResponse { return nil }
-func (m *mockInventoryRepo) CreateInventorySerial(_ string, _ *requests.CreateInventorySerial) *responses.InternalResponse { return nil }
-func (m *mockInventoryRepo) DeleteInventorySerial(_ string) *responses.InternalResponse { return nil }
+func (m *mockInventoryRepo) ExportInventoryToExcel(_ string) ([]byte, *responses.InternalResponse) { return nil, nil }
+func (m *mockInventoryRepo) GetInventoryLots(_, _ string) ([]responses.InventoryLot, *responses.InternalResponse) { return nil, nil }
+func (m *mockInventoryRepo) GetInventorySerials(_, _ string) ([]responses.InventorySerialWithSerial, *responses.InternalResponse) { return nil, nil }
+func (m *mockInventoryRepo) CreateInventoryLot(_, _ string, _ *requests.CreateInventoryLotRequest) *responses.InternalResponse { return nil }
+func (m *mockInventoryRepo) DeleteInventoryLot(_, _ string) *responses.InternalResponse { return nil }
+func (m *mockInventoryRepo) CreateInventorySerial(_, _ string, _ *requests.CreateInventorySerial) *responses.InternalResponse { return nil }
+func (m *mockInventoryRepo) DeleteInventorySerial(_, _ string) *responses.InternalResponse { return nil }
 func (m *mockInventoryRepo) GenerateImportTemplate(_ string) ([]byte, error) { return nil, nil }
-func (m *mockInventoryRepo) GetValuation(_ string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+func (m *mockInventoryRepo) GetValuation(_, _ string) (*responses.InventoryValuationResponse, *responses.InternalResponse) {
+	return m.valuation, nil
+}
+func (m *mockInventoryRepo) ExportValuationToExcel(_, _ string) ([]byte, *responses.InternalResponse) {
 	return nil, nil
 }
 
@@ -62,7 +69,7 @@ func (m *mockInventoryRepo) GetValuation(_ string) (*responses.InventoryValuatio
 
 func TestInventoryService_GetAll_Empty(t *testing.T) {
 	svc := NewInventoryService(&mockInventoryRepo{}, nil)
-	list, err := svc.GetAllInventory()
+	list, err := svc.GetAllInventory("tenant-1")
 	assert.Nil(t, err)
 	assert.Empty(t, list)
 }
@@ -75,7 +82,7 @@ func TestInventoryService_GetAll_WithData(t *testing.T) {
 		},
 	}
 	svc := NewInventoryService(repo, nil)
-	list, err := svc.GetAllInventory()
+	list, err := svc.GetAllInventory("tenant-1")
 	require.Nil(t, err)
 	assert.Len(t, list, 2)
 	assert.Equal(t, "SKU-001", list[0].SKU)
@@ -88,14 +95,14 @@ func TestInventoryService_GetBySkuAndLocation_Found(t *testing.T) {
 		bySkuLoc: &dto.EnhancedInventory{SKU: "SKU-001", Location: "LOC-A01", Quantity: 10},
 	}
 	svc := NewInventoryService(repo, nil)
-	inv, err := svc.GetInventoryBySkuAndLocation("SKU-001", "LOC-A01")
+	inv, err := svc.GetInventoryBySkuAndLocation("tenant-1", "SKU-001", "LOC-A01")
 	require.Nil(t, err)
 	assert.Equal(t, "SKU-001", inv.SKU)
 }
 
 func TestInventoryService_GetBySkuAndLocation_NotFound(t *testing.T) {
 	svc := NewInventoryService(&mockInventoryRepo{}, nil)
-	inv, err := svc.GetInventoryBySkuAndLocation("MISSING", "LOC-X")
+	inv, err := svc.GetInventoryBySkuAndLocation("tenant-1", "MISSING", "LOC-X")
 	assert.Nil(t, err)    // mock returns nil,nil
 	assert.Nil(t, inv)
 }
@@ -104,7 +111,7 @@ func TestInventoryService_GetBySkuAndLocation_NotFound(t *testing.T) {
 
 func TestInventoryService_ImportJSON_Delegates(t *testing.T) {
 	svc := NewInventoryService(&mockInventoryRepo{}, nil)
-	imported, skipped, err := svc.ImportInventoryFromJSON("user1", []requests.InventoryImportRow{
+	imported, skipped, err := svc.ImportInventoryFromJSON("tenant-1", "user1", []requests.InventoryImportRow{
 		{SKU: "SKU-001", Location: "LOC-A01", Quantity: "10"},
 	})
 	assert.Nil(t, err)
@@ -114,7 +121,7 @@ func TestInventoryService_ImportJSON_Delegates(t *testing.T) {
 
 func TestInventoryService_ImportJSON_Empty(t *testing.T) {
 	svc := NewInventoryService(&mockInventoryRepo{}, nil)
-	imported, skipped, err := svc.ImportInventoryFromJSON("user1", []requests.InventoryImportRow{})
+	imported, skipped, err := svc.ImportInventoryFromJSON("tenant-1", "user1", []requests.InventoryImportRow{})
 	assert.Nil(t, err)
 	assert.Empty(t, imported)
 	assert.Empty(t, skipped)
@@ -124,7 +131,7 @@ func TestInventoryService_ImportJSON_Empty(t *testing.T) {
 
 func TestInventoryService_ValidateImportRows_Delegates(t *testing.T) {
 	svc := NewInventoryService(&mockInventoryRepo{}, nil)
-	results, err := svc.ValidateImportRows([]requests.InventoryImportRow{
+	results, err := svc.ValidateImportRows("tenant-1", []requests.InventoryImportRow{
 		{SKU: "SKU-X01", Location: "LOC-A01", Quantity: "5"},
 	})
 	assert.Nil(t, err)
@@ -136,7 +143,7 @@ func TestInventoryService_ValidateImportRows_Delegates(t *testing.T) {
 func TestInventoryService_GetValuation_DefaultsToArticle(t *testing.T) {
 	repo := &mockInventoryRepo{}
 	svc := NewInventoryService(repo, nil)
-	result, errResp := svc.GetValuation("")
+	result, errResp := svc.GetValuation("tenant-1", "", "")
 	require.Nil(t, errResp)
 	// mock returns nil, which is fine for this test
 	_ = result
@@ -146,7 +153,7 @@ func TestInventoryService_GetValuation_ValidGroupBy(t *testing.T) {
 	repo := &mockInventoryRepo{}
 	svc := NewInventoryService(repo, nil)
 	for _, gb := range []string{"article", "location", "category"} {
-		_, errResp := svc.GetValuation(gb)
+		_, errResp := svc.GetValuation("tenant-1", gb, "")
 		require.Nil(t, errResp, "group_by=%s", gb)
 	}
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// KitsService provides business logic for kit bills of materials and the assembly /
+// disassembly orders that build and break down kits.
+type KitsService struct {
+	Repository ports.KitsRepository
+}
+
+func NewKitsService(repo ports.KitsRepository) *KitsService {
+	return &KitsService{Repository: repo}
+}
+
+func (s *KitsService) ListKits(tenantID string) ([]responses.KitView, *responses.InternalResponse) {
+	return s.Repository.ListKits(tenantID)
+}
+
+func (s *KitsService) GetKit(tenantID, kitSKU string) (*responses.KitView, *responses.InternalResponse) {
+	return s.Repository.GetKit(tenantID, strings.TrimSpace(kitSKU))
+}
+
+// UpsertKit checks the components (no repeats, not the kit itself) and saves the kit.
+func (s *KitsService) UpsertKit(tenantID, kitSKU, userID string, req *requests.UpsertKitRequest) (*responses.KitView, *responses.InternalResponse) {
+	kitSKU = strings.TrimSpace(kitSKU)
+	req.Notes = trimmedOrNil(req.Notes)
+	if kitSKU == "" {
+		return nil, &responses.InternalResponse{Message: "Indique el SKU del kit", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if len(req.Components) == 0 {
+		return nil, &responses.InternalResponse{Message: "El kit debe tener al menos un componente", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	seen := make(map[string]bool, len(req.Components))
+	for i := range req.Components {
+		c := &req.Components[i]
+		c.SKU = strings.TrimSpace(c.SKU)
+		switch {
+		case c.SKU == "":
+			return nil, &responses.InternalResponse{Message: "Indique el SKU de cada componente", Handled: true, StatusCode: responses.StatusBadRequest}
+		case c.SKU == kitSKU:
+			return nil, &responses.InternalResponse{Message: "Un kit no puede ser componente de sí mismo", Handled: true, StatusCode: responses.StatusBadRequest}
+		case seen[c.SKU]:
+			return nil, &responses.InternalResponse{Message: fmt.Sprintf("El componente %s está repetido", c.SKU), Handled: true, StatusCode: responses.StatusBadRequest}
+		case c.Quantity <= 0:
+			return nil, &responses.InternalResponse{Message: fmt.Sprintf("La cantidad del componente %s debe ser mayor a cero", c.SKU), Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+		seen[c.SKU] = true
+	}
+	return s.Repository.UpsertKit(tenantID, kitSKU, userID, req)
+}
+
+func (s *KitsService) DeleteKit(tenantID, kitSKU string) *responses.InternalResponse {
+	return s.Repository.DeleteKit(tenantID, strings.TrimSpace(kitSKU))
+}
+
+func (s *KitsService) ListAssemblyOrders(tenantID string, status, orderType, kitSKU *string, limit, offset int) ([]database.AssemblyOrder, *responses.InternalResponse) {
+	return s.Repository.ListAssemblyOrders(tenantID, status, orderType, kitSKU, limit, offset)
+}
+
+func (s *KitsService) GetAssemblyOrder(id, tenantID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return s.Repository.GetAssemblyOrder(id, tenantID)
+}
+
+// CreateAssemblyOrder normalizes the order and creates it as a draft.
+func (s *KitsService) CreateAssemblyOrder(tenantID, userID string, req *requests.CreateAssemblyOrderRequest) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	req.KitSKU = strings.TrimSpace(req.KitSKU)
+	req.Location = strings.TrimSpace(req.Location)
+	req.KitLotNumber = trimmedOrNil(req.KitLotNumber)
+	req.Notes = trimmedOrNil(req.Notes)
+	if req.OrderType != database.AssemblyOrderAssembly && req.OrderType != database.AssemblyOrderDisassembly {
+		return nil, &responses.InternalResponse{Message: "Tipo de orden inválido: use assembly o disassembly", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if req.KitSKU == "" || req.Location == "" {
+		return nil, &responses.InternalResponse{Message: "Indique el kit y la ubicación de la orden", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if req.Quantity <= 0 {
+		return nil, &responses.InternalResponse{Message: "La cantidad de kits debe ser mayor a cero", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	seen := make(map[string]bool, len(req.ComponentLots))
+	for i := range req.ComponentLots {
+		cl := &req.ComponentLots[i]
+		cl.SKU = strings.TrimSpace(cl.SKU)
+		cl.LotNumber = strings.TrimSpace(cl.LotNumber)
+		if cl.SKU == "" || cl.LotNumber == "" {
+			return nil, &responses.InternalResponse{Message: "Indique el SKU y el lote de cada componente", Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+		if seen[cl.SKU] {
+			return nil, &responses.InternalResponse{Message: fmt.Sprintf("El componente %s está repetido en component_lots", cl.SKU), Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+		seen[cl.SKU] = true
+	}
+	return s.Repository.CreateAssemblyOrder(tenantID, userID, req)
+}
+
+func (s *KitsService) CompleteAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return s.Repository.CompleteAssemblyOrder(id, tenantID, userID)
+}
+
+func (s *KitsService) CancelAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return s.Repository.CancelAssemblyOrder(id, tenantID, userID)
+}
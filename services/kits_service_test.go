@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockKitsRepo struct {
+	upsertSKU string
+	upserted  *requests.UpsertKitRequest
+	created   *requests.CreateAssemblyOrderRequest
+}
+
+func (m *mockKitsRepo) ListKits(tenantID string) ([]responses.KitView, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockKitsRepo) GetKit(tenantID, kitSKU string) (*responses.KitView, *responses.InternalResponse) {
+	return &responses.KitView{Kit: database.Kit{ID: "k1", KitSKU: kitSKU}}, nil
+}
+func (m *mockKitsRepo) UpsertKit(tenantID, kitSKU, userID string, req *requests.UpsertKitRequest) (*responses.KitView, *responses.InternalResponse) {
+	m.upsertSKU = kitSKU
+	m.upserted = req
+	return &responses.KitView{Kit: database.Kit{ID: "k1", KitSKU: kitSKU}}, nil
+}
+func (m *mockKitsRepo) DeleteKit(tenantID, kitSKU string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockKitsRepo) ListAssemblyOrders(tenantID string, status, orderType, kitSKU *string, limit, offset int) ([]database.AssemblyOrder, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockKitsRepo) GetAssemblyOrder(id, tenantID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id}}, nil
+}
+func (m *mockKitsRepo) CreateAssemblyOrder(tenantID, userID string, req *requests.CreateAssemblyOrderRequest) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	m.created = req
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: "a1", OrderNumber: "ASM-2026-0001"}}, nil
+}
+func (m *mockKitsRepo) CompleteAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id, Status: database.AssemblyOrderCompleted}}, nil
+}
+func (m *mockKitsRepo) CancelAssemblyOrder(id, tenantID, userID string) (*responses.AssemblyOrderView, *responses.InternalResponse) {
+	return &responses.AssemblyOrderView{AssemblyOrder: database.AssemblyOrder{ID: id, Status: database.AssemblyOrderCancelled}}, nil
+}
+
+func TestKitsService_UpsertKit_Validation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		sku        string
+		components []requests.KitComponentRequest
+	}{
+		"blank kit":       {" ", []requests.KitComponentRequest{{SKU: "GAUZE", Quantity: 1}}},
+		"no components":   {"KIT", nil},
+		"kit in itself":   {"KIT", []requests.KitComponentRequest{{SKU: " KIT ", Quantity: 1}}},
+		"repeated":        {"KIT", []requests.KitComponentRequest{{SKU: "GAUZE", Quantity: 1}, {SKU: "GAUZE ", Quantity: 2}}},
+		"zero quantity":   {"KIT", []requests.KitComponentRequest{{SKU: "GAUZE", Quantity: 0}}},
+		"blank component": {"KIT", []requests.KitComponentRequest{{SKU: "  ", Quantity: 1}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockKitsRepo{}
+			_, resp := NewKitsService(repo).UpsertKit(testTenantA, tc.sku, "user-1", &requests.UpsertKitRequest{Components: tc.components})
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.upserted)
+		})
+	}
+}
+
+func TestKitsService_UpsertKit_Trims(t *testing.T) {
+	repo := &mockKitsRepo{}
+	notes := "  "
+	_, resp := NewKitsService(repo).UpsertKit(testTenantA, " KIT-FA ", "user-1", &requests.UpsertKitRequest{
+		ExplodeOnPick: true,
+		Notes:         &notes,
+		Components:    []requests.KitComponentRequest{{SKU: " GAUZE ", Quantity: 2}, {SKU: "BANDAGE", Quantity: 1}},
+	})
+	require.Nil(t, resp)
+	assert.Equal(t, "KIT-FA", repo.upsertSKU)
+	assert.Equal(t, "GAUZE", repo.upserted.Components[0].SKU)
+	assert.Nil(t, repo.upserted.Notes)
+	assert.True(t, repo.upserted.ExplodeOnPick)
+}
+
+func TestKitsService_CreateAssemblyOrder_Validation(t *testing.T) {
+	for name, req := range map[string]*requests.CreateAssemblyOrderRequest{
+		"bad type":      {OrderType: "repack", KitSKU: "KIT", Quantity: 1, Location: "A-01"},
+		"blank kit":     {OrderType: "assembly", KitSKU: " ", Quantity: 1, Location: "A-01"},
+		"blank loc":     {OrderType: "assembly", KitSKU: "KIT", Quantity: 1, Location: " "},
+		"zero quantity": {OrderType: "assembly", KitSKU: "KIT", Quantity: 0, Location: "A-01"},
+		"repeated lot": {OrderType: "disassembly", KitSKU: "KIT", Quantity: 1, Location: "A-01", ComponentLots: []requests.AssemblyComponentLotRequest{
+			{SKU: "GAUZE", LotNumber: "L1"}, {SKU: " GAUZE", LotNumber: "L2"},
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockKitsRepo{}
+			_, resp := NewKitsService(repo).CreateAssemblyOrder(testTenantA, "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestKitsService_CreateAssemblyOrder_Normalizes(t *testing.T) {
+	repo := &mockKitsRepo{}
+	lot := " "
+	view, resp := NewKitsService(repo).CreateAssemblyOrder(testTenantA, "user-1", &requests.CreateAssemblyOrderRequest{
+		OrderType: database.AssemblyOrderDisassembly, KitSKU: " KIT-FA ", Quantity: 2, Location: " ASM-01 ", KitLotNumber: &lot,
+		ComponentLots: []requests.AssemblyComponentLotRequest{{SKU: " GAUZE ", LotNumber: " L-7 "}},
+	})
+	require.Nil(t, resp)
+	assert.Equal(t, "ASM-2026-0001", view.OrderNumber)
+	assert.Equal(t, "KIT-FA", repo.created.KitSKU)
+	assert.Equal(t, "ASM-01", repo.created.Location)
+	assert.Nil(t, repo.created.KitLotNumber, "a blank kit lot means FEFO")
+	assert.Equal(t, requests.AssemblyComponentLotRequest{SKU: "GAUZE", LotNumber: "L-7"}, repo.created.ComponentLots[0])
+}
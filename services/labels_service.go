@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Label output formats and symbologies.
+const (
+	LabelFormatPDF = "pdf"
+	LabelFormatZPL = "zpl"
+
+	SymbologyCode128 = "code128"
+	SymbologyGS1128  = "gs1-128"
+	SymbologyQR      = "qr"
+)
+
+// maxLabelsPerDocument caps labels × copies in a single render.
+const maxLabelsPerDocument = 1000
+
+// LabelsService renders barcode labels as PDF (one label per page) or raw ZPL for thermal printers.
+type LabelsService struct {
+	Repository ports.LabelsRepository
+}
+
+func NewLabelsService(repo ports.LabelsRepository) *LabelsService {
+	return &LabelsService{Repository: repo}
+}
+
+// LabelDocument is a rendered label file ready to stream to the client.
+type LabelDocument struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+// RenderLabels renders one label per requested id (times copies) for a single kind.
+func (s *LabelsService) RenderLabels(tenantID string, req *requests.LabelBatchRequest) (*LabelDocument, *responses.InternalResponse) {
+	items, resp := s.Repository.GetLabelItems(tenantID, req.Kind, req.IDs)
+	if resp != nil {
+		return nil, resp
+	}
+	return renderLabelDocument(items, req.Format, req.Symbology, req.Copies, "labels-"+req.Kind)
+}
+
+// RenderReceivingTaskLabels renders every label for a completed receiving task.
+func (s *LabelsService) RenderReceivingTaskLabels(tenantID, taskID, format, symbology string, copies int) (*LabelDocument, *responses.InternalResponse) {
+	items, resp := s.Repository.GetReceivingTaskLabelItems(tenantID, taskID)
+	if resp != nil {
+		return nil, resp
+	}
+	if len(items) == 0 {
+		return nil, &responses.InternalResponse{
+			Message:    "La tarea de recepción no tiene artículos para etiquetar",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return renderLabelDocument(items, format, symbology, copies, "labels-receiving-"+taskID)
+}
+
+func renderLabelDocument(items []responses.LabelItem, format, symbology string, copies int, baseName string) (*LabelDocument, *responses.InternalResponse) {
+	if copies <= 0 {
+		copies = 1
+	}
+	if len(items)*copies > maxLabelsPerDocument {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Máximo %d etiquetas por documento (solicitadas: %d)", maxLabelsPerDocument, len(items)*copies),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	specs := make([]labelSpec, 0, len(items))
+	for _, item := range items {
+		spec, err := buildLabelSpec(item, symbology)
+		if err != nil {
+			return nil, &responses.InternalResponse{
+				Error:      err,
+				Message:    err.Error(),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		specs = append(specs, spec)
+	}
+
+	if format == LabelFormatZPL {
+		return &LabelDocument{
+			Data:        renderLabelsZPL(specs, copies),
+			ContentType: "application/zpl; charset=utf-8",
+			Filename:    baseName + ".zpl",
+		}, nil
+	}
+	data, err := renderLabelsPDF(specs, copies)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:      err,
+			Message:    err.Error(),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return &LabelDocument{Data: data, ContentType: "application/pdf", Filename: baseName + ".pdf"}, nil
+}
+
+// labelSpec is a label resolved to printable text and a barcode payload.
+type labelSpec struct {
+	Title     string
+	Lines     []string
+	Symbology string
+	// Payload is the encoded data: the plain code for code128, the GS1 elements for gs1-128,
+	// and either for qr (GS1 elements separated by GS when the item has them).
+	Payload string
+	GS1     []tools.GS1Element
+	// HumanReadable is printed under the symbol.
+	HumanReadable string
+}
+
+// defaultSymbology: lots and serials carry GS1 data (expiry, lot, serial); locations and
+// articles a plain Code128 of their code.
+func defaultSymbology(kind string) string {
+	if kind == responses.LabelKindLot || kind == responses.LabelKindSerial {
+		return SymbologyGS1128
+	}
+	return SymbologyCode128
+}
+
+// labelGS1Elements maps an item to GS1 AIs; locations have none (no GLN is kept).
+func labelGS1Elements(item responses.LabelItem) []tools.GS1Element {
+	switch item.Kind {
+	case responses.LabelKindArticle:
+		return []tools.GS1Element{{AI: tools.GS1AIProductID, Value: item.SKU}}
+	case responses.LabelKindLot:
+		elements := []tools.GS1Element{{AI: tools.GS1AILot, Value: item.Code}, {AI: tools.GS1AIProductID, Value: item.SKU}}
+		if item.ExpirationDate != nil {
+			elements = append(elements, tools.GS1Element{AI: tools.GS1AIExpiry, Value: item.ExpirationDate.Format("060102")})
+		}
+		return elements
+	case responses.LabelKindSerial:
+		return []tools.GS1Element{{AI: tools.GS1AISerial, Value: item.Code}, {AI: tools.GS1AIProductID, Value: item.SKU}}
+	}
+	return nil
+}
+
+func validGS1Elements(elements []tools.GS1Element) error {
+	for _, e := range elements {
+		if err := tools.ValidateGS1Element(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildLabelSpec(item responses.LabelItem, symbology string) (labelSpec, error) {
+	if symbology == "" {
+		symbology = defaultSymbology(item.Kind)
+	}
+	spec := labelSpec{Symbology: symbology, Payload: item.Code, HumanReadable: item.Code}
+
+	switch item.Kind {
+	case responses.LabelKindLocation:
+		spec.Title = item.Code
+		if item.Name != "" {
+			spec.Lines = append(spec.Lines, item.Name)
+		}
+		if item.Zone != "" {
+			spec.Lines = append(spec.Lines, "Zone: "+item.Zone)
+		}
+	default:
+		spec.Title = item.Name
+		if spec.Title == "" {
+			spec.Title = item.SKU
+		}
+		spec.Lines = append(spec.Lines, "SKU: "+item.SKU)
+		if item.Kind == responses.LabelKindLot {
+			spec.Lines = append(spec.Lines, "Lot: "+item.Code)
+			if item.ExpirationDate != nil {
+				spec.Lines = append(spec.Lines, "Exp: "+item.ExpirationDate.Format("2006-01-02"))
+			}
+		}
+		if item.Kind == responses.LabelKindSerial {
+			spec.Lines = append(spec.Lines, "S/N: "+item.Code)
+		}
+	}
+
+	elements := labelGS1Elements(item)
+	switch symbology {
+	case SymbologyGS1128:
+		if len(elements) == 0 {
+			return labelSpec{}, fmt.Errorf("GS1-128 is not available for %s labels; use code128 or qr", item.Kind)
+		}
+		if err := validGS1Elements(elements); err != nil {
+			return labelSpec{}, fmt.Errorf("%s %s: %w", item.Kind, item.Code, err)
+		}
+		spec.GS1 = elements
+		spec.Payload = tools.GS1ElementString(elements, string(code128.FNC1))
+		spec.HumanReadable = tools.GS1HumanReadable(elements)
+	case SymbologyQR:
+		// QR carries the GS1 element string when the data fits the AIs; otherwise the plain code
+		// (e.g. a lot number longer than AI 10 allows), which scanners resolve the same way.
+		if len(elements) > 0 && validGS1Elements(elements) == nil {
+			spec.GS1 = elements
+			spec.Payload = tools.GS1ElementString(elements, tools.GS1GroupSeparator)
+			spec.HumanReadable = tools.GS1HumanReadable(elements)
+		}
+	case SymbologyCode128:
+	default:
+		return labelSpec{}, fmt.Errorf("unsupported symbology %q", symbology)
+	}
+	if spec.Payload == "" {
+		return labelSpec{}, fmt.Errorf("%s label has no code to encode", item.Kind)
+	}
+	return spec, nil
+}
+
+// encodeLabelBarcode encodes the payload; GS1-128 is Code128 starting with FNC1.
+func encodeLabelBarcode(spec labelSpec) (barcode.Barcode, error) {
+	switch spec.Symbology {
+	case SymbologyQR:
+		return qr.Encode(spec.Payload, qr.M, qr.Auto)
+	case SymbologyGS1128:
+		return code128.Encode(string(code128.FNC1) + spec.Payload)
+	default:
+		return code128.Encode(spec.Payload)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PDF — 100 × 60 mm pages, symbols drawn as vector rectangles (no raster scaling)
+// ─────────────────────────────────────────────────────────────────────────────
+
+const (
+	labelWidthMM  = 100.0
+	labelHeightMM = 60.0
+	labelMarginMM = 5.0
+)
+
+func renderLabelsPDF(specs []labelSpec, copies int) ([]byte, error) {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "L",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: labelWidthMM, Ht: labelHeightMM},
+	})
+	pdf.SetMargins(labelMarginMM, labelMarginMM, labelMarginMM)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	contentW := labelWidthMM - 2*labelMarginMM
+
+	for _, spec := range specs {
+		bc, err := encodeLabelBarcode(spec)
+		if err != nil {
+			return nil, fmt.Errorf("encode %q: %w", spec.HumanReadable, err)
+		}
+		for c := 0; c < copies; c++ {
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.SetXY(labelMarginMM, labelMarginMM)
+			pdf.CellFormat(contentW, 6, tr(spec.Title), "", 1, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 9)
+			for _, line := range spec.Lines {
+				pdf.SetX(labelMarginMM)
+				pdf.CellFormat(contentW, 4.5, tr(line), "", 1, "L", false, 0, "")
+			}
+
+			if spec.Symbology == SymbologyQR {
+				size := 30.0
+				drawBarcode(pdf, bc, labelWidthMM-labelMarginMM-size, labelHeightMM-labelMarginMM-size, size, size)
+				pdf.SetFont("Helvetica", "", 7)
+				pdf.SetXY(labelMarginMM, labelHeightMM-labelMarginMM-4)
+				pdf.CellFormat(contentW-size-2, 4, tr(spec.HumanReadable), "", 0, "L", false, 0, "")
+				continue
+			}
+			// Module width capped at 0.5 mm; quiet zones come from the page margins.
+			modules := float64(bc.Bounds().Dx())
+			moduleW := contentW / modules
+			if moduleW > 0.5 {
+				moduleW = 0.5
+			}
+			drawBarcode(pdf, bc, labelMarginMM, 30, modules*moduleW, 18)
+			pdf.SetFont("Helvetica", "", 8)
+			pdf.SetXY(labelMarginMM, 49)
+			pdf.CellFormat(contentW, 4, tr(spec.HumanReadable), "", 0, "L", false, 0, "")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("pdf output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawBarcode paints the dark modules of bc into the w × h box at (x, y). 1D symbols are
+// one module high and are stretched vertically; runs of dark modules become one rectangle.
+func drawBarcode(pdf *gofpdf.Fpdf, bc barcode.Barcode, x, y, w, h float64) {
+	bounds := bc.Bounds()
+	cols, rows := bounds.Dx(), bounds.Dy()
+	moduleW := w / float64(cols)
+	moduleH := h / float64(rows)
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; {
+			if !isDarkModule(bc, bounds.Min.X+col, bounds.Min.Y+row) {
+				col++
+				continue
+			}
+			start := col
+			for col < cols && isDarkModule(bc, bounds.Min.X+col, bounds.Min.Y+row) {
+				col++
+			}
+			pdf.Rect(x+float64(start)*moduleW, y+float64(row)*moduleH, float64(col-start)*moduleW, moduleH, "F")
+		}
+	}
+}
+
+func isDarkModule(bc barcode.Barcode, x, y int) bool {
+	r, g, b, _ := bc.At(x, y).RGBA()
+	return r+g+b < 3*0x8000
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// ZPL — 4 × 2.4 in labels at 203 dpi; the printer renders the symbols natively
+// ─────────────────────────────────────────────────────────────────────────────
+
+func renderLabelsZPL(specs []labelSpec, copies int) []byte {
+	var b strings.Builder
+	for _, spec := range specs {
+		b.WriteString("^XA\n^CI28\n^PW812\n^LL480\n")
+		fmt.Fprintf(&b, "^FO30,25^A0N,36,36^FB750,1,0,L^FH^FD%s^FS\n", zplEscape(spec.Title))
+		y := 70
+		for _, line := range spec.Lines {
+			fmt.Fprintf(&b, "^FO30,%d^A0N,26,26^FH^FD%s^FS\n", y, zplEscape(line))
+			y += 32
+		}
+		switch spec.Symbology {
+		case SymbologyQR:
+			fmt.Fprintf(&b, "^FO560,200^BQN,2,6^FH^FDMA,%s^FS\n", zplEscape(spec.Payload))
+			fmt.Fprintf(&b, "^FO30,430^A0N,22,22^FH^FD%s^FS\n", zplEscape(spec.HumanReadable))
+		case SymbologyGS1128:
+			// Mode D (GS1): the printer inserts FNC1 from the parenthesised AIs.
+			fmt.Fprintf(&b, "^FO30,220^BY2^BCN,150,Y,N,N,D^FH^FD%s^FS\n", zplEscape(spec.HumanReadable))
+		default:
+			fmt.Fprintf(&b, "^FO30,220^BY2^BCN,150,Y,N,N^FH^FD%s^FS\n", zplEscape(spec.Payload))
+		}
+		if copies > 1 {
+			fmt.Fprintf(&b, "^PQ%d\n", copies)
+		}
+		b.WriteString("^XZ\n")
+	}
+	return []byte(b.String())
+}
+
+var zplEscaper = strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E", tools.GS1GroupSeparator, "_1D")
+
+// zplEscape hex-encodes the characters ZPL would read as commands (^FH uses _ as escape).
+func zplEscape(s string) string {
+	return zplEscaper.Replace(s)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLabelsRepo struct {
+	items    []responses.LabelItem
+	err      *responses.InternalResponse
+	tenantID string
+	kind     string
+}
+
+func (m *mockLabelsRepo) GetLabelItems(tenantID, kind string, _ []string) ([]responses.LabelItem, *responses.InternalResponse) {
+	m.tenantID, m.kind = tenantID, kind
+	return m.items, m.err
+}
+
+func (m *mockLabelsRepo) GetReceivingTaskLabelItems(tenantID, _ string) ([]responses.LabelItem, *responses.InternalResponse) {
+	m.tenantID = tenantID
+	return m.items, m.err
+}
+
+func labelLot(code string) responses.LabelItem {
+	exp := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	return responses.LabelItem{Kind: responses.LabelKindLot, ID: "lot-1", Code: code, SKU: "SKU-1", Name: "Ibuprofeno 400", ExpirationDate: &exp}
+}
+
+func TestLabelsService_RenderLabels_PDF(t *testing.T) {
+	repo := &mockLabelsRepo{items: []responses.LabelItem{
+		labelLot("L-001"),
+		{Kind: responses.LabelKindLot, ID: "lot-2", Code: "L-002", SKU: "SKU-1", Name: "Ácido fólico"},
+	}}
+	svc := NewLabelsService(repo)
+	doc, resp := svc.RenderLabels("tenant-1", &requests.LabelBatchRequest{Kind: responses.LabelKindLot, IDs: []string{"lot-1", "lot-2"}, Copies: 2})
+	require.Nil(t, resp)
+	assert.Equal(t, "tenant-1", repo.tenantID)
+	assert.Equal(t, "application/pdf", doc.ContentType)
+	assert.Equal(t, "labels-lot.pdf", doc.Filename)
+	assert.True(t, bytes.HasPrefix(doc.Data, []byte("%PDF")))
+	assert.Equal(t, 4, bytes.Count(doc.Data, []byte("/Type /Page\n")), "two labels × two copies")
+}
+
+func TestLabelsService_RenderLabels_ZPL(t *testing.T) {
+	repo := &mockLabelsRepo{items: []responses.LabelItem{labelLot("L_001")}}
+	svc := NewLabelsService(repo)
+	doc, resp := svc.RenderLabels("tenant-1", &requests.LabelBatchRequest{Kind: responses.LabelKindLot, IDs: []string{"lot-1"}, Format: LabelFormatZPL, Copies: 3})
+	require.Nil(t, resp)
+	zpl := string(doc.Data)
+	assert.Equal(t, "labels-lot.zpl", doc.Filename)
+	assert.True(t, strings.HasPrefix(zpl, "^XA"))
+	assert.Contains(t, zpl, "^BCN,150,Y,N,N,D^FH^FD(17)261231(10)L_5F001(240)SKU-1^FS", "GS1-128 mode D with escaped underscore")
+	assert.Contains(t, zpl, "^PQ3")
+	assert.True(t, strings.HasSuffix(zpl, "^XZ\n"))
+}
+
+func TestLabelsService_RenderLabels_QRAndCode128ZPL(t *testing.T) {
+	repo := &mockLabelsRepo{items: []responses.LabelItem{
+		{Kind: responses.LabelKindSerial, ID: "s-1", Code: "SN-9", SKU: "SKU-1", Name: "Monitor"},
+	}}
+	svc := NewLabelsService(repo)
+	doc, resp := svc.RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindSerial, IDs: []string{"s-1"}, Format: LabelFormatZPL, Symbology: SymbologyQR})
+	require.Nil(t, resp)
+	assert.Contains(t, string(doc.Data), "^BQN,2,6^FH^FDMA,21SN-9_1D240SKU-1^FS", "GS separator hex-escaped in QR data")
+	assert.NotContains(t, string(doc.Data), "^PQ")
+
+	repo.items = []responses.LabelItem{{Kind: responses.LabelKindLocation, ID: "loc-1", Code: "A-01-02", Name: "Rack A"}}
+	doc, resp = svc.RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLocation, IDs: []string{"loc-1"}, Format: LabelFormatZPL})
+	require.Nil(t, resp)
+	assert.Contains(t, string(doc.Data), "^BCN,150,Y,N,N^FH^FDA-01-02^FS")
+}
+
+func TestLabelsService_RenderLabels_QRPDF(t *testing.T) {
+	repo := &mockLabelsRepo{items: []responses.LabelItem{{Kind: responses.LabelKindLocation, ID: "loc-1", Code: "A-01-02"}}}
+	doc, resp := NewLabelsService(repo).RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLocation, IDs: []string{"loc-1"}, Symbology: SymbologyQR})
+	require.Nil(t, resp)
+	assert.True(t, bytes.HasPrefix(doc.Data, []byte("%PDF")))
+}
+
+func TestLabelsService_RenderLabels_Errors(t *testing.T) {
+	t.Run("gs1-128 not available for locations", func(t *testing.T) {
+		repo := &mockLabelsRepo{items: []responses.LabelItem{{Kind: responses.LabelKindLocation, ID: "loc-1", Code: "A-01"}}}
+		_, resp := NewLabelsService(repo).RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLocation, IDs: []string{"loc-1"}, Symbology: SymbologyGS1128})
+		require.NotNil(t, resp)
+		assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("lot number too long for AI 10", func(t *testing.T) {
+		repo := &mockLabelsRepo{items: []responses.LabelItem{labelLot("LOT-NUMBER-LONGER-THAN-20")}}
+		_, resp := NewLabelsService(repo).RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLot, IDs: []string{"lot-1"}})
+		require.NotNil(t, resp)
+		assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.Message, "(10)")
+
+		// code128 and qr still print it as a plain code.
+		_, resp = NewLabelsService(repo).RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLot, IDs: []string{"lot-1"}, Symbology: SymbologyQR})
+		assert.Nil(t, resp)
+	})
+	t.Run("too many labels", func(t *testing.T) {
+		items := make([]responses.LabelItem, 20)
+		for i := range items {
+			items[i] = responses.LabelItem{Kind: responses.LabelKindLocation, Code: "A"}
+		}
+		_, resp := NewLabelsService(&mockLabelsRepo{items: items}).RenderLabels("t", &requests.LabelBatchRequest{Kind: responses.LabelKindLocation, Copies: 100})
+		require.NotNil(t, resp)
+		assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("repository error is passed through", func(t *testing.T) {
+		repo := &mockLabelsRepo{err: &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}}
+		_, resp := NewLabelsService(repo).RenderReceivingTaskLabels("t", "rt-1", "", "", 0)
+		require.NotNil(t, resp)
+		assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"strings"
+
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 )
 
 // LocationsService is a thin pass-through to the tenant-aware repository.
@@ -58,3 +61,46 @@ func (s *LocationsService) ExportLocationsToExcel(tenantID string) ([]byte, *res
 func (s *LocationsService) GenerateImportTemplate(language string) ([]byte, error) {
 	return s.Repository.GenerateImportTemplate(language)
 }
+
+// GetLocationUtilization reports how full each location is against its effective limits.
+// zone narrows the report to one zone (case-insensitive); overOnly keeps only locations
+// that are over any of their limits.
+func (s *LocationsService) GetLocationUtilization(tenantID, zone string, overOnly bool) ([]responses.LocationUtilization, *responses.InternalResponse) {
+	rows, resp := s.Repository.GetLocationUtilization(tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	out := make([]responses.LocationUtilization, 0, len(rows))
+	for _, row := range rows {
+		if zone != "" && (row.Zone == nil || !strings.EqualFold(*row.Zone, zone)) {
+			continue
+		}
+		fillUtilization(&row)
+		if overOnly && !row.OverCapacity {
+			continue
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// fillUtilization sets the per-dimension percentages, the overall (highest) percentage and
+// the over-capacity flag of a report row.
+func fillUtilization(row *responses.LocationUtilization) {
+	row.UnitsPct = tools.CapacityPct(row.UsedUnits, row.MaxUnits)
+	row.WeightPct = tools.CapacityPct(row.UsedWeightKg, row.MaxWeightKg)
+	row.VolumePct = tools.CapacityPct(row.UsedVolumeM3, row.MaxVolumeM3)
+	row.UtilizationPct = nil
+	row.OverCapacity = false
+	for _, pct := range []*float64{row.UnitsPct, row.WeightPct, row.VolumePct} {
+		if pct == nil {
+			continue
+		}
+		if row.UtilizationPct == nil || *pct > *row.UtilizationPct {
+			row.UtilizationPct = pct
+		}
+		if *pct > 100 {
+			row.OverCapacity = true
+		}
+	}
+}
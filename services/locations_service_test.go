@@ -21,6 +21,7 @@ type mockLocationsRepo struct {
 	byID         map[string]*database.Location
 	createErr    *responses.InternalResponse
 	deleteErr    *responses.InternalResponse
+	utilization  []responses.LocationUtilization
 	gotTenantIDs []string // captures every tenantID passed to any method
 }
 
@@ -90,6 +91,11 @@ func (m *mockLocationsRepo) GenerateImportTemplate(language string) ([]byte, err
 	return nil, nil
 }
 
+func (m *mockLocationsRepo) GetLocationUtilization(tenantID string) ([]responses.LocationUtilization, *responses.InternalResponse) {
+	m.recordTenant(tenantID)
+	return m.utilization, nil
+}
+
 // ── GetAllLocations ───────────────────────────────────────────────────────────
 
 func TestLocationsService_GetAll_Empty(t *testing.T) {
@@ -255,3 +261,52 @@ func TestLocationsService_ValidateImportRows_Delegates(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, results)
 }
+
+// ── GetLocationUtilization ────────────────────────────────────────────────────
+
+func TestLocationsService_GetLocationUtilization_ComputesPercentages(t *testing.T) {
+	zoneA, zoneB := "A", "B"
+	repo := &mockLocationsRepo{utilization: []responses.LocationUtilization{
+		{LocationCode: "A-01", Zone: &zoneA, MaxUnits: floatPtr(100), MaxWeightKg: floatPtr(200), UsedUnits: 50, UsedWeightKg: 250},
+		{LocationCode: "A-02", Zone: &zoneA, UsedUnits: 10},
+		{LocationCode: "B-01", Zone: &zoneB, MaxUnits: floatPtr(10), UsedUnits: 5},
+	}}
+	svc := NewLocationsService(repo)
+
+	rows, err := svc.GetLocationUtilization(testTenantA, "", false)
+	require.Nil(t, err)
+	require.Len(t, rows, 3)
+
+	require.NotNil(t, rows[0].UnitsPct)
+	assert.Equal(t, 50.0, *rows[0].UnitsPct)
+	require.NotNil(t, rows[0].UtilizationPct)
+	assert.Equal(t, 125.0, *rows[0].UtilizationPct)
+	assert.True(t, rows[0].OverCapacity)
+
+	// No limits: no percentages, never over capacity.
+	assert.Nil(t, rows[1].UtilizationPct)
+	assert.False(t, rows[1].OverCapacity)
+	assert.Equal(t, []string{testTenantA}, repo.gotTenantIDs)
+}
+
+func TestLocationsService_GetLocationUtilization_Filters(t *testing.T) {
+	zoneA, zoneB := "A", "B"
+	repo := &mockLocationsRepo{utilization: []responses.LocationUtilization{
+		{LocationCode: "A-01", Zone: &zoneA, MaxUnits: floatPtr(10), UsedUnits: 20},
+		{LocationCode: "A-02", Zone: &zoneA, MaxUnits: floatPtr(10), UsedUnits: 5},
+		{LocationCode: "B-01", Zone: &zoneB, MaxUnits: floatPtr(10), UsedUnits: 20},
+		{LocationCode: "NZ-01", UsedUnits: 1},
+	}}
+	svc := NewLocationsService(repo)
+
+	rows, err := svc.GetLocationUtilization(testTenantA, "a", false)
+	require.Nil(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "A-01", rows[0].LocationCode)
+
+	rows, err = svc.GetLocationUtilization(testTenantA, "", true)
+	require.Nil(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "A-01", rows[0].LocationCode)
+	assert.Equal(t, "B-01", rows[1].LocationCode)
+}
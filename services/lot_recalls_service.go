@@ -0,0 +1,367 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/rs/zerolog/log"
+	"github.com/xuri/excelize/v2"
+)
+
+// LotRecallsService provides business logic for lot recalls: freezing a recalled lot, tracking
+// the retrieval of its stock and the regulator report. Opening a recall notifies the tenant's
+// admins through Notifications when set.
+type LotRecallsService struct {
+	Repository    ports.LotRecallsRepository
+	Notifications *NotificationsService
+}
+
+// Recall report formats.
+const (
+	RecallReportPDF   = "pdf"
+	RecallReportExcel = "xlsx"
+)
+
+func NewLotRecallsService(repo ports.LotRecallsRepository) *LotRecallsService {
+	return &LotRecallsService{Repository: repo}
+}
+
+func (s *LotRecallsService) ListRecalls(tenantID string, status, sku *string, limit, offset int) ([]database.LotRecall, *responses.InternalResponse) {
+	return s.Repository.ListRecalls(tenantID, status, sku, limit, offset)
+}
+
+func (s *LotRecallsService) GetRecall(id, tenantID string) (*responses.LotRecallView, *responses.InternalResponse) {
+	return s.Repository.GetRecall(id, tenantID)
+}
+
+// CreateRecall opens the recall and notifies the tenant admins.
+func (s *LotRecallsService) CreateRecall(tenantID, userID string, req *requests.CreateLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	req.LotID = strings.TrimSpace(req.LotID)
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.ReturnLocation = trimmedOrNil(req.ReturnLocation)
+	req.Notes = trimmedOrNil(req.Notes)
+	if req.LotID == "" {
+		return nil, &responses.InternalResponse{Message: "Indique el lote a retirar", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if req.Reason == "" {
+		return nil, &responses.InternalResponse{Message: "El motivo del retiro es obligatorio", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+
+	view, resp := s.Repository.CreateRecall(tenantID, userID, req)
+	if resp != nil {
+		return nil, resp
+	}
+	s.notifyRecall(tenantID, view)
+	return view, nil
+}
+
+func (s *LotRecallsService) CompleteTask(id, tenantID, taskID, userID string, req *requests.CompleteRecallTaskRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.CompleteTask(id, tenantID, taskID, userID, req)
+}
+
+func (s *LotRecallsService) CloseRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.CloseRecall(id, tenantID, userID, req)
+}
+
+func (s *LotRecallsService) CancelRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.CancelRecall(id, tenantID, userID, req)
+}
+
+// GetReport renders the recall report in format (pdf or xlsx) and returns it with its file name
+// and content type.
+func (s *LotRecallsService) GetReport(id, tenantID, format string) ([]byte, string, string, *responses.InternalResponse) {
+	if format != RecallReportPDF && format != RecallReportExcel {
+		return nil, "", "", &responses.InternalResponse{Message: "Formato de reporte inválido: use pdf o xlsx", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	view, resp := s.Repository.GetRecall(id, tenantID)
+	if resp != nil {
+		return nil, "", "", resp
+	}
+	if format == RecallReportExcel {
+		data, err := buildLotRecallExcel(view)
+		if err != nil {
+			return nil, "", "", &responses.InternalResponse{Error: err, Message: "Error al generar el Excel del retiro"}
+		}
+		return data, view.RecallNumber + ".xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+	}
+	data, err := buildLotRecallPDF(view)
+	if err != nil {
+		return nil, "", "", &responses.InternalResponse{Error: err, Message: "Error al generar el PDF del retiro"}
+	}
+	return data, view.RecallNumber + ".pdf", "application/pdf", nil
+}
+
+// notifyRecall tells every admin of the tenant that a lot was recalled (failures are logged).
+func (s *LotRecallsService) notifyRecall(tenantID string, view *responses.LotRecallView) {
+	if s.Notifications == nil {
+		return
+	}
+	adminIDs, resp := s.Repository.TenantAdminIDs(tenantID)
+	if resp != nil {
+		log.Warn().Err(resp.Error).Str("tenant_id", tenantID).Msg("lot recall: query admins failed")
+		return
+	}
+	title, body := recallNotification(view)
+	notifier := s.Notifications.WithTenant(tenantID)
+	for _, uid := range adminIDs {
+		if err := notifier.Send(context.Background(), uid, "lot_recall", title, body, "lot_recall", view.ID); err != nil {
+			log.Warn().Err(err).Str("tenant_id", tenantID).Str("user_id", uid).Msg("lot recall: notify send failed")
+		}
+	}
+}
+
+// recallNotification returns the title and body of the admin notification of a new recall.
+func recallNotification(view *responses.LotRecallView) (string, string) {
+	title := fmt.Sprintf("Retiro de lote %s — %s", view.LotNumber, view.SKU)
+	body := fmt.Sprintf("Se abrió el retiro %s del lote %s (SKU %s): %s. Clientes afectados: %d, notas de entrega: %d (%.3f uds enviadas). Ubicaciones con stock por retirar: %d.",
+		view.RecallNumber, view.LotNumber, view.SKU, view.Reason,
+		len(view.Affected.Customers), len(view.Affected.DeliveryNotes), view.Affected.ShippedQty, len(view.Tasks))
+	if n := len(view.Affected.PickingTasks); n > 0 {
+		body += fmt.Sprintf(" Pickings abiertos bloqueados: %d.", n)
+	}
+	return title, body
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Recall report — PDF (gofpdf) and Excel (excelize)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// buildLotRecallPDF lists the recalled lot, where it was shipped and the retrieval of its stock.
+func buildLotRecallPDF(view *responses.LotRecallView) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 20, 15)
+	pdf.AddPage()
+
+	// ── Header ──────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, "eSTOCK - Lot Recall Report")
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(90, 7, fmt.Sprintf("Recall: %s", view.RecallNumber))
+	pdf.Cell(0, 7, fmt.Sprintf("Opened: %s", view.CreatedAt.Format("2006-01-02")))
+	pdf.Ln(8)
+	pdf.Cell(90, 7, fmt.Sprintf("Status: %s", view.Status))
+	pdf.Cell(0, 7, fmt.Sprintf("Class: %s", optionalText(view.Classification)))
+	pdf.Ln(8)
+	pdf.Cell(90, 7, fmt.Sprintf("SKU: %s", view.SKU))
+	pdf.Cell(0, 7, fmt.Sprintf("Lot: %s", view.LotNumber))
+	pdf.Ln(8)
+	pdf.Cell(90, 7, fmt.Sprintf("Article: %s", optionalText(view.ArticleName)))
+	pdf.Cell(0, 7, fmt.Sprintf("Expiration: %s", optionalDate(view.ExpirationDate)))
+	pdf.Ln(8)
+	if view.ClosedAt != nil {
+		pdf.Cell(0, 7, fmt.Sprintf("Closed: %s", view.ClosedAt.Format("2006-01-02")))
+		pdf.Ln(8)
+	}
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(0, 5, fmt.Sprintf("Reason: %s", view.Reason), "", "L", false)
+	pdf.Ln(4)
+
+	section := func(title string) {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.Cell(0, 8, title)
+		pdf.Ln(9)
+	}
+	headerRow := func(widths []float64, cols []string) {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(220, 220, 220)
+		for i, c := range cols {
+			ln := 0
+			if i == len(cols)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(widths[i], 8, c, "1", ln, "L", true, 0, "")
+		}
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	row := func(widths []float64, cols []string) {
+		for i, c := range cols {
+			ln := 0
+			if i == len(cols)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(widths[i], 7, c, "1", ln, "L", false, 0, "")
+		}
+	}
+
+	// ── Affected customers ──────────────────────────────────────────────────
+	section(fmt.Sprintf("Affected customers (%d) - shipped qty %.3f", len(view.Affected.Customers), view.Affected.ShippedQty))
+	widths := []float64{30, 60, 50, 25, 0}
+	headerRow(widths, []string{"Code", "Customer", "Contact", "Qty", "DNs"})
+	for _, c := range view.Affected.Customers {
+		contact := optionalText(c.Email)
+		if c.Email == nil && c.Phone != nil {
+			contact = *c.Phone
+		}
+		row(widths, []string{optionalText(c.Code), optionalText(c.Name), contact, fmt.Sprintf("%.3f", c.Qty), fmt.Sprintf("%d", c.DeliveryNotes)})
+	}
+	pdf.Ln(4)
+
+	// ── Delivery notes ──────────────────────────────────────────────────────
+	section(fmt.Sprintf("Delivery notes (%d)", len(view.Affected.DeliveryNotes)))
+	widths = []float64{35, 35, 50, 25, 0}
+	headerRow(widths, []string{"Delivery note", "Sales order", "Customer", "Qty", "Shipped / delivered"})
+	for _, d := range view.Affected.DeliveryNotes {
+		qty := fmt.Sprintf("%.3f", d.Qty)
+		if d.MixedLots {
+			qty += " *"
+		}
+		row(widths, []string{d.DNNumber, optionalText(d.SONumber), optionalText(d.CustomerName), qty,
+			fmt.Sprintf("%s / %s", d.ShippedAt.Format("2006-01-02"), optionalDate(d.DeliveredAt))})
+	}
+	if hasMixedLots(view.Affected.DeliveryNotes) {
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.Cell(0, 6, "* line shipped several lots: qty is the whole line")
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	// ── Stock retrieval ─────────────────────────────────────────────────────
+	section(fmt.Sprintf("Remaining stock tasks (%d)", len(view.Tasks)))
+	widths = []float64{35, 30, 25, 25, 30, 0}
+	headerRow(widths, []string{"Location", "Task", "Qty", "Done qty", "Status", "Target"})
+	for _, t := range view.Tasks {
+		done := "-"
+		if t.CompletedQty != nil {
+			done = fmt.Sprintf("%.3f", *t.CompletedQty)
+		}
+		row(widths, []string{t.Location, t.TaskType, fmt.Sprintf("%.3f", t.Qty), done, t.Status, optionalText(t.TargetLocation)})
+	}
+
+	if view.Notes != nil {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Notes: %s", *view.Notes), "", "L", false)
+	}
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Cell(0, 6, fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("pdf output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// buildLotRecallExcel writes the report in four sheets: summary, customers, delivery notes and
+// stock tasks.
+func buildLotRecallExcel(view *responses.LotRecallView) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	writeSheet := func(sheet string, rows [][]interface{}) error {
+		for r, values := range rows {
+			for c, v := range values {
+				cell, _ := excelize.CoordinatesToCellName(c+1, r+1)
+				if err := f.SetCellValue(sheet, cell, v); err != nil {
+					return fmt.Errorf("write %s!%s: %w", sheet, cell, err)
+				}
+			}
+		}
+		return nil
+	}
+
+	const summary = "Resumen"
+	if err := f.SetSheetName("Sheet1", summary); err != nil {
+		return nil, fmt.Errorf("rename sheet: %w", err)
+	}
+	closedAt := ""
+	if view.ClosedAt != nil {
+		closedAt = view.ClosedAt.Format(time.RFC3339)
+	}
+	if err := writeSheet(summary, [][]interface{}{
+		{"Retiro", view.RecallNumber},
+		{"Estado", view.Status},
+		{"Clase", optionalText(view.Classification)},
+		{"SKU", view.SKU},
+		{"Artículo", optionalText(view.ArticleName)},
+		{"Lote", view.LotNumber},
+		{"Vencimiento", optionalDate(view.ExpirationDate)},
+		{"Motivo", view.Reason},
+		{"Abierto", view.CreatedAt.Format(time.RFC3339)},
+		{"Cerrado", closedAt},
+		{"Clientes afectados", len(view.Affected.Customers)},
+		{"Notas de entrega", len(view.Affected.DeliveryNotes)},
+		{"Cantidad enviada", view.Affected.ShippedQty},
+		{"Pickings abiertos", len(view.Affected.PickingTasks)},
+	}); err != nil {
+		return nil, err
+	}
+
+	customers := [][]interface{}{{"Código", "Cliente", "Email", "Teléfono", "Cantidad", "Notas de entrega"}}
+	for _, c := range view.Affected.Customers {
+		customers = append(customers, []interface{}{optionalText(c.Code), optionalText(c.Name), optionalText(c.Email), optionalText(c.Phone), c.Qty, c.DeliveryNotes})
+	}
+	deliveries := [][]interface{}{{"Nota de entrega", "Orden de venta", "Cliente", "Cantidad", "Línea con varios lotes", "Enviada", "Entregada"}}
+	for _, d := range view.Affected.DeliveryNotes {
+		mixed := "No"
+		if d.MixedLots {
+			mixed = "Sí"
+		}
+		deliveries = append(deliveries, []interface{}{d.DNNumber, optionalText(d.SONumber), optionalText(d.CustomerName), d.Qty, mixed, d.ShippedAt.Format(time.RFC3339), optionalDate(d.DeliveredAt)})
+	}
+	tasks := [][]interface{}{{"Ubicación", "Tarea", "Cantidad", "Cantidad retirada", "Estado", "Destino", "Completada"}}
+	for _, t := range view.Tasks {
+		var done interface{} = ""
+		if t.CompletedQty != nil {
+			done = *t.CompletedQty
+		}
+		completedAt := ""
+		if t.CompletedAt != nil {
+			completedAt = t.CompletedAt.Format(time.RFC3339)
+		}
+		tasks = append(tasks, []interface{}{t.Location, t.TaskType, t.Qty, done, t.Status, optionalText(t.TargetLocation), completedAt})
+	}
+	for _, s := range []struct {
+		name string
+		rows [][]interface{}
+	}{
+		{"Clientes", customers},
+		{"Entregas", deliveries},
+		{"Tareas", tasks},
+	} {
+		if _, err := f.NewSheet(s.name); err != nil {
+			return nil, fmt.Errorf("create sheet %s: %w", s.name, err)
+		}
+		if err := writeSheet(s.name, s.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("excel output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// optionalDate formats t as a date, "-" when nil.
+func optionalDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// hasMixedLots reports whether a delivery line of the lot also shipped other lots.
+func hasMixedLots(deliveries []responses.RecallDelivery) bool {
+	for _, d := range deliveries {
+		if d.MixedLots {
+			return true
+		}
+	}
+	return false
+}
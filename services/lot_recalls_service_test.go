@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLotRecallsRepo struct {
+	created   *requests.CreateLotRecallRequest
+	completed *requests.CompleteRecallTaskRequest
+	closed    *requests.CloseLotRecallRequest
+	adminIDs  []string
+	view      *responses.LotRecallView
+}
+
+func (m *mockLotRecallsRepo) ListRecalls(tenantID string, status, sku *string, limit, offset int) ([]database.LotRecall, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockLotRecallsRepo) GetRecall(id, tenantID string) (*responses.LotRecallView, *responses.InternalResponse) {
+	if m.view == nil {
+		return nil, &responses.InternalResponse{Message: "Retiro no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.view, nil
+}
+func (m *mockLotRecallsRepo) CreateRecall(tenantID, userID string, req *requests.CreateLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.created = req
+	return sampleRecallView(), nil
+}
+func (m *mockLotRecallsRepo) CompleteTask(id, tenantID, taskID, userID string, req *requests.CompleteRecallTaskRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.completed = req
+	return sampleRecallView(), nil
+}
+func (m *mockLotRecallsRepo) CloseRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.closed = req
+	return sampleRecallView(), nil
+}
+func (m *mockLotRecallsRepo) CancelRecall(id, tenantID, userID string, req *requests.CloseLotRecallRequest) (*responses.LotRecallView, *responses.InternalResponse) {
+	m.closed = req
+	return sampleRecallView(), nil
+}
+func (m *mockLotRecallsRepo) TenantAdminIDs(tenantID string) ([]string, *responses.InternalResponse) {
+	return m.adminIDs, nil
+}
+
+func sampleRecallView() *responses.LotRecallView {
+	name := "Farmacia Sol"
+	so := "SO-2026-0001"
+	target := "RECALL-01"
+	done := 4.0
+	delivered := time.Date(2026, 9, 2, 10, 0, 0, 0, time.UTC)
+	return &responses.LotRecallView{
+		LotRecall: database.LotRecall{
+			ID: "r1", RecallNumber: "RCL-2026-0001", SKU: "SKU-1", LotNumber: "L1",
+			Status: database.LotRecallOpen, Reason: "Contaminación cruzada", CreatedAt: time.Date(2026, 9, 10, 8, 0, 0, 0, time.UTC),
+		},
+		Tasks: []database.LotRecallTask{
+			{ID: "t1", TaskType: database.RecallTaskPickBack, Location: "A-01", Qty: 4, TargetLocation: &target, Status: database.RecallTaskCompleted, CompletedQty: &done},
+			{ID: "t2", TaskType: database.RecallTaskQuarantine, Location: "RECALL-01", Qty: 1, Status: database.RecallTaskPending},
+		},
+		Affected: responses.LotRecallAffected{
+			Customers:   []responses.RecallCustomer{{CustomerID: "c-1", Name: &name, Qty: 6, DeliveryNotes: 1}},
+			SalesOrders: []responses.RecallSalesOrder{{SalesOrderID: "so-1", SONumber: &so, CustomerID: "c-1", Qty: 6}},
+			DeliveryNotes: []responses.RecallDelivery{{
+				DeliveryNoteID: "dn-1", DNNumber: "DN-2026-0001", SalesOrderID: "so-1", SONumber: &so, CustomerID: "c-1",
+				CustomerName: &name, Qty: 6, MixedLots: true, ShippedAt: delivered.Add(-24 * time.Hour), DeliveredAt: &delivered,
+			}},
+			PickingTasks: []responses.RecallPickingTask{{ID: "pt-1", TaskID: "PICK-1", Status: "open"}},
+			ShippedQty:   6,
+		},
+	}
+}
+
+func TestLotRecallsService_CreateRecall_Validation(t *testing.T) {
+	for name, req := range map[string]*requests.CreateLotRecallRequest{
+		"blank lot":    {LotID: " ", Reason: "x"},
+		"blank reason": {LotID: "lot-1", Reason: "  "},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockLotRecallsRepo{}
+			_, resp := NewLotRecallsService(repo).CreateRecall("tenant-1", "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestLotRecallsService_CreateRecall_NotifiesAdmins(t *testing.T) {
+	repo := &mockLotRecallsRepo{adminIDs: []string{"admin-1", "admin-2"}}
+	notifRepo := newMockNotifRepo()
+	svc := NewLotRecallsService(repo)
+	svc.Notifications = NewNotificationsService(notifRepo, nil, "pod-tenant")
+
+	view, resp := svc.CreateRecall("tenant-1", "user-1", &requests.CreateLotRecallRequest{
+		LotID: " lot-1 ", Reason: " Contaminación cruzada ", ReturnLocation: new(string),
+	})
+	require.Nil(t, resp)
+	assert.Equal(t, "RCL-2026-0001", view.RecallNumber)
+	assert.Equal(t, "lot-1", repo.created.LotID)
+	assert.Equal(t, "Contaminación cruzada", repo.created.Reason)
+	assert.Nil(t, repo.created.ReturnLocation, "blank return location means quarantine in place")
+
+	require.Len(t, notifRepo.created, 2)
+	for _, n := range notifRepo.created {
+		assert.Equal(t, "lot_recall", n.EventType)
+		assert.Equal(t, "tenant-1", n.TenantID)
+		require.NotNil(t, n.ResourceID)
+		assert.Equal(t, "r1", *n.ResourceID)
+	}
+	assert.Contains(t, *notifRepo.created[0].Body, "RCL-2026-0001")
+	assert.Contains(t, *notifRepo.created[0].Body, "Pickings abiertos bloqueados: 1")
+}
+
+func TestLotRecallsService_CreateRecall_WithoutNotifications(t *testing.T) {
+	repo := &mockLotRecallsRepo{adminIDs: []string{"admin-1"}}
+	_, resp := NewLotRecallsService(repo).CreateRecall("tenant-1", "user-1", &requests.CreateLotRecallRequest{LotID: "lot-1", Reason: "x"})
+	require.Nil(t, resp)
+}
+
+func TestLotRecallsService_TrimsNotes(t *testing.T) {
+	repo := &mockLotRecallsRepo{}
+	svc := NewLotRecallsService(repo)
+	blank := "  "
+
+	_, resp := svc.CompleteTask("r1", "tenant-1", "t1", "user-1", &requests.CompleteRecallTaskRequest{Notes: &blank})
+	require.Nil(t, resp)
+	assert.Nil(t, repo.completed.Notes)
+
+	_, resp = svc.CloseRecall("r1", "tenant-1", "user-1", &requests.CloseLotRecallRequest{Notes: &blank})
+	require.Nil(t, resp)
+	assert.Nil(t, repo.closed.Notes)
+}
+
+func TestLotRecallsService_GetReport(t *testing.T) {
+	svc := NewLotRecallsService(&mockLotRecallsRepo{view: sampleRecallView()})
+
+	data, name, contentType, resp := svc.GetReport("r1", "tenant-1", RecallReportPDF)
+	require.Nil(t, resp)
+	assert.Equal(t, "RCL-2026-0001.pdf", name)
+	assert.Equal(t, "application/pdf", contentType)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+
+	data, name, contentType, resp = svc.GetReport("r1", "tenant-1", RecallReportExcel)
+	require.Nil(t, resp)
+	assert.Equal(t, "RCL-2026-0001.xlsx", name)
+	assert.Contains(t, contentType, "spreadsheetml")
+	assert.True(t, bytes.HasPrefix(data, []byte("PK")), "xlsx is a zip archive")
+
+	_, _, _, resp = svc.GetReport("r1", "tenant-1", "csv")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLotRecallsService_GetReport_NotFound(t *testing.T) {
+	_, _, _, resp := NewLotRecallsService(&mockLotRecallsRepo{}).GetReport("r1", "tenant-1", RecallReportPDF)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+}
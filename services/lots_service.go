@@ -87,11 +87,58 @@ func sortLotsByRotationStrategy(lots []database.Lot, strategy string) {
 	})
 }
 
+// isQCLotStatus reports whether status is set only by the QC hold or recall workflows.
+func isQCLotStatus(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case database.QCHoldQuarantine, database.QCHoldReleased, database.QCHoldRejected, database.LotStatusRecalled:
+		return true
+	}
+	return false
+}
+
 func (s *LotsService) Create(tenantID string, data *requests.CreateLotRequest) *responses.InternalResponse {
+	if data.Status != nil && isQCLotStatus(*data.Status) {
+		return &responses.InternalResponse{
+			Message:    "El estado de calidad de un lote se gestiona con las retenciones de calidad (/qc-holds) y los retiros (/lot-recalls)",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
 	return s.Repository.CreateLot(tenantID, data)
 }
 
+// UpdateUpdateLot applies a partial update. QC statuses (quarantine, released, rejected) are
+// only moved by the QC hold workflow and recalled by the recall workflow, so they cannot be
+// set nor left through this endpoint.
 func (s *LotsService) UpdateUpdateLot(tenantID, id string, data map[string]interface{}) *responses.InternalResponse {
+	if raw, ok := data["status"]; ok {
+		status, _ := raw.(string)
+		if isQCLotStatus(status) {
+			return &responses.InternalResponse{
+				Message:    "El estado de calidad de un lote se gestiona con las retenciones de calidad (/qc-holds) y los retiros (/lot-recalls)",
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		lot, resp := s.Repository.GetLotByIDForTenant(id, tenantID)
+		if resp != nil {
+			return resp
+		}
+		if lot != nil && lot.Status != nil && (*lot.Status == database.QCHoldQuarantine || *lot.Status == database.QCHoldRejected) {
+			return &responses.InternalResponse{
+				Message:    "El lote está retenido por calidad; libérelo o recházelo desde su retención de calidad",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+		if lot != nil && lot.Status != nil && *lot.Status == database.LotStatusRecalled {
+			return &responses.InternalResponse{
+				Message:    "El lote está en retiro de mercado; su estado se gestiona desde el retiro",
+				Handled:    true,
+				StatusCode: responses.StatusConflict,
+			}
+		}
+	}
 	return s.Repository.UpdateLot(tenantID, id, data)
 }
 
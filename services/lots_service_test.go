@@ -401,3 +401,51 @@ func TestLotsService_UpdateAndDelete_PropagateTenantID(t *testing.T) {
 	require.Nil(t, svc.DeleteLot(testTenantA, "lot-1"))
 	assert.Equal(t, testTenantA, repo.lastDeleteTenant)
 }
+
+func TestLotsService_CreateLot_RejectsQCStatus(t *testing.T) {
+	repo := &mockLotsRepo{}
+	svc := NewLotsService(repo, nil)
+	status := "Quarantine"
+	errResp := svc.Create(testTenantA, &requests.CreateLotRequest{LotNumber: "L", SKU: "S", Quantity: 1, Status: &status})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
+	assert.Empty(t, repo.lastCreateTenant, "repo must not be called")
+}
+
+func TestLotsService_UpdateLot_QCStatusGuard(t *testing.T) {
+	quarantine := database.QCHoldQuarantine
+	repo := &mockLotsRepo{lots: []database.Lot{
+		{ID: "held", TenantID: testTenantA, Status: &quarantine},
+		{ID: "free", TenantID: testTenantA},
+	}}
+	svc := NewLotsService(repo, nil)
+
+	errResp := svc.UpdateUpdateLot(testTenantA, "free", map[string]interface{}{"status": "released"})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
+
+	errResp = svc.UpdateUpdateLot(testTenantA, "held", map[string]interface{}{"status": "pending"})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusConflict, errResp.StatusCode)
+	assert.Empty(t, repo.lastUpdateTenant, "repo must not be called")
+
+	// Other fields of a held lot can still be edited.
+	require.Nil(t, svc.UpdateUpdateLot(testTenantA, "held", map[string]interface{}{"lot_notes": "x"}))
+	require.Nil(t, svc.UpdateUpdateLot(testTenantA, "free", map[string]interface{}{"status": "archived"}))
+	assert.Equal(t, testTenantA, repo.lastUpdateTenant)
+}
+
+func TestLotsService_UpdateLot_RecalledGuard(t *testing.T) {
+	recalled := database.LotStatusRecalled
+	repo := &mockLotsRepo{lots: []database.Lot{{ID: "rcl", TenantID: testTenantA, Status: &recalled}}}
+	svc := NewLotsService(repo, nil)
+
+	errResp := svc.UpdateUpdateLot(testTenantA, "rcl", map[string]interface{}{"status": "pending"})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusConflict, errResp.StatusCode)
+
+	errResp = svc.UpdateUpdateLot(testTenantA, "rcl", map[string]interface{}{"status": "recalled"})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
+	assert.Empty(t, repo.lastUpdateTenant)
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// defaultPreviewCount is how many numbers a preview shows when the request does not say.
+const defaultPreviewCount = 5
+
+// NumberSeriesService previews the lot and serial numbers the tenant series generate.
+type NumberSeriesService struct {
+	Repository ports.NumberSeriesRepository
+}
+
+func NewNumberSeriesService(repo ports.NumberSeriesRepository) *NumberSeriesService {
+	return &NumberSeriesService{Repository: repo}
+}
+
+// Preview returns the next req.Count numbers (default 5) without taking them.
+func (s *NumberSeriesService) Preview(tenantID string, req *requests.NumberSeriesPreviewRequest) (*responses.NumberSeriesPreview, *responses.InternalResponse) {
+	if req.Count == 0 {
+		req.Count = defaultPreviewCount
+	}
+	return s.Repository.Preview(tenantID, req)
+}
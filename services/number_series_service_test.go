@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNumberSeriesRepo struct {
+	gotTenantID string
+	gotReq      *requests.NumberSeriesPreviewRequest
+}
+
+func (m *mockNumberSeriesRepo) Preview(tenantID string, req *requests.NumberSeriesPreviewRequest) (*responses.NumberSeriesPreview, *responses.InternalResponse) {
+	m.gotTenantID = tenantID
+	m.gotReq = req
+	return &responses.NumberSeriesPreview{Kind: req.Kind, Next: make([]string, req.Count)}, nil
+}
+
+func TestNumberSeriesService_Preview_DefaultCount(t *testing.T) {
+	repo := &mockNumberSeriesRepo{}
+	svc := NewNumberSeriesService(repo)
+
+	preview, errResp := svc.Preview(testTenantA, &requests.NumberSeriesPreviewRequest{Kind: "lot"})
+	require.Nil(t, errResp)
+	assert.Len(t, preview.Next, defaultPreviewCount)
+	assert.Equal(t, testTenantA, repo.gotTenantID)
+
+	preview, errResp = svc.Preview(testTenantA, &requests.NumberSeriesPreviewRequest{Kind: "serial", Count: 12})
+	require.Nil(t, errResp)
+	assert.Len(t, preview.Next, 12)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 )
 
 type PickingTaskService struct {
@@ -109,3 +110,75 @@ func (s *PickingTaskService) validateCustomer(customerID string) *responses.Inte
 	}
 	return nil
 }
+
+// GetPickRoute returns the task's allocations grouped by location, in pick path order
+// (explicit pick_sequence, then zone/aisle/rack/level; see tools.OrderPickPath).
+func (s *PickingTaskService) GetPickRoute(id, tenantID string) (*responses.PickRoute, *responses.InternalResponse) {
+	task, resp := s.Repository.GetPickingTaskByID(id)
+	if resp != nil {
+		return nil, resp
+	}
+	if task == nil || task.TenantID != tenantID {
+		return nil, &responses.InternalResponse{
+			Message:    "Tarea de picking no encontrada",
+			Handled:    true,
+			StatusCode: responses.StatusNotFound,
+		}
+	}
+	items, err := requests.ParsePickingTaskItems(task.Items)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al leer los artículos de la tarea de picking"}
+	}
+
+	lines := make(map[string][]responses.PickRouteLine)
+	codes := make([]string, 0)
+	for _, item := range items {
+		for _, alloc := range item.Allocations {
+			if _, ok := lines[alloc.Location]; !ok {
+				codes = append(codes, alloc.Location)
+			}
+			status := "pending"
+			if alloc.Status != nil && *alloc.Status != "" {
+				status = *alloc.Status
+			}
+			lines[alloc.Location] = append(lines[alloc.Location], responses.PickRouteLine{
+				SKU:            item.SKU,
+				LotNumber:      alloc.LotNumber,
+				ExpirationDate: alloc.ExpirationDate,
+				Quantity:       alloc.Quantity,
+				PickedQty:      alloc.PickedQty,
+				Status:         status,
+			})
+		}
+	}
+
+	paths, resp := s.Repository.GetLocationPickPaths(tenantID, codes)
+	if resp != nil {
+		return nil, resp
+	}
+	route := &responses.PickRoute{
+		PickingTaskID: task.ID,
+		TaskID:        task.TaskID,
+		Status:        task.Status,
+		Stops:         make([]responses.PickRouteStop, 0, len(codes)),
+	}
+	for i, code := range tools.OrderPickPath(codes, paths) {
+		loc, ok := paths[code]
+		if !ok {
+			loc = responses.LocationPickPath{LocationCode: code}
+		}
+		route.Stops = append(route.Stops, responses.PickRouteStop{Stop: i + 1, Location: loc, Lines: lines[code]})
+	}
+	route.TotalStops = len(route.Stops)
+	return route, nil
+}
+
+// pickStopNumbers maps each distinct location code to its 1-based stop on the pick path.
+func pickStopNumbers(codes []string, paths map[string]responses.LocationPickPath) map[string]int {
+	ordered := tools.OrderPickPath(codes, paths)
+	stops := make(map[string]int, len(ordered))
+	for i, code := range ordered {
+		stops[code] = i + 1
+	}
+	return stops
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -28,6 +29,7 @@ type mockPickingTaskRepo struct {
 	completeLineErr *responses.InternalResponse
 	templateBytes   []byte
 	templateErr     error
+	pickPaths       map[string]responses.LocationPickPath
 }
 
 func (m *mockPickingTaskRepo) GetAllPickingTasks() ([]responses.PickingTaskView, *responses.InternalResponse) {
@@ -90,6 +92,10 @@ func (m *mockPickingTaskRepo) LinkCustomer(taskID string, customerID *string) *r
 	return nil
 }
 
+func (m *mockPickingTaskRepo) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	return m.pickPaths, nil
+}
+
 // --- Tests ---
 
 func TestPickingTaskService_GetAllPickingTasks_Success(t *testing.T) {
@@ -146,6 +152,49 @@ func TestPickingTaskService_GetPickingTaskByID_NotFound(t *testing.T) {
 	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
 }
 
+func TestPickingTaskService_GetPickRoute_OrdersStopsByPickPath(t *testing.T) {
+	items, err := json.Marshal([]requests.PickingTaskItemRequest{
+		{SKU: "SKU-1", ExpectedQuantity: 5, Allocations: []database.LocationAllocation{{Location: "A-2-1", Quantity: 3}, {Location: "A-1-1", Quantity: 2}}},
+		{SKU: "SKU-2", ExpectedQuantity: 4, Allocations: []database.LocationAllocation{{Location: "DOCK", Quantity: 1}, {Location: "A-2-1", Quantity: 3}}},
+	})
+	require.NoError(t, err)
+	zone, aisle1, aisle2, rack := "Z1", "1", "2", "1"
+	repo := &mockPickingTaskRepo{
+		byID: map[string]*database.PickingTask{
+			"1": {ID: "1", TenantID: "tenant-1", TaskID: "TASK-001", Status: "in_progress", Items: items},
+		},
+		pickPaths: map[string]responses.LocationPickPath{
+			"A-1-1": {LocationCode: "A-1-1", Zone: &zone, Aisle: &aisle1, Rack: &rack},
+			"A-2-1": {LocationCode: "A-2-1", Zone: &zone, Aisle: &aisle2, Rack: &rack},
+		},
+	}
+	svc := NewPickingTaskService(repo)
+
+	route, errResp := svc.GetPickRoute("1", "tenant-1")
+	require.Nil(t, errResp)
+	require.Equal(t, 3, route.TotalStops)
+	assert.Equal(t, "A-1-1", route.Stops[0].Location.LocationCode)
+	assert.Equal(t, "A-2-1", route.Stops[1].Location.LocationCode)
+	assert.Equal(t, 2, route.Stops[1].Stop)
+	require.Len(t, route.Stops[1].Lines, 2, "both SKUs picked at A-2-1 share one stop")
+	assert.Equal(t, "pending", route.Stops[1].Lines[0].Status)
+	assert.Equal(t, "DOCK", route.Stops[2].Location.LocationCode, "locations without a pick path come last")
+}
+
+func TestPickingTaskService_GetPickRoute_OtherTenant(t *testing.T) {
+	repo := &mockPickingTaskRepo{
+		byID: map[string]*database.PickingTask{
+			"1": {ID: "1", TenantID: "tenant-1", TaskID: "TASK-001", Items: json.RawMessage("[]")},
+		},
+	}
+	svc := NewPickingTaskService(repo)
+
+	route, errResp := svc.GetPickRoute("1", "tenant-2")
+	require.NotNil(t, errResp)
+	assert.Nil(t, route)
+	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
+}
+
 func TestPickingTaskService_CreatePickingTask_Success(t *testing.T) {
 	repo := &mockPickingTaskRepo{}
 	svc := NewPickingTaskService(repo)
@@ -0,0 +1,473 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+)
+
+// wavePickEpsilon absorbs float noise when comparing picked and allocated quantities.
+const wavePickEpsilon = 1e-6
+
+// PickingWavesService provides business logic for wave (batch) picking: grouping open
+// picking tasks, the consolidated pick list with per-task sort-to quantities, and starting
+// and completing the wave. Member tasks are started and completed through
+// PickingTaskRepository, so reservations, stock movements, sales order picked quantities
+// and delivery notes follow exactly the per-task path.
+type PickingWavesService struct {
+	Repository  ports.PickingWavesRepository
+	PickingRepo ports.PickingTaskRepository
+}
+
+func NewPickingWavesService(repo ports.PickingWavesRepository, pickingRepo ports.PickingTaskRepository) *PickingWavesService {
+	return &PickingWavesService{
+		Repository:  repo,
+		PickingRepo: pickingRepo,
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// isFinalPickingStatus reports whether a picking task no longer needs work in a wave.
+func isFinalPickingStatus(status string) bool {
+	return status == "completed" || status == "completed_with_differences" || status == "cancelled"
+}
+
+// normalizeWaveRequest fills group_by (manual when only task ids are given), de-duplicates
+// the explicit task ids and checks that the criteria of the chosen grouping are present.
+func normalizeWaveRequest(req *requests.CreatePickingWaveRequest) *responses.InternalResponse {
+	if req.GroupBy == "" {
+		req.GroupBy = "manual"
+	}
+
+	var missing string
+	switch req.GroupBy {
+	case "manual":
+		seen := make(map[string]bool, len(req.PickingTaskIDs))
+		ids := make([]string, 0, len(req.PickingTaskIDs))
+		for _, id := range req.PickingTaskIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		req.PickingTaskIDs = ids
+		if len(ids) == 0 {
+			missing = "picking_task_ids"
+		}
+	case "customer":
+		if len(req.CustomerIDs) == 0 {
+			missing = "customer_ids"
+		}
+	case "priority":
+		if req.Priority == nil {
+			missing = "priority"
+		}
+	case "expected_date":
+		if req.ExpectedDate == nil {
+			missing = "expected_date"
+		}
+	}
+	if missing != "" {
+		return &responses.InternalResponse{
+			Message:    fmt.Sprintf("Agrupación %q requiere %s", req.GroupBy, missing),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+// buildPickingWaveView maps a wave and its members to the response shape.
+func buildPickingWaveView(wave *database.PickingWave, members []database.PickingWaveMember) *responses.PickingWaveView {
+	view := &responses.PickingWaveView{
+		Wave:  wave,
+		Tasks: make([]responses.PickingWaveTaskView, 0, len(members)),
+	}
+	for _, m := range members {
+		view.Tasks = append(view.Tasks, responses.PickingWaveTaskView{
+			PickingTaskID: m.ID,
+			TaskID:        m.TaskID,
+			OrderNumber:   m.OrderNumber,
+			Status:        m.Status,
+			Priority:      m.Priority,
+			CustomerID:    m.CustomerID,
+			SalesOrderID:  m.SalesOrderID,
+			ExpectedDate:  m.ExpectedDate,
+			Sequence:      m.Sequence,
+			Active:        m.Active,
+		})
+	}
+	return view
+}
+
+// wavePickStatus is pending until a quantity is recorded, then picked or short.
+func wavePickStatus(quantity float64, picked *float64) string {
+	switch {
+	case picked == nil:
+		return "pending"
+	case *picked+wavePickEpsilon >= quantity:
+		return "picked"
+	default:
+		return "short"
+	}
+}
+
+// buildWavePickList merges the allocations of the active, non-cancelled members into one
+// line per location + SKU + lot. Each line keeps the quantity owed to every task (sort-to),
+// in wave sequence. A line's picked_qty is set once every allocation in it has one. Lines
+// are left in discovery order; routeWavePickList puts them in walk order.
+func buildWavePickList(wave *database.PickingWave, members []database.PickingWaveMember) (*responses.WavePickList, error) {
+	type lineAcc struct {
+		line     *responses.WavePickLine
+		sortIdx  map[string]int
+		recorded bool
+		picked   float64
+	}
+	lines := make(map[string]*lineAcc)
+	order := make([]*lineAcc, 0)
+
+	for _, m := range members {
+		if !m.Active || m.Status == "cancelled" {
+			continue
+		}
+		items, err := requests.ParsePickingTaskItems(m.Items)
+		if err != nil {
+			return nil, fmt.Errorf("parse items of task %s: %w", m.TaskID, err)
+		}
+		for _, item := range items {
+			for _, alloc := range item.Allocations {
+				lot := ""
+				if alloc.LotNumber != nil {
+					lot = *alloc.LotNumber
+				}
+				key := alloc.Location + "\x00" + item.SKU + "\x00" + lot
+				acc, ok := lines[key]
+				if !ok {
+					acc = &lineAcc{
+						line: &responses.WavePickLine{
+							Location:       alloc.Location,
+							SKU:            item.SKU,
+							ExpirationDate: alloc.ExpirationDate,
+							SortTo:         make([]responses.WavePickSortTo, 0, 1),
+						},
+						sortIdx:  make(map[string]int),
+						recorded: true,
+					}
+					if lot != "" {
+						l := lot
+						acc.line.LotNumber = &l
+					}
+					lines[key] = acc
+					order = append(order, acc)
+				}
+
+				acc.line.Quantity += alloc.Quantity
+				if alloc.PickedQty == nil {
+					acc.recorded = false
+				} else {
+					acc.picked += *alloc.PickedQty
+				}
+
+				idx, ok := acc.sortIdx[m.ID]
+				if !ok {
+					acc.line.SortTo = append(acc.line.SortTo, responses.WavePickSortTo{
+						PickingTaskID: m.ID,
+						TaskID:        m.TaskID,
+						OrderNumber:   m.OrderNumber,
+						Sequence:      m.Sequence,
+					})
+					idx = len(acc.line.SortTo) - 1
+					acc.sortIdx[m.ID] = idx
+				}
+				st := &acc.line.SortTo[idx]
+				st.Quantity += alloc.Quantity
+				if alloc.PickedQty != nil {
+					p := *alloc.PickedQty
+					if st.PickedQty != nil {
+						p += *st.PickedQty
+					}
+					st.PickedQty = &p
+				}
+			}
+		}
+	}
+
+	list := &responses.WavePickList{
+		WaveID:     wave.ID,
+		WaveNumber: wave.WaveNumber,
+		Status:     wave.Status,
+		Lines:      make([]responses.WavePickLine, 0, len(order)),
+	}
+	for _, acc := range order {
+		if acc.recorded {
+			p := acc.picked
+			acc.line.PickedQty = &p
+		}
+		acc.line.Status = wavePickStatus(acc.line.Quantity, acc.line.PickedQty)
+		for i := range acc.line.SortTo {
+			st := &acc.line.SortTo[i]
+			st.Status = wavePickStatus(st.Quantity, st.PickedQty)
+		}
+		list.Lines = append(list.Lines, *acc.line)
+	}
+	list.TotalLines = len(list.Lines)
+	return list, nil
+}
+
+// routeWavePickList sorts the lines along the pick path (see tools.OrderPickPath), then by SKU
+// and lot within a location, and numbers the stops.
+func routeWavePickList(list *responses.WavePickList, paths map[string]responses.LocationPickPath) {
+	codes := make([]string, 0, len(list.Lines))
+	for _, l := range list.Lines {
+		codes = append(codes, l.Location)
+	}
+	stops := pickStopNumbers(codes, paths)
+	sort.SliceStable(list.Lines, func(i, j int) bool {
+		a, b := list.Lines[i], list.Lines[j]
+		if stops[a.Location] != stops[b.Location] {
+			return stops[a.Location] < stops[b.Location]
+		}
+		if a.SKU != b.SKU {
+			return a.SKU < b.SKU
+		}
+		return lotKey(a.LotNumber) < lotKey(b.LotNumber)
+	})
+	for i := range list.Lines {
+		list.Lines[i].Stop = stops[list.Lines[i].Location]
+	}
+}
+
+func lotKey(lot *string) string {
+	if lot == nil {
+		return ""
+	}
+	return *lot
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Waves
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CreateWave validates the grouping criteria and creates an open wave with its member tasks.
+func (s *PickingWavesService) CreateWave(tenantID, userID string, req *requests.CreatePickingWaveRequest) (*responses.PickingWaveView, *responses.InternalResponse) {
+	if resp := normalizeWaveRequest(req); resp != nil {
+		return nil, resp
+	}
+	wave, resp := s.Repository.CreateWave(tenantID, userID, req)
+	if resp != nil {
+		return nil, resp
+	}
+	return s.loadView(wave)
+}
+
+// GetWave returns a wave with its member tasks.
+func (s *PickingWavesService) GetWave(id, tenantID string) (*responses.PickingWaveView, *responses.InternalResponse) {
+	wave, resp := s.Repository.GetWaveByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	return s.loadView(wave)
+}
+
+func (s *PickingWavesService) ListWaves(tenantID string, status *string, limit, offset int) ([]database.PickingWave, *responses.InternalResponse) {
+	return s.Repository.ListWaves(tenantID, status, limit, offset)
+}
+
+// GetPickList returns the consolidated pick list of a wave.
+func (s *PickingWavesService) GetPickList(id, tenantID string) (*responses.WavePickList, *responses.InternalResponse) {
+	wave, resp := s.Repository.GetWaveByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	members, resp := s.Repository.GetWaveMembers(wave.ID)
+	if resp != nil {
+		return nil, resp
+	}
+	list, err := buildWavePickList(wave, members)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al construir la lista de picking de la ola"}
+	}
+	codes := make([]string, 0, len(list.Lines))
+	for _, l := range list.Lines {
+		codes = append(codes, l.Location)
+	}
+	paths, resp := s.Repository.GetLocationPickPaths(tenantID, codes)
+	if resp != nil {
+		return nil, resp
+	}
+	routeWavePickList(list, paths)
+	return list, nil
+}
+
+// StartWave starts every open member task (reserving its stock) and moves the wave to
+// in_progress once at least one member is being picked. Calling it again on an in-progress
+// wave retries the members that could not be started.
+func (s *PickingWavesService) StartWave(ctx context.Context, id, tenantID, userID string) (*responses.PickingWaveActionResult, *responses.InternalResponse) {
+	wave, resp := s.Repository.GetWaveByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if wave.Status != "open" && wave.Status != "in_progress" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("No se puede iniciar una ola en estado %q", wave.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	members, resp := s.Repository.GetWaveMembers(wave.ID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	results := make([]responses.PickingWaveTaskResult, 0, len(members))
+	picking := 0
+	for _, m := range members {
+		if !m.Active || isFinalPickingStatus(m.Status) {
+			continue
+		}
+		result := responses.PickingWaveTaskResult{PickingTaskID: m.ID, TaskID: m.TaskID, Status: m.Status}
+		if m.Status != "in_progress" {
+			if resp := s.PickingRepo.StartPickingTask(ctx, m.ID, userID); resp != nil {
+				result.Error = resp.Message
+				results = append(results, result)
+				continue
+			}
+			result.Status = "in_progress"
+		}
+		picking++
+		results = append(results, result)
+	}
+
+	if wave.Status == "open" && picking > 0 {
+		if wave, resp = s.Repository.TransitionWave(wave.ID, tenantID, "in_progress"); resp != nil {
+			return nil, resp
+		}
+	}
+	view, resp := s.loadView(wave)
+	if resp != nil {
+		return nil, resp
+	}
+	return &responses.PickingWaveActionResult{Wave: view, Results: results}, nil
+}
+
+// RecordPick records the quantity picked for one consolidated pick line, sorts it to the
+// member tasks and returns the updated line.
+func (s *PickingWavesService) RecordPick(id, tenantID string, req *requests.RecordWavePickRequest) (*responses.WavePickLine, *responses.InternalResponse) {
+	if resp := s.Repository.RecordPick(id, tenantID, req); resp != nil {
+		return nil, resp
+	}
+	list, resp := s.GetPickList(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	for i := range list.Lines {
+		l := list.Lines[i]
+		if l.Location == req.Location && l.SKU == req.SKU && lotKey(l.LotNumber) == lotKey(req.LotNumber) {
+			return &l, nil
+		}
+	}
+	return nil, &responses.InternalResponse{
+		Message:    "Línea de picking no encontrada",
+		Handled:    true,
+		StatusCode: responses.StatusNotFound,
+	}
+}
+
+// CompleteWave completes every in-progress member task through the per-task completion
+// (stock, sales order picked qty, delivery note, backorders). A member that fails is reported
+// in the results and the wave stays in_progress so it can be fixed and completed again;
+// once every member is finished the wave is completed, or completed_with_differences when
+// any member was.
+func (s *PickingWavesService) CompleteWave(ctx context.Context, id, tenantID, userID string) (*responses.PickingWaveActionResult, *responses.InternalResponse) {
+	wave, resp := s.Repository.GetWaveByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if wave.Status != "in_progress" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Solo se pueden completar olas en proceso (estado actual: %q)", wave.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	members, resp := s.Repository.GetWaveMembers(wave.ID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	results := make([]responses.PickingWaveTaskResult, 0, len(members))
+	for _, m := range members {
+		if !m.Active || m.Status == "cancelled" {
+			continue
+		}
+		result := responses.PickingWaveTaskResult{PickingTaskID: m.ID, TaskID: m.TaskID, Status: m.Status}
+		if m.Status == "in_progress" {
+			if resp := s.PickingRepo.CompletePickingTask(ctx, m.ID, userID); resp != nil {
+				result.Error = resp.Message
+			}
+		}
+		results = append(results, result)
+	}
+
+	// Read back the final task statuses set by the per-task completion.
+	members, resp = s.Repository.GetWaveMembers(wave.ID)
+	if resp != nil {
+		return nil, resp
+	}
+	finalStatus := "completed"
+	pending := false
+	for _, m := range members {
+		if !m.Active {
+			continue
+		}
+		for i := range results {
+			if results[i].PickingTaskID == m.ID {
+				results[i].Status = m.Status
+			}
+		}
+		switch {
+		case !isFinalPickingStatus(m.Status):
+			pending = true
+		case m.Status == "completed_with_differences":
+			finalStatus = "completed_with_differences"
+		}
+	}
+
+	if !pending {
+		if wave, resp = s.Repository.TransitionWave(wave.ID, tenantID, finalStatus); resp != nil {
+			return nil, resp
+		}
+	}
+	view, resp := s.loadView(wave)
+	if resp != nil {
+		return nil, resp
+	}
+	return &responses.PickingWaveActionResult{Wave: view, Results: results}, nil
+}
+
+// CancelWave cancels an open or in-progress wave and releases its tasks, which go back to
+// being picked one by one. The tasks themselves (and any quantities already recorded on
+// them) are left as they are.
+func (s *PickingWavesService) CancelWave(id, tenantID string) (*responses.PickingWaveView, *responses.InternalResponse) {
+	wave, resp := s.Repository.TransitionWave(id, tenantID, "cancelled")
+	if resp != nil {
+		return nil, resp
+	}
+	return s.loadView(wave)
+}
+
+func (s *PickingWavesService) loadView(wave *database.PickingWave) (*responses.PickingWaveView, *responses.InternalResponse) {
+	members, resp := s.Repository.GetWaveMembers(wave.ID)
+	if resp != nil {
+		return nil, resp
+	}
+	return buildPickingWaveView(wave, members), nil
+}
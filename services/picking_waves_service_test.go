@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mock repositories
+// ─────────────────────────────────────────────────────────────────────────────
+
+// mockPickingWavesRepo keeps one wave and its members in memory.
+type mockPickingWavesRepo struct {
+	wave        *database.PickingWave
+	members     []database.PickingWaveMember
+	created     *requests.CreatePickingWaveRequest
+	transitions []string
+	pickPaths   map[string]responses.LocationPickPath
+}
+
+func (m *mockPickingWavesRepo) CreateWave(tenantID, createdBy string, req *requests.CreatePickingWaveRequest) (*database.PickingWave, *responses.InternalResponse) {
+	m.created = req
+	return m.wave, nil
+}
+
+func (m *mockPickingWavesRepo) GetWaveByID(id, tenantID string) (*database.PickingWave, *responses.InternalResponse) {
+	if m.wave == nil || m.wave.ID != id {
+		return nil, &responses.InternalResponse{Message: "Ola de picking no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.wave, nil
+}
+
+func (m *mockPickingWavesRepo) ListWaves(tenantID string, status *string, limit, offset int) ([]database.PickingWave, *responses.InternalResponse) {
+	return []database.PickingWave{*m.wave}, nil
+}
+
+func (m *mockPickingWavesRepo) GetWaveMembers(waveID string) ([]database.PickingWaveMember, *responses.InternalResponse) {
+	out := make([]database.PickingWaveMember, len(m.members))
+	copy(out, m.members)
+	return out, nil
+}
+
+func (m *mockPickingWavesRepo) TransitionWave(id, tenantID, next string) (*database.PickingWave, *responses.InternalResponse) {
+	m.transitions = append(m.transitions, next)
+	m.wave.Status = next
+	return m.wave, nil
+}
+
+func (m *mockPickingWavesRepo) RecordPick(waveID, tenantID string, req *requests.RecordWavePickRequest) *responses.InternalResponse {
+	return nil
+}
+
+func (m *mockPickingWavesRepo) GetLocationPickPaths(tenantID string, codes []string) (map[string]responses.LocationPickPath, *responses.InternalResponse) {
+	return m.pickPaths, nil
+}
+
+func (m *mockPickingWavesRepo) member(id string) *database.PickingWaveMember {
+	for i := range m.members {
+		if m.members[i].ID == id {
+			return &m.members[i]
+		}
+	}
+	return nil
+}
+
+// wavePickingTaskRepo starts and completes tasks by updating the wave mock's members.
+// Tasks listed in fail return an error instead.
+type wavePickingTaskRepo struct {
+	mockPickingTaskRepo
+	waves      *mockPickingWavesRepo
+	finalState map[string]string
+	fail       map[string]bool
+	started    []string
+	completed  []string
+}
+
+func (m *wavePickingTaskRepo) StartPickingTask(_ context.Context, id, userId string) *responses.InternalResponse {
+	if m.fail[id] {
+		return &responses.InternalResponse{Message: "Stock insuficiente", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	m.started = append(m.started, id)
+	m.waves.member(id).Status = "in_progress"
+	return nil
+}
+
+func (m *wavePickingTaskRepo) CompletePickingTask(_ context.Context, id, userId string) *responses.InternalResponse {
+	if m.fail[id] {
+		return &responses.InternalResponse{Message: "Tolerancia excedida", Handled: true, StatusCode: responses.StatusConflict}
+	}
+	m.completed = append(m.completed, id)
+	status := "completed"
+	if s, ok := m.finalState[id]; ok {
+		status = s
+	}
+	m.waves.member(id).Status = status
+	return nil
+}
+
+func waveItems(t *testing.T, items ...requests.PickingTaskItemRequest) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(items)
+	require.NoError(t, err)
+	return raw
+}
+
+func waveMember(id string, seq int, status string, items json.RawMessage) database.PickingWaveMember {
+	return database.PickingWaveMember{
+		PickingTask: database.PickingTask{ID: id, TaskID: "PICK-" + id, OrderNumber: "SO-" + id, Status: status, Priority: "normal", Items: items},
+		Sequence:    seq,
+		Active:      true,
+	}
+}
+
+func newWaveTestRepos(t *testing.T, waveStatus string, memberStatus string) (*mockPickingWavesRepo, *wavePickingTaskRepo) {
+	lot := "L1"
+	waves := &mockPickingWavesRepo{
+		wave: &database.PickingWave{ID: "wave-1", WaveNumber: "WV-2026-0001", Status: waveStatus, GroupBy: "manual"},
+		members: []database.PickingWaveMember{
+			waveMember("t1", 1, memberStatus, waveItems(t,
+				requests.PickingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 5, Allocations: []database.LocationAllocation{{Location: "B-02", Quantity: 5}}},
+				requests.PickingTaskItemRequest{SKU: "SKU-2", ExpectedQuantity: 2, Allocations: []database.LocationAllocation{{Location: "A-01", Quantity: 2, LotNumber: &lot}}},
+			)),
+			waveMember("t2", 2, memberStatus, waveItems(t,
+				requests.PickingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 3, Allocations: []database.LocationAllocation{{Location: "B-02", Quantity: 3}}},
+			)),
+		},
+	}
+	picking := &wavePickingTaskRepo{waves: waves, finalState: map[string]string{}, fail: map[string]bool{}}
+	return waves, picking
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Create
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestPickingWavesService_CreateWave_DefaultsToManualAndDedupes(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "open", "open")
+	svc := NewPickingWavesService(waves, picking)
+
+	view, resp := svc.CreateWave("tenant-1", "user-1", &requests.CreatePickingWaveRequest{PickingTaskIDs: []string{"t1", "t2", "t1"}})
+	require.Nil(t, resp)
+	require.NotNil(t, waves.created)
+	assert.Equal(t, "manual", waves.created.GroupBy)
+	assert.Equal(t, []string{"t1", "t2"}, waves.created.PickingTaskIDs)
+	require.Len(t, view.Tasks, 2)
+	assert.Equal(t, "PICK-t1", view.Tasks[0].TaskID)
+}
+
+func TestPickingWavesService_CreateWave_MissingCriteria(t *testing.T) {
+	tests := []requests.CreatePickingWaveRequest{
+		{},
+		{GroupBy: "customer"},
+		{GroupBy: "priority"},
+		{GroupBy: "expected_date"},
+	}
+	for _, req := range tests {
+		waves, picking := newWaveTestRepos(t, "open", "open")
+		svc := NewPickingWavesService(waves, picking)
+		r := req
+		_, resp := svc.CreateWave("tenant-1", "user-1", &r)
+		require.NotNil(t, resp, "group_by %q", req.GroupBy)
+		assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+		assert.Nil(t, waves.created)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Pick list
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestPickingWavesService_GetPickList_ConsolidatesByLocation(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	svc := NewPickingWavesService(waves, picking)
+
+	list, resp := svc.GetPickList("wave-1", "tenant-1")
+	require.Nil(t, resp)
+	require.Equal(t, 2, list.TotalLines)
+
+	// No pick path configured: walk order falls back to the location code, A-01 first.
+	first := list.Lines[0]
+	assert.Equal(t, 1, first.Stop)
+	assert.Equal(t, "A-01", first.Location)
+	assert.Equal(t, "SKU-2", first.SKU)
+	require.NotNil(t, first.LotNumber)
+	assert.Equal(t, "L1", *first.LotNumber)
+	assert.Equal(t, 2.0, first.Quantity)
+	require.Len(t, first.SortTo, 1)
+
+	merged := list.Lines[1]
+	assert.Equal(t, "B-02", merged.Location)
+	assert.Equal(t, "SKU-1", merged.SKU)
+	assert.Equal(t, 8.0, merged.Quantity)
+	assert.Nil(t, merged.PickedQty)
+	assert.Equal(t, "pending", merged.Status)
+	require.Len(t, merged.SortTo, 2)
+	assert.Equal(t, "t1", merged.SortTo[0].PickingTaskID)
+	assert.Equal(t, 5.0, merged.SortTo[0].Quantity)
+	assert.Equal(t, "t2", merged.SortTo[1].PickingTaskID)
+	assert.Equal(t, 3.0, merged.SortTo[1].Quantity)
+}
+
+func TestPickingWavesService_GetPickList_FollowsPickPath(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	seq := 1
+	waves.pickPaths = map[string]responses.LocationPickPath{
+		"B-02": {LocationCode: "B-02", PickSequence: &seq},
+	}
+	svc := NewPickingWavesService(waves, picking)
+
+	list, resp := svc.GetPickList("wave-1", "tenant-1")
+	require.Nil(t, resp)
+	require.Len(t, list.Lines, 2)
+	assert.Equal(t, "B-02", list.Lines[0].Location, "an explicit pick_sequence comes before unsequenced locations")
+	assert.Equal(t, 1, list.Lines[0].Stop)
+	assert.Equal(t, "A-01", list.Lines[1].Location)
+	assert.Equal(t, 2, list.Lines[1].Stop)
+}
+
+func TestPickingWavesService_GetPickList_ReportsShortPicks(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	waves.members[0].Items = waveItems(t,
+		requests.PickingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 5, Allocations: []database.LocationAllocation{{Location: "B-02", Quantity: 5, PickedQty: tools.Float64Ptr(5)}}},
+	)
+	waves.members[1].Items = waveItems(t,
+		requests.PickingTaskItemRequest{SKU: "SKU-1", ExpectedQuantity: 3, Allocations: []database.LocationAllocation{{Location: "B-02", Quantity: 3, PickedQty: tools.Float64Ptr(1)}}},
+	)
+	svc := NewPickingWavesService(waves, picking)
+
+	list, resp := svc.GetPickList("wave-1", "tenant-1")
+	require.Nil(t, resp)
+	require.Len(t, list.Lines, 1)
+	line := list.Lines[0]
+	require.NotNil(t, line.PickedQty)
+	assert.Equal(t, 6.0, *line.PickedQty)
+	assert.Equal(t, "short", line.Status)
+	assert.Equal(t, "picked", line.SortTo[0].Status)
+	assert.Equal(t, "short", line.SortTo[1].Status)
+}
+
+func TestPickingWavesService_GetPickList_SkipsReleasedAndCancelledTasks(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	waves.members[0].Status = "cancelled"
+	waves.members[1].Active = false
+	svc := NewPickingWavesService(waves, picking)
+
+	list, resp := svc.GetPickList("wave-1", "tenant-1")
+	require.Nil(t, resp)
+	assert.Empty(t, list.Lines)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Start / complete / cancel
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestPickingWavesService_StartWave_StartsOpenTasks(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "open", "open")
+	picking.fail["t2"] = true
+	svc := NewPickingWavesService(waves, picking)
+
+	result, resp := svc.StartWave(context.Background(), "wave-1", "tenant-1", "user-1")
+	require.Nil(t, resp)
+	assert.Equal(t, []string{"t1"}, picking.started)
+	assert.Equal(t, []string{"in_progress"}, waves.transitions)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "in_progress", result.Results[0].Status)
+	assert.Empty(t, result.Results[0].Error)
+	assert.Equal(t, "open", result.Results[1].Status)
+	assert.Equal(t, "Stock insuficiente", result.Results[1].Error)
+}
+
+func TestPickingWavesService_StartWave_StaysOpenWhenNothingStarts(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "open", "open")
+	picking.fail["t1"] = true
+	picking.fail["t2"] = true
+	svc := NewPickingWavesService(waves, picking)
+
+	result, resp := svc.StartWave(context.Background(), "wave-1", "tenant-1", "user-1")
+	require.Nil(t, resp)
+	assert.Empty(t, waves.transitions)
+	assert.Equal(t, "open", result.Wave.Wave.Status)
+}
+
+func TestPickingWavesService_CompleteWave_CompletesEveryTask(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	picking.finalState["t2"] = "completed_with_differences"
+	svc := NewPickingWavesService(waves, picking)
+
+	result, resp := svc.CompleteWave(context.Background(), "wave-1", "tenant-1", "user-1")
+	require.Nil(t, resp)
+	assert.Equal(t, []string{"t1", "t2"}, picking.completed)
+	assert.Equal(t, []string{"completed_with_differences"}, waves.transitions)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "completed", result.Results[0].Status)
+	assert.Equal(t, "completed_with_differences", result.Results[1].Status)
+}
+
+func TestPickingWavesService_CompleteWave_FailedTaskKeepsWaveInProgress(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	picking.fail["t1"] = true
+	svc := NewPickingWavesService(waves, picking)
+
+	result, resp := svc.CompleteWave(context.Background(), "wave-1", "tenant-1", "user-1")
+	require.Nil(t, resp)
+	assert.Equal(t, []string{"t2"}, picking.completed)
+	assert.Empty(t, waves.transitions)
+	assert.Equal(t, "in_progress", result.Wave.Wave.Status)
+	assert.Equal(t, "Tolerancia excedida", result.Results[0].Error)
+	assert.Equal(t, "in_progress", result.Results[0].Status)
+}
+
+func TestPickingWavesService_CompleteWave_RequiresInProgress(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "open", "open")
+	svc := NewPickingWavesService(waves, picking)
+
+	_, resp := svc.CompleteWave(context.Background(), "wave-1", "tenant-1", "user-1")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, picking.completed)
+}
+
+func TestPickingWavesService_RecordPick_ReturnsLine(t *testing.T) {
+	waves, picking := newWaveTestRepos(t, "in_progress", "in_progress")
+	svc := NewPickingWavesService(waves, picking)
+
+	line, resp := svc.RecordPick("wave-1", "tenant-1", &requests.RecordWavePickRequest{Location: "B-02", SKU: "SKU-1", PickedQty: tools.Float64Ptr(8)})
+	require.Nil(t, resp)
+	assert.Equal(t, "B-02", line.Location)
+	assert.Equal(t, 8.0, line.Quantity)
+}
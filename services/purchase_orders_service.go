@@ -4,11 +4,13 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/rs/zerolog/log"
 )
 
 // PurchaseOrdersService provides business logic for Purchase Orders (PO1 + PO2 + PO3).
 type PurchaseOrdersService struct {
-	Repository ports.PurchaseOrdersRepository
+	Repository    ports.PurchaseOrdersRepository
+	ExchangeRates ports.ExchangeRatesRepository // optional: enables order totals in GetByIDWithTotals
 }
 
 func NewPurchaseOrdersService(repo ports.PurchaseOrdersRepository) *PurchaseOrdersService {
@@ -26,6 +28,40 @@ func (s *PurchaseOrdersService) GetByID(id, tenantID string) (*responses.Purchas
 	return s.Repository.GetByID(id, tenantID)
 }
 
+// GetByIDWithTotals is GetByID plus Totals: expected qty × unit cost of every line in the
+// tenant base currency at the rates of the order date and, when currency is given, in that
+// currency too. Without a requested currency a missing rate only drops the totals; with one
+// it is returned as an error.
+func (s *PurchaseOrdersService) GetByIDWithTotals(id, tenantID, currency string) (*responses.PurchaseOrderView, *responses.InternalResponse) {
+	po, resp := s.Repository.GetByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if s.ExchangeRates == nil {
+		if normalizeCurrency(currency) != "" {
+			return nil, currencyUnavailable()
+		}
+		return po, nil
+	}
+
+	amounts := make([]currencyAmount, 0, len(po.Items))
+	for _, it := range po.Items {
+		if it.UnitCost != nil {
+			amounts = append(amounts, currencyAmount{Amount: it.ExpectedQty * *it.UnitCost, Currency: it.Currency})
+		}
+	}
+	totals, resp := orderTotals(s.ExchangeRates, tenantID, po.CreatedAt, amounts, currency)
+	if resp != nil {
+		if normalizeCurrency(currency) != "" {
+			return nil, resp
+		}
+		log.Warn().Str("purchase_order_id", id).Str("reason", resp.Message).Msg("purchase order totals unavailable")
+		return po, nil
+	}
+	po.Totals = totals
+	return po, nil
+}
+
 // List returns purchase orders for a tenant with optional filters and pagination.
 func (s *PurchaseOrdersService) List(tenantID string, status, supplierID, search *string, from, to *string, limit, offset int) ([]responses.PurchaseOrderView, *responses.InternalResponse) {
 	return s.Repository.List(tenantID, status, supplierID, search, from, to, limit, offset)
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+)
+
+// Putaway scoring weights. Consolidating with existing stock beats everything except the
+// article's configured default location; zone and location-type preferences break ties
+// between otherwise equivalent bins.
+const (
+	putawayScoreDefaultLocation = 100
+	putawayScoreSameLot         = 60
+	putawayScoreSameSKU         = 40
+	putawayScorePreferredZone   = 30
+	putawayScoreRelatedZone     = 15
+	putawayScoreEmpty           = 20
+	putawayScoreTypeMax         = 10
+	putawayPenaltyMixedSKU      = -25
+	putawayPenaltyMixedLot      = -20
+	// Bins with capacity limits lose up to putawayOccupancyPenaltyMax points in proportion
+	// to how full their tightest dimension would be after the put; bins without limits lose
+	// one point per putawayOccupancyUnitsPerPoint units on hand, up to the same maximum.
+	putawayOccupancyPenaltyMax    = 10
+	putawayOccupancyUnitsPerPoint = 100
+
+	defaultPutawayLimit = 5
+)
+
+// PutawayService ranks destination locations for received lines.
+type PutawayService struct {
+	Repository ports.PutawayRepository
+}
+
+func NewPutawayService(repo ports.PutawayRepository) *PutawayService {
+	return &PutawayService{Repository: repo}
+}
+
+// putawayContext is everything the ranking needs, loaded once per receiving task.
+type putawayContext struct {
+	articles   map[string]database.Article
+	locations  []database.Location
+	byID       map[string]database.Location
+	typeRank   map[string]int
+	typeActive map[string]bool
+	stock      map[string]map[string][]ports.LocationStock // sku -> location code -> rows
+	capacity   map[string]ports.LocationCapacity
+}
+
+// SuggestForReceivingTask returns the ranked destinations for every line of a receiving task.
+func (s *PutawayService) SuggestForReceivingTask(tenantID, taskID string, req *requests.PutawaySuggestRequest) (*responses.PutawayResult, *responses.InternalResponse) {
+	_, _, result, resp := s.plan(tenantID, taskID, req)
+	return result, resp
+}
+
+// ApplyToReceivingTask writes the best suggestion into each open line's location and
+// returns the plan with Applied set on the lines that changed. Lines that already have a
+// location keep it unless req.Overwrite is set.
+func (s *PutawayService) ApplyToReceivingTask(tenantID, taskID string, req *requests.PutawaySuggestRequest) (*responses.PutawayResult, *responses.InternalResponse) {
+	task, items, result, resp := s.plan(tenantID, taskID, req)
+	if resp != nil {
+		return nil, resp
+	}
+	if task.Status != "open" && task.Status != "in_progress" {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Solo se pueden asignar ubicaciones a tareas abiertas o en progreso (estado actual: %s)", task.Status),
+			Handled:    true,
+			StatusCode: responses.StatusConflict,
+		}
+	}
+
+	changed := false
+	for i := range items {
+		line := &result.Lines[i]
+		if len(line.Suggestions) == 0 || isClosedReceivingLine(items[i]) {
+			continue
+		}
+		if strings.TrimSpace(items[i].Location) != "" && !req.Overwrite {
+			continue
+		}
+		best := line.Suggestions[0].LocationCode
+		if items[i].Location == best {
+			continue
+		}
+		items[i].Location = best
+		line.CurrentLocation = best
+		line.Applied = true
+		changed = true
+	}
+	if !changed {
+		return result, nil
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al serializar los artículos de la tarea de recepción",
+			Handled: false,
+		}
+	}
+	if resp := s.Repository.UpdateReceivingTaskItems(tenantID, taskID, raw); resp != nil {
+		return nil, resp
+	}
+	return result, nil
+}
+
+func (s *PutawayService) plan(tenantID, taskID string, req *requests.PutawaySuggestRequest) (*database.ReceivingTask, []requests.ReceivingTaskItemRequest, *responses.PutawayResult, *responses.InternalResponse) {
+	task, resp := s.Repository.GetReceivingTask(tenantID, taskID)
+	if resp != nil {
+		return nil, nil, nil, resp
+	}
+	var items []requests.ReceivingTaskItemRequest
+	if err := json.Unmarshal(task.Items, &items); err != nil {
+		return nil, nil, nil, &responses.InternalResponse{
+			Error:   err,
+			Message: "Error al leer los artículos de la tarea de recepción",
+			Handled: false,
+		}
+	}
+
+	skus := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, it := range items {
+		if !seen[it.SKU] {
+			seen[it.SKU] = true
+			skus = append(skus, it.SKU)
+		}
+	}
+	pc, resp := s.load(tenantID, skus)
+	if resp != nil {
+		return nil, nil, nil, resp
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPutawayLimit
+	}
+	result := &responses.PutawayResult{TaskID: task.ID, Lines: make([]responses.PutawayLineSuggestions, 0, len(items))}
+	for _, it := range items {
+		line := responses.PutawayLineSuggestions{
+			SKU:             it.SKU,
+			Quantity:        receivingLineQuantity(it),
+			CurrentLocation: it.Location,
+		}
+		for _, lot := range it.LotNumbers {
+			line.LotNumbers = append(line.LotNumbers, lot.LotNumber)
+		}
+		line.Suggestions = rankPutawayLocations(pc, it.SKU, line.LotNumbers, line.Quantity, req, limit)
+		result.Lines = append(result.Lines, line)
+	}
+	return task, items, result, nil
+}
+
+func (s *PutawayService) load(tenantID string, skus []string) (*putawayContext, *responses.InternalResponse) {
+	articles, resp := s.Repository.GetArticles(tenantID, skus)
+	if resp != nil {
+		return nil, resp
+	}
+	locations, resp := s.Repository.ListPutawayLocations(tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	types, resp := s.Repository.ListLocationTypes()
+	if resp != nil {
+		return nil, resp
+	}
+	stock, resp := s.Repository.GetLocationStock(tenantID, skus)
+	if resp != nil {
+		return nil, resp
+	}
+	codes := make([]string, 0, len(locations))
+	for _, l := range locations {
+		codes = append(codes, l.LocationCode)
+	}
+	capacity, resp := s.Repository.GetLocationCapacity(tenantID, codes)
+	if resp != nil {
+		return nil, resp
+	}
+
+	pc := &putawayContext{
+		articles:   make(map[string]database.Article, len(articles)),
+		locations:  locations,
+		byID:       make(map[string]database.Location, len(locations)),
+		typeRank:   make(map[string]int, len(types)),
+		typeActive: make(map[string]bool, len(types)),
+		stock:      make(map[string]map[string][]ports.LocationStock),
+		capacity:   capacity,
+	}
+	for _, a := range articles {
+		pc.articles[a.SKU] = a
+	}
+	for _, l := range locations {
+		pc.byID[l.ID] = l
+	}
+	// types arrive ordered by sort_order; the rank is the position among active types.
+	rank := 0
+	for _, t := range types {
+		code := strings.ToUpper(t.Code)
+		pc.typeActive[code] = t.IsActive
+		if t.IsActive {
+			pc.typeRank[code] = rank
+			rank++
+		}
+	}
+	for _, row := range stock {
+		if pc.stock[row.SKU] == nil {
+			pc.stock[row.SKU] = make(map[string][]ports.LocationStock)
+		}
+		pc.stock[row.SKU][row.Location] = append(pc.stock[row.SKU][row.Location], row)
+	}
+	return pc, nil
+}
+
+// rankPutawayLocations scores every eligible location for one line of qty units and returns
+// the best limit, highest score first (ties: emptier location, then location code). Locations
+// that cannot take qty within their unit, weight or volume limits are left out.
+func rankPutawayLocations(pc *putawayContext, sku string, lots []string, qty float64, req *requests.PutawaySuggestRequest, limit int) []responses.PutawaySuggestion {
+	article, hasArticle := pc.articles[sku]
+	skuStock := pc.stock[sku]
+
+	incoming := tools.LocationLoad{Units: qty}
+	if hasArticle {
+		weight, volume := tools.ArticleUnitLoad(article.WeightKg, article.LengthCm, article.WidthCm, article.HeightCm)
+		incoming.WeightKg = qty * weight
+		incoming.VolumeM3 = qty * volume
+	}
+
+	allowedTypes := make(map[string]bool, len(req.LocationTypes))
+	for _, t := range req.LocationTypes {
+		allowedTypes[strings.ToUpper(strings.TrimSpace(t))] = true
+	}
+	preferredZones := make(map[string]bool, len(req.Zones))
+	for _, z := range req.Zones {
+		preferredZones[strings.ToUpper(strings.TrimSpace(z))] = true
+	}
+	// Zones where the article already lives (default location or current stock).
+	relatedZones := make(map[string]bool)
+	if hasArticle && article.DefaultLocationID != nil {
+		if loc, ok := pc.byID[*article.DefaultLocationID]; ok && loc.Zone != nil {
+			relatedZones[strings.ToUpper(*loc.Zone)] = true
+		}
+	}
+	for _, loc := range pc.locations {
+		if _, ok := skuStock[loc.LocationCode]; ok && loc.Zone != nil {
+			relatedZones[strings.ToUpper(*loc.Zone)] = true
+		}
+	}
+	lotSet := make(map[string]bool, len(lots))
+	for _, l := range lots {
+		lotSet[l] = true
+	}
+	activeTypes := len(pc.typeRank)
+
+	out := make([]responses.PutawaySuggestion, 0, len(pc.locations))
+	for _, loc := range pc.locations {
+		typeCode := strings.ToUpper(loc.Type)
+		if active, known := pc.typeActive[typeCode]; known && !active {
+			continue
+		}
+		if len(allowedTypes) > 0 && !allowedTypes[typeCode] {
+			continue
+		}
+
+		capacity := pc.capacity[loc.LocationCode]
+		limits := tools.LocationLimits{MaxUnits: capacity.MaxUnits, MaxWeightKg: capacity.MaxWeightKg, MaxVolumeM3: capacity.MaxVolumeM3}
+		current := tools.LocationLoad{Units: capacity.Units, WeightKg: capacity.WeightKg, VolumeM3: capacity.VolumeM3}
+		if tools.EvaluateLocationCapacity(loc.LocationCode, limits, current, incoming) != nil {
+			continue
+		}
+
+		sug := responses.PutawaySuggestion{
+			LocationID:   loc.ID,
+			LocationCode: loc.LocationCode,
+			Zone:         loc.Zone,
+			Type:         loc.Type,
+			Reasons:      []string{},
+			Occupancy:    capacity.Units,
+		}
+		add := func(points float64, reason string) {
+			sug.Score += points
+			sug.Reasons = append(sug.Reasons, reason)
+		}
+
+		if hasArticle && article.DefaultLocationID != nil && *article.DefaultLocationID == loc.ID {
+			add(putawayScoreDefaultLocation, "Ubicación predeterminada del artículo")
+		}
+
+		rows := skuStock[loc.LocationCode]
+		sameLot, otherLot := false, false
+		for _, row := range rows {
+			sug.SKUQuantity += row.Quantity
+			if row.LotNumber == "" {
+				continue
+			}
+			if lotSet[row.LotNumber] {
+				sameLot = true
+			} else {
+				otherLot = true
+			}
+		}
+		switch {
+		case sameLot:
+			add(putawayScoreSameLot, "Ya contiene el mismo lote")
+		case len(rows) > 0:
+			add(putawayScoreSameSKU, "Ya contiene el artículo")
+			if len(lotSet) > 0 && otherLot {
+				add(putawayPenaltyMixedLot, "Contiene otros lotes del artículo")
+			}
+		case sug.Occupancy <= 0:
+			add(putawayScoreEmpty, "Ubicación vacía")
+		default:
+			add(putawayPenaltyMixedSKU, "Contiene otros artículos")
+		}
+
+		if loc.Zone != nil {
+			zone := strings.ToUpper(*loc.Zone)
+			if preferredZones[zone] {
+				add(putawayScorePreferredZone, "Zona preferida")
+			} else if relatedZones[zone] {
+				add(putawayScoreRelatedZone, "Zona donde se almacena el artículo")
+			}
+		}
+
+		if rank, ok := pc.typeRank[typeCode]; ok && activeTypes > 0 {
+			points := math.Round(putawayScoreTypeMax*float64(activeTypes-rank)/float64(activeTypes)*100) / 100
+			add(points, fmt.Sprintf("Tipo de ubicación %s (prioridad %d)", loc.Type, rank+1))
+		}
+
+		if fill, bounded := projectedFill(limits, current, incoming); bounded {
+			free := math.Round((1-fill)*10000) / 100
+			sug.FreeCapacityPct = &free
+			sug.Score -= math.Round(putawayOccupancyPenaltyMax*fill*100) / 100
+		} else if sug.Occupancy > 0 {
+			penalty := math.Min(sug.Occupancy/putawayOccupancyUnitsPerPoint, putawayOccupancyPenaltyMax)
+			sug.Score -= math.Round(penalty*100) / 100
+		}
+		out = append(out, sug)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Occupancy != out[j].Occupancy {
+			return out[i].Occupancy < out[j].Occupancy
+		}
+		return out[i].LocationCode < out[j].LocationCode
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// projectedFill returns how full (0–1) the tightest capped dimension of a location would be
+// after adding incoming. bounded is false when the location has no limits.
+func projectedFill(limits tools.LocationLimits, current, incoming tools.LocationLoad) (fill float64, bounded bool) {
+	dims := []struct {
+		max      *float64
+		cur, add float64
+	}{
+		{limits.MaxUnits, current.Units, incoming.Units},
+		{limits.MaxWeightKg, current.WeightKg, incoming.WeightKg},
+		{limits.MaxVolumeM3, current.VolumeM3, incoming.VolumeM3},
+	}
+	for _, d := range dims {
+		if d.max == nil || *d.max <= 0 {
+			continue
+		}
+		bounded = true
+		fill = math.Max(fill, (d.cur+d.add) / *d.max)
+	}
+	return math.Min(fill, 1), bounded
+}
+
+// receivingLineQuantity is the quantity to put away: what was received, else what is expected.
+func receivingLineQuantity(it requests.ReceivingTaskItemRequest) float64 {
+	if it.AcceptedQty != nil && *it.AcceptedQty > 0 {
+		return *it.AcceptedQty
+	}
+	if it.ReceivedQuantity != nil && *it.ReceivedQuantity > 0 {
+		return float64(*it.ReceivedQuantity)
+	}
+	return float64(it.ExpectedQuantity)
+}
+
+// isClosedReceivingLine mirrors the line statuses CompleteReceivingLine refuses to re-receive.
+func isClosedReceivingLine(it requests.ReceivingTaskItemRequest) bool {
+	if it.Status == nil {
+		return false
+	}
+	switch *it.Status {
+	case "completed", "closed", "partial":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPutawayRepo struct {
+	task      *database.ReceivingTask
+	articles  []database.Article
+	locations []database.Location
+	types     []database.LocationType
+	stock     []ports.LocationStock
+	capacity  map[string]ports.LocationCapacity
+	saved     json.RawMessage
+}
+
+func (m *mockPutawayRepo) GetReceivingTask(_, _ string) (*database.ReceivingTask, *responses.InternalResponse) {
+	if m.task == nil {
+		return nil, &responses.InternalResponse{Message: "Tarea de recepción no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.task, nil
+}
+
+func (m *mockPutawayRepo) GetArticles(_ string, _ []string) ([]database.Article, *responses.InternalResponse) {
+	return m.articles, nil
+}
+
+func (m *mockPutawayRepo) ListPutawayLocations(_ string) ([]database.Location, *responses.InternalResponse) {
+	return m.locations, nil
+}
+
+func (m *mockPutawayRepo) ListLocationTypes() ([]database.LocationType, *responses.InternalResponse) {
+	return m.types, nil
+}
+
+func (m *mockPutawayRepo) GetLocationStock(_ string, _ []string) ([]ports.LocationStock, *responses.InternalResponse) {
+	return m.stock, nil
+}
+
+func (m *mockPutawayRepo) GetLocationCapacity(_ string, _ []string) (map[string]ports.LocationCapacity, *responses.InternalResponse) {
+	return m.capacity, nil
+}
+
+func (m *mockPutawayRepo) UpdateReceivingTaskItems(_, _ string, items json.RawMessage) *responses.InternalResponse {
+	m.saved = items
+	return nil
+}
+
+func putawayLoc(id, code, zone, typ string) database.Location {
+	return database.Location{ID: id, LocationCode: code, Zone: &zone, Type: typ, IsActive: true}
+}
+
+func newPutawayRepo(t *testing.T, status string, items []requests.ReceivingTaskItemRequest) *mockPutawayRepo {
+	raw, err := json.Marshal(items)
+	require.NoError(t, err)
+	defaultLoc := "loc-default"
+	return &mockPutawayRepo{
+		task: &database.ReceivingTask{ID: "rt-1", Status: status, Items: raw},
+		articles: []database.Article{
+			{SKU: "SKU-A", DefaultLocationID: &defaultLoc},
+			{SKU: "SKU-B", TrackByLot: true},
+		},
+		locations: []database.Location{
+			putawayLoc("loc-default", "A-01", "A", "SHELF"),
+			putawayLoc("loc-lot", "B-01", "B", "PALLET"),
+			putawayLoc("loc-otherlot", "B-02", "B", "PALLET"),
+			putawayLoc("loc-empty", "C-01", "C", "BIN"),
+			putawayLoc("loc-mixed", "C-02", "C", "BIN"),
+			putawayLoc("loc-floor", "D-01", "D", "FLOOR"),
+		},
+		types: []database.LocationType{
+			{Code: "PALLET", SortOrder: 10, IsActive: true},
+			{Code: "SHELF", SortOrder: 20, IsActive: true},
+			{Code: "BIN", SortOrder: 30, IsActive: true},
+			{Code: "FLOOR", SortOrder: 40, IsActive: false},
+		},
+		stock: []ports.LocationStock{
+			{Location: "B-01", SKU: "SKU-B", LotNumber: "L-1", Quantity: 10},
+			{Location: "B-02", SKU: "SKU-B", LotNumber: "L-0", Quantity: 5},
+		},
+		capacity: map[string]ports.LocationCapacity{
+			"B-01": {Units: 10},
+			"B-02": {Units: 5},
+			"C-02": {Units: 40},
+		},
+	}
+}
+
+func putawayCodes(s []responses.PutawaySuggestion) []string {
+	out := make([]string, 0, len(s))
+	for _, x := range s {
+		out = append(out, x.LocationCode)
+	}
+	return out
+}
+
+func TestPutawayService_Suggest_Ranking(t *testing.T) {
+	repo := newPutawayRepo(t, "open", []requests.ReceivingTaskItemRequest{
+		{SKU: "SKU-A", ExpectedQuantity: 5},
+		{SKU: "SKU-B", ExpectedQuantity: 8, LotNumbers: []requests.CreateLotRequest{{LotNumber: "L-1"}}},
+	})
+	svc := NewPutawayService(repo)
+
+	res, resp := svc.SuggestForReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{})
+	require.Nil(t, resp)
+	require.Len(t, res.Lines, 2)
+
+	a := res.Lines[0]
+	assert.Equal(t, "A-01", a.Suggestions[0].LocationCode, "article default location wins")
+	assert.NotContains(t, putawayCodes(a.Suggestions), "D-01", "inactive location type excluded")
+	assert.Equal(t, "C-02", a.Suggestions[len(a.Suggestions)-1].LocationCode, "bin holding other SKUs ranks last")
+
+	b := res.Lines[1]
+	assert.Equal(t, []string{"L-1"}, b.LotNumbers)
+	assert.Equal(t, 8.0, b.Quantity)
+	assert.Equal(t, []string{"B-01", "B-02"}, putawayCodes(b.Suggestions)[:2], "same lot, then same SKU")
+	assert.Equal(t, 10.0, b.Suggestions[0].SKUQuantity)
+}
+
+func TestPutawayService_Suggest_ZonesTypesAndLimit(t *testing.T) {
+	repo := newPutawayRepo(t, "open", []requests.ReceivingTaskItemRequest{{SKU: "SKU-NEW", ExpectedQuantity: 1}})
+	svc := NewPutawayService(repo)
+
+	res, resp := svc.SuggestForReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{Zones: []string{"c"}, Limit: 1})
+	require.Nil(t, resp)
+	assert.Equal(t, []string{"C-01"}, putawayCodes(res.Lines[0].Suggestions), "empty bin in preferred zone")
+
+	res, resp = svc.SuggestForReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{LocationTypes: []string{"pallet"}})
+	require.Nil(t, resp)
+	assert.ElementsMatch(t, []string{"B-01", "B-02"}, putawayCodes(res.Lines[0].Suggestions))
+}
+
+func TestPutawayService_Apply(t *testing.T) {
+	done := "completed"
+	repo := newPutawayRepo(t, "in_progress", []requests.ReceivingTaskItemRequest{
+		{SKU: "SKU-A", ExpectedQuantity: 5},
+		{SKU: "SKU-B", ExpectedQuantity: 8, Location: "MANUAL", LotNumbers: []requests.CreateLotRequest{{LotNumber: "L-1"}}},
+		{SKU: "SKU-A", ExpectedQuantity: 1, Status: &done},
+	})
+	svc := NewPutawayService(repo)
+
+	res, resp := svc.ApplyToReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{})
+	require.Nil(t, resp)
+	assert.True(t, res.Lines[0].Applied)
+	assert.False(t, res.Lines[1].Applied, "existing location kept without overwrite")
+	assert.False(t, res.Lines[2].Applied, "completed line untouched")
+
+	var saved []requests.ReceivingTaskItemRequest
+	require.NoError(t, json.Unmarshal(repo.saved, &saved))
+	assert.Equal(t, "A-01", saved[0].Location)
+	assert.Equal(t, "MANUAL", saved[1].Location)
+	assert.Equal(t, "", saved[2].Location)
+
+	res, resp = svc.ApplyToReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{Overwrite: true})
+	require.Nil(t, resp)
+	assert.True(t, res.Lines[1].Applied)
+	require.NoError(t, json.Unmarshal(repo.saved, &saved))
+	assert.Equal(t, "B-01", saved[1].Location)
+}
+
+func TestPutawayService_Apply_ClosedTask(t *testing.T) {
+	repo := newPutawayRepo(t, "completed", []requests.ReceivingTaskItemRequest{{SKU: "SKU-A", ExpectedQuantity: 5}})
+	_, resp := NewPutawayService(repo).ApplyToReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Nil(t, repo.saved)
+}
+
+func TestPutawayService_Suggest_Capacity(t *testing.T) {
+	repo := newPutawayRepo(t, "open", []requests.ReceivingTaskItemRequest{{SKU: "SKU-HEAVY", ExpectedQuantity: 8}})
+	weight := 2.0
+	repo.articles = append(repo.articles, database.Article{SKU: "SKU-HEAVY", WeightKg: &weight})
+	repo.locations = append(repo.locations,
+		putawayLoc("loc-roomy", "C-03", "C", "BIN"),
+		putawayLoc("loc-small", "C-04", "C", "BIN"),
+	)
+	maxWeight, tight, roomy, small := 20.0, 10.0, 100.0, 5.0
+	repo.capacity["A-01"] = ports.LocationCapacity{MaxWeightKg: &maxWeight, Units: 5, WeightKg: 10}
+	repo.capacity["C-01"] = ports.LocationCapacity{MaxUnits: &tight}
+	repo.capacity["C-03"] = ports.LocationCapacity{MaxUnits: &roomy}
+	repo.capacity["C-04"] = ports.LocationCapacity{MaxUnits: &small}
+	svc := NewPutawayService(repo)
+
+	res, resp := svc.SuggestForReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{Limit: 10})
+	require.Nil(t, resp)
+	codes := putawayCodes(res.Lines[0].Suggestions)
+	assert.NotContains(t, codes, "A-01", "16 kg more would exceed the 20 kg limit")
+	assert.NotContains(t, codes, "C-04", "8 units do not fit in a 5-unit bin")
+	assert.Equal(t, []string{"C-03", "C-01"}, codes[:2], "empty bins ranked by free capacity after the put")
+	require.NotNil(t, res.Lines[0].Suggestions[0].FreeCapacityPct)
+	assert.Equal(t, 92.0, *res.Lines[0].Suggestions[0].FreeCapacityPct)
+	assert.Equal(t, 20.0, *res.Lines[0].Suggestions[1].FreeCapacityPct)
+
+	applied, resp := svc.ApplyToReceivingTask("tenant-1", "rt-1", &requests.PutawaySuggestRequest{})
+	require.Nil(t, resp)
+	assert.Equal(t, "C-03", applied.Lines[0].CurrentLocation)
+}
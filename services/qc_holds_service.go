@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+)
+
+// QCHoldsService provides business logic for quarantine / QC holds: putting a lot or a
+// quantity of inventory on hold, recording inspection results and attachments (certificates,
+// photos) and the release or reject decision. Attachments live in Storage under
+// tenant-prefixed keys.
+type QCHoldsService struct {
+	Repository ports.QCHoldsRepository
+	Storage    tools.DocumentStorage
+}
+
+// MaxQCAttachmentBytes is the size limit of a QC attachment.
+const MaxQCAttachmentBytes = 10 << 20
+
+// qcAttachmentExts maps the accepted attachment content types to their file extension.
+var qcAttachmentExts = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+}
+
+func NewQCHoldsService(repo ports.QCHoldsRepository, storage tools.DocumentStorage) *QCHoldsService {
+	return &QCHoldsService{Repository: repo, Storage: storage}
+}
+
+func (s *QCHoldsService) ListHolds(tenantID string, status, scope, sku *string, limit, offset int) ([]database.QCHold, *responses.InternalResponse) {
+	return s.Repository.ListHolds(tenantID, status, scope, sku, limit, offset)
+}
+
+func (s *QCHoldsService) GetHold(id, tenantID string) (*responses.QCHoldView, *responses.InternalResponse) {
+	return s.Repository.GetHold(id, tenantID)
+}
+
+// CreateHold checks the hold names what its scope needs: a lot hold its lot number only, an
+// inventory hold the location and quantity (and optionally the lot the units belong to).
+func (s *QCHoldsService) CreateHold(tenantID, userID string, req *requests.CreateQCHoldRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	badRequest := func(msg string) *responses.InternalResponse {
+		return &responses.InternalResponse{Message: msg, Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+
+	req.SKU = strings.TrimSpace(req.SKU)
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.LotNumber = trimmedOrNil(req.LotNumber)
+	req.Location = trimmedOrNil(req.Location)
+	if req.SKU == "" {
+		return nil, badRequest("El SKU es obligatorio")
+	}
+	if req.Reason == "" {
+		return nil, badRequest("El motivo de la retención es obligatorio")
+	}
+	switch req.Scope {
+	case database.QCHoldScopeLot:
+		if req.LotNumber == nil {
+			return nil, badRequest("Indique el lote a poner en cuarentena")
+		}
+		if req.Location != nil || req.Qty != nil {
+			return nil, badRequest("Una retención de lote abarca todo el lote: no indique ubicación ni cantidad")
+		}
+	case database.QCHoldScopeInventory:
+		if req.Location == nil || req.Qty == nil {
+			return nil, badRequest("Indique la ubicación y la cantidad a retener")
+		}
+	default:
+		return nil, badRequest("Alcance de retención inválido")
+	}
+	return s.Repository.CreateHold(tenantID, userID, req)
+}
+
+// RecordInspection checks the measurements are a JSON object and the inspection date is not in
+// the future.
+func (s *QCHoldsService) RecordInspection(id, tenantID, userID string, req *requests.RecordQCInspectionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	measurements := bytes.TrimSpace(req.Measurements)
+	switch {
+	case len(measurements) == 0 || bytes.Equal(measurements, []byte("null")):
+		req.Measurements = nil
+	case measurements[0] != '{' || !json.Valid(measurements):
+		return nil, &responses.InternalResponse{Message: "Las mediciones deben ser un objeto JSON", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if req.InspectedAt != nil && req.InspectedAt.After(time.Now()) {
+		return nil, &responses.InternalResponse{Message: "La fecha de inspección no puede ser futura", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.RecordInspection(id, tenantID, userID, req)
+}
+
+// AddAttachment stores a PDF or image in the document storage and records it on the hold.
+// The stored file is removed when the hold refuses it.
+func (s *QCHoldsService) AddAttachment(id, tenantID, userID, fileName string, inspectionID *string, data []byte) (*database.QCAttachment, *responses.InternalResponse) {
+	badRequest := func(msg string) *responses.InternalResponse {
+		return &responses.InternalResponse{Message: msg, Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if len(data) == 0 {
+		return nil, badRequest("El archivo está vacío")
+	}
+	if len(data) > MaxQCAttachmentBytes {
+		return nil, badRequest(fmt.Sprintf("El archivo supera el máximo de %d MB", MaxQCAttachmentBytes>>20))
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := qcAttachmentExts[contentType]
+	if !ok {
+		return nil, badRequest("Formato no soportado: se aceptan PDF, JPEG, PNG y WebP")
+	}
+	// The name is echoed in the download's Content-Disposition header: drop quotes and controls.
+	fileName = strings.Map(func(r rune) rune {
+		if r == '"' || r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, fileName)
+	fileName = strings.TrimSpace(filepath.Base(fileName))
+	if fileName == "" || fileName == "." || fileName == string(filepath.Separator) {
+		fileName = "attachment" + ext
+	}
+
+	// The key carries a timestamp so attachments never overwrite each other.
+	ctx := context.Background()
+	key := tools.TenantDocumentKey(tenantID, "qc", id, fmt.Sprintf("%d%s", time.Now().UnixNano(), ext))
+	stored, err := s.Storage.Put(ctx, key, data, contentType)
+	if err != nil {
+		return nil, &responses.InternalResponse{Error: err, Message: "Error al guardar el adjunto"}
+	}
+
+	attachment := &database.QCAttachment{
+		InspectionID: trimmedOrNil(inspectionID),
+		FileName:     fileName,
+		ContentType:  contentType,
+		SizeBytes:    stored.Size,
+		StorageKey:   key,
+		SHA256:       stored.SHA256,
+	}
+	if userID != "" {
+		attachment.UploadedBy = &userID
+	}
+	created, resp := s.Repository.AddAttachment(id, tenantID, attachment)
+	if resp != nil {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			fmt.Printf("[WARN] AddQCAttachment: delete %s: %v\n", key, err)
+		}
+		return nil, resp
+	}
+	return created, nil
+}
+
+// AttachmentFile returns the content of an attachment of the hold with its record.
+func (s *QCHoldsService) AttachmentFile(id, tenantID, attachmentID string) ([]byte, *database.QCAttachment, *responses.InternalResponse) {
+	attachment, resp := s.Repository.GetAttachment(id, tenantID, attachmentID)
+	if resp != nil {
+		return nil, nil, resp
+	}
+	data, _, err := s.Storage.Get(context.Background(), attachment.StorageKey)
+	if errors.Is(err, tools.ErrDocumentNotFound) {
+		return nil, nil, &responses.InternalResponse{Message: "Adjunto no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	if err != nil {
+		return nil, nil, &responses.InternalResponse{Error: err, Message: "Error al leer el adjunto"}
+	}
+	return data, attachment, nil
+}
+
+func (s *QCHoldsService) ReleaseHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.ReleaseHold(id, tenantID, userID, req)
+}
+
+func (s *QCHoldsService) RejectHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	req.Notes = trimmedOrNil(req.Notes)
+	return s.Repository.RejectHold(id, tenantID, userID, req)
+}
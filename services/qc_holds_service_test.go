@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockQCHoldsRepo struct {
+	created    *requests.CreateQCHoldRequest
+	inspection *requests.RecordQCInspectionRequest
+	attachment *database.QCAttachment
+	addResp    *responses.InternalResponse
+	released   *requests.QCDecisionRequest
+}
+
+func (m *mockQCHoldsRepo) ListHolds(tenantID string, status, scope, sku *string, limit, offset int) ([]database.QCHold, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockQCHoldsRepo) GetHold(id, tenantID string) (*responses.QCHoldView, *responses.InternalResponse) {
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id}}, nil
+}
+func (m *mockQCHoldsRepo) CreateHold(tenantID, userID string, req *requests.CreateQCHoldRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	m.created = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: "h1", Scope: req.Scope, Status: database.QCHoldQuarantine}}, nil
+}
+func (m *mockQCHoldsRepo) RecordInspection(id, tenantID, userID string, req *requests.RecordQCInspectionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	m.inspection = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id}}, nil
+}
+func (m *mockQCHoldsRepo) AddAttachment(id, tenantID string, attachment *database.QCAttachment) (*database.QCAttachment, *responses.InternalResponse) {
+	if m.addResp != nil {
+		return nil, m.addResp
+	}
+	attachment.ID = "a1"
+	attachment.HoldID = id
+	m.attachment = attachment
+	return attachment, nil
+}
+func (m *mockQCHoldsRepo) GetAttachment(id, tenantID, attachmentID string) (*database.QCAttachment, *responses.InternalResponse) {
+	if m.attachment == nil || m.attachment.ID != attachmentID {
+		return nil, &responses.InternalResponse{Message: "no encontrado", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.attachment, nil
+}
+func (m *mockQCHoldsRepo) ReleaseHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	m.released = req
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id, Status: database.QCHoldReleased}}, nil
+}
+func (m *mockQCHoldsRepo) RejectHold(id, tenantID, userID string, req *requests.QCDecisionRequest) (*responses.QCHoldView, *responses.InternalResponse) {
+	return &responses.QCHoldView{QCHold: database.QCHold{ID: id, Status: database.QCHoldRejected}}, nil
+}
+
+func TestQCHoldsService_CreateHold_ScopeFields(t *testing.T) {
+	for name, req := range map[string]*requests.CreateQCHoldRequest{
+		"lot without lot":          {Scope: "lot", SKU: "A", Reason: "r"},
+		"lot with qty":             {Scope: "lot", SKU: "A", Reason: "r", LotNumber: tools.StrPtr("L1"), Qty: tools.Float64Ptr(1)},
+		"lot with location":        {Scope: "lot", SKU: "A", Reason: "r", LotNumber: tools.StrPtr("L1"), Location: tools.StrPtr("A-01")},
+		"inventory without qty":    {Scope: "inventory", SKU: "A", Reason: "r", Location: tools.StrPtr("A-01")},
+		"inventory blank location": {Scope: "inventory", SKU: "A", Reason: "r", Location: tools.StrPtr(" "), Qty: tools.Float64Ptr(1)},
+		"blank reason":             {Scope: "lot", SKU: "A", Reason: "  ", LotNumber: tools.StrPtr("L1")},
+		"unknown scope":            {Scope: "pallet", SKU: "A", Reason: "r"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockQCHoldsRepo{}
+			_, resp := NewQCHoldsService(repo, nil).CreateHold("tenant-1", "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestQCHoldsService_CreateHold_TrimsAndDelegates(t *testing.T) {
+	repo := &mockQCHoldsRepo{}
+	_, resp := NewQCHoldsService(repo, nil).CreateHold("tenant-1", "user-1", &requests.CreateQCHoldRequest{
+		Scope: "inventory", SKU: " SKU-1 ", Reason: " damaged ", Location: tools.StrPtr(" A-01 "), Qty: tools.Float64Ptr(2), LotNumber: tools.StrPtr(" "),
+	})
+	require.Nil(t, resp)
+	require.NotNil(t, repo.created)
+	assert.Equal(t, "SKU-1", repo.created.SKU)
+	assert.Equal(t, "damaged", repo.created.Reason)
+	assert.Equal(t, "A-01", *repo.created.Location)
+	assert.Nil(t, repo.created.LotNumber)
+}
+
+func TestQCHoldsService_RecordInspection(t *testing.T) {
+	svc := NewQCHoldsService(&mockQCHoldsRepo{}, nil)
+	future := time.Now().Add(time.Hour)
+
+	for name, req := range map[string]*requests.RecordQCInspectionRequest{
+		"array measurements":   {Result: "pass", Measurements: json.RawMessage(`[1,2]`)},
+		"invalid measurements": {Result: "pass", Measurements: json.RawMessage(`{"ph":`)},
+		"future date":          {Result: "fail", InspectedAt: &future},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, resp := svc.RecordInspection("h1", "tenant-1", "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+		})
+	}
+
+	repo := &mockQCHoldsRepo{}
+	_, resp := NewQCHoldsService(repo, nil).RecordInspection("h1", "tenant-1", "user-1", &requests.RecordQCInspectionRequest{
+		Result: "conditional", Measurements: json.RawMessage(` null `), Notes: tools.StrPtr("  "),
+	})
+	require.Nil(t, resp)
+	assert.Nil(t, repo.inspection.Measurements)
+	assert.Nil(t, repo.inspection.Notes)
+
+	_, resp = NewQCHoldsService(repo, nil).RecordInspection("h1", "tenant-1", "user-1", &requests.RecordQCInspectionRequest{
+		Result: "pass", Measurements: json.RawMessage(`{"ph": 6.8}`),
+	})
+	require.Nil(t, resp)
+	assert.JSONEq(t, `{"ph": 6.8}`, string(repo.inspection.Measurements))
+}
+
+func TestQCHoldsService_AddAttachment(t *testing.T) {
+	storage := tools.NewLocalDocumentStorage(t.TempDir())
+	repo := &mockQCHoldsRepo{}
+	svc := NewQCHoldsService(repo, storage)
+	pdf := []byte("%PDF-1.4\n% certificate of analysis\n")
+
+	att, resp := svc.AddAttachment("h1", "tenant-1", "user-1", `../coa "v2".pdf`, tools.StrPtr(" "), pdf)
+	require.Nil(t, resp)
+	assert.Equal(t, "coa v2.pdf", att.FileName)
+	assert.Equal(t, "application/pdf", att.ContentType)
+	assert.Equal(t, int64(len(pdf)), att.SizeBytes)
+	assert.Nil(t, att.InspectionID)
+	assert.NotEmpty(t, att.SHA256)
+
+	data, got, resp := svc.AttachmentFile("h1", "tenant-1", att.ID)
+	require.Nil(t, resp)
+	assert.Equal(t, pdf, data)
+	assert.Equal(t, att.StorageKey, got.StorageKey)
+}
+
+func TestQCHoldsService_AddAttachment_Invalid(t *testing.T) {
+	svc := NewQCHoldsService(&mockQCHoldsRepo{}, tools.NewLocalDocumentStorage(t.TempDir()))
+
+	_, resp := svc.AddAttachment("h1", "tenant-1", "user-1", "empty.pdf", nil, nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+
+	_, resp = svc.AddAttachment("h1", "tenant-1", "user-1", "notes.txt", nil, []byte("plain text notes"))
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+
+	big := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("x"), MaxQCAttachmentBytes)...)
+	_, resp = svc.AddAttachment("h1", "tenant-1", "user-1", "big.pdf", nil, big)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+}
+
+func TestQCHoldsService_AddAttachment_RemovesFileWhenRefused(t *testing.T) {
+	dir := t.TempDir()
+	storage := tools.NewLocalDocumentStorage(dir)
+	repo := &mockQCHoldsRepo{addResp: &responses.InternalResponse{Message: "no encontrada", Handled: true, StatusCode: responses.StatusNotFound}}
+	svc := NewQCHoldsService(repo, storage)
+
+	_, resp := svc.AddAttachment("h1", "tenant-1", "user-1", "coa.pdf", nil, []byte("%PDF-1.4\n"))
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+
+	_, _, err := storage.Get(t.Context(), tools.TenantDocumentKey("tenant-1", "qc", "h1"))
+	assert.Error(t, err)
+}
+
+func TestQCHoldsService_ReleaseHold_TrimsNotes(t *testing.T) {
+	repo := &mockQCHoldsRepo{}
+	view, resp := NewQCHoldsService(repo, nil).ReleaseHold("h1", "tenant-1", "user-1", &requests.QCDecisionRequest{Notes: tools.StrPtr("  ")})
+	require.Nil(t, resp)
+	assert.Equal(t, database.QCHoldReleased, view.Status)
+	assert.Nil(t, repo.released.Notes)
+}
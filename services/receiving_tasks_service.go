@@ -69,16 +69,19 @@ func (s *ReceivingTasksService) ExportReceivingTaskToExcel(tenantID string) ([]b
 	return s.Repository.ExportReceivingTaskToExcel(tenantID)
 }
 
-func (s *ReceivingTasksService) CompleteFullTask(id string, location, userId string) *responses.InternalResponse {
-	return s.Repository.CompleteFullTask(id, location, userId)
+// CompleteFullTask receives all pending lines. override is nil for regular completions; a
+// supervisor override lets counts above the tenant's over-receipt allowance through.
+func (s *ReceivingTasksService) CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse {
+	return s.Repository.CompleteFullTask(id, location, userId, override)
 }
 
 // CompleteReceivingLine applies R1 backfill logic before delegating to the repository.
 // If accepted_qty and rejected_qty are both nil/0 but received_qty > 0, accepted_qty is backfilled
 // from received_qty to preserve backward compatibility with legacy callers.
-func (s *ReceivingTasksService) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+// override works as in CompleteFullTask.
+func (s *ReceivingTasksService) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	item = applyAcceptedRejectedBackfill(item)
-	return s.Repository.CompleteReceivingLine(id, location, userId, item)
+	return s.Repository.CompleteReceivingLine(id, location, userId, item, override)
 }
 
 func (s *ReceivingTasksService) GenerateImportTemplate(language string) ([]byte, error) {
@@ -68,11 +68,11 @@ func (m *mockReceivingTasksRepo) ExportReceivingTaskToExcel(_ string) ([]byte, *
 	return m.exportBytes, m.exportErr
 }
 
-func (m *mockReceivingTasksRepo) CompleteFullTask(id string, location, userId string) *responses.InternalResponse {
+func (m *mockReceivingTasksRepo) CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	return m.completeTaskErr
 }
 
-func (m *mockReceivingTasksRepo) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+func (m *mockReceivingTasksRepo) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	return m.completeLineErr
 }
 
@@ -235,7 +235,7 @@ func TestReceivingTasksService_ExportReceivingTaskToExcel_Error(t *testing.T) {
 func TestReceivingTasksService_CompleteFullTask_Success(t *testing.T) {
 	repo := &mockReceivingTasksRepo{}
 	svc := NewReceivingTasksService(repo)
-	errResp := svc.CompleteFullTask("1", "LOC-A", "user-1")
+	errResp := svc.CompleteFullTask("1", "LOC-A", "user-1", nil)
 	require.Nil(t, errResp)
 }
 
@@ -248,7 +248,7 @@ func TestReceivingTasksService_CompleteFullTask_Error(t *testing.T) {
 		},
 	}
 	svc := NewReceivingTasksService(repo)
-	errResp := svc.CompleteFullTask("1", "LOC-A", "user-1")
+	errResp := svc.CompleteFullTask("1", "LOC-A", "user-1", nil)
 	require.NotNil(t, errResp)
 	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
 }
@@ -261,7 +261,7 @@ func TestReceivingTasksService_CompleteReceivingLine_Success(t *testing.T) {
 		ExpectedQuantity: 10,
 		Location:         "LOC-A",
 	}
-	errResp := svc.CompleteReceivingLine("1", "LOC-A", "user-1", item)
+	errResp := svc.CompleteReceivingLine("1", "LOC-A", "user-1", item, nil)
 	require.Nil(t, errResp)
 }
 
@@ -275,7 +275,7 @@ func TestReceivingTasksService_CompleteReceivingLine_Error(t *testing.T) {
 	}
 	svc := NewReceivingTasksService(repo)
 	item := requests.ReceivingTaskItemRequest{SKU: "SKU-001", Location: "LOC-A"}
-	errResp := svc.CompleteReceivingLine("99", "LOC-A", "user-1", item)
+	errResp := svc.CompleteReceivingLine("99", "LOC-A", "user-1", item, nil)
 	require.NotNil(t, errResp)
 	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
 }
@@ -347,7 +347,7 @@ func TestReceivingTasksService_CompleteReceivingLine_LegacyBackfill(t *testing.T
 		Location:         "LOC-A",
 		ReceivedQuantity: &rv,
 	}
-	errResp := svc.CompleteReceivingLine("task-1", "LOC-A", "user-1", item)
+	errResp := svc.CompleteReceivingLine("task-1", "LOC-A", "user-1", item, nil)
 	require.Nil(t, errResp)
 	require.NotNil(t, calledWithItem.AcceptedQty)
 	assert.Equal(t, float64(20), *calledWithItem.AcceptedQty)
@@ -433,10 +433,10 @@ func (m *mockReceivingTasksRepoCapture) ImportReceivingTaskFromExcel(userID stri
 func (m *mockReceivingTasksRepoCapture) ExportReceivingTaskToExcel(_ string) ([]byte, *responses.InternalResponse) {
 	return nil, nil
 }
-func (m *mockReceivingTasksRepoCapture) CompleteFullTask(id string, location, userId string) *responses.InternalResponse {
+func (m *mockReceivingTasksRepoCapture) CompleteFullTask(id string, location, userId string, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	return nil
 }
-func (m *mockReceivingTasksRepoCapture) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest) *responses.InternalResponse {
+func (m *mockReceivingTasksRepoCapture) CompleteReceivingLine(id string, location, userId string, item requests.ReceivingTaskItemRequest, override *requests.OverReceiptOverride) *responses.InternalResponse {
 	*m.captured = item
 	return nil
 }
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/dto"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/rs/zerolog/log"
+)
+
+// replenishmentLookbackDays is the outbound movement window used to estimate daily demand.
+const replenishmentLookbackDays = 30
+
+// ReplenishmentService computes reorder suggestions from article reorder policies and the
+// current stock position, and turns them into draft purchase orders grouped by supplier.
+// Automatic (cron) creation only happens when StockSetting.AutoCreateMaterialRequest is on.
+type ReplenishmentService struct {
+	Repository     ports.ReplenishmentRepository
+	PurchaseOrders ports.PurchaseOrdersRepository
+	StockSettings  ports.StockSettingsRepository
+}
+
+func NewReplenishmentService(repo ports.ReplenishmentRepository, poRepo ports.PurchaseOrdersRepository, settingsRepo ports.StockSettingsRepository) *ReplenishmentService {
+	return &ReplenishmentService{
+		Repository:     repo,
+		PurchaseOrders: poRepo,
+		StockSettings:  settingsRepo,
+	}
+}
+
+// suggestReorder decides whether an article needs to be reordered and how much.
+// The article is reordered when its projected stock (on hand − reserved + open PO) is at or
+// below the reorder point (safety stock + demand during the supplier lead time, never below
+// min_quantity). The order brings projected stock up to max_quantity (or the reorder point
+// when no max is set), rounded up to whole units and to at least min_order_qty.
+func suggestReorder(c dto.ReplenishmentCandidate) (responses.ReplenishmentSuggestion, bool) {
+	leadTime := 0
+	if c.LeadTimeDays != nil && *c.LeadTimeDays > 0 {
+		leadTime = *c.LeadTimeDays
+	}
+
+	reorderPoint := c.SafetyStock + c.DailyDemand*float64(leadTime)
+	if c.MinQuantity != nil && float64(*c.MinQuantity) > reorderPoint {
+		reorderPoint = float64(*c.MinQuantity)
+	}
+	target := reorderPoint
+	if c.MaxQuantity != nil && float64(*c.MaxQuantity) > target {
+		target = float64(*c.MaxQuantity)
+	}
+
+	projected := c.OnHand - c.Reserved + c.OpenPOQty
+	s := responses.ReplenishmentSuggestion{
+		SKU:          c.SKU,
+		Name:         c.Name,
+		OnHand:       c.OnHand,
+		Reserved:     c.Reserved,
+		OpenPOQty:    c.OpenPOQty,
+		Projected:    projected,
+		DailyDemand:  c.DailyDemand,
+		ReorderPoint: reorderPoint,
+		TargetQty:    target,
+		SupplierID:   c.SupplierID,
+		SupplierName: c.SupplierName,
+		LeadTimeDays: leadTime,
+		UnitCost:     c.UnitCost,
+	}
+	if projected > reorderPoint {
+		return s, false
+	}
+
+	qty := math.Ceil(target - projected)
+	if qty <= 0 {
+		return s, false
+	}
+	if c.MinOrderQty > 0 && qty < c.MinOrderQty {
+		qty = math.Ceil(c.MinOrderQty)
+	}
+	s.SuggestedQty = qty
+	return s, true
+}
+
+// groupSuggestions splits suggestions into one group per supplier (sorted by supplier id)
+// and the list of articles without supplier.
+func groupSuggestions(suggestions []responses.ReplenishmentSuggestion) ([]responses.ReplenishmentSupplierGroup, []responses.ReplenishmentSuggestion) {
+	bySupplier := make(map[string]*responses.ReplenishmentSupplierGroup)
+	unassigned := make([]responses.ReplenishmentSuggestion, 0)
+
+	for _, s := range suggestions {
+		if s.SupplierID == nil || *s.SupplierID == "" {
+			unassigned = append(unassigned, s)
+			continue
+		}
+		g, ok := bySupplier[*s.SupplierID]
+		if !ok {
+			g = &responses.ReplenishmentSupplierGroup{SupplierID: *s.SupplierID, SupplierName: s.SupplierName}
+			bySupplier[*s.SupplierID] = g
+		}
+		g.Items = append(g.Items, s)
+		if s.LeadTimeDays > g.LeadTimeDays {
+			g.LeadTimeDays = s.LeadTimeDays
+		}
+		if s.UnitCost != nil {
+			g.EstimatedTotal += s.SuggestedQty * *s.UnitCost
+		}
+	}
+
+	groups := make([]responses.ReplenishmentSupplierGroup, 0, len(bySupplier))
+	for _, g := range bySupplier {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].SupplierID < groups[j].SupplierID })
+	return groups, unassigned
+}
+
+// autoCreateEnabled reads StockSetting.AutoCreateMaterialRequest (false when settings are unavailable).
+func (s *ReplenishmentService) autoCreateEnabled(tenantID string) (bool, *responses.InternalResponse) {
+	if s.StockSettings == nil {
+		return false, nil
+	}
+	settings, resp := s.StockSettings.GetOrCreate(tenantID)
+	if resp != nil {
+		return false, resp
+	}
+	return settings != nil && settings.AutoCreateMaterialRequest, nil
+}
+
+// Preview returns the current reorder suggestions grouped by supplier without creating anything.
+func (s *ReplenishmentService) Preview(tenantID string) (*responses.ReplenishmentPreview, *responses.InternalResponse) {
+	candidates, resp := s.Repository.ListCandidates(tenantID, replenishmentLookbackDays)
+	if resp != nil {
+		return nil, resp
+	}
+
+	suggestions := make([]responses.ReplenishmentSuggestion, 0)
+	for _, c := range candidates {
+		if sug, ok := suggestReorder(c); ok {
+			suggestions = append(suggestions, sug)
+		}
+	}
+
+	autoCreate, resp := s.autoCreateEnabled(tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	groups, unassigned := groupSuggestions(suggestions)
+	return &responses.ReplenishmentPreview{
+		AutoCreateEnabled: autoCreate,
+		Groups:            groups,
+		Unassigned:        unassigned,
+	}, nil
+}
+
+// GenerateDraftPOs creates one draft PO per supplier group. createdBy may be empty for
+// system-generated (cron) orders. Each PO is created in its own transaction: if one fails,
+// the POs already created are kept and the error is returned.
+func (s *ReplenishmentService) GenerateDraftPOs(tenantID, createdBy string) ([]responses.PurchaseOrderView, *responses.InternalResponse) {
+	preview, resp := s.Preview(tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	created := make([]responses.PurchaseOrderView, 0, len(preview.Groups))
+	for _, g := range preview.Groups {
+		items := make([]requests.CreatePurchaseOrderItemRequest, 0, len(g.Items))
+		for _, it := range g.Items {
+			items = append(items, requests.CreatePurchaseOrderItemRequest{
+				ArticleSKU:  it.SKU,
+				ExpectedQty: it.SuggestedQty,
+				UnitCost:    it.UnitCost,
+			})
+		}
+
+		expected := tools.GetCurrentTime().Add(time.Duration(g.LeadTimeDays) * 24 * time.Hour)
+		notes := "Generada automáticamente por reabastecimiento"
+		po, resp := s.PurchaseOrders.Create(tenantID, createdBy, &requests.CreatePurchaseOrderRequest{
+			SupplierID:   g.SupplierID,
+			ExpectedDate: &expected,
+			Notes:        &notes,
+			Items:        items,
+		})
+		if resp != nil {
+			return created, resp
+		}
+		created = append(created, *po)
+	}
+	return created, nil
+}
+
+// RunScheduled is the cron entry point for one tenant: drafts POs only when the tenant has
+// AutoCreateMaterialRequest enabled.
+func (s *ReplenishmentService) RunScheduled(tenantID string) error {
+	enabled, resp := s.autoCreateEnabled(tenantID)
+	if resp != nil {
+		return fmt.Errorf("replenishment: read stock settings: %s", resp.Message)
+	}
+	if !enabled {
+		return nil
+	}
+
+	created, resp := s.GenerateDraftPOs(tenantID, "")
+	if resp != nil {
+		if resp.Error != nil {
+			return resp.Error
+		}
+		return fmt.Errorf("replenishment: %s", resp.Message)
+	}
+	if len(created) > 0 {
+		log.Info().Str("tenant_id", tenantID).Int("purchase_orders", len(created)).Msg("cron: replenishment drafted purchase orders")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/dto"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mocks
+// ─────────────────────────────────────────────────────────────────────────────
+
+type mockReplenishmentRepo struct {
+	candidates []dto.ReplenishmentCandidate
+}
+
+func (m *mockReplenishmentRepo) ListCandidates(tenantID string, lookbackDays int) ([]dto.ReplenishmentCandidate, *responses.InternalResponse) {
+	return m.candidates, nil
+}
+
+type mockReplenishmentSettingsRepo struct {
+	autoCreate bool
+}
+
+func (m *mockReplenishmentSettingsRepo) GetOrCreate(tenantID string) (*database.StockSetting, *responses.InternalResponse) {
+	return &database.StockSetting{TenantID: tenantID, AutoCreateMaterialRequest: m.autoCreate}, nil
+}
+
+func (m *mockReplenishmentSettingsRepo) Upsert(tenantID string, data *requests.UpdateStockSettingsRequest) (*database.StockSetting, *responses.InternalResponse) {
+	return nil, nil
+}
+
+// recordingPORepo records the draft POs created by the replenishment service.
+type recordingPORepo struct {
+	mockPORepo
+	createdBy []string
+	requests  []requests.CreatePurchaseOrderRequest
+}
+
+func (m *recordingPORepo) Create(tenantID, createdBy string, req *requests.CreatePurchaseOrderRequest) (*responses.PurchaseOrderView, *responses.InternalResponse) {
+	m.createdBy = append(m.createdBy, createdBy)
+	m.requests = append(m.requests, *req)
+	return &responses.PurchaseOrderView{ID: "po-new", SupplierID: req.SupplierID, Status: "draft"}, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ─────────────────────────────────────────────────────────────────────────────
+// suggestReorder
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestSuggestReorder(t *testing.T) {
+	tests := []struct {
+		name      string
+		c         dto.ReplenishmentCandidate
+		wantOK    bool
+		wantQty   float64
+		wantPoint float64
+	}{
+		{
+			name:   "above reorder point",
+			c:      dto.ReplenishmentCandidate{SKU: "A", SafetyStock: 10, OnHand: 50},
+			wantOK: false, wantPoint: 10,
+		},
+		{
+			name:   "below min, order up to max",
+			c:      dto.ReplenishmentCandidate{SKU: "A", MinQuantity: tools.IntToPtr(20), MaxQuantity: tools.IntToPtr(100), OnHand: 15},
+			wantOK: true, wantQty: 85, wantPoint: 20,
+		},
+		{
+			name:   "reserved stock counts against available",
+			c:      dto.ReplenishmentCandidate{SKU: "A", MinQuantity: tools.IntToPtr(20), MaxQuantity: tools.IntToPtr(100), OnHand: 40, Reserved: 25},
+			wantOK: true, wantQty: 85, wantPoint: 20,
+		},
+		{
+			name:   "open PO covers the need",
+			c:      dto.ReplenishmentCandidate{SKU: "A", MinQuantity: tools.IntToPtr(20), MaxQuantity: tools.IntToPtr(100), OnHand: 5, OpenPOQty: 90},
+			wantOK: false, wantPoint: 20,
+		},
+		{
+			name:   "lead time demand raises reorder point",
+			c:      dto.ReplenishmentCandidate{SKU: "A", SafetyStock: 10, DailyDemand: 2.5, LeadTimeDays: tools.IntToPtr(4), OnHand: 18},
+			wantOK: true, wantQty: 2, wantPoint: 20,
+		},
+		{
+			name:   "min order qty rounds the order up",
+			c:      dto.ReplenishmentCandidate{SKU: "A", MinQuantity: tools.IntToPtr(10), OnHand: 7, MinOrderQty: 24},
+			wantOK: true, wantQty: 24, wantPoint: 10,
+		},
+		{
+			name:   "fractional need rounds up to whole units",
+			c:      dto.ReplenishmentCandidate{SKU: "A", SafetyStock: 10.2, OnHand: 10},
+			wantOK: true, wantQty: 1, wantPoint: 10.2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := suggestReorder(tt.c)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.wantPoint, s.ReorderPoint, 0.0001)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantQty, s.SuggestedQty)
+			}
+		})
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Preview / Generate / RunScheduled
+// ─────────────────────────────────────────────────────────────────────────────
+
+func replenishmentCandidates() []dto.ReplenishmentCandidate {
+	return []dto.ReplenishmentCandidate{
+		{SKU: "SKU-1", MinQuantity: tools.IntToPtr(10), MaxQuantity: tools.IntToPtr(50), OnHand: 5, SupplierID: tools.StrPtr("sup-b"), LeadTimeDays: tools.IntToPtr(3), UnitCost: tools.Float64Ptr(2)},
+		{SKU: "SKU-2", MinQuantity: tools.IntToPtr(10), MaxQuantity: tools.IntToPtr(20), OnHand: 0, SupplierID: tools.StrPtr("sup-a"), LeadTimeDays: tools.IntToPtr(7), UnitCost: tools.Float64Ptr(1.5)},
+		{SKU: "SKU-3", MinQuantity: tools.IntToPtr(10), MaxQuantity: tools.IntToPtr(30), OnHand: 0, SupplierID: tools.StrPtr("sup-b"), LeadTimeDays: tools.IntToPtr(5)},
+		{SKU: "SKU-4", MinQuantity: tools.IntToPtr(10), OnHand: 2}, // no supplier
+		{SKU: "SKU-5", MinQuantity: tools.IntToPtr(10), OnHand: 100, SupplierID: tools.StrPtr("sup-a")},
+	}
+}
+
+func TestReplenishmentService_Preview_GroupsBySupplier(t *testing.T) {
+	svc := NewReplenishmentService(&mockReplenishmentRepo{candidates: replenishmentCandidates()}, &recordingPORepo{}, &mockReplenishmentSettingsRepo{autoCreate: true})
+
+	preview, resp := svc.Preview(ccTenant)
+	require.Nil(t, resp)
+	assert.True(t, preview.AutoCreateEnabled)
+
+	require.Len(t, preview.Groups, 2)
+	assert.Equal(t, "sup-a", preview.Groups[0].SupplierID)
+	require.Len(t, preview.Groups[0].Items, 1)
+	assert.Equal(t, "SKU-2", preview.Groups[0].Items[0].SKU)
+	assert.InDelta(t, 30.0, preview.Groups[0].EstimatedTotal, 0.0001)
+
+	assert.Equal(t, "sup-b", preview.Groups[1].SupplierID)
+	assert.Len(t, preview.Groups[1].Items, 2)
+	assert.Equal(t, 5, preview.Groups[1].LeadTimeDays)
+
+	require.Len(t, preview.Unassigned, 1)
+	assert.Equal(t, "SKU-4", preview.Unassigned[0].SKU)
+}
+
+func TestReplenishmentService_GenerateDraftPOs_OnePOPerSupplier(t *testing.T) {
+	poRepo := &recordingPORepo{}
+	svc := NewReplenishmentService(&mockReplenishmentRepo{candidates: replenishmentCandidates()}, poRepo, nil)
+
+	pos, resp := svc.GenerateDraftPOs(ccTenant, "user-1")
+	require.Nil(t, resp)
+	assert.Len(t, pos, 2)
+	require.Len(t, poRepo.requests, 2)
+
+	first := poRepo.requests[0]
+	assert.Equal(t, "sup-a", first.SupplierID)
+	require.Len(t, first.Items, 1)
+	assert.Equal(t, 20.0, first.Items[0].ExpectedQty)
+	require.NotNil(t, first.ExpectedDate)
+	assert.Equal(t, []string{"user-1", "user-1"}, poRepo.createdBy)
+}
+
+func TestReplenishmentService_RunScheduled_HonorsAutoCreateSetting(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		poRepo := &recordingPORepo{}
+		svc := NewReplenishmentService(&mockReplenishmentRepo{candidates: replenishmentCandidates()}, poRepo, &mockReplenishmentSettingsRepo{autoCreate: false})
+		require.NoError(t, svc.RunScheduled(ccTenant))
+		assert.Empty(t, poRepo.requests)
+	})
+
+	t.Run("no settings repository", func(t *testing.T) {
+		poRepo := &recordingPORepo{}
+		svc := NewReplenishmentService(&mockReplenishmentRepo{candidates: replenishmentCandidates()}, poRepo, nil)
+		require.NoError(t, svc.RunScheduled(ccTenant))
+		assert.Empty(t, poRepo.requests)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		poRepo := &recordingPORepo{}
+		svc := NewReplenishmentService(&mockReplenishmentRepo{candidates: replenishmentCandidates()}, poRepo, &mockReplenishmentSettingsRepo{autoCreate: true})
+		require.NoError(t, svc.RunScheduled(ccTenant))
+		assert.Len(t, poRepo.requests, 2)
+		assert.Equal(t, []string{"", ""}, poRepo.createdBy, "cron POs are system-generated")
+	})
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/rs/zerolog/log"
 )
 
 // SalesOrdersService implements business logic for sales orders.
 type SalesOrdersService struct {
 	Repository     ports.SalesOrdersRepository
-	ClientsService clientLookup // optional: validate customer_id
+	ClientsService clientLookup                  // optional: validate customer_id
+	ExchangeRates  ports.ExchangeRatesRepository // optional: enables order totals in GetByIDWithTotals
 }
 
 func NewSalesOrdersService(repo ports.SalesOrdersRepository) *SalesOrdersService {
@@ -85,6 +87,40 @@ func (s *SalesOrdersService) GetByID(id, tenantID string) (*responses.SalesOrder
 	return s.Repository.GetByID(id, tenantID)
 }
 
+// GetByIDWithTotals is GetByID plus Totals: expected qty × unit price of every line in the
+// tenant base currency at the rates of the order date and, when currency is given, in that
+// currency too. Without a requested currency a missing rate only drops the totals; with one
+// it is returned as an error.
+func (s *SalesOrdersService) GetByIDWithTotals(id, tenantID, currency string) (*responses.SalesOrderResponse, *responses.InternalResponse) {
+	so, resp := s.Repository.GetByID(id, tenantID)
+	if resp != nil {
+		return nil, resp
+	}
+	if s.ExchangeRates == nil {
+		if normalizeCurrency(currency) != "" {
+			return nil, currencyUnavailable()
+		}
+		return so, nil
+	}
+
+	amounts := make([]currencyAmount, 0, len(so.Items))
+	for _, it := range so.Items {
+		if it.UnitPrice != nil {
+			amounts = append(amounts, currencyAmount{Amount: it.ExpectedQty * *it.UnitPrice, Currency: it.Currency})
+		}
+	}
+	totals, resp := orderTotals(s.ExchangeRates, tenantID, so.CreatedAt, amounts, currency)
+	if resp != nil {
+		if normalizeCurrency(currency) != "" {
+			return nil, resp
+		}
+		log.Warn().Str("sales_order_id", id).Str("reason", resp.Message).Msg("sales order totals unavailable")
+		return so, nil
+	}
+	so.Totals = totals
+	return so, nil
+}
+
 func (s *SalesOrdersService) Update(id, tenantID string, req *requests.UpdateSalesOrderRequest) (*responses.SalesOrderResponse, *responses.InternalResponse) {
 	if req.CustomerID != nil {
 		if resp := s.validateCustomer(*req.CustomerID); resp != nil {
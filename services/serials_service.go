@@ -9,8 +9,11 @@ import (
 
 // SerialsService is a thin pass-through to the tenant-aware repository.
 // S3.5 W2-A: every method now requires tenantID.
+// Lifecycle serves the status transitions and the chain of custody; the status itself is
+// never written through UpdateSerial.
 type SerialsService struct {
 	Repository ports.SerialsRepository
+	Lifecycle  ports.SerialLifecycleRepository
 }
 
 func NewSerialsService(repo ports.SerialsRepository) *SerialsService {
@@ -29,10 +32,29 @@ func (s *SerialsService) Create(tenantID string, data *requests.CreateSerialRequ
 	return s.Repository.CreateSerial(tenantID, data)
 }
 
+// UpdateSerial applies a partial update. The status follows the serial state machine, so it
+// only changes through TransitionSerial and the documents that move the unit.
 func (s *SerialsService) UpdateSerial(tenantID, id string, data map[string]interface{}) *responses.InternalResponse {
+	if _, ok := data["status"]; ok {
+		return &responses.InternalResponse{
+			Message:    "El estado de una serie se cambia con POST /serials/:id/transition",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
 	return s.Repository.UpdateSerial(tenantID, id, data)
 }
 
 func (s *SerialsService) Delete(tenantID, id string) *responses.InternalResponse {
 	return s.Repository.DeleteSerial(tenantID, id)
 }
+
+// TransitionSerial moves a serial by hand to another status of its lifecycle.
+func (s *SerialsService) TransitionSerial(tenantID, id, userID string, req *requests.SerialTransitionRequest) (*database.Serial, *responses.InternalResponse) {
+	return s.Lifecycle.TransitionSerial(tenantID, id, userID, req)
+}
+
+// GetChainOfCustody returns a serial with its full history and the customer it shipped to.
+func (s *SerialsService) GetChainOfCustody(tenantID, serialNumber string) (*responses.SerialCustodyView, *responses.InternalResponse) {
+	return s.Lifecycle.GetChainOfCustody(tenantID, serialNumber)
+}
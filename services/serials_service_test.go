@@ -201,10 +201,19 @@ func TestSerialsService_Create_Conflict(t *testing.T) {
 func TestSerialsService_UpdateSerial_Success(t *testing.T) {
 	repo := &mockSerialsRepo{}
 	svc := NewSerialsService(repo)
-	errResp := svc.UpdateSerial(testTenantA, "s1", map[string]interface{}{"status": "used"})
+	errResp := svc.UpdateSerial(testTenantA, "s1", map[string]interface{}{"sku": "SKU-B"})
 	require.Nil(t, errResp)
 }
 
+func TestSerialsService_UpdateSerial_RejectsStatus(t *testing.T) {
+	repo := &mockSerialsRepo{}
+	svc := NewSerialsService(repo)
+	errResp := svc.UpdateSerial(testTenantA, "s1", map[string]interface{}{"status": database.SerialScrapped})
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusBadRequest, errResp.StatusCode)
+	assert.Empty(t, repo.gotTenantIDs, "the repository must not be called")
+}
+
 func TestSerialsService_UpdateSerial_NotFound(t *testing.T) {
 	repo := &mockSerialsRepo{
 		updateErr: &responses.InternalResponse{
@@ -214,7 +223,7 @@ func TestSerialsService_UpdateSerial_NotFound(t *testing.T) {
 		},
 	}
 	svc := NewSerialsService(repo)
-	errResp := svc.UpdateSerial(testTenantA, "missing", map[string]interface{}{"status": "used"})
+	errResp := svc.UpdateSerial(testTenantA, "missing", map[string]interface{}{"sku": "SKU-B"})
 	require.NotNil(t, errResp)
 	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
 }
@@ -239,3 +248,51 @@ func TestSerialsService_Delete_Error(t *testing.T) {
 	require.NotNil(t, errResp)
 	assert.False(t, errResp.Handled)
 }
+
+// mockSerialLifecycleRepo records the transition it receives and returns a fixed custody view.
+type mockSerialLifecycleRepo struct {
+	gotUserID string
+	gotReq    *requests.SerialTransitionRequest
+	custody   *responses.SerialCustodyView
+}
+
+func (m *mockSerialLifecycleRepo) TransitionSerial(tenantID, id, userID string, req *requests.SerialTransitionRequest) (*database.Serial, *responses.InternalResponse) {
+	m.gotUserID = userID
+	m.gotReq = req
+	return &database.Serial{ID: id, TenantID: tenantID, Status: req.Status}, nil
+}
+
+func (m *mockSerialLifecycleRepo) GetChainOfCustody(tenantID, serialNumber string) (*responses.SerialCustodyView, *responses.InternalResponse) {
+	if m.custody == nil || m.custody.SerialNumber != serialNumber {
+		return nil, &responses.InternalResponse{Message: "Serie no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.custody, nil
+}
+
+func TestSerialsService_TransitionSerial_ForwardsToLifecycle(t *testing.T) {
+	lifecycle := &mockSerialLifecycleRepo{}
+	svc := NewSerialsService(&mockSerialsRepo{})
+	svc.Lifecycle = lifecycle
+
+	serial, errResp := svc.TransitionSerial(testTenantA, "s1", "u1", &requests.SerialTransitionRequest{Status: database.SerialScrapped})
+	require.Nil(t, errResp)
+	require.NotNil(t, serial)
+	assert.Equal(t, database.SerialScrapped, serial.Status)
+	assert.Equal(t, "u1", lifecycle.gotUserID)
+}
+
+func TestSerialsService_GetChainOfCustody(t *testing.T) {
+	lifecycle := &mockSerialLifecycleRepo{custody: &responses.SerialCustodyView{
+		Serial: database.Serial{ID: "s1", SerialNumber: "SN-001", Status: database.SerialShipped},
+	}}
+	svc := NewSerialsService(&mockSerialsRepo{})
+	svc.Lifecycle = lifecycle
+
+	view, errResp := svc.GetChainOfCustody(testTenantA, "SN-001")
+	require.Nil(t, errResp)
+	assert.Equal(t, "s1", view.ID)
+
+	_, errResp = svc.GetChainOfCustody(testTenantA, "SN-404")
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ShipmentsService provides business logic for the packing stage between picking and the
+// delivery note: packages (cartons / pallets with SSCC), their contents, the packing list and
+// closing the shipment. Closing creates the delivery note; its PDF is generated through
+// DeliveryNotes like the one created at picking completion.
+type ShipmentsService struct {
+	Repository    ports.ShipmentsRepository
+	DeliveryNotes *DeliveryNotesService
+}
+
+func NewShipmentsService(repo ports.ShipmentsRepository, dnSvc *DeliveryNotesService) *ShipmentsService {
+	return &ShipmentsService{
+		Repository:    repo,
+		DeliveryNotes: dnSvc,
+	}
+}
+
+func (s *ShipmentsService) ListShipments(tenantID string, status *string, limit, offset int) ([]database.Shipment, *responses.InternalResponse) {
+	return s.Repository.ListShipments(tenantID, status, limit, offset)
+}
+
+func (s *ShipmentsService) GetShipment(id, tenantID string) (*responses.ShipmentView, *responses.InternalResponse) {
+	return s.Repository.GetShipment(id, tenantID)
+}
+
+func (s *ShipmentsService) AddPackage(shipmentID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	return s.Repository.AddPackage(shipmentID, tenantID, req)
+}
+
+func (s *ShipmentsService) UpdatePackage(shipmentID, packageID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	return s.Repository.UpdatePackage(shipmentID, packageID, tenantID, req)
+}
+
+func (s *ShipmentsService) DeletePackage(shipmentID, packageID, tenantID string) *responses.InternalResponse {
+	return s.Repository.DeletePackage(shipmentID, packageID, tenantID)
+}
+
+func (s *ShipmentsService) AddPackageContent(shipmentID, packageID, tenantID string, req *requests.AddPackageContentRequest) (*database.PackageContent, *responses.InternalResponse) {
+	return s.Repository.AddPackageContent(shipmentID, packageID, tenantID, req)
+}
+
+func (s *ShipmentsService) RemovePackageContent(shipmentID, packageID, contentID, tenantID string) *responses.InternalResponse {
+	return s.Repository.RemovePackageContent(shipmentID, packageID, contentID, tenantID)
+}
+
+// CloseShipment closes a fully packed shipment and starts the PDF of its delivery note.
+func (s *ShipmentsService) CloseShipment(id, tenantID, userID string, req *requests.CloseShipmentRequest) (*database.Shipment, *responses.InternalResponse) {
+	shipment, resp := s.Repository.CloseShipment(id, tenantID, userID, req)
+	if resp != nil {
+		return nil, resp
+	}
+	if s.DeliveryNotes != nil && shipment.DeliveryNoteID != nil {
+		s.DeliveryNotes.GeneratePDFAsync(*shipment.DeliveryNoteID, tenantID)
+	}
+	return shipment, nil
+}
+
+// GetPackingListPDF renders the packing list of a shipment and returns it with its file name.
+func (s *ShipmentsService) GetPackingListPDF(id, tenantID string) ([]byte, string, *responses.InternalResponse) {
+	view, resp := s.Repository.GetShipment(id, tenantID)
+	if resp != nil {
+		return nil, "", resp
+	}
+	pdf, err := buildPackingListPDF(view)
+	if err != nil {
+		return nil, "", &responses.InternalResponse{Error: err, Message: "Error al generar la lista de empaque"}
+	}
+	return pdf, fmt.Sprintf("packing-list-%s.pdf", view.ShipmentNumber), nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// buildPackingListPDF — PDF layout with gofpdf
+// ─────────────────────────────────────────────────────────────────────────────
+
+// buildPackingListPDF lists every package (SSCC, type, weight, dimensions) with its contents.
+func buildPackingListPDF(view *responses.ShipmentView) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 20, 15)
+	pdf.AddPage()
+
+	// ── Header ──────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, "eSTOCK - Packing List")
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "", 11)
+	customer := ""
+	if view.CustomerName != nil {
+		customer = *view.CustomerName
+	} else if view.CustomerID != nil {
+		customer = *view.CustomerID
+	}
+	salesOrder := view.SONumber
+	if salesOrder == "" {
+		salesOrder = view.SalesOrderID
+	}
+	pdf.Cell(90, 7, fmt.Sprintf("Shipment: %s", view.ShipmentNumber))
+	pdf.Cell(0, 7, fmt.Sprintf("Date: %s", view.CreatedAt.Format("2006-01-02")))
+	pdf.Ln(8)
+	pdf.Cell(90, 7, fmt.Sprintf("Sales Order: %s", salesOrder))
+	pdf.Cell(0, 7, fmt.Sprintf("Customer: %s", customer))
+	pdf.Ln(8)
+	if view.DNNumber != nil {
+		pdf.Cell(0, 7, fmt.Sprintf("Delivery Note: %s", *view.DNNumber))
+		pdf.Ln(8)
+	}
+	pdf.Ln(4)
+
+	// ── Packages ─────────────────────────────────────────────────────────────
+	for _, p := range view.Packages {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(220, 220, 220)
+		pdf.CellFormat(0, 8, fmt.Sprintf("#%d  %s  (00) %s", p.Sequence, strings.ToUpper(p.PackageType), p.SSCC), "1", 1, "L", true, 0, "")
+
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(0, 7, packageMeasures(p), "1", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(50, 7, "SKU", "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, "Lot", "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 7, "Qty", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(0, 7, "Serials", "1", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "", 9)
+		for _, c := range p.Contents {
+			lot := ""
+			if c.LotNumber != nil {
+				lot = *c.LotNumber
+			}
+			pdf.CellFormat(50, 7, c.ArticleSKU, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(40, 7, lot, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(25, 7, fmt.Sprintf("%.3f", c.Qty), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(0, 7, strings.Join(c.SerialNumbers, ", "), "1", 1, "L", false, 0, "")
+		}
+		pdf.Ln(4)
+	}
+
+	// ── Totals ───────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.Cell(0, 7, fmt.Sprintf("Packages: %d    Total weight: %.3f kg    Total volume: %.3f m3",
+		view.TotalPackages, view.TotalWeightKg, view.TotalVolumeM3))
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Cell(0, 6, fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("pdf output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// packageMeasures formats the weight and dimensions of a package, "-" when not recorded.
+func packageMeasures(p responses.PackageView) string {
+	num := func(v *float64, unit string) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f %s", *v, unit)
+	}
+	return fmt.Sprintf("Weight: %s    L x W x H: %s x %s x %s",
+		num(p.WeightKg, "kg"), num(p.LengthCm, "cm"), num(p.WidthCm, "cm"), num(p.HeightCm, "cm"))
+}
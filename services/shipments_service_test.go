@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockShipmentsRepo struct {
+	view      *responses.ShipmentView
+	closeResp *responses.InternalResponse
+	closed    bool
+}
+
+func (m *mockShipmentsRepo) ListShipments(tenantID string, status *string, limit, offset int) ([]database.Shipment, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockShipmentsRepo) GetShipment(id, tenantID string) (*responses.ShipmentView, *responses.InternalResponse) {
+	if m.view == nil || m.view.ID != id {
+		return nil, &responses.InternalResponse{Message: "not found", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.view, nil
+}
+func (m *mockShipmentsRepo) AddPackage(shipmentID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockShipmentsRepo) UpdatePackage(shipmentID, packageID, tenantID string, req *requests.PackageRequest) (*database.Package, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockShipmentsRepo) DeletePackage(shipmentID, packageID, tenantID string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockShipmentsRepo) AddPackageContent(shipmentID, packageID, tenantID string, req *requests.AddPackageContentRequest) (*database.PackageContent, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockShipmentsRepo) RemovePackageContent(shipmentID, packageID, contentID, tenantID string) *responses.InternalResponse {
+	return nil
+}
+func (m *mockShipmentsRepo) CloseShipment(id, tenantID, userID string, req *requests.CloseShipmentRequest) (*database.Shipment, *responses.InternalResponse) {
+	if m.closeResp != nil {
+		return nil, m.closeResp
+	}
+	m.closed = true
+	dnID := "dn-1"
+	return &database.Shipment{ID: id, Status: "packed", DeliveryNoteID: &dnID}, nil
+}
+
+func sampleShipmentView() *responses.ShipmentView {
+	weight, l, w, h := 12.5, 120.0, 80.0, 100.0
+	lot := "L1"
+	return &responses.ShipmentView{
+		Shipment: database.Shipment{ID: "sh-1", ShipmentNumber: "SH-2026-0001", SalesOrderID: "so-1", Status: "packing", CreatedAt: time.Now()},
+		SONumber: "SO-2026-0001",
+		Packages: []responses.PackageView{{
+			Package: database.Package{ID: "p1", SSCC: "106141411234567897", PackageType: "pallet", Sequence: 1, WeightKg: &weight, LengthCm: &l, WidthCm: &w, HeightCm: &h},
+			Contents: []responses.PackageContentView{{
+				PackageContent: database.PackageContent{ID: "c1", Qty: 2, SerialNumbers: []string{"S1", "S2"}},
+				ArticleSKU:     "SKU-1",
+				LotNumber:      &lot,
+			}},
+		}},
+		TotalPackages: 1,
+		TotalWeightKg: weight,
+		TotalVolumeM3: 0.96,
+	}
+}
+
+func TestShipmentsService_GetPackingListPDF(t *testing.T) {
+	svc := NewShipmentsService(&mockShipmentsRepo{view: sampleShipmentView()}, nil)
+
+	data, filename, resp := svc.GetPackingListPDF("sh-1", "tenant-1")
+	require.Nil(t, resp)
+	assert.Equal(t, "packing-list-SH-2026-0001.pdf", filename)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+}
+
+func TestShipmentsService_GetPackingListPDF_NotFound(t *testing.T) {
+	svc := NewShipmentsService(&mockShipmentsRepo{view: sampleShipmentView()}, nil)
+
+	_, _, resp := svc.GetPackingListPDF("missing", "tenant-1")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+}
+
+func TestShipmentsService_CloseShipment(t *testing.T) {
+	repo := &mockShipmentsRepo{}
+	svc := NewShipmentsService(repo, nil)
+
+	shipment, resp := svc.CloseShipment("sh-1", "tenant-1", "user-1", &requests.CloseShipmentRequest{})
+	require.Nil(t, resp)
+	assert.True(t, repo.closed)
+	assert.Equal(t, "packed", shipment.Status)
+	require.NotNil(t, shipment.DeliveryNoteID)
+}
+
+func TestShipmentsService_CloseShipment_PropagatesConflict(t *testing.T) {
+	repo := &mockShipmentsRepo{closeResp: &responses.InternalResponse{Message: "Faltan por empacar", Handled: true, StatusCode: responses.StatusConflict}}
+	svc := NewShipmentsService(repo, nil)
+
+	shipment, resp := svc.CloseShipment("sh-1", "tenant-1", "user-1", nil)
+	assert.Nil(t, shipment)
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"strings"
+
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
 	"github.com/eflowcr/eSTOCK_backend/models/responses"
 	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/eflowcr/eSTOCK_backend/tools"
 )
 
 type StockSettingsService struct {
@@ -19,6 +22,24 @@ func (s *StockSettingsService) GetOrCreate(tenantID string) (*database.StockSett
 	return s.Repository.GetOrCreate(tenantID)
 }
 
+// Update saves the tenant settings. An empty BaseCurrency keeps the tenant's current base
+// currency so clients that predate the field do not reset it. Number series patterns must
+// parse (one counter, known tokens).
 func (s *StockSettingsService) Update(tenantID string, data *requests.UpdateStockSettingsRequest) (*database.StockSetting, *responses.InternalResponse) {
+	for _, pattern := range []*string{data.LotNumberPattern, data.SerialNumberPattern} {
+		if pattern == nil || strings.TrimSpace(*pattern) == "" {
+			continue
+		}
+		if _, err := tools.ParseNumberPattern(*pattern); err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: err.Error(), Handled: true, StatusCode: responses.StatusBadRequest}
+		}
+	}
+	if data.BaseCurrency == "" {
+		current, resp := s.Repository.GetOrCreate(tenantID)
+		if resp != nil {
+			return nil, resp
+		}
+		data.BaseCurrency = current.BaseCurrency
+	}
 	return s.Repository.Upsert(tenantID, data)
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/eflowcr/eSTOCK_backend/models/database"
 	"github.com/eflowcr/eSTOCK_backend/models/requests"
@@ -52,6 +53,15 @@ func (s *StockTransfersService) GetStockTransferByTransferNumber(transferNumber
 }
 
 func (s *StockTransfersService) CreateStockTransfer(req *requests.StockTransferCreate, createdBy string) (*database.StockTransfer, *responses.InternalResponse) {
+	conv := s.uomConverter()
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		qty, factor, resp := transferQtyToBase(conv, line.Sku, line.Presentation, line.Quantity)
+		if resp != nil {
+			return nil, resp
+		}
+		line.Quantity, line.PresentationFactor = qty, factor
+	}
 	return s.Repository.CreateStockTransfer(req, createdBy)
 }
 
@@ -72,29 +82,395 @@ func (s *StockTransfersService) ListStockTransferLines(transferID string) ([]dat
 }
 
 func (s *StockTransfersService) CreateStockTransferLine(transferID string, req *requests.StockTransferLineInput) (*database.StockTransferLine, *responses.InternalResponse) {
+	qty, factor, resp := transferQtyToBase(s.uomConverter(), req.Sku, req.Presentation, req.Quantity)
+	if resp != nil {
+		return nil, resp
+	}
+	req.Quantity, req.PresentationFactor = qty, factor
 	return s.Repository.CreateStockTransferLine(transferID, req)
 }
 
 func (s *StockTransfersService) UpdateStockTransferLine(lineID string, req *requests.StockTransferLineUpdate) (*database.StockTransferLine, *responses.InternalResponse) {
+	if conv := s.uomConverter(); conv != nil && req.Presentation != nil && strings.TrimSpace(*req.Presentation) != "" {
+		var skus []string
+		if err := s.DB.Table("stock_transfer_lines").Where("id = ?", lineID).Limit(1).Pluck("sku", &skus).Error; err != nil {
+			return nil, &responses.InternalResponse{Error: err, Message: "Error loading stock transfer line"}
+		}
+		if len(skus) == 0 {
+			return nil, &responses.InternalResponse{Message: "Stock transfer line not found", Handled: true, StatusCode: responses.StatusNotFound}
+		}
+		qty, factor, resp := transferQtyToBase(conv, skus[0], req.Presentation, req.Quantity)
+		if resp != nil {
+			return nil, resp
+		}
+		req.Quantity, req.PresentationFactor = qty, factor
+	}
 	return s.Repository.UpdateStockTransferLine(lineID, req)
 }
 
+// uomConverter returns the presentation converter for transfer lines, or nil when the service
+// has no database (lines are then stored as given).
+func (s *StockTransfersService) uomConverter() *tools.UoMConverter {
+	if s.DB == nil {
+		return nil
+	}
+	return tools.NewUoMConverter(s.DB, s.TenantID)
+}
+
+// transferQtyToBase converts a line quantity entered in a presentation other than the article's
+// into base units and returns the factor used (nil for lines in base units).
+func transferQtyToBase(conv *tools.UoMConverter, sku string, presentation *string, qty float64) (float64, *float64, *responses.InternalResponse) {
+	if conv == nil || presentation == nil || strings.TrimSpace(*presentation) == "" {
+		return qty, nil, nil
+	}
+	base, factor, resp := conv.ToBase(sku, presentation, qty)
+	if resp != nil {
+		return 0, nil, resp
+	}
+	return base, &factor, nil
+}
+
 func (s *StockTransfersService) DeleteStockTransferLine(lineID string) *responses.InternalResponse {
 	return s.Repository.DeleteStockTransferLine(lineID)
 }
 
+// transferQtyEpsilon absorbs float noise when comparing NUMERIC(10,3) quantities.
+const transferQtyEpsilon = 0.0005
+
 // ExecuteTransfer moves stock from source to destination: decrements inventory at from_location,
 // increments at to_location, creates outbound/inbound movements, and sets transfer status to completed.
 // Requires LocationsRepository and DB to be set (use NewStockTransfersServiceWithExecute).
+// For transfers between distant sites use ShipTransfer + ReceiveTransfer instead.
 func (s *StockTransfersService) ExecuteTransfer(transferID, userID string) (*database.StockTransfer, *responses.InternalResponse) {
-	if s.LocationsRepository == nil || s.DB == nil {
+	if resp := s.requireExecuteDeps(); resp != nil {
+		return nil, resp
+	}
+
+	transfer, resp := s.getTransfer(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+	if !canShipTransfer(transfer.Status) {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Transfer cannot be executed in status %q", transfer.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	lines, resp := s.movableLines(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	fromLoc, toLoc, resp := s.transferLocations(transfer)
+	if resp != nil {
+		return nil, resp
+	}
+
+	fromCode := fromLoc.LocationCode
+	toCode := toLoc.LocationCode
+	tenantID := fromLoc.TenantID
+
+	var capacityResp *responses.InternalResponse
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialize concurrent executions and shipments of the same transfer.
+		status, err := lockTransferStatus(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if !canShipTransfer(status) {
+			return fmt.Errorf("transfer cannot be executed in status %q", status)
+		}
+
+		additions := make([]tools.CapacityAddition, 0, len(lines))
+		for _, line := range lines {
+			additions = append(additions, tools.CapacityAddition{SKU: line.Sku, Quantity: line.Quantity})
+		}
+		exceeded, err := tools.CheckLocationCapacity(tx, tenantID, toCode, additions)
+		if err != nil {
+			return err
+		}
+		if exceeded != nil {
+			capacityResp = tools.CapacityExceededResponse(exceeded)
+			return errors.New(capacityResp.Message)
+		}
+
+		for _, line := range lines {
+			if err := moveTransferStockOut(tx, tenantID, transfer, line.Sku, line.Quantity, fromCode, userID); err != nil {
+				return err
+			}
+			if err := moveTransferStockIn(tx, tenantID, transfer, line.Sku, line.Quantity, toCode, userID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(
+			`UPDATE stock_transfer_lines
+			 SET shipped_qty = quantity, received_qty = quantity, line_status = ?, received_at = CURRENT_TIMESTAMP
+			 WHERE stock_transfer_id = ? AND line_status <> ?`,
+			database.TransferLineReceived, transferID, database.TransferLineCancelled,
+		).Error; err != nil {
+			return fmt.Errorf("update transfer lines: %w", err)
+		}
+		if err := tx.Exec("UPDATE stock_transfers SET status = 'completed', completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?", transferID).Error; err != nil {
+			return fmt.Errorf("update transfer status: %w", err)
+		}
+		return nil
+	})
+
+	if capacityResp != nil {
+		return nil, capacityResp
+	}
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:      err,
+			Message:    err.Error(),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	updated, _ := s.Repository.GetStockTransferByID(transferID)
+	return updated, nil
+}
+
+// ShipTransfer is the first half of a two-step transfer: it decrements stock at from_location,
+// writes the outbound movements and leaves every non-cancelled line in_transit with
+// shipped_qty = quantity. The stock is only added at the destination by ReceiveTransfer.
+func (s *StockTransfersService) ShipTransfer(transferID, userID string) (*database.StockTransfer, *responses.InternalResponse) {
+	if resp := s.requireExecuteDeps(); resp != nil {
+		return nil, resp
+	}
+
+	transfer, resp := s.getTransfer(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+	if !canShipTransfer(transfer.Status) {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Transfer cannot be shipped in status %q", transfer.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	lines, resp := s.movableLines(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+
+	fromLoc, _, resp := s.transferLocations(transfer)
+	if resp != nil {
+		return nil, resp
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialize concurrent shipments and executions of the same transfer.
+		status, err := lockTransferStatus(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if !canShipTransfer(status) {
+			return fmt.Errorf("transfer cannot be shipped in status %q", status)
+		}
+
+		for _, line := range lines {
+			if err := moveTransferStockOut(tx, fromLoc.TenantID, transfer, line.Sku, line.Quantity, fromLoc.LocationCode, userID); err != nil {
+				return err
+			}
+			if err := tx.Exec(
+				"UPDATE stock_transfer_lines SET shipped_qty = ?, line_status = ? WHERE id = ?",
+				line.Quantity, database.TransferLineInTransit, line.ID,
+			).Error; err != nil {
+				return fmt.Errorf("update transfer line %s: %w", line.ID, err)
+			}
+		}
+
+		if err := tx.Exec(
+			"UPDATE stock_transfers SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			database.StockTransferStatusInTransit, transferID,
+		).Error; err != nil {
+			return fmt.Errorf("update transfer status: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, &responses.InternalResponse{
+			Error:      err,
+			Message:    err.Error(),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	updated, _ := s.Repository.GetStockTransferByID(transferID)
+	return updated, nil
+}
+
+// ReceiveTransfer confirms the quantities that arrived for an in_transit or partially_received
+// transfer. Received stock lands at the transfer's dock_location when set, otherwise at
+// to_location. A line stays partially_received until everything shipped has arrived or the
+// caller closes it; closed lines end as received, short_received or over_received. Once every
+// line is closed the transfer is completed. Discrepancies are reported in the result, not
+// adjusted: whatever a short receipt is missing is left for a manual adjustment.
+func (s *StockTransfersService) ReceiveTransfer(transferID, userID string, req *requests.ReceiveStockTransferRequest) (*responses.StockTransferReceiptResult, *responses.InternalResponse) {
+	if resp := s.requireExecuteDeps(); resp != nil {
+		return nil, resp
+	}
+
+	transfer, resp := s.getTransfer(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+	if !canReceiveTransfer(transfer.Status) {
+		return nil, &responses.InternalResponse{
+			Message:    fmt.Sprintf("Transfer cannot be received in status %q", transfer.Status),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	_, toLoc, resp := s.transferLocations(transfer)
+	if resp != nil {
+		return nil, resp
+	}
+	tenantID := toLoc.TenantID
+	destCode := toLoc.LocationCode
+	if transfer.DockLocation != nil && strings.TrimSpace(*transfer.DockLocation) != "" {
+		destCode = strings.TrimSpace(*transfer.DockLocation)
+	}
+
+	var capacityResp *responses.InternalResponse
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialize concurrent receipts of the same transfer.
+		status, err := lockTransferStatus(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if !canReceiveTransfer(status) {
+			return fmt.Errorf("transfer cannot be received in status %q", status)
+		}
+
+		var lines []database.StockTransferLine
+		if err := tx.Raw("SELECT * FROM stock_transfer_lines WHERE stock_transfer_id = ? FOR UPDATE", transferID).Scan(&lines).Error; err != nil {
+			return fmt.Errorf("lock transfer lines: %w", err)
+		}
+		byID := make(map[string]int, len(lines))
+		for i := range lines {
+			byID[lines[i].ID] = i
+		}
+
+		now := tools.GetCurrentTime()
+		for _, in := range req.Lines {
+			idx, ok := byID[in.LineID]
+			if !ok {
+				return fmt.Errorf("line %s does not belong to transfer %s", in.LineID, transfer.TransferNumber)
+			}
+			line := &lines[idx]
+			if !isOpenTransferLine(line.LineStatus) {
+				return fmt.Errorf("line %s (SKU %s) cannot be received in status %q", line.ID, line.Sku, line.LineStatus)
+			}
+
+			if in.ReceivedQty > 0 {
+				// Earlier lines are already written in this tx, so the load read is cumulative.
+				exceeded, err := tools.CheckLocationCapacity(tx, tenantID, destCode, []tools.CapacityAddition{{SKU: line.Sku, Quantity: in.ReceivedQty}})
+				if err != nil {
+					return err
+				}
+				if exceeded != nil {
+					capacityResp = tools.CapacityExceededResponse(exceeded)
+					return errors.New(capacityResp.Message)
+				}
+				if err := moveTransferStockIn(tx, tenantID, transfer, line.Sku, in.ReceivedQty, destCode, userID); err != nil {
+					return err
+				}
+			}
+
+			line.ReceivedQty += in.ReceivedQty
+			line.LineStatus = receivedLineStatus(line.ShippedQty, line.ReceivedQty, in.Close)
+			line.ReceivedAt = &now
+			if in.Reason != nil && strings.TrimSpace(*in.Reason) != "" {
+				reason := strings.TrimSpace(*in.Reason)
+				line.DiscrepancyReason = &reason
+			}
+			if err := tx.Exec(
+				"UPDATE stock_transfer_lines SET received_qty = ?, line_status = ?, discrepancy_reason = ?, received_at = ? WHERE id = ?",
+				line.ReceivedQty, line.LineStatus, line.DiscrepancyReason, now, line.ID,
+			).Error; err != nil {
+				return fmt.Errorf("update transfer line %s: %w", line.ID, err)
+			}
+		}
+
+		newStatus := transferStatusAfterReceipt(lines)
+		if newStatus == database.StockTransferStatusCompleted {
+			if err := tx.Exec(
+				"UPDATE stock_transfers SET status = ?, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+				newStatus, transferID,
+			).Error; err != nil {
+				return fmt.Errorf("update transfer status: %w", err)
+			}
+			return nil
+		}
+		if err := tx.Exec(
+			"UPDATE stock_transfers SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			newStatus, transferID,
+		).Error; err != nil {
+			return fmt.Errorf("update transfer status: %w", err)
+		}
+		return nil
+	})
+
+	if capacityResp != nil {
+		return nil, capacityResp
+	}
+	if err != nil {
 		return nil, &responses.InternalResponse{
+			Error:      err,
+			Message:    err.Error(),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+
+	updated, _ := s.Repository.GetStockTransferByID(transferID)
+	lines, resp := s.Repository.ListStockTransferLines(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+	return &responses.StockTransferReceiptResult{
+		Transfer:      updated,
+		Lines:         lines,
+		Discrepancies: transferDiscrepancies(lines),
+	}, nil
+}
+
+// GetTransferDiscrepancies lists the closed lines of a transfer whose received quantity
+// differs from the shipped quantity.
+func (s *StockTransfersService) GetTransferDiscrepancies(transferID string) ([]responses.StockTransferDiscrepancy, *responses.InternalResponse) {
+	if _, resp := s.getTransfer(transferID); resp != nil {
+		return nil, resp
+	}
+	lines, resp := s.Repository.ListStockTransferLines(transferID)
+	if resp != nil {
+		return nil, resp
+	}
+	return transferDiscrepancies(lines), nil
+}
+
+func (s *StockTransfersService) requireExecuteDeps() *responses.InternalResponse {
+	if s.LocationsRepository == nil || s.DB == nil {
+		return &responses.InternalResponse{
 			Message:    "Execute transfer is not configured (missing locations or database)",
 			Handled:    true,
 			StatusCode: responses.StatusInternalServerError,
 		}
 	}
+	return nil
+}
 
+func (s *StockTransfersService) getTransfer(transferID string) (*database.StockTransfer, *responses.InternalResponse) {
 	transfer, resp := s.Repository.GetStockTransferByID(transferID)
 	if resp != nil {
 		return nil, resp
@@ -106,32 +482,38 @@ func (s *StockTransfersService) ExecuteTransfer(transferID, userID string) (*dat
 			StatusCode: responses.StatusNotFound,
 		}
 	}
-	if transfer.Status != "draft" && transfer.Status != "in_progress" {
-		return nil, &responses.InternalResponse{
-			Message:    fmt.Sprintf("Transfer cannot be executed in status %q", transfer.Status),
-			Handled:    true,
-			StatusCode: responses.StatusBadRequest,
-		}
-	}
+	return transfer, nil
+}
 
+// movableLines returns the non-cancelled lines of a transfer; a transfer without any is rejected.
+func (s *StockTransfersService) movableLines(transferID string) ([]database.StockTransferLine, *responses.InternalResponse) {
 	lines, resp := s.Repository.ListStockTransferLines(transferID)
 	if resp != nil {
 		return nil, resp
 	}
-	if len(lines) == 0 {
+	movable := make([]database.StockTransferLine, 0, len(lines))
+	for _, line := range lines {
+		if line.LineStatus != database.TransferLineCancelled {
+			movable = append(movable, line)
+		}
+	}
+	if len(movable) == 0 {
 		return nil, &responses.InternalResponse{
 			Message:    "Transfer has no lines",
 			Handled:    true,
 			StatusCode: responses.StatusBadRequest,
 		}
 	}
+	return movable, nil
+}
 
+func (s *StockTransfersService) transferLocations(transfer *database.StockTransfer) (*database.Location, *database.Location, *responses.InternalResponse) {
 	fromLoc, resp := s.LocationsRepository.GetLocationByID(s.TenantID, transfer.FromLocationID)
 	if resp != nil || fromLoc == nil {
 		if resp != nil {
-			return nil, resp
+			return nil, nil, resp
 		}
-		return nil, &responses.InternalResponse{
+		return nil, nil, &responses.InternalResponse{
 			Message:    "From location not found",
 			Handled:    true,
 			StatusCode: responses.StatusNotFound,
@@ -140,165 +522,226 @@ func (s *StockTransfersService) ExecuteTransfer(transferID, userID string) (*dat
 	toLoc, resp := s.LocationsRepository.GetLocationByID(s.TenantID, transfer.ToLocationID)
 	if resp != nil || toLoc == nil {
 		if resp != nil {
-			return nil, resp
+			return nil, nil, resp
 		}
-		return nil, &responses.InternalResponse{
+		return nil, nil, &responses.InternalResponse{
 			Message:    "To location not found",
 			Handled:    true,
 			StatusCode: responses.StatusNotFound,
 		}
 	}
+	return fromLoc, toLoc, nil
+}
 
-	fromCode := fromLoc.LocationCode
-	toCode := toLoc.LocationCode
-
-	err := s.DB.Transaction(func(tx *gorm.DB) error {
-		for _, line := range lines {
-			sku := line.Sku
-			qty := line.Quantity
-
-			var fromInv database.Inventory
-			// Lock the inventory row to prevent race conditions during concurrent transfers
-			// or simultaneous picking operations (B3e A5).
-			if err := tx.Raw(
-				`SELECT id, sku, location, quantity, reserved_qty FROM inventory WHERE sku = ? AND location = ? FOR UPDATE`,
-				sku, fromCode,
-			).Scan(&fromInv).Error; err != nil {
-				return fmt.Errorf("find inventory %s at %s: %w", sku, fromCode, err)
-			}
-			if fromInv.ID == "" {
-				return fmt.Errorf("insufficient stock: SKU %s not found at source location %s", sku, fromCode)
-			}
-			// B3e (A5): check available (non-reserved) stock.
-			available := fromInv.Quantity - fromInv.ReservedQty
-			if qty > available {
-				return fmt.Errorf(
-					"no puede transferir %.2f de %s en %s — hay %.2f reservadas en pickings activos (disponible: %.2f)",
-					qty, sku, fromCode, fromInv.ReservedQty, available,
-				)
-			}
-			if fromInv.Quantity < qty {
-				return fmt.Errorf("insufficient stock: SKU %s at %s has %.3f, need %.3f", sku, fromCode, fromInv.Quantity, qty)
-			}
+// moveTransferStockOut decrements available stock of sku at fromCode and writes the outbound movement.
+func moveTransferStockOut(tx *gorm.DB, tenantID string, transfer *database.StockTransfer, sku string, qty float64, fromCode, userID string) error {
+	var fromInv database.Inventory
+	// Lock the inventory row to prevent race conditions during concurrent transfers
+	// or simultaneous picking operations (B3e A5).
+	// held_qty is read as everything on QC hold: inventory holds plus held lots.
+	if err := tx.Raw(
+		`SELECT i.id, i.sku, i.location, i.quantity, i.reserved_qty, i.held_qty + `+tools.HeldLotQtySQL("i")+` AS held_qty
+		   FROM inventory i WHERE i.tenant_id = ? AND i.sku = ? AND i.location = ? FOR UPDATE OF i`,
+		tenantID, sku, fromCode,
+	).Scan(&fromInv).Error; err != nil {
+		return fmt.Errorf("find inventory %s at %s: %w", sku, fromCode, err)
+	}
+	if fromInv.ID == "" {
+		return fmt.Errorf("insufficient stock: SKU %s not found at source location %s", sku, fromCode)
+	}
+	// B3e (A5): check available (non-reserved, not on QC hold) stock.
+	available := fromInv.Quantity - fromInv.ReservedQty - fromInv.HeldQty
+	if qty > available {
+		if fromInv.HeldQty > 0 {
+			return fmt.Errorf(
+				"no puede transferir %.2f de %s en %s — hay %.2f reservadas y %.2f retenidas por calidad (disponible: %.2f)",
+				qty, sku, fromCode, fromInv.ReservedQty, fromInv.HeldQty, available,
+			)
+		}
+		return fmt.Errorf(
+			"no puede transferir %.2f de %s en %s — hay %.2f reservadas en pickings activos (disponible: %.2f)",
+			qty, sku, fromCode, fromInv.ReservedQty, available,
+		)
+	}
+	if fromInv.Quantity < qty {
+		return fmt.Errorf("insufficient stock: SKU %s at %s has %.3f, need %.3f", sku, fromCode, fromInv.Quantity, qty)
+	}
 
-			newFromQty := fromInv.Quantity - qty
-			if err := tx.Model(&database.Inventory{}).Where("id = ?", fromInv.ID).Update("quantity", newFromQty).Error; err != nil {
-				return fmt.Errorf("update inventory %s at source: %w", sku, err)
-			}
+	newFromQty := fromInv.Quantity - qty
+	if err := tx.Model(&database.Inventory{}).Where("id = ?", fromInv.ID).Update("quantity", newFromQty).Error; err != nil {
+		return fmt.Errorf("update inventory %s at source: %w", sku, err)
+	}
 
-			movOutID, err := tools.GenerateNanoid(tx)
-			if err != nil {
-				return fmt.Errorf("generate movement id: %w", err)
-			}
-			fromBeforeQty := fromInv.Quantity
-			outRefType := "stock_transfer"
-			movOut := &database.InventoryMovement{
-				ID:             movOutID,
-				SKU:            sku,
-				Location:       fromCode,
-				MovementType:   "outbound",
-				Quantity:       -qty,
-				RemainingStock: newFromQty,
-				Reason:         strPtr("stock transfer " + transfer.TransferNumber),
-				CreatedBy:      userID,
-				CreatedAt:      tools.GetCurrentTime(),
-				ReferenceType:  &outRefType,
-				ReferenceID:    &transferID,
-				UnitCost:       fromInv.UnitPrice,
-				BeforeQty:      &fromBeforeQty,
-				AfterQty:       &newFromQty,
-				UserID:         &userID,
-			}
-			if err := tx.Create(movOut).Error; err != nil {
-				return fmt.Errorf("create outbound movement: %w", err)
-			}
+	movOutID, err := tools.GenerateNanoid(tx)
+	if err != nil {
+		return fmt.Errorf("generate movement id: %w", err)
+	}
+	fromBeforeQty := fromInv.Quantity
+	outRefType := "stock_transfer"
+	transferID := transfer.ID
+	movOut := &database.InventoryMovement{
+		ID:             movOutID,
+		SKU:            sku,
+		Location:       fromCode,
+		MovementType:   "outbound",
+		Quantity:       -qty,
+		RemainingStock: newFromQty,
+		Reason:         strPtr("stock transfer " + transfer.TransferNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &outRefType,
+		ReferenceID:    &transferID,
+		UnitCost:       fromInv.UnitPrice,
+		BeforeQty:      &fromBeforeQty,
+		AfterQty:       &newFromQty,
+		UserID:         &userID,
+	}
+	if err := tx.Create(movOut).Error; err != nil {
+		return fmt.Errorf("create outbound movement: %w", err)
+	}
+	return nil
+}
 
-			var toInv database.Inventory
-			errFind := tx.Where("sku = ? AND location = ?", sku, toCode).First(&toInv).Error
-			if errFind != nil {
-				if errors.Is(errFind, gorm.ErrRecordNotFound) {
-					var article database.Article
-					if err := tx.Where("sku = ?", sku).First(&article).Error; err != nil {
-						return fmt.Errorf("article %s not found: %w", sku, err)
-					}
-					invID, err := tools.GenerateNanoid(tx)
-					if err != nil {
-						return fmt.Errorf("generate inventory id: %w", err)
-					}
-					pres := article.Presentation
-					if line.Presentation != nil && *line.Presentation != "" {
-						pres = *line.Presentation
-					}
-					toInv = database.Inventory{
-						ID:           invID,
-						SKU:          sku,
-						Name:         article.Name,
-						Location:     toCode,
-						Quantity:     qty,
-						Status:       "available",
-						Presentation: pres,
-						CreatedAt:    tools.GetCurrentTime(),
-						UpdatedAt:    tools.GetCurrentTime(),
-					}
-					if err := tx.Create(&toInv).Error; err != nil {
-						return fmt.Errorf("create inventory at destination: %w", err)
-					}
-				} else {
-					return fmt.Errorf("find inventory %s at destination: %w", sku, errFind)
-				}
-			} else {
-				toInv.Quantity += qty
-				toInv.UpdatedAt = tools.GetCurrentTime()
-				if err := tx.Save(&toInv).Error; err != nil {
-					return fmt.Errorf("update inventory %s at destination: %w", sku, err)
-				}
+// moveTransferStockIn adds qty (base units) of sku at toCode, creating the inventory row in the
+// article's presentation if needed, and writes the inbound movement.
+func moveTransferStockIn(tx *gorm.DB, tenantID string, transfer *database.StockTransfer, sku string, qty float64, toCode, userID string) error {
+	var toInv database.Inventory
+	errFind := tx.Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, sku, toCode).First(&toInv).Error
+	if errFind != nil {
+		if errors.Is(errFind, gorm.ErrRecordNotFound) {
+			var article database.Article
+			if err := tx.Where("sku = ?", sku).First(&article).Error; err != nil {
+				return fmt.Errorf("article %s not found: %w", sku, err)
 			}
-
-			movInID, err := tools.GenerateNanoid(tx)
+			invID, err := tools.GenerateNanoid(tx)
 			if err != nil {
-				return fmt.Errorf("generate movement id: %w", err)
+				return fmt.Errorf("generate inventory id: %w", err)
 			}
-			toBeforeQty := toInv.Quantity - qty
-			inRefType := "stock_transfer"
-			movIn := &database.InventoryMovement{
-				ID:             movInID,
-				SKU:            sku,
-				Location:       toCode,
-				MovementType:   "inbound",
-				Quantity:       qty,
-				RemainingStock: toInv.Quantity,
-				Reason:         strPtr("stock transfer " + transfer.TransferNumber),
-				CreatedBy:      userID,
-				CreatedAt:      tools.GetCurrentTime(),
-				ReferenceType:  &inRefType,
-				ReferenceID:    &transferID,
-				UnitCost:       toInv.UnitPrice,
-				BeforeQty:      &toBeforeQty,
-				AfterQty:       &toInv.Quantity,
-				UserID:         &userID,
+			toInv = database.Inventory{
+				ID:           invID,
+				TenantID:     tenantID,
+				SKU:          sku,
+				Name:         article.Name,
+				Location:     toCode,
+				Quantity:     qty,
+				Status:       "available",
+				Presentation: article.Presentation,
+				CreatedAt:    tools.GetCurrentTime(),
+				UpdatedAt:    tools.GetCurrentTime(),
 			}
-			if err := tx.Create(movIn).Error; err != nil {
-				return fmt.Errorf("create inbound movement: %w", err)
+			if err := tx.Create(&toInv).Error; err != nil {
+				return fmt.Errorf("create inventory at destination: %w", err)
 			}
+		} else {
+			return fmt.Errorf("find inventory %s at destination: %w", sku, errFind)
 		}
-
-		if err := tx.Exec("UPDATE stock_transfers SET status = 'completed', completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?", transferID).Error; err != nil {
-			return fmt.Errorf("update transfer status: %w", err)
+	} else {
+		toInv.Quantity += qty
+		toInv.UpdatedAt = tools.GetCurrentTime()
+		if err := tx.Save(&toInv).Error; err != nil {
+			return fmt.Errorf("update inventory %s at destination: %w", sku, err)
 		}
-		return nil
-	})
+	}
 
+	movInID, err := tools.GenerateNanoid(tx)
 	if err != nil {
-		return nil, &responses.InternalResponse{
-			Error:   err,
-			Message: err.Error(),
-			Handled: true,
-			StatusCode: responses.StatusBadRequest,
+		return fmt.Errorf("generate movement id: %w", err)
+	}
+	toBeforeQty := toInv.Quantity - qty
+	inRefType := "stock_transfer"
+	transferID := transfer.ID
+	movIn := &database.InventoryMovement{
+		ID:             movInID,
+		SKU:            sku,
+		Location:       toCode,
+		MovementType:   "inbound",
+		Quantity:       qty,
+		RemainingStock: toInv.Quantity,
+		Reason:         strPtr("stock transfer " + transfer.TransferNumber),
+		CreatedBy:      userID,
+		CreatedAt:      tools.GetCurrentTime(),
+		ReferenceType:  &inRefType,
+		ReferenceID:    &transferID,
+		UnitCost:       toInv.UnitPrice,
+		BeforeQty:      &toBeforeQty,
+		AfterQty:       &toInv.Quantity,
+		UserID:         &userID,
+	}
+	if err := tx.Create(movIn).Error; err != nil {
+		return fmt.Errorf("create inbound movement: %w", err)
+	}
+	return nil
+}
+
+// lockTransferStatus locks the transfer row until tx ends and returns its current status, so a
+// status checked before the transaction is confirmed against concurrent ship / execute / receive.
+func lockTransferStatus(tx *gorm.DB, transferID string) (string, error) {
+	var status string
+	if err := tx.Raw("SELECT status FROM stock_transfers WHERE id = ? FOR UPDATE", transferID).Scan(&status).Error; err != nil {
+		return "", fmt.Errorf("lock transfer: %w", err)
+	}
+	return status, nil
+}
+
+func canShipTransfer(status string) bool {
+	return status == database.StockTransferStatusDraft || status == database.StockTransferStatusInProgress
+}
+
+func canReceiveTransfer(status string) bool {
+	return status == database.StockTransferStatusInTransit || status == database.StockTransferStatusPartiallyReceived
+}
+
+func isOpenTransferLine(status string) bool {
+	return status == database.TransferLineInTransit || status == database.TransferLinePartiallyReceived
+}
+
+// receivedLineStatus is the line status after a receipt: the line closes once received covers
+// shipped or when the caller closes it explicitly.
+func receivedLineStatus(shipped, received float64, closeLine bool) string {
+	if received < shipped-transferQtyEpsilon && !closeLine {
+		return database.TransferLinePartiallyReceived
+	}
+	switch {
+	case received < shipped-transferQtyEpsilon:
+		return database.TransferLineShortReceived
+	case received > shipped+transferQtyEpsilon:
+		return database.TransferLineOverReceived
+	default:
+		return database.TransferLineReceived
+	}
+}
+
+// transferStatusAfterReceipt is completed when every non-cancelled line is closed,
+// partially_received otherwise.
+func transferStatusAfterReceipt(lines []database.StockTransferLine) string {
+	for _, line := range lines {
+		if line.LineStatus == database.TransferLineCancelled {
+			continue
+		}
+		if isOpenTransferLine(line.LineStatus) {
+			return database.StockTransferStatusPartiallyReceived
 		}
 	}
+	return database.StockTransferStatusCompleted
+}
 
-	updated, _ := s.Repository.GetStockTransferByID(transferID)
-	return updated, nil
+func transferDiscrepancies(lines []database.StockTransferLine) []responses.StockTransferDiscrepancy {
+	out := make([]responses.StockTransferDiscrepancy, 0)
+	for _, line := range lines {
+		if line.LineStatus != database.TransferLineShortReceived && line.LineStatus != database.TransferLineOverReceived {
+			continue
+		}
+		out = append(out, responses.StockTransferDiscrepancy{
+			LineID:         line.ID,
+			Sku:            line.Sku,
+			ShippedQty:     line.ShippedQty,
+			ReceivedQty:    line.ReceivedQty,
+			DiscrepancyQty: line.ReceivedQty - line.ShippedQty,
+			LineStatus:     line.LineStatus,
+			Reason:         line.DiscrepancyReason,
+		})
+	}
+	return out
 }
 
 func strPtr(s string) *string {
@@ -404,3 +404,90 @@ func TestStockTransfersService_ExecuteTransfer_MissingDeps(t *testing.T) {
 	assert.Equal(t, responses.StatusInternalServerError, errResp.StatusCode)
 	assert.True(t, errResp.Handled)
 }
+
+func TestStockTransfersService_ShipTransfer_MissingDeps(t *testing.T) {
+	svc := NewStockTransfersService(&mockStockTransfersRepo{})
+	result, errResp := svc.ShipTransfer("1", "user-1")
+	require.NotNil(t, errResp)
+	assert.Nil(t, result)
+	assert.Equal(t, responses.StatusInternalServerError, errResp.StatusCode)
+}
+
+func TestStockTransfersService_ReceiveTransfer_MissingDeps(t *testing.T) {
+	svc := NewStockTransfersService(&mockStockTransfersRepo{})
+	result, errResp := svc.ReceiveTransfer("1", "user-1", &requests.ReceiveStockTransferRequest{
+		Lines: []requests.ReceiveStockTransferLine{{LineID: "l1", ReceivedQty: 1}},
+	})
+	require.NotNil(t, errResp)
+	assert.Nil(t, result)
+	assert.Equal(t, responses.StatusInternalServerError, errResp.StatusCode)
+}
+
+func TestReceivedLineStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		shipped  float64
+		received float64
+		close    bool
+		want     string
+	}{
+		{"partial stays open", 10, 4, false, database.TransferLinePartiallyReceived},
+		{"partial closed is short", 10, 4, true, database.TransferLineShortReceived},
+		{"nothing arrived and closed", 10, 0, true, database.TransferLineShortReceived},
+		{"exact closes", 10, 10, false, database.TransferLineReceived},
+		{"exact within epsilon", 10, 9.9999, false, database.TransferLineReceived},
+		{"over closes", 10, 12, false, database.TransferLineOverReceived},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, receivedLineStatus(tc.shipped, tc.received, tc.close))
+		})
+	}
+}
+
+func TestTransferStatusAfterReceipt(t *testing.T) {
+	open := []database.StockTransferLine{
+		{LineStatus: database.TransferLineReceived},
+		{LineStatus: database.TransferLinePartiallyReceived},
+	}
+	assert.Equal(t, database.StockTransferStatusPartiallyReceived, transferStatusAfterReceipt(open))
+
+	notYet := []database.StockTransferLine{
+		{LineStatus: database.TransferLineShortReceived},
+		{LineStatus: database.TransferLineInTransit},
+	}
+	assert.Equal(t, database.StockTransferStatusPartiallyReceived, transferStatusAfterReceipt(notYet))
+
+	closed := []database.StockTransferLine{
+		{LineStatus: database.TransferLineReceived},
+		{LineStatus: database.TransferLineOverReceived},
+		{LineStatus: database.TransferLineCancelled},
+	}
+	assert.Equal(t, database.StockTransferStatusCompleted, transferStatusAfterReceipt(closed))
+}
+
+func TestStockTransfersService_GetTransferDiscrepancies(t *testing.T) {
+	reason := "pallet damaged in transit"
+	repo := &mockStockTransfersRepo{
+		byID: map[string]*database.StockTransfer{"st-1": {ID: "st-1", Status: database.StockTransferStatusCompleted}},
+		lines: []database.StockTransferLine{
+			{ID: "l1", Sku: "A", ShippedQty: 10, ReceivedQty: 10, LineStatus: database.TransferLineReceived},
+			{ID: "l2", Sku: "B", ShippedQty: 10, ReceivedQty: 7, LineStatus: database.TransferLineShortReceived, DiscrepancyReason: &reason},
+			{ID: "l3", Sku: "C", ShippedQty: 5, ReceivedQty: 6, LineStatus: database.TransferLineOverReceived},
+			{ID: "l4", Sku: "D", ShippedQty: 5, ReceivedQty: 2, LineStatus: database.TransferLinePartiallyReceived},
+		},
+	}
+	svc := NewStockTransfersService(repo)
+	list, errResp := svc.GetTransferDiscrepancies("st-1")
+	require.Nil(t, errResp)
+	require.Len(t, list, 2)
+	assert.Equal(t, "B", list[0].Sku)
+	assert.Equal(t, -3.0, list[0].DiscrepancyQty)
+	assert.Equal(t, &reason, list[0].Reason)
+	assert.Equal(t, "C", list[1].Sku)
+	assert.Equal(t, 1.0, list[1].DiscrepancyQty)
+
+	_, errResp = svc.GetTransferDiscrepancies("missing")
+	require.NotNil(t, errResp)
+	assert.Equal(t, responses.StatusNotFound, errResp.StatusCode)
+}
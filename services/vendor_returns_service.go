@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/eflowcr/eSTOCK_backend/ports"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// VendorReturnsService provides business logic for returns to vendor (RTV): drafting a return
+// from the rejections of a purchase order or receiving task, shipping it, recording the
+// supplier's credit note and the return document for the supplier. Rejected units never
+// entered stock, so no inventory movement is posted.
+type VendorReturnsService struct {
+	Repository ports.VendorReturnsRepository
+}
+
+func NewVendorReturnsService(repo ports.VendorReturnsRepository) *VendorReturnsService {
+	return &VendorReturnsService{Repository: repo}
+}
+
+func (s *VendorReturnsService) ListReturns(tenantID string, status, purchaseOrderID, supplierID *string, limit, offset int) ([]database.VendorReturn, *responses.InternalResponse) {
+	return s.Repository.ListReturns(tenantID, status, purchaseOrderID, supplierID, limit, offset)
+}
+
+func (s *VendorReturnsService) GetReturn(id, tenantID string) (*responses.VendorReturnView, *responses.InternalResponse) {
+	return s.Repository.GetReturn(id, tenantID)
+}
+
+// CreateReturn checks the return refers to exactly one purchase order or receiving task, then
+// drafts it.
+func (s *VendorReturnsService) CreateReturn(tenantID, userID string, req *requests.CreateVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	req.PurchaseOrderID = trimmedOrNil(req.PurchaseOrderID)
+	req.ReceivingTaskID = trimmedOrNil(req.ReceivingTaskID)
+	if (req.PurchaseOrderID == nil) == (req.ReceivingTaskID == nil) {
+		return nil, &responses.InternalResponse{
+			Message:    "Indique la orden de compra o la tarea de recepción de la devolución (solo una)",
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	for i := range req.Lines {
+		req.Lines[i].ArticleSKU = strings.TrimSpace(req.Lines[i].ArticleSKU)
+	}
+	return s.Repository.CreateReturn(tenantID, userID, req)
+}
+
+func (s *VendorReturnsService) ShipReturn(id, tenantID string, req *requests.ShipVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	return s.Repository.ShipReturn(id, tenantID, req)
+}
+
+// CreditReturn records the supplier's credit note; the credit date cannot be in the future.
+func (s *VendorReturnsService) CreditReturn(id, tenantID string, req *requests.CreditVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	req.CreditNoteNumber = strings.TrimSpace(req.CreditNoteNumber)
+	if req.CreditNoteNumber == "" {
+		return nil, &responses.InternalResponse{Message: "El número de nota de crédito es obligatorio", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	if req.CreditedAt != nil && req.CreditedAt.After(time.Now()) {
+		return nil, &responses.InternalResponse{Message: "La fecha del crédito no puede ser futura", Handled: true, StatusCode: responses.StatusBadRequest}
+	}
+	return s.Repository.CreditReturn(id, tenantID, req)
+}
+
+func (s *VendorReturnsService) CancelReturn(id, tenantID string) (*database.VendorReturn, *responses.InternalResponse) {
+	return s.Repository.CancelReturn(id, tenantID)
+}
+
+// GetReturnPDF renders the return document for the supplier and returns it with its file name.
+func (s *VendorReturnsService) GetReturnPDF(id, tenantID string) ([]byte, string, *responses.InternalResponse) {
+	view, resp := s.Repository.GetReturn(id, tenantID)
+	if resp != nil {
+		return nil, "", resp
+	}
+	pdf, err := buildVendorReturnPDF(view)
+	if err != nil {
+		return nil, "", &responses.InternalResponse{Error: err, Message: "Error al generar el PDF de la devolución a proveedor"}
+	}
+	return pdf, fmt.Sprintf("%s.pdf", view.ReturnNumber), nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// buildVendorReturnPDF — PDF layout with gofpdf
+// ─────────────────────────────────────────────────────────────────────────────
+
+// buildVendorReturnPDF lists the returned lines with their PO unit cost and the expected credit.
+func buildVendorReturnPDF(view *responses.VendorReturnView) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 20, 15)
+	pdf.AddPage()
+
+	// ── Header ──────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, "eSTOCK - Return to Vendor")
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(90, 7, fmt.Sprintf("Return: %s", view.ReturnNumber))
+	pdf.Cell(0, 7, fmt.Sprintf("Date: %s", view.CreatedAt.Format("2006-01-02")))
+	pdf.Ln(8)
+	pdf.Cell(90, 7, fmt.Sprintf("Purchase Order: %s", optionalText(view.PONumber)))
+	pdf.Cell(0, 7, fmt.Sprintf("Receipt: %s", optionalText(view.InboundNumber)))
+	pdf.Ln(8)
+	pdf.Cell(0, 7, fmt.Sprintf("Supplier: %s", view.SupplierName))
+	pdf.Ln(8)
+	if view.SupplierTaxID != nil {
+		pdf.Cell(0, 7, fmt.Sprintf("Tax ID: %s", *view.SupplierTaxID))
+		pdf.Ln(8)
+	}
+	if view.SupplierAddress != nil {
+		pdf.Cell(0, 7, fmt.Sprintf("Address: %s", *view.SupplierAddress))
+		pdf.Ln(8)
+	}
+	if view.ShippedAt != nil {
+		pdf.Cell(90, 7, fmt.Sprintf("Shipped: %s", view.ShippedAt.Format("2006-01-02")))
+		pdf.Cell(0, 7, fmt.Sprintf("Carrier / tracking: %s / %s", optionalText(view.Carrier), optionalText(view.TrackingNumber)))
+		pdf.Ln(8)
+	}
+	pdf.Ln(4)
+
+	// ── Lines ────────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	pdf.CellFormat(45, 8, "SKU", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(25, 8, "Qty", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(30, 8, "Unit cost", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(30, 8, "Amount", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(0, 8, "Reason", "1", 1, "L", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, l := range view.Lines {
+		unitCost, amount := "-", "-"
+		if l.UnitCost != nil {
+			unitCost = fmt.Sprintf("%.4f %s", *l.UnitCost, optionalText(l.Currency))
+			amount = fmt.Sprintf("%.2f", l.Qty**l.UnitCost)
+		}
+		pdf.CellFormat(45, 7, l.ArticleSKU, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 7, fmt.Sprintf("%.3f", l.Qty), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 7, unitCost, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, amount, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(0, 7, optionalText(l.Reason), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// ── Totals ───────────────────────────────────────────────────────────────
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.Cell(0, 7, fmt.Sprintf("Total qty: %.3f    Expected credit: %.2f %s",
+		view.TotalQty, view.ExpectedCredit, optionalText(view.Currency)))
+	pdf.Ln(8)
+	if view.CreditNoteNumber != nil && view.CreditedAmount != nil {
+		pdf.Cell(0, 7, fmt.Sprintf("Credit note: %s    Credited: %.2f", *view.CreditNoteNumber, *view.CreditedAmount))
+		pdf.Ln(8)
+	}
+	if view.Notes != nil {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Notes: %s", *view.Notes), "", "L", false)
+	}
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.Cell(90, 7, "Shipped by: ____________________")
+	pdf.Cell(0, 7, "Received by: ____________________")
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Cell(0, 6, fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("pdf output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// optionalText returns the value of v, "-" when nil or blank.
+func optionalText(v *string) string {
+	if v == nil || strings.TrimSpace(*v) == "" {
+		return "-"
+	}
+	return *v
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/eflowcr/eSTOCK_backend/models/database"
+	"github.com/eflowcr/eSTOCK_backend/models/requests"
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockVendorReturnsRepo struct {
+	created  *requests.CreateVendorReturnRequest
+	credited *requests.CreditVendorReturnRequest
+	view     *responses.VendorReturnView
+}
+
+func (m *mockVendorReturnsRepo) ListReturns(tenantID string, status, purchaseOrderID, supplierID *string, limit, offset int) ([]database.VendorReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockVendorReturnsRepo) GetReturn(id, tenantID string) (*responses.VendorReturnView, *responses.InternalResponse) {
+	if m.view == nil {
+		return nil, &responses.InternalResponse{Message: "no encontrada", Handled: true, StatusCode: responses.StatusNotFound}
+	}
+	return m.view, nil
+}
+func (m *mockVendorReturnsRepo) CreateReturn(tenantID, userID string, req *requests.CreateVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	m.created = req
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: "v1", Status: database.VendorReturnDraft}}, nil
+}
+func (m *mockVendorReturnsRepo) ShipReturn(id, tenantID string, req *requests.ShipVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	return nil, nil
+}
+func (m *mockVendorReturnsRepo) CreditReturn(id, tenantID string, req *requests.CreditVendorReturnRequest) (*responses.VendorReturnView, *responses.InternalResponse) {
+	m.credited = req
+	return &responses.VendorReturnView{VendorReturn: database.VendorReturn{ID: id, Status: database.VendorReturnCredited}}, nil
+}
+func (m *mockVendorReturnsRepo) CancelReturn(id, tenantID string) (*database.VendorReturn, *responses.InternalResponse) {
+	return nil, nil
+}
+
+func TestVendorReturnsService_CreateReturn_RequiresOneSource(t *testing.T) {
+	po, task, blank := "po-1", "rt-1", " "
+	for name, req := range map[string]*requests.CreateVendorReturnRequest{
+		"none":  {},
+		"blank": {PurchaseOrderID: &blank},
+		"both":  {PurchaseOrderID: &po, ReceivingTaskID: &task},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockVendorReturnsRepo{}
+			_, resp := NewVendorReturnsService(repo).CreateReturn("tenant-1", "user-1", req)
+			require.NotNil(t, resp)
+			assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestVendorReturnsService_CreateReturn_TrimsAndDelegates(t *testing.T) {
+	repo := &mockVendorReturnsRepo{}
+	task, qty := " rt-1 ", 2.0
+	_, resp := NewVendorReturnsService(repo).CreateReturn("tenant-1", "user-1", &requests.CreateVendorReturnRequest{
+		ReceivingTaskID: &task,
+		Lines:           []requests.VendorReturnLineRequest{{ArticleSKU: " SKU-1 ", Qty: &qty}},
+	})
+	require.Nil(t, resp)
+	require.NotNil(t, repo.created)
+	assert.Nil(t, repo.created.PurchaseOrderID)
+	assert.Equal(t, "rt-1", *repo.created.ReceivingTaskID)
+	assert.Equal(t, "SKU-1", repo.created.Lines[0].ArticleSKU)
+}
+
+func TestVendorReturnsService_CreditReturn(t *testing.T) {
+	amount := 10.0
+	future := time.Now().Add(48 * time.Hour)
+
+	repo := &mockVendorReturnsRepo{}
+	svc := NewVendorReturnsService(repo)
+	_, resp := svc.CreditReturn("v1", "tenant-1", &requests.CreditVendorReturnRequest{CreditNoteNumber: "NC-1", CreditedAmount: &amount, CreditedAt: &future})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+
+	_, resp = svc.CreditReturn("v1", "tenant-1", &requests.CreditVendorReturnRequest{CreditNoteNumber: "  ", CreditedAmount: &amount})
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusBadRequest, resp.StatusCode)
+	assert.Nil(t, repo.credited)
+
+	view, resp := svc.CreditReturn("v1", "tenant-1", &requests.CreditVendorReturnRequest{CreditNoteNumber: " NC-1 ", CreditedAmount: &amount})
+	require.Nil(t, resp)
+	assert.Equal(t, database.VendorReturnCredited, view.Status)
+	assert.Equal(t, "NC-1", repo.credited.CreditNoteNumber)
+}
+
+func TestVendorReturnsService_GetReturnPDF(t *testing.T) {
+	cost, po, reason := 4.5, "PO-2026-0001", "damaged"
+	shipped := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockVendorReturnsRepo{view: &responses.VendorReturnView{
+		VendorReturn:   database.VendorReturn{ID: "v1", ReturnNumber: "RTV-2026-0001", Status: database.VendorReturnShipped, ShippedAt: &shipped},
+		PONumber:       &po,
+		SupplierName:   "Proveedor S.A.",
+		Lines:          []database.VendorReturnLine{{ArticleSKU: "SKU-1", Qty: 2, UnitCost: &cost, Reason: &reason}, {ArticleSKU: "SKU-2", Qty: 1}},
+		TotalQty:       3,
+		ExpectedCredit: 9,
+	}}
+	data, filename, resp := NewVendorReturnsService(repo).GetReturnPDF("v1", "tenant-1")
+	require.Nil(t, resp)
+	assert.Equal(t, "RTV-2026-0001.pdf", filename)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+
+	_, _, resp = NewVendorReturnsService(&mockVendorReturnsRepo{}).GetReturnPDF("missing", "tenant-1")
+	require.NotNil(t, resp)
+	assert.Equal(t, responses.StatusNotFound, resp.StatusCode)
+}
@@ -9,6 +9,8 @@ const (
 	ActionExecute = "execute"
 	ActionLogin   = "login"
 	ActionLogout  = "logout"
+	// ActionOverride marks a supervisor bypassing a business rule (e.g. over-receipt allowance).
+	ActionOverride = "override"
 )
 
 // Audit resource types (must match path/domain names used in API)
@@ -20,4 +22,15 @@ const (
 	ResourceInventory    = "inventory"
 	ResourceStockTransfer = "stock_transfer"
 	ResourceAdjustment    = "adjustment"
+	ResourceCycleCount    = "cycle_count"
+	ResourceReceivingTask = "receiving_task"
+	ResourcePickingWave   = "picking_wave"
+	ResourceShipment      = "shipment"
+	ResourceDeliveryNote  = "delivery_note"
+	ResourceCustomerReturn = "customer_return"
+	ResourceVendorReturn   = "vendor_return"
+	ResourceQCHold         = "qc_hold"
+	ResourceLotRecall      = "lot_recall"
+	ResourceKit            = "kit"
+	ResourceAssemblyOrder  = "assembly_order"
 )
@@ -62,6 +62,32 @@ func RunStaleReservationsCleanup(db *gorm.DB) error {
 			return err
 		}
 
+		// Caso 1a: las series reservadas por esos pickings vuelven a stock (con su historial).
+		if err := tx.Exec(`
+			WITH stale_serials AS (
+			  SELECT pt.id AS task_id, pt.task_id AS task_number, pt.tenant_id,
+			         item->>'sku' AS sku, s->>'serial_number' AS serial_number
+			  FROM picking_tasks pt,
+			       jsonb_array_elements(pt.items) item,
+			       jsonb_array_elements(CASE WHEN jsonb_typeof(item->'serials') = 'array'
+			                                 THEN item->'serials' ELSE '[]'::jsonb END) s
+			  WHERE pt.status = 'in_progress'
+			    AND pt.updated_at < NOW() - INTERVAL '7 days'
+			), released AS (
+			  UPDATE serials se
+			     SET status = 'in_stock', updated_at = NOW()
+			    FROM stale_serials ss
+			   WHERE se.tenant_id = ss.tenant_id AND se.sku = ss.sku
+			     AND se.serial_number = ss.serial_number AND se.status = 'reserved'
+			  RETURNING se.id, se.tenant_id, ss.task_id, ss.task_number
+			)
+			INSERT INTO serial_history (tenant_id, serial_id, from_status, to_status, document_type, document_id, document_number, notes)
+			SELECT tenant_id, id, 'reserved', 'in_stock', 'picking_task', task_id, task_number, 'Reserva liberada: picking abandonado'
+			  FROM released;
+		`).Error; err != nil {
+			return err
+		}
+
 		// Caso 1b: después de liberar reservas, marcar esos in_progress como abandoned.
 		if err := tx.Exec(`
 			UPDATE picking_tasks
@@ -435,6 +461,41 @@ func RunTrialExpirationCheck(db *gorm.DB, sendFn func(ctx context.Context, toEma
 	})
 }
 
+// RunReplenishment invokes replenishFn once per active tenant. The callback decides whether
+// the tenant has automatic material requests enabled and drafts the purchase orders; it is
+// injected (like the stock alerts analyzer) to avoid importing services from tools.
+// Advisory lock 987654324 (transaction-scoped) keeps two pods from drafting the same POs.
+func RunReplenishment(db *gorm.DB, replenishFn func(tenantID string) error) error {
+	if db == nil {
+		return errors.New("cron: nil db")
+	}
+	if replenishFn == nil {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(987654324)").Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			log.Debug().Msg("cron: replenishment: otro pod tiene el lock, skipping")
+			return nil
+		}
+
+		tenantIDs, err := activeTenantIDs(tx)
+		if err != nil {
+			return fmt.Errorf("replenishment: list tenants: %w", err)
+		}
+
+		for _, tid := range tenantIDs {
+			if err := replenishFn(tid); err != nil {
+				log.Error().Err(err).Str("tenant_id", tid).Msg("cron: replenishment failed for tenant")
+			}
+		}
+		return nil
+	})
+}
+
 // CronDispatch ejecuta todos los jobs del cron en secuencia.
 // Se invoca: una vez al arrancar (tras delay de estabilización) y luego cada hora por el ticker.
 // Los errores se loggean sin parar la ejecución del siguiente job.
@@ -444,7 +505,8 @@ func RunTrialExpirationCheck(db *gorm.DB, sendFn func(ctx context.Context, toEma
 //   - lotNotifyFn: called per expiring lot event (tenantID, eventType, title, body) — S3.5 W5.5 per-tenant
 //   - lowStockNotifyFn: called per unresolved low-stock alert (tenantID, sku, message) — S3.5 W5.5 per-tenant
 //   - trialSendFn: called per trial tenant requiring a reminder or expiration email
-func CronDispatch(db *gorm.DB, analyzer func(tenantID string) error, lotNotifyFn func(tenantID, eventType, title, body string) error, lowStockNotifyFn func(tenantID, sku, message string) error, trialSendFn func(ctx context.Context, toEmail, tenantName, templateType string, daysLeft int) error) {
+//   - replenishFn: invoked per active tenant to draft replenishment purchase orders
+func CronDispatch(db *gorm.DB, analyzer func(tenantID string) error, lotNotifyFn func(tenantID, eventType, title, body string) error, lowStockNotifyFn func(tenantID, sku, message string) error, trialSendFn func(ctx context.Context, toEmail, tenantName, templateType string, daysLeft int) error, replenishFn func(tenantID string) error) {
 	if err := RunStockAlertAnalysis(db, analyzer); err != nil {
 		log.Error().Err(err).Msg("cron: stock alerts failed")
 	}
@@ -462,5 +524,9 @@ func CronDispatch(db *gorm.DB, analyzer func(tenantID string) error, lotNotifyFn
 	if err := RunTrialExpirationCheck(db, trialSendFn); err != nil {
 		log.Error().Err(err).Msg("cron: trial expiration check failed")
 	}
+	// Replenishment: draft POs for tenants with auto_create_material_request enabled.
+	if err := RunReplenishment(db, replenishFn); err != nil {
+		log.Error().Err(err).Msg("cron: replenishment failed")
+	}
 }
 
@@ -88,9 +88,9 @@ func cronSeedInventory(t *testing.T, db *gorm.DB, sku, location string, qty, res
 	id, err := GenerateNanoid(db)
 	require.NoError(t, err)
 	require.NoError(t, db.Exec(`
-		INSERT INTO inventory (id, sku, name, location, quantity, reserved_qty, status, presentation, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, 'available', 'unit', NOW(), NOW())`,
-		id, sku, sku, location, qty, reserved).Error)
+		INSERT INTO inventory (id, tenant_id, sku, name, location, quantity, reserved_qty, status, presentation, created_at, updated_at)
+		VALUES (?, (SELECT tenant_id FROM articles WHERE sku = ?), ?, ?, ?, ?, ?, 'available', 'unit', NOW(), NOW())`,
+		id, sku, sku, sku, location, qty, reserved).Error)
 	return id
 }
 
@@ -223,7 +223,7 @@ func TestCronDispatch_BothJobsRun(t *testing.T) {
 	}
 
 	// Should not panic — errors are logged, not propagated
-	CronDispatch(db, analyzer, nil, nil, nil)
+	CronDispatch(db, analyzer, nil, nil, nil, nil)
 
 	assert.True(t, analyzerCalled, "analyzer must be called")
 	assert.NotEmpty(t, analyzerTenants, "analyzer must receive at least the default tenant when no tenants exist")
@@ -271,6 +271,13 @@ func TestRunTrialExpirationCheck_NilDB(t *testing.T) {
 	assert.Contains(t, err.Error(), "nil db")
 }
 
+// TestRunReplenishment_NilDB verifies nil db returns an error cleanly.
+func TestRunReplenishment_NilDB(t *testing.T) {
+	err := RunReplenishment(nil, func(string) error { return nil })
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nil db")
+}
+
 // ─── unit tests for email template selection ─────────────────────────────────
 
 // TestRenderTrialEmail_Templates verifies that each templateType produces the
@@ -441,7 +441,7 @@ func seedArticles(ctx context.Context, tx *gorm.DB, tenantID string, categoryIDs
 
 // ─── inventory ───────────────────────────────────────────────────────────────
 
-func seedInventory(ctx context.Context, tx *gorm.DB, _ string, articles []farmaArticle, locationIDs []string) error {
+func seedInventory(ctx context.Context, tx *gorm.DB, tenantID string, articles []farmaArticle, locationIDs []string) error {
 	// Create ~2 inventory rows per article across different locations (100 total, capped at 100).
 	count := 0
 	for i, a := range articles {
@@ -452,6 +452,7 @@ func seedInventory(ctx context.Context, tx *gorm.DB, _ string, articles []farmaA
 
 			inv := database.Inventory{
 				ID:           uuid.NewString(),
+				TenantID:     tenantID,
 				SKU:          a.sku,
 				Name:         a.name,
 				Location:     locCode,
@@ -462,7 +463,7 @@ func seedInventory(ctx context.Context, tx *gorm.DB, _ string, articles []farmaA
 				UnitPrice:    &price,
 			}
 			if err := tx.WithContext(ctx).
-				Where("sku = ? AND location = ?", a.sku, locCode).
+				Where("tenant_id = ? AND sku = ? AND location = ?", tenantID, a.sku, locCode).
 				FirstOrCreate(&inv).Error; err != nil {
 				return fmt.Errorf("inventory %s@%s: %w", a.sku, locCode, err)
 			}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentStorage is the contract for storing generated documents (delivery note PDFs) and
+// uploaded attachments (proof of delivery images). Keys are slash-separated and start with the
+// tenant id (see TenantDocumentKey). Every object carries the SHA-256 of its content: Put
+// returns it and Get verifies it, so a truncated or altered object is reported as
+// ErrDocumentChecksum instead of being served.
+type DocumentStorage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) (StoredDocument, error)
+	Get(ctx context.Context, key string) ([]byte, StoredDocument, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// StoredDocument describes a stored object.
+type StoredDocument struct {
+	Key         string
+	Size        int64
+	SHA256      string
+	ContentType string
+}
+
+var (
+	// ErrDocumentNotFound is returned by Get when the key does not exist.
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrDocumentChecksum is returned by Get when the content does not match its stored SHA-256.
+	ErrDocumentChecksum = errors.New("document checksum mismatch")
+)
+
+// DocumentChecksum returns the hex SHA-256 of data.
+func DocumentChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TenantDocumentKey builds the storage key "<tenantID>/<parts...>".
+func TenantDocumentKey(tenantID string, parts ...string) string {
+	return path.Join(append([]string{tenantID}, parts...)...)
+}
+
+// validateDocumentKey rejects keys that could escape the storage root or bucket prefix.
+func validateDocumentKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid document key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid document key %q", key)
+		}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// LocalDocumentStorage — local filesystem (single instance / dev)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// LocalDocumentStorage stores objects as files under Root. The checksum is kept next to each
+// file in "<file>.sha256". Files are only shared across replicas when Root is a shared volume.
+type LocalDocumentStorage struct {
+	Root string
+}
+
+// NewLocalDocumentStorage returns a storage rooted at root.
+func NewLocalDocumentStorage(root string) *LocalDocumentStorage {
+	return &LocalDocumentStorage{Root: root}
+}
+
+func (l *LocalDocumentStorage) filePath(key string) (string, error) {
+	if err := validateDocumentKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.Root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the object through a temporary file so readers never see a partial file.
+func (l *LocalDocumentStorage) Put(_ context.Context, key string, data []byte, contentType string) (StoredDocument, error) {
+	p, err := l.filePath(key)
+	if err != nil {
+		return StoredDocument{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return StoredDocument{}, fmt.Errorf("local storage: mkdir: %w", err)
+	}
+	checksum := DocumentChecksum(data)
+	if err := writeFileAtomic(p, data); err != nil {
+		return StoredDocument{}, fmt.Errorf("local storage: write %s: %w", key, err)
+	}
+	if err := writeFileAtomic(p+".sha256", []byte(checksum)); err != nil {
+		return StoredDocument{}, fmt.Errorf("local storage: write checksum %s: %w", key, err)
+	}
+	return StoredDocument{Key: key, Size: int64(len(data)), SHA256: checksum, ContentType: contentType}, nil
+}
+
+// Get reads the object and verifies it against its checksum file (when present).
+func (l *LocalDocumentStorage) Get(_ context.Context, key string) ([]byte, StoredDocument, error) {
+	p, err := l.filePath(key)
+	if err != nil {
+		return nil, StoredDocument{}, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, StoredDocument{}, ErrDocumentNotFound
+		}
+		return nil, StoredDocument{}, fmt.Errorf("local storage: read %s: %w", key, err)
+	}
+	checksum := DocumentChecksum(data)
+	if stored, err := os.ReadFile(p + ".sha256"); err == nil && strings.TrimSpace(string(stored)) != checksum {
+		return nil, StoredDocument{}, ErrDocumentChecksum
+	}
+	return data, StoredDocument{Key: key, Size: int64(len(data)), SHA256: checksum}, nil
+}
+
+// Delete removes the object and its checksum file; a missing object is not an error.
+func (l *LocalDocumentStorage) Delete(_ context.Context, key string) error {
+	p, err := l.filePath(key)
+	if err != nil {
+		return err
+	}
+	for _, f := range []string{p, p + ".sha256"} {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("local storage: delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func writeFileAtomic(p string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
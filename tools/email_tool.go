@@ -324,7 +324,7 @@ func RenderNotificationEmail(eventType, title, body string) (htmlBody, text stri
 	switch eventType {
 	case "task_assigned":
 		return renderTaskAssignedHTML(title, body), fmt.Sprintf("%s\n\n%s", title, body)
-	case "task_completed":
+	case "task_completed", "order_delivered", "lot_recall":
 		return renderGenericHTML(title, body), fmt.Sprintf("%s\n\n%s", title, body)
 	case "lot_expiring_7d":
 		return renderLotExpiringHTML(title, body), fmt.Sprintf("%s\n\n%s", title, body)
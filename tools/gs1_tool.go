@@ -0,0 +1,380 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GS1 application identifiers (AIs) used on eSTOCK labels and understood by the scan parser.
+// Articles carry no GTIN of their own, so eSTOCK labels put the SKU in AI 240 (additional
+// product identification assigned by the manufacturer).
+const (
+	GS1AISSCC          = "00"
+	GS1AIGTIN          = "01"
+	GS1AIContentGTIN   = "02"
+	GS1AILot           = "10"
+	GS1AIProductionDay = "11"
+	GS1AIBestBefore    = "15"
+	GS1AIExpiry        = "17"
+	GS1AISerial        = "21"
+	GS1AIVariableCount = "30"
+	GS1AICount         = "37"
+	GS1AIProductID     = "240"
+	GS1AIOrderNumber   = "400"
+	GS1AIShipToGLN     = "410"
+)
+
+// GS1GroupSeparator (ASCII 29) terminates a variable-length element when another follows.
+// In GS1-128 the separator is encoded as FNC1; scanners transmit it as GS.
+const GS1GroupSeparator = "\x1d"
+
+// gs1AISpec describes the data length of an AI: fixed length, or a maximum for variable ones.
+type gs1AISpec struct {
+	fixed   int
+	maxLen  int
+	numeric bool
+}
+
+var gs1AISpecs = map[string]gs1AISpec{
+	GS1AISSCC:          {fixed: 18, numeric: true},
+	GS1AIGTIN:          {fixed: 14, numeric: true},
+	GS1AIContentGTIN:   {fixed: 14, numeric: true},
+	GS1AILot:           {maxLen: 20},
+	GS1AIProductionDay: {fixed: 6, numeric: true},
+	GS1AIBestBefore:    {fixed: 6, numeric: true},
+	GS1AIExpiry:        {fixed: 6, numeric: true},
+	GS1AISerial:        {maxLen: 20},
+	GS1AIVariableCount: {maxLen: 8, numeric: true},
+	GS1AICount:         {maxLen: 8, numeric: true},
+	GS1AIProductID:     {maxLen: 30},
+	GS1AIOrderNumber:   {maxLen: 30},
+	GS1AIShipToGLN:     {fixed: 13, numeric: true},
+}
+
+// gs1Charset82 is the GS1 AI encodable character set 82 allowed in alphanumeric AIs.
+const gs1Charset82 = "!\"%&'()*+,-./0123456789:;<=>?ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+// GS1Element is one application identifier with its data.
+type GS1Element struct {
+	AI    string
+	Value string
+}
+
+// IsFixedLength reports whether the element's AI has a predefined length (no separator needed).
+func (e GS1Element) IsFixedLength() bool {
+	return gs1AISpecs[e.AI].fixed > 0
+}
+
+// ValidateGS1Element checks the AI is supported and its data fits the AI's length and charset.
+func ValidateGS1Element(e GS1Element) error {
+	spec, ok := gs1AISpecs[e.AI]
+	if !ok {
+		return fmt.Errorf("unsupported GS1 application identifier (%s)", e.AI)
+	}
+	if e.Value == "" {
+		return fmt.Errorf("GS1 (%s) value is empty", e.AI)
+	}
+	if spec.fixed > 0 && len(e.Value) != spec.fixed {
+		return fmt.Errorf("GS1 (%s) must be %d characters, got %d", e.AI, spec.fixed, len(e.Value))
+	}
+	if spec.maxLen > 0 && len(e.Value) > spec.maxLen {
+		return fmt.Errorf("GS1 (%s) allows at most %d characters, got %d", e.AI, spec.maxLen, len(e.Value))
+	}
+	for _, r := range e.Value {
+		if spec.numeric && (r < '0' || r > '9') {
+			return fmt.Errorf("GS1 (%s) must be numeric", e.AI)
+		}
+		if !strings.ContainsRune(gs1Charset82, r) {
+			return fmt.Errorf("GS1 (%s) contains unsupported character %q", e.AI, r)
+		}
+	}
+	return nil
+}
+
+// GS1ElementString concatenates elements the way they are encoded in the symbol: fixed-length
+// elements first, then variable-length ones, with sep after every variable-length element
+// except the last. Pass GS1GroupSeparator for QR payloads, or the symbology's FNC1 character.
+func GS1ElementString(elements []GS1Element, sep string) string {
+	ordered := orderGS1Elements(elements)
+	var b strings.Builder
+	for i, e := range ordered {
+		b.WriteString(e.AI)
+		b.WriteString(e.Value)
+		if !e.IsFixedLength() && i < len(ordered)-1 {
+			b.WriteString(sep)
+		}
+	}
+	return b.String()
+}
+
+// GS1HumanReadable renders elements as the interpretation line printed under the symbol,
+// e.g. "(17)261231(10)L-001(240)SKU-1".
+func GS1HumanReadable(elements []GS1Element) string {
+	var b strings.Builder
+	for _, e := range orderGS1Elements(elements) {
+		b.WriteString("(" + e.AI + ")" + e.Value)
+	}
+	return b.String()
+}
+
+func orderGS1Elements(elements []GS1Element) []GS1Element {
+	ordered := make([]GS1Element, 0, len(elements))
+	for _, e := range elements {
+		if e.IsFixedLength() {
+			ordered = append(ordered, e)
+		}
+	}
+	for _, e := range elements {
+		if !e.IsFixedLength() {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+// GS1CheckDigit computes the mod-10 check digit of a GTIN/SSCC/GLN body (all digits but the last).
+func GS1CheckDigit(body string) (byte, error) {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		c := body[i]
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%q is not numeric", body)
+		}
+		d := int(c - '0')
+		// Weights alternate 3,1,3,… starting from the digit next to the check digit.
+		if (len(body)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// ValidGS1CheckDigit reports whether the last digit of code is its correct GS1 check digit.
+func ValidGS1CheckDigit(code string) bool {
+	if len(code) < 2 {
+		return false
+	}
+	check, err := GS1CheckDigit(code[:len(code)-1])
+	return err == nil && check == code[len(code)-1]
+}
+
+// NormalizeGTIN left-pads a GTIN-8/12/13/14 to 14 digits and verifies its check digit.
+func NormalizeGTIN(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("GTIN must have 8, 12, 13 or 14 digits, got %d", len(code))
+	}
+	gtin := strings.Repeat("0", 14-len(code)) + code
+	if !ValidGS1CheckDigit(gtin) {
+		return "", fmt.Errorf("GTIN %s has an invalid check digit", code)
+	}
+	return gtin, nil
+}
+
+// GS1 symbology identifiers (AIM) that scanners may prepend to the data.
+var gs1SymbologyIDs = map[string]string{
+	"]C1": "gs1-128",
+	"]d2": "gs1-datamatrix",
+	"]Q3": "gs1-qr",
+	"]e0": "gs1-databar",
+}
+
+// GS1Scan is a decoded GS1 element string.
+type GS1Scan struct {
+	// Symbology comes from the AIM identifier when the scanner sent one ("gs1-128", ...).
+	Symbology string
+	Elements  []GS1Element
+}
+
+// Value returns the data of the first element with ai, or "".
+func (s GS1Scan) Value(ai string) string {
+	for _, e := range s.Elements {
+		if e.AI == ai {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+var gs1HumanReadableRe = regexp.MustCompile(`\((\d{2,4})\)([^(]*)`)
+
+// ParseGS1 decodes scanner output into GS1 elements. It accepts the raw element string
+// (optionally prefixed with an AIM identifier such as "]C1" or "]d2", or with a leading
+// FNC1 transmitted as GS) and the human-readable form "(01)…(10)…". ok is false when the
+// input is not GS1 data at all (a plain SKU or internal code); err is set when it looks
+// like GS1 data but cannot be decoded.
+func ParseGS1(raw string) (scan GS1Scan, ok bool, err error) {
+	data := strings.TrimSpace(raw)
+	explicit := false
+	if len(data) >= 3 && data[0] == ']' {
+		sym, known := gs1SymbologyIDs[data[:3]]
+		if !known {
+			return GS1Scan{}, false, nil
+		}
+		scan.Symbology = sym
+		data = data[3:]
+		explicit = true
+	}
+	if strings.HasPrefix(data, GS1GroupSeparator) {
+		data = strings.TrimLeft(data, GS1GroupSeparator)
+		explicit = true
+	}
+
+	if strings.HasPrefix(data, "(") {
+		matches := gs1HumanReadableRe.FindAllStringSubmatch(data, -1)
+		if len(matches) == 0 {
+			return GS1Scan{}, false, nil
+		}
+		for _, m := range matches {
+			e := GS1Element{AI: m[1], Value: strings.TrimSpace(m[2])}
+			if err := ValidateGS1Element(e); err != nil {
+				return GS1Scan{}, true, err
+			}
+			scan.Elements = append(scan.Elements, e)
+		}
+		return scan, true, validateGS1Scan(scan)
+	}
+
+	elements, err := parseGS1ElementString(data)
+	if err != nil {
+		if explicit {
+			return GS1Scan{}, true, err
+		}
+		// Without an AIM identifier or FNC1 this is most likely a plain code.
+		return GS1Scan{}, false, nil
+	}
+	// Without an AIM identifier or FNC1, only accept strings that start with a GTIN or SSCC:
+	// a plain code such as "10045" would otherwise decode as lot "045".
+	if !explicit && elements[0].AI != GS1AIGTIN && elements[0].AI != GS1AISSCC && elements[0].AI != GS1AIContentGTIN {
+		return GS1Scan{}, false, nil
+	}
+	scan.Elements = elements
+	if err := validateGS1Scan(scan); err != nil {
+		if explicit {
+			return GS1Scan{}, true, err
+		}
+		return GS1Scan{}, false, nil
+	}
+	return scan, true, nil
+}
+
+func parseGS1ElementString(data string) ([]GS1Element, error) {
+	var elements []GS1Element
+	for len(data) > 0 {
+		ai, spec, found := "", gs1AISpec{}, false
+		for n := 2; n <= 4 && n <= len(data); n++ {
+			if sp, known := gs1AISpecs[data[:n]]; known {
+				ai, spec, found = data[:n], sp, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown GS1 application identifier at %q", data)
+		}
+		data = data[len(ai):]
+
+		var value string
+		if spec.fixed > 0 {
+			if len(data) < spec.fixed {
+				return nil, fmt.Errorf("GS1 (%s) is truncated", ai)
+			}
+			value, data = data[:spec.fixed], data[spec.fixed:]
+			// A separator after a fixed-length element is tolerated.
+			data = strings.TrimPrefix(data, GS1GroupSeparator)
+		} else {
+			end := strings.Index(data, GS1GroupSeparator)
+			if end < 0 {
+				value, data = data, ""
+			} else {
+				value, data = data[:end], data[end+1:]
+			}
+		}
+		e := GS1Element{AI: ai, Value: value}
+		if err := ValidateGS1Element(e); err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("empty GS1 element string")
+	}
+	return elements, nil
+}
+
+// validateGS1Scan checks the check digits of GTIN/SSCC elements and the dates.
+func validateGS1Scan(scan GS1Scan) error {
+	for _, e := range scan.Elements {
+		switch e.AI {
+		case GS1AISSCC, GS1AIGTIN, GS1AIContentGTIN, GS1AIShipToGLN:
+			if !ValidGS1CheckDigit(e.Value) {
+				return fmt.Errorf("GS1 (%s) %s has an invalid check digit", e.AI, e.Value)
+			}
+		case GS1AIExpiry, GS1AIBestBefore, GS1AIProductionDay:
+			if _, err := ParseGS1Date(e.Value, time.Now()); err != nil {
+				return fmt.Errorf("GS1 (%s): %w", e.AI, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseGS1Date decodes a YYMMDD date. The century follows the GS1 sliding window (up to 49
+// years in the past, 50 in the future relative to now); day 00 means the last day of the month.
+func ParseGS1Date(yymmdd string, now time.Time) (time.Time, error) {
+	if len(yymmdd) != 6 {
+		return time.Time{}, fmt.Errorf("date %q must be YYMMDD", yymmdd)
+	}
+	var yy, mm, dd int
+	if _, err := fmt.Sscanf(yymmdd, "%02d%02d%02d", &yy, &mm, &dd); err != nil {
+		return time.Time{}, fmt.Errorf("date %q must be YYMMDD", yymmdd)
+	}
+	if mm < 1 || mm > 12 || dd > 31 {
+		return time.Time{}, fmt.Errorf("date %q is not a valid date", yymmdd)
+	}
+	currentYY := now.Year() % 100
+	century := now.Year() - currentYY
+	switch diff := yy - currentYY; {
+	case diff >= 51:
+		century -= 100
+	case diff <= -50:
+		century += 100
+	}
+	year := century + yy
+	if dd == 0 {
+		return time.Date(year, time.Month(mm)+1, 0, 0, 0, 0, 0, time.UTC), nil
+	}
+	t := time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if t.Day() != dd {
+		return time.Time{}, fmt.Errorf("date %q is not a valid date", yymmdd)
+	}
+	return t, nil
+}
+
+// BuildSSCC builds an 18-digit SSCC (AI 00): extension digit, GS1 company prefix, serial
+// reference zero-padded to fill 17 digits, and the check digit. The serial reference gets
+// 16 - len(companyPrefix) digits; a serial that does not fit is an error.
+func BuildSSCC(extension byte, companyPrefix string, serial int64) (string, error) {
+	if extension > 9 {
+		return "", fmt.Errorf("SSCC extension digit must be 0-9, got %d", extension)
+	}
+	if len(companyPrefix) < 7 || len(companyPrefix) > 10 {
+		return "", fmt.Errorf("GS1 company prefix must have 7 to 10 digits, got %q", companyPrefix)
+	}
+	width := 16 - len(companyPrefix)
+	ref := strconv.FormatInt(serial, 10)
+	if serial < 0 || len(ref) > width {
+		return "", fmt.Errorf("SSCC serial reference %d does not fit in %d digits", serial, width)
+	}
+	body := string(rune('0'+extension)) + companyPrefix + strings.Repeat("0", width-len(ref)) + ref
+	check, err := GS1CheckDigit(body)
+	if err != nil {
+		return "", err
+	}
+	return body + string(check), nil
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGS1ElementString_FixedFirstAndSeparators(t *testing.T) {
+	elements := []GS1Element{
+		{AI: GS1AILot, Value: "L-001"},
+		{AI: GS1AIProductID, Value: "SKU-1"},
+		{AI: GS1AIExpiry, Value: "261231"},
+	}
+	assert.Equal(t, "17261231"+"10L-001"+GS1GroupSeparator+"240SKU-1", GS1ElementString(elements, GS1GroupSeparator))
+	assert.Equal(t, "(17)261231(10)L-001(240)SKU-1", GS1HumanReadable(elements))
+}
+
+func TestGS1ElementString_NoTrailingSeparator(t *testing.T) {
+	elements := []GS1Element{{AI: GS1AISerial, Value: "SN1"}}
+	assert.Equal(t, "21SN1", GS1ElementString(elements, GS1GroupSeparator))
+}
+
+func TestValidateGS1Element(t *testing.T) {
+	assert.NoError(t, ValidateGS1Element(GS1Element{AI: GS1AILot, Value: "ABC-123/x"}))
+	assert.NoError(t, ValidateGS1Element(GS1Element{AI: GS1AIExpiry, Value: "261231"}))
+
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: "99", Value: "x"}), "unsupported AI")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AILot, Value: ""}), "empty value")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AILot, Value: "123456789012345678901"}), "lot longer than 20")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AIExpiry, Value: "2612"}), "wrong fixed length")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AIExpiry, Value: "26123A"}), "non-numeric")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AISerial, Value: "SN 1"}), "space is not in charset 82")
+	assert.Error(t, ValidateGS1Element(GS1Element{AI: GS1AISerial, Value: "SÑ1"}), "non-ASCII")
+}
+
+func TestGS1CheckDigitAndNormalizeGTIN(t *testing.T) {
+	check, err := GS1CheckDigit("0950600013435")
+	assert.NoError(t, err)
+	assert.Equal(t, byte('2'), check)
+	assert.True(t, ValidGS1CheckDigit("09506000134352"))
+	assert.False(t, ValidGS1CheckDigit("09506000134353"))
+
+	gtin, err := NormalizeGTIN("9506000134352")
+	assert.NoError(t, err)
+	assert.Equal(t, "09506000134352", gtin)
+
+	_, err = NormalizeGTIN("9506000134353")
+	assert.Error(t, err, "bad check digit")
+	_, err = NormalizeGTIN("12345")
+	assert.Error(t, err, "bad length")
+}
+
+func TestParseGS1_ElementStringWithAIMAndSeparators(t *testing.T) {
+	raw := "]d2" + "0109506000134352" + "17261231" + "10LOT-7" + GS1GroupSeparator + "21SN001"
+	scan, ok, err := ParseGS1(raw)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "gs1-datamatrix", scan.Symbology)
+	assert.Equal(t, "09506000134352", scan.Value(GS1AIGTIN))
+	assert.Equal(t, "261231", scan.Value(GS1AIExpiry))
+	assert.Equal(t, "LOT-7", scan.Value(GS1AILot))
+	assert.Equal(t, "SN001", scan.Value(GS1AISerial))
+}
+
+func TestParseGS1_LeadingFNC1AndHumanReadable(t *testing.T) {
+	scan, ok, err := ParseGS1(GS1GroupSeparator + "0109506000134352" + "3712")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "12", scan.Value(GS1AICount))
+
+	scan, ok, err = ParseGS1("(01)09506000134352(10)ABC(17)270600")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ABC", scan.Value(GS1AILot))
+	assert.Equal(t, "270600", scan.Value(GS1AIExpiry))
+}
+
+func TestParseGS1_PlainCodesAreNotGS1(t *testing.T) {
+	for _, raw := range []string{"SKU-001", "10045", "7501234567893", "]E0123"} {
+		_, ok, err := ParseGS1(raw)
+		assert.NoError(t, err, raw)
+		assert.False(t, ok, raw)
+	}
+}
+
+func TestParseGS1_InvalidExplicitData(t *testing.T) {
+	_, ok, err := ParseGS1("]C1" + "0109506000134353")
+	assert.True(t, ok)
+	assert.Error(t, err, "bad GTIN check digit")
+
+	_, ok, err = ParseGS1("]C1" + "0109506000134352" + "17261332")
+	assert.True(t, ok)
+	assert.Error(t, err, "month 13")
+
+	_, ok, err = ParseGS1("]C1" + "99abc")
+	assert.True(t, ok)
+	assert.Error(t, err, "unknown AI")
+}
+
+func TestParseGS1Date(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	d, err := ParseGS1Date("270615", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2027, 6, 15, 0, 0, 0, 0, time.UTC), d)
+
+	d, err = ParseGS1Date("280200", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), d, "day 00 is the last day of the month")
+
+	d, err = ParseGS1Date("990101", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1999, d.Year(), "more than 50 years ahead falls in the previous century")
+
+	_, err = ParseGS1Date("270231", now)
+	assert.Error(t, err)
+}
+
+func TestBuildSSCC(t *testing.T) {
+	sscc, err := BuildSSCC(1, "0614141", 123456789)
+	assert.NoError(t, err)
+	assert.Equal(t, "106141411234567897", sscc)
+	assert.True(t, ValidGS1CheckDigit(sscc))
+
+	sscc, err = BuildSSCC(0, "0000000", 42)
+	assert.NoError(t, err)
+	assert.Len(t, sscc, 18)
+	assert.Equal(t, "00000000000000042", sscc[:17], "serial reference is zero-padded")
+
+	_, err = BuildSSCC(0, "0614141", 1234567890)
+	assert.Error(t, err, "serial reference overflows 9 digits")
+
+	_, err = BuildSSCC(0, "123", 1)
+	assert.Error(t, err, "company prefix too short")
+
+	_, err = BuildSSCC(10, "0614141", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"gorm.io/gorm"
+)
+
+// capacityEpsilon absorbs float noise from NUMERIC quantities and the weight/volume products.
+const capacityEpsilon = 1e-6
+
+// SQL fragments for the per-unit load of an article row aliased "a". Articles without
+// weight or full dimensions count as zero on that dimension.
+const (
+	ArticleUnitWeightSQL = "COALESCE(a.weight_kg, 0)"
+	ArticleUnitVolumeSQL = "COALESCE(a.length_cm * a.width_cm * a.height_cm, 0) / 1000000.0"
+)
+
+// LocationLimits are the effective capacity limits of a location: its own value, else the
+// value of its location type. Nil means unlimited on that dimension.
+type LocationLimits struct {
+	MaxUnits    *float64 `gorm:"column:max_units"`
+	MaxWeightKg *float64 `gorm:"column:max_weight_kg"`
+	MaxVolumeM3 *float64 `gorm:"column:max_volume_m3"`
+}
+
+// Unlimited reports whether no dimension is capped.
+func (l LocationLimits) Unlimited() bool {
+	return l.MaxUnits == nil && l.MaxWeightKg == nil && l.MaxVolumeM3 == nil
+}
+
+// LocationLoad is what a location holds (or is about to receive) on each dimension.
+type LocationLoad struct {
+	Units    float64 `gorm:"column:units"`
+	WeightKg float64 `gorm:"column:weight_kg"`
+	VolumeM3 float64 `gorm:"column:volume_m3"`
+}
+
+// CapacityAddition is a quantity of a SKU about to be put into a location.
+type CapacityAddition struct {
+	SKU      string
+	Quantity float64
+}
+
+// ArticleUnitLoad returns the weight (kg) and volume (m³) of one unit from the article's
+// weight and dimensions in centimetres. Missing values count as zero.
+func ArticleUnitLoad(weightKg, lengthCm, widthCm, heightCm *float64) (float64, float64) {
+	var weight, volume float64
+	if weightKg != nil {
+		weight = *weightKg
+	}
+	if lengthCm != nil && widthCm != nil && heightCm != nil {
+		volume = *lengthCm * *widthCm * *heightCm / 1e6
+	}
+	return weight, volume
+}
+
+// EvaluateLocationCapacity returns the first dimension (units, weight, volume) on which
+// current+incoming goes over the limit, or nil. A dimension the incoming stock does not add
+// to is never reported, so a bin that is already over capacity can still take stock that
+// does not make it worse (e.g. weightless articles into a bin over its weight limit).
+func EvaluateLocationCapacity(location string, limits LocationLimits, current, incoming LocationLoad) *responses.CapacityExceeded {
+	checks := []struct {
+		dimension string
+		max       *float64
+		cur, add  float64
+	}{
+		{responses.CapacityUnits, limits.MaxUnits, current.Units, incoming.Units},
+		{responses.CapacityWeight, limits.MaxWeightKg, current.WeightKg, incoming.WeightKg},
+		{responses.CapacityVolume, limits.MaxVolumeM3, current.VolumeM3, incoming.VolumeM3},
+	}
+	for _, c := range checks {
+		if c.max == nil || c.add <= 0 {
+			continue
+		}
+		if c.cur+c.add > *c.max+capacityEpsilon {
+			return &responses.CapacityExceeded{
+				Location:  location,
+				Dimension: c.dimension,
+				Capacity:  *c.max,
+				Current:   c.cur,
+				Incoming:  c.add,
+				Projected: c.cur + c.add,
+			}
+		}
+	}
+	return nil
+}
+
+// CapacityPct returns used as a percentage of max, or nil when max is unlimited.
+func CapacityPct(used float64, max *float64) *float64 {
+	if max == nil || *max <= 0 {
+		return nil
+	}
+	pct := math.Round(used / *max * 10000) / 100
+	return &pct
+}
+
+// GetLocationLimits reads the effective limits of a tenant location. An unknown location
+// yields no limits: existence is validated by the callers, not here.
+func GetLocationLimits(tx *gorm.DB, tenantID, locationCode string) (LocationLimits, error) {
+	var limits LocationLimits
+	err := tx.Raw(`
+		SELECT COALESCE(l.max_units, lt.max_units) AS max_units,
+		       COALESCE(l.max_weight_kg, lt.max_weight_kg) AS max_weight_kg,
+		       COALESCE(l.max_volume_m3, lt.max_volume_m3) AS max_volume_m3
+		FROM locations l
+		LEFT JOIN location_types lt ON UPPER(lt.code) = UPPER(l.type)
+		WHERE l.tenant_id = ? AND l.location_code = ?
+		LIMIT 1`, tenantID, locationCode).Scan(&limits).Error
+	if err != nil {
+		return LocationLimits{}, fmt.Errorf("read capacity of %s: %w", locationCode, err)
+	}
+	return limits, nil
+}
+
+// GetLocationLoad sums the units, weight and volume of the stock at a tenant location.
+func GetLocationLoad(tx *gorm.DB, tenantID, locationCode string) (LocationLoad, error) {
+	var load LocationLoad
+	err := tx.Raw(`
+		SELECT COALESCE(SUM(i.quantity), 0) AS units,
+		       COALESCE(SUM(i.quantity * `+ArticleUnitWeightSQL+`), 0) AS weight_kg,
+		       COALESCE(SUM(i.quantity * `+ArticleUnitVolumeSQL+`), 0) AS volume_m3
+		FROM inventory i
+		LEFT JOIN articles a ON a.sku = i.sku AND a.tenant_id = i.tenant_id
+		WHERE i.tenant_id = ? AND i.location = ?`, tenantID, locationCode).Scan(&load).Error
+	if err != nil {
+		return LocationLoad{}, fmt.Errorf("read stock load of %s: %w", locationCode, err)
+	}
+	return load, nil
+}
+
+// additionsLoad converts additions into a load using the articles' weight and dimensions.
+func additionsLoad(tx *gorm.DB, tenantID string, additions []CapacityAddition) (LocationLoad, error) {
+	var load LocationLoad
+	skus := make([]string, 0, len(additions))
+	for _, a := range additions {
+		load.Units += a.Quantity
+		skus = append(skus, a.SKU)
+	}
+	if len(skus) == 0 {
+		return load, nil
+	}
+
+	var rows []struct {
+		SKU      string   `gorm:"column:sku"`
+		WeightKg *float64 `gorm:"column:weight_kg"`
+		LengthCm *float64 `gorm:"column:length_cm"`
+		WidthCm  *float64 `gorm:"column:width_cm"`
+		HeightCm *float64 `gorm:"column:height_cm"`
+	}
+	if err := tx.Raw(
+		"SELECT sku, weight_kg, length_cm, width_cm, height_cm FROM articles WHERE tenant_id = ? AND sku IN ?",
+		tenantID, skus,
+	).Scan(&rows).Error; err != nil {
+		return LocationLoad{}, fmt.Errorf("read article dimensions: %w", err)
+	}
+	type unitLoad struct{ weight, volume float64 }
+	perUnit := make(map[string]unitLoad, len(rows))
+	for _, r := range rows {
+		w, v := ArticleUnitLoad(r.WeightKg, r.LengthCm, r.WidthCm, r.HeightCm)
+		perUnit[r.SKU] = unitLoad{w, v}
+	}
+	for _, a := range additions {
+		u := perUnit[a.SKU]
+		load.WeightKg += a.Quantity * u.weight
+		load.VolumeM3 += a.Quantity * u.volume
+	}
+	return load, nil
+}
+
+// CheckLocationCapacity reports whether putting additions into a tenant location would take
+// it over capacity. Call it inside the transaction that writes the stock: the location row
+// is locked FOR UPDATE until that transaction ends, so concurrent puts into the same bin are
+// checked one after the other against the load the previous one left. Returns (nil, nil)
+// when everything fits.
+func CheckLocationCapacity(tx *gorm.DB, tenantID, locationCode string, additions []CapacityAddition) (*responses.CapacityExceeded, error) {
+	if err := lockLocation(tx, tenantID, locationCode); err != nil {
+		return nil, err
+	}
+	limits, err := GetLocationLimits(tx, tenantID, locationCode)
+	if err != nil || limits.Unlimited() {
+		return nil, err
+	}
+	current, err := GetLocationLoad(tx, tenantID, locationCode)
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := additionsLoad(tx, tenantID, additions)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateLocationCapacity(locationCode, limits, current, incoming), nil
+}
+
+// lockLocation takes a row lock on a tenant location for the rest of the transaction. An
+// unknown location locks nothing.
+func lockLocation(tx *gorm.DB, tenantID, locationCode string) error {
+	var ids []string
+	if err := tx.Raw(`SELECT id FROM locations WHERE tenant_id = ? AND location_code = ? FOR UPDATE`,
+		tenantID, locationCode).Scan(&ids).Error; err != nil {
+		return fmt.Errorf("lock location %s: %w", locationCode, err)
+	}
+	return nil
+}
+
+// capacityDimensionLabels are the user-facing names of each capacity dimension.
+var capacityDimensionLabels = map[string]string{
+	responses.CapacityUnits:  "unidades",
+	responses.CapacityWeight: "peso (kg)",
+	responses.CapacityVolume: "volumen (m³)",
+}
+
+// CapacityExceededResponse builds the handled 409 for a capacity violation; Details carries
+// the numbers for clients.
+func CapacityExceededResponse(detail *responses.CapacityExceeded) *responses.InternalResponse {
+	return &responses.InternalResponse{
+		Message: fmt.Sprintf("La ubicación %s excede su capacidad de %s: actual %.2f + entrante %.2f = %.2f, máximo %.2f",
+			detail.Location, capacityDimensionLabels[detail.Dimension], detail.Current, detail.Incoming, detail.Projected, detail.Capacity),
+		Handled:    true,
+		StatusCode: responses.StatusConflict,
+		Details:    detail,
+	}
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func f64(v float64) *float64 { return &v }
+
+func TestArticleUnitLoad(t *testing.T) {
+	w, v := ArticleUnitLoad(f64(2.5), f64(50), f64(40), f64(30))
+	assert.Equal(t, 2.5, w)
+	assert.InDelta(t, 0.06, v, 1e-12)
+
+	// Partial dimensions do not yield a volume.
+	w, v = ArticleUnitLoad(nil, f64(50), nil, f64(30))
+	assert.Zero(t, w)
+	assert.Zero(t, v)
+}
+
+func TestEvaluateLocationCapacity_Fits(t *testing.T) {
+	limits := LocationLimits{MaxUnits: f64(100), MaxWeightKg: f64(500)}
+	got := EvaluateLocationCapacity("A-01", limits, LocationLoad{Units: 60, WeightKg: 300}, LocationLoad{Units: 40, WeightKg: 200})
+	assert.Nil(t, got, "filling exactly to the limit is allowed")
+}
+
+func TestEvaluateLocationCapacity_Unlimited(t *testing.T) {
+	got := EvaluateLocationCapacity("A-01", LocationLimits{}, LocationLoad{Units: 1e6}, LocationLoad{Units: 1e6})
+	assert.Nil(t, got)
+	assert.True(t, LocationLimits{}.Unlimited())
+}
+
+func TestEvaluateLocationCapacity_UnitsExceeded(t *testing.T) {
+	limits := LocationLimits{MaxUnits: f64(100)}
+	got := EvaluateLocationCapacity("A-01", limits, LocationLoad{Units: 90}, LocationLoad{Units: 15})
+	require.NotNil(t, got)
+	assert.Equal(t, responses.CapacityUnits, got.Dimension)
+	assert.Equal(t, "A-01", got.Location)
+	assert.Equal(t, 100.0, got.Capacity)
+	assert.Equal(t, 105.0, got.Projected)
+}
+
+func TestEvaluateLocationCapacity_WeightAndVolume(t *testing.T) {
+	limits := LocationLimits{MaxUnits: f64(1000), MaxWeightKg: f64(50), MaxVolumeM3: f64(1)}
+
+	got := EvaluateLocationCapacity("B-02", limits, LocationLoad{Units: 10, WeightKg: 45}, LocationLoad{Units: 5, WeightKg: 10})
+	require.NotNil(t, got)
+	assert.Equal(t, responses.CapacityWeight, got.Dimension)
+
+	got = EvaluateLocationCapacity("B-02", limits, LocationLoad{VolumeM3: 0.9}, LocationLoad{Units: 1, VolumeM3: 0.2})
+	require.NotNil(t, got)
+	assert.Equal(t, responses.CapacityVolume, got.Dimension)
+}
+
+func TestEvaluateLocationCapacity_IgnoresDimensionNotAddedTo(t *testing.T) {
+	// Already over its weight limit, but the incoming article has no weight recorded.
+	limits := LocationLimits{MaxWeightKg: f64(50)}
+	got := EvaluateLocationCapacity("B-02", limits, LocationLoad{Units: 10, WeightKg: 60}, LocationLoad{Units: 5})
+	assert.Nil(t, got)
+}
+
+func TestCapacityPct(t *testing.T) {
+	assert.Nil(t, CapacityPct(10, nil))
+	pct := CapacityPct(1, f64(3))
+	require.NotNil(t, pct)
+	assert.Equal(t, 33.33, *pct)
+}
+
+func TestCapacityExceededResponse(t *testing.T) {
+	detail := &responses.CapacityExceeded{Location: "A-01", Dimension: responses.CapacityWeight, Capacity: 50, Current: 45, Incoming: 10, Projected: 55}
+	resp := CapacityExceededResponse(detail)
+	assert.True(t, resp.Handled)
+	assert.Equal(t, responses.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Message, "A-01")
+	assert.Contains(t, resp.Message, "peso")
+	assert.Same(t, detail, resp.Details)
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Number series patterns generate lot and serial numbers, e.g. "LOT-{YYYY}{MM}-{####}".
+// Tokens: {YYYY}, {YY}, {MM}, {DD} (date of generation), {SKU} (the article) and exactly one
+// counter {#...#} padded to the number of #. A series without any token is a plain prefix
+// and gets PrefixCounterToken appended.
+
+// PrefixCounterToken is the counter appended to a series that is only a prefix.
+const PrefixCounterToken = "{######}"
+
+// seriesCounterMarker replaces the counter in a series key.
+const seriesCounterMarker = "{#}"
+
+type patternPart struct {
+	literal string
+	token   string
+	width   int
+}
+
+// NumberPattern is a parsed number series pattern.
+type NumberPattern struct {
+	raw   string
+	parts []patternPart
+}
+
+// ParseNumberPattern validates pattern and returns its parsed form. The error message is
+// meant for the user (Spanish), since patterns come from settings and articles.
+func ParseNumberPattern(pattern string) (*NumberPattern, error) {
+	raw := strings.TrimSpace(pattern)
+	if raw == "" {
+		return nil, fmt.Errorf("el patrón de numeración está vacío")
+	}
+	if !strings.ContainsAny(raw, "{}") {
+		raw += PrefixCounterToken
+	}
+
+	p := &NumberPattern{raw: raw}
+	counters := 0
+	rest := raw
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if closeIdx := strings.IndexByte(rest, '}'); closeIdx >= 0 && (open < 0 || closeIdx < open) {
+			return nil, fmt.Errorf("el patrón %q tiene una llave } sin abrir", raw)
+		}
+		if open < 0 {
+			p.parts = append(p.parts, patternPart{literal: rest})
+			break
+		}
+		if open > 0 {
+			p.parts = append(p.parts, patternPart{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("el patrón %q tiene una llave { sin cerrar", raw)
+		}
+		token := rest[open+1 : open+end]
+		switch {
+		case token == "YYYY" || token == "YY" || token == "MM" || token == "DD" || token == "SKU":
+			p.parts = append(p.parts, patternPart{token: token})
+		case token != "" && strings.Trim(token, "#") == "":
+			counters++
+			p.parts = append(p.parts, patternPart{token: "#", width: len(token)})
+		default:
+			return nil, fmt.Errorf("el patrón %q usa el token desconocido {%s}", raw, token)
+		}
+		rest = rest[open+end+1:]
+	}
+	if counters != 1 {
+		return nil, fmt.Errorf("el patrón %q debe tener un único contador {#...}", raw)
+	}
+	return p, nil
+}
+
+// String returns the pattern, with the appended counter when it was a prefix.
+func (p *NumberPattern) String() string {
+	return p.raw
+}
+
+// Key is the pattern rendered for now and sku with the counter left out. Each key has its
+// own counter, so a pattern with {YYYY}{MM} restarts every month and one with {SKU} counts
+// per article.
+func (p *NumberPattern) Key(now time.Time, sku string) string {
+	return p.render(now, sku, func(int) string { return seriesCounterMarker })
+}
+
+// Format renders the number n of the series for now and sku.
+func (p *NumberPattern) Format(now time.Time, sku string, n int64) string {
+	return p.render(now, sku, func(width int) string { return fmt.Sprintf("%0*d", width, n) })
+}
+
+func (p *NumberPattern) render(now time.Time, sku string, counter func(width int) string) string {
+	var b strings.Builder
+	for _, part := range p.parts {
+		switch part.token {
+		case "":
+			b.WriteString(part.literal)
+		case "YYYY":
+			b.WriteString(fmt.Sprintf("%04d", now.Year()))
+		case "YY":
+			b.WriteString(fmt.Sprintf("%02d", now.Year()%100))
+		case "MM":
+			b.WriteString(fmt.Sprintf("%02d", int(now.Month())))
+		case "DD":
+			b.WriteString(fmt.Sprintf("%02d", now.Day()))
+		case "SKU":
+			b.WriteString(sku)
+		case "#":
+			b.WriteString(counter(part.width))
+		}
+	}
+	return b.String()
+}
+
+// ResolveNumberPattern picks the series that applies: the article's own series, else the
+// tenant pattern, else fallback.
+func ResolveNumberPattern(articleSeries, tenantPattern *string, fallback string) string {
+	for _, s := range []*string{articleSeries, tenantPattern} {
+		if s != nil && strings.TrimSpace(*s) != "" {
+			return strings.TrimSpace(*s)
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumberPattern_Format(t *testing.T) {
+	now := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	p, err := ParseNumberPattern("LOT-{YYYY}{MM}-{####}")
+	require.NoError(t, err)
+	assert.Equal(t, "LOT-202603-0001", p.Format(now, "SKU-1", 1))
+	assert.Equal(t, "LOT-202603-12345", p.Format(now, "SKU-1", 12345))
+	assert.Equal(t, "LOT-202603-{#}", p.Key(now, "SKU-1"))
+
+	p, err = ParseNumberPattern("{SKU}/{YY}{DD}-{##}")
+	require.NoError(t, err)
+	assert.Equal(t, "ABC/2607-03", p.Format(now, "ABC", 3))
+	assert.Equal(t, "ABC/2607-{#}", p.Key(now, "ABC"))
+}
+
+func TestParseNumberPattern_PrefixGetsCounter(t *testing.T) {
+	p, err := ParseNumberPattern("  ACME-  ")
+	require.NoError(t, err)
+	assert.Equal(t, "ACME-"+PrefixCounterToken, p.String())
+	assert.Equal(t, "ACME-000042", p.Format(time.Now(), "X", 42))
+}
+
+func TestParseNumberPattern_Invalid(t *testing.T) {
+	for _, pattern := range []string{
+		"",
+		"LOT-{YYYY}",       // no counter
+		"LOT-{###}-{###}",  // two counters
+		"LOT-{WEEK}-{###}", // unknown token
+		"LOT-{YYYY-{###}",  // unclosed
+		"LOT-YYYY}-{###}",  // unopened
+		"LOT-{}-{###}",     // empty token
+	} {
+		_, err := ParseNumberPattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestResolveNumberPattern(t *testing.T) {
+	article := "ART-"
+	tenant := "T-{####}"
+	blank := "  "
+	assert.Equal(t, "ART-", ResolveNumberPattern(&article, &tenant, "F-{#}"))
+	assert.Equal(t, "T-{####}", ResolveNumberPattern(&blank, &tenant, "F-{#}"))
+	assert.Equal(t, "F-{#}", ResolveNumberPattern(nil, nil, "F-{#}"))
+}
@@ -7,3 +7,7 @@ func IntToFloat64(i int) float64 {
 func IntToPtr(i int) *int {
 	return &i
 }
+
+func Float64Ptr(f float64) *float64 {
+	return &f
+}
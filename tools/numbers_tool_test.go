@@ -22,3 +22,12 @@ func TestIntToPtr(t *testing.T) {
 	require.NotNil(t, zero)
 	assert.Equal(t, 0, *zero)
 }
+
+func TestFloat64Ptr(t *testing.T) {
+	ptr := Float64Ptr(2.5)
+	require.NotNil(t, ptr)
+	assert.Equal(t, 2.5, *ptr)
+
+	a, b := Float64Ptr(1), Float64Ptr(1)
+	assert.NotSame(t, a, b, "each call returns its own pointer")
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"gorm.io/gorm"
+)
+
+// GetLocationPickPaths loads the pick path of the given location codes for a tenant, keyed by
+// code. Codes that are not found are simply absent from the map.
+func GetLocationPickPaths(tx *gorm.DB, tenantID string, codes []string) (map[string]responses.LocationPickPath, error) {
+	out := make(map[string]responses.LocationPickPath, len(codes))
+	if len(codes) == 0 {
+		return out, nil
+	}
+	var rows []responses.LocationPickPath
+	if err := tx.Table("locations").
+		Select("location_code, zone, aisle, rack, level, pick_sequence").
+		Where("tenant_id = ? AND location_code IN ?", tenantID, codes).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.LocationCode] = row
+	}
+	return out, nil
+}
+
+// OrderPickPath returns the distinct codes in walk order:
+//  1. locations with an explicit pick_sequence, ascending;
+//  2. locations laid out by zone/aisle/rack/level — zones and aisles in natural order, racks
+//     ascending in one aisle and descending in the next (serpentine), then level;
+//  3. everything else (no layout, or unknown location), by code.
+//
+// Ties always break on the location code so the result is deterministic.
+func OrderPickPath(codes []string, paths map[string]responses.LocationPickPath) []string {
+	seen := make(map[string]bool, len(codes))
+	var sequenced, laidOut, rest []string
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		p, ok := paths[code]
+		switch {
+		case ok && p.PickSequence != nil:
+			sequenced = append(sequenced, code)
+		case ok && (p.Zone != nil || p.Aisle != nil || p.Rack != nil || p.Level != nil):
+			laidOut = append(laidOut, code)
+		default:
+			rest = append(rest, code)
+		}
+	}
+
+	sort.SliceStable(sequenced, func(i, j int) bool {
+		a, b := *paths[sequenced[i]].PickSequence, *paths[sequenced[j]].PickSequence
+		if a != b {
+			return a < b
+		}
+		return NaturalLess(sequenced[i], sequenced[j])
+	})
+
+	// Serpentine: number the distinct (zone, aisle) pairs in walk order; odd ones walk back.
+	aisleKey := func(p responses.LocationPickPath) [2]*string { return [2]*string{p.Zone, p.Aisle} }
+	var aisles [][2]*string
+	aisleSeen := map[string]bool{}
+	for _, code := range laidOut {
+		k := aisleKey(paths[code])
+		id := derefOr(k[0]) + "\x00" + derefOr(k[1])
+		if !aisleSeen[id] {
+			aisleSeen[id] = true
+			aisles = append(aisles, k)
+		}
+	}
+	sort.SliceStable(aisles, func(i, j int) bool {
+		if c := compareOptional(aisles[i][0], aisles[j][0]); c != 0 {
+			return c < 0
+		}
+		return compareOptional(aisles[i][1], aisles[j][1]) < 0
+	})
+	reverse := make(map[string]bool, len(aisles))
+	for i, k := range aisles {
+		reverse[derefOr(k[0])+"\x00"+derefOr(k[1])] = k[1] != nil && i%2 == 1
+	}
+
+	sort.SliceStable(laidOut, func(i, j int) bool {
+		a, b := paths[laidOut[i]], paths[laidOut[j]]
+		if c := compareOptional(a.Zone, b.Zone); c != 0 {
+			return c < 0
+		}
+		if c := compareOptional(a.Aisle, b.Aisle); c != 0 {
+			return c < 0
+		}
+		if c := compareOptional(a.Rack, b.Rack); c != 0 {
+			// Missing racks stay at the end of the aisle in both directions.
+			if reverse[derefOr(a.Zone)+"\x00"+derefOr(a.Aisle)] && a.Rack != nil && b.Rack != nil {
+				return c > 0
+			}
+			return c < 0
+		}
+		if c := compareOptional(a.Level, b.Level); c != 0 {
+			return c < 0
+		}
+		return NaturalLess(laidOut[i], laidOut[j])
+	})
+
+	sort.SliceStable(rest, func(i, j int) bool { return NaturalLess(rest[i], rest[j]) })
+
+	out := make([]string, 0, len(sequenced)+len(laidOut)+len(rest))
+	out = append(out, sequenced...)
+	out = append(out, laidOut...)
+	return append(out, rest...)
+}
+
+// NaturalLess compares strings with embedded numbers by value ("A2" < "A10"), case-insensitive.
+func NaturalLess(a, b string) bool {
+	if c := naturalCompare(a, b); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+// compareOptional orders values naturally with nil last.
+func compareOptional(a, b *string) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return naturalCompare(*a, *b)
+}
+
+func derefOr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// naturalCompare splits both strings into digit / non-digit runs and compares run by run.
+func naturalCompare(a, b string) int {
+	ra, rb := naturalRuns(strings.ToLower(a)), naturalRuns(strings.ToLower(b))
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		x, y := ra[i], rb[i]
+		nx, errX := strconv.ParseUint(x, 10, 64)
+		ny, errY := strconv.ParseUint(y, 10, 64)
+		if errX == nil && errY == nil {
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(ra) < len(rb):
+		return -1
+	case len(ra) > len(rb):
+		return 1
+	}
+	return 0
+}
+
+func naturalRuns(s string) []string {
+	var runs []string
+	start, prevDigit := 0, false
+	for i, r := range s {
+		digit := unicode.IsDigit(r)
+		if i > start && digit != prevDigit {
+			runs = append(runs, s[start:i])
+			start = i
+		}
+		prevDigit = digit
+	}
+	if start < len(s) {
+		runs = append(runs, s[start:])
+	}
+	return runs
+}
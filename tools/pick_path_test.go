@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"github.com/stretchr/testify/assert"
+)
+
+func slot(code, zone, aisle, rack, level string) responses.LocationPickPath {
+	p := responses.LocationPickPath{LocationCode: code}
+	if zone != "" {
+		p.Zone = StrPtr(zone)
+	}
+	if aisle != "" {
+		p.Aisle = StrPtr(aisle)
+	}
+	if rack != "" {
+		p.Rack = StrPtr(rack)
+	}
+	if level != "" {
+		p.Level = StrPtr(level)
+	}
+	return p
+}
+
+func pathMap(ps ...responses.LocationPickPath) map[string]responses.LocationPickPath {
+	m := make(map[string]responses.LocationPickPath, len(ps))
+	for _, p := range ps {
+		m[p.LocationCode] = p
+	}
+	return m
+}
+
+func TestNaturalLess(t *testing.T) {
+	assert.True(t, NaturalLess("A2", "A10"))
+	assert.False(t, NaturalLess("A10", "A2"))
+	assert.True(t, NaturalLess("a1", "B1"), "case-insensitive")
+	assert.True(t, NaturalLess("R-2-1", "R-10-1"))
+	assert.False(t, NaturalLess("X", "X"))
+}
+
+func TestOrderPickPath_Serpentine(t *testing.T) {
+	paths := pathMap(
+		slot("A-1-1", "Z1", "1", "1", "1"),
+		slot("A-1-3", "Z1", "1", "3", "1"),
+		slot("A-2-1", "Z1", "2", "1", "1"),
+		slot("A-2-3", "Z1", "2", "3", "1"),
+		slot("A-10-1", "Z1", "10", "1", "1"),
+		slot("A-10-2", "Z1", "10", "2", "1"),
+	)
+	got := OrderPickPath([]string{"A-10-2", "A-2-1", "A-1-3", "A-10-1", "A-2-3", "A-1-1"}, paths)
+	// Aisle 1 up, aisle 2 back down, aisle 10 up again (natural order, not "10" < "2").
+	assert.Equal(t, []string{"A-1-1", "A-1-3", "A-2-3", "A-2-1", "A-10-1", "A-10-2"}, got)
+}
+
+func TestOrderPickPath_LevelsWithinRack(t *testing.T) {
+	paths := pathMap(
+		slot("R1-L3", "Z1", "1", "1", "3"),
+		slot("R1-L1", "Z1", "1", "1", "1"),
+		slot("R2-L1", "Z1", "1", "2", "1"),
+	)
+	got := OrderPickPath([]string{"R2-L1", "R1-L3", "R1-L1"}, paths)
+	assert.Equal(t, []string{"R1-L1", "R1-L3", "R2-L1"}, got)
+}
+
+func TestOrderPickPath_SequenceFirstThenLayoutThenRest(t *testing.T) {
+	seqB := slot("SEQ-B", "Z9", "9", "9", "9")
+	seqB.PickSequence = IntToPtr(20)
+	seqA := responses.LocationPickPath{LocationCode: "SEQ-A", PickSequence: IntToPtr(5)}
+	paths := pathMap(
+		seqA,
+		seqB,
+		slot("ZB-1", "B", "1", "", ""),
+		slot("ZA-1", "A", "1", "", ""),
+		slot("NOZONE", "", "1", "", ""),
+		responses.LocationPickPath{LocationCode: "BARE"},
+	)
+	got := OrderPickPath([]string{"UNKNOWN", "BARE", "NOZONE", "ZB-1", "SEQ-B", "ZA-1", "SEQ-A", "ZA-1"}, paths)
+	assert.Equal(t, []string{"SEQ-A", "SEQ-B", "ZA-1", "ZB-1", "NOZONE", "BARE", "UNKNOWN"}, got)
+}
+
+func TestOrderPickPath_Empty(t *testing.T) {
+	assert.Empty(t, OrderPickPath(nil, nil))
+}
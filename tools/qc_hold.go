@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// LotHeldSQL is the condition, over a lots row aliased lotAlias, of a lot whose stock is on QC
+// hold: in quarantine, rejected or recalled, or not released yet when the lot's tenant requires
+// lot release (stock_settings.require_lot_release, pharma tenants). Never NULL, so it can be
+// negated.
+func LotHeldSQL(lotAlias string) string {
+	return fmt.Sprintf(`(COALESCE(%[1]s.status, 'pending') IN ('quarantine', 'rejected', 'recalled')
+		OR (COALESCE(%[1]s.status, 'pending') <> 'released'
+			AND EXISTS (SELECT 1 FROM stock_settings qc_ss
+			             WHERE qc_ss.tenant_id = %[1]s.tenant_id AND qc_ss.require_lot_release)))`, lotAlias)
+}
+
+// HeldLotQtySQL is the quantity of an inventory row aliased invAlias that sits in lots on QC hold.
+func HeldLotQtySQL(invAlias string) string {
+	return fmt.Sprintf(`COALESCE((SELECT SUM(qc_il.quantity)
+		  FROM inventory_lots qc_il
+		  JOIN lots qc_l ON qc_l.id = qc_il.lot_id
+		 WHERE qc_il.inventory_id = %s.id AND %s), 0)`, invAlias, LotHeldSQL("qc_l"))
+}
+
+// HeldInLotQtySQL is the quantity of the lot aliased lotAlias, in the inventory row aliased
+// invAlias, blocked by open inventory holds that name the lot (e.g. quarantined customer returns).
+func HeldInLotQtySQL(invAlias, lotAlias string) string {
+	return fmt.Sprintf(`COALESCE((SELECT SUM(qc_h.qty)
+		  FROM qc_holds qc_h
+		 WHERE qc_h.inventory_id = %s.id AND qc_h.lot_id = %s.id
+		   AND qc_h.scope = 'inventory' AND qc_h.status = 'quarantine'), 0)`, invAlias, lotAlias)
+}
+
+// AvailableQtySQL is the quantity of an inventory row aliased invAlias that can be reserved,
+// picked or moved: on hand minus reserved, minus quantity holds (held_qty) and held lots.
+func AvailableQtySQL(invAlias string) string {
+	return fmt.Sprintf("(%[1]s.quantity - %[1]s.reserved_qty - %[1]s.held_qty - %[2]s)", invAlias, HeldLotQtySQL(invAlias))
+}
+
+// HeldLots returns, among the given lot numbers of sku, those on QC hold for the tenant.
+func HeldLots(tx *gorm.DB, tenantID, sku string, lotNumbers []string) ([]string, error) {
+	if len(lotNumbers) == 0 {
+		return nil, nil
+	}
+	var held []string
+	if err := tx.Raw(`
+		SELECT l.lot_number
+		  FROM lots l
+		 WHERE l.tenant_id = ? AND l.sku = ? AND l.lot_number IN ?
+		   AND `+LotHeldSQL("l")+`
+		 ORDER BY l.lot_number
+	`, tenantID, sku, lotNumbers).Scan(&held).Error; err != nil {
+		return nil, fmt.Errorf("check held lots of %s: %w", sku, err)
+	}
+	return held, nil
+}
@@ -88,6 +88,11 @@ func ResponseConflict(c *gin.Context, transactionType, message, endpointCode str
 	writeResponse(c, http.StatusConflict, transactionType, message, endpointCode, nil, false, "", false)
 }
 
+// 409 Conflict with a structured detail in data (e.g. allowance exceeded)
+func ResponseConflictWithData(c *gin.Context, transactionType, message, endpointCode string, data interface{}) {
+	writeResponse(c, http.StatusConflict, transactionType, message, endpointCode, data, false, "", false)
+}
+
 // 500 Internal Server Error (unexpected server error)
 func ResponseInternal(c *gin.Context, transactionType, message, endpointCode string) {
 	writeResponse(c, http.StatusInternalServerError, transactionType, message, endpointCode, nil, false, "", false)
@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// S3DocumentStorage — S3-compatible object storage (AWS S3, MinIO, R2, ...)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// s3ChecksumMeta is the user metadata header holding the object's SHA-256.
+const s3ChecksumMeta = "X-Amz-Meta-Sha256"
+
+// S3DocumentStorage stores objects in a bucket through the S3 REST API with path-style URLs
+// ({Endpoint}/{Bucket}/{key}) and Signature Version 4, which MinIO and AWS both accept.
+type S3DocumentStorage struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3DocumentStorage constructs an S3 storage. endpoint is the service base URL, e.g.
+// "https://s3.us-east-1.amazonaws.com" or "http://minio:9000"; region defaults to us-east-1.
+func NewS3DocumentStorage(endpoint, region, bucket, accessKey, secretKey string) *S3DocumentStorage {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3DocumentStorage{
+		Endpoint:  strings.TrimRight(endpoint, "/"),
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}
+}
+
+func (s *S3DocumentStorage) objectURL(key string) string {
+	return s.Endpoint + "/" + s3EscapePath(s.Bucket+"/"+key)
+}
+
+// Put uploads the object with its SHA-256 as user metadata. x-amz-content-sha256 makes S3
+// reject the upload when the body does not match.
+func (s *S3DocumentStorage) Put(ctx context.Context, key string, data []byte, contentType string) (StoredDocument, error) {
+	if err := validateDocumentKey(key); err != nil {
+		return StoredDocument{}, err
+	}
+	checksum := DocumentChecksum(data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return StoredDocument{}, fmt.Errorf("s3: build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set(s3ChecksumMeta, checksum)
+	resp, err := s.do(req, checksum)
+	if err != nil {
+		return StoredDocument{}, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return StoredDocument{}, fmt.Errorf("s3: put %s: status %d", key, resp.StatusCode)
+	}
+	return StoredDocument{Key: key, Size: int64(len(data)), SHA256: checksum, ContentType: contentType}, nil
+}
+
+// Get downloads the object and verifies it against the SHA-256 stored in its metadata.
+func (s *S3DocumentStorage) Get(ctx context.Context, key string) ([]byte, StoredDocument, error) {
+	if err := validateDocumentKey(key); err != nil {
+		return nil, StoredDocument{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, StoredDocument{}, fmt.Errorf("s3: build request: %w", err)
+	}
+	resp, err := s.do(req, DocumentChecksum(nil))
+	if err != nil {
+		return nil, StoredDocument{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		io.Copy(io.Discard, resp.Body)
+		return nil, StoredDocument{}, ErrDocumentNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, StoredDocument{}, fmt.Errorf("s3: get %s: status %d", key, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, StoredDocument{}, fmt.Errorf("s3: read %s: %w", key, err)
+	}
+	checksum := DocumentChecksum(data)
+	if stored := resp.Header.Get(s3ChecksumMeta); stored != "" && stored != checksum {
+		return nil, StoredDocument{}, ErrDocumentChecksum
+	}
+	return data, StoredDocument{
+		Key:         key,
+		Size:        int64(len(data)),
+		SHA256:      checksum,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// Delete removes the object; S3 answers 204 whether or not it existed.
+func (s *S3DocumentStorage) Delete(ctx context.Context, key string) error {
+	if err := validateDocumentKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("s3: build request: %w", err)
+	}
+	resp, err := s.do(req, DocumentChecksum(nil))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("s3: delete %s: status %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *S3DocumentStorage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signS3RequestV4(req, payloadHash, s.AccessKey, s.SecretKey, s.Region, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: http: %w", err)
+	}
+	return resp, nil
+}
+
+// signS3RequestV4 adds the x-amz-date, x-amz-content-sha256 and Authorization headers of AWS
+// Signature Version 4. The host and every header already set on req are signed.
+func signS3RequestV4(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3CanonicalQuery sorts and URI-encodes query parameters as SigV4 requires.
+func s3CanonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3EscapePath URI-encodes an object path, keeping the "/" separators.
+func s3EscapePath(p string) string {
+	return s3Escape(p, false)
+}
+
+// s3Escape percent-encodes every byte except the SigV4 unreserved characters (and "/" unless
+// encodeSlash is set).
+func s3Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/eflowcr/eSTOCK_backend/models/responses"
+	"gorm.io/gorm"
+)
+
+// uomEpsilon absorbs float noise from NUMERIC(18,6) factors when checking whole quantities.
+const uomEpsilon = 1e-6
+
+// UoMConversion is an edge of the presentation conversion graph: 1 From = Factor To.
+type UoMConversion struct {
+	From   string
+	To     string
+	Factor float64
+}
+
+// UoMFactor returns how many units of to one unit of from holds. Conversions are walked in
+// both directions (a reverse edge is 1/Factor) over as many hops as needed, shortest path
+// first. ok is false when the two presentations are not connected.
+func UoMFactor(conversions []UoMConversion, from, to string) (factor float64, ok bool) {
+	if from == to {
+		return 1, true
+	}
+	type edge struct {
+		to     string
+		factor float64
+	}
+	graph := make(map[string][]edge, len(conversions)*2)
+	for _, c := range conversions {
+		if c.Factor <= 0 || c.From == c.To {
+			continue
+		}
+		graph[c.From] = append(graph[c.From], edge{c.To, c.Factor})
+		graph[c.To] = append(graph[c.To], edge{c.From, 1 / c.Factor})
+	}
+
+	reached := map[string]float64{from: 1}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, e := range graph[node] {
+			if _, seen := reached[e.to]; seen {
+				continue
+			}
+			reached[e.to] = reached[node] * e.factor
+			if e.to == to {
+				return reached[e.to], true
+			}
+			queue = append(queue, e.to)
+		}
+	}
+	return 0, false
+}
+
+// UoMWholeQty returns qty as an int when it is a whole number (within float noise).
+func UoMWholeQty(qty float64) (int, bool) {
+	rounded := math.Round(qty)
+	if math.Abs(qty-rounded) > uomEpsilon {
+		return 0, false
+	}
+	return int(rounded), true
+}
+
+// UoMConverter converts document quantities entered in a presentation (UNIDAD, CAJA, PALLET…)
+// into the base presentation of the article, which is what inventory and every quantity column
+// are kept in. The conversion graph and the article presentations are loaded lazily and cached,
+// so one converter serves all the lines of a document.
+type UoMConverter struct {
+	db       *gorm.DB
+	tenantID string
+
+	loaded bool
+	types  map[string]string // lower-cased id, code or name → presentation type id
+	edges  []UoMConversion   // between presentation type ids
+	bases  map[string]string // sku → article presentation
+}
+
+func NewUoMConverter(db *gorm.DB, tenantID string) *UoMConverter {
+	return &UoMConverter{db: db, tenantID: tenantID, bases: make(map[string]string)}
+}
+
+// Factor returns the base units of sku held by one unit of presentation. An empty presentation,
+// or the article's own presentation, is factor 1. A presentation that is unknown or has no
+// conversion path to the base presentation is a 400.
+func (c *UoMConverter) Factor(sku string, presentation *string) (float64, *responses.InternalResponse) {
+	if presentation == nil || strings.TrimSpace(*presentation) == "" {
+		return 1, nil
+	}
+	entered := strings.TrimSpace(*presentation)
+
+	base, ok := c.bases[sku]
+	if !ok {
+		var presentations []string
+		if err := c.db.Table("articles").Where("tenant_id = ? AND sku = ?", c.tenantID, sku).
+			Limit(1).Pluck("presentation", &presentations).Error; err != nil {
+			return 0, &responses.InternalResponse{Error: err, Message: "Error al obtener la presentación del artículo"}
+		}
+		if len(presentations) == 0 {
+			return 0, &responses.InternalResponse{
+				Message:    fmt.Sprintf("El artículo %s no existe", sku),
+				Handled:    true,
+				StatusCode: responses.StatusBadRequest,
+			}
+		}
+		base = strings.TrimSpace(presentations[0])
+		c.bases[sku] = base
+	}
+	if strings.EqualFold(entered, base) {
+		return 1, nil
+	}
+
+	if err := c.load(); err != nil {
+		return 0, &responses.InternalResponse{Error: err, Message: "Error al obtener las conversiones de presentación"}
+	}
+	fromID, ok := c.types[strings.ToLower(entered)]
+	if !ok {
+		return 0, &responses.InternalResponse{
+			Message:    fmt.Sprintf("La presentación %s no existe", entered),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	toID, ok := c.types[strings.ToLower(base)]
+	if ok && fromID == toID {
+		return 1, nil
+	}
+	factor, found := 0.0, false
+	if ok {
+		factor, found = UoMFactor(c.edges, fromID, toID)
+	}
+	if !found {
+		return 0, &responses.InternalResponse{
+			Message:    fmt.Sprintf("No hay conversión de %s a %s (presentación base de %s)", entered, base, sku),
+			Handled:    true,
+			StatusCode: responses.StatusBadRequest,
+		}
+	}
+	return factor, nil
+}
+
+// ToBase converts qty entered in presentation into base units of sku and returns the factor used.
+func (c *UoMConverter) ToBase(sku string, presentation *string, qty float64) (float64, float64, *responses.InternalResponse) {
+	factor, resp := c.Factor(sku, presentation)
+	if resp != nil {
+		return 0, 0, resp
+	}
+	return qty * factor, factor, nil
+}
+
+// load reads the presentation types and active conversions once.
+func (c *UoMConverter) load() error {
+	if c.loaded {
+		return nil
+	}
+	var types []struct {
+		ID   string
+		Code string
+		Name string
+	}
+	if err := c.db.Table("presentation_types").Select("id, code, name").Scan(&types).Error; err != nil {
+		return fmt.Errorf("load presentation types: %w", err)
+	}
+	c.types = make(map[string]string, len(types)*3)
+	// Codes win over names when a name happens to equal another type's code.
+	for _, t := range types {
+		c.types[strings.ToLower(t.Name)] = t.ID
+	}
+	for _, t := range types {
+		c.types[strings.ToLower(t.Code)] = t.ID
+		c.types[strings.ToLower(t.ID)] = t.ID
+	}
+
+	var conversions []struct {
+		FromPresentationTypeID string
+		ToPresentationTypeID   string
+		ConversionFactor       float64
+	}
+	if err := c.db.Table("presentation_conversions").
+		Select("from_presentation_type_id, to_presentation_type_id, conversion_factor").
+		Where("is_active = ?", true).Scan(&conversions).Error; err != nil {
+		return fmt.Errorf("load presentation conversions: %w", err)
+	}
+	c.edges = make([]UoMConversion, 0, len(conversions))
+	for _, cv := range conversions {
+		c.edges = append(c.edges, UoMConversion{From: cv.FromPresentationTypeID, To: cv.ToPresentationTypeID, Factor: cv.ConversionFactor})
+	}
+	c.loaded = true
+	return nil
+}
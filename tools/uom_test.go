@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 1 PALLET = 20 CAJA, 1 CAJA = 12 UNIDAD, 1 PAQUETE = 6 UNIDAD.
+var testConversions = []UoMConversion{
+	{From: "PALLET", To: "CAJA", Factor: 20},
+	{From: "CAJA", To: "UNIDAD", Factor: 12},
+	{From: "PAQUETE", To: "UNIDAD", Factor: 6},
+}
+
+func TestUoMFactor_DirectAndReverse(t *testing.T) {
+	f, ok := UoMFactor(testConversions, "CAJA", "UNIDAD")
+	assert.True(t, ok)
+	assert.InDelta(t, 12, f, 1e-9)
+
+	f, ok = UoMFactor(testConversions, "UNIDAD", "CAJA")
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0/12, f, 1e-9)
+}
+
+func TestUoMFactor_MultiHop(t *testing.T) {
+	f, ok := UoMFactor(testConversions, "PALLET", "UNIDAD")
+	assert.True(t, ok)
+	assert.InDelta(t, 240, f, 1e-9)
+
+	// CAJA → UNIDAD → PAQUETE mixes a forward and a reverse edge.
+	f, ok = UoMFactor(testConversions, "CAJA", "PAQUETE")
+	assert.True(t, ok)
+	assert.InDelta(t, 2, f, 1e-9)
+
+	f, ok = UoMFactor(testConversions, "PALLET", "PAQUETE")
+	assert.True(t, ok)
+	assert.InDelta(t, 40, f, 1e-9)
+}
+
+func TestUoMFactor_SameAndUnconnected(t *testing.T) {
+	f, ok := UoMFactor(nil, "CAJA", "CAJA")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, f)
+
+	_, ok = UoMFactor(testConversions, "CAJA", "LITRO")
+	assert.False(t, ok)
+
+	_, ok = UoMFactor(nil, "CAJA", "UNIDAD")
+	assert.False(t, ok)
+}
+
+func TestUoMFactor_IgnoresInvalidEdges(t *testing.T) {
+	_, ok := UoMFactor([]UoMConversion{{From: "CAJA", To: "UNIDAD", Factor: 0}}, "CAJA", "UNIDAD")
+	assert.False(t, ok)
+}
+
+func TestUoMWholeQty(t *testing.T) {
+	n, ok := UoMWholeQty(24)
+	assert.True(t, ok)
+	assert.Equal(t, 24, n)
+
+	n, ok = UoMWholeQty(2.9999999999)
+	assert.True(t, ok, "float noise from 1/factor")
+	assert.Equal(t, 3, n)
+
+	_, ok = UoMWholeQty(0.5)
+	assert.False(t, ok)
+}
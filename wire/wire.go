@@ -171,23 +171,16 @@ func NewGamification(db *gorm.DB) (ports.GamificationRepository, *services.Gamif
 
 // NewInventory builds InventoryRepository and InventoryService. When pool is non-nil, injects
 // ArticlesRepository so GetPickSuggestionsBySKU sorts by rotation (FIFO/FEFO) then quantity.
-//
-// S3.5 W2-A: tenantID is left empty here to preserve the legacy signature for
-// internal callers (sales orders, backorders) that compose their own inventory
-// repo. Routes that handle live HTTP traffic must use NewInventoryWithConfig.
+// The tenant is passed per call, so one repository serves every tenant.
 func NewInventory(db *gorm.DB, pool *pgxpool.Pool) (ports.InventoryRepository, *services.InventoryService) {
-	return NewInventoryWithConfig(db, pool, configuration.Config{})
-}
-
-// NewInventoryWithConfig is identical to NewInventory but stamps the configured
-// tenant_id on every inventory_lots row created via this repository.
-func NewInventoryWithConfig(db *gorm.DB, pool *pgxpool.Pool, config configuration.Config) (ports.InventoryRepository, *services.InventoryService) {
-	r := &repositories.InventoryRepository{DB: db, TenantID: config.TenantID}
+	r := &repositories.InventoryRepository{DB: db}
 	var articlesRepo ports.ArticlesRepository
 	if pool != nil {
 		articlesRepo, _ = NewArticles(db, pool)
 	}
-	return r, services.NewInventoryService(r, articlesRepo)
+	svc := services.NewInventoryService(r, articlesRepo)
+	svc.ExchangeRates, _ = NewExchangeRates(db)
+	return r, svc
 }
 
 func NewInventoryMovements(db *gorm.DB) (ports.InventoryMovementsRepository, *services.InventoryMovementsService) {
@@ -250,21 +243,24 @@ func NewPresentations(db *gorm.DB, pool *pgxpool.Pool) (ports.PresentationsRepos
 	return r, services.NewPresentationsService(r)
 }
 
-func NewReceivingTasks(db *gorm.DB, notifSvc *services.NotificationsService) (ports.ReceivingTasksRepository, *services.ReceivingTasksService) {
-	r := &repositories.ReceivingTasksRepository{DB: db, NotificationsSvc: notifSvc}
+func NewReceivingTasks(db *gorm.DB, auditSvc *services.AuditService, notifSvc *services.NotificationsService) (ports.ReceivingTasksRepository, *services.ReceivingTasksService) {
+	r := &repositories.ReceivingTasksRepository{DB: db, AuditService: auditSvc, NotificationsSvc: notifSvc}
 	return r, services.NewReceivingTasksService(r)
 }
 
 // NewSerials builds SerialsRepository and SerialsService. When pool is non-nil, uses SerialsRepositorySQLC.
+// The lifecycle (transitions, chain of custody) always runs on GORM, like the documents that move serials.
 func NewSerials(db *gorm.DB, pool *pgxpool.Pool) (ports.SerialsRepository, *services.SerialsService) {
 	var r ports.SerialsRepository
 	if pool != nil {
 		queries := sqlc.New(pool)
-		r = repositories.NewSerialsRepositorySQLC(queries)
+		r = repositories.NewSerialsRepositorySQLCWithGORM(queries, db)
 	} else {
 		r = &repositories.SerialsRepository{DB: db}
 	}
-	return r, services.NewSerialsService(r)
+	svc := services.NewSerialsService(r)
+	svc.Lifecycle = &repositories.SerialLifecycleRepository{DB: db}
+	return r, svc
 }
 
 func NewStockAlerts(db *gorm.DB, redisClient *redis.Client) (ports.StockAlertsRepository, *services.StockAlertsService) {
@@ -376,7 +372,9 @@ func NewStockSettings(pool *pgxpool.Pool) (ports.StockSettingsRepository, *servi
 // Uses GORM (consistent with ReceivingTasksRepository and PickingTaskRepository).
 func NewPurchaseOrders(db *gorm.DB) (ports.PurchaseOrdersRepository, *services.PurchaseOrdersService) {
 	r := &repositories.PurchaseOrdersRepository{DB: db}
-	return r, services.NewPurchaseOrdersService(r)
+	svc := services.NewPurchaseOrdersService(r)
+	svc.ExchangeRates, _ = NewExchangeRates(db)
+	return r, svc
 }
 
 // NewSalesOrders builds SalesOrdersRepository and SalesOrdersService (S3-W2-B).
@@ -389,13 +387,64 @@ func NewSalesOrders(db *gorm.DB, config configuration.Config) (ports.SalesOrders
 		DB:           db,
 		InventorySvc: invSvc,
 	}
-	return r, services.NewSalesOrdersService(r)
+	svc := services.NewSalesOrdersService(r)
+	svc.ExchangeRates, _ = NewExchangeRates(db)
+	return r, svc
+}
+
+// NewNumberSeries builds NumberSeriesRepository and NumberSeriesService (GORM).
+func NewNumberSeries(db *gorm.DB) (ports.NumberSeriesRepository, *services.NumberSeriesService) {
+	r := &repositories.NumberSeriesRepository{DB: db}
+	return r, services.NewNumberSeriesService(r)
+}
+
+// NewExchangeRates builds ExchangeRatesRepository and ExchangeRatesService (GORM).
+func NewExchangeRates(db *gorm.DB) (ports.ExchangeRatesRepository, *services.ExchangeRatesService) {
+	r := &repositories.ExchangeRatesRepository{DB: db}
+	return r, services.NewExchangeRatesService(r)
+}
+
+// NewLabels builds LabelsRepository and LabelsService (GORM).
+func NewLabels(db *gorm.DB) (ports.LabelsRepository, *services.LabelsService) {
+	r := &repositories.LabelsRepository{DB: db}
+	return r, services.NewLabelsService(r)
+}
+
+// NewArticleBarcodes builds ArticleBarcodesRepository and ArticleBarcodesService (GORM).
+func NewArticleBarcodes(db *gorm.DB) (ports.ArticleBarcodesRepository, *services.ArticleBarcodesService) {
+	r := &repositories.ArticleBarcodesRepository{DB: db}
+	return r, services.NewArticleBarcodesService(r)
+}
+
+// NewPutaway builds PutawayRepository and PutawayService (GORM).
+func NewPutaway(db *gorm.DB) (ports.PutawayRepository, *services.PutawayService) {
+	r := &repositories.PutawayRepository{DB: db}
+	return r, services.NewPutawayService(r)
+}
+
+// DocumentStorageForConfig returns the document storage selected by DOCUMENT_STORAGE:
+// an S3-compatible bucket for "s3", otherwise the local directory DOCUMENT_STORAGE_DIR.
+// LoadConfig has already validated the S3 settings.
+func DocumentStorageForConfig(config configuration.Config) tools.DocumentStorage {
+	if config.DocumentStorage == "s3" {
+		return tools.NewS3DocumentStorage(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKeyID, config.S3SecretAccessKey)
+	}
+	return tools.NewLocalDocumentStorage(config.DocumentStorageDir)
 }
 
 // NewDeliveryNotes builds DeliveryNotesRepository and DeliveryNotesService (S3-W3-A DN3).
-func NewDeliveryNotes(db *gorm.DB) (ports.DeliveryNotesRepository, *services.DeliveryNotesService) {
+// PDFs and POD images go to the configured document storage.
+func NewDeliveryNotes(db *gorm.DB, config configuration.Config) (ports.DeliveryNotesRepository, *services.DeliveryNotesService) {
 	r := &repositories.DeliveryNotesRepository{DB: db}
-	return r, services.NewDeliveryNotesService(r, db)
+	return r, services.NewDeliveryNotesService(r, db, DocumentStorageForConfig(config))
+}
+
+// NewDeliveryNotesWithNotifications builds the delivery notes service used by the API routes;
+// recording a proof of delivery notifies the sales order creator through notifSvc.
+func NewDeliveryNotesWithNotifications(db *gorm.DB, config configuration.Config, notifSvc *services.NotificationsService) (ports.DeliveryNotesRepository, *services.DeliveryNotesService) {
+	r, svc := NewDeliveryNotes(db, config)
+	svc.Notifications = notifSvc
+	return r, svc
 }
 
 // NewBackorders builds BackordersRepository and BackordersService (S3-W3-A BO1+BO2).
@@ -411,8 +460,8 @@ func NewBackorders(db *gorm.DB) (ports.BackordersRepository, *services.Backorder
 }
 
 // NewPickingTaskWithDN builds PickingTaskRepository with SO + DN PDF generator injected (S3-W3-A).
-func NewPickingTaskWithDN(db *gorm.DB, auditSvc *services.AuditService, notifSvc *services.NotificationsService, soRepo repositories.SOPickedQtyUpdater) (ports.PickingTaskRepository, *services.PickingTaskService) {
-	_, dnSvc := NewDeliveryNotes(db)
+func NewPickingTaskWithDN(db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, soRepo repositories.SOPickedQtyUpdater) (ports.PickingTaskRepository, *services.PickingTaskService) {
+	_, dnSvc := NewDeliveryNotes(db, config)
 	r := &repositories.PickingTaskRepository{
 		DB:               db,
 		AuditService:     auditSvc,
@@ -422,3 +471,78 @@ func NewPickingTaskWithDN(db *gorm.DB, auditSvc *services.AuditService, notifSvc
 	}
 	return r, services.NewPickingTaskService(r)
 }
+
+// NewPickingWaves builds PickingWavesRepository and PickingWavesService. Member tasks are
+// started and completed through a PickingTaskRepository wired like NewPickingTaskWithDN, so
+// wave completion updates sales orders and generates delivery notes.
+func NewPickingWaves(db *gorm.DB, config configuration.Config, auditSvc *services.AuditService, notifSvc *services.NotificationsService, soRepo repositories.SOPickedQtyUpdater) (ports.PickingWavesRepository, *services.PickingWavesService) {
+	r := &repositories.PickingWavesRepository{DB: db}
+	pickingRepo, _ := NewPickingTaskWithDN(db, config, auditSvc, notifSvc, soRepo)
+	return r, services.NewPickingWavesService(r, pickingRepo)
+}
+
+// NewCustomerReturns builds CustomerReturnsRepository and CustomerReturnsService (RMA).
+func NewCustomerReturns(db *gorm.DB) (ports.CustomerReturnsRepository, *services.CustomerReturnsService) {
+	r := &repositories.CustomerReturnsRepository{DB: db}
+	return r, services.NewCustomerReturnsService(r)
+}
+
+// NewVendorReturns builds VendorReturnsRepository and VendorReturnsService (return to vendor).
+func NewVendorReturns(db *gorm.DB) (ports.VendorReturnsRepository, *services.VendorReturnsService) {
+	r := &repositories.VendorReturnsRepository{DB: db}
+	return r, services.NewVendorReturnsService(r)
+}
+
+// NewQCHolds builds QCHoldsRepository and QCHoldsService (quarantine / QC holds). Attachments
+// go to the configured document storage.
+func NewQCHolds(db *gorm.DB, config configuration.Config) (ports.QCHoldsRepository, *services.QCHoldsService) {
+	r := &repositories.QCHoldsRepository{DB: db}
+	return r, services.NewQCHoldsService(r, DocumentStorageForConfig(config))
+}
+
+// NewLotRecalls builds LotRecallsRepository and LotRecallsService (lot recalls). Opening a
+// recall notifies the tenant admins through notifSvc.
+func NewLotRecalls(db *gorm.DB, notifSvc *services.NotificationsService) (ports.LotRecallsRepository, *services.LotRecallsService) {
+	r := &repositories.LotRecallsRepository{DB: db}
+	svc := services.NewLotRecallsService(r)
+	svc.Notifications = notifSvc
+	return r, svc
+}
+
+// NewKits builds KitsRepository and KitsService (kit BOMs and assembly orders).
+func NewKits(db *gorm.DB) (ports.KitsRepository, *services.KitsService) {
+	r := &repositories.KitsRepository{DB: db}
+	return r, services.NewKitsService(r)
+}
+
+// NewShipments builds ShipmentsRepository and ShipmentsService (packing stage). Package SSCCs
+// use the configured GS1 company prefix; closing a shipment generates the delivery note PDF.
+func NewShipments(db *gorm.DB, config configuration.Config) (ports.ShipmentsRepository, *services.ShipmentsService) {
+	r := &repositories.ShipmentsRepository{DB: db, GS1CompanyPrefix: config.GS1CompanyPrefix}
+	_, dnSvc := NewDeliveryNotes(db, config)
+	return r, services.NewShipmentsService(r, dnSvc)
+}
+
+// NewCycleCounts builds CycleCountsRepository and CycleCountsService.
+// The reason code repository (pool-backed, optional) validates plan reason codes.
+func NewCycleCounts(db *gorm.DB, pool *pgxpool.Pool) (ports.CycleCountsRepository, *services.CycleCountsService) {
+	r := &repositories.CycleCountsRepository{DB: db}
+	var reasonRepo ports.AdjustmentReasonCodesRepository
+	if pool != nil {
+		reasonRepo, _ = NewAdjustmentReasonCodes(pool)
+	}
+	return r, services.NewCycleCountsService(r, reasonRepo)
+}
+
+// NewReplenishment builds ReplenishmentRepository and ReplenishmentService.
+// Draft POs go through the GORM PurchaseOrdersRepository; stock settings (pool-backed, optional)
+// gate automatic creation — without a pool the cron never auto-creates.
+func NewReplenishment(db *gorm.DB, pool *pgxpool.Pool) (ports.ReplenishmentRepository, *services.ReplenishmentService) {
+	r := &repositories.ReplenishmentRepository{DB: db}
+	poRepo, _ := NewPurchaseOrders(db)
+	var settingsRepo ports.StockSettingsRepository
+	if pool != nil {
+		settingsRepo, _ = NewStockSettings(pool)
+	}
+	return r, services.NewReplenishmentService(r, poRepo, settingsRepo)
+}